@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE IF EXISTS stock_alerts;
+
+DROP TABLE IF EXISTS product_stock_thresholds;
+
+ALTER TABLE warehouse_stocks
+    DROP COLUMN IF EXISTS reorder_threshold;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+ALTER TABLE warehouse_stocks
+    ADD COLUMN reorder_threshold INTEGER CHECK (reorder_threshold >= 0);
+
+CREATE TABLE product_stock_thresholds (
+    product_id UUID PRIMARY KEY,
+    threshold INTEGER NOT NULL CHECK (threshold >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE stock_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    scope TEXT NOT NULL CHECK (scope IN ('warehouse', 'product')),
+    warehouse_id UUID,
+    product_id UUID NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('low', 'out', 'recovered')),
+    available INTEGER NOT NULL,
+    threshold INTEGER NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_alerts_product_id ON stock_alerts (product_id, created_at);
+CREATE INDEX idx_stock_alerts_warehouse_id ON stock_alerts (warehouse_id);
+
+COMMIT;
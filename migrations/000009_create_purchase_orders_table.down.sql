@@ -0,0 +1,13 @@
+BEGIN;
+
+DROP TABLE IF EXISTS purchase_order_discrepancies;
+
+DROP TABLE IF EXISTS goods_receipt_lines;
+
+DROP TABLE IF EXISTS goods_receipts;
+
+DROP TABLE IF EXISTS purchase_order_lines;
+
+DROP TABLE IF EXISTS purchase_orders;
+
+COMMIT;
@@ -0,0 +1,63 @@
+BEGIN;
+
+CREATE TABLE purchase_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    supplier_name TEXT NOT NULL,
+    notes TEXT,
+    status TEXT NOT NULL CHECK (status IN ('draft', 'ordered', 'partially_received', 'closed')),
+    ordered_at TIMESTAMPTZ,
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_purchase_orders_status ON purchase_orders (status);
+
+CREATE TABLE purchase_order_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    purchase_order_id UUID NOT NULL REFERENCES purchase_orders (id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    expected_quantity INTEGER NOT NULL CHECK (expected_quantity > 0),
+    received_quantity INTEGER NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (purchase_order_id, product_id)
+);
+
+CREATE TABLE goods_receipts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    purchase_order_id UUID NOT NULL REFERENCES purchase_orders (id) ON DELETE CASCADE,
+    warehouse_id UUID NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_goods_receipts_purchase_order_id ON goods_receipts (purchase_order_id);
+CREATE INDEX idx_goods_receipts_warehouse_id ON goods_receipts (warehouse_id);
+
+CREATE TABLE goods_receipt_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    goods_receipt_id UUID NOT NULL REFERENCES goods_receipts (id) ON DELETE CASCADE,
+    purchase_order_line_id UUID NOT NULL REFERENCES purchase_order_lines (id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    received_quantity INTEGER NOT NULL CHECK (received_quantity >= 0),
+    rejected_quantity INTEGER NOT NULL DEFAULT 0 CHECK (rejected_quantity >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_goods_receipt_lines_goods_receipt_id ON goods_receipt_lines (goods_receipt_id);
+
+CREATE TABLE purchase_order_discrepancies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    purchase_order_id UUID NOT NULL REFERENCES purchase_orders (id) ON DELETE CASCADE,
+    goods_receipt_id UUID REFERENCES goods_receipts (id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('over', 'short', 'damaged')),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    note TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_purchase_order_discrepancies_purchase_order_id ON purchase_order_discrepancies (purchase_order_id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS stock_adjustments;
+
+DROP TABLE IF EXISTS cycle_count_lines;
+
+DROP TABLE IF EXISTS cycle_counts;
+
+COMMIT;
@@ -0,0 +1,43 @@
+BEGIN;
+
+CREATE TABLE cycle_counts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    warehouse_id UUID NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('open', 'posted', 'cancelled')),
+    notes TEXT,
+    posted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_cycle_counts_warehouse_id ON cycle_counts (warehouse_id);
+CREATE INDEX idx_cycle_counts_status ON cycle_counts (status);
+
+CREATE TABLE cycle_count_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cycle_count_id UUID NOT NULL REFERENCES cycle_counts (id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    expected_quantity INTEGER NOT NULL CHECK (expected_quantity >= 0),
+    counted_quantity INTEGER CHECK (counted_quantity >= 0),
+    variance INTEGER,
+    reason TEXT CHECK (reason IN ('damaged', 'lost', 'found')),
+    counted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (cycle_count_id, product_id)
+);
+
+CREATE TABLE stock_adjustments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    warehouse_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    cycle_count_id UUID REFERENCES cycle_counts (id) ON DELETE SET NULL,
+    quantity INTEGER NOT NULL CHECK (quantity <> 0),
+    reason TEXT NOT NULL CHECK (reason IN ('damaged', 'lost', 'found')),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_adjustments_warehouse_id ON stock_adjustments (warehouse_id);
+CREATE INDEX idx_stock_adjustments_product_id ON stock_adjustments (product_id);
+
+COMMIT;
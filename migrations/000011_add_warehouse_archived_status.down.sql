@@ -0,0 +1,10 @@
+BEGIN;
+
+UPDATE warehouses SET status = 'inactive' WHERE status = 'archived';
+
+ALTER TABLE warehouses
+    DROP COLUMN archived_at,
+    DROP CONSTRAINT warehouses_status_check,
+    ADD CONSTRAINT warehouses_status_check CHECK (status IN ('active', 'inactive'));
+
+COMMIT;
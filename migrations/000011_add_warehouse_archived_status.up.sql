@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE warehouses
+    DROP CONSTRAINT warehouses_status_check,
+    ADD CONSTRAINT warehouses_status_check CHECK (status IN ('active', 'inactive', 'archived')),
+    ADD COLUMN archived_at TIMESTAMPTZ;
+
+COMMIT;
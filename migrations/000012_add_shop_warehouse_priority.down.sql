@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE shop_warehouses
+    DROP CONSTRAINT uq_shop_warehouses_shop_warehouse,
+    DROP COLUMN priority;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE shop_warehouses
+    ADD COLUMN priority INTEGER NOT NULL DEFAULT 0 CHECK (priority >= 0),
+    ADD CONSTRAINT uq_shop_warehouses_shop_warehouse UNIQUE (shop_id, warehouse_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE warehouses
+    DROP CONSTRAINT warehouses_coordinates_check,
+    DROP COLUMN longitude,
+    DROP COLUMN latitude;
+
+COMMIT;
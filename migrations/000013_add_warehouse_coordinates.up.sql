@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE warehouses
+    ADD COLUMN latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    ADD CONSTRAINT warehouses_coordinates_check CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+UPDATE warehouses SET status = 'active' WHERE status = 'draining';
+
+ALTER TABLE warehouses
+    DROP CONSTRAINT warehouses_status_check,
+    ADD CONSTRAINT warehouses_status_check CHECK (status IN ('active', 'inactive', 'archived'));
+
+COMMIT;
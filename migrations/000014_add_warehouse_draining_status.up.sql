@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE warehouses
+    DROP CONSTRAINT warehouses_status_check,
+    ADD CONSTRAINT warehouses_status_check CHECK (status IN ('active', 'draining', 'inactive', 'archived'));
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+DROP TABLE IF EXISTS stock_transfer_receipts;
+
+DROP INDEX IF EXISTS idx_stock_transfers_status;
+
+ALTER TABLE stock_transfers
+    DROP CONSTRAINT stock_transfers_settled_quantity_check,
+    DROP COLUMN received_at,
+    DROP COLUMN in_transit_at,
+    DROP COLUMN dispatched_at,
+    DROP COLUMN notes,
+    DROP COLUMN lost_quantity,
+    DROP COLUMN received_quantity,
+    DROP COLUMN status;
+
+COMMIT;
@@ -0,0 +1,35 @@
+BEGIN;
+
+ALTER TABLE stock_transfers
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'received' CHECK (status IN ('dispatched', 'in_transit', 'partially_received', 'received')),
+    ADD COLUMN received_quantity INTEGER NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    ADD COLUMN lost_quantity INTEGER NOT NULL DEFAULT 0 CHECK (lost_quantity >= 0),
+    ADD COLUMN notes TEXT,
+    ADD COLUMN dispatched_at TIMESTAMPTZ,
+    ADD COLUMN in_transit_at TIMESTAMPTZ,
+    ADD COLUMN received_at TIMESTAMPTZ;
+
+-- transfers recorded before this migration were moved instantly
+UPDATE stock_transfers
+SET received_quantity = quantity,
+    dispatched_at = created_at,
+    received_at = created_at;
+
+ALTER TABLE stock_transfers
+    ALTER COLUMN status DROP DEFAULT,
+    ADD CONSTRAINT stock_transfers_settled_quantity_check CHECK (received_quantity + lost_quantity <= quantity);
+
+CREATE INDEX idx_stock_transfers_status ON stock_transfers (status);
+
+CREATE TABLE stock_transfer_receipts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    stock_transfer_id UUID NOT NULL REFERENCES stock_transfers (id) ON DELETE CASCADE,
+    received_quantity INTEGER NOT NULL CHECK (received_quantity >= 0),
+    lost_quantity INTEGER NOT NULL DEFAULT 0 CHECK (lost_quantity >= 0),
+    note TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_transfer_receipts_stock_transfer_id ON stock_transfer_receipts (stock_transfer_id);
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE stock_lots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    warehouse_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    lot_number TEXT NOT NULL,
+    expiry_date DATE,
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (warehouse_id, product_id) REFERENCES warehouse_stocks (warehouse_id, product_id) ON DELETE CASCADE,
+    CONSTRAINT stock_lots_reserved_check CHECK (reserved <= quantity)
+);
+
+CREATE UNIQUE INDEX stock_lot_unique_key ON stock_lots (warehouse_id, product_id, lot_number);
+CREATE INDEX idx_stock_lots_product_id_expiry_date ON stock_lots (product_id, expiry_date);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS stock_serial_events;
+
+DROP TABLE IF EXISTS stock_serials;
+
+DROP TABLE IF EXISTS serialized_products;
+
+COMMIT;
@@ -0,0 +1,34 @@
+BEGIN;
+
+CREATE TABLE serialized_products (
+    product_id UUID PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE stock_serials (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL,
+    serial_number TEXT NOT NULL,
+    warehouse_id UUID NOT NULL REFERENCES warehouses (id),
+    status TEXT NOT NULL CHECK (status IN ('in_stock', 'sold')),
+    order_ref TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX stock_serial_unique_key ON stock_serials (product_id, serial_number);
+CREATE INDEX idx_stock_serials_serial_number ON stock_serials (serial_number);
+CREATE INDEX idx_stock_serials_warehouse_id_product_id ON stock_serials (warehouse_id, product_id, status);
+
+CREATE TABLE stock_serial_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    stock_serial_id UUID NOT NULL REFERENCES stock_serials (id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('received', 'sold', 'returned')),
+    warehouse_id UUID NOT NULL,
+    order_ref TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_serial_events_stock_serial_id ON stock_serial_events (stock_serial_id);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS pick_list_items;
+
+DROP TABLE IF EXISTS pick_lists;
+
+DROP TABLE IF EXISTS bin_stocks;
+
+DROP TABLE IF EXISTS warehouse_bins;
+
+COMMIT;
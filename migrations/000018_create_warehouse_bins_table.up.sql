@@ -0,0 +1,49 @@
+BEGIN;
+
+CREATE TABLE warehouse_bins (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    warehouse_id UUID NOT NULL REFERENCES warehouses (id),
+    zone TEXT NOT NULL,
+    code TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX warehouse_bin_unique_key ON warehouse_bins (warehouse_id, code);
+
+CREATE TABLE bin_stocks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bin_id UUID NOT NULL REFERENCES warehouse_bins (id),
+    warehouse_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (warehouse_id, product_id) REFERENCES warehouse_stocks (warehouse_id, product_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX bin_stock_unique_key ON bin_stocks (bin_id, product_id);
+CREATE INDEX idx_bin_stocks_warehouse_id_product_id ON bin_stocks (warehouse_id, product_id);
+
+CREATE TABLE pick_lists (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    warehouse_id UUID NOT NULL REFERENCES warehouses (id),
+    order_ref TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_pick_lists_order_ref ON pick_lists (order_ref);
+
+CREATE TABLE pick_list_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pick_list_id UUID NOT NULL REFERENCES pick_lists (id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    bin_id UUID REFERENCES warehouse_bins (id),
+    zone TEXT,
+    bin_code TEXT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0)
+);
+
+CREATE INDEX idx_pick_list_items_pick_list_id ON pick_list_items (pick_list_id);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS stock_snapshots;
+
+DROP TRIGGER IF EXISTS warehouse_stocks_movement ON warehouse_stocks;
+
+DROP FUNCTION IF EXISTS record_stock_movement();
+
+DROP TABLE IF EXISTS stock_movements;
+
+COMMIT;
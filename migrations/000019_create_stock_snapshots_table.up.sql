@@ -0,0 +1,57 @@
+BEGIN;
+
+CREATE TABLE stock_movements (
+    id BIGSERIAL PRIMARY KEY,
+    warehouse_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    quantity_delta INTEGER NOT NULL,
+    reserved_delta INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_movements_created_at ON stock_movements (created_at);
+
+-- every change to warehouse_stocks is recorded so past stock levels can be
+-- replayed from the nearest snapshot
+CREATE FUNCTION record_stock_movement() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO stock_movements (warehouse_id, product_id, quantity_delta, reserved_delta)
+        VALUES (NEW.warehouse_id, NEW.product_id, NEW.quantity, NEW.reserved);
+    ELSIF TG_OP = 'UPDATE' THEN
+        IF NEW.warehouse_id <> OLD.warehouse_id OR NEW.product_id <> OLD.product_id THEN
+            INSERT INTO stock_movements (warehouse_id, product_id, quantity_delta, reserved_delta)
+            VALUES (OLD.warehouse_id, OLD.product_id, -OLD.quantity, -OLD.reserved),
+                   (NEW.warehouse_id, NEW.product_id, NEW.quantity, NEW.reserved);
+        ELSIF NEW.quantity <> OLD.quantity OR NEW.reserved <> OLD.reserved THEN
+            INSERT INTO stock_movements (warehouse_id, product_id, quantity_delta, reserved_delta)
+            VALUES (NEW.warehouse_id, NEW.product_id, NEW.quantity - OLD.quantity, NEW.reserved - OLD.reserved);
+        END IF;
+    ELSIF TG_OP = 'DELETE' THEN
+        INSERT INTO stock_movements (warehouse_id, product_id, quantity_delta, reserved_delta)
+        VALUES (OLD.warehouse_id, OLD.product_id, -OLD.quantity, -OLD.reserved);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER warehouse_stocks_movement
+AFTER INSERT OR UPDATE OR DELETE ON warehouse_stocks
+FOR EACH ROW EXECUTE FUNCTION record_stock_movement();
+
+CREATE TABLE stock_snapshots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    taken_at TIMESTAMPTZ NOT NULL,
+    warehouse_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INTEGER NOT NULL,
+    reserved INTEGER NOT NULL
+);
+
+CREATE UNIQUE INDEX stock_snapshot_unique_key ON stock_snapshots (taken_at, warehouse_id, product_id);
+
+-- baseline to replay from until the first nightly snapshot is taken
+INSERT INTO stock_snapshots (taken_at, warehouse_id, product_id, quantity, reserved)
+SELECT CURRENT_TIMESTAMP, warehouse_id, product_id, quantity, reserved FROM warehouse_stocks;
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+UPDATE orders SET status = 'cancelled' WHERE status = 'waiting';
+
+ALTER TABLE orders
+    DROP CONSTRAINT orders_status_check,
+    ADD CONSTRAINT orders_status_check CHECK (status IN ('pending', 'completed', 'cancelled'));
+
+DROP TABLE IF EXISTS backorder_allocations;
+
+DROP TABLE IF EXISTS backorders;
+
+DROP TABLE IF EXISTS backorder_products;
+
+COMMIT;
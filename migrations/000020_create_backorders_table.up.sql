@@ -0,0 +1,42 @@
+BEGIN;
+
+CREATE TABLE backorder_products (
+    product_id UUID PRIMARY KEY,
+    mode TEXT NOT NULL CHECK (mode IN ('backorder', 'preorder')),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE backorders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_ref TEXT NOT NULL,
+    product_id UUID NOT NULL,
+    shop_id UUID,
+    mode TEXT NOT NULL CHECK (mode IN ('backorder', 'preorder')),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    allocated_quantity INTEGER NOT NULL DEFAULT 0 CHECK (allocated_quantity >= 0),
+    status TEXT NOT NULL CHECK (status IN ('waiting', 'allocated')),
+    allocated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT backorders_allocated_quantity_check CHECK (allocated_quantity <= quantity)
+);
+
+CREATE INDEX idx_backorders_product_id_status ON backorders (product_id, status, created_at);
+CREATE INDEX idx_backorders_order_ref ON backorders (order_ref);
+
+CREATE TABLE backorder_allocations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    backorder_id UUID NOT NULL REFERENCES backorders (id) ON DELETE CASCADE,
+    warehouse_id UUID NOT NULL REFERENCES warehouses (id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_backorder_allocations_backorder_id ON backorder_allocations (backorder_id);
+
+ALTER TABLE orders
+    DROP CONSTRAINT orders_status_check,
+    ADD CONSTRAINT orders_status_check CHECK (status IN ('waiting', 'pending', 'completed', 'cancelled'));
+
+COMMIT;
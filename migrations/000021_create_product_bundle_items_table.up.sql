@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE product_bundle_items (
+    bundle_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    component_id UUID NOT NULL REFERENCES products (id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (bundle_id, component_id),
+    CONSTRAINT product_bundle_items_component_check CHECK (bundle_id <> component_id)
+);
+
+CREATE INDEX idx_product_bundle_items_component_id ON product_bundle_items (component_id);
+
+COMMIT;
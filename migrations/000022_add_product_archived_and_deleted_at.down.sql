@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_products_deleted_at;
+
+ALTER TABLE products
+    DROP COLUMN archived_at,
+    DROP COLUMN deleted_at;
+
+COMMIT;
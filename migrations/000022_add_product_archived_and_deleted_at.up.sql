@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE products
+    ADD COLUMN archived_at TIMESTAMPTZ,
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_products_deleted_at ON products (deleted_at);
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TABLE IF EXISTS product_tags;
+
+DROP INDEX IF EXISTS idx_products_category_id;
+
+ALTER TABLE products
+    DROP COLUMN category_id;
+
+DROP TABLE IF EXISTS categories;
+
+COMMIT;
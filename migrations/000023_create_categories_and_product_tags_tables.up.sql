@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE categories (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    parent_id UUID REFERENCES categories (id),
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT categories_parent_check CHECK (parent_id <> id)
+);
+
+CREATE UNIQUE INDEX idx_categories_parent_id_name ON categories (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), name);
+
+ALTER TABLE products
+    ADD COLUMN category_id UUID REFERENCES categories (id);
+
+CREATE INDEX idx_products_category_id ON products (category_id);
+
+CREATE TABLE product_tags (
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (product_id, tag)
+);
+
+CREATE INDEX idx_product_tags_tag ON product_tags (tag);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE orders
+    DROP COLUMN variant_id;
+
+DROP TABLE IF EXISTS product_variant_options;
+DROP TABLE IF EXISTS product_variants;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+CREATE TABLE product_variants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL,
+    price DECIMAL(10, 2) CHECK (price >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_product_variants_sku ON product_variants (sku);
+CREATE INDEX idx_product_variants_product_id ON product_variants (product_id);
+
+CREATE TABLE product_variant_options (
+    variant_id UUID NOT NULL REFERENCES product_variants (id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    value VARCHAR(100) NOT NULL,
+    PRIMARY KEY (variant_id, name)
+);
+
+ALTER TABLE orders
+    ADD COLUMN variant_id UUID;
+
+COMMIT;
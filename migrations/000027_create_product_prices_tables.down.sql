@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS product_sales;
+DROP TABLE IF EXISTS product_prices;
+
+COMMIT;
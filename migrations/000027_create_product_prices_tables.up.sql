@@ -0,0 +1,29 @@
+BEGIN;
+
+CREATE TABLE product_prices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0),
+    effective_from TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_prices_product_id ON product_prices (product_id, effective_from);
+
+-- the current prices start the history
+INSERT INTO product_prices (product_id, price, effective_from, created_at)
+SELECT id, price, COALESCE(created_at, CURRENT_TIMESTAMP), COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM products;
+
+CREATE TABLE product_sales (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL CHECK (ends_at > starts_at),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_product_sales_product_id ON product_sales (product_id, starts_at);
+
+COMMIT;
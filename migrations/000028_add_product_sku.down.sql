@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_products_shop_id_sku;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS sku;
+
+COMMIT;
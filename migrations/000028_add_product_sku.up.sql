@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE products
+    ADD COLUMN sku VARCHAR(64);
+
+-- a merchant SKU names one product of the shop, deleted products free theirs
+CREATE UNIQUE INDEX idx_products_shop_id_sku ON products (shop_id, sku) WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE stock_lot_reservations (
+    stock_lot_id UUID NOT NULL REFERENCES stock_lots (id) ON DELETE CASCADE,
+    order_ref TEXT NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (stock_lot_id, order_ref)
+);
+
+-- lots reserved so far were not recorded per order, they are released from
+-- the reservations without an order
+INSERT INTO stock_lot_reservations (stock_lot_id, order_ref, quantity)
+SELECT id, '', reserved FROM stock_lots WHERE reserved > 0;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DELETE FROM stock_serial_events WHERE type = 'transferred';
+
+ALTER TABLE stock_serial_events
+    DROP CONSTRAINT stock_serial_events_type_check,
+    ADD CONSTRAINT stock_serial_events_type_check CHECK (type IN ('received', 'sold', 'returned'));
+
+COMMIT;
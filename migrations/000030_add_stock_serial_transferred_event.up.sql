@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE stock_serial_events
+    DROP CONSTRAINT stock_serial_events_type_check,
+    ADD CONSTRAINT stock_serial_events_type_check CHECK (type IN ('received', 'sold', 'returned', 'transferred'));
+
+COMMIT;
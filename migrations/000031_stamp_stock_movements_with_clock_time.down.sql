@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE stock_movements ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- CURRENT_TIMESTAMP is when the transaction started, so a movement written
+-- late in a long transaction could be stamped before a snapshot that already
+-- missed it. Stamp movements with the time they are actually written.
+ALTER TABLE stock_movements ALTER COLUMN created_at SET DEFAULT clock_timestamp();
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DELETE FROM backorders WHERE status = 'cancelled';
+
+ALTER TABLE backorders
+    DROP CONSTRAINT backorders_status_check,
+    ADD CONSTRAINT backorders_status_check CHECK (status IN ('waiting', 'allocated'));
+
+COMMIT;
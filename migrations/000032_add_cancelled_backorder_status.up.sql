@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE backorders
+    DROP CONSTRAINT backorders_status_check,
+    ADD CONSTRAINT backorders_status_check CHECK (status IN ('waiting', 'allocated', 'cancelled'));
+
+COMMIT;
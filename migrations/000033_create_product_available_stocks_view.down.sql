@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP VIEW IF EXISTS product_available_stocks;
+
+COMMIT;
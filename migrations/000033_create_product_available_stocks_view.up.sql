@@ -0,0 +1,21 @@
+BEGIN;
+
+-- The available stock of each product in the active warehouses, less the
+-- unreserved quantity of expired lots, as the warehouse service reports it.
+-- Product listings filter on it in SQL instead of fetching the availability
+-- of the whole catalogue. TestProductAvailableStocksView in the warehouse
+-- service checks the two agree.
+CREATE VIEW product_available_stocks AS
+SELECT ws.product_id, sum(ws.quantity) - sum(ws.reserved) - coalesce(sum(el.expired), 0) AS available_stock
+FROM warehouse_stocks ws
+JOIN warehouses w ON ws.warehouse_id = w.id
+LEFT JOIN (
+    SELECT warehouse_id, product_id, sum(quantity - reserved) AS expired
+    FROM stock_lots
+    WHERE expiry_date < CURRENT_DATE
+    GROUP BY warehouse_id, product_id
+) el ON el.warehouse_id = ws.warehouse_id AND el.product_id = ws.product_id
+WHERE w.status = 'active'
+GROUP BY ws.product_id;
+
+COMMIT;
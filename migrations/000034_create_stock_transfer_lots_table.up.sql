@@ -0,0 +1,16 @@
+BEGIN;
+
+-- The lots a transfer took out of its source warehouse, with what of them is
+-- still in transit. Receipts book them into the destination warehouse first
+-- expiry first, so the goods keep their lot and expiry date on the way.
+CREATE TABLE stock_transfer_lots (
+    stock_transfer_id UUID NOT NULL REFERENCES stock_transfers (id) ON DELETE CASCADE,
+    lot_number TEXT NOT NULL,
+    expiry_date DATE,
+    quantity INTEGER NOT NULL CHECK (quantity >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (stock_transfer_id, lot_number)
+);
+
+COMMIT;
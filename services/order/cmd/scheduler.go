@@ -28,6 +28,19 @@ func startScheduler(logger *pkg.Logger, modules *internal.Modules) {
 		logger.Fatal("Failed to create job:", err)
 	}
 
-	logger.Info("Scheduler started, processing expired orders every minute")
+	_, err = s.NewJob(gocron.CronJob("* * * * *", false), gocron.NewTask(func() {
+		ctx, cancelCtx := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancelCtx()
+
+		logger.Info("Processing backordered orders...")
+		if err := modules.Order.OrderService.ProcessBackorderedOrders(ctx); err != nil {
+			logger.Error("Failed to process backordered orders:", err)
+		}
+	}))
+	if err != nil {
+		logger.Fatal("Failed to create job:", err)
+	}
+
+	logger.Info("Scheduler started, processing expired and backordered orders every minute")
 	s.Start()
 }
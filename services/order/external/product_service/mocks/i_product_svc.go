@@ -14,6 +14,34 @@ type IProductSvc struct {
 	mock.Mock
 }
 
+// GetEffectivePrice provides a mock function with given fields: ctx, req
+func (_m *IProductSvc) GetEffectivePrice(ctx context.Context, req productservice.GetEffectivePriceReq) (productservice.GetEffectivePriceResp, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEffectivePrice")
+	}
+
+	var r0 productservice.GetEffectivePriceResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, productservice.GetEffectivePriceReq) (productservice.GetEffectivePriceResp, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, productservice.GetEffectivePriceReq) productservice.GetEffectivePriceResp); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(productservice.GetEffectivePriceResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, productservice.GetEffectivePriceReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProductByID provides a mock function with given fields: ctx, req
 func (_m *IProductSvc) GetProductByID(ctx context.Context, req productservice.GetProductByIDReq) (productservice.GetProductByIDResp, error) {
 	ret := _m.Called(ctx, req)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/alifmufthi91/ecommerce-system/services/order/internal/_options"
 	"github.com/alifmufthi91/ecommerce-system/services/order/internal/pkg/apperr"
@@ -16,6 +17,7 @@ import (
 //go:generate mockery --name=IProductSvc --case underscore
 type IProductSvc interface {
 	GetProductByID(ctx context.Context, req GetProductByIDReq) (GetProductByIDResp, error)
+	GetEffectivePrice(ctx context.Context, req GetEffectivePriceReq) (GetEffectivePriceResp, error)
 }
 
 type ProductSvc struct {
@@ -73,6 +75,53 @@ func (w *ProductSvc) GetProductByID(ctx context.Context, req GetProductByIDReq)
 	return res, nil
 }
 
+// GetEffectivePrice returns the price the product, or its variant, sells for
+// at req.At, sales included.
+func (w *ProductSvc) GetEffectivePrice(ctx context.Context, req GetEffectivePriceReq) (res GetEffectivePriceResp, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productsvc.GetEffectivePrice")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	queryParams := map[string]string{
+		"at": req.At.UTC().Format(time.RFC3339Nano),
+	}
+	if req.VariantID != "" {
+		queryParams["variant_id"] = req.VariantID
+	}
+
+	resp, err := w.httpClient.Get(ctx, &httpclient.PropRequest{
+		URI:         w.URL + "/products/" + req.ProductID + "/price",
+		QueryParams: queryParams,
+		Headers: map[string]string{
+			"Authorization": "Bearer " + req.Token,
+		},
+	})
+
+	if err != nil {
+		return res, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, ProductServicePrefix+`internal server error`)
+	}
+
+	defer resp.Body.Close()
+
+	rawData, err := io.ReadAll(resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		if err := handleErrorResponse(rawData, resp.StatusCode); err != nil {
+			return res, err
+		}
+	}
+
+	if err = json.Unmarshal(rawData, &res); err != nil {
+		return res, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, ProductServicePrefix+"failed to unmarshal response")
+	}
+
+	return res, nil
+}
+
 func handleErrorResponse(rawData []byte, statusCode int) error {
 	if len(rawData) == 0 {
 		return apperr.NewWithCode(apperr.MapStatusCodeToErrorCode(statusCode), ProductServicePrefix+http.StatusText(statusCode))
@@ -1,6 +1,17 @@
 package productservice
 
+import "time"
+
 type GetProductByIDReq struct {
 	ProductID string `json:"product_id"`
 	Token     string `json:"-"`
 }
+
+// GetEffectivePriceReq asks the price of a product, or of one of its variants
+// when VariantID is set, at a time.
+type GetEffectivePriceReq struct {
+	ProductID string    `json:"product_id"`
+	VariantID string    `json:"variant_id"`
+	At        time.Time `json:"at"`
+	Token     string    `json:"-"`
+}
@@ -12,8 +12,32 @@ type GetProductByIDRespData struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	Price       float64   `json:"price"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	// BundleItems are the components of a bundle, empty for a plain product
+	BundleItems []GetProductByIDRespBundleItem `json:"bundle_items"`
+	CreatedAt   time.Time                      `json:"created_at"`
+	UpdatedAt   time.Time                      `json:"updated_at"`
+	ArchivedAt  *time.Time                     `json:"archived_at"`
+	DeletedAt   *time.Time                     `json:"deleted_at"`
+
+	// Variants of a product sold in variants, their stock is kept by variant
+	Variants []GetProductByIDRespVariant `json:"variants"`
+}
+
+// IsAvailable reports whether the product can still be ordered.
+func (d GetProductByIDRespData) IsAvailable() bool {
+	return d.ArchivedAt == nil && d.DeletedAt == nil
+}
+
+type GetProductByIDRespBundleItem struct {
+	ComponentID uuid.UUID `json:"component_id"`
+	Quantity    int       `json:"quantity"`
+}
+
+type GetProductByIDRespVariant struct {
+	ID  uuid.UUID `json:"id"`
+	SKU string    `json:"sku"`
+	// Price overrides the price of the product when set
+	Price *float64 `json:"price"`
 }
 
 type GetProductByIDResp struct {
@@ -21,6 +45,22 @@ type GetProductByIDResp struct {
 	Success string                 `json:"success"`
 }
 
+type GetEffectivePriceRespData struct {
+	ProductID    uuid.UUID  `json:"product_id"`
+	VariantID    *uuid.UUID `json:"variant_id"`
+	At           time.Time  `json:"at"`
+	RegularPrice float64    `json:"regular_price"`
+	// SalePrice is set when a sale runs at the time
+	SalePrice *float64 `json:"sale_price"`
+	// Price is what the product is sold at
+	Price float64 `json:"price"`
+}
+
+type GetEffectivePriceResp struct {
+	Data    GetEffectivePriceRespData `json:"data"`
+	Success string                    `json:"success"`
+}
+
 type ErrorResponse struct {
 	Metadata ErrorMetadata `json:"metadata"`
 }
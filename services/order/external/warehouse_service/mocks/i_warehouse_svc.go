@@ -14,6 +14,24 @@ type IWarehouseSvc struct {
 	mock.Mock
 }
 
+// CancelBackorders provides a mock function with given fields: ctx, req
+func (_m *IWarehouseSvc) CancelBackorders(ctx context.Context, req warehouseservice.CancelBackordersReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelBackorders")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, warehouseservice.CancelBackordersReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CommitReserves provides a mock function with given fields: ctx, req
 func (_m *IWarehouseSvc) CommitReserves(ctx context.Context, req warehouseservice.CommitReservesReq) (warehouseservice.CommitReservesResp, error) {
 	ret := _m.Called(ctx, req)
@@ -42,6 +60,34 @@ func (_m *IWarehouseSvc) CommitReserves(ctx context.Context, req warehouseservic
 	return r0, r1
 }
 
+// GetBackorders provides a mock function with given fields: ctx, req
+func (_m *IWarehouseSvc) GetBackorders(ctx context.Context, req warehouseservice.GetBackordersReq) (warehouseservice.GetBackordersResp, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBackorders")
+	}
+
+	var r0 warehouseservice.GetBackordersResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, warehouseservice.GetBackordersReq) (warehouseservice.GetBackordersResp, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, warehouseservice.GetBackordersReq) warehouseservice.GetBackordersResp); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(warehouseservice.GetBackordersResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, warehouseservice.GetBackordersReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReserveStocks provides a mock function with given fields: ctx, req
 func (_m *IWarehouseSvc) ReserveStocks(ctx context.Context, req warehouseservice.ReserveStocksReq) (warehouseservice.ReserveStocksResp, error) {
 	ret := _m.Called(ctx, req)
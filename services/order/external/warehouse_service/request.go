@@ -1,18 +1,23 @@
 package warehouseservice
 
+// ReserveStocksReq reserves stocks for the order OrderRef. Products that take
+// backorders are backordered under it when short of stock.
 type ReserveStocksReq struct {
-	Stocks []ReserveStocksReqData `json:"stocks"`
-	Token  string                 `json:"-"`
+	Stocks   []ReserveStocksReqData `json:"stocks"`
+	OrderRef string                 `json:"order_ref,omitempty"`
+	Token    string                 `json:"-"`
 }
 
 type ReserveStocksReqData struct {
 	ProductID string `json:"product_id"`
+	ShopID    string `json:"shop_id,omitempty"`
 	Quantity  int    `json:"quantity"`
 }
 
 type CommitReservesReq struct {
-	Stocks []CommitReservesReqData `json:"stocks"`
-	Token  string                  `json:"-"`
+	Stocks   []CommitReservesReqData `json:"stocks"`
+	OrderRef string                  `json:"order_ref,omitempty"`
+	Token    string                  `json:"-"`
 }
 
 type CommitReservesReqData struct {
@@ -22,8 +27,9 @@ type CommitReservesReqData struct {
 }
 
 type RollbackReservesReq struct {
-	Stocks []RollbackReservesReqData `json:"stocks"`
-	Token  string                    `json:"-"`
+	Stocks   []RollbackReservesReqData `json:"stocks"`
+	OrderRef string                    `json:"order_ref,omitempty"`
+	Token    string                    `json:"-"`
 }
 
 type RollbackReservesReqData struct {
@@ -31,3 +37,13 @@ type RollbackReservesReqData struct {
 	WarehouseID string `json:"warehouse_id"`
 	Quantity    int    `json:"quantity"`
 }
+
+type GetBackordersReq struct {
+	OrderRefIN []string `json:"-"`
+	Token      string   `json:"-"`
+}
+
+type CancelBackordersReq struct {
+	OrderRef string `json:"order_ref"`
+	Token    string `json:"-"`
+}
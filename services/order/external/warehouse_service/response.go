@@ -2,10 +2,14 @@ package warehouseservice
 
 import "github.com/google/uuid"
 
+// ReserveStocksRespData is a quantity reserved in a warehouse, or a backorder
+// when BackorderID is set.
 type ReserveStocksRespData struct {
-	WarehouseID      uuid.UUID `json:"warehouse_id"`
-	ProductID        uuid.UUID `json:"product_id"`
-	ReservedQuantity int       `json:"reserved_quantity"`
+	WarehouseID       uuid.UUID `json:"warehouse_id"`
+	ProductID         uuid.UUID `json:"product_id"`
+	ReservedQuantity  int       `json:"reserved_quantity"`
+	BackorderID       string    `json:"backorder_id"`
+	BackorderQuantity int       `json:"backorder_quantity"`
 }
 
 type ReserveStocksResp struct {
@@ -23,6 +27,26 @@ type RollbackReservesResp struct {
 	Success string `json:"success"`
 }
 
+type GetBackordersResp struct {
+	Data    []Backorder `json:"data"`
+	Success string      `json:"success"`
+}
+
+type Backorder struct {
+	ID                uuid.UUID             `json:"id"`
+	OrderRef          string                `json:"order_ref"`
+	ProductID         uuid.UUID             `json:"product_id"`
+	Quantity          int                   `json:"quantity"`
+	AllocatedQuantity int                   `json:"allocated_quantity"`
+	Status            string                `json:"status"`
+	Allocations       []BackorderAllocation `json:"allocations"`
+}
+
+type BackorderAllocation struct {
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	Quantity    int       `json:"quantity"`
+}
+
 type ErrorResponse struct {
 	Metadata ErrorMetadata `json:"metadata"`
 }
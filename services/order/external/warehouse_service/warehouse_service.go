@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
 
 	"github.com/alifmufthi91/ecommerce-system/services/order/internal/_options"
 	"github.com/alifmufthi91/ecommerce-system/services/order/internal/pkg/apperr"
@@ -18,6 +19,8 @@ type IWarehouseSvc interface {
 	ReserveStocks(ctx context.Context, req ReserveStocksReq) (ReserveStocksResp, error)
 	CommitReserves(ctx context.Context, req CommitReservesReq) (CommitReservesResp, error)
 	RollbackReserves(ctx context.Context, req RollbackReservesReq) error
+	GetBackorders(ctx context.Context, req GetBackordersReq) (GetBackordersResp, error)
+	CancelBackorders(ctx context.Context, req CancelBackordersReq) error
 }
 
 type WarehouseSvc struct {
@@ -148,6 +151,80 @@ func (w *WarehouseSvc) RollbackReserves(ctx context.Context, req RollbackReserve
 	return nil
 }
 
+func (w *WarehouseSvc) GetBackorders(ctx context.Context, req GetBackordersReq) (res GetBackordersResp, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehousesvc.GetBackorders")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	resp, err := w.httpClient.Get(ctx, &httpclient.PropRequest{
+		URI: w.URL + "/stocks/backorders",
+		MultiQueryParams: url.Values{
+			"order_ref_in": req.OrderRefIN,
+		},
+		Headers: map[string]string{
+			"Authorization": "Bearer " + req.Token,
+		},
+	})
+
+	if err != nil {
+		return res, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, WarehouseServicePrefix+`internal server error`)
+	}
+
+	defer resp.Body.Close()
+
+	rawData, err := io.ReadAll(resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		if err := handleErrorResponse(rawData, resp.StatusCode); err != nil {
+			return res, err
+		}
+	}
+
+	if err = json.Unmarshal(rawData, &res); err != nil {
+		return res, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, WarehouseServicePrefix+"failed to unmarshal response")
+	}
+
+	return res, nil
+}
+
+func (w *WarehouseSvc) CancelBackorders(ctx context.Context, req CancelBackordersReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehousesvc.CancelBackorders")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	resp, err := w.httpClient.Post(ctx, &httpclient.PropRequest{
+		URI:  w.URL + "/stocks/backorders/cancel",
+		Body: req,
+		Headers: map[string]string{
+			"Authorization": "Bearer " + req.Token,
+		},
+	})
+
+	if err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, WarehouseServicePrefix+`internal server error`)
+	}
+
+	defer resp.Body.Close()
+
+	rawData, err := io.ReadAll(resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		if err := handleErrorResponse(rawData, resp.StatusCode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func handleErrorResponse(rawData []byte, statusCode int) error {
 	if len(rawData) == 0 {
 		return apperr.NewWithCode(apperr.MapStatusCodeToErrorCode(statusCode), WarehouseServicePrefix+http.StatusText(statusCode))
@@ -1,9 +1,18 @@
 package constant
 
 const (
+	OrderStatusWaiting   = "waiting"
 	OrderStatusPending   = "pending"
 	OrderStatusCompleted = "completed"
 	OrderStatusCancelled = "cancelled"
 
+	// BackorderStatusAllocated is the warehouse status of a backorder once
+	// all of its quantity is reserved
+	BackorderStatusAllocated = "allocated"
+
 	OrderExpirationTime = 24 * 60 * 60 // 24 hours in seconds
+
+	// BackorderExpirationTime is how long an order waits for its backorders
+	// before it is cancelled
+	BackorderExpirationTime = 30 * 24 * 60 * 60 // 30 days in seconds
 )
@@ -33,4 +33,5 @@ func (h orderHandler) RegisterRoutes(base *gin.RouterGroup) {
 	g.GET("", h.GetOrders)
 	g.POST("", h.CreateOrder)
 	g.PATCH("/:id/complete", h.CompleteOrder)
+	g.PATCH("/:id/cancel", h.CancelOrder)
 }
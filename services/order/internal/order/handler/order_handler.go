@@ -120,3 +120,41 @@ func (h *orderHandler) CompleteOrder(c *gin.Context) {
 
 	httpresp.HttpRespSuccess(c, order, nil)
 }
+
+// @Summary		Order - Cancel Order
+// @Description	cancel an order that is waiting for its backorders or pending, releasing its stock
+// @Tags		Order
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"Order ID"
+// @Success		200	{object}	httpresp.Response{data=model.Order}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/orders/{id}/cancel [patch]
+func (h *orderHandler) CancelOrder(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "orderHandler.CancelOrder")
+	defer span.End()
+
+	id := c.Param("id")
+	if id == "" {
+		span.SetStatus(codes.Error, "order ID is required")
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "order ID is required"))
+		return
+	}
+
+	claims := auth.GetClaimsFromContext(c)
+
+	var req payload.CancelOrderReq
+	req.OrderID = id
+	req.Token = claims.Token
+	order, err := h.orderService.CancelOrder(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, order, nil)
+}
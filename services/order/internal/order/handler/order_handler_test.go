@@ -258,3 +258,81 @@ func TestCompleteOrder_ShouldReturnExpectedStatusCode(t *testing.T) {
 		})
 	}
 }
+
+func TestCancelOrder_ShouldReturnExpectedStatusCode(t *testing.T) {
+	orderID := uuid.New()
+
+	testScenarios := []struct {
+		testName           string
+		orderID            string
+		mockResult         model.Order
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			orderID:            orderID.String(),
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+			mockResult: model.Order{
+				ID:         orderID,
+				Status:     "cancelled",
+				UserID:     uuid.New(),
+				ProductID:  uuid.New(),
+				Quantity:   2,
+				TotalPrice: 100.0,
+			},
+		},
+		{
+			testName:           "failed - error handle cancel order",
+			orderID:            orderID.String(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+			mockResult:         model.Order{},
+		},
+		{
+			testName:           "failed - missing order ID",
+			orderID:            "",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockOrderSvc := &mocks.OrderService{}
+			mockOrderSvc.
+				On("CancelOrder", mock.Anything, mock.Anything).
+				Return(scenario.mockResult, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+
+			// Build URL with order ID parameter
+			url := "/orders/" + scenario.orderID + "/cancel"
+			ctx.Request = httptest.NewRequest(http.MethodPatch, url, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &orderHandler{
+				router:       r,
+				config:       mockConfig,
+				orderService: mockOrderSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
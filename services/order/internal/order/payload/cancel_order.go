@@ -0,0 +1,6 @@
+package payload
+
+type CancelOrderReq struct {
+	OrderID string `json:"-"`
+	Token   string `json:"-"`
+}
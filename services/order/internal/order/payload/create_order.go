@@ -4,7 +4,9 @@ import "github.com/google/uuid"
 
 type CreateOrderReq struct {
 	ProductID uuid.UUID `json:"product_id" binding:"required"`
-	Quantity  int       `json:"quantity" binding:"required,min=1"`
-	UserID    string    `json:"-"`
-	Token     string    `json:"-"`
+	// VariantID is required for a product sold in variants
+	VariantID *uuid.UUID `json:"variant_id"`
+	Quantity  int        `json:"quantity" binding:"required,min=1"`
+	UserID    string     `json:"-"`
+	Token     string     `json:"-"`
 }
@@ -7,4 +7,7 @@ type GetOrdersReq struct {
 	ProductIDIN   []string  `form:"product_id_in" binding:"omitempty"`
 	StatusIN      []string  `form:"status_in" binding:"omitempty"`
 	ExpiresBefore time.Time `form:"expires_before" binding:"omitempty"`
+	// IDAfter and Limit page through the orders by ID
+	IDAfter string `form:"-"`
+	Limit   int    `form:"-"`
 }
@@ -82,6 +82,14 @@ func (r *orderRepository) GetOrders(ctx context.Context, req payload.GetOrdersRe
 		stmt = stmt.Where("expires_at < ?", req.ExpiresBefore)
 	}
 
+	if req.IDAfter != "" {
+		stmt = stmt.Where("id > ?", req.IDAfter)
+	}
+
+	if req.Limit > 0 {
+		stmt = stmt.Order("id").Limit(req.Limit)
+	}
+
 	var orders []model.Order
 	if err := stmt.Find(&orders).Error; err != nil {
 		span.SetStatus(codes.Error, err.Error())
@@ -49,11 +49,12 @@ func TestCreateOrder(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, data model.Order) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`INSERT INTO "orders" ("user_id","product_id","quantity","total_price","status","expires_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`,
+							`INSERT INTO "orders" ("user_id","product_id","variant_id","quantity","total_price","status","expires_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`,
 						),
 					).WithArgs(
 						data.UserID,
 						data.ProductID,
+						data.VariantID,
 						data.Quantity,
 						data.TotalPrice,
 						data.Status,
@@ -81,11 +82,12 @@ func TestCreateOrder(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, data model.Order) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`INSERT INTO "orders" ("user_id","product_id","quantity","total_price","status","expires_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`,
+							`INSERT INTO "orders" ("user_id","product_id","variant_id","quantity","total_price","status","expires_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`,
 						),
 					).WithArgs(
 						data.UserID,
 						data.ProductID,
+						data.VariantID,
 						data.Quantity,
 						data.TotalPrice,
 						data.Status,
@@ -207,6 +209,39 @@ func TestGetOrders(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "success - page by ID",
+			req: payload.GetOrdersReq{
+				StatusIN: []string{"waiting"},
+				IDAfter:  userID.String(),
+				Limit:    100,
+			},
+			data: []model.Order{
+				{
+					UserID:     userID,
+					ProductID:  productID,
+					Quantity:   1,
+					TotalPrice: 50.0,
+					Status:     "waiting",
+				},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data []model.Order) {
+					rows := sqlmock.NewRows([]string{"id", "user_id", "product_id", "quantity", "total_price", "status", "created_at", "updated_at"})
+					for _, order := range data {
+						rows.AddRow(uuid.New(), order.UserID, order.ProductID, order.Quantity, order.TotalPrice, order.Status, time.Now(), time.Now())
+					}
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "orders" WHERE status IN ($1) AND id > $2 ORDER BY id LIMIT $3`),
+					).WithArgs(
+						data[0].Status,
+						userID.String(),
+						100,
+					).WillReturnRows(rows)
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "error - failed to get orders",
 			req:  payload.GetOrdersReq{},
@@ -354,10 +389,11 @@ func TestUpdateOrder(t *testing.T) {
 			sqlMock: sqlMock{
 				Setup: func(mockDB sqlmock.Sqlmock, data model.Order) {
 					mockDB.ExpectExec(
-						regexp.QuoteMeta(`UPDATE "orders" SET "user_id"=$1,"product_id"=$2,"quantity"=$3,"total_price"=$4,"status"=$5,"expires_at"=$6,"created_at"=$7,"updated_at"=$8 WHERE "id" = $9`),
+						regexp.QuoteMeta(`UPDATE "orders" SET "user_id"=$1,"product_id"=$2,"variant_id"=$3,"quantity"=$4,"total_price"=$5,"status"=$6,"expires_at"=$7,"created_at"=$8,"updated_at"=$9 WHERE "id" = $10`),
 					).WithArgs(
 						data.UserID,
 						data.ProductID,
+						data.VariantID,
 						data.Quantity,
 						data.TotalPrice,
 						data.Status,
@@ -384,10 +420,11 @@ func TestUpdateOrder(t *testing.T) {
 			sqlMock: sqlMock{
 				Setup: func(mockDB sqlmock.Sqlmock, data model.Order) {
 					mockDB.ExpectExec(
-						regexp.QuoteMeta(`UPDATE "orders" SET "user_id"=$1,"product_id"=$2,"quantity"=$3,"total_price"=$4,"status"=$5,"expires_at"=$6,"created_at"=$7,"updated_at"=$8 WHERE "id" = $9`),
+						regexp.QuoteMeta(`UPDATE "orders" SET "user_id"=$1,"product_id"=$2,"variant_id"=$3,"quantity"=$4,"total_price"=$5,"status"=$6,"expires_at"=$7,"created_at"=$8,"updated_at"=$9 WHERE "id" = $10`),
 					).WithArgs(
 						data.UserID,
 						data.ProductID,
+						data.VariantID,
 						data.Quantity,
 						data.TotalPrice,
 						data.Status,
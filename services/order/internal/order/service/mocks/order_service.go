@@ -15,6 +15,34 @@ type OrderService struct {
 	mock.Mock
 }
 
+// CancelOrder provides a mock function with given fields: ctx, req
+func (_m *OrderService) CancelOrder(ctx context.Context, req payload.CancelOrderReq) (model.Order, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelOrder")
+	}
+
+	var r0 model.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.CancelOrderReq) (model.Order, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.CancelOrderReq) model.Order); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(model.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.CancelOrderReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CompleteOrder provides a mock function with given fields: ctx, req
 func (_m *OrderService) CompleteOrder(ctx context.Context, req payload.CompleteOrderReq) (model.Order, error) {
 	ret := _m.Called(ctx, req)
@@ -101,6 +129,24 @@ func (_m *OrderService) GetOrders(ctx context.Context, req payload.GetOrdersReq)
 	return r0, r1
 }
 
+// ProcessBackorderedOrders provides a mock function with given fields: ctx
+func (_m *OrderService) ProcessBackorderedOrders(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProcessBackorderedOrders")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ProcessExpiredOrders provides a mock function with given fields: ctx
 func (_m *OrderService) ProcessExpiredOrders(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/alifmufthi91/ecommerce-system/services/order/config"
@@ -24,7 +25,9 @@ type OrderService interface {
 	GetOrders(ctx context.Context, req payload.GetOrdersReq) ([]model.Order, error)
 	CreateOrder(ctx context.Context, req payload.CreateOrderReq) (model.Order, error)
 	CompleteOrder(ctx context.Context, req payload.CompleteOrderReq) (model.Order, error)
+	CancelOrder(ctx context.Context, req payload.CancelOrderReq) (model.Order, error)
 	ProcessExpiredOrders(ctx context.Context) error
+	ProcessBackorderedOrders(ctx context.Context) error
 }
 
 type orderService struct {
@@ -89,17 +92,44 @@ func (s *orderService) CreateOrder(ctx context.Context, req payload.CreateOrderR
 	if resp.Data.ID == uuid.Nil {
 		return model.Order{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product not found")
 	}
+	if !resp.Data.IsAvailable() {
+		return model.Order{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product is no longer available")
+	}
+	if err := s.checkBundleComponentsAvailable(ctx, resp.Data, req.Token); err != nil {
+		return model.Order{}, err
+	}
+
+	variant, err := orderedVariant(resp.Data, req.VariantID)
+	if err != nil {
+		return model.Order{}, err
+	}
+
+	// the product service prices it from the price history and sales, so the
+	// order is priced as of now
+	priceReq := productservice.GetEffectivePriceReq{
+		ProductID: req.ProductID.String(),
+		At:        time.Now(),
+		Token:     req.Token,
+	}
+	if variant != nil {
+		priceReq.VariantID = variant.ID.String()
+	}
+	priceResp, err := s.productSvc.GetEffectivePrice(ctx, priceReq)
+	if err != nil {
+		return model.Order{}, err
+	}
 
 	tx := s.db.Begin()
 	defer tx.Rollback()
 
 	order := model.Order{
 		ProductID:  req.ProductID,
+		VariantID:  req.VariantID,
 		Quantity:   req.Quantity,
 		UserID:     userId,
 		Status:     constant.OrderStatusPending,
 		ExpiresAt:  time.Now().Add(time.Second * constant.OrderExpirationTime),
-		TotalPrice: resp.Data.Price * float64(req.Quantity),
+		TotalPrice: priceResp.Data.Price * float64(req.Quantity),
 	}
 
 	err = s.orderRepo.WithTX(tx).WithReturning().CreateOrder(ctx, &order)
@@ -108,16 +138,22 @@ func (s *orderService) CreateOrder(ctx context.Context, req payload.CreateOrderR
 	}
 
 	reservedStocks, err := s.warehouseSvc.ReserveStocks(ctx, warehouseservice.ReserveStocksReq{
-		Token: req.Token,
-		Stocks: []warehouseservice.ReserveStocksReqData{
-			{
-				ProductID: req.ProductID.String(),
-				Quantity:  req.Quantity,
-			},
-		},
+		Token:    req.Token,
+		Stocks:   reserveStocksData(resp.Data, variant, req.Quantity),
+		OrderRef: order.ID.String(),
 	})
+	if err != nil {
+		return model.Order{}, err
+	}
 
+	// a backordered order waits for its stock, it is locked once allocated
+	var backordered bool
 	for _, stock := range reservedStocks.Data {
+		if stock.BackorderID != "" {
+			backordered = true
+			continue
+		}
+
 		err := s.stockLockRepo.WithTX(tx).CreateStockLock(ctx, &model.StockLock{
 			OrderID:     order.ID,
 			ProductID:   stock.ProductID,
@@ -129,6 +165,14 @@ func (s *orderService) CreateOrder(ctx context.Context, req payload.CreateOrderR
 		}
 	}
 
+	if backordered {
+		order.Status = constant.OrderStatusWaiting
+		order.ExpiresAt = time.Now().Add(time.Second * constant.BackorderExpirationTime)
+		if err := s.orderRepo.WithTX(tx).UpdateOrder(ctx, &order); err != nil {
+			return model.Order{}, err
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return model.Order{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
 	}
@@ -136,6 +180,81 @@ func (s *orderService) CreateOrder(ctx context.Context, req payload.CreateOrderR
 	return order, nil
 }
 
+// checkBundleComponentsAvailable checks every component of a bundle can still
+// be ordered, a bundle cannot be sold once one of them is archived or deleted.
+func (s *orderService) checkBundleComponentsAvailable(ctx context.Context, product productservice.GetProductByIDRespData, token string) error {
+	for _, item := range product.BundleItems {
+		resp, err := s.productSvc.GetProductByID(ctx, productservice.GetProductByIDReq{
+			ProductID: item.ComponentID.String(),
+			Token:     token,
+		})
+		if err != nil {
+			return err
+		}
+		if resp.Data.ID == uuid.Nil || !resp.Data.IsAvailable() {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "bundle component "+item.ComponentID.String()+" is no longer available")
+		}
+	}
+	return nil
+}
+
+// orderedVariant is the variant ordered of a product sold in variants, nil for
+// other products.
+func orderedVariant(product productservice.GetProductByIDRespData, variantID *uuid.UUID) (*productservice.GetProductByIDRespVariant, error) {
+	if len(product.Variants) == 0 {
+		if variantID != nil {
+			return nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product has no variants")
+		}
+		return nil, nil
+	}
+	if variantID == nil {
+		return nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "variant_id is required for a product sold in variants")
+	}
+
+	for _, variant := range product.Variants {
+		if variant.ID == *variantID {
+			return &variant, nil
+		}
+	}
+	return nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "variant not found")
+}
+
+// reserveStocksData is the stock an order of the product holds. A bundle holds
+// its components, so they are reserved together in one call, and the stock of
+// a product with variants is kept by variant.
+func reserveStocksData(product productservice.GetProductByIDRespData, variant *productservice.GetProductByIDRespVariant, quantity int) []warehouseservice.ReserveStocksReqData {
+	var shopID string
+	if product.ShopID != uuid.Nil {
+		shopID = product.ShopID.String()
+	}
+
+	if variant != nil {
+		return []warehouseservice.ReserveStocksReqData{{
+			ProductID: variant.ID.String(),
+			Quantity:  quantity,
+			ShopID:    shopID,
+		}}
+	}
+
+	if len(product.BundleItems) == 0 {
+		return []warehouseservice.ReserveStocksReqData{{
+			ProductID: product.ID.String(),
+			Quantity:  quantity,
+			ShopID:    shopID,
+		}}
+	}
+
+	stocks := make([]warehouseservice.ReserveStocksReqData, 0, len(product.BundleItems))
+	for _, item := range product.BundleItems {
+		stocks = append(stocks, warehouseservice.ReserveStocksReqData{
+			ProductID: item.ComponentID.String(),
+			Quantity:  item.Quantity * quantity,
+			ShopID:    shopID,
+		})
+	}
+	return stocks
+}
+
 func (s *orderService) CompleteOrder(ctx context.Context, req payload.CompleteOrderReq) (result model.Order, err error) {
 	ctx, span := observ.GetTracer().Start(ctx, "orderService.CompleteOrder")
 	defer span.End()
@@ -173,8 +292,9 @@ func (s *orderService) CompleteOrder(ctx context.Context, req payload.CompleteOr
 	}
 
 	_, err = s.warehouseSvc.CommitReserves(ctx, warehouseservice.CommitReservesReq{
-		Token:  req.Token,
-		Stocks: commitStockLocks,
+		Token:    req.Token,
+		Stocks:   commitStockLocks,
+		OrderRef: order.ID.String(),
 	})
 	if err != nil {
 		return model.Order{}, err
@@ -192,8 +312,10 @@ func (s *orderService) CompleteOrder(ctx context.Context, req payload.CompleteOr
 	return order, nil
 }
 
-func (s *orderService) ProcessExpiredOrders(ctx context.Context) (err error) {
-	ctx, span := observ.GetTracer().Start(ctx, "orderService.ProcessExpiredOrders")
+// CancelOrder cancels an order that is waiting for its backorders or pending,
+// releasing the stock held for it.
+func (s *orderService) CancelOrder(ctx context.Context, req payload.CancelOrderReq) (result model.Order, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "orderService.CancelOrder")
 	defer span.End()
 	defer func() {
 		if err != nil {
@@ -202,22 +324,49 @@ func (s *orderService) ProcessExpiredOrders(ctx context.Context) (err error) {
 		}
 	}()
 
-	expiredOrders, err := s.orderRepo.GetOrders(ctx, payload.GetOrdersReq{
-		StatusIN:      []string{constant.OrderStatusPending},
-		ExpiresBefore: time.Now(),
-	})
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	order, err := s.orderRepo.WithTX(tx).WithLockForUpdate().GetOrderByID(ctx, req.OrderID)
 	if err != nil {
-		return err
+		return model.Order{}, err
 	}
 
-	for _, order := range expiredOrders {
-		tx := s.db.Begin()
-		defer tx.Rollback()
+	if order.Status != constant.OrderStatusWaiting && order.Status != constant.OrderStatusPending {
+		return model.Order{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "order is not in waiting or pending status")
+	}
+
+	if err := s.cancelOrder(ctx, tx, &order, req.Token); err != nil {
+		return model.Order{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return model.Order{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return order, nil
+}
 
-		stockLocks, err := s.stockLockRepo.WithTX(tx).GetStockLocksByOrderID(ctx, order.ID.String())
+// cancelOrder releases the stock held for the order and marks it cancelled. A
+// waiting order also gives up its backorders, which are cancelled first since
+// cancelling them again is a no-op while rolling back reserves is not.
+func (s *orderService) cancelOrder(ctx context.Context, tx *gorm.DB, order *model.Order, token string) error {
+	if order.Status == constant.OrderStatusWaiting {
+		err := s.warehouseSvc.CancelBackorders(ctx, warehouseservice.CancelBackordersReq{
+			Token:    token,
+			OrderRef: order.ID.String(),
+		})
 		if err != nil {
 			return err
 		}
+	}
+
+	stockLocks, err := s.stockLockRepo.WithTX(tx).GetStockLocksByOrderID(ctx, order.ID.String())
+	if err != nil {
+		return err
+	}
+
+	if len(stockLocks) > 0 {
 		var rollbackStockLocks []warehouseservice.RollbackReservesReqData
 		for _, stockLock := range stockLocks {
 			rollbackStockLocks = append(rollbackStockLocks, warehouseservice.RollbackReservesReqData{
@@ -228,21 +377,187 @@ func (s *orderService) ProcessExpiredOrders(ctx context.Context) (err error) {
 		}
 
 		err = s.warehouseSvc.RollbackReserves(ctx, warehouseservice.RollbackReservesReq{
-			Token:  s.config.External.WarehouseServiceStaticToken,
-			Stocks: rollbackStockLocks,
+			Token:    token,
+			Stocks:   rollbackStockLocks,
+			OrderRef: order.ID.String(),
 		})
 		if err != nil {
 			return err
 		}
+	}
 
-		order.Status = constant.OrderStatusCancelled
-		if err := s.orderRepo.WithTX(tx).UpdateOrder(ctx, &order); err != nil {
+	order.Status = constant.OrderStatusCancelled
+	return s.orderRepo.WithTX(tx).UpdateOrder(ctx, order)
+}
+
+// ProcessExpiredOrders cancels the pending orders that were not completed in
+// time and the waiting orders whose backorders were not allocated in time.
+func (s *orderService) ProcessExpiredOrders(ctx context.Context) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "orderService.ProcessExpiredOrders")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	now := time.Now()
+	expiredOrders, err := s.orderRepo.GetOrders(ctx, payload.GetOrdersReq{
+		StatusIN:      []string{constant.OrderStatusWaiting, constant.OrderStatusPending},
+		ExpiresBefore: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, order := range expiredOrders {
+		if err := s.expireOrder(ctx, order.ID.String(), now); err != nil {
 			return err
 		}
+	}
+
+	return nil
+}
 
-		if err := tx.Commit().Error; err != nil {
-			return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+// expireOrder cancels the order unless it was completed, cancelled or moved on
+// from waiting since it was found expired.
+func (s *orderService) expireOrder(ctx context.Context, orderID string, now time.Time) error {
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	order, err := s.orderRepo.WithTX(tx).WithLockForUpdate().GetOrderByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != constant.OrderStatusWaiting && order.Status != constant.OrderStatusPending || !order.ExpiresAt.Before(now) {
+		return nil
+	}
+
+	if err := s.cancelOrder(ctx, tx, &order, s.config.External.WarehouseServiceStaticToken); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// backorderedOrdersBatchSize is how many waiting orders are checked against
+// their backorders at a time.
+const backorderedOrdersBatchSize = 100
+
+// ProcessBackorderedOrders moves the waiting orders whose backorders have all
+// been allocated to pending, locking the allocated stock. The order expires
+// like any other order from then on. Waiting orders are paged through by ID.
+func (s *orderService) ProcessBackorderedOrders(ctx context.Context) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "orderService.ProcessBackorderedOrders")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	var idAfter string
+	for {
+		waitingOrders, err := s.orderRepo.GetOrders(ctx, payload.GetOrdersReq{
+			StatusIN: []string{constant.OrderStatusWaiting},
+			IDAfter:  idAfter,
+			Limit:    backorderedOrdersBatchSize,
+		})
+		if err != nil {
+			return err
 		}
+		if len(waitingOrders) == 0 {
+			return nil
+		}
+
+		if err := s.processBackorderedOrders(ctx, waitingOrders); err != nil {
+			return err
+		}
+
+		if len(waitingOrders) < backorderedOrdersBatchSize {
+			return nil
+		}
+		idAfter = waitingOrders[len(waitingOrders)-1].ID.String()
+	}
+}
+
+func (s *orderService) processBackorderedOrders(ctx context.Context, waitingOrders []model.Order) error {
+	var orderRefs []string
+	for _, order := range waitingOrders {
+		orderRefs = append(orderRefs, order.ID.String())
+	}
+
+	resp, err := s.warehouseSvc.GetBackorders(ctx, warehouseservice.GetBackordersReq{
+		Token:      s.config.External.WarehouseServiceStaticToken,
+		OrderRefIN: orderRefs,
+	})
+	if err != nil {
+		return err
+	}
+
+	backorders := make(map[string][]warehouseservice.Backorder)
+	for _, backorder := range resp.Data {
+		backorders[backorder.OrderRef] = append(backorders[backorder.OrderRef], backorder)
+	}
+
+	for _, order := range waitingOrders {
+		orderBackorders := backorders[order.ID.String()]
+		if len(orderBackorders) == 0 || slices.ContainsFunc(orderBackorders, func(b warehouseservice.Backorder) bool {
+			return b.Status != constant.BackorderStatusAllocated
+		}) {
+			continue
+		}
+
+		if err := s.allocateBackorderedOrder(ctx, order.ID.String(), orderBackorders); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// allocateBackorderedOrder locks the stock allocated to the order's backorders
+// and makes it pending, unless it was cancelled or expired in the meantime.
+func (s *orderService) allocateBackorderedOrder(ctx context.Context, orderID string, backorders []warehouseservice.Backorder) error {
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	order, err := s.orderRepo.WithTX(tx).WithLockForUpdate().GetOrderByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != constant.OrderStatusWaiting {
+		return nil
+	}
+
+	for _, backorder := range backorders {
+		for _, allocation := range backorder.Allocations {
+			err := s.stockLockRepo.WithTX(tx).CreateStockLock(ctx, &model.StockLock{
+				OrderID:     order.ID,
+				ProductID:   backorder.ProductID,
+				Quantity:    allocation.Quantity,
+				WarehouseID: allocation.WarehouseID,
+			})
+			if err != nil {
+				return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create stock lock")
+			}
+		}
+	}
+
+	order.Status = constant.OrderStatusPending
+	order.ExpiresAt = time.Now().Add(time.Second * constant.OrderExpirationTime)
+	if err := s.orderRepo.WithTX(tx).UpdateOrder(ctx, &order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 
@@ -142,12 +143,67 @@ func TestCreateOrder_ShouldSuccess(t *testing.T) {
 	assert.NoError(t, err)
 
 	productID := uuid.New()
+	shopID := uuid.New()
 	userID := uuid.New()
+	orderID := uuid.New()
+
+	componentID1 := uuid.New()
+	componentID2 := uuid.New()
+	variantID := uuid.New()
+
+	expectOrder := func(m dependencyMocks, bundleItems ...productservice.GetProductByIDRespBundleItem) {
+		m.productSvc.On("GetProductByID", mock.Anything, productservice.GetProductByIDReq{
+			ProductID: productID.String(),
+			Token:     "test-token",
+		}).Return(productservice.GetProductByIDResp{
+			Data: productservice.GetProductByIDRespData{
+				ID:          productID,
+				ShopID:      shopID,
+				Price:       80.0,
+				BundleItems: bundleItems,
+			},
+		}, nil)
+		for _, item := range bundleItems {
+			m.productSvc.On("GetProductByID", mock.Anything, productservice.GetProductByIDReq{
+				ProductID: item.ComponentID.String(),
+				Token:     "test-token",
+			}).Return(productservice.GetProductByIDResp{
+				Data: productservice.GetProductByIDRespData{ID: item.ComponentID, ShopID: shopID},
+			}, nil)
+		}
+		// a sale prices the product below its listed price
+		m.productSvc.On("GetEffectivePrice", mock.Anything, mock.MatchedBy(func(req productservice.GetEffectivePriceReq) bool {
+			return req.ProductID == productID.String() && req.VariantID == "" && !req.At.IsZero() && req.Token == "test-token"
+		})).Return(productservice.GetEffectivePriceResp{
+			Data: productservice.GetEffectivePriceRespData{ProductID: productID, RegularPrice: 80.0, Price: 50.0},
+		}, nil)
+
+		m.db.ExpectBegin()
+		m.orderRepo.On("WithTX", mock.Anything).Return(m.orderRepo)
+		m.orderRepo.On("WithReturning").Return(m.orderRepo)
+		m.orderRepo.On("CreateOrder", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				order := args.Get(1).(*model.Order)
+				order.ID = orderID // Simulate DB setting ID
+			}).Return(nil)
+	}
+	reserveReq := warehouseservice.ReserveStocksReq{
+		Token: "test-token",
+		Stocks: []warehouseservice.ReserveStocksReqData{
+			{
+				ProductID: productID.String(),
+				ShopID:    shopID.String(),
+				Quantity:  2,
+			},
+		},
+		OrderRef: orderID.String(),
+	}
 
 	tests := []struct {
-		name  string
-		req   payload.CreateOrderReq
-		setup func(m dependencyMocks)
+		name           string
+		req            payload.CreateOrderReq
+		setup          func(m dependencyMocks)
+		expectedStatus string
 	}{
 		{
 			name: "success",
@@ -158,38 +214,162 @@ func TestCreateOrder_ShouldSuccess(t *testing.T) {
 				Token:     "test-token",
 			},
 			setup: func(m dependencyMocks) {
-				m.productSvc.On("GetProductByID", mock.Anything, productservice.GetProductByIDReq{
-					ProductID: productID.String(),
-					Token:     "test-token",
-				}).Return(productservice.GetProductByIDResp{
+				expectOrder(m)
+
+				// Mock warehouse service
+				m.warehouseSvc.On("ReserveStocks", mock.Anything, reserveReq).Return(warehouseservice.ReserveStocksResp{
+					Data: []warehouseservice.ReserveStocksRespData{
+						{
+							ProductID:        productID,
+							ReservedQuantity: 2,
+							WarehouseID:      uuid.New(),
+						},
+					},
+				}, nil)
+
+				m.stockLockRepo.On("WithTX", mock.Anything).Return(m.stockLockRepo)
+				m.stockLockRepo.On("CreateStockLock", mock.Anything, mock.Anything).
+					Return(nil)
+				m.db.ExpectCommit()
+			},
+			expectedStatus: constant.OrderStatusPending,
+		},
+		{
+			name: "success - backordered order waits for stock",
+			req: payload.CreateOrderReq{
+				UserID:    userID.String(),
+				ProductID: productID,
+				Quantity:  2,
+				Token:     "test-token",
+			},
+			setup: func(m dependencyMocks) {
+				expectOrder(m)
+
+				m.warehouseSvc.On("ReserveStocks", mock.Anything, reserveReq).Return(warehouseservice.ReserveStocksResp{
+					Data: []warehouseservice.ReserveStocksRespData{
+						{
+							ProductID:         productID,
+							BackorderID:       uuid.New().String(),
+							BackorderQuantity: 2,
+						},
+					},
+				}, nil)
+
+				m.orderRepo.On("UpdateOrder", mock.Anything, mock.MatchedBy(func(order *model.Order) bool {
+					// it waits for its backorders far longer than a pending order
+					return order.ID == orderID && order.Status == constant.OrderStatusWaiting &&
+						order.ExpiresAt.After(time.Now().Add(time.Second*constant.OrderExpirationTime))
+				})).Return(nil)
+				m.db.ExpectCommit()
+			},
+			expectedStatus: constant.OrderStatusWaiting,
+		},
+		{
+			name: "success - bundle reserves its components",
+			req: payload.CreateOrderReq{
+				UserID:    userID.String(),
+				ProductID: productID,
+				Quantity:  2,
+				Token:     "test-token",
+			},
+			setup: func(m dependencyMocks) {
+				expectOrder(m,
+					productservice.GetProductByIDRespBundleItem{ComponentID: componentID1, Quantity: 3},
+					productservice.GetProductByIDRespBundleItem{ComponentID: componentID2, Quantity: 1},
+				)
+
+				m.warehouseSvc.On("ReserveStocks", mock.Anything, warehouseservice.ReserveStocksReq{
+					Token: "test-token",
+					Stocks: []warehouseservice.ReserveStocksReqData{
+						{
+							ProductID: componentID1.String(),
+							ShopID:    shopID.String(),
+							Quantity:  6,
+						},
+						{
+							ProductID: componentID2.String(),
+							ShopID:    shopID.String(),
+							Quantity:  2,
+						},
+					},
+					OrderRef: orderID.String(),
+				}).Return(warehouseservice.ReserveStocksResp{
+					Data: []warehouseservice.ReserveStocksRespData{
+						{
+							ProductID:        componentID1,
+							ReservedQuantity: 6,
+							WarehouseID:      uuid.New(),
+						},
+						{
+							ProductID:        componentID2,
+							ReservedQuantity: 2,
+							WarehouseID:      uuid.New(),
+						},
+					},
+				}, nil)
+
+				m.stockLockRepo.On("WithTX", mock.Anything).Return(m.stockLockRepo)
+				m.stockLockRepo.On("CreateStockLock", mock.Anything, mock.MatchedBy(func(lock *model.StockLock) bool {
+					return lock.ProductID == componentID1 && lock.Quantity == 6
+				})).Return(nil).Once()
+				m.stockLockRepo.On("CreateStockLock", mock.Anything, mock.MatchedBy(func(lock *model.StockLock) bool {
+					return lock.ProductID == componentID2 && lock.Quantity == 2
+				})).Return(nil).Once()
+				m.db.ExpectCommit()
+			},
+			expectedStatus: constant.OrderStatusPending,
+		},
+		{
+			name: "success - variant reserves its own stock at its price",
+			req: payload.CreateOrderReq{
+				UserID:    userID.String(),
+				ProductID: productID,
+				VariantID: &variantID,
+				Quantity:  2,
+				Token:     "test-token",
+			},
+			setup: func(m dependencyMocks) {
+				variantPrice := 50.0
+				m.productSvc.On("GetProductByID", mock.Anything, mock.Anything).Return(productservice.GetProductByIDResp{
 					Data: productservice.GetProductByIDRespData{
-						ID:    productID,
-						Price: 50.0,
+						ID:     productID,
+						ShopID: shopID,
+						Price:  80.0,
+						Variants: []productservice.GetProductByIDRespVariant{
+							{ID: uuid.New(), SKU: "SHIRT-M"},
+							{ID: variantID, SKU: "SHIRT-L", Price: &variantPrice},
+						},
 					},
 				}, nil)
+				m.productSvc.On("GetEffectivePrice", mock.Anything, mock.MatchedBy(func(req productservice.GetEffectivePriceReq) bool {
+					return req.ProductID == productID.String() && req.VariantID == variantID.String()
+				})).Return(productservice.GetEffectivePriceResp{
+					Data: productservice.GetEffectivePriceRespData{ProductID: productID, VariantID: &variantID, RegularPrice: variantPrice, Price: variantPrice},
+				}, nil)
 
 				m.db.ExpectBegin()
 				m.orderRepo.On("WithTX", mock.Anything).Return(m.orderRepo)
 				m.orderRepo.On("WithReturning").Return(m.orderRepo)
-				m.orderRepo.On("CreateOrder", mock.Anything, mock.Anything).
-					Run(func(args mock.Arguments) {
-						order := args.Get(1).(*model.Order)
-						order.ID = uuid.New() // Simulate DB setting ID
-					}).Return(nil)
+				m.orderRepo.On("CreateOrder", mock.Anything, mock.MatchedBy(func(order *model.Order) bool {
+					return order.VariantID != nil && *order.VariantID == variantID
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*model.Order).ID = orderID
+				}).Return(nil)
 
-				// Mock warehouse service
 				m.warehouseSvc.On("ReserveStocks", mock.Anything, warehouseservice.ReserveStocksReq{
 					Token: "test-token",
 					Stocks: []warehouseservice.ReserveStocksReqData{
 						{
-							ProductID: productID.String(),
+							ProductID: variantID.String(),
+							ShopID:    shopID.String(),
 							Quantity:  2,
 						},
 					},
+					OrderRef: orderID.String(),
 				}).Return(warehouseservice.ReserveStocksResp{
 					Data: []warehouseservice.ReserveStocksRespData{
 						{
-							ProductID:        productID,
+							ProductID:        variantID,
 							ReservedQuantity: 2,
 							WarehouseID:      uuid.New(),
 						},
@@ -197,10 +377,12 @@ func TestCreateOrder_ShouldSuccess(t *testing.T) {
 				}, nil)
 
 				m.stockLockRepo.On("WithTX", mock.Anything).Return(m.stockLockRepo)
-				m.stockLockRepo.On("CreateStockLock", mock.Anything, mock.Anything).
-					Return(nil)
+				m.stockLockRepo.On("CreateStockLock", mock.Anything, mock.MatchedBy(func(lock *model.StockLock) bool {
+					return lock.ProductID == variantID && lock.Quantity == 2
+				})).Return(nil)
 				m.db.ExpectCommit()
 			},
+			expectedStatus: constant.OrderStatusPending,
 		},
 	}
 
@@ -235,7 +417,7 @@ func TestCreateOrder_ShouldSuccess(t *testing.T) {
 			assert.Equal(t, userID, result.UserID)
 			assert.Equal(t, 2, result.Quantity)
 			assert.Equal(t, 100.0, result.TotalPrice) // 50.0 * 2
-			assert.Equal(t, constant.OrderStatusPending, result.Status)
+			assert.Equal(t, tt.expectedStatus, result.Status)
 
 			mocks.orderRepo.AssertExpectations(t)
 			mocks.stockLockRepo.AssertExpectations(t)
@@ -258,6 +440,7 @@ func TestCreateOrder_ShouldReturnError(t *testing.T) {
 	assert.NoError(t, err)
 
 	productID := uuid.New()
+	componentID := uuid.New()
 	userID := uuid.New()
 
 	tests := []struct {
@@ -293,6 +476,172 @@ func TestCreateOrder_ShouldReturnError(t *testing.T) {
 			},
 			wantErr: "",
 		},
+		{
+			name: "error - product is archived",
+			req: payload.CreateOrderReq{
+				UserID:    userID.String(),
+				ProductID: productID,
+				Quantity:  2,
+				Token:     "test-token",
+			},
+			setup: func(m dependencyMocks) {
+				archivedAt := time.Now()
+				m.productSvc.On("GetProductByID", mock.Anything, mock.Anything).
+					Return(productservice.GetProductByIDResp{
+						Data: productservice.GetProductByIDRespData{ID: productID, Price: 50.0, ArchivedAt: &archivedAt},
+					}, nil)
+			},
+			wantErr: "product is no longer available",
+		},
+		{
+			name: "error - bundle component is archived",
+			req: payload.CreateOrderReq{
+				UserID:    userID.String(),
+				ProductID: productID,
+				Quantity:  2,
+				Token:     "test-token",
+			},
+			setup: func(m dependencyMocks) {
+				archivedAt := time.Now()
+				m.productSvc.On("GetProductByID", mock.Anything, productservice.GetProductByIDReq{
+					ProductID: productID.String(),
+					Token:     "test-token",
+				}).Return(productservice.GetProductByIDResp{
+					Data: productservice.GetProductByIDRespData{
+						ID:          productID,
+						Price:       50.0,
+						BundleItems: []productservice.GetProductByIDRespBundleItem{{ComponentID: componentID, Quantity: 1}},
+					},
+				}, nil)
+				m.productSvc.On("GetProductByID", mock.Anything, productservice.GetProductByIDReq{
+					ProductID: componentID.String(),
+					Token:     "test-token",
+				}).Return(productservice.GetProductByIDResp{
+					Data: productservice.GetProductByIDRespData{ID: componentID, ArchivedAt: &archivedAt},
+				}, nil)
+			},
+			wantErr: "bundle component " + componentID.String() + " is no longer available",
+		},
+		{
+			name: "error - variant is required",
+			req: payload.CreateOrderReq{
+				UserID:    userID.String(),
+				ProductID: productID,
+				Quantity:  2,
+				Token:     "test-token",
+			},
+			setup: func(m dependencyMocks) {
+				m.productSvc.On("GetProductByID", mock.Anything, mock.Anything).
+					Return(productservice.GetProductByIDResp{
+						Data: productservice.GetProductByIDRespData{
+							ID:       productID,
+							Price:    50.0,
+							Variants: []productservice.GetProductByIDRespVariant{{ID: uuid.New(), SKU: "SHIRT-M"}},
+						},
+					}, nil)
+			},
+			wantErr: "variant_id is required for a product sold in variants",
+		},
+		{
+			name: "error - variant of another product",
+			req: payload.CreateOrderReq{
+				UserID:    userID.String(),
+				ProductID: productID,
+				VariantID: &productID,
+				Quantity:  2,
+				Token:     "test-token",
+			},
+			setup: func(m dependencyMocks) {
+				m.productSvc.On("GetProductByID", mock.Anything, mock.Anything).
+					Return(productservice.GetProductByIDResp{
+						Data: productservice.GetProductByIDRespData{
+							ID:       productID,
+							Price:    50.0,
+							Variants: []productservice.GetProductByIDRespVariant{{ID: uuid.New(), SKU: "SHIRT-M"}},
+						},
+					}, nil)
+			},
+			wantErr: "variant not found",
+		},
+		{
+			name: "error - product has no variants",
+			req: payload.CreateOrderReq{
+				UserID:    userID.String(),
+				ProductID: productID,
+				VariantID: &productID,
+				Quantity:  2,
+				Token:     "test-token",
+			},
+			setup: func(m dependencyMocks) {
+				m.productSvc.On("GetProductByID", mock.Anything, mock.Anything).
+					Return(productservice.GetProductByIDResp{
+						Data: productservice.GetProductByIDRespData{ID: productID, Price: 50.0},
+					}, nil)
+			},
+			wantErr: "product has no variants",
+		},
+		{
+			name: "error - product is deleted",
+			req: payload.CreateOrderReq{
+				UserID:    userID.String(),
+				ProductID: productID,
+				Quantity:  2,
+				Token:     "test-token",
+			},
+			setup: func(m dependencyMocks) {
+				deletedAt := time.Now()
+				m.productSvc.On("GetProductByID", mock.Anything, mock.Anything).
+					Return(productservice.GetProductByIDResp{
+						Data: productservice.GetProductByIDRespData{ID: productID, Price: 50.0, DeletedAt: &deletedAt},
+					}, nil)
+			},
+			wantErr: "product is no longer available",
+		},
+		{
+			name: "error - effective price failure",
+			req: payload.CreateOrderReq{
+				UserID:    userID.String(),
+				ProductID: productID,
+				Quantity:  2,
+				Token:     "test-token",
+			},
+			setup: func(m dependencyMocks) {
+				m.productSvc.On("GetProductByID", mock.Anything, mock.Anything).
+					Return(productservice.GetProductByIDResp{
+						Data: productservice.GetProductByIDRespData{ID: productID, Price: 50.0},
+					}, nil)
+				m.productSvc.On("GetEffectivePrice", mock.Anything, mock.Anything).
+					Return(productservice.GetEffectivePriceResp{}, assert.AnError)
+			},
+			wantErr: "",
+		},
+		{
+			name: "error - warehouse service failure",
+			req: payload.CreateOrderReq{
+				UserID:    userID.String(),
+				ProductID: productID,
+				Quantity:  2,
+				Token:     "test-token",
+			},
+			setup: func(m dependencyMocks) {
+				m.productSvc.On("GetProductByID", mock.Anything, mock.Anything).
+					Return(productservice.GetProductByIDResp{
+						Data: productservice.GetProductByIDRespData{ID: productID, Price: 50.0},
+					}, nil)
+				m.productSvc.On("GetEffectivePrice", mock.Anything, mock.Anything).
+					Return(productservice.GetEffectivePriceResp{
+						Data: productservice.GetEffectivePriceRespData{ProductID: productID, RegularPrice: 50.0, Price: 50.0},
+					}, nil)
+				m.db.ExpectBegin()
+				m.orderRepo.On("WithTX", mock.Anything).Return(m.orderRepo)
+				m.orderRepo.On("WithReturning").Return(m.orderRepo)
+				m.orderRepo.On("CreateOrder", mock.Anything, mock.Anything).Return(nil)
+				m.warehouseSvc.On("ReserveStocks", mock.Anything, mock.Anything).
+					Return(warehouseservice.ReserveStocksResp{}, assert.AnError)
+				m.db.ExpectRollback()
+			},
+			wantErr: "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -395,6 +744,7 @@ func TestCompleteOrder_ShouldSuccess(t *testing.T) {
 							Quantity:    2,
 						},
 					},
+					OrderRef: orderID.String(),
 				}).Return(warehouseservice.CommitReservesResp{}, nil)
 
 				// Mock update order
@@ -650,37 +1000,43 @@ func TestProcessExpiredOrders_ShouldSuccess(t *testing.T) {
 	warehouseID2 := uuid.New()
 	expiredTime := time.Now().Add(-time.Hour) // 1 hour ago
 
+	pendingOrder := model.Order{
+		ID:         orderID1,
+		UserID:     uuid.New(),
+		ProductID:  productID1,
+		Quantity:   2,
+		TotalPrice: 100.0,
+		Status:     constant.OrderStatusPending,
+		ExpiresAt:  expiredTime,
+	}
+	waitingOrder := model.Order{
+		ID:         orderID2,
+		UserID:     uuid.New(),
+		ProductID:  productID2,
+		Quantity:   1,
+		TotalPrice: 50.0,
+		Status:     constant.OrderStatusWaiting,
+		ExpiresAt:  expiredTime,
+	}
+
 	tests := []struct {
 		name  string
 		setup func(m dependencyMocks)
 	}{
 		{
-			name: "success - process multiple expired orders",
+			name: "success - process expired pending and waiting orders",
 			setup: func(m dependencyMocks) {
 				// Mock get expired orders
-				m.orderRepo.On("GetOrders", mock.Anything, mock.Anything).Return([]model.Order{
-					{
-						ID:         orderID1,
-						UserID:     uuid.New(),
-						ProductID:  productID1,
-						Quantity:   2,
-						TotalPrice: 100.0,
-						Status:     constant.OrderStatusPending,
-						ExpiresAt:  expiredTime,
-					},
-					{
-						ID:         orderID2,
-						UserID:     uuid.New(),
-						ProductID:  productID2,
-						Quantity:   1,
-						TotalPrice: 50.0,
-						Status:     constant.OrderStatusPending,
-						ExpiresAt:  expiredTime,
-					},
-				}, nil)
+				m.orderRepo.On("GetOrders", mock.Anything, mock.MatchedBy(func(req payload.GetOrdersReq) bool {
+					return slices.Equal(req.StatusIN, []string{constant.OrderStatusWaiting, constant.OrderStatusPending}) &&
+						!req.ExpiresBefore.IsZero()
+				})).Return([]model.Order{pendingOrder, waitingOrder}, nil)
 
-				// Mock transaction for first order
+				// Mock transaction for the pending order
 				m.db.ExpectBegin()
+				m.orderRepo.On("WithTX", mock.Anything).Return(m.orderRepo)
+				m.orderRepo.On("WithLockForUpdate").Return(m.orderRepo)
+				m.orderRepo.On("GetOrderByID", mock.Anything, orderID1.String()).Return(pendingOrder, nil)
 				m.stockLockRepo.On("WithTX", mock.Anything).Return(m.stockLockRepo)
 				m.stockLockRepo.On("GetStockLocksByOrderID", mock.Anything, orderID1.String()).Return([]model.StockLock{
 					{
@@ -692,7 +1048,7 @@ func TestProcessExpiredOrders_ShouldSuccess(t *testing.T) {
 					},
 				}, nil)
 
-				// Mock warehouse service rollback for first order
+				// Mock warehouse service rollback for the pending order
 				m.warehouseSvc.On("RollbackReserves", mock.Anything, warehouseservice.RollbackReservesReq{
 					Token: "static-token", // from config
 					Stocks: []warehouseservice.RollbackReservesReqData{
@@ -702,17 +1058,22 @@ func TestProcessExpiredOrders_ShouldSuccess(t *testing.T) {
 							Quantity:    2,
 						},
 					},
+					OrderRef: orderID1.String(),
 				}).Return(nil)
 
-				// Mock update order for first order
-				m.orderRepo.On("WithTX", mock.Anything).Return(m.orderRepo)
 				m.orderRepo.On("UpdateOrder", mock.Anything, mock.MatchedBy(func(order *model.Order) bool {
 					return order.ID == orderID1 && order.Status == constant.OrderStatusCancelled
 				})).Return(nil)
 				m.db.ExpectCommit()
 
-				// Mock transaction for second order
+				// Mock transaction for the waiting order, its backorders are
+				// cancelled along with what it holds of the stock
 				m.db.ExpectBegin()
+				m.orderRepo.On("GetOrderByID", mock.Anything, orderID2.String()).Return(waitingOrder, nil)
+				m.warehouseSvc.On("CancelBackorders", mock.Anything, warehouseservice.CancelBackordersReq{
+					Token:    "static-token",
+					OrderRef: orderID2.String(),
+				}).Return(nil)
 				m.stockLockRepo.On("GetStockLocksByOrderID", mock.Anything, orderID2.String()).Return([]model.StockLock{
 					{
 						ID:          uuid.New(),
@@ -722,10 +1083,8 @@ func TestProcessExpiredOrders_ShouldSuccess(t *testing.T) {
 						WarehouseID: warehouseID2,
 					},
 				}, nil)
-
-				// Mock warehouse service rollback for second order
 				m.warehouseSvc.On("RollbackReserves", mock.Anything, warehouseservice.RollbackReservesReq{
-					Token: "static-token", // from config
+					Token: "static-token",
 					Stocks: []warehouseservice.RollbackReservesReqData{
 						{
 							ProductID:   productID2.String(),
@@ -733,15 +1092,30 @@ func TestProcessExpiredOrders_ShouldSuccess(t *testing.T) {
 							Quantity:    1,
 						},
 					},
+					OrderRef: orderID2.String(),
 				}).Return(nil)
-
-				// Mock update order for second order
 				m.orderRepo.On("UpdateOrder", mock.Anything, mock.MatchedBy(func(order *model.Order) bool {
 					return order.ID == orderID2 && order.Status == constant.OrderStatusCancelled
 				})).Return(nil)
 				m.db.ExpectCommit()
 			},
 		},
+		{
+			name: "success - order moved on since it was found expired",
+			setup: func(m dependencyMocks) {
+				m.orderRepo.On("GetOrders", mock.Anything, mock.Anything).Return([]model.Order{waitingOrder}, nil)
+
+				m.db.ExpectBegin()
+				m.orderRepo.On("WithTX", mock.Anything).Return(m.orderRepo)
+				m.orderRepo.On("WithLockForUpdate").Return(m.orderRepo)
+				m.orderRepo.On("GetOrderByID", mock.Anything, orderID2.String()).Return(model.Order{
+					ID:        orderID2,
+					Status:    constant.OrderStatusPending,
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil)
+				m.db.ExpectRollback()
+			},
+		},
 		{
 			name: "success - no expired orders",
 			setup: func(m dependencyMocks) {
@@ -780,9 +1154,7 @@ func TestProcessExpiredOrders_ShouldSuccess(t *testing.T) {
 
 			// Then
 			assert.NoError(t, err)
-			mocks.orderRepo.AssertExpectations(t)
-			mocks.stockLockRepo.AssertExpectations(t)
-			mocks.warehouseSvc.AssertExpectations(t)
+			assert.NoError(t, mockDB.Mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -803,6 +1175,33 @@ func TestProcessExpiredOrders_ShouldReturnError(t *testing.T) {
 	warehouseID := uuid.New()
 	expiredTime := time.Now().Add(-time.Hour)
 
+	expiredOrder := model.Order{
+		ID:         orderID,
+		UserID:     uuid.New(),
+		ProductID:  productID,
+		Quantity:   2,
+		TotalPrice: 100.0,
+		Status:     constant.OrderStatusPending,
+		ExpiresAt:  expiredTime,
+	}
+	expectExpiredOrder := func(m dependencyMocks, order model.Order) {
+		m.orderRepo.On("GetOrders", mock.Anything, mock.Anything).Return([]model.Order{order}, nil)
+
+		m.db.ExpectBegin()
+		m.orderRepo.On("WithTX", mock.Anything).Return(m.orderRepo)
+		m.orderRepo.On("WithLockForUpdate").Return(m.orderRepo)
+		m.orderRepo.On("GetOrderByID", mock.Anything, orderID.String()).Return(order, nil)
+	}
+	stockLocks := []model.StockLock{
+		{
+			ID:          uuid.New(),
+			OrderID:     orderID,
+			ProductID:   productID,
+			Quantity:    2,
+			WarehouseID: warehouseID,
+		},
+	}
+
 	tests := []struct {
 		name    string
 		setup   func(m dependencyMocks)
@@ -817,92 +1216,338 @@ func TestProcessExpiredOrders_ShouldReturnError(t *testing.T) {
 			wantErr: "",
 		},
 		{
-			name: "error - failed to get stock locks",
+			name: "error - failed to lock order",
 			setup: func(m dependencyMocks) {
-				m.orderRepo.On("GetOrders", mock.Anything, mock.Anything).Return([]model.Order{
-					{
-						ID:         orderID,
-						UserID:     uuid.New(),
-						ProductID:  productID,
-						Quantity:   2,
-						TotalPrice: 100.0,
-						Status:     constant.OrderStatusPending,
-						ExpiresAt:  expiredTime,
-					},
-				}, nil)
+				m.orderRepo.On("GetOrders", mock.Anything, mock.Anything).Return([]model.Order{expiredOrder}, nil)
 
 				m.db.ExpectBegin()
-				m.stockLockRepo.On("WithTX", mock.Anything).Return(m.stockLockRepo)
+				m.orderRepo.On("WithTX", mock.Anything).Return(m.orderRepo)
+				m.orderRepo.On("WithLockForUpdate").Return(m.orderRepo)
+				m.orderRepo.On("GetOrderByID", mock.Anything, orderID.String()).Return(model.Order{}, assert.AnError)
+				m.db.ExpectRollback()
+			},
+			wantErr: "",
+		},
+		{
+			name: "error - failed to get stock locks",
+			setup: func(m dependencyMocks) {
+				expectExpiredOrder(m, expiredOrder)
+				m.stockLockRepo.On("WithTX", mock.Anything).Return(m.stockLockRepo)
 				m.stockLockRepo.On("GetStockLocksByOrderID", mock.Anything, orderID.String()).
 					Return(nil, assert.AnError)
+				m.db.ExpectRollback()
 			},
 			wantErr: "",
 		},
 		{
 			name: "error - warehouse service rollback failure",
 			setup: func(m dependencyMocks) {
-				m.orderRepo.On("GetOrders", mock.Anything, mock.Anything).Return([]model.Order{
-					{
-						ID:         orderID,
-						UserID:     uuid.New(),
-						ProductID:  productID,
-						Quantity:   2,
-						TotalPrice: 100.0,
-						Status:     constant.OrderStatusPending,
-						ExpiresAt:  expiredTime,
-					},
-				}, nil)
-
-				m.db.ExpectBegin()
+				expectExpiredOrder(m, expiredOrder)
 				m.stockLockRepo.On("WithTX", mock.Anything).Return(m.stockLockRepo)
-				m.stockLockRepo.On("GetStockLocksByOrderID", mock.Anything, orderID.String()).Return([]model.StockLock{
-					{
-						ID:          uuid.New(),
-						OrderID:     orderID,
-						ProductID:   productID,
-						Quantity:    2,
-						WarehouseID: warehouseID,
-					},
-				}, nil)
+				m.stockLockRepo.On("GetStockLocksByOrderID", mock.Anything, orderID.String()).Return(stockLocks, nil)
 
 				m.warehouseSvc.On("RollbackReserves", mock.Anything, mock.Anything).
 					Return(assert.AnError)
+				m.db.ExpectRollback()
+			},
+			wantErr: "",
+		},
+		{
+			name: "error - warehouse service cancel backorders failure",
+			setup: func(m dependencyMocks) {
+				waitingOrder := expiredOrder
+				waitingOrder.Status = constant.OrderStatusWaiting
+				expectExpiredOrder(m, waitingOrder)
+
+				m.warehouseSvc.On("CancelBackorders", mock.Anything, mock.Anything).
+					Return(assert.AnError)
+				m.db.ExpectRollback()
 			},
 			wantErr: "",
 		},
 		{
 			name: "error - failed to update order",
 			setup: func(m dependencyMocks) {
-				m.orderRepo.On("GetOrders", mock.Anything, mock.Anything).Return([]model.Order{
-					{
-						ID:         orderID,
-						UserID:     uuid.New(),
-						ProductID:  productID,
-						Quantity:   2,
-						TotalPrice: 100.0,
-						Status:     constant.OrderStatusPending,
-						ExpiresAt:  expiredTime,
+				expectExpiredOrder(m, expiredOrder)
+				m.stockLockRepo.On("WithTX", mock.Anything).Return(m.stockLockRepo)
+				m.stockLockRepo.On("GetStockLocksByOrderID", mock.Anything, orderID.String()).Return(stockLocks, nil)
+
+				m.warehouseSvc.On("RollbackReserves", mock.Anything, mock.Anything).Return(nil)
+
+				m.orderRepo.On("UpdateOrder", mock.Anything, mock.Anything).Return(assert.AnError)
+				m.db.ExpectRollback()
+			},
+			wantErr: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:            mockDB.Mock,
+				orderRepo:     orderRepoMock.NewOrderRepository(t),
+				stockLockRepo: stockLockRepoMock.NewStockLockRepository(t),
+				warehouseSvc:  warehouseSvcMock.NewIWarehouseSvc(t),
+			}
+
+			orderSvc := orderService{
+				config: &config.Config{
+					External: config.External{
+						WarehouseServiceStaticToken: "static-token",
+					},
+				},
+				db:            mockDB.Db,
+				orderRepo:     mocks.orderRepo,
+				stockLockRepo: mocks.stockLockRepo,
+				warehouseSvc:  mocks.warehouseSvc,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := orderSvc.ProcessExpiredOrders(context.Background())
+
+			// Then
+			assert.Error(t, err)
+			if tt.wantErr != "" {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+			assert.NoError(t, mockDB.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestProcessBackorderedOrders_ShouldSuccess(t *testing.T) {
+	type dependencyMocks struct {
+		db            sqlmock.Sqlmock
+		orderRepo     *orderRepoMock.OrderRepository
+		stockLockRepo *stockLockRepoMock.StockLockRepository
+		warehouseSvc  *warehouseSvcMock.IWarehouseSvc
+	}
+
+	mockDB, err := pkg.SetupMockDB()
+	assert.NoError(t, err)
+
+	allocatedOrderID := uuid.New()
+	waitingOrderID := uuid.New()
+	productID := uuid.New()
+	warehouseID1 := uuid.New()
+	warehouseID2 := uuid.New()
+
+	tests := []struct {
+		name  string
+		setup func(m dependencyMocks)
+	}{
+		{
+			name: "success - allocated order becomes pending",
+			setup: func(m dependencyMocks) {
+				m.orderRepo.On("GetOrders", mock.Anything, payload.GetOrdersReq{
+					StatusIN: []string{constant.OrderStatusWaiting},
+					Limit:    backorderedOrdersBatchSize,
+				}).Return([]model.Order{
+					{ID: allocatedOrderID, ProductID: productID, Quantity: 3, Status: constant.OrderStatusWaiting},
+					{ID: waitingOrderID, ProductID: productID, Quantity: 2, Status: constant.OrderStatusWaiting},
+				}, nil)
+
+				m.warehouseSvc.On("GetBackorders", mock.Anything, warehouseservice.GetBackordersReq{
+					Token:      "static-token",
+					OrderRefIN: []string{allocatedOrderID.String(), waitingOrderID.String()},
+				}).Return(warehouseservice.GetBackordersResp{
+					Data: []warehouseservice.Backorder{
+						{
+							OrderRef:          allocatedOrderID.String(),
+							ProductID:         productID,
+							Quantity:          3,
+							AllocatedQuantity: 3,
+							Status:            constant.BackorderStatusAllocated,
+							Allocations: []warehouseservice.BackorderAllocation{
+								{WarehouseID: warehouseID1, Quantity: 1},
+								{WarehouseID: warehouseID2, Quantity: 2},
+							},
+						},
+						{
+							OrderRef:          waitingOrderID.String(),
+							ProductID:         productID,
+							Quantity:          2,
+							AllocatedQuantity: 1,
+							Status:            "waiting",
+						},
 					},
 				}, nil)
 
+				// only the fully allocated order is locked and made pending
 				m.db.ExpectBegin()
+				m.orderRepo.On("WithTX", mock.Anything).Return(m.orderRepo)
+				m.orderRepo.On("WithLockForUpdate").Return(m.orderRepo)
+				m.orderRepo.On("GetOrderByID", mock.Anything, allocatedOrderID.String()).Return(model.Order{
+					ID: allocatedOrderID, ProductID: productID, Quantity: 3, Status: constant.OrderStatusWaiting,
+				}, nil)
 				m.stockLockRepo.On("WithTX", mock.Anything).Return(m.stockLockRepo)
-				m.stockLockRepo.On("GetStockLocksByOrderID", mock.Anything, orderID.String()).Return([]model.StockLock{
-					{
-						ID:          uuid.New(),
-						OrderID:     orderID,
-						ProductID:   productID,
-						Quantity:    2,
-						WarehouseID: warehouseID,
+				m.stockLockRepo.On("CreateStockLock", mock.Anything, &model.StockLock{
+					OrderID:     allocatedOrderID,
+					ProductID:   productID,
+					WarehouseID: warehouseID1,
+					Quantity:    1,
+				}).Return(nil)
+				m.stockLockRepo.On("CreateStockLock", mock.Anything, &model.StockLock{
+					OrderID:     allocatedOrderID,
+					ProductID:   productID,
+					WarehouseID: warehouseID2,
+					Quantity:    2,
+				}).Return(nil)
+				m.orderRepo.On("UpdateOrder", mock.Anything, mock.MatchedBy(func(order *model.Order) bool {
+					return order.ID == allocatedOrderID && order.Status == constant.OrderStatusPending &&
+						order.ExpiresAt.After(time.Now())
+				})).Return(nil)
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - waiting orders are paged through",
+			setup: func(m dependencyMocks) {
+				page := make([]model.Order, backorderedOrdersBatchSize)
+				for i := range page {
+					page[i] = model.Order{ID: uuid.New(), ProductID: productID, Quantity: 1, Status: constant.OrderStatusWaiting}
+				}
+
+				m.orderRepo.On("GetOrders", mock.Anything, payload.GetOrdersReq{
+					StatusIN: []string{constant.OrderStatusWaiting},
+					Limit:    backorderedOrdersBatchSize,
+				}).Return(page, nil).Once()
+				m.orderRepo.On("GetOrders", mock.Anything, payload.GetOrdersReq{
+					StatusIN: []string{constant.OrderStatusWaiting},
+					IDAfter:  page[len(page)-1].ID.String(),
+					Limit:    backorderedOrdersBatchSize,
+				}).Return([]model.Order{
+					{ID: waitingOrderID, ProductID: productID, Quantity: 2, Status: constant.OrderStatusWaiting},
+				}, nil).Once()
+
+				m.warehouseSvc.On("GetBackorders", mock.Anything, mock.MatchedBy(func(req warehouseservice.GetBackordersReq) bool {
+					return len(req.OrderRefIN) == backorderedOrdersBatchSize
+				})).Return(warehouseservice.GetBackordersResp{}, nil).Once()
+				m.warehouseSvc.On("GetBackorders", mock.Anything, warehouseservice.GetBackordersReq{
+					Token:      "static-token",
+					OrderRefIN: []string{waitingOrderID.String()},
+				}).Return(warehouseservice.GetBackordersResp{}, nil).Once()
+			},
+		},
+		{
+			name: "success - order cancelled since it was found waiting",
+			setup: func(m dependencyMocks) {
+				m.orderRepo.On("GetOrders", mock.Anything, mock.Anything).Return([]model.Order{
+					{ID: allocatedOrderID, ProductID: productID, Quantity: 3, Status: constant.OrderStatusWaiting},
+				}, nil)
+				m.warehouseSvc.On("GetBackorders", mock.Anything, mock.Anything).Return(warehouseservice.GetBackordersResp{
+					Data: []warehouseservice.Backorder{
+						{OrderRef: allocatedOrderID.String(), ProductID: productID, Quantity: 3, Status: constant.BackorderStatusAllocated},
 					},
 				}, nil)
 
-				m.warehouseSvc.On("RollbackReserves", mock.Anything, mock.Anything).Return(nil)
+				m.db.ExpectBegin()
+				m.orderRepo.On("WithTX", mock.Anything).Return(m.orderRepo)
+				m.orderRepo.On("WithLockForUpdate").Return(m.orderRepo)
+				m.orderRepo.On("GetOrderByID", mock.Anything, allocatedOrderID.String()).Return(model.Order{
+					ID: allocatedOrderID, Status: constant.OrderStatusCancelled,
+				}, nil)
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "success - no waiting orders",
+			setup: func(m dependencyMocks) {
+				m.orderRepo.On("GetOrders", mock.Anything, mock.Anything).Return([]model.Order{}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:            mockDB.Mock,
+				orderRepo:     orderRepoMock.NewOrderRepository(t),
+				stockLockRepo: stockLockRepoMock.NewStockLockRepository(t),
+				warehouseSvc:  warehouseSvcMock.NewIWarehouseSvc(t),
+			}
+
+			orderSvc := orderService{
+				config: &config.Config{
+					External: config.External{
+						WarehouseServiceStaticToken: "static-token",
+					},
+				},
+				db:            mockDB.Db,
+				orderRepo:     mocks.orderRepo,
+				stockLockRepo: mocks.stockLockRepo,
+				warehouseSvc:  mocks.warehouseSvc,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := orderSvc.ProcessBackorderedOrders(context.Background())
+
+			// Then
+			assert.NoError(t, err)
+			assert.NoError(t, mockDB.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestProcessBackorderedOrders_ShouldReturnError(t *testing.T) {
+	type dependencyMocks struct {
+		db            sqlmock.Sqlmock
+		orderRepo     *orderRepoMock.OrderRepository
+		stockLockRepo *stockLockRepoMock.StockLockRepository
+		warehouseSvc  *warehouseSvcMock.IWarehouseSvc
+	}
+
+	mockDB, err := pkg.SetupMockDB()
+	assert.NoError(t, err)
 
+	orderID := uuid.New()
+	productID := uuid.New()
+	waitingOrders := []model.Order{
+		{ID: orderID, ProductID: productID, Quantity: 1, Status: constant.OrderStatusWaiting},
+	}
+
+	tests := []struct {
+		name  string
+		setup func(m dependencyMocks)
+	}{
+		{
+			name: "error - failed to get orders",
+			setup: func(m dependencyMocks) {
+				m.orderRepo.On("GetOrders", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+			},
+		},
+		{
+			name: "error - warehouse service failure",
+			setup: func(m dependencyMocks) {
+				m.orderRepo.On("GetOrders", mock.Anything, mock.Anything).Return(waitingOrders, nil)
+				m.warehouseSvc.On("GetBackorders", mock.Anything, mock.Anything).
+					Return(warehouseservice.GetBackordersResp{}, assert.AnError)
+			},
+		},
+		{
+			name: "error - failed to update order",
+			setup: func(m dependencyMocks) {
+				m.orderRepo.On("GetOrders", mock.Anything, mock.Anything).Return(waitingOrders, nil)
+				m.warehouseSvc.On("GetBackorders", mock.Anything, mock.Anything).
+					Return(warehouseservice.GetBackordersResp{
+						Data: []warehouseservice.Backorder{
+							{OrderRef: orderID.String(), ProductID: productID, Quantity: 1, Status: constant.BackorderStatusAllocated},
+						},
+					}, nil)
+
+				m.db.ExpectBegin()
 				m.orderRepo.On("WithTX", mock.Anything).Return(m.orderRepo)
+				m.orderRepo.On("WithLockForUpdate").Return(m.orderRepo)
+				m.orderRepo.On("GetOrderByID", mock.Anything, orderID.String()).Return(waitingOrders[0], nil)
 				m.orderRepo.On("UpdateOrder", mock.Anything, mock.Anything).Return(assert.AnError)
+				m.db.ExpectRollback()
 			},
-			wantErr: "",
 		},
 	}
 
@@ -931,13 +1576,116 @@ func TestProcessExpiredOrders_ShouldReturnError(t *testing.T) {
 			tt.setup(mocks)
 
 			// When
-			err := orderSvc.ProcessExpiredOrders(context.Background())
+			err := orderSvc.ProcessBackorderedOrders(context.Background())
 
 			// Then
 			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	type dependencyMocks struct {
+		db            sqlmock.Sqlmock
+		orderRepo     *orderRepoMock.OrderRepository
+		stockLockRepo *stockLockRepoMock.StockLockRepository
+		warehouseSvc  *warehouseSvcMock.IWarehouseSvc
+	}
+
+	mockDB, err := pkg.SetupMockDB()
+	assert.NoError(t, err)
+
+	orderID := uuid.New()
+	productID := uuid.New()
+	req := payload.CancelOrderReq{
+		OrderID: orderID.String(),
+		Token:   "test-token",
+	}
+
+	expectOrder := func(m dependencyMocks, status string) {
+		m.db.ExpectBegin()
+		m.orderRepo.On("WithTX", mock.Anything).Return(m.orderRepo)
+		m.orderRepo.On("WithLockForUpdate").Return(m.orderRepo)
+		m.orderRepo.On("GetOrderByID", mock.Anything, orderID.String()).Return(model.Order{
+			ID:        orderID,
+			ProductID: productID,
+			Quantity:  2,
+			Status:    status,
+		}, nil)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr string
+	}{
+		{
+			name: "success - waiting order gives up its backorders",
+			setup: func(m dependencyMocks) {
+				expectOrder(m, constant.OrderStatusWaiting)
+				m.warehouseSvc.On("CancelBackorders", mock.Anything, warehouseservice.CancelBackordersReq{
+					Token:    "test-token",
+					OrderRef: orderID.String(),
+				}).Return(nil)
+				m.stockLockRepo.On("WithTX", mock.Anything).Return(m.stockLockRepo)
+				m.stockLockRepo.On("GetStockLocksByOrderID", mock.Anything, orderID.String()).Return([]model.StockLock{}, nil)
+				m.orderRepo.On("UpdateOrder", mock.Anything, mock.MatchedBy(func(order *model.Order) bool {
+					return order.ID == orderID && order.Status == constant.OrderStatusCancelled
+				})).Return(nil)
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - order already completed",
+			setup: func(m dependencyMocks) {
+				expectOrder(m, constant.OrderStatusCompleted)
+				m.db.ExpectRollback()
+			},
+			wantErr: "order is not in waiting or pending status",
+		},
+		{
+			name: "error - warehouse service cancel backorders failure",
+			setup: func(m dependencyMocks) {
+				expectOrder(m, constant.OrderStatusWaiting)
+				m.warehouseSvc.On("CancelBackorders", mock.Anything, mock.Anything).Return(assert.AnError)
+				m.db.ExpectRollback()
+			},
+			wantErr: assert.AnError.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:            mockDB.Mock,
+				orderRepo:     orderRepoMock.NewOrderRepository(t),
+				stockLockRepo: stockLockRepoMock.NewStockLockRepository(t),
+				warehouseSvc:  warehouseSvcMock.NewIWarehouseSvc(t),
+			}
+
+			orderSvc := orderService{
+				config:        &config.Config{},
+				db:            mockDB.Db,
+				orderRepo:     mocks.orderRepo,
+				stockLockRepo: mocks.stockLockRepo,
+				warehouseSvc:  mocks.warehouseSvc,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			order, err := orderSvc.CancelOrder(context.Background(), req)
+
+			// Then
 			if tt.wantErr != "" {
+				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, constant.OrderStatusCancelled, order.Status)
 			}
+			assert.NoError(t, mockDB.Mock.ExpectationsWereMet())
 		})
 	}
 }
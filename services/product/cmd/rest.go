@@ -11,6 +11,7 @@ import (
 	ginprometheus "github.com/zsais/go-gin-prometheus"
 
 	docs "github.com/alifmufthi91/ecommerce-system/services/product/docs"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/constant"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/registry"
 	"github.com/gin-contrib/cors"
@@ -75,6 +76,15 @@ func RegisterServiceRouter(config *config.Config, router *gin.Engine, db *sql.DB
 	docs.SwaggerInfo.BasePath = "/api"
 	serviceRouter.GET("/docs/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
+	// files of the local storage are served publicly, like objects of a bucket
+	if config.Storage.Driver == "" || config.Storage.Driver == constant.StorageDriverLocal {
+		dir := config.Storage.LocalDir
+		if dir == "" {
+			dir = constant.DefaultStorageLocalDir
+		}
+		serviceRouter.Static(constant.MediaPath, dir)
+	}
+
 	for _, router := range registry.GetRouters() {
 		router.RegisterRoutes(serviceRouter)
 	}
@@ -56,7 +56,7 @@ func startServer(config *config.Config) {
 	)
 
 	// returned value can be used later when adding external connection like pubsub
-	_ = internal.InitModules(internal.InitOptions{
+	m := internal.InitModules(internal.InitOptions{
 		DefaultOptions: defaultOpt,
 	})
 
@@ -71,6 +71,8 @@ func startServer(config *config.Config) {
 		Handler: router,
 	}
 
+	go startScheduler(logger, m)
+
 	go func() {
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.Fatal("Failed to start server:", err)
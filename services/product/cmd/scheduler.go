@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/go-co-op/gocron/v2"
+)
+
+func startScheduler(logger *pkg.Logger, modules *internal.Modules) {
+	s, err := gocron.NewScheduler()
+	if err != nil {
+		logger.Fatal("Failed to create scheduler:", err)
+	}
+
+	_, err = s.NewJob(gocron.CronJob("* * * * *", false), gocron.NewTask(func() {
+		ctx, cancelCtx := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancelCtx()
+
+		logger.Info("Applying scheduled prices...")
+		if err := modules.Product.ProductService.ApplyScheduledPrices(ctx); err != nil {
+			logger.Error("Failed to apply scheduled prices:", err)
+		}
+	}))
+	if err != nil {
+		logger.Fatal("Failed to create job:", err)
+	}
+
+	logger.Info("Scheduler started, applying scheduled prices every minute")
+	s.Start()
+}
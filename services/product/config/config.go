@@ -10,6 +10,7 @@ type Config struct {
 	Token    Token
 	App      App
 	External External
+	Storage  Storage
 }
 
 type App struct {
@@ -31,6 +32,23 @@ type External struct {
 	WarehouseServiceURL string
 }
 
+// Storage selects where uploaded media is kept. PublicURL is the base URL the
+// stored files are served from.
+type Storage struct {
+	Driver    string
+	LocalDir  string
+	PublicURL string
+	S3        S3
+}
+
+type S3 struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
 func LoadConfig() (*Config, error) {
 
 	viper.SetConfigType("env")
@@ -57,6 +75,18 @@ func LoadConfig() (*Config, error) {
 		External: External{
 			WarehouseServiceURL: viper.GetString("WAREHOUSE_SERVICE_URL"),
 		},
+		Storage: Storage{
+			Driver:    viper.GetString("STORAGE_DRIVER"),
+			LocalDir:  viper.GetString("STORAGE_LOCAL_DIR"),
+			PublicURL: viper.GetString("STORAGE_PUBLIC_URL"),
+			S3: S3{
+				Endpoint:  viper.GetString("STORAGE_S3_ENDPOINT"),
+				Region:    viper.GetString("STORAGE_S3_REGION"),
+				Bucket:    viper.GetString("STORAGE_S3_BUCKET"),
+				AccessKey: viper.GetString("STORAGE_S3_ACCESS_KEY"),
+				SecretKey: viper.GetString("STORAGE_S3_SECRET_KEY"),
+			},
+		},
 	}
 
 	return c, nil
@@ -42,6 +42,34 @@ func (_m *IWarehouseSvc) GetStockAvailables(ctx context.Context, req warehousese
 	return r0, r1
 }
 
+// GetStocks provides a mock function with given fields: ctx, req
+func (_m *IWarehouseSvc) GetStocks(ctx context.Context, req warehouseservice.GetStocksReq) (warehouseservice.GetStocksResp, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStocks")
+	}
+
+	var r0 warehouseservice.GetStocksResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, warehouseservice.GetStocksReq) (warehouseservice.GetStocksResp, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, warehouseservice.GetStocksReq) warehouseservice.GetStocksResp); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(warehouseservice.GetStocksResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, warehouseservice.GetStocksReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewIWarehouseSvc creates a new instance of IWarehouseSvc. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIWarehouseSvc(t interface {
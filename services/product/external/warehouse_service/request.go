@@ -18,3 +18,20 @@ func (r *GetStockAvailablesReq) ToQueryParams() url.Values {
 
 	return queryParams
 }
+
+type GetStocksReq struct {
+	ProductIDIN []string
+	Token       string
+}
+
+func (r *GetStocksReq) ToQueryParams() url.Values {
+	queryParams := make(url.Values)
+
+	if len(r.ProductIDIN) > 0 {
+		for _, id := range r.ProductIDIN {
+			queryParams.Add("product_id_in", id)
+		}
+	}
+
+	return queryParams
+}
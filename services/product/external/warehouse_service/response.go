@@ -10,6 +10,18 @@ type GetStockAvailablesResp struct {
 	Success string                   `json:"success"`
 }
 
+type GetStocksData struct {
+	WarehouseID string `json:"warehouse_id"`
+	ProductID   string `json:"product_id"`
+	Quantity    int    `json:"quantity"`
+	Reserved    int    `json:"reserved"`
+}
+
+type GetStocksResp struct {
+	Data    []GetStocksData `json:"data"`
+	Success string          `json:"success"`
+}
+
 type ErrorResponse struct {
 	Metadata ErrorMetadata `json:"metadata"`
 }
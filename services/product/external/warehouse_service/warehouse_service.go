@@ -16,6 +16,7 @@ import (
 //go:generate mockery --name=IWarehouseSvc --case underscore
 type IWarehouseSvc interface {
 	GetStockAvailables(ctx context.Context, req GetStockAvailablesReq) (res GetStockAvailablesResp, err error)
+	GetStocks(ctx context.Context, req GetStocksReq) (res GetStocksResp, err error)
 }
 
 type WarehouseSvc struct {
@@ -74,6 +75,44 @@ func (w *WarehouseSvc) GetStockAvailables(ctx context.Context, req GetStockAvail
 	return res, nil
 }
 
+func (w *WarehouseSvc) GetStocks(ctx context.Context, req GetStocksReq) (res GetStocksResp, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehousesvc.GetStocks")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	resp, err := w.httpClient.Get(ctx, &httpclient.PropRequest{
+		URI:              w.URL + "/stocks",
+		MultiQueryParams: req.ToQueryParams(),
+		Headers: map[string]string{
+			"Authorization": "Bearer " + req.Token,
+		},
+	})
+
+	if err != nil {
+		return res, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, WarehouseServicePrefix+`internal server error`)
+	}
+
+	defer resp.Body.Close()
+
+	rawData, err := io.ReadAll(resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		if err := handleErrorResponse(rawData, resp.StatusCode); err != nil {
+			return res, err
+		}
+	}
+
+	if err = json.Unmarshal(rawData, &res); err != nil {
+		return res, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, WarehouseServicePrefix+"failed to unmarshal response")
+	}
+
+	return res, nil
+}
+
 func handleErrorResponse(rawData []byte, statusCode int) error {
 	if len(rawData) == 0 {
 		return apperr.NewWithCode(apperr.MapStatusCodeToErrorCode(statusCode), WarehouseServicePrefix+http.StatusText(statusCode))
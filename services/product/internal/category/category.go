@@ -0,0 +1,30 @@
+package category
+
+import (
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/_options"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/category/handler"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/category/repository"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/category/service"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/registry"
+)
+
+type CategoryModule struct {
+	CategoryService service.CategoryService
+}
+
+type Options struct {
+	_options.DefaultOptions
+}
+
+func NewCategoryModule(opts Options) *CategoryModule {
+
+	categoryRepo := repository.NewCategoryRepository(opts.Db)
+
+	categoryService := service.NewCategoryService(opts.Db, categoryRepo)
+
+	registry.RegisterRouter(handler.NewHandler(opts.Router, opts.Config, opts.Logger, categoryService))
+
+	return &CategoryModule{
+		CategoryService: categoryService,
+	}
+}
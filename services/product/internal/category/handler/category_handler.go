@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/category/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/httpresp"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// @Summary		Category - Create Category
+// @Description	create a category, under a parent category when it is set
+// @Tags		Category
+// @Accept		json
+// @Produce		json
+// @param		request	body	payload.CreateCategoryReq	true	"create category request body"
+// @Success		200	{object}	httpresp.Response{data=model.Category}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/categories [post]
+func (h *categoryHandler) CreateCategory(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "categoryHandler.CreateCategory")
+	defer span.End()
+
+	var req payload.CreateCategoryReq
+	if err := c.BindJSON(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, category, nil)
+}
+
+// @Summary		Category - Get Category Tree
+// @Description	get the root categories with their subcategories nested, for navigation menus
+// @Tags		Category
+// @Accept		json
+// @Produce		json
+// @Success		200	{object}	httpresp.Response{data=[]model.Category}
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/categories/tree [get]
+func (h *categoryHandler) GetCategoryTree(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "categoryHandler.GetCategoryTree")
+	defer span.End()
+
+	tree, err := h.categoryService.GetCategoryTree(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, tree, nil)
+}
+
+// @Summary		Category - Update Category
+// @Description	rename a category or move it under another parent
+// @Tags		Category
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"category ID"
+// @param		request	body	payload.UpdateCategoryReq	true	"update category request body"
+// @Success		200	{object}	httpresp.Response{data=model.Category}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/categories/{id} [put]
+func (h *categoryHandler) UpdateCategory(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "categoryHandler.UpdateCategory")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid category ID"))
+		return
+	}
+
+	var req payload.UpdateCategoryReq
+	if err := c.BindJSON(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ID = parsedID
+	category, err := h.categoryService.UpdateCategory(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, category, nil)
+}
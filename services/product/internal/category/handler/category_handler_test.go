@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/config"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/category/service/mocks"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCategoryHandler_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		method             string
+		path               string
+		requestBody        string
+		serviceMethod      string
+		mockResult         []any
+		statusCodeExpected int
+	}{
+		{
+			testName:           "create - success",
+			method:             http.MethodPost,
+			path:               "/categories",
+			requestBody:        `{"name": "Shoes"}`,
+			serviceMethod:      "CreateCategory",
+			mockResult:         []any{model.Category{}, nil},
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "create - failed invalid request body",
+			method:             http.MethodPost,
+			path:               "/categories",
+			requestBody:        `{"parent_id": "` + uuid.New().String() + `"}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "get tree - success",
+			method:             http.MethodGet,
+			path:               "/categories/tree",
+			serviceMethod:      "GetCategoryTree",
+			mockResult:         []any{[]model.Category{}, nil},
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "get tree - failed error handle get category tree",
+			method:             http.MethodGet,
+			path:               "/categories/tree",
+			serviceMethod:      "GetCategoryTree",
+			mockResult:         []any{nil, errors.New("something went wrong")},
+			statusCodeExpected: http.StatusInternalServerError,
+		},
+		{
+			testName:           "update - success",
+			method:             http.MethodPut,
+			path:               "/categories/" + uuid.New().String(),
+			requestBody:        `{"name": "Footwear"}`,
+			serviceMethod:      "UpdateCategory",
+			mockResult:         []any{model.Category{}, nil},
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "update - failed invalid param",
+			method:             http.MethodPut,
+			path:               "/categories/invalid-uuid",
+			requestBody:        `{"name": "Footwear"}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockCategorySvc := mocks.NewCategoryService(t)
+			if scenario.serviceMethod == "GetCategoryTree" {
+				mockCategorySvc.On(scenario.serviceMethod, mock.Anything).Return(scenario.mockResult...)
+			} else if scenario.serviceMethod != "" {
+				mockCategorySvc.On(scenario.serviceMethod, mock.Anything, mock.Anything).Return(scenario.mockResult...)
+			}
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(scenario.method, scenario.path, strings.NewReader(scenario.requestBody))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &categoryHandler{
+				router:          r,
+				config:          mockConfig,
+				categoryService: mockCategorySvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
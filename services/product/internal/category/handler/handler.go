@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"github.com/alifmufthi91/ecommerce-system/services/product/config"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/category/service"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/middleware"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/registry"
+	"github.com/gin-gonic/gin"
+)
+
+type categoryHandler struct {
+	router          *gin.Engine
+	config          *config.Config
+	logger          *pkg.Logger
+	categoryService service.CategoryService
+}
+
+func NewHandler(rt *gin.Engine, cfg *config.Config, logger *pkg.Logger, categorySvc service.CategoryService) registry.Router {
+	return &categoryHandler{
+		categoryService: categorySvc,
+		router:          rt,
+		config:          cfg,
+		logger:          logger,
+	}
+}
+
+func (h categoryHandler) RegisterRoutes(base *gin.RouterGroup) {
+	g := base.Group("/categories")
+
+	g.Use(middleware.JwtMiddleware(h.config))
+
+	g.POST("", h.CreateCategory)
+	g.GET("/tree", h.GetCategoryTree)
+	g.PUT("/:id", h.UpdateCategory)
+}
@@ -0,0 +1,9 @@
+package payload
+
+import "github.com/google/uuid"
+
+// CreateCategoryReq creates a category, under ParentID when it is set.
+type CreateCategoryReq struct {
+	Name     string     `json:"name" binding:"required,max=100"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
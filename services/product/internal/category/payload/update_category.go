@@ -0,0 +1,11 @@
+package payload
+
+import "github.com/google/uuid"
+
+// UpdateCategoryReq renames a category and moves it under ParentID, or to the
+// root when ParentID is nil.
+type UpdateCategoryReq struct {
+	ID       uuid.UUID  `json:"-"`
+	Name     string     `json:"name" binding:"required,max=100"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name=CategoryRepository --case underscore
+type CategoryRepository interface {
+	WithTX(tx *gorm.DB) CategoryRepository
+	WithLockForUpdate() CategoryRepository
+	CreateCategory(ctx context.Context, category *model.Category) error
+	GetCategories(ctx context.Context) ([]model.Category, error)
+	GetCategoryByID(ctx context.Context, categoryID string) (model.Category, error)
+	UpdateCategory(ctx context.Context, category *model.Category) error
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) WithTX(tx *gorm.DB) CategoryRepository {
+	if tx == nil {
+		return r
+	}
+	return &categoryRepository{db: tx}
+}
+
+func (r *categoryRepository) WithLockForUpdate() CategoryRepository {
+	return &categoryRepository{
+		db: r.db.Clauses(clause.Locking{Strength: "UPDATE"}),
+	}
+}
+
+func (r *categoryRepository) CreateCategory(ctx context.Context, category *model.Category) error {
+	ctx, span := observ.GetTracer().Start(ctx, "categoryRepository.CreateCategory")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(category).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if isUniqueViolation(err) {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "category with this name already exists under the parent")
+		}
+		return apperr.WrapWithCode(err, apperr.CodeSQLCreate, "failed to create category")
+	}
+	return nil
+}
+
+// GetCategories returns every category ordered by name, the tree is built
+// from them in memory.
+func (r *categoryRepository) GetCategories(ctx context.Context) ([]model.Category, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "categoryRepository.GetCategories")
+	defer span.End()
+
+	var categories []model.Category
+	if err := r.db.WithContext(ctx).Order("name").Find(&categories).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get categories")
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) GetCategoryByID(ctx context.Context, categoryID string) (model.Category, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "categoryRepository.GetCategoryByID")
+	defer span.End()
+
+	var category model.Category
+	if err := r.db.WithContext(ctx).Where("id = ?", categoryID).First(&category).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if err == gorm.ErrRecordNotFound {
+			return model.Category{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "category not found")
+		}
+		return model.Category{}, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get category by ID")
+	}
+	return category, nil
+}
+
+func (r *categoryRepository) UpdateCategory(ctx context.Context, category *model.Category) error {
+	ctx, span := observ.GetTracer().Start(ctx, "categoryRepository.UpdateCategory")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Model(category).
+		Select("name", "parent_id", "updated_at").
+		Updates(category).Error
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if isUniqueViolation(err) {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "category with this name already exists under the parent")
+		}
+		return apperr.WrapWithCode(err, apperr.CodeSQLUpdate, "failed to update category")
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether the parent already has a category with the
+// name, see idx_categories_parent_id_name.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
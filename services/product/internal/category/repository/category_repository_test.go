@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCategory(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	parentID := uuid.New()
+
+	tests := []struct {
+		name     string
+		setup    func(mockDB sqlmock.Sqlmock)
+		wantCode apperr.Code
+	}{
+		{
+			name: "success",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`INSERT INTO "categories" ("parent_id","name","created_at","updated_at") VALUES ($1,$2,$3,$4) RETURNING "id"`),
+				).WithArgs(&parentID, "Shoes", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+			},
+		},
+		{
+			name: "error - name taken under the parent",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`INSERT INTO "categories"`),
+				).WillReturnError(&pgconn.PgError{Code: "23505"})
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - failed to create category",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`INSERT INTO "categories"`),
+				).WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantCode: apperr.CodeSQLCreate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(mockDb.Mock)
+
+			repo := NewCategoryRepository(mockDb.Db)
+
+			err := repo.CreateCategory(context.Background(), &model.Category{ParentID: &parentID, Name: "Shoes"})
+
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestGetCategories(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(mockDB sqlmock.Sqlmock)
+		wantLen int
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "categories" ORDER BY name`),
+				).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "parent_id", "name", "created_at", "updated_at"}).
+						AddRow(uuid.New(), nil, "Apparel", time.Now(), time.Now()).
+						AddRow(uuid.New(), uuid.New(), "Shoes", time.Now(), time.Now()),
+				)
+			},
+			wantLen: 2,
+		},
+		{
+			name: "error - failed to get categories",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "categories" ORDER BY name`),
+				).WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(mockDb.Mock)
+
+			repo := NewCategoryRepository(mockDb.Db)
+
+			result, err := repo.GetCategories(context.Background())
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, result, tt.wantLen)
+		})
+	}
+}
+
+func TestGetCategoryByID(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	categoryID := uuid.New()
+
+	tests := []struct {
+		name    string
+		setup   func(mockDB sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "categories" WHERE id = $1 ORDER BY "categories"."id" LIMIT $2`),
+				).WithArgs(categoryID.String(), 1).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "name"}).AddRow(categoryID, "Apparel"),
+				)
+			},
+		},
+		{
+			name: "error - category not found",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "categories" WHERE id = $1`),
+				).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(mockDb.Mock)
+
+			repo := NewCategoryRepository(mockDb.Db)
+
+			result, err := repo.GetCategoryByID(context.Background(), categoryID.String())
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, categoryID, result.ID)
+		})
+	}
+}
+
+func TestUpdateCategory(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	category := model.Category{ID: uuid.New(), Name: "Footwear"}
+
+	mockDb.Mock.ExpectExec(
+		regexp.QuoteMeta(`UPDATE "categories" SET "parent_id"=$1,"name"=$2,"updated_at"=$3 WHERE "id" = $4`),
+	).WithArgs(nil, category.Name, sqlmock.AnyArg(), category.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewCategoryRepository(mockDb.Db)
+
+	err = repo.UpdateCategory(context.Background(), &category)
+
+	assert.Nil(t, err)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/category/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/category/repository"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=CategoryService --case underscore
+type CategoryService interface {
+	CreateCategory(ctx context.Context, req payload.CreateCategoryReq) (model.Category, error)
+	UpdateCategory(ctx context.Context, req payload.UpdateCategoryReq) (model.Category, error)
+	GetCategoryTree(ctx context.Context) ([]model.Category, error)
+	GetCategoryByID(ctx context.Context, categoryID string) (model.Category, error)
+	GetSubtreeIDs(ctx context.Context, categoryID string) ([]string, error)
+}
+
+type categoryService struct {
+	db           *gorm.DB
+	categoryRepo repository.CategoryRepository
+}
+
+func NewCategoryService(db *gorm.DB, categoryRepo repository.CategoryRepository) CategoryService {
+	return &categoryService{
+		db:           db,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (s *categoryService) CreateCategory(ctx context.Context, req payload.CreateCategoryReq) (res model.Category, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "categoryService.CreateCategory")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if req.ParentID != nil {
+		if _, err := s.getParent(ctx, *req.ParentID); err != nil {
+			return model.Category{}, err
+		}
+	}
+
+	category := model.Category{
+		ParentID: req.ParentID,
+		Name:     req.Name,
+	}
+	if err := s.categoryRepo.CreateCategory(ctx, &category); err != nil {
+		return model.Category{}, err
+	}
+
+	return category, nil
+}
+
+// UpdateCategory renames and moves a category. It can't be moved under itself
+// or one of its descendants. A move locks every category, so two moves can't
+// each pass the check and close a cycle between them.
+func (s *categoryService) UpdateCategory(ctx context.Context, req payload.UpdateCategoryReq) (res model.Category, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "categoryService.UpdateCategory")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	category, err := s.categoryRepo.WithTX(tx).GetCategoryByID(ctx, req.ID.String())
+	if err != nil {
+		return model.Category{}, err
+	}
+
+	if req.ParentID != nil {
+		categories, err := s.categoryRepo.WithTX(tx).WithLockForUpdate().GetCategories(ctx)
+		if err != nil {
+			return model.Category{}, err
+		}
+
+		var parentFound bool
+		for _, c := range categories {
+			if c.ID == *req.ParentID {
+				parentFound = true
+				break
+			}
+		}
+		if !parentFound {
+			return model.Category{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "parent category not found")
+		}
+
+		for _, id := range subtreeIDs(categories, category.ID) {
+			if id == req.ParentID.String() {
+				return model.Category{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "category can't be moved under itself")
+			}
+		}
+	}
+
+	category.Name = req.Name
+	category.ParentID = req.ParentID
+	if err := s.categoryRepo.WithTX(tx).UpdateCategory(ctx, &category); err != nil {
+		return model.Category{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return model.Category{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return category, nil
+}
+
+// GetCategoryTree returns the root categories with their descendants nested
+// as children.
+func (s *categoryService) GetCategoryTree(ctx context.Context) (result []model.Category, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "categoryService.GetCategoryTree")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	categories, err := s.categoryRepo.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTree(categories, nil), nil
+}
+
+func (s *categoryService) GetCategoryByID(ctx context.Context, categoryID string) (model.Category, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "categoryService.GetCategoryByID")
+	defer span.End()
+
+	category, err := s.categoryRepo.GetCategoryByID(ctx, categoryID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+		return model.Category{}, err
+	}
+
+	return category, nil
+}
+
+// GetSubtreeIDs returns the IDs of the category and all its descendants.
+func (s *categoryService) GetSubtreeIDs(ctx context.Context, categoryID string) (result []string, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "categoryService.GetSubtreeIDs")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	id, err := uuid.Parse(categoryID)
+	if err != nil {
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid category ID")
+	}
+
+	categories, err := s.categoryRepo.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result = subtreeIDs(categories, id)
+	if len(result) == 0 {
+		return nil, apperr.NewWithCode(apperr.CodeHTTPNotFound, "category not found")
+	}
+
+	return result, nil
+}
+
+func (s *categoryService) getParent(ctx context.Context, parentID uuid.UUID) (model.Category, error) {
+	parent, err := s.categoryRepo.GetCategoryByID(ctx, parentID.String())
+	if apperr.ErrCode(err) == apperr.CodeHTTPNotFound {
+		return model.Category{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "parent category not found")
+	}
+	return parent, err
+}
+
+// buildTree nests the categories under the parent, keeping their order.
+func buildTree(categories []model.Category, parentID *uuid.UUID) []model.Category {
+	var nodes []model.Category
+	for _, category := range categories {
+		if !sameParent(category.ParentID, parentID) {
+			continue
+		}
+		id := category.ID
+		category.Children = buildTree(categories, &id)
+		nodes = append(nodes, category)
+	}
+	return nodes
+}
+
+// subtreeIDs returns the ID of the root and its descendants, nothing when the
+// root is not one of the categories. Each category is visited once, so a
+// cycle in the parents can't loop.
+func subtreeIDs(categories []model.Category, rootID uuid.UUID) []string {
+	children := make(map[uuid.UUID][]uuid.UUID)
+	var rootFound bool
+	for _, category := range categories {
+		if category.ID == rootID {
+			rootFound = true
+		}
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category.ID)
+		}
+	}
+	if !rootFound {
+		return nil
+	}
+
+	ids := []string{rootID.String()}
+	visited := map[uuid.UUID]bool{rootID: true}
+	queue := []uuid.UUID{rootID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range children[id] {
+			if visited[child] {
+				continue
+			}
+			visited[child] = true
+			ids = append(ids, child.String())
+			queue = append(queue, child)
+		}
+	}
+	return ids
+}
+
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/category/payload"
+	categoryRepoMock "github.com/alifmufthi91/ecommerce-system/services/product/internal/category/repository/mocks"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// apparel > shoes > sneakers, and books as another root
+var (
+	apparelID  = uuid.New()
+	shoesID    = uuid.New()
+	sneakersID = uuid.New()
+	booksID    = uuid.New()
+	categories = []model.Category{
+		{ID: apparelID, Name: "Apparel"},
+		{ID: booksID, Name: "Books"},
+		{ID: sneakersID, ParentID: &shoesID, Name: "Sneakers"},
+		{ID: shoesID, ParentID: &apparelID, Name: "Shoes"},
+	}
+)
+
+func TestCreateCategory(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     payload.CreateCategoryReq
+		setup   func(categoryRepo *categoryRepoMock.CategoryRepository)
+		wantErr bool
+	}{
+		{
+			name: "success - root",
+			req:  payload.CreateCategoryReq{Name: "Toys"},
+			setup: func(categoryRepo *categoryRepoMock.CategoryRepository) {
+				categoryRepo.On("CreateCategory", mock.Anything, &model.Category{Name: "Toys"}).
+					Return(nil)
+			},
+		},
+		{
+			name: "success - under parent",
+			req:  payload.CreateCategoryReq{Name: "Boots", ParentID: &shoesID},
+			setup: func(categoryRepo *categoryRepoMock.CategoryRepository) {
+				categoryRepo.On("GetCategoryByID", mock.Anything, shoesID.String()).
+					Return(model.Category{ID: shoesID}, nil)
+				categoryRepo.On("CreateCategory", mock.Anything, &model.Category{Name: "Boots", ParentID: &shoesID}).
+					Return(nil)
+			},
+		},
+		{
+			name: "error - parent not found",
+			req:  payload.CreateCategoryReq{Name: "Boots", ParentID: &shoesID},
+			setup: func(categoryRepo *categoryRepoMock.CategoryRepository) {
+				categoryRepo.On("GetCategoryByID", mock.Anything, shoesID.String()).
+					Return(model.Category{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "category not found"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			categoryRepo := categoryRepoMock.NewCategoryRepository(t)
+			categorySvc := categoryService{categoryRepo: categoryRepo}
+
+			tt.setup(categoryRepo)
+
+			// When
+			_, err := categorySvc.CreateCategory(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Equal(t, apperr.CodeHTTPBadRequest, apperr.ErrCode(err))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUpdateCategory(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		req     payload.UpdateCategoryReq
+		setup   func(db sqlmock.Sqlmock, categoryRepo *categoryRepoMock.CategoryRepository)
+		wantErr string
+	}{
+		{
+			name: "success - move under another root",
+			req:  payload.UpdateCategoryReq{ID: shoesID, Name: "Shoes", ParentID: &booksID},
+			setup: func(db sqlmock.Sqlmock, categoryRepo *categoryRepoMock.CategoryRepository) {
+				db.ExpectBegin()
+				categoryRepo.On("WithTX", mock.Anything).Return(categoryRepo)
+				categoryRepo.On("GetCategoryByID", mock.Anything, shoesID.String()).
+					Return(categories[3], nil)
+				categoryRepo.On("WithLockForUpdate").Return(categoryRepo)
+				categoryRepo.On("GetCategories", mock.Anything).
+					Return(categories, nil)
+				categoryRepo.On("UpdateCategory", mock.Anything, mock.MatchedBy(func(category *model.Category) bool {
+					return *category.ParentID == booksID
+				})).
+					Return(nil)
+				db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - move to the root",
+			req:  payload.UpdateCategoryReq{ID: shoesID, Name: "Footwear"},
+			setup: func(db sqlmock.Sqlmock, categoryRepo *categoryRepoMock.CategoryRepository) {
+				db.ExpectBegin()
+				categoryRepo.On("WithTX", mock.Anything).Return(categoryRepo)
+				categoryRepo.On("GetCategoryByID", mock.Anything, shoesID.String()).
+					Return(categories[3], nil)
+				categoryRepo.On("UpdateCategory", mock.Anything, mock.MatchedBy(func(category *model.Category) bool {
+					return category.ParentID == nil && category.Name == "Footwear"
+				})).
+					Return(nil)
+				db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - move under a descendant",
+			req:  payload.UpdateCategoryReq{ID: apparelID, Name: "Apparel", ParentID: &sneakersID},
+			setup: func(db sqlmock.Sqlmock, categoryRepo *categoryRepoMock.CategoryRepository) {
+				db.ExpectBegin()
+				categoryRepo.On("WithTX", mock.Anything).Return(categoryRepo)
+				categoryRepo.On("GetCategoryByID", mock.Anything, apparelID.String()).
+					Return(categories[0], nil)
+				categoryRepo.On("WithLockForUpdate").Return(categoryRepo)
+				categoryRepo.On("GetCategories", mock.Anything).
+					Return(categories, nil)
+				db.ExpectRollback()
+			},
+			wantErr: "category can't be moved under itself",
+		},
+		{
+			name: "error - move under a descendant of a cycle",
+			req:  payload.UpdateCategoryReq{ID: apparelID, Name: "Apparel", ParentID: &booksID},
+			setup: func(db sqlmock.Sqlmock, categoryRepo *categoryRepoMock.CategoryRepository) {
+				cycle := []model.Category{
+					{ID: apparelID, ParentID: &shoesID, Name: "Apparel"},
+					{ID: booksID, ParentID: &shoesID, Name: "Books"},
+					{ID: shoesID, ParentID: &apparelID, Name: "Shoes"},
+				}
+				db.ExpectBegin()
+				categoryRepo.On("WithTX", mock.Anything).Return(categoryRepo)
+				categoryRepo.On("GetCategoryByID", mock.Anything, apparelID.String()).
+					Return(cycle[0], nil)
+				categoryRepo.On("WithLockForUpdate").Return(categoryRepo)
+				categoryRepo.On("GetCategories", mock.Anything).
+					Return(cycle, nil)
+				db.ExpectRollback()
+			},
+			wantErr: "category can't be moved under itself",
+		},
+		{
+			name: "error - parent not found",
+			req:  payload.UpdateCategoryReq{ID: shoesID, Name: "Shoes", ParentID: func() *uuid.UUID { id := uuid.New(); return &id }()},
+			setup: func(db sqlmock.Sqlmock, categoryRepo *categoryRepoMock.CategoryRepository) {
+				db.ExpectBegin()
+				categoryRepo.On("WithTX", mock.Anything).Return(categoryRepo)
+				categoryRepo.On("GetCategoryByID", mock.Anything, shoesID.String()).
+					Return(categories[3], nil)
+				categoryRepo.On("WithLockForUpdate").Return(categoryRepo)
+				categoryRepo.On("GetCategories", mock.Anything).
+					Return(categories, nil)
+				db.ExpectRollback()
+			},
+			wantErr: "parent category not found",
+		},
+		{
+			name: "error - name taken under the parent",
+			req:  payload.UpdateCategoryReq{ID: sneakersID, Name: "Shoes", ParentID: &apparelID},
+			setup: func(db sqlmock.Sqlmock, categoryRepo *categoryRepoMock.CategoryRepository) {
+				db.ExpectBegin()
+				categoryRepo.On("WithTX", mock.Anything).Return(categoryRepo)
+				categoryRepo.On("GetCategoryByID", mock.Anything, sneakersID.String()).
+					Return(categories[2], nil)
+				categoryRepo.On("WithLockForUpdate").Return(categoryRepo)
+				categoryRepo.On("GetCategories", mock.Anything).
+					Return(categories, nil)
+				categoryRepo.On("UpdateCategory", mock.Anything, mock.Anything).
+					Return(apperr.NewWithCode(apperr.CodeHTTPBadRequest, "category with this name already exists under the parent"))
+				db.ExpectRollback()
+			},
+			wantErr: "category with this name already exists under the parent",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			categoryRepo := categoryRepoMock.NewCategoryRepository(t)
+			categorySvc := categoryService{db: mockDb.Db, categoryRepo: categoryRepo}
+
+			tt.setup(mockDb.Mock, categoryRepo)
+
+			// When
+			_, err := categorySvc.UpdateCategory(context.Background(), tt.req)
+
+			// Then
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGetCategoryTree(t *testing.T) {
+	categoryRepo := categoryRepoMock.NewCategoryRepository(t)
+	categoryRepo.On("GetCategories", mock.Anything).
+		Return(categories, nil)
+
+	categorySvc := categoryService{categoryRepo: categoryRepo}
+
+	tree, err := categorySvc.GetCategoryTree(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, apparelID, tree[0].ID)
+	assert.Equal(t, booksID, tree[1].ID)
+	assert.Empty(t, tree[1].Children)
+	assert.Len(t, tree[0].Children, 1)
+	assert.Equal(t, shoesID, tree[0].Children[0].ID)
+	assert.Equal(t, sneakersID, tree[0].Children[0].Children[0].ID)
+}
+
+func TestGetSubtreeIDs(t *testing.T) {
+	tests := []struct {
+		name       string
+		categoryID string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name:       "success - with descendants",
+			categoryID: apparelID.String(),
+			want:       []string{apparelID.String(), shoesID.String(), sneakersID.String()},
+		},
+		{
+			name:       "success - leaf",
+			categoryID: sneakersID.String(),
+			want:       []string{sneakersID.String()},
+		},
+		{
+			name:       "error - category not found",
+			categoryID: uuid.New().String(),
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			categoryRepo := categoryRepoMock.NewCategoryRepository(t)
+			categoryRepo.On("GetCategories", mock.Anything).
+				Return(categories, nil)
+			categorySvc := categoryService{categoryRepo: categoryRepo}
+
+			// When
+			result, err := categorySvc.GetSubtreeIDs(context.Background(), tt.categoryID)
+
+			// Then
+			if tt.wantErr {
+				assert.Equal(t, apperr.CodeHTTPNotFound, apperr.ErrCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
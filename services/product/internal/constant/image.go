@@ -0,0 +1,16 @@
+package constant
+
+const (
+	ImageContentTypeJPEG = "image/jpeg"
+	ImageContentTypePNG  = "image/png"
+)
+
+const (
+	// ImageMaxSize is the largest product image accepted, in bytes.
+	ImageMaxSize = 5 << 20
+	// ImageMaxPixels bounds the decoded size of an image, a small file can
+	// still claim huge dimensions.
+	ImageMaxPixels = 40_000_000
+	// ImageThumbnailSize is the longest side of a thumbnail, in pixels.
+	ImageThumbnailSize = 320
+)
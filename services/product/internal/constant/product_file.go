@@ -0,0 +1,20 @@
+package constant
+
+// formats of the product files imported and exported
+const (
+	ProductFileFormatCSV   = "csv"
+	ProductFileFormatJSONL = "jsonl"
+)
+
+const (
+	ProductFileContentTypeCSV   = "text/csv"
+	ProductFileContentTypeJSONL = "application/x-ndjson"
+)
+
+const (
+	// ProductFileTagSeparator separates the tags of a product in a CSV column.
+	ProductFileTagSeparator = "|"
+	// ProductExportBatchSize is how many products are read at a time while
+	// exporting.
+	ProductExportBatchSize = 500
+)
@@ -0,0 +1,5 @@
+package constant
+
+// SearchPriceRangeBounds split prices into the ranges counted by the price
+// facet of a product search: below 50, 50 to 100 and so on, and 500 or more.
+var SearchPriceRangeBounds = []float64{50, 100, 250, 500}
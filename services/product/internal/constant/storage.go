@@ -0,0 +1,14 @@
+package constant
+
+const (
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+)
+
+// DefaultStorageLocalDir keeps the files of the local storage when no
+// directory is configured.
+const DefaultStorageLocalDir = "./media"
+
+// MediaPath is where the files of the local storage are served, under the API
+// base path.
+const MediaPath = "/media"
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category is a node of the category tree, a root when ParentID is nil.
+type Category struct {
+	ID        uuid.UUID  `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	ParentID  *uuid.UUID `json:"parent_id"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// Children is only filled when the categories are returned as a tree
+	Children []Category `json:"children,omitempty" gorm:"-"`
+}
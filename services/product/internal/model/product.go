@@ -1,17 +1,66 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Product struct {
-	ID          uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
-	ShopID      uuid.UUID `json:"shop_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
+	ID          uuid.UUID  `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	ShopID      uuid.UUID  `json:"shop_id"`
+	CategoryID  *uuid.UUID `json:"category_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Price       float64    `json:"price"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// SKU is the merchant's own code for the product, unique within the shop
+	SKU *string `json:"sku" gorm:"column:sku"`
+	// ArchivedAt is set when the product is no longer sold
+	ArchivedAt *time.Time     `json:"archived_at"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at"`
+	// BundleItems are the components of a bundle, empty for a plain product
+	BundleItems []ProductBundleItem `json:"bundle_items,omitempty" gorm:"foreignKey:BundleID"`
+	Tags        []ProductTag        `json:"tags,omitempty" gorm:"foreignKey:ProductID"`
+	Variants    []ProductVariant    `json:"variants,omitempty" gorm:"foreignKey:ProductID"`
+	Images      []ProductImage      `json:"images,omitempty" gorm:"foreignKey:ProductID"`
+}
+
+// IsBundle reports whether the product is sold as a set of other products.
+func (p Product) IsBundle() bool {
+	return len(p.BundleItems) > 0
+}
+
+// HasVariants reports whether the product is sold in variants rather than as
+// itself.
+func (p Product) HasVariants() bool {
+	return len(p.Variants) > 0
+}
+
+// IsAvailable reports whether the product can still be ordered.
+func (p Product) IsAvailable() bool {
+	return p.ArchivedAt == nil && !p.DeletedAt.Valid
+}
+
+// ProductBundleItem is a component of a bundle and how many of it one bundle
+// holds.
+type ProductBundleItem struct {
+	BundleID    uuid.UUID `json:"bundle_id" gorm:"column:bundle_id;primaryKey"`
+	ComponentID uuid.UUID `json:"component_id" gorm:"column:component_id;primaryKey"`
+	Quantity    int       `json:"quantity"`
 	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ProductTag is a free-form label of a product.
+type ProductTag struct {
+	ProductID uuid.UUID `gorm:"column:product_id;primaryKey"`
+	Tag       string    `gorm:"column:tag;primaryKey"`
+}
+
+// MarshalJSON writes the tag as a plain string.
+func (t ProductTag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Tag)
 }
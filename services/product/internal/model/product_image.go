@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductImage is a picture of a product, kept in the media storage with a
+// thumbnail. The images of a product are shown by position.
+type ProductImage struct {
+	ID           uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	ProductID    uuid.UUID `json:"product_id"`
+	Position     int       `json:"position"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Key          string    `json:"-"`
+	ThumbnailKey string    `json:"-"`
+	// URL and ThumbnailURL are where the storage serves the files, set by the
+	// service
+	URL          string    `json:"url" gorm:"-"`
+	ThumbnailURL string    `json:"thumbnail_url" gorm:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
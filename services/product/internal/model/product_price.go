@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductPrice is the regular price of a product from EffectiveFrom until the
+// next one starts. The past prices are the price history of the product, the
+// future ones its scheduled price changes.
+type ProductPrice struct {
+	ID            uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	ProductID     uuid.UUID `json:"product_id"`
+	Price         float64   `json:"price"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ProductSale is a price the product is sold at instead of its regular price
+// from StartsAt until EndsAt.
+type ProductSale struct {
+	ID        uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	ProductID uuid.UUID `json:"product_id"`
+	Price     float64   `json:"price"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
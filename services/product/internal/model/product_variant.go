@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductVariant is a sellable version of a product, like a size or colour of
+// it. The warehouse keeps the stock of a product with variants by variant ID.
+type ProductVariant struct {
+	ID        uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	ProductID uuid.UUID `json:"product_id"`
+	SKU       string    `json:"sku" gorm:"column:sku"`
+	// Price overrides the price of the product when set
+	Price     *float64               `json:"price"`
+	Options   []ProductVariantOption `json:"options" gorm:"foreignKey:VariantID"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// EffectivePrice is the price the variant is sold at.
+func (v ProductVariant) EffectivePrice(product Product) float64 {
+	if v.Price != nil {
+		return *v.Price
+	}
+	return product.Price
+}
+
+// ProductVariantOption is the value of an option, like "size", of a variant.
+type ProductVariantOption struct {
+	VariantID uuid.UUID `json:"-" gorm:"column:variant_id;primaryKey"`
+	Name      string    `json:"name" gorm:"column:name;primaryKey"`
+	Value     string    `json:"value"`
+}
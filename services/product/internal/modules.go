@@ -3,11 +3,14 @@ package internal
 import (
 	warehouseservice "github.com/alifmufthi91/ecommerce-system/services/product/external/warehouse_service"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/_options"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/category"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/storage"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product"
 )
 
 type Modules struct {
-	Product *product.ProductModule
+	Product  *product.ProductModule
+	Category *category.CategoryModule
 }
 
 type InitOptions struct {
@@ -18,12 +21,24 @@ func InitModules(opts InitOptions) *Modules {
 
 	warehouseSvc := warehouseservice.Init(opts.DefaultOptions)
 
+	mediaStorage, err := storage.New(opts.Config.Storage)
+	if err != nil {
+		opts.Logger.Fatal("Failed to init media storage:", err)
+	}
+
+	categoryModule := category.NewCategoryModule(category.Options{
+		DefaultOptions: opts.DefaultOptions,
+	})
+
 	productModule := product.NewProductModule(product.Options{
 		DefaultOptions:   opts.DefaultOptions,
 		WarehouseService: warehouseSvc,
+		CategoryService:  categoryModule.CategoryService,
+		Storage:          mediaStorage,
 	})
 
 	return &Modules{
-		Product: productModule,
+		Product:  productModule,
+		Category: categoryModule,
 	}
 }
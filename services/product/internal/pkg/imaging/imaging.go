@@ -0,0 +1,93 @@
+// Package imaging makes thumbnails of uploaded images. It registers the JPEG
+// and PNG decoders of the image package.
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+)
+
+const jpegQuality = 85
+
+// Thumbnail scales the image down to fit a size by size square, keeping its
+// aspect ratio. A smaller image is kept as is.
+func Thumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return img
+	}
+
+	thumbWidth, thumbHeight := size, size
+	if width > height {
+		thumbHeight = max(height*size/width, 1)
+	} else {
+		thumbWidth = max(width*size/height, 1)
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	return boxResize(src, thumbWidth, thumbHeight)
+}
+
+// boxResize shrinks the image, each pixel averaging the source pixels it
+// covers.
+func boxResize(src *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max((y+1)*srcHeight/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max((x+1)*srcWidth/width, x0+1)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					b += int(p[2])
+					a += int(p[3])
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
+
+// Encode writes the image in the format of the content type, JPEG or PNG.
+func Encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch contentType {
+	case constant.ImageContentTypeJPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	case constant.ImageContentTypePNG:
+		err = png.Encode(&buf, img)
+	default:
+		return nil, apperr.NewWithCode(apperr.CodeValueInvalid, "unsupported image content type "+contentType)
+	}
+	if err != nil {
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to encode image")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,70 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/constant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThumbnail(t *testing.T) {
+	tests := []struct {
+		name       string
+		width      int
+		height     int
+		wantWidth  int
+		wantHeight int
+	}{
+		{name: "landscape", width: 800, height: 400, wantWidth: 320, wantHeight: 160},
+		{name: "portrait", width: 300, height: 900, wantWidth: 106, wantHeight: 320},
+		{name: "square", width: 640, height: 640, wantWidth: 320, wantHeight: 320},
+		{name: "thin", width: 2000, height: 1, wantWidth: 320, wantHeight: 1},
+		{name: "small image kept", width: 200, height: 100, wantWidth: 200, wantHeight: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, tt.width, tt.height))
+
+			thumbnail := Thumbnail(img, 320)
+
+			assert.Equal(t, tt.wantWidth, thumbnail.Bounds().Dx())
+			assert.Equal(t, tt.wantHeight, thumbnail.Bounds().Dy())
+		})
+	}
+}
+
+func TestThumbnailAveragesPixels(t *testing.T) {
+	// left half black, right half white
+	img := image.NewGray(image.Rect(10, 10, 14, 12))
+	for x := 12; x < 14; x++ {
+		for y := 10; y < 12; y++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+
+	thumbnail := Thumbnail(img, 2)
+
+	assert.Equal(t, color.RGBA{A: 255}, thumbnail.At(0, 0))
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, thumbnail.At(1, 0))
+}
+
+func TestEncode(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+
+	for _, contentType := range []string{constant.ImageContentTypeJPEG, constant.ImageContentTypePNG} {
+		data, err := Encode(img, contentType)
+		require.NoError(t, err)
+
+		_, format, err := image.DecodeConfig(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, contentType, "image/"+format)
+	}
+
+	_, err := Encode(img, "image/gif")
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// localStorage keeps the files under a directory of the filesystem, the
+// service serves them itself.
+type localStorage struct {
+	dir       string
+	publicURL string
+}
+
+func NewLocalStorage(dir, publicURL string) Storage {
+	return &localStorage{dir: dir, publicURL: publicURL}
+}
+
+func (s *localStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	_, span := observ.GetTracer().Start(ctx, "localStorage.Put")
+	defer span.End()
+
+	if err := writeFile(s.path(key), data); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to store file")
+	}
+	return nil
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	_, span := observ.GetTracer().Start(ctx, "localStorage.Delete")
+	defer span.End()
+
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to delete file")
+	}
+	return nil
+}
+
+func (s *localStorage) URL(key string) string {
+	return joinURL(s.publicURL, key)
+}
+
+// path is the file of the key, kept inside the directory whatever the key
+// holds.
+func (s *localStorage) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+// writeFile writes the file aside and renames it, a reader never sees a
+// partly written file.
+func writeFile(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/config"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// s3Storage keeps the files in a bucket of an S3 compatible object storage,
+// addressed path-style so any endpoint works without bucket subdomains.
+type s3Storage struct {
+	cfg       config.S3
+	publicURL string
+	client    *http.Client
+	now       func() time.Time
+}
+
+func NewS3Storage(cfg config.S3, publicURL string, client *http.Client) Storage {
+	return &s3Storage{
+		cfg:       cfg,
+		publicURL: publicURL,
+		client:    client,
+		now:       time.Now,
+	}
+}
+
+func (s *s3Storage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	ctx, span := observ.GetTracer().Start(ctx, "s3Storage.Put")
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to store file")
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	if err := s.do(req, data); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to store file")
+	}
+	return nil
+}
+
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	ctx, span := observ.GetTracer().Start(ctx, "s3Storage.Delete")
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to delete file")
+	}
+
+	if err := s.do(req, nil); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to delete file")
+	}
+	return nil
+}
+
+// URL is the object in the bucket, unless the files are served from elsewhere,
+// like a CDN.
+func (s *s3Storage) URL(key string) string {
+	if s.publicURL != "" {
+		return joinURL(s.publicURL, key)
+	}
+	return s.objectURL(key)
+}
+
+func (s *s3Storage) objectURL(key string) string {
+	return strings.TrimSuffix(s.cfg.Endpoint, "/") + "/" + s.cfg.Bucket + "/" + uriEncode(key, false)
+}
+
+// do signs and sends the request. Deleting a missing object succeeds, as S3
+// itself answers.
+func (s *s3Storage) do(req *http.Request, payload []byte) error {
+	signV4(req, payload, s.cfg, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices && !(req.Method == http.MethodDelete && resp.StatusCode == http.StatusNotFound) {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3 %s %s: %s %s", req.Method, req.URL.Path, resp.Status, bytes.TrimSpace(body))
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
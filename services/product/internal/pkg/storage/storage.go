@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/config"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+)
+
+// Storage keeps uploaded files by key, a slash separated path like
+// "products/<id>/<file>".
+//
+//go:generate mockery --name=Storage --case underscore
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Delete removes the file, a missing file is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL is where the file is publicly served from.
+	URL(key string) string
+}
+
+func New(cfg config.Storage) (Storage, error) {
+	switch cfg.Driver {
+	case "", constant.StorageDriverLocal:
+		if cfg.LocalDir == "" {
+			cfg.LocalDir = constant.DefaultStorageLocalDir
+		}
+		return NewLocalStorage(cfg.LocalDir, cfg.PublicURL), nil
+	case constant.StorageDriverS3:
+		return NewS3Storage(cfg.S3, cfg.PublicURL, &http.Client{Timeout: 30 * time.Second}), nil
+	default:
+		return nil, apperr.NewWithCode(apperr.CodeValueInvalid, "unsupported storage driver "+cfg.Driver)
+	}
+}
+
+func joinURL(base, key string) string {
+	if base == "" || base[len(base)-1] != '/' {
+		base += "/"
+	}
+	return base + key
+}
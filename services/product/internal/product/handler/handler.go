@@ -32,5 +32,24 @@ func (h productHandler) RegisterRoutes(base *gin.RouterGroup) {
 
 	g.POST("", h.CreateProduct)
 	g.GET("", h.GetProducts)
+	g.GET("/search", h.SearchProducts)
+	g.POST("/import", h.ImportProducts)
+	g.GET("/export", h.ExportProducts)
 	g.GET("/:id", h.GetProductByID)
+	g.PUT("/:id", h.UpdateProduct)
+	g.PATCH("/:id", h.PatchProduct)
+	g.PATCH("/:id/archive", h.ArchiveProduct)
+	g.DELETE("/:id", h.DeleteProduct)
+	g.POST("/:id/variants", h.CreateVariant)
+	g.PUT("/:id/variants/:variant_id", h.UpdateVariant)
+	g.DELETE("/:id/variants/:variant_id", h.DeleteVariant)
+	g.POST("/:id/images", h.UploadImage)
+	g.PUT("/:id/images/order", h.ReorderImages)
+	g.DELETE("/:id/images/:image_id", h.DeleteImage)
+	g.GET("/:id/price", h.GetEffectivePrice)
+	g.GET("/:id/prices", h.GetPrices)
+	g.POST("/:id/prices", h.SchedulePrice)
+	g.DELETE("/:id/prices/:price_id", h.CancelScheduledPrice)
+	g.POST("/:id/sales", h.CreateSale)
+	g.DELETE("/:id/sales/:sale_id", h.EndSale)
 }
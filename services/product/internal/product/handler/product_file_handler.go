@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/httpresp"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/utils"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// @Summary		Product - Import Products
+// @Description	bulk upsert the products of a shop by SKU from a CSV file with sku, name, description, price, category_id and tags columns, or a JSONL file of the same fields. Tags in a CSV file are separated by "|". category_id and tags are optional, an updated product keeps its category and tags when the file leaves them out
+// @Tags		Product
+// @Accept		multipart/form-data
+// @Produce		json
+// @Param		file	formData	file	true	"CSV or JSONL file"
+// @Param		request	query	payload.ImportProductsReq	true	"import products request query parameters"
+// @Success		200	{object}	httpresp.Response{data=payload.ImportProductsResult}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/import [post]
+func (h *productHandler) ImportProducts(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.ImportProducts")
+	defer span.End()
+
+	var req payload.ImportProductsReq
+	if err := c.BindQuery(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "file is required"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "failed to open file"))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.productService.ImportProducts(ctx, req, file)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, result, nil)
+}
+
+// @Summary		Product - Export Products
+// @Description	stream the listed products of a shop as a CSV or JSONL file that can be imported again
+// @Tags		Product
+// @Produce		text/csv
+// @Produce		application/x-ndjson
+// @Param		request	query	payload.ExportProductsReq	true	"export products request query parameters"
+// @Success		200	{file}		file
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/export [get]
+func (h *productHandler) ExportProducts(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.ExportProducts")
+	defer span.End()
+
+	var req payload.ExportProductsReq
+	if err := c.BindQuery(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	contentType, fileName := constant.ProductFileContentTypeCSV, "products.csv"
+	if req.Format == constant.ProductFileFormatJSONL {
+		contentType, fileName = constant.ProductFileContentTypeJSONL, "products.jsonl"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+
+	if err := h.productService.ExportProducts(ctx, req, c.Writer); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		// once the file is on its way the error can't be reported anymore
+		if c.Writer.Written() {
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		httpresp.HttpRespError(c, err)
+	}
+}
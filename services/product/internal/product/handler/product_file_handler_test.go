@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/config"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportProducts_ShouldReturnExpectedStatusCode(t *testing.T) {
+	shopID := uuid.New().String()
+
+	testScenarios := []struct {
+		testName           string
+		query              string
+		field              string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			query:              "?shop_id=" + shopID + "&dry_run=true",
+			field:              "file",
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "success - jsonl",
+			query:              "?shop_id=" + shopID + "&format=jsonl",
+			field:              "file",
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - without shop ID",
+			field:              "file",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - unknown format",
+			query:              "?shop_id=" + shopID + "&format=xlsx",
+			field:              "file",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - without file",
+			query:              "?shop_id=" + shopID,
+			field:              "products",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle import products",
+			query:              "?shop_id=" + shopID,
+			field:              "file",
+			mockError:          errors.New("something went wrong"),
+			statusCodeExpected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockProductSvc := &mocks.ProductService{}
+			mockProductSvc.
+				On("ImportProducts", mock.Anything, mock.Anything, mock.Anything).
+				Return(payload.ImportProductsResult{}, scenario.mockError)
+
+			body, contentType := multipartImage(t, scenario.field, []byte("sku,name,description,price\n"))
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/products/import"+scenario.query, body)
+			ctx.Request.Header.Set("Content-Type", contentType)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &productHandler{
+				router:         r,
+				config:         mockConfig,
+				productService: mockProductSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestExportProducts_ShouldReturnExpectedStatusCode(t *testing.T) {
+	shopID := uuid.New().String()
+
+	testScenarios := []struct {
+		testName            string
+		query               string
+		mockWrite           string
+		mockError           error
+		statusCodeExpected  int
+		contentTypeExpected string
+	}{
+		{
+			testName:            "success - csv",
+			query:               "?shop_id=" + shopID,
+			mockWrite:           "sku,name,description,price,category_id,tags\n",
+			statusCodeExpected:  http.StatusOK,
+			contentTypeExpected: "text/csv",
+		},
+		{
+			testName:            "success - jsonl",
+			query:               "?shop_id=" + shopID + "&format=jsonl",
+			statusCodeExpected:  http.StatusOK,
+			contentTypeExpected: "application/x-ndjson",
+		},
+		{
+			testName:           "failed - invalid shop ID",
+			query:              "?shop_id=invalid-uuid",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:            "failed - error handle export products",
+			query:               "?shop_id=" + shopID,
+			mockError:           errors.New("something went wrong"),
+			statusCodeExpected:  http.StatusInternalServerError,
+			contentTypeExpected: "application/json; charset=utf-8",
+		},
+		{
+			testName:            "failed - error after the file went out",
+			query:               "?shop_id=" + shopID,
+			mockWrite:           "sku,name,description,price,category_id,tags\n",
+			mockError:           errors.New("something went wrong"),
+			statusCodeExpected:  http.StatusOK,
+			contentTypeExpected: "text/csv",
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockProductSvc := &mocks.ProductService{}
+			mockProductSvc.
+				On("ExportProducts", mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					if scenario.mockWrite != "" {
+						_, _ = io.WriteString(args.Get(2).(io.Writer), scenario.mockWrite)
+					}
+				}).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/products/export"+scenario.query, bytes.NewBuffer(nil))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &productHandler{
+				router:         r,
+				config:         mockConfig,
+				productService: mockProductSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+			if scenario.contentTypeExpected != "" {
+				assert.Equal(t, scenario.contentTypeExpected, rr.Header().Get("Content-Type"))
+			}
+			if scenario.mockWrite != "" {
+				assert.Equal(t, scenario.mockWrite, rr.Body.String())
+			}
+		})
+	}
+}
@@ -48,11 +48,56 @@ func (h *productHandler) CreateProduct(c *gin.Context) {
 	httpresp.HttpRespSuccess(c, "success", nil)
 }
 
+// @Summary		Product - Search Products
+// @Description	search products by name and description, best match first, with counts by shop, category and price range
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.SearchProductsReq	false	"search products request query parameters"
+// @Success		200	{object}	httpresp.Response{data=payload.SearchProductsResp}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/search [get]
+func (h *productHandler) SearchProducts(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.SearchProducts")
+	defer span.End()
+
+	var req payload.SearchProductsReq
+	if err := c.BindQuery(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	claims := auth.GetClaimsFromContext(c)
+	req.Token = claims.Token
+
+	result, total, err := h.productService.SearchProducts(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	page, pageSize := req.Pagination()
+	httpresp.HttpRespSuccess(c, result, &httpresp.Pagination{
+		CurrentPage:     int64(page),
+		CurrentElements: int64(len(result.Products)),
+		TotalPages:      (total + int64(pageSize) - 1) / int64(pageSize),
+		TotalElements:   total,
+		SortBy:          "relevance",
+	})
+}
+
 // @Summary		Product - Get Products
-// @Description	get all products
+// @Description	get a page of the products the warehouses stock, or of all products with include_out_of_stock, with their available stock, optionally of a category and its subcategories or with a tag
 // @Tags		Product
 // @Accept		json
 // @Produce		json
+// @Param		request	query	payload.GetProductsReq	false	"get products request query parameters"
 // @Success		200	{object}	httpresp.Response{data=[]payload.GetProductsResp}
 // @Failure		400	{object}	httpresp.HTTPErrResp
 // @Failure		404	{object}	httpresp.HTTPErrResp
@@ -63,16 +108,33 @@ func (h *productHandler) GetProducts(c *gin.Context) {
 	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.GetProducts")
 	defer span.End()
 
+	var req payload.GetProductsReq
+	if err := c.BindQuery(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
 	claims := auth.GetClaimsFromContext(c)
+	req.Token = claims.Token
 
-	products, err := h.productService.GetProducts(ctx, claims.Token)
+	products, total, err := h.productService.GetProducts(ctx, req)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		httpresp.HttpRespError(c, err)
 		return
 	}
 
-	httpresp.HttpRespSuccess(c, products, nil)
+	page, pageSize := req.Pagination()
+	sortBy, sortOrder := req.Sorting()
+	httpresp.HttpRespSuccess(c, products, &httpresp.Pagination{
+		CurrentPage:     int64(page),
+		CurrentElements: int64(len(products)),
+		TotalPages:      (total + int64(pageSize) - 1) / int64(pageSize),
+		TotalElements:   total,
+		SortBy:          sortBy + " " + sortOrder,
+	})
 }
 
 // @Summary		Product - Get Product By ID
@@ -108,3 +170,153 @@ func (h *productHandler) GetProductByID(c *gin.Context) {
 
 	httpresp.HttpRespSuccess(c, product, nil)
 }
+
+// @Summary		Product - Update Product
+// @Description	replace the details of a product
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @param		request	body	payload.UpdateProductReq	true	"update product request body"
+// @Success		200	{object}	httpresp.Response{data=model.Product}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id} [put]
+func (h *productHandler) UpdateProduct(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.UpdateProduct")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	var req payload.UpdateProductReq
+	if err := c.BindJSON(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ID = parsedID
+	product, err := h.productService.UpdateProduct(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, product, nil)
+}
+
+// @Summary		Product - Patch Product
+// @Description	change some details of a product
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @param		request	body	payload.PatchProductReq	true	"patch product request body"
+// @Success		200	{object}	httpresp.Response{data=model.Product}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id} [patch]
+func (h *productHandler) PatchProduct(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.PatchProduct")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	var req payload.PatchProductReq
+	if err := c.BindJSON(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ID = parsedID
+	product, err := h.productService.PatchProduct(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, product, nil)
+}
+
+// @Summary		Product - Archive Product
+// @Description	stop listing and selling a product
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/archive [patch]
+func (h *productHandler) ArchiveProduct(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.ArchiveProduct")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	if err := h.productService.ArchiveProduct(ctx, parsedID.String()); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Product - Delete Product
+// @Description	soft delete a product, orders of it stay readable
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id} [delete]
+func (h *productHandler) DeleteProduct(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.DeleteProduct")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	if err := h.productService.DeleteProduct(ctx, parsedID.String()); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
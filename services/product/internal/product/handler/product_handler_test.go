@@ -89,6 +89,7 @@ func TestCreateProduct_ShouldReturnExpectedStatusCode(t *testing.T) {
 func TestGetProducts_ShouldReturnExpectedStatusCode(t *testing.T) {
 	testScenarios := []struct {
 		testName           string
+		query              string
 		mockResult         []payload.GetProductsResp
 		mockError          error
 		statusCodeExpected int
@@ -107,6 +108,31 @@ func TestGetProducts_ShouldReturnExpectedStatusCode(t *testing.T) {
 			statusCodeExpected: http.StatusInternalServerError,
 			mockError:          errors.New("something went wrong"),
 		},
+		{
+			testName:           "success - filtered by category and tag",
+			query:              "?category_id=" + uuid.New().String() + "&tag=summer",
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid category ID",
+			query:              "?category_id=invalid-uuid",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "success - paged and sorted with out of stock products",
+			query:              "?include_out_of_stock=true&sort_by=price&sort_order=desc&page=2&page_size=10",
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid sort column",
+			query:              "?sort_by=stock",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - page size too large",
+			query:              "?page_size=101",
+			statusCodeExpected: http.StatusBadRequest,
+		},
 	}
 
 	for _, scenario := range testScenarios {
@@ -123,11 +149,86 @@ func TestGetProducts_ShouldReturnExpectedStatusCode(t *testing.T) {
 			mockProductSvc := &mocks.ProductService{}
 			mockProductSvc.
 				On("GetProducts", mock.Anything, mock.Anything).
-				Return(scenario.mockResult, scenario.mockError)
+				Return(scenario.mockResult, int64(len(scenario.mockResult)), scenario.mockError)
 
 			rr := httptest.NewRecorder()
 			ctx := pkg.GetTestGinContext(rr)
-			ctx.Request = httptest.NewRequest(http.MethodGet, "/products", nil)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/products"+scenario.query, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &productHandler{
+				router:         r,
+				config:         mockConfig,
+				productService: mockProductSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestSearchProducts_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		query              string
+		mockResult         payload.SearchProductsResp
+		mockTotal          int64
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			query:              "?q=shirt&shop_id=" + uuid.New().String() + "&price_min=10&price_max=50&in_stock=true&page=2&page_size=10",
+			statusCodeExpected: http.StatusOK,
+			mockResult: payload.SearchProductsResp{
+				Products: []payload.GetProductsResp{
+					{ID: uuid.New(), Name: "Red Shirt", AvailableStock: 10, Price: 20.0},
+				},
+			},
+			mockTotal: 11,
+		},
+		{
+			testName:           "failed - error handle search products",
+			query:              "?q=shirt",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - price max below price min",
+			query:              "?price_min=50&price_max=10",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - invalid page size",
+			query:              "?page_size=101",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockProductSvc := &mocks.ProductService{}
+			mockProductSvc.
+				On("SearchProducts", mock.Anything, mock.Anything).
+				Return(scenario.mockResult, scenario.mockTotal, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/products/search"+scenario.query, nil)
 			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
 
 			h := &productHandler{
@@ -216,3 +317,285 @@ func TestGetProductByID_ShouldReturnExpectedStatusCode(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateProduct_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		productID          string
+		requestBody        string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			productID:          uuid.New().String(),
+			requestBody:        `{"name": "Item 1", "description": "description A", "price": 100}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - error handle update product",
+			productID:          uuid.New().String(),
+			requestBody:        `{"name": "Item 1", "description": "description A", "price": 100}`,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - invalid param",
+			productID:          "invalid-uuid",
+			requestBody:        `{"name": "Item 1", "description": "description A", "price": 100}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - invalid request body",
+			productID:          uuid.New().String(),
+			requestBody:        `{"name": "Item 1", "price": 100}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockProductSvc := &mocks.ProductService{}
+			mockProductSvc.
+				On("UpdateProduct", mock.Anything, mock.Anything).
+				Return(model.Product{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/products/"+scenario.productID, strings.NewReader(scenario.requestBody))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &productHandler{
+				router:         r,
+				config:         mockConfig,
+				productService: mockProductSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestPatchProduct_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		productID          string
+		requestBody        string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			productID:          uuid.New().String(),
+			requestBody:        `{"price": 120}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - error handle patch product",
+			productID:          uuid.New().String(),
+			requestBody:        `{"price": 120}`,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - invalid param",
+			productID:          "invalid-uuid",
+			requestBody:        `{"price": 120}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - invalid request body",
+			productID:          uuid.New().String(),
+			requestBody:        `{"price": -1}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - category set and cleared",
+			productID:          uuid.New().String(),
+			requestBody:        `{"category_id": "0b1e7f52-3c4d-4e8a-9f10-2a3b4c5d6e7f", "clear_category": true}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockProductSvc := &mocks.ProductService{}
+			mockProductSvc.
+				On("PatchProduct", mock.Anything, mock.Anything).
+				Return(model.Product{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/products/"+scenario.productID, strings.NewReader(scenario.requestBody))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &productHandler{
+				router:         r,
+				config:         mockConfig,
+				productService: mockProductSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestArchiveProduct_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		productID          string
+		requestBody        string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			productID:          uuid.New().String(),
+			requestBody:        "",
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - error handle archive product",
+			productID:          uuid.New().String(),
+			requestBody:        "",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - invalid param",
+			productID:          "invalid-uuid",
+			requestBody:        "",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockProductSvc := &mocks.ProductService{}
+			mockProductSvc.
+				On("ArchiveProduct", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/products/"+scenario.productID+"/archive", strings.NewReader(scenario.requestBody))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &productHandler{
+				router:         r,
+				config:         mockConfig,
+				productService: mockProductSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestDeleteProduct_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		productID          string
+		requestBody        string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			productID:          uuid.New().String(),
+			requestBody:        "",
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - error handle delete product",
+			productID:          uuid.New().String(),
+			requestBody:        "",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - invalid param",
+			productID:          "invalid-uuid",
+			requestBody:        "",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockProductSvc := &mocks.ProductService{}
+			mockProductSvc.
+				On("DeleteProduct", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodDelete, "/products/"+scenario.productID, strings.NewReader(scenario.requestBody))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &productHandler{
+				router:         r,
+				config:         mockConfig,
+				productService: mockProductSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/httpresp"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/utils"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// multipartOverhead leaves room in an upload for the multipart headers around
+// the image.
+const multipartOverhead = 1 << 20
+
+// @Summary		Product - Upload Product Image
+// @Description	add a JPEG or PNG image after the other images of a product, a thumbnail of it is made
+// @Tags		Product
+// @Accept		multipart/form-data
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @Param		image	formData	file	true	"image, at most 5 MiB"
+// @Success		200	{object}	httpresp.Response{data=model.ProductImage}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/images [post]
+func (h *productHandler) UploadImage(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.UploadImage")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constant.ImageMaxSize+multipartOverhead)
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, fmt.Sprintf("image must not be larger than %d MiB", constant.ImageMaxSize>>20)))
+			return
+		}
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "image file is required"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to read image"))
+		return
+	}
+	defer file.Close()
+
+	// one byte over the limit is enough to reject the image
+	data, err := io.ReadAll(io.LimitReader(file, constant.ImageMaxSize+1))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to read image"))
+		return
+	}
+
+	image, err := h.productService.UploadImage(ctx, payload.UploadProductImageReq{
+		ProductID: parsedID,
+		Data:      data,
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, image, nil)
+}
+
+// @Summary		Product - Reorder Product Images
+// @Description	set the order of the images of a product, every image is listed once
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @param		request	body	payload.ReorderProductImagesReq	true	"reorder product images request body"
+// @Success		200	{object}	httpresp.Response{data=[]model.ProductImage}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/images/order [put]
+func (h *productHandler) ReorderImages(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.ReorderImages")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	var req payload.ReorderProductImagesReq
+	if err := c.BindJSON(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ProductID = parsedID
+	images, err := h.productService.ReorderImages(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, images, nil)
+}
+
+// @Summary		Product - Delete Product Image
+// @Description	delete an image of a product with its thumbnail
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @Param		image_id	path	string	true	"image ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/images/{image_id} [delete]
+func (h *productHandler) DeleteImage(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.DeleteImage")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	parsedImageID, err := uuid.Parse(c.Param("image_id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid image ID"))
+		return
+	}
+
+	if err := h.productService.DeleteImage(ctx, parsedID.String(), parsedImageID.String()); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
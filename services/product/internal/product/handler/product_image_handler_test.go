@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/config"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// multipartImage builds an upload form holding the data as the given field.
+func multipartImage(t *testing.T, field string, data []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, "image.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return body, writer.FormDataContentType()
+}
+
+func TestImageHandlers_ShouldReturnExpectedStatusCode(t *testing.T) {
+	productID := uuid.New().String()
+	imageID := uuid.New().String()
+
+	testScenarios := []struct {
+		testName           string
+		method             string
+		path               string
+		field              string
+		data               []byte
+		requestBody        string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success - upload",
+			method:             http.MethodPost,
+			path:               "/products/" + productID + "/images",
+			field:              "image",
+			data:               []byte("image"),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - upload without image",
+			method:             http.MethodPost,
+			path:               "/products/" + productID + "/images",
+			field:              "file",
+			data:               []byte("image"),
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - upload too large",
+			method:             http.MethodPost,
+			path:               "/products/" + productID + "/images",
+			field:              "image",
+			data:               make([]byte, constant.ImageMaxSize+multipartOverhead),
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - upload with invalid product ID",
+			method:             http.MethodPost,
+			path:               "/products/invalid-uuid/images",
+			field:              "image",
+			data:               []byte("image"),
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle upload image",
+			method:             http.MethodPost,
+			path:               "/products/" + productID + "/images",
+			field:              "image",
+			data:               []byte("image"),
+			mockError:          errors.New("something went wrong"),
+			statusCodeExpected: http.StatusInternalServerError,
+		},
+		{
+			testName:           "success - reorder",
+			method:             http.MethodPut,
+			path:               "/products/" + productID + "/images/order",
+			requestBody:        `{"image_ids":["` + imageID + `"]}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - reorder without images",
+			method:             http.MethodPut,
+			path:               "/products/" + productID + "/images/order",
+			requestBody:        `{"image_ids":[]}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "success - delete",
+			method:             http.MethodDelete,
+			path:               "/products/" + productID + "/images/" + imageID,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - delete with invalid image ID",
+			method:             http.MethodDelete,
+			path:               "/products/" + productID + "/images/invalid-uuid",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle delete image",
+			method:             http.MethodDelete,
+			path:               "/products/" + productID + "/images/" + imageID,
+			mockError:          errors.New("something went wrong"),
+			statusCodeExpected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockProductSvc := &mocks.ProductService{}
+			mockProductSvc.
+				On("UploadImage", mock.Anything, mock.Anything).
+				Return(model.ProductImage{}, scenario.mockError)
+			mockProductSvc.
+				On("ReorderImages", mock.Anything, mock.Anything).
+				Return([]model.ProductImage{}, scenario.mockError)
+			mockProductSvc.
+				On("DeleteImage", mock.Anything, mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			if scenario.data != nil {
+				body, contentType := multipartImage(t, scenario.field, scenario.data)
+				ctx.Request = httptest.NewRequest(scenario.method, scenario.path, body)
+				ctx.Request.Header.Set("Content-Type", contentType)
+			} else {
+				ctx.Request = httptest.NewRequest(scenario.method, scenario.path, bytes.NewBufferString(scenario.requestBody))
+			}
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &productHandler{
+				router:         r,
+				config:         mockConfig,
+				productService: mockProductSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
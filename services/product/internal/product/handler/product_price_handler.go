@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/httpresp"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/utils"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// @Summary		Product - Get Product Prices
+// @Description	get the price history, scheduled price changes and sales of a product
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @Success		200	{object}	httpresp.Response{data=payload.GetProductPricesResp}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/prices [get]
+func (h *productHandler) GetPrices(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.GetPrices")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	prices, err := h.productService.GetPrices(ctx, parsedID.String())
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, prices, nil)
+}
+
+// @Summary		Product - Schedule Product Price
+// @Description	change the regular price of a product from a time in the future
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @param		request	body	payload.ScheduleProductPriceReq	true	"schedule product price request body"
+// @Success		200	{object}	httpresp.Response{data=model.ProductPrice}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/prices [post]
+func (h *productHandler) SchedulePrice(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.SchedulePrice")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	var req payload.ScheduleProductPriceReq
+	if err := c.BindJSON(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ProductID = parsedID
+	price, err := h.productService.SchedulePrice(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, price, nil)
+}
+
+// @Summary		Product - Cancel Scheduled Product Price
+// @Description	cancel a price change of a product that is not in effect yet
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @Param		price_id	path	string	true	"price ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/prices/{price_id} [delete]
+func (h *productHandler) CancelScheduledPrice(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.CancelScheduledPrice")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	parsedPriceID, err := uuid.Parse(c.Param("price_id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid price ID"))
+		return
+	}
+
+	if err := h.productService.CancelScheduledPrice(ctx, parsedID.String(), parsedPriceID.String()); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Product - Create Product Sale
+// @Description	sell a product at a sale price from starts_at until ends_at, sales of a product may not overlap
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @param		request	body	payload.CreateProductSaleReq	true	"create product sale request body"
+// @Success		200	{object}	httpresp.Response{data=model.ProductSale}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/sales [post]
+func (h *productHandler) CreateSale(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.CreateSale")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	var req payload.CreateProductSaleReq
+	if err := c.BindJSON(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ProductID = parsedID
+	sale, err := h.productService.CreateSale(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, sale, nil)
+}
+
+// @Summary		Product - End Product Sale
+// @Description	cancel a sale that has not started, or end a running one now
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @Param		sale_id	path	string	true	"sale ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/sales/{sale_id} [delete]
+func (h *productHandler) EndSale(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.EndSale")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	parsedSaleID, err := uuid.Parse(c.Param("sale_id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid sale ID"))
+		return
+	}
+
+	if err := h.productService.EndSale(ctx, parsedID.String(), parsedSaleID.String()); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Product - Get Effective Product Price
+// @Description	get the price a product, or one of its variants, sells for at a time, sales included
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @Param		request	query	payload.GetEffectivePriceReq	false	"time as RFC 3339, now by default, and variant"
+// @Success		200	{object}	httpresp.Response{data=payload.GetEffectivePriceResp}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/price [get]
+func (h *productHandler) GetEffectivePrice(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.GetEffectivePrice")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	var req payload.GetEffectivePriceReq
+	if err := c.BindQuery(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ProductID = parsedID
+	price, err := h.productService.GetEffectivePrice(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, price, nil)
+}
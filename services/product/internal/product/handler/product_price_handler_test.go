@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/config"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPriceHandlers_ShouldReturnExpectedStatusCode(t *testing.T) {
+	productID := uuid.New().String()
+	priceID := uuid.New().String()
+	saleID := uuid.New().String()
+
+	testScenarios := []struct {
+		testName           string
+		method             string
+		path               string
+		requestBody        string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success - get effective price",
+			method:             http.MethodGet,
+			path:               "/products/" + productID + "/price?at=2026-03-01T10:00:00Z&variant_id=" + uuid.New().String(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - get effective price with invalid time",
+			method:             http.MethodGet,
+			path:               "/products/" + productID + "/price?at=yesterday",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - get effective price with invalid variant ID",
+			method:             http.MethodGet,
+			path:               "/products/" + productID + "/price?variant_id=invalid-uuid",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "success - get prices",
+			method:             http.MethodGet,
+			path:               "/products/" + productID + "/prices",
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - get prices with invalid product ID",
+			method:             http.MethodGet,
+			path:               "/products/invalid-uuid/prices",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "success - schedule price",
+			method:             http.MethodPost,
+			path:               "/products/" + productID + "/prices",
+			requestBody:        `{"price":9.5,"effective_from":"2030-01-01T00:00:00Z"}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - schedule price without effective_from",
+			method:             http.MethodPost,
+			path:               "/products/" + productID + "/prices",
+			requestBody:        `{"price":9.5}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle schedule price",
+			method:             http.MethodPost,
+			path:               "/products/" + productID + "/prices",
+			requestBody:        `{"price":9.5,"effective_from":"2030-01-01T00:00:00Z"}`,
+			mockError:          errors.New("something went wrong"),
+			statusCodeExpected: http.StatusInternalServerError,
+		},
+		{
+			testName:           "success - cancel scheduled price",
+			method:             http.MethodDelete,
+			path:               "/products/" + productID + "/prices/" + priceID,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - cancel scheduled price with invalid price ID",
+			method:             http.MethodDelete,
+			path:               "/products/" + productID + "/prices/invalid-uuid",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "success - create sale",
+			method:             http.MethodPost,
+			path:               "/products/" + productID + "/sales",
+			requestBody:        `{"price":7,"starts_at":"2030-01-01T00:00:00Z","ends_at":"2030-01-08T00:00:00Z"}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - create sale with non-positive price",
+			method:             http.MethodPost,
+			path:               "/products/" + productID + "/sales",
+			requestBody:        `{"price":-1,"starts_at":"2030-01-01T00:00:00Z","ends_at":"2030-01-08T00:00:00Z"}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "success - end sale",
+			method:             http.MethodDelete,
+			path:               "/products/" + productID + "/sales/" + saleID,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - error handle end sale",
+			method:             http.MethodDelete,
+			path:               "/products/" + productID + "/sales/" + saleID,
+			mockError:          errors.New("something went wrong"),
+			statusCodeExpected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockProductSvc := &mocks.ProductService{}
+			mockProductSvc.
+				On("GetEffectivePrice", mock.Anything, mock.Anything).
+				Return(payload.GetEffectivePriceResp{}, scenario.mockError)
+			mockProductSvc.
+				On("GetPrices", mock.Anything, mock.Anything).
+				Return(payload.GetProductPricesResp{}, scenario.mockError)
+			mockProductSvc.
+				On("SchedulePrice", mock.Anything, mock.Anything).
+				Return(model.ProductPrice{}, scenario.mockError)
+			mockProductSvc.
+				On("CancelScheduledPrice", mock.Anything, mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+			mockProductSvc.
+				On("CreateSale", mock.Anything, mock.Anything).
+				Return(model.ProductSale{}, scenario.mockError)
+			mockProductSvc.
+				On("EndSale", mock.Anything, mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(scenario.method, scenario.path, bytes.NewBufferString(scenario.requestBody))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &productHandler{
+				router:         r,
+				config:         mockConfig,
+				productService: mockProductSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
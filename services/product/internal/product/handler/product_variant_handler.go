@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/auth"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/httpresp"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/utils"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// @Summary		Product - Create Product Variant
+// @Description	add a variant to a product, its stock is kept in the warehouse by variant ID
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @param		request	body	payload.CreateProductVariantReq	true	"create product variant request body"
+// @Success		200	{object}	httpresp.Response{data=model.ProductVariant}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/variants [post]
+func (h *productHandler) CreateVariant(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.CreateVariant")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	var req payload.CreateProductVariantReq
+	if err := c.BindJSON(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	claims := auth.GetClaimsFromContext(c)
+	req.ProductID = parsedID
+	req.Token = claims.Token
+	variant, err := h.productService.CreateVariant(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, variant, nil)
+}
+
+// @Summary		Product - Update Product Variant
+// @Description	replace the SKU, price and options of a product variant
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @Param		variant_id	path	string	true	"variant ID"
+// @param		request	body	payload.UpdateProductVariantReq	true	"update product variant request body"
+// @Success		200	{object}	httpresp.Response{data=model.ProductVariant}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/variants/{variant_id} [put]
+func (h *productHandler) UpdateVariant(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.UpdateVariant")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	parsedVariantID, err := uuid.Parse(c.Param("variant_id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid variant ID"))
+		return
+	}
+
+	var req payload.UpdateProductVariantReq
+	if err := c.BindJSON(&req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ProductID = parsedID
+	req.ID = parsedVariantID
+	variant, err := h.productService.UpdateVariant(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, variant, nil)
+}
+
+// @Summary		Product - Delete Product Variant
+// @Description	delete a variant of a product the warehouses hold no stock of, orders of it keep the variant ID
+// @Tags		Product
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"product ID"
+// @Param		variant_id	path	string	true	"variant ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/products/{id}/variants/{variant_id} [delete]
+func (h *productHandler) DeleteVariant(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "productHandler.DeleteVariant")
+	defer span.End()
+
+	parsedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	parsedVariantID, err := uuid.Parse(c.Param("variant_id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid variant ID"))
+		return
+	}
+
+	claims := auth.GetClaimsFromContext(c)
+	req := payload.DeleteProductVariantReq{
+		ProductID: parsedID,
+		ID:        parsedVariantID,
+		Token:     claims.Token,
+	}
+	if err := h.productService.DeleteVariant(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/config"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVariantHandlers_ShouldReturnExpectedStatusCode(t *testing.T) {
+	productID := uuid.New().String()
+	variantID := uuid.New().String()
+	validBody := `{"sku":"SHIRT-M","price":25,"options":[{"name":"size","value":"M"}]}`
+
+	testScenarios := []struct {
+		testName           string
+		method             string
+		path               string
+		requestBody        string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success - create",
+			method:             http.MethodPost,
+			path:               "/products/" + productID + "/variants",
+			requestBody:        validBody,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - create without options",
+			method:             http.MethodPost,
+			path:               "/products/" + productID + "/variants",
+			requestBody:        `{"sku":"SHIRT-M"}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - create with invalid product ID",
+			method:             http.MethodPost,
+			path:               "/products/invalid-uuid/variants",
+			requestBody:        validBody,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle create variant",
+			method:             http.MethodPost,
+			path:               "/products/" + productID + "/variants",
+			requestBody:        validBody,
+			mockError:          errors.New("something went wrong"),
+			statusCodeExpected: http.StatusInternalServerError,
+		},
+		{
+			testName:           "success - update",
+			method:             http.MethodPut,
+			path:               "/products/" + productID + "/variants/" + variantID,
+			requestBody:        validBody,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - update with invalid variant ID",
+			method:             http.MethodPut,
+			path:               "/products/" + productID + "/variants/invalid-uuid",
+			requestBody:        validBody,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - update with a negative price",
+			method:             http.MethodPut,
+			path:               "/products/" + productID + "/variants/" + variantID,
+			requestBody:        `{"sku":"SHIRT-M","price":-1,"options":[{"name":"size","value":"M"}]}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "success - delete",
+			method:             http.MethodDelete,
+			path:               "/products/" + productID + "/variants/" + variantID,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - error handle delete variant",
+			method:             http.MethodDelete,
+			path:               "/products/" + productID + "/variants/" + variantID,
+			mockError:          errors.New("something went wrong"),
+			statusCodeExpected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockProductSvc := &mocks.ProductService{}
+			mockProductSvc.
+				On("CreateVariant", mock.Anything, mock.Anything).
+				Return(model.ProductVariant{}, scenario.mockError)
+			mockProductSvc.
+				On("UpdateVariant", mock.Anything, mock.Anything).
+				Return(model.ProductVariant{}, scenario.mockError)
+			mockProductSvc.
+				On("DeleteVariant", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(scenario.method, scenario.path, strings.NewReader(scenario.requestBody))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &productHandler{
+				router:         r,
+				config:         mockConfig,
+				productService: mockProductSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
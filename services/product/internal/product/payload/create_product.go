@@ -2,9 +2,20 @@ package payload
 
 import "github.com/google/uuid"
 
+// CreateProductReq creates a product, or a bundle of other products of the
+// same shop when BundleItems is set.
 type CreateProductReq struct {
-	Name        string    `json:"name" binding:"required"`
-	Description string    `json:"description" binding:"required"`
-	Price       float64   `json:"price" binding:"required"`
-	ShopID      uuid.UUID `json:"shop_id" binding:"required"`
+	Name        string                `json:"name" binding:"required"`
+	Description string                `json:"description" binding:"required"`
+	Price       float64               `json:"price" binding:"required"`
+	ShopID      uuid.UUID             `json:"shop_id" binding:"required"`
+	SKU         string                `json:"sku" binding:"omitempty,max=64"`
+	CategoryID  *uuid.UUID            `json:"category_id"`
+	Tags        []string              `json:"tags" binding:"omitempty,max=20,dive,max=50"`
+	BundleItems []CreateBundleItemReq `json:"bundle_items" binding:"omitempty,unique=ComponentID,dive"`
+}
+
+type CreateBundleItemReq struct {
+	ComponentID uuid.UUID `json:"component_id" binding:"required"`
+	Quantity    int       `json:"quantity" binding:"required,gt=0"`
 }
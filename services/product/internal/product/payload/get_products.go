@@ -3,16 +3,83 @@ package payload
 import (
 	"time"
 
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
 	"github.com/google/uuid"
 )
 
+const (
+	DefaultProductPageSize = 20
+	DefaultProductSortBy   = "name"
+)
+
+// GetProductsReq filters, sorts and pages the listed products. A category lists
+// the products of its subcategories too. Products the warehouses keep no stock
+// of are left out unless IncludeOutOfStock is set, those that ran out are
+// listed with no available stock.
+type GetProductsReq struct {
+	Token             string `form:"-"`
+	CategoryID        string `form:"category_id" binding:"omitempty,uuid"`
+	Tag               string `form:"tag"`
+	IncludeOutOfStock bool   `form:"include_out_of_stock"`
+	SortBy            string `form:"sort_by" binding:"omitempty,oneof=name price created_at"`
+	SortOrder         string `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	Page              int    `form:"page" binding:"omitempty,min=1"`
+	PageSize          int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+
+	CategoryIDIN []string `form:"-"`
+}
+
+// Pagination returns the requested page and page size with defaults applied.
+func (r GetProductsReq) Pagination() (page, pageSize int) {
+	page, pageSize = r.Page, r.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = DefaultProductPageSize
+	}
+	return page, pageSize
+}
+
+// Sorting returns the requested sort column and order, by name ascending when
+// not given.
+func (r GetProductsReq) Sorting() (sortBy, sortOrder string) {
+	sortBy, sortOrder = r.SortBy, r.SortOrder
+	if sortBy == "" {
+		sortBy = DefaultProductSortBy
+	}
+	if sortOrder == "" {
+		sortOrder = "asc"
+	}
+	return sortBy, sortOrder
+}
+
+// GetProductsResp is a product with its available stock. The available stock
+// of a bundle is how many whole bundles its components make up, and of a
+// product with variants the sum of theirs.
 type GetProductsResp struct {
-	ID             uuid.UUID `json:"id"`
-	ShopID         uuid.UUID `json:"shop_id"`
-	Name           string    `json:"name"`
-	Description    string    `json:"description"`
-	Price          float64   `json:"price"`
-	AvailableStock int       `json:"available_stock"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             uuid.UUID                 `json:"id"`
+	ShopID         uuid.UUID                 `json:"shop_id"`
+	CategoryID     *uuid.UUID                `json:"category_id"`
+	SKU            *string                   `json:"sku"`
+	Name           string                    `json:"name"`
+	Description    string                    `json:"description"`
+	Price          float64                   `json:"price"`
+	AvailableStock int                       `json:"available_stock"`
+	BundleItems    []model.ProductBundleItem `json:"bundle_items,omitempty"`
+	Tags           []model.ProductTag        `json:"tags,omitempty"`
+	Variants       []GetProductsRespVariant  `json:"variants,omitempty"`
+	Images         []model.ProductImage      `json:"images,omitempty"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	UpdatedAt      time.Time                 `json:"updated_at"`
+}
+
+// GetProductsRespVariant is a variant with the price it is sold at and its
+// available stock.
+type GetProductsRespVariant struct {
+	ID             uuid.UUID                    `json:"id"`
+	SKU            string                       `json:"sku"`
+	Options        []model.ProductVariantOption `json:"options"`
+	Price          float64                      `json:"price"`
+	AvailableStock int                          `json:"available_stock"`
 }
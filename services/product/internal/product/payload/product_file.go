@@ -0,0 +1,48 @@
+package payload
+
+import "github.com/google/uuid"
+
+// ImportProductsReq imports a CSV or JSONL file of products into a shop. A row
+// updates the product of the shop with its SKU, or creates one when there is
+// none. The whole file is applied in one transaction and nothing is written if
+// any row is invalid.
+type ImportProductsReq struct {
+	ShopID string `form:"shop_id" binding:"required,uuid"`
+	Format string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+	DryRun bool   `form:"dry_run"`
+}
+
+type ImportProductsResult struct {
+	DryRun      bool                  `json:"dry_run"`
+	TotalRows   int                   `json:"total_rows"`
+	ValidRows   int                   `json:"valid_rows"`
+	CreatedRows int                   `json:"created_rows"`
+	UpdatedRows int                   `json:"updated_rows"`
+	Errors      []ImportProductRowErr `json:"errors"`
+}
+
+// ImportProductRowErr reports why a row was not imported. Row is the line
+// number in the file, the header of a CSV file being line 1.
+type ImportProductRowErr struct {
+	Row     int    `json:"row"`
+	SKU     string `json:"sku,omitempty"`
+	Message string `json:"message"`
+}
+
+// ExportProductsReq exports the products of a shop in the format they are
+// imported in.
+type ExportProductsReq struct {
+	ShopID string `form:"shop_id" binding:"required,uuid"`
+	Format string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+}
+
+// ProductFileRow is a product in an imported or exported file. A CSV file has
+// a column of each field, the tags separated by "|".
+type ProductFileRow struct {
+	SKU         string     `json:"sku"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Price       float64    `json:"price"`
+	CategoryID  *uuid.UUID `json:"category_id"`
+	Tags        []string   `json:"tags"`
+}
@@ -0,0 +1,16 @@
+package payload
+
+import "github.com/google/uuid"
+
+// UploadProductImageReq adds an image after the other images of a product. Its
+// content type is detected from the data.
+type UploadProductImageReq struct {
+	ProductID uuid.UUID
+	Data      []byte
+}
+
+// ReorderProductImagesReq lists every image of a product in its new order.
+type ReorderProductImagesReq struct {
+	ProductID uuid.UUID   `json:"-"`
+	ImageIDs  []uuid.UUID `json:"image_ids" binding:"required,min=1"`
+}
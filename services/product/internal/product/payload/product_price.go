@@ -0,0 +1,50 @@
+package payload
+
+import (
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/google/uuid"
+)
+
+// ScheduleProductPriceReq changes the regular price of a product from a time
+// in the future.
+type ScheduleProductPriceReq struct {
+	ProductID     uuid.UUID `json:"-"`
+	Price         float64   `json:"price" binding:"required,gt=0"`
+	EffectiveFrom time.Time `json:"effective_from" binding:"required"`
+}
+
+// CreateProductSaleReq sells a product at a price from StartsAt until EndsAt.
+type CreateProductSaleReq struct {
+	ProductID uuid.UUID `json:"-"`
+	Price     float64   `json:"price" binding:"required,gt=0"`
+	StartsAt  time.Time `json:"starts_at" binding:"required"`
+	EndsAt    time.Time `json:"ends_at" binding:"required"`
+}
+
+// GetProductPricesResp is the price history, scheduled prices and sales of a
+// product.
+type GetProductPricesResp struct {
+	Prices []model.ProductPrice `json:"prices"`
+	Sales  []model.ProductSale  `json:"sales"`
+}
+
+// GetEffectivePriceReq asks the price of a product, or of one of its
+// variants, at a time. No time means now.
+type GetEffectivePriceReq struct {
+	ProductID uuid.UUID `form:"-"`
+	VariantID string    `form:"variant_id" binding:"omitempty,uuid"`
+	At        time.Time `form:"at"`
+}
+
+type GetEffectivePriceResp struct {
+	ProductID    uuid.UUID  `json:"product_id"`
+	VariantID    *uuid.UUID `json:"variant_id"`
+	At           time.Time  `json:"at"`
+	RegularPrice float64    `json:"regular_price"`
+	// SalePrice is set when a sale runs at the time
+	SalePrice *float64 `json:"sale_price"`
+	// Price is what the product is sold at
+	Price float64 `json:"price"`
+}
@@ -0,0 +1,35 @@
+package payload
+
+import "github.com/google/uuid"
+
+// CreateProductVariantReq adds a variant to a product. Each variant of a
+// product has a different set of option values, like size M and colour red.
+type CreateProductVariantReq struct {
+	ProductID uuid.UUID `json:"-"`
+	Token     string    `json:"-"`
+	SKU       string    `json:"sku" binding:"required,max=64"`
+	// Price overrides the price of the product when set
+	Price   *float64                  `json:"price" binding:"omitempty,gt=0"`
+	Options []ProductVariantOptionReq `json:"options" binding:"required,min=1,max=5,dive"`
+}
+
+// UpdateProductVariantReq replaces the SKU, price and options of a variant.
+type UpdateProductVariantReq struct {
+	ProductID uuid.UUID                 `json:"-"`
+	ID        uuid.UUID                 `json:"-"`
+	SKU       string                    `json:"sku" binding:"required,max=64"`
+	Price     *float64                  `json:"price" binding:"omitempty,gt=0"`
+	Options   []ProductVariantOptionReq `json:"options" binding:"required,min=1,max=5,dive"`
+}
+
+// DeleteProductVariantReq removes a variant the warehouses hold no stock of.
+type DeleteProductVariantReq struct {
+	ProductID uuid.UUID
+	ID        uuid.UUID
+	Token     string
+}
+
+type ProductVariantOptionReq struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Value string `json:"value" binding:"required,max=100"`
+}
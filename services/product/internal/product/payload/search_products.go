@@ -0,0 +1,60 @@
+package payload
+
+const (
+	DefaultSearchPageSize = 20
+)
+
+// SearchProductsReq searches products by name and description. Words are
+// matched by prefix, and names close to the query match despite typos.
+type SearchProductsReq struct {
+	Query      string  `form:"q" binding:"omitempty,max=200"`
+	ShopID     string  `form:"shop_id" binding:"omitempty,uuid"`
+	CategoryID string  `form:"category_id" binding:"omitempty,uuid"`
+	PriceMin   float64 `form:"price_min" binding:"omitempty,min=0"`
+	PriceMax   float64 `form:"price_max" binding:"omitempty,min=0,gtefield=PriceMin"`
+	InStock    bool    `form:"in_stock"`
+	Page       int     `form:"page" binding:"omitempty,min=1"`
+	PageSize   int     `form:"page_size" binding:"omitempty,min=1,max=100"`
+
+	Token        string   `form:"-"`
+	CategoryIDIN []string `form:"-"`
+}
+
+// Pagination returns the requested page and page size with defaults applied.
+func (r SearchProductsReq) Pagination() (page, pageSize int) {
+	page, pageSize = r.Page, r.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = DefaultSearchPageSize
+	}
+	return page, pageSize
+}
+
+type SearchProductsResp struct {
+	Products []GetProductsResp    `json:"products"`
+	Facets   SearchProductsFacets `json:"facets"`
+}
+
+// SearchProductsFacets counts the matching products by shop, category and
+// price range. Each facet ignores its own filter, so the other values of it
+// can still be picked.
+type SearchProductsFacets struct {
+	Shops       []FacetCount      `json:"shops"`
+	Categories  []FacetCount      `json:"categories"`
+	PriceRanges []PriceRangeCount `json:"price_ranges"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// PriceRangeCount counts the products priced from Min up to, but not
+// including, Max. The last range has no Max.
+type PriceRangeCount struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int64    `json:"count"`
+}
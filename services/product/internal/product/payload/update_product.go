@@ -0,0 +1,28 @@
+package payload
+
+import "github.com/google/uuid"
+
+// UpdateProductReq replaces the details of a product, its category and tags
+// included. The shop and the items of a bundle can't be changed.
+type UpdateProductReq struct {
+	ID          uuid.UUID  `json:"-"`
+	Name        string     `json:"name" binding:"required"`
+	Description string     `json:"description" binding:"required"`
+	Price       float64    `json:"price" binding:"required,gt=0"`
+	CategoryID  *uuid.UUID `json:"category_id"`
+	Tags        []string   `json:"tags" binding:"omitempty,max=20,dive,max=50"`
+}
+
+// PatchProductReq changes only the details that are set.
+type PatchProductReq struct {
+	ID          uuid.UUID  `json:"-"`
+	Name        *string    `json:"name" binding:"omitempty,min=1"`
+	Description *string    `json:"description" binding:"omitempty,min=1"`
+	Price       *float64   `json:"price" binding:"omitempty,gt=0"`
+	CategoryID  *uuid.UUID `json:"category_id" binding:"excluded_with=ClearCategory"`
+	// ClearCategory removes the product from its category
+	ClearCategory bool    `json:"clear_category"`
+	SKU           *string `json:"sku" binding:"omitempty,min=1,max=64"`
+	// Tags replace the tags of the product when set, an empty list clears them
+	Tags []string `json:"tags" binding:"omitempty,max=20,dive,max=50"`
+}
@@ -3,30 +3,37 @@ package product
 import (
 	warehouseservice "github.com/alifmufthi91/ecommerce-system/services/product/external/warehouse_service"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/_options"
+	categoryservice "github.com/alifmufthi91/ecommerce-system/services/product/internal/category/service"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/registry"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/storage"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/handler"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/repository"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/service"
 )
 
 type ProductModule struct {
-	productService service.ProductService
+	ProductService service.ProductService
 }
 
 type Options struct {
 	_options.DefaultOptions
 	WarehouseService warehouseservice.IWarehouseSvc
+	CategoryService  categoryservice.CategoryService
+	Storage          storage.Storage
 }
 
 func NewProductModule(opts Options) *ProductModule {
 
 	productRepo := repository.NewProductRepository(opts.Db)
+	variantRepo := repository.NewProductVariantRepository(opts.Db)
+	imageRepo := repository.NewProductImageRepository(opts.Db)
+	priceRepo := repository.NewProductPriceRepository(opts.Db)
 
-	productService := service.NewProductService(opts.Config, opts.WarehouseService, productRepo)
+	productService := service.NewProductService(opts.Config, opts.Db, opts.WarehouseService, opts.CategoryService, productRepo, variantRepo, imageRepo, priceRepo, opts.Storage)
 
 	registry.RegisterRouter(handler.NewHandler(opts.Router, opts.Config, opts.Logger, productService))
 
 	return &ProductModule{
-		productService: productService,
+		ProductService: productService,
 	}
 }
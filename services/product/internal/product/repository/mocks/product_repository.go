@@ -11,7 +11,11 @@ import (
 
 	model "github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
 
+	payload "github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+
 	repository "github.com/alifmufthi91/ecommerce-system/services/product/internal/product/repository"
+
+	uuid "github.com/google/uuid"
 )
 
 // ProductRepository is an autogenerated mock type for the ProductRepository type
@@ -19,6 +23,24 @@ type ProductRepository struct {
 	mock.Mock
 }
 
+// CreateBundleItems provides a mock function with given fields: ctx, items
+func (_m *ProductRepository) CreateBundleItems(ctx context.Context, items []model.ProductBundleItem) error {
+	ret := _m.Called(ctx, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBundleItems")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []model.ProductBundleItem) error); ok {
+		r0 = rf(ctx, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateProduct provides a mock function with given fields: ctx, product
 func (_m *ProductRepository) CreateProduct(ctx context.Context, product *model.Product) error {
 	ret := _m.Called(ctx, product)
@@ -37,6 +59,42 @@ func (_m *ProductRepository) CreateProduct(ctx context.Context, product *model.P
 	return r0
 }
 
+// DeleteProduct provides a mock function with given fields: ctx, productID
+func (_m *ProductRepository) DeleteProduct(ctx context.Context, productID string) error {
+	ret := _m.Called(ctx, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProduct")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportProducts provides a mock function with given fields: ctx, shopID, batchSize, fn
+func (_m *ProductRepository) ExportProducts(ctx context.Context, shopID string, batchSize int, fn func([]model.Product) error) error {
+	ret := _m.Called(ctx, shopID, batchSize, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportProducts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, func([]model.Product) error) error); ok {
+		r0 = rf(ctx, shopID, batchSize, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetProductByID provides a mock function with given fields: ctx, productID
 func (_m *ProductRepository) GetProductByID(ctx context.Context, productID string) (model.Product, error) {
 	ret := _m.Called(ctx, productID)
@@ -65,29 +123,124 @@ func (_m *ProductRepository) GetProductByID(ctx context.Context, productID strin
 	return r0, r1
 }
 
-// GetProducts provides a mock function with given fields: ctx
-func (_m *ProductRepository) GetProducts(ctx context.Context) ([]model.Product, error) {
-	ret := _m.Called(ctx)
+// GetProducts provides a mock function with given fields: ctx, req
+func (_m *ProductRepository) GetProducts(ctx context.Context, req payload.GetProductsReq) ([]model.Product, int64, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetProducts")
 	}
 
+	var r0 []model.Product
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetProductsReq) ([]model.Product, int64, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetProductsReq) []model.Product); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetProductsReq) int64); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, payload.GetProductsReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetProductsByIDs provides a mock function with given fields: ctx, productIDs
+func (_m *ProductRepository) GetProductsByIDs(ctx context.Context, productIDs []string) ([]model.Product, error) {
+	ret := _m.Called(ctx, productIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProductsByIDs")
+	}
+
 	var r0 []model.Product
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]model.Product, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]model.Product, error)); ok {
+		return rf(ctx, productIDs)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []model.Product); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []model.Product); ok {
+		r0 = rf(ctx, productIDs)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Product)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, productIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProductsBySKUs provides a mock function with given fields: ctx, shopID, skus
+func (_m *ProductRepository) GetProductsBySKUs(ctx context.Context, shopID string, skus []string) ([]model.Product, error) {
+	ret := _m.Called(ctx, shopID, skus)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProductsBySKUs")
+	}
+
+	var r0 []model.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) ([]model.Product, error)); ok {
+		return rf(ctx, shopID, skus)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) []model.Product); ok {
+		r0 = rf(ctx, shopID, skus)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, shopID, skus)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSearchFacets provides a mock function with given fields: ctx, req
+func (_m *ProductRepository) GetSearchFacets(ctx context.Context, req payload.SearchProductsReq) (payload.SearchProductsFacets, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSearchFacets")
+	}
+
+	var r0 payload.SearchProductsFacets
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.SearchProductsReq) (payload.SearchProductsFacets, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.SearchProductsReq) payload.SearchProductsFacets); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(payload.SearchProductsFacets)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.SearchProductsReq) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -95,6 +248,107 @@ func (_m *ProductRepository) GetProducts(ctx context.Context) ([]model.Product,
 	return r0, r1
 }
 
+// IsBundleComponent provides a mock function with given fields: ctx, productID
+func (_m *ProductRepository) IsBundleComponent(ctx context.Context, productID string) (bool, error) {
+	ret := _m.Called(ctx, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsBundleComponent")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, productID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceProductTags provides a mock function with given fields: ctx, productID, tags
+func (_m *ProductRepository) ReplaceProductTags(ctx context.Context, productID uuid.UUID, tags []string) error {
+	ret := _m.Called(ctx, productID, tags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceProductTags")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []string) error); ok {
+		r0 = rf(ctx, productID, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchProducts provides a mock function with given fields: ctx, req
+func (_m *ProductRepository) SearchProducts(ctx context.Context, req payload.SearchProductsReq) ([]model.Product, int64, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchProducts")
+	}
+
+	var r0 []model.Product
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.SearchProductsReq) ([]model.Product, int64, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.SearchProductsReq) []model.Product); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.SearchProductsReq) int64); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, payload.SearchProductsReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UpdateProduct provides a mock function with given fields: ctx, product
+func (_m *ProductRepository) UpdateProduct(ctx context.Context, product *model.Product) error {
+	ret := _m.Called(ctx, product)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProduct")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Product) error); ok {
+		r0 = rf(ctx, product)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // WithReturning provides a mock function with no fields
 func (_m *ProductRepository) WithReturning() repository.ProductRepository {
 	ret := _m.Called()
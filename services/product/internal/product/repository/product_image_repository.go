@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=ProductImageRepository --case underscore
+type ProductImageRepository interface {
+	WithTX(tx *gorm.DB) ProductImageRepository
+	CreateImage(ctx context.Context, image *model.ProductImage) error
+	UpdateImagePosition(ctx context.Context, imageID string, position int) error
+	DeleteImage(ctx context.Context, imageID string) error
+}
+
+type productImageRepository struct {
+	db *gorm.DB
+}
+
+func NewProductImageRepository(db *gorm.DB) ProductImageRepository {
+	return &productImageRepository{db: db}
+}
+
+func (r *productImageRepository) WithTX(tx *gorm.DB) ProductImageRepository {
+	if tx == nil {
+		return r
+	}
+	return &productImageRepository{db: tx}
+}
+
+func (r *productImageRepository) CreateImage(ctx context.Context, image *model.ProductImage) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productImageRepository.CreateImage")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(image).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLCreate, "failed to create product image")
+	}
+	return nil
+}
+
+func (r *productImageRepository) UpdateImagePosition(ctx context.Context, imageID string, position int) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productImageRepository.UpdateImagePosition")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Model(&model.ProductImage{}).Where("id = ?", imageID).Update("position", position).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLUpdate, "failed to update product image position")
+	}
+	return nil
+}
+
+func (r *productImageRepository) DeleteImage(ctx context.Context, imageID string) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productImageRepository.DeleteImage")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Where("id = ?", imageID).Delete(&model.ProductImage{}).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLDelete, "failed to delete product image")
+	}
+	return nil
+}
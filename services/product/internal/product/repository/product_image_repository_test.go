@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateImage(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	image := model.ProductImage{
+		ID:           uuid.New(),
+		ProductID:    uuid.New(),
+		Position:     2,
+		ContentType:  "image/png",
+		Size:         1024,
+		Width:        640,
+		Height:       480,
+		Key:          "products/p1/i1.png",
+		ThumbnailKey: "products/p1/i1_thumb.png",
+	}
+
+	mockDb.Mock.ExpectQuery(
+		regexp.QuoteMeta(`INSERT INTO "product_images" ("product_id","position","content_type","size","width","height","key","thumbnail_key","created_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`),
+	).WithArgs(image.ProductID, 2, "image/png", int64(1024), 640, 480, image.Key, image.ThumbnailKey, sqlmock.AnyArg(), image.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(image.ID))
+
+	repo := NewProductImageRepository(mockDb.Db)
+
+	err = repo.CreateImage(context.Background(), &image)
+
+	assert.Nil(t, err)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
+
+func TestUpdateImagePosition(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	imageID := uuid.New().String()
+
+	mockDb.Mock.ExpectExec(
+		regexp.QuoteMeta(`UPDATE "product_images" SET "position"=$1 WHERE id = $2`),
+	).WithArgs(1, imageID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewProductImageRepository(mockDb.Db)
+
+	err = repo.UpdateImagePosition(context.Background(), imageID, 1)
+
+	assert.Nil(t, err)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
+
+func TestDeleteImage(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	imageID := uuid.New().String()
+
+	mockDb.Mock.ExpectExec(
+		regexp.QuoteMeta(`DELETE FROM "product_images" WHERE id = $1`),
+	).WithArgs(imageID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewProductImageRepository(mockDb.Db)
+
+	err = repo.DeleteImage(context.Background(), imageID)
+
+	assert.Nil(t, err)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
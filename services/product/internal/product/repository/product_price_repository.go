@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=ProductPriceRepository --case underscore
+type ProductPriceRepository interface {
+	WithTX(tx *gorm.DB) ProductPriceRepository
+	CreatePrice(ctx context.Context, price *model.ProductPrice) error
+	GetPrices(ctx context.Context, productID string) ([]model.ProductPrice, error)
+	GetPriceAt(ctx context.Context, productID string, at time.Time) (model.ProductPrice, error)
+	DeleteScheduledPrice(ctx context.Context, productID, priceID string, now time.Time) error
+	ApplyScheduledPrices(ctx context.Context, now time.Time) error
+	CreateSale(ctx context.Context, sale *model.ProductSale) error
+	GetSales(ctx context.Context, productID string) ([]model.ProductSale, error)
+	GetSaleByID(ctx context.Context, productID, saleID string) (model.ProductSale, error)
+	GetSaleAt(ctx context.Context, productID string, at time.Time) (model.ProductSale, error)
+	UpdateSaleEndsAt(ctx context.Context, saleID string, endsAt time.Time) error
+	DeleteSale(ctx context.Context, saleID string) error
+}
+
+type productPriceRepository struct {
+	db *gorm.DB
+}
+
+func NewProductPriceRepository(db *gorm.DB) ProductPriceRepository {
+	return &productPriceRepository{db: db}
+}
+
+func (r *productPriceRepository) WithTX(tx *gorm.DB) ProductPriceRepository {
+	if tx == nil {
+		return r
+	}
+	return &productPriceRepository{db: tx}
+}
+
+func (r *productPriceRepository) CreatePrice(ctx context.Context, price *model.ProductPrice) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productPriceRepository.CreatePrice")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(price).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLCreate, "failed to create product price")
+	}
+	return nil
+}
+
+// GetPrices returns the price history and scheduled prices of the product,
+// oldest first.
+func (r *productPriceRepository) GetPrices(ctx context.Context, productID string) ([]model.ProductPrice, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productPriceRepository.GetPrices")
+	defer span.End()
+
+	var prices []model.ProductPrice
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("effective_from, created_at").Find(&prices).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get product prices")
+	}
+	return prices, nil
+}
+
+// GetPriceAt returns the regular price of the product in effect at the given
+// time. Of prices starting at the same time the last one created wins.
+func (r *productPriceRepository) GetPriceAt(ctx context.Context, productID string, at time.Time) (model.ProductPrice, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productPriceRepository.GetPriceAt")
+	defer span.End()
+
+	var price model.ProductPrice
+	err := r.db.WithContext(ctx).
+		Where("product_id = ? AND effective_from <= ?", productID, at).
+		Order("effective_from DESC, created_at DESC").
+		Take(&price).Error
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if err == gorm.ErrRecordNotFound {
+			return model.ProductPrice{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product price not found")
+		}
+		return model.ProductPrice{}, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get product price")
+	}
+	return price, nil
+}
+
+// DeleteScheduledPrice cancels a price change that is not in effect yet.
+func (r *productPriceRepository) DeleteScheduledPrice(ctx context.Context, productID, priceID string, now time.Time) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productPriceRepository.DeleteScheduledPrice")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND product_id = ? AND effective_from > ?", priceID, productID, now).
+		Delete(&model.ProductPrice{})
+	if err := result.Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLDelete, "failed to delete scheduled product price")
+	}
+	if result.RowsAffected == 0 {
+		return apperr.NewWithCode(apperr.CodeHTTPNotFound, "scheduled product price not found")
+	}
+	return nil
+}
+
+// ApplyScheduledPrices sets the price of every product to the one in effect
+// at now.
+func (r *productPriceRepository) ApplyScheduledPrices(ctx context.Context, now time.Time) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productPriceRepository.ApplyScheduledPrices")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Exec(
+		`UPDATE products SET price = p.price, updated_at = ? FROM (SELECT DISTINCT ON (product_id) product_id, price FROM product_prices WHERE effective_from <= ? ORDER BY product_id, effective_from DESC, created_at DESC) AS p WHERE products.id = p.product_id AND products.price <> p.price AND products.deleted_at IS NULL`,
+		now, now,
+	).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLUpdate, "failed to apply scheduled product prices")
+	}
+	return nil
+}
+
+func (r *productPriceRepository) CreateSale(ctx context.Context, sale *model.ProductSale) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productPriceRepository.CreateSale")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(sale).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLCreate, "failed to create product sale")
+	}
+	return nil
+}
+
+// GetSales returns the past, running and upcoming sales of the product by
+// start time.
+func (r *productPriceRepository) GetSales(ctx context.Context, productID string) ([]model.ProductSale, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productPriceRepository.GetSales")
+	defer span.End()
+
+	var sales []model.ProductSale
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("starts_at").Find(&sales).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get product sales")
+	}
+	return sales, nil
+}
+
+func (r *productPriceRepository) GetSaleByID(ctx context.Context, productID, saleID string) (model.ProductSale, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productPriceRepository.GetSaleByID")
+	defer span.End()
+
+	var sale model.ProductSale
+	if err := r.db.WithContext(ctx).Where("id = ? AND product_id = ?", saleID, productID).Take(&sale).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if err == gorm.ErrRecordNotFound {
+			return model.ProductSale{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product sale not found")
+		}
+		return model.ProductSale{}, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get product sale")
+	}
+	return sale, nil
+}
+
+// GetSaleAt returns the sale of the product running at the given time.
+func (r *productPriceRepository) GetSaleAt(ctx context.Context, productID string, at time.Time) (model.ProductSale, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productPriceRepository.GetSaleAt")
+	defer span.End()
+
+	var sale model.ProductSale
+	err := r.db.WithContext(ctx).
+		Where("product_id = ? AND starts_at <= ? AND ends_at > ?", productID, at, at).
+		Take(&sale).Error
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if err == gorm.ErrRecordNotFound {
+			return model.ProductSale{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product sale not found")
+		}
+		return model.ProductSale{}, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get product sale")
+	}
+	return sale, nil
+}
+
+func (r *productPriceRepository) UpdateSaleEndsAt(ctx context.Context, saleID string, endsAt time.Time) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productPriceRepository.UpdateSaleEndsAt")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Model(&model.ProductSale{}).Where("id = ?", saleID).Update("ends_at", endsAt).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLUpdate, "failed to update product sale")
+	}
+	return nil
+}
+
+func (r *productPriceRepository) DeleteSale(ctx context.Context, saleID string) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productPriceRepository.DeleteSale")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Where("id = ?", saleID).Delete(&model.ProductSale{}).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLDelete, "failed to delete product sale")
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePrice(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	price := model.ProductPrice{
+		ID:            uuid.New(),
+		ProductID:     uuid.New(),
+		Price:         12.5,
+		EffectiveFrom: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	mockDb.Mock.ExpectQuery(
+		regexp.QuoteMeta(`INSERT INTO "product_prices" ("product_id","price","effective_from","created_at","id") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`),
+	).WithArgs(price.ProductID, 12.5, price.EffectiveFrom, sqlmock.AnyArg(), price.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(price.ID))
+
+	repo := NewProductPriceRepository(mockDb.Db)
+
+	err = repo.CreatePrice(context.Background(), &price)
+
+	assert.Nil(t, err)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
+
+func TestGetPriceAt(t *testing.T) {
+	productID := uuid.New()
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(`SELECT * FROM "product_prices" WHERE product_id = $1 AND effective_from <= $2 ORDER BY effective_from DESC, created_at DESC LIMIT $3`)
+
+	scenarios := []struct {
+		name     string
+		mock     func(mockDb pkg.Database)
+		want     float64
+		wantCode apperr.Code
+	}{
+		{
+			name: "success - price in effect",
+			mock: func(mockDb pkg.Database) {
+				mockDb.Mock.ExpectQuery(query).
+					WithArgs(productID.String(), at, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "price"}).AddRow(uuid.New(), productID, 9.5))
+			},
+			want: 9.5,
+		},
+		{
+			name: "error - no price yet",
+			mock: func(mockDb pkg.Database) {
+				mockDb.Mock.ExpectQuery(query).
+					WithArgs(productID.String(), at, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			wantCode: apperr.CodeHTTPNotFound,
+		},
+		{
+			name: "error - database error",
+			mock: func(mockDb pkg.Database) {
+				mockDb.Mock.ExpectQuery(query).
+					WithArgs(productID.String(), at, 1).
+					WillReturnError(errors.New("db error"))
+			},
+			wantCode: apperr.CodeSQLRead,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			mockDb, err := pkg.SetupMockDB()
+			if err != nil {
+				t.Errorf("Failed to open mock sql db, got error: %v", err)
+			}
+			s.mock(*mockDb)
+
+			repo := NewProductPriceRepository(mockDb.Db)
+
+			got, err := repo.GetPriceAt(context.Background(), productID.String(), at)
+
+			if s.wantCode != 0 {
+				assert.Equal(t, s.wantCode, apperr.ErrCode(err))
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, s.want, got.Price)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteScheduledPrice(t *testing.T) {
+	productID := uuid.New().String()
+	priceID := uuid.New().String()
+	now := time.Now()
+	query := regexp.QuoteMeta(`DELETE FROM "product_prices" WHERE id = $1 AND product_id = $2 AND effective_from > $3`)
+
+	scenarios := []struct {
+		name     string
+		rows     int64
+		wantCode apperr.Code
+	}{
+		{
+			name: "success - scheduled price deleted",
+			rows: 1,
+		},
+		{
+			name:     "error - price not scheduled",
+			rows:     0,
+			wantCode: apperr.CodeHTTPNotFound,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			mockDb, err := pkg.SetupMockDB()
+			if err != nil {
+				t.Errorf("Failed to open mock sql db, got error: %v", err)
+			}
+			mockDb.Mock.ExpectExec(query).
+				WithArgs(priceID, productID, now).
+				WillReturnResult(sqlmock.NewResult(0, s.rows))
+
+			repo := NewProductPriceRepository(mockDb.Db)
+
+			err = repo.DeleteScheduledPrice(context.Background(), productID, priceID, now)
+
+			if s.wantCode != 0 {
+				assert.Equal(t, s.wantCode, apperr.ErrCode(err))
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestApplyScheduledPrices(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	now := time.Now()
+
+	mockDb.Mock.ExpectExec(
+		regexp.QuoteMeta(`UPDATE products SET price = p.price, updated_at = $1 FROM (SELECT DISTINCT ON (product_id) product_id, price FROM product_prices WHERE effective_from <= $2 ORDER BY product_id, effective_from DESC, created_at DESC) AS p WHERE products.id = p.product_id AND products.price <> p.price AND products.deleted_at IS NULL`),
+	).WithArgs(now, now).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := NewProductPriceRepository(mockDb.Db)
+
+	err = repo.ApplyScheduledPrices(context.Background(), now)
+
+	assert.Nil(t, err)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
+
+func TestGetSaleAt(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	at := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mockDb.Mock.ExpectQuery(
+		regexp.QuoteMeta(`SELECT * FROM "product_sales" WHERE product_id = $1 AND starts_at <= $2 AND ends_at > $3 LIMIT $4`),
+	).WithArgs(productID.String(), at, at, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "price"}).AddRow(uuid.New(), productID, 7.0))
+
+	repo := NewProductPriceRepository(mockDb.Db)
+
+	got, err := repo.GetSaleAt(context.Background(), productID.String(), at)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 7.0, got.Price)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
+
+func TestUpdateSaleEndsAt(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	saleID := uuid.New().String()
+	endsAt := time.Now()
+
+	mockDb.Mock.ExpectExec(
+		regexp.QuoteMeta(`UPDATE "product_sales" SET "ends_at"=$1 WHERE id = $2`),
+	).WithArgs(endsAt, saleID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewProductPriceRepository(mockDb.Db)
+
+	err = repo.UpdateSaleEndsAt(context.Background(), saleID, endsAt)
+
+	assert.Nil(t, err)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
@@ -2,10 +2,17 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"regexp"
+	"strings"
 
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/constant"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.opentelemetry.io/otel/codes"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -16,10 +23,27 @@ type ProductRepository interface {
 	WithTX(tx *gorm.DB) ProductRepository
 	WithReturning() ProductRepository
 	CreateProduct(ctx context.Context, product *model.Product) error
-	GetProducts(ctx context.Context) ([]model.Product, error)
+	GetProducts(ctx context.Context, req payload.GetProductsReq) ([]model.Product, int64, error)
 	GetProductByID(ctx context.Context, productID string) (model.Product, error)
+	GetProductsByIDs(ctx context.Context, productIDs []string) ([]model.Product, error)
+	CreateBundleItems(ctx context.Context, items []model.ProductBundleItem) error
+	UpdateProduct(ctx context.Context, product *model.Product) error
+	DeleteProduct(ctx context.Context, productID string) error
+	ReplaceProductTags(ctx context.Context, productID uuid.UUID, tags []string) error
+	SearchProducts(ctx context.Context, req payload.SearchProductsReq) ([]model.Product, int64, error)
+	GetSearchFacets(ctx context.Context, req payload.SearchProductsReq) (payload.SearchProductsFacets, error)
+	IsBundleComponent(ctx context.Context, productID string) (bool, error)
+	GetProductsBySKUs(ctx context.Context, shopID string, skus []string) ([]model.Product, error)
+	ExportProducts(ctx context.Context, shopID string, batchSize int, fn func(products []model.Product) error) error
 }
 
+// search facets, a facet is counted without its own filter
+const (
+	facetShop     = "shop"
+	facetCategory = "category"
+	facetPrice    = "price"
+)
+
 type productRepository struct {
 	db *gorm.DB
 }
@@ -47,29 +71,60 @@ func (r *productRepository) CreateProduct(ctx context.Context, product *model.Pr
 
 	if err := r.db.WithContext(ctx).Create(&product).Error; err != nil {
 		span.SetStatus(codes.Error, err.Error())
+		if isUniqueViolation(err) {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "sku already exists")
+		}
 		return apperr.WrapWithCode(err, apperr.CodeSQLCreate, "failed to create product")
 	}
 	return nil
 }
 
-func (r *productRepository) GetProducts(ctx context.Context) ([]model.Product, error) {
+// GetProducts returns a page of the listed products in the requested order,
+// and the number of all listed products.
+func (r *productRepository) GetProducts(ctx context.Context, req payload.GetProductsReq) ([]model.Product, int64, error) {
 	ctx, span := observ.GetTracer().Start(ctx, "productRepository.GetProducts")
 	defer span.End()
 
+	stmt := r.db.WithContext(ctx).Model(&model.Product{}).Where("archived_at IS NULL")
+	if len(req.CategoryIDIN) > 0 {
+		stmt = stmt.Where("category_id IN ?", req.CategoryIDIN)
+	}
+	if req.Tag != "" {
+		stmt = stmt.Where("id IN (?)", r.db.Model(&model.ProductTag{}).Select("product_id").Where("tag = ?", req.Tag))
+	}
+	if !req.IncludeOutOfStock {
+		stmt = stmt.Where(stockedCond)
+	}
+
+	var total int64
+	if err := stmt.Count(&total).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, 0, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to count products")
+	}
+
+	if req.PageSize > 0 {
+		stmt = stmt.Limit(req.PageSize).Offset((req.Page - 1) * req.PageSize)
+	}
+
+	sortBy, sortOrder := req.Sorting()
+	stmt = stmt.Order(clause.OrderByColumn{Column: clause.Column{Name: sortBy}, Desc: sortOrder == "desc"}).Order("id")
+
 	var products []model.Product
-	if err := r.db.WithContext(ctx).Find(&products).Error; err != nil {
+	if err := stmt.Scopes(withDetails).Find(&products).Error; err != nil {
 		span.SetStatus(codes.Error, err.Error())
-		return nil, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get products")
+		return nil, 0, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get products")
 	}
-	return products, nil
+	return products, total, nil
 }
 
+// GetProductByID also finds deleted products, so orders placed before the
+// product was deleted still resolve it.
 func (r *productRepository) GetProductByID(ctx context.Context, productID string) (model.Product, error) {
 	ctx, span := observ.GetTracer().Start(ctx, "productRepository.GetProductByID")
 	defer span.End()
 
 	var product model.Product
-	if err := r.db.WithContext(ctx).Where("id = ?", productID).First(&product).Error; err != nil {
+	if err := r.db.WithContext(ctx).Unscoped().Scopes(withDetails).Where("id = ?", productID).First(&product).Error; err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		if err == gorm.ErrRecordNotFound {
 			return model.Product{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product not found")
@@ -78,3 +133,314 @@ func (r *productRepository) GetProductByID(ctx context.Context, productID string
 	}
 	return product, nil
 }
+
+func (r *productRepository) GetProductsByIDs(ctx context.Context, productIDs []string) ([]model.Product, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productRepository.GetProductsByIDs")
+	defer span.End()
+
+	var products []model.Product
+	if err := r.db.WithContext(ctx).Scopes(withDetails).Where("id IN ?", productIDs).Find(&products).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get products by IDs")
+	}
+	return products, nil
+}
+
+func (r *productRepository) CreateBundleItems(ctx context.Context, items []model.ProductBundleItem) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productRepository.CreateBundleItems")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(&items).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLCreate, "failed to create bundle items")
+	}
+	return nil
+}
+
+func (r *productRepository) UpdateProduct(ctx context.Context, product *model.Product) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productRepository.UpdateProduct")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Model(product).
+		Select("category_id", "sku", "name", "description", "price", "archived_at", "updated_at").
+		Updates(product).Error
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if isUniqueViolation(err) {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "sku already exists")
+		}
+		return apperr.WrapWithCode(err, apperr.CodeSQLUpdate, "failed to update product")
+	}
+	return nil
+}
+
+func (r *productRepository) DeleteProduct(ctx context.Context, productID string) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productRepository.DeleteProduct")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Where("id = ?", productID).Delete(&model.Product{})
+	if err := result.Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLDelete, "failed to delete product")
+	}
+	if result.RowsAffected == 0 {
+		return apperr.NewWithCode(apperr.CodeHTTPNotFound, "product not found")
+	}
+	return nil
+}
+
+// ReplaceProductTags sets the tags of the product to exactly the given ones.
+func (r *productRepository) ReplaceProductTags(ctx context.Context, productID uuid.UUID, tags []string) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productRepository.ReplaceProductTags")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Delete(&model.ProductTag{}).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLDelete, "failed to delete product tags")
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	productTags := make([]model.ProductTag, 0, len(tags))
+	for _, tag := range tags {
+		productTags = append(productTags, model.ProductTag{ProductID: productID, Tag: tag})
+	}
+	if err := r.db.WithContext(ctx).Create(&productTags).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLCreate, "failed to create product tags")
+	}
+	return nil
+}
+
+// SearchProducts returns a page of the matching products, best match first,
+// and the number of all matching products.
+func (r *productRepository) SearchProducts(ctx context.Context, req payload.SearchProductsReq) ([]model.Product, int64, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productRepository.SearchProducts")
+	defer span.End()
+
+	stmt := r.searchStmt(ctx, req, "")
+
+	var total int64
+	if err := stmt.Count(&total).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, 0, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to count products")
+	}
+
+	if req.PageSize > 0 {
+		stmt = stmt.Limit(req.PageSize).Offset((req.Page - 1) * req.PageSize)
+	}
+
+	if tsQuery := toPrefixTSQuery(req.Query); tsQuery != "" {
+		stmt = stmt.Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "ts_rank(search_vector, to_tsquery('simple', ?)) + word_similarity(?, name) DESC, name, id",
+			Vars:               []any{tsQuery, req.Query},
+			WithoutParentheses: true,
+		}})
+	} else {
+		stmt = stmt.Order("name, id")
+	}
+
+	var products []model.Product
+	if err := stmt.Scopes(withDetails).Find(&products).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, 0, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to search products")
+	}
+	return products, total, nil
+}
+
+func (r *productRepository) GetSearchFacets(ctx context.Context, req payload.SearchProductsReq) (payload.SearchProductsFacets, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productRepository.GetSearchFacets")
+	defer span.End()
+
+	facets := payload.SearchProductsFacets{
+		Shops:       []payload.FacetCount{},
+		Categories:  []payload.FacetCount{},
+		PriceRanges: []payload.PriceRangeCount{},
+	}
+
+	err := r.searchStmt(ctx, req, facetShop).
+		Select("shop_id::text AS value, count(*) AS count").
+		Group("shop_id").
+		Order("count DESC, value").
+		Scan(&facets.Shops).Error
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return payload.SearchProductsFacets{}, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to count products by shop")
+	}
+
+	err = r.searchStmt(ctx, req, facetCategory).
+		Select("category_id::text AS value, count(*) AS count").
+		Where("category_id IS NOT NULL").
+		Group("category_id").
+		Order("count DESC, value").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return payload.SearchProductsFacets{}, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to count products by category")
+	}
+
+	bounds := constant.SearchPriceRangeBounds
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(bounds)), ",")
+	vars := make([]any, 0, len(bounds))
+	for _, bound := range bounds {
+		vars = append(vars, bound)
+	}
+
+	var buckets []struct {
+		Bucket int
+		Count  int64
+	}
+	err = r.searchStmt(ctx, req, facetPrice).
+		Select("width_bucket(price::float8, ARRAY["+placeholders+"]::float8[]) AS bucket, count(*) AS count", vars...).
+		Group("bucket").
+		Scan(&buckets).Error
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return payload.SearchProductsFacets{}, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to count products by price range")
+	}
+
+	counts := make(map[int]int64, len(buckets))
+	for _, bucket := range buckets {
+		counts[bucket.Bucket] = bucket.Count
+	}
+	for i := 0; i <= len(bounds); i++ {
+		priceRange := payload.PriceRangeCount{Count: counts[i]}
+		if i > 0 {
+			priceRange.Min = bounds[i-1]
+		}
+		if i < len(bounds) {
+			priceRange.Max = &bounds[i]
+		}
+		facets.PriceRanges = append(facets.PriceRanges, priceRange)
+	}
+
+	return facets, nil
+}
+
+// IsBundleComponent reports whether the product is a component of a bundle.
+func (r *productRepository) IsBundleComponent(ctx context.Context, productID string) (bool, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productRepository.IsBundleComponent")
+	defer span.End()
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.ProductBundleItem{}).Where("component_id = ?", productID).Count(&count).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return false, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get bundle items")
+	}
+	return count > 0, nil
+}
+
+// GetProductsBySKUs returns the products of the shop with the given SKUs,
+// archived ones included.
+func (r *productRepository) GetProductsBySKUs(ctx context.Context, shopID string, skus []string) ([]model.Product, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productRepository.GetProductsBySKUs")
+	defer span.End()
+
+	var products []model.Product
+	if err := r.db.WithContext(ctx).Where("shop_id = ? AND sku IN ?", shopID, skus).Find(&products).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get products by SKUs")
+	}
+	return products, nil
+}
+
+// ExportProducts reads the listed products of the shop with their tags in
+// batches, handing every batch to fn before the next is read.
+func (r *productRepository) ExportProducts(ctx context.Context, shopID string, batchSize int, fn func(products []model.Product) error) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productRepository.ExportProducts")
+	defer span.End()
+
+	var products []model.Product
+	var fnErr error
+	err := r.db.WithContext(ctx).
+		Preload("Tags").
+		Where("shop_id = ? AND archived_at IS NULL", shopID).
+		FindInBatches(&products, batchSize, func(tx *gorm.DB, batch int) error {
+			fnErr = fn(products)
+			return fnErr
+		}).Error
+	if fnErr != nil {
+		span.SetStatus(codes.Error, fnErr.Error())
+		return fnErr
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to export products")
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether another product of the shop took the SKU
+// since it was checked, see idx_products_shop_id_sku.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// withDetails preloads the bundle items, tags and variants of the products.
+func withDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("BundleItems").
+		Preload("Tags").
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("sku") }).
+		Preload("Variants.Options").
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Order("position, created_at") })
+}
+
+// stockedCond keeps the products the warehouses keep stock of, available or
+// not. Bundles and products with variants are stocked by component and by
+// variant, so they are always kept.
+const stockedCond = `(EXISTS (SELECT 1 FROM product_bundle_items bi WHERE bi.bundle_id = products.id)
+	OR EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id)
+	OR EXISTS (SELECT 1 FROM product_available_stocks pas WHERE pas.product_id = products.id))`
+
+// inStockCond keeps the products with some available stock: a bundle when its
+// components make one up, a product with variants when one of them has some.
+const inStockCond = `CASE WHEN EXISTS (SELECT 1 FROM product_bundle_items bi WHERE bi.bundle_id = products.id)
+	THEN NOT EXISTS (SELECT 1 FROM product_bundle_items bi LEFT JOIN product_available_stocks pas ON pas.product_id = bi.component_id WHERE bi.bundle_id = products.id AND coalesce(pas.available_stock, 0) < bi.quantity)
+	ELSE EXISTS (SELECT 1 FROM product_available_stocks pas WHERE pas.available_stock > 0 AND (pas.product_id = products.id OR pas.product_id IN (SELECT pv.id FROM product_variants pv WHERE pv.product_id = products.id)))
+	END`
+
+// searchStmt selects the listed products matching the search, with every
+// filter applied but the one of the skipped facet.
+func (r *productRepository) searchStmt(ctx context.Context, req payload.SearchProductsReq, skip string) *gorm.DB {
+	stmt := r.db.WithContext(ctx).Model(&model.Product{}).Where("archived_at IS NULL")
+
+	if req.Query != "" {
+		if tsQuery := toPrefixTSQuery(req.Query); tsQuery != "" {
+			stmt = stmt.Where("search_vector @@ to_tsquery('simple', ?) OR ? <% name", tsQuery, req.Query)
+		} else {
+			stmt = stmt.Where("? <% name", req.Query)
+		}
+	}
+	if req.InStock {
+		stmt = stmt.Where(inStockCond)
+	}
+	if req.ShopID != "" && skip != facetShop {
+		stmt = stmt.Where("shop_id = ?", req.ShopID)
+	}
+	if len(req.CategoryIDIN) > 0 && skip != facetCategory {
+		stmt = stmt.Where("category_id IN ?", req.CategoryIDIN)
+	}
+	if skip != facetPrice {
+		if req.PriceMin > 0 {
+			stmt = stmt.Where("price >= ?", req.PriceMin)
+		}
+		if req.PriceMax > 0 {
+			stmt = stmt.Where("price <= ?", req.PriceMax)
+		}
+	}
+	return stmt
+}
+
+var tsQueryWord = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// toPrefixTSQuery turns the words of a search query into a tsquery matching
+// words starting with each of them.
+func toPrefixTSQuery(query string) string {
+	words := tsQueryWord.FindAllString(strings.ToLower(query), -1)
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
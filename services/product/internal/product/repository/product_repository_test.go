@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql/driver"
 	"regexp"
 	"testing"
 	"time"
@@ -9,7 +10,9 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
@@ -39,15 +42,19 @@ func TestCreateProduct(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, data model.Product) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`INSERT INTO "products" ("shop_id","name","description","price","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
+							`INSERT INTO "products" ("shop_id","category_id","name","description","price","created_at","updated_at","sku","archived_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`,
 						),
 					).WithArgs(
 						data.ShopID,
+						data.CategoryID,
 						data.Name,
 						data.Description,
 						data.Price,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
+						data.SKU,
+						nil,
+						nil,
 					).WillReturnRows(
 						sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()),
 					)
@@ -64,15 +71,19 @@ func TestCreateProduct(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, data model.Product) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`INSERT INTO "products" ("shop_id","name","description","price","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
+							`INSERT INTO "products" ("shop_id","category_id","name","description","price","created_at","updated_at","sku","archived_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`,
 						),
 					).WithArgs(
 						data.ShopID,
+						data.CategoryID,
 						data.Name,
 						data.Description,
 						data.Price,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
+						data.SKU,
+						nil,
+						nil,
 					).WillReturnError(
 						sqlmock.ErrCancelled,
 					)
@@ -80,6 +91,23 @@ func TestCreateProduct(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "error - sku taken",
+			data: model.Product{
+				Name: "Test Product",
+				SKU:  func() *string { sku := "TS-001"; return &sku }(),
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.Product) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`INSERT INTO "products"`),
+					).WillReturnError(
+						&pgconn.PgError{Code: "23505"},
+					)
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -136,12 +164,49 @@ func TestGetProducts(t *testing.T) {
 			sqlMock: sqlMock{
 				Setup: func(mockDB sqlmock.Sqlmock, data []model.Product) {
 					rows := sqlmock.NewRows([]string{"id", "name", "shop_id", "description", "price", "created_at", "updated_at"})
+					var ids []driver.Value
 					for _, product := range data {
-						rows.AddRow(uuid.New(), product.Name, product.ShopID, product.Description, product.Price, time.Now(), time.Now())
+						id := uuid.New()
+						ids = append(ids, id)
+						rows.AddRow(id, product.Name, product.ShopID, product.Description, product.Price, time.Now(), time.Now())
 					}
 					mockDB.ExpectQuery(
-						regexp.QuoteMeta(`SELECT * FROM "products"`),
-					).WillReturnRows(rows)
+						regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE archived_at IS NULL AND "products"."deleted_at" IS NULL`),
+					).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(data)))
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "products" WHERE archived_at IS NULL AND "products"."deleted_at" IS NULL ORDER BY "price" DESC,id LIMIT $1 OFFSET $2`),
+					).WithArgs(2, 2).WillReturnRows(rows)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "product_bundle_items" WHERE "product_bundle_items"."bundle_id" IN ($1,$2)`),
+					).WithArgs(ids...).WillReturnRows(
+						sqlmock.NewRows([]string{"bundle_id", "component_id", "quantity"}).
+							AddRow(ids[1], ids[0], 3),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "product_images" WHERE "product_images"."product_id" IN ($1,$2) ORDER BY position, created_at`),
+					).WithArgs(ids...).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "product_id", "position", "key"}).
+							AddRow(uuid.New(), ids[0], 0, "products/image.jpg"),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "product_tags" WHERE "product_tags"."product_id" IN ($1,$2)`),
+					).WithArgs(ids...).WillReturnRows(
+						sqlmock.NewRows([]string{"product_id", "tag"}).
+							AddRow(ids[0], "summer"),
+					)
+					variantID := uuid.New()
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE "product_variants"."product_id" IN ($1,$2) ORDER BY sku`),
+					).WithArgs(ids...).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "product_id", "sku"}).
+							AddRow(variantID, ids[0], "TP1-RED"),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "product_variant_options" WHERE "product_variant_options"."variant_id" = $1`),
+					).WithArgs(variantID).WillReturnRows(
+						sqlmock.NewRows([]string{"variant_id", "name", "value"}).
+							AddRow(variantID, "colour", "red"),
+					)
 				},
 			},
 			wantErr: false,
@@ -152,7 +217,7 @@ func TestGetProducts(t *testing.T) {
 			sqlMock: sqlMock{
 				Setup: func(mockDB sqlmock.Sqlmock, data []model.Product) {
 					mockDB.ExpectQuery(
-						regexp.QuoteMeta(`SELECT * FROM "products"`),
+						regexp.QuoteMeta(`SELECT count(*) FROM "products"`),
 					).WillReturnError(sqlmock.ErrCancelled)
 				},
 			},
@@ -167,7 +232,13 @@ func TestGetProducts(t *testing.T) {
 
 			repo := NewProductRepository(mockDb.Db)
 
-			result, err := repo.GetProducts(context.Background())
+			result, total, err := repo.GetProducts(context.Background(), payload.GetProductsReq{
+				IncludeOutOfStock: true,
+				SortBy:            "price",
+				SortOrder:         "desc",
+				Page:              2,
+				PageSize:          2,
+			})
 
 			if tt.wantErr {
 				assert.NotNil(t, err)
@@ -175,6 +246,7 @@ func TestGetProducts(t *testing.T) {
 			}
 
 			assert.Nil(t, err)
+			assert.Equal(t, int64(len(tt.data)), total)
 			assert.Equal(t, len(tt.data), len(result))
 			for i, product := range result {
 				assert.Equal(t, tt.data[i].Name, product.Name)
@@ -216,6 +288,24 @@ func TestGetProductByID(t *testing.T) {
 						sqlmock.NewRows([]string{"id", "name", "shop_id", "description", "price", "created_at", "updated_at"}).
 							AddRow(data.ID, data.Name, data.ShopID, data.Description, data.Price, time.Now(), time.Now()),
 					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "product_bundle_items" WHERE "product_bundle_items"."bundle_id" = $1`),
+					).WithArgs(data.ID).WillReturnRows(
+						sqlmock.NewRows([]string{"bundle_id", "component_id", "quantity"}),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "product_images" WHERE "product_images"."product_id" = $1 ORDER BY position, created_at`),
+					).WithArgs(data.ID).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "position"}))
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "product_tags" WHERE "product_tags"."product_id" = $1`),
+					).WithArgs(data.ID).WillReturnRows(
+						sqlmock.NewRows([]string{"product_id", "tag"}),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE "product_variants"."product_id" = $1 ORDER BY sku`),
+					).WithArgs(data.ID).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "product_id", "sku"}),
+					)
 				},
 			},
 			wantErr: false,
@@ -255,3 +345,501 @@ func TestGetProductByID(t *testing.T) {
 		})
 	}
 }
+
+func TestGetProductsByIDs(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	bundleID := uuid.New()
+	componentID := uuid.New()
+
+	tests := []struct {
+		name    string
+		setup   func(mockDB sqlmock.Sqlmock)
+		wantLen int
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "products" WHERE id IN ($1,$2) AND "products"."deleted_at" IS NULL`),
+				).WithArgs(bundleID.String(), componentID.String()).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "name"}).
+						AddRow(bundleID, "Bundle").
+						AddRow(componentID, "Component"),
+				)
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_bundle_items" WHERE "product_bundle_items"."bundle_id" IN ($1,$2)`),
+				).WithArgs(bundleID, componentID).WillReturnRows(
+					sqlmock.NewRows([]string{"bundle_id", "component_id", "quantity"}).
+						AddRow(bundleID, componentID, 2),
+				)
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_images" WHERE "product_images"."product_id" IN ($1,$2) ORDER BY position, created_at`),
+				).WithArgs(bundleID, componentID).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "position"}))
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_tags" WHERE "product_tags"."product_id" IN ($1,$2)`),
+				).WithArgs(bundleID, componentID).WillReturnRows(
+					sqlmock.NewRows([]string{"product_id", "tag"}),
+				)
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE "product_variants"."product_id" IN ($1,$2) ORDER BY sku`),
+				).WithArgs(bundleID, componentID).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "product_id", "sku"}),
+				)
+			},
+			wantLen: 2,
+		},
+		{
+			name: "error - failed to get products",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "products" WHERE id IN ($1,$2) AND "products"."deleted_at" IS NULL`),
+				).WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(mockDb.Mock)
+
+			repo := NewProductRepository(mockDb.Db)
+
+			result, err := repo.GetProductsByIDs(context.Background(), []string{bundleID.String(), componentID.String()})
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, result, tt.wantLen)
+			assert.True(t, result[0].IsBundle())
+			assert.False(t, result[1].IsBundle())
+		})
+	}
+}
+
+func TestCreateBundleItems(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	items := []model.ProductBundleItem{
+		{BundleID: uuid.New(), ComponentID: uuid.New(), Quantity: 2},
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(mockDB sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`INSERT INTO "product_bundle_items" ("bundle_id","component_id","quantity","created_at") VALUES ($1,$2,$3,$4)`),
+				).WithArgs(items[0].BundleID, items[0].ComponentID, items[0].Quantity, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "error - failed to create bundle items",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`INSERT INTO "product_bundle_items"`),
+				).WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(mockDb.Mock)
+
+			repo := NewProductRepository(mockDb.Db)
+
+			err := repo.CreateBundleItems(context.Background(), items)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestUpdateProduct(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	now := time.Now()
+	sku := "TP-001"
+	product := model.Product{
+		ID:         uuid.New(),
+		ShopID:     uuid.New(),
+		SKU:        &sku,
+		Name:       "Test Product",
+		ArchivedAt: &now,
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(mockDB sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`UPDATE "products" SET "category_id"=$1,"name"=$2,"description"=$3,"price"=$4,"updated_at"=$5,"sku"=$6,"archived_at"=$7 WHERE "products"."deleted_at" IS NULL AND "id" = $8`),
+				).WithArgs(
+					product.CategoryID,
+					product.Name,
+					product.Description,
+					product.Price,
+					sqlmock.AnyArg(),
+					product.SKU,
+					product.ArchivedAt,
+					product.ID,
+				).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "error - failed to update product",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`UPDATE "products"`),
+				).WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(mockDb.Mock)
+
+			repo := NewProductRepository(mockDb.Db)
+
+			err := repo.UpdateProduct(context.Background(), &product)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestDeleteProduct(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New().String()
+
+	tests := []struct {
+		name    string
+		setup   func(mockDB sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1 WHERE id = $2 AND "products"."deleted_at" IS NULL`),
+				).WithArgs(sqlmock.AnyArg(), productID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "error - product not found",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1 WHERE id = $2 AND "products"."deleted_at" IS NULL`),
+				).WithArgs(sqlmock.AnyArg(), productID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - failed to delete product",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1`),
+				).WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(mockDb.Mock)
+
+			repo := NewProductRepository(mockDb.Db)
+
+			err := repo.DeleteProduct(context.Background(), productID)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestGetProducts_Filtered(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	categoryID := uuid.New().String()
+	subcategoryID := uuid.New().String()
+
+	mockDb.Mock.ExpectQuery(
+		regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE archived_at IS NULL AND category_id IN ($1,$2) AND id IN (SELECT "product_id" FROM "product_tags" WHERE tag = $3) AND (EXISTS (SELECT 1 FROM product_bundle_items bi WHERE bi.bundle_id = products.id) OR EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id) OR EXISTS (SELECT 1 FROM product_available_stocks pas WHERE pas.product_id = products.id)) AND "products"."deleted_at" IS NULL`),
+	).WithArgs(categoryID, subcategoryID, "summer").WillReturnRows(
+		sqlmock.NewRows([]string{"count"}).AddRow(0),
+	)
+	mockDb.Mock.ExpectQuery(
+		regexp.QuoteMeta(`SELECT * FROM "products" WHERE archived_at IS NULL AND category_id IN ($1,$2) AND id IN (SELECT "product_id" FROM "product_tags" WHERE tag = $3) AND (EXISTS (SELECT 1 FROM product_bundle_items bi WHERE bi.bundle_id = products.id) OR EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id) OR EXISTS (SELECT 1 FROM product_available_stocks pas WHERE pas.product_id = products.id)) AND "products"."deleted_at" IS NULL ORDER BY "name",id`),
+	).WithArgs(categoryID, subcategoryID, "summer").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "name"}),
+	)
+
+	repo := NewProductRepository(mockDb.Db)
+
+	result, total, err := repo.GetProducts(context.Background(), payload.GetProductsReq{
+		CategoryIDIN: []string{categoryID, subcategoryID},
+		Tag:          "summer",
+	})
+
+	assert.Nil(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, result)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
+
+func TestReplaceProductTags(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+
+	tests := []struct {
+		name    string
+		tags    []string
+		setup   func(mockDB sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "success",
+			tags: []string{"summer", "sale"},
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`DELETE FROM "product_tags" WHERE product_id = $1`),
+				).WithArgs(productID).WillReturnResult(sqlmock.NewResult(0, 1))
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`INSERT INTO "product_tags" ("product_id","tag") VALUES ($1,$2),($3,$4)`),
+				).WithArgs(productID, "summer", productID, "sale").WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name: "success - clear tags",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`DELETE FROM "product_tags" WHERE product_id = $1`),
+				).WithArgs(productID).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "error - failed to create product tags",
+			tags: []string{"summer"},
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`DELETE FROM "product_tags" WHERE product_id = $1`),
+				).WithArgs(productID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`INSERT INTO "product_tags"`),
+				).WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(mockDb.Mock)
+
+			repo := NewProductRepository(mockDb.Db)
+
+			err := repo.ReplaceProductTags(context.Background(), productID, tt.tags)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIsBundleComponent(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New().String()
+
+	mockDb.Mock.ExpectQuery(
+		regexp.QuoteMeta(`SELECT count(*) FROM "product_bundle_items" WHERE component_id = $1`),
+	).WithArgs(productID).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	repo := NewProductRepository(mockDb.Db)
+
+	isComponent, err := repo.IsBundleComponent(context.Background(), productID)
+
+	assert.Nil(t, err)
+	assert.True(t, isComponent)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
+
+func TestGetProductsBySKUs(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	shopID := uuid.New().String()
+
+	mockDb.Mock.ExpectQuery(
+		regexp.QuoteMeta(`SELECT * FROM "products" WHERE (shop_id = $1 AND sku IN ($2,$3)) AND "products"."deleted_at" IS NULL`),
+	).WithArgs(shopID, "TS-001", "TS-002").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "sku"}).AddRow(uuid.New(), "TS-001"),
+	)
+
+	repo := NewProductRepository(mockDb.Db)
+
+	products, err := repo.GetProductsBySKUs(context.Background(), shopID, []string{"TS-001", "TS-002"})
+
+	assert.Nil(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, "TS-001", *products[0].SKU)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
+
+func TestExportProducts(t *testing.T) {
+	shopID := uuid.New().String()
+	productID1 := uuid.New()
+	productID2 := uuid.New()
+	productID3 := uuid.New()
+
+	tests := []struct {
+		name      string
+		setup     func(mockDB sqlmock.Sqlmock)
+		fnErr     error
+		wantErr   bool
+		wantBatch []int
+	}{
+		{
+			name: "success - products in batches",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "products" WHERE (shop_id = $1 AND archived_at IS NULL) AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2`),
+				).WithArgs(shopID, 2).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "sku"}).AddRow(productID1, "TS-001").AddRow(productID2, "TS-002"),
+				)
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_tags" WHERE "product_tags"."product_id" IN ($1,$2)`),
+				).WillReturnRows(
+					sqlmock.NewRows([]string{"product_id", "tag"}).AddRow(productID1, "summer"),
+				)
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "products" WHERE (shop_id = $1 AND archived_at IS NULL) AND "products"."id" > $2 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $3`),
+				).WithArgs(shopID, productID2, 2).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "sku"}).AddRow(productID3, "TS-003"),
+				)
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_tags" WHERE "product_tags"."product_id" = $1`),
+				).WillReturnRows(sqlmock.NewRows([]string{"product_id", "tag"}))
+			},
+			wantBatch: []int{2, 1},
+		},
+		{
+			name: "error - failed to read products",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "products"`),
+				).WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - batch not written",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "products"`),
+				).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "sku"}).AddRow(productID1, "TS-001"),
+				)
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_tags"`),
+				).WillReturnRows(sqlmock.NewRows([]string{"product_id", "tag"}))
+			},
+			fnErr:     assert.AnError,
+			wantErr:   true,
+			wantBatch: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDb, err := pkg.SetupMockDB()
+			if err != nil {
+				t.Errorf("Failed to open mock sql db, got error: %v", err)
+			}
+			tt.setup(mockDb.Mock)
+
+			repo := NewProductRepository(mockDb.Db)
+
+			var batches []int
+			err = repo.ExportProducts(context.Background(), shopID, 2, func(products []model.Product) error {
+				batches = append(batches, len(products))
+				return tt.fnErr
+			})
+
+			assert.Equal(t, tt.wantBatch, batches)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
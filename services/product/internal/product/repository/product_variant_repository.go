@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=ProductVariantRepository --case underscore
+type ProductVariantRepository interface {
+	WithTX(tx *gorm.DB) ProductVariantRepository
+	CreateVariant(ctx context.Context, variant *model.ProductVariant) error
+	GetVariantsByProductID(ctx context.Context, productID string) ([]model.ProductVariant, error)
+	GetVariantBySKU(ctx context.Context, sku string) (model.ProductVariant, error)
+	UpdateVariant(ctx context.Context, variant *model.ProductVariant) error
+	DeleteVariant(ctx context.Context, productID, variantID string) error
+	ReplaceVariantOptions(ctx context.Context, variantID uuid.UUID, options []model.ProductVariantOption) error
+}
+
+type productVariantRepository struct {
+	db *gorm.DB
+}
+
+func NewProductVariantRepository(db *gorm.DB) ProductVariantRepository {
+	return &productVariantRepository{db: db}
+}
+
+func (r *productVariantRepository) WithTX(tx *gorm.DB) ProductVariantRepository {
+	if tx == nil {
+		return r
+	}
+	return &productVariantRepository{db: tx}
+}
+
+// CreateVariant creates the variant without its options, they are set by
+// ReplaceVariantOptions.
+func (r *productVariantRepository) CreateVariant(ctx context.Context, variant *model.ProductVariant) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productVariantRepository.CreateVariant")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Omit("Options").Create(variant).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLCreate, "failed to create product variant")
+	}
+	return nil
+}
+
+func (r *productVariantRepository) GetVariantsByProductID(ctx context.Context, productID string) ([]model.ProductVariant, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productVariantRepository.GetVariantsByProductID")
+	defer span.End()
+
+	var variants []model.ProductVariant
+	if err := r.db.WithContext(ctx).Preload("Options").Where("product_id = ?", productID).Order("sku").Find(&variants).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get product variants")
+	}
+	return variants, nil
+}
+
+func (r *productVariantRepository) GetVariantBySKU(ctx context.Context, sku string) (model.ProductVariant, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productVariantRepository.GetVariantBySKU")
+	defer span.End()
+
+	var variant model.ProductVariant
+	if err := r.db.WithContext(ctx).Where("sku = ?", sku).First(&variant).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if err == gorm.ErrRecordNotFound {
+			return model.ProductVariant{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product variant not found")
+		}
+		return model.ProductVariant{}, apperr.WrapWithCode(err, apperr.CodeSQLRead, "failed to get product variant by SKU")
+	}
+	return variant, nil
+}
+
+func (r *productVariantRepository) UpdateVariant(ctx context.Context, variant *model.ProductVariant) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productVariantRepository.UpdateVariant")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Model(variant).
+		Select("sku", "price", "updated_at").
+		Updates(variant).Error
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLUpdate, "failed to update product variant")
+	}
+	return nil
+}
+
+func (r *productVariantRepository) DeleteVariant(ctx context.Context, productID, variantID string) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productVariantRepository.DeleteVariant")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Where("id = ? AND product_id = ?", variantID, productID).Delete(&model.ProductVariant{})
+	if err := result.Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLDelete, "failed to delete product variant")
+	}
+	if result.RowsAffected == 0 {
+		return apperr.NewWithCode(apperr.CodeHTTPNotFound, "product variant not found")
+	}
+	return nil
+}
+
+// ReplaceVariantOptions sets the options of the variant to exactly the given
+// ones.
+func (r *productVariantRepository) ReplaceVariantOptions(ctx context.Context, variantID uuid.UUID, options []model.ProductVariantOption) error {
+	ctx, span := observ.GetTracer().Start(ctx, "productVariantRepository.ReplaceVariantOptions")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Where("variant_id = ?", variantID).Delete(&model.ProductVariantOption{}).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLDelete, "failed to delete product variant options")
+	}
+	if len(options) == 0 {
+		return nil
+	}
+
+	for i := range options {
+		options[i].VariantID = variantID
+	}
+	if err := r.db.WithContext(ctx).Create(&options).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeSQLCreate, "failed to create product variant options")
+	}
+	return nil
+}
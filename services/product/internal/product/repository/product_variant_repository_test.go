@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateVariant(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	price := 25.0
+
+	tests := []struct {
+		name    string
+		setup   func(mockDB sqlmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`INSERT INTO "product_variants" ("product_id","sku","price","created_at","updated_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`),
+				).WithArgs(productID, "SHIRT-M", &price, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnRows(
+					sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()),
+				)
+			},
+		},
+		{
+			name: "error - failed to create product variant",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`INSERT INTO "product_variants"`),
+				).WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(mockDb.Mock)
+
+			repo := NewProductVariantRepository(mockDb.Db)
+
+			variant := model.ProductVariant{
+				ProductID: productID,
+				SKU:       "SHIRT-M",
+				Price:     &price,
+				Options:   []model.ProductVariantOption{{Name: "size", Value: "M"}},
+			}
+			err := repo.CreateVariant(context.Background(), &variant)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.NotEqual(t, uuid.Nil, variant.ID)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetVariantsByProductID(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	variantID := uuid.New()
+
+	mockDb.Mock.ExpectQuery(
+		regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE product_id = $1 ORDER BY sku`),
+	).WithArgs(productID.String()).WillReturnRows(
+		sqlmock.NewRows([]string{"id", "product_id", "sku"}).AddRow(variantID, productID, "SHIRT-M"),
+	)
+	mockDb.Mock.ExpectQuery(
+		regexp.QuoteMeta(`SELECT * FROM "product_variant_options" WHERE "product_variant_options"."variant_id" = $1`),
+	).WithArgs(variantID).WillReturnRows(
+		sqlmock.NewRows([]string{"variant_id", "name", "value"}).AddRow(variantID, "size", "M"),
+	)
+
+	repo := NewProductVariantRepository(mockDb.Db)
+
+	result, err := repo.GetVariantsByProductID(context.Background(), productID.String())
+
+	assert.Nil(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, []model.ProductVariantOption{{VariantID: variantID, Name: "size", Value: "M"}}, result[0].Options)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
+
+func TestGetVariantBySKU(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	variantID := uuid.New()
+
+	tests := []struct {
+		name     string
+		setup    func(mockDB sqlmock.Sqlmock)
+		wantCode apperr.Code
+	}{
+		{
+			name: "success",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE sku = $1 ORDER BY "product_variants"."id" LIMIT $2`),
+				).WithArgs("SHIRT-M", 1).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "sku"}).AddRow(variantID, "SHIRT-M"),
+				)
+			},
+		},
+		{
+			name: "error - not found",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE sku = $1`),
+				).WillReturnRows(sqlmock.NewRows([]string{"id", "sku"}))
+			},
+			wantCode: apperr.CodeHTTPNotFound,
+		},
+		{
+			name: "error - failed to get product variant",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE sku = $1`),
+				).WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantCode: apperr.CodeSQLRead,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(mockDb.Mock)
+
+			repo := NewProductVariantRepository(mockDb.Db)
+
+			result, err := repo.GetVariantBySKU(context.Background(), "SHIRT-M")
+
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, variantID, result.ID)
+		})
+	}
+}
+
+func TestUpdateVariant(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	variant := model.ProductVariant{ID: uuid.New(), SKU: "SHIRT-M"}
+
+	mockDb.Mock.ExpectExec(
+		regexp.QuoteMeta(`UPDATE "product_variants" SET "sku"=$1,"price"=$2,"updated_at"=$3 WHERE "id" = $4`),
+	).WithArgs("SHIRT-M", nil, sqlmock.AnyArg(), variant.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewProductVariantRepository(mockDb.Db)
+
+	err = repo.UpdateVariant(context.Background(), &variant)
+
+	assert.Nil(t, err)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
+
+func TestDeleteVariant(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New().String()
+	variantID := uuid.New().String()
+
+	tests := []struct {
+		name     string
+		setup    func(mockDB sqlmock.Sqlmock)
+		wantCode apperr.Code
+	}{
+		{
+			name: "success",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`DELETE FROM "product_variants" WHERE id = $1 AND product_id = $2`),
+				).WithArgs(variantID, productID).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "error - not found",
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectExec(
+					regexp.QuoteMeta(`DELETE FROM "product_variants" WHERE id = $1 AND product_id = $2`),
+				).WithArgs(variantID, productID).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantCode: apperr.CodeHTTPNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(mockDb.Mock)
+
+			repo := NewProductVariantRepository(mockDb.Db)
+
+			err := repo.DeleteVariant(context.Background(), productID, variantID)
+
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestReplaceVariantOptions(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	variantID := uuid.New()
+
+	mockDb.Mock.ExpectExec(
+		regexp.QuoteMeta(`DELETE FROM "product_variant_options" WHERE variant_id = $1`),
+	).WithArgs(variantID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDb.Mock.ExpectExec(
+		regexp.QuoteMeta(`INSERT INTO "product_variant_options" ("variant_id","name","value") VALUES ($1,$2,$3),($4,$5,$6)`),
+	).WithArgs(variantID, "colour", "red", variantID, "size", "M").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := NewProductVariantRepository(mockDb.Db)
+
+	err = repo.ReplaceVariantOptions(context.Background(), variantID, []model.ProductVariantOption{
+		{Name: "colour", Value: "red"},
+		{Name: "size", Value: "M"},
+	})
+
+	assert.Nil(t, err)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchProducts(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	shopID := uuid.New().String()
+	productID := uuid.New()
+
+	tests := []struct {
+		name      string
+		req       payload.SearchProductsReq
+		setup     func(mockDB sqlmock.Sqlmock)
+		wantTotal int64
+		wantLen   int
+		wantErr   bool
+	}{
+		{
+			name: "success",
+			req: payload.SearchProductsReq{
+				Query:    "Red shirt",
+				ShopID:   shopID,
+				PriceMin: 10,
+				PriceMax: 50,
+				Page:     2,
+				PageSize: 10,
+			},
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE archived_at IS NULL AND (search_vector @@ to_tsquery('simple', $1) OR $2 <% name) AND shop_id = $3 AND price >= $4 AND price <= $5 AND "products"."deleted_at" IS NULL`),
+				).WithArgs("red:* & shirt:*", "Red shirt", shopID, 10.0, 50.0).WillReturnRows(
+					sqlmock.NewRows([]string{"count"}).AddRow(11),
+				)
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "products" WHERE archived_at IS NULL AND (search_vector @@ to_tsquery('simple', $1) OR $2 <% name) AND shop_id = $3 AND price >= $4 AND price <= $5 AND "products"."deleted_at" IS NULL ORDER BY ts_rank(search_vector, to_tsquery('simple', $6)) + word_similarity($7, name) DESC, name, id LIMIT $8 OFFSET $9`),
+				).WithArgs("red:* & shirt:*", "Red shirt", shopID, 10.0, 50.0, "red:* & shirt:*", "Red shirt", 10, 10).WillReturnRows(
+					sqlmock.NewRows([]string{"id", "name"}).AddRow(productID, "Red Shirt"),
+				)
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_bundle_items" WHERE "product_bundle_items"."bundle_id" = $1`),
+				).WithArgs(productID).WillReturnRows(sqlmock.NewRows([]string{"bundle_id", "component_id", "quantity"}))
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_images" WHERE "product_images"."product_id" = $1 ORDER BY position, created_at`),
+				).WithArgs(productID).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "position"}))
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_tags" WHERE "product_tags"."product_id" = $1`),
+				).WithArgs(productID).WillReturnRows(sqlmock.NewRows([]string{"product_id", "tag"}))
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE "product_variants"."product_id" = $1 ORDER BY sku`),
+				).WithArgs(productID).WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "sku"}))
+			},
+			wantTotal: 11,
+			wantLen:   1,
+		},
+		{
+			name: "success - in stock without query",
+			req: payload.SearchProductsReq{
+				InStock:  true,
+				Page:     1,
+				PageSize: 20,
+			},
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE archived_at IS NULL AND (CASE WHEN EXISTS (SELECT 1 FROM product_bundle_items bi WHERE bi.bundle_id = products.id) THEN NOT EXISTS (SELECT 1 FROM product_bundle_items bi LEFT JOIN product_available_stocks pas ON pas.product_id = bi.component_id WHERE bi.bundle_id = products.id AND coalesce(pas.available_stock, 0) < bi.quantity) ELSE EXISTS (SELECT 1 FROM product_available_stocks pas WHERE pas.available_stock > 0 AND (pas.product_id = products.id OR pas.product_id IN (SELECT pv.id FROM product_variants pv WHERE pv.product_id = products.id))) END) AND "products"."deleted_at" IS NULL`),
+				).WillReturnRows(
+					sqlmock.NewRows([]string{"count"}).AddRow(0),
+				)
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT * FROM "products" WHERE archived_at IS NULL AND (CASE WHEN EXISTS (SELECT 1 FROM product_bundle_items bi WHERE bi.bundle_id = products.id) THEN NOT EXISTS (SELECT 1 FROM product_bundle_items bi LEFT JOIN product_available_stocks pas ON pas.product_id = bi.component_id WHERE bi.bundle_id = products.id AND coalesce(pas.available_stock, 0) < bi.quantity) ELSE EXISTS (SELECT 1 FROM product_available_stocks pas WHERE pas.available_stock > 0 AND (pas.product_id = products.id OR pas.product_id IN (SELECT pv.id FROM product_variants pv WHERE pv.product_id = products.id))) END) AND "products"."deleted_at" IS NULL ORDER BY name, id LIMIT $1`),
+				).WithArgs(20).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+		},
+		{
+			name: "error - failed to count products",
+			req:  payload.SearchProductsReq{Query: "shirt"},
+			setup: func(mockDB sqlmock.Sqlmock) {
+				mockDB.ExpectQuery(
+					regexp.QuoteMeta(`SELECT count(*) FROM "products"`),
+				).WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(mockDb.Mock)
+
+			repo := NewProductRepository(mockDb.Db)
+
+			result, total, err := repo.SearchProducts(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantTotal, total)
+			assert.Len(t, result, tt.wantLen)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetSearchFacets(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	shopID := uuid.New().String()
+	categoryID := uuid.New().String()
+
+	mockDb.Mock.ExpectQuery(
+		regexp.QuoteMeta(`SELECT shop_id::text AS value, count(*) AS count FROM "products" WHERE archived_at IS NULL AND $1 <% name AND category_id IN ($2) AND price >= $3 AND "products"."deleted_at" IS NULL GROUP BY "shop_id" ORDER BY count DESC, value`),
+	).WithArgs("--", categoryID, 100.0).WillReturnRows(
+		sqlmock.NewRows([]string{"value", "count"}).AddRow(shopID, 3),
+	)
+	mockDb.Mock.ExpectQuery(
+		regexp.QuoteMeta(`SELECT category_id::text AS value, count(*) AS count FROM "products" WHERE archived_at IS NULL AND $1 <% name AND shop_id = $2 AND price >= $3 AND category_id IS NOT NULL AND "products"."deleted_at" IS NULL GROUP BY "category_id" ORDER BY count DESC, value`),
+	).WithArgs("--", shopID, 100.0).WillReturnRows(
+		sqlmock.NewRows([]string{"value", "count"}).AddRow(categoryID, 2),
+	)
+	mockDb.Mock.ExpectQuery(
+		regexp.QuoteMeta(`SELECT width_bucket(price::float8, ARRAY[$1,$2,$3,$4]::float8[]) AS bucket, count(*) AS count FROM "products" WHERE archived_at IS NULL AND $5 <% name AND shop_id = $6 AND category_id IN ($7) AND "products"."deleted_at" IS NULL GROUP BY "bucket"`),
+	).WithArgs(50.0, 100.0, 250.0, 500.0, "--", shopID, categoryID).WillReturnRows(
+		sqlmock.NewRows([]string{"bucket", "count"}).AddRow(0, 4).AddRow(3, 2),
+	)
+
+	repo := NewProductRepository(mockDb.Db)
+
+	facets, err := repo.GetSearchFacets(context.Background(), payload.SearchProductsReq{
+		Query:        "--",
+		ShopID:       shopID,
+		CategoryIDIN: []string{categoryID},
+		PriceMin:     100,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []payload.FacetCount{{Value: shopID, Count: 3}}, facets.Shops)
+	assert.Equal(t, []payload.FacetCount{{Value: categoryID, Count: 2}}, facets.Categories)
+	assert.Len(t, facets.PriceRanges, 5)
+	assert.Equal(t, int64(4), facets.PriceRanges[0].Count)
+	assert.Equal(t, 250.0, facets.PriceRanges[3].Min)
+	assert.Equal(t, 500.0, *facets.PriceRanges[3].Max)
+	assert.Equal(t, int64(2), facets.PriceRanges[3].Count)
+	assert.Equal(t, 500.0, facets.PriceRanges[4].Min)
+	assert.Nil(t, facets.PriceRanges[4].Max)
+	assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+}
+
+func TestToPrefixTSQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "shirt", want: "shirt:*"},
+		{query: "  Red   SHIRT ", want: "red:* & shirt:*"},
+		{query: "t-shirt's & (cotton) | !wool", want: "t:* & shirt:* & s:* & cotton:* & wool:*"},
+		{query: "café 2024", want: "café:* & 2024:*"},
+		{query: "--", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assert.Equal(t, tt.want, toPrefixTSQuery(tt.query))
+		})
+	}
+}
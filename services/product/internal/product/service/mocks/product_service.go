@@ -4,10 +4,13 @@ package mocks
 
 import (
 	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
 
 	model "github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+
 	payload "github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
-	mock "github.com/stretchr/testify/mock"
 )
 
 // ProductService is an autogenerated mock type for the ProductService type
@@ -15,6 +18,60 @@ type ProductService struct {
 	mock.Mock
 }
 
+// ApplyScheduledPrices provides a mock function with given fields: ctx
+func (_m *ProductService) ApplyScheduledPrices(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyScheduledPrices")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ArchiveProduct provides a mock function with given fields: ctx, productID
+func (_m *ProductService) ArchiveProduct(ctx context.Context, productID string) error {
+	ret := _m.Called(ctx, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ArchiveProduct")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CancelScheduledPrice provides a mock function with given fields: ctx, productID, priceID
+func (_m *ProductService) CancelScheduledPrice(ctx context.Context, productID string, priceID string) error {
+	ret := _m.Called(ctx, productID, priceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelScheduledPrice")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, productID, priceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateProduct provides a mock function with given fields: ctx, req
 func (_m *ProductService) CreateProduct(ctx context.Context, req payload.CreateProductReq) error {
 	ret := _m.Called(ctx, req)
@@ -33,6 +90,208 @@ func (_m *ProductService) CreateProduct(ctx context.Context, req payload.CreateP
 	return r0
 }
 
+// CreateSale provides a mock function with given fields: ctx, req
+func (_m *ProductService) CreateSale(ctx context.Context, req payload.CreateProductSaleReq) (model.ProductSale, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSale")
+	}
+
+	var r0 model.ProductSale
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.CreateProductSaleReq) (model.ProductSale, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.CreateProductSaleReq) model.ProductSale); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(model.ProductSale)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.CreateProductSaleReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateVariant provides a mock function with given fields: ctx, req
+func (_m *ProductService) CreateVariant(ctx context.Context, req payload.CreateProductVariantReq) (model.ProductVariant, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateVariant")
+	}
+
+	var r0 model.ProductVariant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.CreateProductVariantReq) (model.ProductVariant, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.CreateProductVariantReq) model.ProductVariant); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(model.ProductVariant)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.CreateProductVariantReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteImage provides a mock function with given fields: ctx, productID, imageID
+func (_m *ProductService) DeleteImage(ctx context.Context, productID string, imageID string) error {
+	ret := _m.Called(ctx, productID, imageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteImage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, productID, imageID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteProduct provides a mock function with given fields: ctx, productID
+func (_m *ProductService) DeleteProduct(ctx context.Context, productID string) error {
+	ret := _m.Called(ctx, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProduct")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteVariant provides a mock function with given fields: ctx, req
+func (_m *ProductService) DeleteVariant(ctx context.Context, req payload.DeleteProductVariantReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteVariant")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.DeleteProductVariantReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EndSale provides a mock function with given fields: ctx, productID, saleID
+func (_m *ProductService) EndSale(ctx context.Context, productID string, saleID string) error {
+	ret := _m.Called(ctx, productID, saleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EndSale")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, productID, saleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportProducts provides a mock function with given fields: ctx, req, w
+func (_m *ProductService) ExportProducts(ctx context.Context, req payload.ExportProductsReq, w io.Writer) error {
+	ret := _m.Called(ctx, req, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportProducts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.ExportProductsReq, io.Writer) error); ok {
+		r0 = rf(ctx, req, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetEffectivePrice provides a mock function with given fields: ctx, req
+func (_m *ProductService) GetEffectivePrice(ctx context.Context, req payload.GetEffectivePriceReq) (payload.GetEffectivePriceResp, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEffectivePrice")
+	}
+
+	var r0 payload.GetEffectivePriceResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetEffectivePriceReq) (payload.GetEffectivePriceResp, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetEffectivePriceReq) payload.GetEffectivePriceResp); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(payload.GetEffectivePriceResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetEffectivePriceReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPrices provides a mock function with given fields: ctx, productID
+func (_m *ProductService) GetPrices(ctx context.Context, productID string) (payload.GetProductPricesResp, error) {
+	ret := _m.Called(ctx, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPrices")
+	}
+
+	var r0 payload.GetProductPricesResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (payload.GetProductPricesResp, error)); ok {
+		return rf(ctx, productID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) payload.GetProductPricesResp); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		r0 = ret.Get(0).(payload.GetProductPricesResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProductByID provides a mock function with given fields: ctx, productID
 func (_m *ProductService) GetProductByID(ctx context.Context, productID string) (model.Product, error) {
 	ret := _m.Called(ctx, productID)
@@ -61,29 +320,269 @@ func (_m *ProductService) GetProductByID(ctx context.Context, productID string)
 	return r0, r1
 }
 
-// GetProducts provides a mock function with given fields: ctx, token
-func (_m *ProductService) GetProducts(ctx context.Context, token string) ([]payload.GetProductsResp, error) {
-	ret := _m.Called(ctx, token)
+// GetProducts provides a mock function with given fields: ctx, req
+func (_m *ProductService) GetProducts(ctx context.Context, req payload.GetProductsReq) ([]payload.GetProductsResp, int64, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetProducts")
 	}
 
 	var r0 []payload.GetProductsResp
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]payload.GetProductsResp, error)); ok {
-		return rf(ctx, token)
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetProductsReq) ([]payload.GetProductsResp, int64, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []payload.GetProductsResp); ok {
-		r0 = rf(ctx, token)
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetProductsReq) []payload.GetProductsResp); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]payload.GetProductsResp)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, token)
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetProductsReq) int64); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, payload.GetProductsReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ImportProducts provides a mock function with given fields: ctx, req, file
+func (_m *ProductService) ImportProducts(ctx context.Context, req payload.ImportProductsReq, file io.Reader) (payload.ImportProductsResult, error) {
+	ret := _m.Called(ctx, req, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportProducts")
+	}
+
+	var r0 payload.ImportProductsResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.ImportProductsReq, io.Reader) (payload.ImportProductsResult, error)); ok {
+		return rf(ctx, req, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.ImportProductsReq, io.Reader) payload.ImportProductsResult); ok {
+		r0 = rf(ctx, req, file)
+	} else {
+		r0 = ret.Get(0).(payload.ImportProductsResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.ImportProductsReq, io.Reader) error); ok {
+		r1 = rf(ctx, req, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchProduct provides a mock function with given fields: ctx, req
+func (_m *ProductService) PatchProduct(ctx context.Context, req payload.PatchProductReq) (model.Product, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchProduct")
+	}
+
+	var r0 model.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.PatchProductReq) (model.Product, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.PatchProductReq) model.Product); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(model.Product)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.PatchProductReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReorderImages provides a mock function with given fields: ctx, req
+func (_m *ProductService) ReorderImages(ctx context.Context, req payload.ReorderProductImagesReq) ([]model.ProductImage, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReorderImages")
+	}
+
+	var r0 []model.ProductImage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.ReorderProductImagesReq) ([]model.ProductImage, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.ReorderProductImagesReq) []model.ProductImage); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ProductImage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.ReorderProductImagesReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SchedulePrice provides a mock function with given fields: ctx, req
+func (_m *ProductService) SchedulePrice(ctx context.Context, req payload.ScheduleProductPriceReq) (model.ProductPrice, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SchedulePrice")
+	}
+
+	var r0 model.ProductPrice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.ScheduleProductPriceReq) (model.ProductPrice, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.ScheduleProductPriceReq) model.ProductPrice); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(model.ProductPrice)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.ScheduleProductPriceReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchProducts provides a mock function with given fields: ctx, req
+func (_m *ProductService) SearchProducts(ctx context.Context, req payload.SearchProductsReq) (payload.SearchProductsResp, int64, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchProducts")
+	}
+
+	var r0 payload.SearchProductsResp
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.SearchProductsReq) (payload.SearchProductsResp, int64, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.SearchProductsReq) payload.SearchProductsResp); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(payload.SearchProductsResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.SearchProductsReq) int64); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, payload.SearchProductsReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UpdateProduct provides a mock function with given fields: ctx, req
+func (_m *ProductService) UpdateProduct(ctx context.Context, req payload.UpdateProductReq) (model.Product, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProduct")
+	}
+
+	var r0 model.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.UpdateProductReq) (model.Product, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.UpdateProductReq) model.Product); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(model.Product)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.UpdateProductReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateVariant provides a mock function with given fields: ctx, req
+func (_m *ProductService) UpdateVariant(ctx context.Context, req payload.UpdateProductVariantReq) (model.ProductVariant, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateVariant")
+	}
+
+	var r0 model.ProductVariant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.UpdateProductVariantReq) (model.ProductVariant, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.UpdateProductVariantReq) model.ProductVariant); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(model.ProductVariant)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.UpdateProductVariantReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UploadImage provides a mock function with given fields: ctx, req
+func (_m *ProductService) UploadImage(ctx context.Context, req payload.UploadProductImageReq) (model.ProductImage, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadImage")
+	}
+
+	var r0 model.ProductImage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.UploadProductImageReq) (model.ProductImage, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.UploadProductImageReq) model.ProductImage); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(model.ProductImage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.UploadProductImageReq) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,506 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+var (
+	productFileColumns         = []string{"sku", "name", "description", "price", "category_id", "tags"}
+	productFileRequiredColumns = []string{"sku", "name", "description", "price"}
+)
+
+// productFileMaxLine bounds a line of a JSONL file, in bytes.
+const productFileMaxLine = 1 << 20
+
+// productImportRow is a row of an imported file. The category and tags of an
+// updated product are only replaced when the file has them, as the columns are
+// optional.
+type productImportRow struct {
+	line        int
+	row         payload.ProductFileRow
+	hasCategory bool
+	hasTags     bool
+}
+
+// ImportProducts upserts the products of a CSV or JSONL file into the shop by
+// SKU. Nothing is written on a dry run, or when any row is invalid.
+func (s *productService) ImportProducts(ctx context.Context, req payload.ImportProductsReq, file io.Reader) (result payload.ImportProductsResult, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.ImportProducts")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	shopID, err := uuid.Parse(req.ShopID)
+	if err != nil {
+		return payload.ImportProductsResult{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid shop ID")
+	}
+
+	var rows []productImportRow
+	var rowErrs []payload.ImportProductRowErr
+	if req.Format == constant.ProductFileFormatJSONL {
+		rows, rowErrs, err = parseProductImportJSONL(file)
+	} else {
+		rows, rowErrs, err = parseProductImportCSV(file)
+	}
+	if err != nil {
+		return payload.ImportProductsResult{}, err
+	}
+
+	result = payload.ImportProductsResult{
+		DryRun:    req.DryRun,
+		TotalRows: len(rows) + len(rowErrs),
+	}
+
+	rows, errs := validateProductImportRows(rows)
+	rowErrs = append(rowErrs, errs...)
+
+	var tx *gorm.DB
+	if !req.DryRun {
+		tx = s.db.Begin()
+		defer tx.Rollback()
+	}
+
+	existing, errs, err := s.checkProductImportRows(ctx, tx, shopID.String(), rows)
+	if err != nil {
+		return payload.ImportProductsResult{}, err
+	}
+	rowErrs = append(rowErrs, errs...)
+
+	result.ValidRows = result.TotalRows - len(rowErrs)
+	result.Errors = rowErrs
+	if result.Errors == nil {
+		result.Errors = []payload.ImportProductRowErr{}
+	}
+
+	// all or nothing, a single bad row keeps the whole file out
+	if req.DryRun || len(rowErrs) > 0 {
+		return result, nil
+	}
+
+	for _, row := range rows {
+		if product, ok := existing[row.row.SKU]; ok {
+			err = s.updateImportedProduct(ctx, tx, product, row)
+			result.UpdatedRows++
+		} else {
+			err = s.createImportedProduct(ctx, tx, shopID, row.row)
+			result.CreatedRows++
+		}
+		if err != nil {
+			if apperr.ErrCode(err) != apperr.CodeHTTPBadRequest {
+				return payload.ImportProductsResult{}, err
+			}
+			// another import took the SKU since the rows were checked, the
+			// transaction is aborted so the file is rejected with the row
+			result.ValidRows--
+			result.CreatedRows, result.UpdatedRows = 0, 0
+			result.Errors = append(result.Errors, payload.ImportProductRowErr{Row: row.line, SKU: row.row.SKU, Message: "sku already exists"})
+			return result, nil
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return payload.ImportProductsResult{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return result, nil
+}
+
+// checkProductImportRows checks the categories of the rows exist and returns
+// the products of the shop the rows update, by SKU.
+func (s *productService) checkProductImportRows(ctx context.Context, tx *gorm.DB, shopID string, rows []productImportRow) (map[string]model.Product, []payload.ImportProductRowErr, error) {
+	if len(rows) == 0 {
+		return nil, nil, nil
+	}
+
+	skus := make([]string, 0, len(rows))
+	for _, row := range rows {
+		skus = append(skus, row.row.SKU)
+	}
+
+	products, err := s.productRepo.WithTX(tx).GetProductsBySKUs(ctx, shopID, skus)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	existing := make(map[string]model.Product, len(products))
+	for _, product := range products {
+		existing[*product.SKU] = product
+	}
+
+	categoryFound := make(map[uuid.UUID]bool)
+	var errs []payload.ImportProductRowErr
+	for _, row := range rows {
+		categoryID := row.row.CategoryID
+		if categoryID == nil {
+			continue
+		}
+		found, checked := categoryFound[*categoryID]
+		if !checked {
+			_, err := s.categorySvc.GetCategoryByID(ctx, categoryID.String())
+			if err != nil && apperr.ErrCode(err) != apperr.CodeHTTPNotFound {
+				return nil, nil, err
+			}
+			found = err == nil
+			categoryFound[*categoryID] = found
+		}
+		if !found {
+			errs = append(errs, payload.ImportProductRowErr{Row: row.line, SKU: row.row.SKU, Message: "category not found"})
+		}
+	}
+
+	return existing, errs, nil
+}
+
+func (s *productService) createImportedProduct(ctx context.Context, tx *gorm.DB, shopID uuid.UUID, row payload.ProductFileRow) error {
+	product := model.Product{
+		ShopID:      shopID,
+		CategoryID:  row.CategoryID,
+		SKU:         &row.SKU,
+		Name:        row.Name,
+		Description: row.Description,
+		Price:       row.Price,
+	}
+	if err := s.productRepo.WithTX(tx).CreateProduct(ctx, &product); err != nil {
+		return err
+	}
+
+	if err := s.priceRepo.WithTX(tx).CreatePrice(ctx, &model.ProductPrice{
+		ProductID:     product.ID,
+		Price:         product.Price,
+		EffectiveFrom: product.CreatedAt,
+	}); err != nil {
+		return err
+	}
+
+	if len(row.Tags) == 0 {
+		return nil
+	}
+	return s.productRepo.WithTX(tx).ReplaceProductTags(ctx, product.ID, row.Tags)
+}
+
+// updateImportedProduct replaces the details of the product with the row, a
+// changed price is recorded in the price history. The category and tags are
+// kept when the file leaves them out.
+func (s *productService) updateImportedProduct(ctx context.Context, tx *gorm.DB, product model.Product, importRow productImportRow) error {
+	row := importRow.row
+	priceChanged := product.Price != row.Price
+	if importRow.hasCategory {
+		product.CategoryID = row.CategoryID
+	}
+	product.Name = row.Name
+	product.Description = row.Description
+	product.Price = row.Price
+	if err := s.productRepo.WithTX(tx).UpdateProduct(ctx, &product); err != nil {
+		return err
+	}
+
+	if priceChanged {
+		if err := s.priceRepo.WithTX(tx).CreatePrice(ctx, &model.ProductPrice{
+			ProductID:     product.ID,
+			Price:         product.Price,
+			EffectiveFrom: time.Now(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if !importRow.hasTags {
+		return nil
+	}
+	return s.productRepo.WithTX(tx).ReplaceProductTags(ctx, product.ID, row.Tags)
+}
+
+// ExportProducts writes the listed products of the shop to w in the format
+// they are imported in, a batch at a time.
+func (s *productService) ExportProducts(ctx context.Context, req payload.ExportProductsReq, w io.Writer) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.ExportProducts")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	var writeBatch func(rows []payload.ProductFileRow) error
+	if req.Format == constant.ProductFileFormatJSONL {
+		encoder := json.NewEncoder(w)
+		writeBatch = func(rows []payload.ProductFileRow) error {
+			for _, row := range rows {
+				if err := encoder.Encode(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	} else {
+		writer := csv.NewWriter(w)
+		writeBatch = func(rows []payload.ProductFileRow) error {
+			for _, row := range rows {
+				categoryID := ""
+				if row.CategoryID != nil {
+					categoryID = row.CategoryID.String()
+				}
+				_ = writer.Write([]string{
+					row.SKU,
+					row.Name,
+					row.Description,
+					strconv.FormatFloat(row.Price, 'f', -1, 64),
+					categoryID,
+					strings.Join(row.Tags, constant.ProductFileTagSeparator),
+				})
+			}
+			writer.Flush()
+			return writer.Error()
+		}
+		// the header goes out with the first batch
+		if err := writer.Write(productFileColumns); err != nil {
+			return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to write products")
+		}
+	}
+
+	err = s.productRepo.ExportProducts(ctx, req.ShopID, constant.ProductExportBatchSize, func(products []model.Product) error {
+		rows := make([]payload.ProductFileRow, 0, len(products))
+		for _, product := range products {
+			rows = append(rows, newProductFileRow(product))
+		}
+		if err := writeBatch(rows); err != nil {
+			return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to write products")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// a shop without products still gets the CSV header
+	if err := writeBatch(nil); err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to write products")
+	}
+	return nil
+}
+
+func newProductFileRow(product model.Product) payload.ProductFileRow {
+	row := payload.ProductFileRow{
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		CategoryID:  product.CategoryID,
+		Tags:        make([]string, 0, len(product.Tags)),
+	}
+	if product.SKU != nil {
+		row.SKU = *product.SKU
+	}
+	for _, tag := range product.Tags {
+		row.Tags = append(row.Tags, tag.Tag)
+	}
+	return row
+}
+
+// validateProductImportRows checks the fields of the rows and normalizes
+// their tags. A SKU may appear only once in a file.
+func validateProductImportRows(rows []productImportRow) ([]productImportRow, []payload.ImportProductRowErr) {
+	var valid []productImportRow
+	var errs []payload.ImportProductRowErr
+	seen := make(map[string]int)
+	for _, row := range rows {
+		row.row.SKU = strings.TrimSpace(row.row.SKU)
+		row.row.Tags = normalizeTags(row.row.Tags)
+
+		message := productFileRowErr(row.row)
+		if first, ok := seen[row.row.SKU]; ok && message == "" {
+			message = fmt.Sprintf("duplicate of row %d", first)
+		}
+		if message != "" {
+			errs = append(errs, payload.ImportProductRowErr{Row: row.line, SKU: row.row.SKU, Message: message})
+			continue
+		}
+
+		seen[row.row.SKU] = row.line
+		valid = append(valid, row)
+	}
+	return valid, errs
+}
+
+// productFileRowErr tells what is wrong with the row, the same rules as for a
+// product created one by one.
+func productFileRowErr(row payload.ProductFileRow) string {
+	switch {
+	case row.SKU == "":
+		return "sku is required"
+	case len(row.SKU) > 64:
+		return "sku must be at most 64 characters"
+	case strings.TrimSpace(row.Name) == "":
+		return "name is required"
+	case strings.TrimSpace(row.Description) == "":
+		return "description is required"
+	case row.Price <= 0:
+		return "price must be greater than 0"
+	case len(row.Tags) > 20:
+		return "a product has at most 20 tags"
+	}
+	for _, tag := range row.Tags {
+		if len(tag) > 50 {
+			return "tag " + tag + " is longer than 50 characters"
+		}
+	}
+	return ""
+}
+
+// parseProductImportCSV reads the rows of a CSV file. Rows that cannot be
+// parsed are reported instead of failing the import, only an unreadable file
+// or a missing column is an error.
+func parseProductImportCSV(file io.Reader) ([]productImportRow, []payload.ImportProductRowErr, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "csv file is empty")
+		}
+		return nil, nil, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "failed to read csv header")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range productFileRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "csv file is missing the "+name+" column")
+		}
+	}
+
+	_, hasCategory := columns["category_id"]
+	_, hasTags := columns["tags"]
+
+	var rows []productImportRow
+	var errs []payload.ImportProductRowErr
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				errs = append(errs, payload.ImportProductRowErr{Row: parseErr.StartLine, Message: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "failed to read csv file")
+		}
+
+		// only a record read without error has field positions
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := payload.ProductFileRow{
+			SKU:         field("sku"),
+			Name:        field("name"),
+			Description: field("description"),
+		}
+
+		row.Price, err = strconv.ParseFloat(field("price"), 64)
+		if err != nil {
+			errs = append(errs, payload.ImportProductRowErr{Row: line, SKU: row.SKU, Message: "price must be a number"})
+			continue
+		}
+
+		if categoryID := field("category_id"); categoryID != "" {
+			parsed, err := uuid.Parse(categoryID)
+			if err != nil {
+				errs = append(errs, payload.ImportProductRowErr{Row: line, SKU: row.SKU, Message: "invalid category_id"})
+				continue
+			}
+			row.CategoryID = &parsed
+		}
+
+		if tags := field("tags"); tags != "" {
+			row.Tags = strings.Split(tags, constant.ProductFileTagSeparator)
+		}
+
+		rows = append(rows, productImportRow{line: line, row: row, hasCategory: hasCategory, hasTags: hasTags})
+	}
+
+	if len(rows)+len(errs) == 0 {
+		return nil, nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "csv file has no rows")
+	}
+
+	return rows, errs, nil
+}
+
+// parseProductImportJSONL reads a JSONL file, a product on each line. Blank
+// lines are skipped and lines that are not a product are reported.
+func parseProductImportJSONL(file io.Reader) ([]productImportRow, []payload.ImportProductRowErr, error) {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), productFileMaxLine)
+
+	var rows []productImportRow
+	var errs []payload.ImportProductRowErr
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var row payload.ProductFileRow
+		if err := json.Unmarshal(data, &row); err != nil {
+			errs = append(errs, payload.ImportProductRowErr{Row: line, Message: "invalid json: " + err.Error()})
+			continue
+		}
+
+		// a key left out keeps the category or tags, a null clears them
+		var keys map[string]json.RawMessage
+		_ = json.Unmarshal(data, &keys)
+		importRow := productImportRow{line: line, row: row}
+		for key := range keys {
+			switch strings.ToLower(key) {
+			case "category_id":
+				importRow.hasCategory = true
+			case "tags":
+				importRow.hasTags = true
+			}
+		}
+		rows = append(rows, importRow)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "failed to read jsonl file")
+	}
+
+	if len(rows)+len(errs) == 0 {
+		return nil, nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "jsonl file has no rows")
+	}
+
+	return rows, errs, nil
+}
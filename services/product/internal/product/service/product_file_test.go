@@ -0,0 +1,475 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	categorySvcMock "github.com/alifmufthi91/ecommerce-system/services/product/internal/category/service/mocks"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	productRepoMock "github.com/alifmufthi91/ecommerce-system/services/product/internal/product/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseProductImportCSV(t *testing.T) {
+	categoryID := uuid.New()
+
+	tests := []struct {
+		name     string
+		csv      string
+		wantRows []productImportRow
+		wantErrs []payload.ImportProductRowErr
+		wantErr  bool
+	}{
+		{
+			name: "success - columns in any order",
+			csv: "price,sku,name,description,tags,category_id\n" +
+				"12.5,TS-001,T-Shirt,\"Cotton, white\",summer|Sale," + categoryID.String() + "\n" +
+				" 8 , TS-002 ,Socks,Wool,,\n",
+			wantRows: []productImportRow{
+				{line: 2, row: payload.ProductFileRow{SKU: "TS-001", Name: "T-Shirt", Description: "Cotton, white", Price: 12.5, CategoryID: &categoryID, Tags: []string{"summer", "Sale"}}, hasCategory: true, hasTags: true},
+				{line: 3, row: payload.ProductFileRow{SKU: "TS-002", Name: "Socks", Description: "Wool", Price: 8}, hasCategory: true, hasTags: true},
+			},
+		},
+		{
+			name: "success - optional columns left out",
+			csv:  "sku,name,description,price\nTS-001,T-Shirt,Cotton,12.5\n",
+			wantRows: []productImportRow{
+				{line: 2, row: payload.ProductFileRow{SKU: "TS-001", Name: "T-Shirt", Description: "Cotton", Price: 12.5}},
+			},
+		},
+		{
+			name: "success - invalid rows are reported",
+			csv: "sku,name,description,price,category_id\n" +
+				"TS-001,T-Shirt,Cotton,cheap,\n" +
+				"TS-002,Socks,Wool,8,not-a-uuid\n" +
+				"TS-003,\"Cap,Cotton,5,\n",
+			wantErrs: []payload.ImportProductRowErr{
+				{Row: 2, SKU: "TS-001", Message: "price must be a number"},
+				{Row: 3, SKU: "TS-002", Message: "invalid category_id"},
+				{Row: 4, Message: "extraneous or missing \" in quoted-field"},
+			},
+		},
+		{
+			name: "success - malformed first row is reported and the next rows read",
+			csv: "sku,name,description,price\n" +
+				"TS-001,Ca\"p,Cotton,5\n" +
+				"TS-002,Socks,Wool,8\n",
+			wantRows: []productImportRow{
+				{line: 3, row: payload.ProductFileRow{SKU: "TS-002", Name: "Socks", Description: "Wool", Price: 8}},
+			},
+			wantErrs: []payload.ImportProductRowErr{
+				{Row: 2, Message: "bare \" in non-quoted-field"},
+			},
+		},
+		{
+			name:    "error - missing column",
+			csv:     "sku,name,price\nTS-001,T-Shirt,12.5\n",
+			wantErr: true,
+		},
+		{
+			name:    "error - header only",
+			csv:     "sku,name,description,price\n",
+			wantErr: true,
+		},
+		{
+			name:    "error - empty file",
+			csv:     "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, errs, err := parseProductImportCSV(strings.NewReader(tt.csv))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, apperr.CodeHTTPBadRequest, apperr.ErrCode(err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRows, rows)
+			assert.Equal(t, tt.wantErrs, errs)
+		})
+	}
+}
+
+func TestParseProductImportJSONL(t *testing.T) {
+	categoryID := uuid.New()
+
+	tests := []struct {
+		name     string
+		jsonl    string
+		wantRows []productImportRow
+		wantErrs []payload.ImportProductRowErr
+		wantErr  bool
+	}{
+		{
+			name: "success - blank lines skipped and invalid lines reported",
+			jsonl: `{"sku":"TS-001","name":"T-Shirt","description":"Cotton","price":12.5,"category_id":"` + categoryID.String() + `","tags":["summer"]}` + "\n" +
+				"\n" +
+				`{"sku":"TS-002","name":"Socks","description":"Wool","price":"cheap"}` + "\n" +
+				`{"sku":"TS-003","name":"Cap","description":"Cotton","price":5}` + "\n" +
+				`{"sku":"TS-004","name":"Scarf","description":"Silk","price":9,"category_id":null}`,
+			wantRows: []productImportRow{
+				{line: 1, row: payload.ProductFileRow{SKU: "TS-001", Name: "T-Shirt", Description: "Cotton", Price: 12.5, CategoryID: &categoryID, Tags: []string{"summer"}}, hasCategory: true, hasTags: true},
+				{line: 4, row: payload.ProductFileRow{SKU: "TS-003", Name: "Cap", Description: "Cotton", Price: 5}},
+				{line: 5, row: payload.ProductFileRow{SKU: "TS-004", Name: "Scarf", Description: "Silk", Price: 9}, hasCategory: true},
+			},
+			wantErrs: []payload.ImportProductRowErr{
+				{Row: 3, Message: "invalid json: json: cannot unmarshal string into Go struct field ProductFileRow.price of type float64"},
+			},
+		},
+		{
+			name:    "error - empty file",
+			jsonl:   "\n\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, errs, err := parseProductImportJSONL(strings.NewReader(tt.jsonl))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, apperr.CodeHTTPBadRequest, apperr.ErrCode(err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRows, rows)
+			assert.Equal(t, tt.wantErrs, errs)
+		})
+	}
+}
+
+func TestValidateProductImportRows(t *testing.T) {
+	valid := payload.ProductFileRow{SKU: "TS-001", Name: "T-Shirt", Description: "Cotton", Price: 12.5}
+	with := func(change func(row *payload.ProductFileRow)) payload.ProductFileRow {
+		row := valid
+		change(&row)
+		return row
+	}
+
+	rows, errs := validateProductImportRows([]productImportRow{
+		{line: 2, row: with(func(row *payload.ProductFileRow) { row.SKU = " TS-001 "; row.Tags = []string{"Summer", "summer "} })},
+		{line: 3, row: with(func(row *payload.ProductFileRow) { row.SKU = "" })},
+		{line: 4, row: with(func(row *payload.ProductFileRow) { row.SKU = "TS-002"; row.Name = " " })},
+		{line: 5, row: with(func(row *payload.ProductFileRow) { row.SKU = "TS-003"; row.Price = 0 })},
+		{line: 6, row: with(func(row *payload.ProductFileRow) { row.SKU = "TS-004"; row.Tags = []string{strings.Repeat("x", 51)} })},
+		{line: 7, row: valid},
+	})
+
+	assert.Equal(t, []productImportRow{
+		{line: 2, row: with(func(row *payload.ProductFileRow) { row.Tags = []string{"summer"} })},
+	}, rows)
+	assert.Equal(t, []payload.ImportProductRowErr{
+		{Row: 3, Message: "sku is required"},
+		{Row: 4, SKU: "TS-002", Message: "name is required"},
+		{Row: 5, SKU: "TS-003", Message: "price must be greater than 0"},
+		{Row: 6, SKU: "TS-004", Message: "tag " + strings.Repeat("x", 51) + " is longer than 50 characters"},
+		{Row: 7, SKU: "TS-001", Message: "duplicate of row 2"},
+	}, errs)
+}
+
+func TestImportProducts(t *testing.T) {
+	type dependencyMocks struct {
+		db          sqlmock.Sqlmock
+		categorySvc *categorySvcMock.CategoryService
+		productRepo *productRepoMock.ProductRepository
+		priceRepo   *productRepoMock.ProductPriceRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	shopID := uuid.New()
+	productID := uuid.New()
+	categoryID := uuid.New()
+	sku := "TS-001"
+
+	file := "sku,name,description,price,category_id,tags\n" +
+		"TS-001,T-Shirt,Cotton,15,,summer\n" +
+		"TS-002,Socks,Wool,8," + categoryID.String() + ",\n"
+
+	tests := []struct {
+		name     string
+		req      payload.ImportProductsReq
+		file     string
+		setup    func(m dependencyMocks)
+		want     payload.ImportProductsResult
+		wantCode apperr.Code
+	}{
+		{
+			name: "success - dry run writes nothing",
+			req:  payload.ImportProductsReq{ShopID: shopID.String(), DryRun: true},
+			file: file,
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("GetProductsBySKUs", mock.Anything, shopID.String(), []string{"TS-001", "TS-002"}).
+					Return([]model.Product{{ID: productID, ShopID: shopID, SKU: &sku, Price: 12.5}}, nil)
+				m.categorySvc.On("GetCategoryByID", mock.Anything, categoryID.String()).
+					Return(model.Category{ID: categoryID}, nil)
+			},
+			want: payload.ImportProductsResult{DryRun: true, TotalRows: 2, ValidRows: 2, Errors: []payload.ImportProductRowErr{}},
+		},
+		{
+			name: "success - products updated by SKU and created",
+			req:  payload.ImportProductsReq{ShopID: shopID.String()},
+			file: file,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("GetProductsBySKUs", mock.Anything, shopID.String(), []string{"TS-001", "TS-002"}).
+					Return([]model.Product{{ID: productID, ShopID: shopID, SKU: &sku, Price: 12.5}}, nil)
+				m.categorySvc.On("GetCategoryByID", mock.Anything, categoryID.String()).
+					Return(model.Category{ID: categoryID}, nil)
+				m.productRepo.On("UpdateProduct", mock.Anything, mock.MatchedBy(func(product *model.Product) bool {
+					return product.ID == productID && product.Name == "T-Shirt" && product.Price == 15
+				})).
+					Return(nil)
+				m.priceRepo.On("WithTX", mock.Anything).
+					Return(m.priceRepo)
+				m.priceRepo.On("CreatePrice", mock.Anything, mock.MatchedBy(func(price *model.ProductPrice) bool {
+					return price.ProductID == productID && price.Price == 15
+				})).
+					Return(nil).Once()
+				m.productRepo.On("ReplaceProductTags", mock.Anything, productID, []string{"summer"}).
+					Return(nil)
+				m.productRepo.On("CreateProduct", mock.Anything, mock.MatchedBy(func(product *model.Product) bool {
+					return product.ShopID == shopID && *product.SKU == "TS-002" && *product.CategoryID == categoryID
+				})).
+					Return(nil)
+				m.priceRepo.On("CreatePrice", mock.Anything, mock.MatchedBy(func(price *model.ProductPrice) bool {
+					return price.Price == 8
+				})).
+					Return(nil).Once()
+				m.db.ExpectCommit()
+			},
+			want: payload.ImportProductsResult{TotalRows: 2, ValidRows: 2, CreatedRows: 1, UpdatedRows: 1, Errors: []payload.ImportProductRowErr{}},
+		},
+		{
+			name: "success - required columns only keep the category and tags",
+			req:  payload.ImportProductsReq{ShopID: shopID.String()},
+			file: "sku,name,description,price\nTS-001,T-Shirt,Cotton,12.5\n",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("GetProductsBySKUs", mock.Anything, shopID.String(), []string{"TS-001"}).
+					Return([]model.Product{{ID: productID, ShopID: shopID, CategoryID: &categoryID, SKU: &sku, Price: 12.5}}, nil)
+				m.productRepo.On("UpdateProduct", mock.Anything, mock.MatchedBy(func(product *model.Product) bool {
+					return product.ID == productID && product.Name == "T-Shirt" && product.CategoryID != nil && *product.CategoryID == categoryID
+				})).
+					Return(nil)
+				m.db.ExpectCommit()
+			},
+			want: payload.ImportProductsResult{TotalRows: 1, ValidRows: 1, UpdatedRows: 1, Errors: []payload.ImportProductRowErr{}},
+		},
+		{
+			name: "success - invalid rows keep the file out",
+			req:  payload.ImportProductsReq{ShopID: shopID.String()},
+			file: file + "TS-003,Cap,Cotton,-5,,\n",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("GetProductsBySKUs", mock.Anything, shopID.String(), []string{"TS-001", "TS-002"}).
+					Return(nil, nil)
+				m.categorySvc.On("GetCategoryByID", mock.Anything, categoryID.String()).
+					Return(model.Category{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "category not found"))
+				m.db.ExpectRollback()
+			},
+			want: payload.ImportProductsResult{
+				TotalRows: 3,
+				ValidRows: 1,
+				Errors: []payload.ImportProductRowErr{
+					{Row: 4, SKU: "TS-003", Message: "price must be greater than 0"},
+					{Row: 3, SKU: "TS-002", Message: "category not found"},
+				},
+			},
+		},
+		{
+			name: "success - jsonl",
+			req:  payload.ImportProductsReq{ShopID: shopID.String(), Format: constant.ProductFileFormatJSONL, DryRun: true},
+			file: `{"sku":"TS-001","name":"T-Shirt","description":"Cotton","price":15}`,
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("GetProductsBySKUs", mock.Anything, shopID.String(), []string{"TS-001"}).
+					Return(nil, nil)
+			},
+			want: payload.ImportProductsResult{DryRun: true, TotalRows: 1, ValidRows: 1, Errors: []payload.ImportProductRowErr{}},
+		},
+		{
+			name: "success - sku taken by another import keeps the file out",
+			req:  payload.ImportProductsReq{ShopID: shopID.String()},
+			file: "sku,name,description,price\nTS-002,Socks,Wool,8\n",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("GetProductsBySKUs", mock.Anything, shopID.String(), []string{"TS-002"}).
+					Return(nil, nil)
+				m.productRepo.On("CreateProduct", mock.Anything, mock.Anything).
+					Return(apperr.NewWithCode(apperr.CodeHTTPBadRequest, "sku already exists"))
+				m.db.ExpectRollback()
+			},
+			want: payload.ImportProductsResult{
+				TotalRows: 1,
+				Errors: []payload.ImportProductRowErr{
+					{Row: 2, SKU: "TS-002", Message: "sku already exists"},
+				},
+			},
+		},
+		{
+			name:     "error - unreadable file",
+			req:      payload.ImportProductsReq{ShopID: shopID.String()},
+			file:     "",
+			setup:    func(m dependencyMocks) {},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - failed to create product",
+			req:  payload.ImportProductsReq{ShopID: shopID.String()},
+			file: "sku,name,description,price\nTS-002,Socks,Wool,8\n",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("GetProductsBySKUs", mock.Anything, shopID.String(), []string{"TS-002"}).
+					Return(nil, nil)
+				m.productRepo.On("CreateProduct", mock.Anything, mock.Anything).
+					Return(apperr.WrapWithCode(assert.AnError, apperr.CodeSQLCreate, "failed to create product"))
+				m.db.ExpectRollback()
+			},
+			wantCode: apperr.CodeSQLCreate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:          mockDb.Mock,
+				categorySvc: categorySvcMock.NewCategoryService(t),
+				productRepo: productRepoMock.NewProductRepository(t),
+				priceRepo:   productRepoMock.NewProductPriceRepository(t),
+			}
+			productSvc := productService{
+				db:          mockDb.Db,
+				categorySvc: mocks.categorySvc,
+				productRepo: mocks.productRepo,
+				priceRepo:   mocks.priceRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			result, err := productSvc.ImportProducts(context.Background(), tt.req, strings.NewReader(tt.file))
+
+			// Then
+			if tt.wantCode != 0 {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, result)
+			}
+			mocks.productRepo.AssertExpectations(t)
+			mocks.priceRepo.AssertExpectations(t)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestExportProducts(t *testing.T) {
+	shopID := uuid.New().String()
+	categoryID := uuid.MustParse("6f1c2b1e-0000-4000-8000-000000000001")
+	sku := "TS-001"
+
+	products := []model.Product{
+		{
+			SKU:         &sku,
+			Name:        "T-Shirt",
+			Description: "Cotton, white",
+			Price:       12.5,
+			CategoryID:  &categoryID,
+			Tags:        []model.ProductTag{{Tag: "summer"}, {Tag: "sale"}},
+		},
+		{Name: "Socks", Description: "Wool", Price: 8},
+	}
+
+	tests := []struct {
+		name     string
+		format   string
+		products []model.Product
+		repoErr  error
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "success - csv",
+			products: products,
+			want: "sku,name,description,price,category_id,tags\n" +
+				"TS-001,T-Shirt,\"Cotton, white\",12.5," + categoryID.String() + ",summer|sale\n" +
+				",Socks,Wool,8,,\n",
+		},
+		{
+			name: "success - csv without products",
+			want: "sku,name,description,price,category_id,tags\n",
+		},
+		{
+			name:     "success - jsonl",
+			format:   constant.ProductFileFormatJSONL,
+			products: products,
+			want: `{"sku":"TS-001","name":"T-Shirt","description":"Cotton, white","price":12.5,"category_id":"` + categoryID.String() + `","tags":["summer","sale"]}` + "\n" +
+				`{"sku":"","name":"Socks","description":"Wool","price":8,"category_id":null,"tags":[]}` + "\n",
+		},
+		{
+			name:    "error - failed to read products",
+			repoErr: assert.AnError,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			productRepo := productRepoMock.NewProductRepository(t)
+			productRepo.On("ExportProducts", mock.Anything, shopID, constant.ProductExportBatchSize, mock.Anything).
+				Run(func(args mock.Arguments) {
+					if len(tt.products) > 0 {
+						_ = args.Get(3).(func([]model.Product) error)(tt.products)
+					}
+				}).
+				Return(tt.repoErr)
+			productSvc := productService{productRepo: productRepo}
+
+			// When
+			var out bytes.Buffer
+			err := productSvc.ExportProducts(context.Background(), payload.ExportProductsReq{ShopID: shopID, Format: tt.format}, &out)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
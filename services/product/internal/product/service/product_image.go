@@ -0,0 +1,236 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"net/http"
+	"path"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/imaging"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// UploadImage stores the image and a thumbnail of it, and adds it after the
+// other images of the product.
+func (s *productService) UploadImage(ctx context.Context, req payload.UploadProductImageReq) (res model.ProductImage, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.UploadImage")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	contentType, config, err := validateImage(req.Data)
+	if err != nil {
+		return model.ProductImage{}, err
+	}
+
+	product, err := s.getProduct(ctx, req.ProductID.String())
+	if err != nil {
+		return model.ProductImage{}, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(req.Data))
+	if err != nil {
+		return model.ProductImage{}, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid image")
+	}
+	thumbnail, err := imaging.Encode(imaging.Thumbnail(img, constant.ImageThumbnailSize), contentType)
+	if err != nil {
+		return model.ProductImage{}, err
+	}
+
+	position := 0
+	if len(product.Images) > 0 {
+		position = product.Images[len(product.Images)-1].Position + 1
+	}
+
+	id := uuid.New()
+	ext := imageExtensions[contentType]
+	productImage := model.ProductImage{
+		ID:           id,
+		ProductID:    product.ID,
+		Position:     position,
+		ContentType:  contentType,
+		Size:         int64(len(req.Data)),
+		Width:        config.Width,
+		Height:       config.Height,
+		Key:          path.Join("products", product.ID.String(), id.String()+ext),
+		ThumbnailKey: path.Join("products", product.ID.String(), id.String()+"_thumb"+ext),
+	}
+
+	if err := s.storeImage(ctx, productImage, req.Data, thumbnail); err != nil {
+		return model.ProductImage{}, err
+	}
+	if err := s.imageRepo.CreateImage(ctx, &productImage); err != nil {
+		s.deleteImageFiles(ctx, productImage)
+		return model.ProductImage{}, err
+	}
+
+	productImage.URL = s.storage.URL(productImage.Key)
+	productImage.ThumbnailURL = s.storage.URL(productImage.ThumbnailKey)
+	return productImage, nil
+}
+
+// ReorderImages puts the images of the product in the order of the request,
+// which lists each of them once.
+func (s *productService) ReorderImages(ctx context.Context, req payload.ReorderProductImagesReq) (res []model.ProductImage, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.ReorderImages")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	product, err := s.getProduct(ctx, req.ProductID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	imageMap := make(map[uuid.UUID]model.ProductImage, len(product.Images))
+	for _, productImage := range product.Images {
+		imageMap[productImage.ID] = productImage
+	}
+
+	images := make([]model.ProductImage, 0, len(req.ImageIDs))
+	for _, imageID := range req.ImageIDs {
+		productImage, ok := imageMap[imageID]
+		if !ok {
+			return nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "image "+imageID.String()+" not found or listed twice")
+		}
+		delete(imageMap, imageID)
+		images = append(images, productImage)
+	}
+	if len(imageMap) > 0 {
+		return nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "image_ids must list every image of the product")
+	}
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	for i := range images {
+		images[i].Position = i
+		if err := s.imageRepo.WithTX(tx).UpdateImagePosition(ctx, images[i].ID.String(), i); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.setImageURLs(images)
+	return images, nil
+}
+
+// DeleteImage deletes the image and then its files, once the deletion is
+// committed, so a failed deletion never leaves an image without files.
+func (s *productService) DeleteImage(ctx context.Context, productID, imageID string) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.DeleteImage")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	product, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	var productImage model.ProductImage
+	for _, i := range product.Images {
+		if i.ID.String() == imageID {
+			productImage = i
+		}
+	}
+	if productImage.ID == uuid.Nil {
+		return apperr.NewWithCode(apperr.CodeHTTPNotFound, "product image not found")
+	}
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	if err := s.imageRepo.WithTX(tx).DeleteImage(ctx, imageID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.deleteImageFiles(ctx, productImage)
+	return nil
+}
+
+var imageExtensions = map[string]string{
+	constant.ImageContentTypeJPEG: ".jpg",
+	constant.ImageContentTypePNG:  ".png",
+}
+
+// validateImage checks the image is a JPEG or PNG within the size limits, and
+// returns its content type and dimensions.
+func validateImage(data []byte) (string, image.Config, error) {
+	if len(data) == 0 {
+		return "", image.Config{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "image is empty")
+	}
+	if len(data) > constant.ImageMaxSize {
+		return "", image.Config{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, fmt.Sprintf("image must not be larger than %d MiB", constant.ImageMaxSize>>20))
+	}
+
+	contentType := http.DetectContentType(data)
+	if _, ok := imageExtensions[contentType]; !ok {
+		return "", image.Config{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "image must be a JPEG or PNG, got "+contentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", image.Config{}, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid image")
+	}
+	if config.Width*config.Height > constant.ImageMaxPixels {
+		return "", image.Config{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, fmt.Sprintf("image must not have more than %d pixels", constant.ImageMaxPixels))
+	}
+
+	return contentType, config, nil
+}
+
+// storeImage puts the image and its thumbnail in the storage, none of them
+// stays when either fails.
+func (s *productService) storeImage(ctx context.Context, productImage model.ProductImage, data, thumbnail []byte) error {
+	if err := s.storage.Put(ctx, productImage.Key, data, productImage.ContentType); err != nil {
+		return err
+	}
+	if err := s.storage.Put(ctx, productImage.ThumbnailKey, thumbnail, productImage.ContentType); err != nil {
+		s.deleteImageFiles(ctx, productImage)
+		return err
+	}
+	return nil
+}
+
+// deleteImageFiles cleans up the files of an image that was not saved or was
+// deleted. A file left behind is only unused space, so failures are ignored.
+func (s *productService) deleteImageFiles(ctx context.Context, productImage model.ProductImage) {
+	_ = s.storage.Delete(ctx, productImage.Key)
+	_ = s.storage.Delete(ctx, productImage.ThumbnailKey)
+}
+
+// setImageURLs sets where the storage serves the images and their thumbnails.
+func (s *productService) setImageURLs(images []model.ProductImage) {
+	for i := range images {
+		images[i].URL = s.storage.URL(images[i].Key)
+		images[i].ThumbnailURL = s.storage.URL(images[i].ThumbnailKey)
+	}
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	storageMock "github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/storage/mocks"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	productRepoMock "github.com/alifmufthi91/ecommerce-system/services/product/internal/product/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func pngImage(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestUploadImage(t *testing.T) {
+	type dependencyMocks struct {
+		productRepo *productRepoMock.ProductRepository
+		imageRepo   *productRepoMock.ProductImageRepository
+		storage     *storageMock.Storage
+	}
+
+	productID := uuid.New()
+	data := pngImage(t, 640, 480)
+
+	product := model.Product{
+		ID:     productID,
+		Images: []model.ProductImage{{ID: uuid.New(), ProductID: productID, Position: 2}},
+	}
+
+	// isThumbnail matches a PNG scaled down to the thumbnail size
+	isThumbnail := mock.MatchedBy(func(thumbnail []byte) bool {
+		config, err := png.DecodeConfig(bytes.NewReader(thumbnail))
+		return err == nil && config.Width == constant.ImageThumbnailSize && config.Height == 240
+	})
+	url := func(key string) string { return "http://media/" + key }
+
+	tests := []struct {
+		name     string
+		data     []byte
+		setup    func(m dependencyMocks)
+		wantCode apperr.Code
+	}{
+		{
+			name: "success",
+			data: data,
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.storage.On("URL", mock.Anything).Return(url)
+				m.storage.On("Put", mock.Anything, mock.Anything, data, constant.ImageContentTypePNG).Return(nil).Once()
+				m.storage.On("Put", mock.Anything, mock.Anything, isThumbnail, constant.ImageContentTypePNG).Return(nil).Once()
+				m.imageRepo.On("CreateImage", mock.Anything, mock.MatchedBy(func(image *model.ProductImage) bool {
+					return image.ProductID == productID && image.Position == 3 && image.Width == 640 && image.Height == 480 &&
+						image.Size == int64(len(data)) && image.Key == "products/"+productID.String()+"/"+image.ID.String()+".png"
+				})).Return(nil)
+			},
+		},
+		{
+			name: "error - empty image",
+			data: []byte{},
+			setup: func(m dependencyMocks) {
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - image too large",
+			data: append(append([]byte{}, data...), make([]byte, constant.ImageMaxSize)...),
+			setup: func(m dependencyMocks) {
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - not an image",
+			data: []byte("GIF89a not supported"),
+			setup: func(m dependencyMocks) {
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - corrupted image",
+			data: data[:64],
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.storage.On("URL", mock.Anything).Return(url)
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - product not found",
+			data: data,
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product not found"))
+			},
+			wantCode: apperr.CodeHTTPNotFound,
+		},
+		{
+			name: "error - failed to store thumbnail",
+			data: data,
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.storage.On("URL", mock.Anything).Return(url)
+				m.storage.On("Put", mock.Anything, mock.Anything, data, constant.ImageContentTypePNG).Return(nil).Once()
+				m.storage.On("Put", mock.Anything, mock.Anything, isThumbnail, constant.ImageContentTypePNG).
+					Return(apperr.NewWithCode(apperr.CodeHTTPInternalServerError, "failed to store file")).Once()
+				m.storage.On("Delete", mock.Anything, mock.Anything).Return(nil).Twice()
+			},
+			wantCode: apperr.CodeHTTPInternalServerError,
+		},
+		{
+			name: "error - failed to create image",
+			data: data,
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.storage.On("URL", mock.Anything).Return(url)
+				m.storage.On("Put", mock.Anything, mock.Anything, mock.Anything, constant.ImageContentTypePNG).Return(nil).Twice()
+				m.imageRepo.On("CreateImage", mock.Anything, mock.Anything).
+					Return(apperr.NewWithCode(apperr.CodeSQLCreate, "failed to create product image"))
+				m.storage.On("Delete", mock.Anything, mock.Anything).Return(errors.New("unreachable")).Twice()
+			},
+			wantCode: apperr.CodeSQLCreate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				productRepo: productRepoMock.NewProductRepository(t),
+				imageRepo:   productRepoMock.NewProductImageRepository(t),
+				storage:     storageMock.NewStorage(t),
+			}
+			productSvc := productService{
+				productRepo: mocks.productRepo,
+				imageRepo:   mocks.imageRepo,
+				storage:     mocks.storage,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			result, err := productSvc.UploadImage(context.Background(), payload.UploadProductImageReq{
+				ProductID: productID,
+				Data:      tt.data,
+			})
+
+			// Then
+			if tt.wantCode != 0 {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 3, result.Position)
+			assert.Equal(t, constant.ImageContentTypePNG, result.ContentType)
+			assert.Equal(t, url(result.Key), result.URL)
+			assert.Equal(t, url(result.ThumbnailKey), result.ThumbnailURL)
+		})
+	}
+}
+
+func TestReorderImages(t *testing.T) {
+	type dependencyMocks struct {
+		db          sqlmock.Sqlmock
+		productRepo *productRepoMock.ProductRepository
+		imageRepo   *productRepoMock.ProductImageRepository
+		storage     *storageMock.Storage
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	product := model.Product{
+		ID: productID,
+		Images: []model.ProductImage{
+			{ID: first, Position: 0, Key: "first.png"},
+			{ID: second, Position: 1, Key: "second.png"},
+			{ID: third, Position: 2, Key: "third.png"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		imageIDs []uuid.UUID
+		setup    func(m dependencyMocks)
+		want     []uuid.UUID
+		wantCode apperr.Code
+	}{
+		{
+			name:     "success",
+			imageIDs: []uuid.UUID{third, first, second},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.storage.On("URL", mock.Anything).Return("http://media/image.png")
+				m.db.ExpectBegin()
+				m.imageRepo.On("WithTX", mock.Anything).Return(m.imageRepo)
+				m.imageRepo.On("UpdateImagePosition", mock.Anything, third.String(), 0).Return(nil)
+				m.imageRepo.On("UpdateImagePosition", mock.Anything, first.String(), 1).Return(nil)
+				m.imageRepo.On("UpdateImagePosition", mock.Anything, second.String(), 2).Return(nil)
+				m.db.ExpectCommit()
+			},
+			want: []uuid.UUID{third, first, second},
+		},
+		{
+			name:     "error - image missing",
+			imageIDs: []uuid.UUID{third, first},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.storage.On("URL", mock.Anything).Return("http://media/image.png")
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name:     "error - image listed twice",
+			imageIDs: []uuid.UUID{third, first, third},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.storage.On("URL", mock.Anything).Return("http://media/image.png")
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name:     "error - image of another product",
+			imageIDs: []uuid.UUID{third, first, second, uuid.New()},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.storage.On("URL", mock.Anything).Return("http://media/image.png")
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:          mockDb.Mock,
+				productRepo: productRepoMock.NewProductRepository(t),
+				imageRepo:   productRepoMock.NewProductImageRepository(t),
+				storage:     storageMock.NewStorage(t),
+			}
+			productSvc := productService{
+				db:          mockDb.Db,
+				productRepo: mocks.productRepo,
+				imageRepo:   mocks.imageRepo,
+				storage:     mocks.storage,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			result, err := productSvc.ReorderImages(context.Background(), payload.ReorderProductImagesReq{
+				ProductID: productID,
+				ImageIDs:  tt.imageIDs,
+			})
+
+			// Then
+			if tt.wantCode != 0 {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			for i, image := range result {
+				assert.Equal(t, tt.want[i], image.ID)
+				assert.Equal(t, i, image.Position)
+				assert.Equal(t, "http://media/image.png", image.URL)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteImage(t *testing.T) {
+	type dependencyMocks struct {
+		db          sqlmock.Sqlmock
+		productRepo *productRepoMock.ProductRepository
+		imageRepo   *productRepoMock.ProductImageRepository
+		storage     *storageMock.Storage
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	imageID := uuid.New()
+
+	product := model.Product{
+		ID:     productID,
+		Images: []model.ProductImage{{ID: imageID, Key: "image.png", ThumbnailKey: "image_thumb.png"}},
+	}
+
+	tests := []struct {
+		name     string
+		imageID  string
+		setup    func(m dependencyMocks)
+		wantCode apperr.Code
+	}{
+		{
+			name:    "success",
+			imageID: imageID.String(),
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.storage.On("URL", mock.Anything).Return("http://media/image.png")
+				m.db.ExpectBegin()
+				m.imageRepo.On("WithTX", mock.Anything).Return(m.imageRepo)
+				m.imageRepo.On("DeleteImage", mock.Anything, imageID.String()).Return(nil)
+				m.db.ExpectCommit()
+				m.storage.On("Delete", mock.Anything, "image.png").Return(nil)
+				m.storage.On("Delete", mock.Anything, "image_thumb.png").Return(nil)
+			},
+		},
+		{
+			name:    "error - image not found",
+			imageID: uuid.New().String(),
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.storage.On("URL", mock.Anything).Return("http://media/image.png")
+			},
+			wantCode: apperr.CodeHTTPNotFound,
+		},
+		{
+			name:    "success - failed to delete file is ignored",
+			imageID: imageID.String(),
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.storage.On("URL", mock.Anything).Return("http://media/image.png")
+				m.db.ExpectBegin()
+				m.imageRepo.On("WithTX", mock.Anything).Return(m.imageRepo)
+				m.imageRepo.On("DeleteImage", mock.Anything, imageID.String()).Return(nil)
+				m.db.ExpectCommit()
+				m.storage.On("Delete", mock.Anything, "image.png").
+					Return(apperr.NewWithCode(apperr.CodeHTTPInternalServerError, "failed to delete file"))
+				m.storage.On("Delete", mock.Anything, "image_thumb.png").Return(nil)
+			},
+		},
+		{
+			name:    "error - failed to commit keeps the files",
+			imageID: imageID.String(),
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.storage.On("URL", mock.Anything).Return("http://media/image.png")
+				m.db.ExpectBegin()
+				m.imageRepo.On("WithTX", mock.Anything).Return(m.imageRepo)
+				m.imageRepo.On("DeleteImage", mock.Anything, imageID.String()).Return(nil)
+				m.db.ExpectCommit().WillReturnError(sqlmock.ErrCancelled)
+			},
+			wantCode: apperr.CodeHTTPInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:          mockDb.Mock,
+				productRepo: productRepoMock.NewProductRepository(t),
+				imageRepo:   productRepoMock.NewProductImageRepository(t),
+				storage:     storageMock.NewStorage(t),
+			}
+			productSvc := productService{
+				db:          mockDb.Db,
+				productRepo: mocks.productRepo,
+				imageRepo:   mocks.imageRepo,
+				storage:     mocks.storage,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := productSvc.DeleteImage(context.Background(), productID.String(), tt.imageID)
+
+			// Then
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+			if tt.wantCode != 0 {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// GetPrices returns the price history, scheduled prices and sales of the
+// product, deleted products included for the orders placed before.
+func (s *productService) GetPrices(ctx context.Context, productID string) (res payload.GetProductPricesResp, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.GetPrices")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if _, err := s.productRepo.GetProductByID(ctx, productID); err != nil {
+		return payload.GetProductPricesResp{}, err
+	}
+
+	prices, err := s.priceRepo.GetPrices(ctx, productID)
+	if err != nil {
+		return payload.GetProductPricesResp{}, err
+	}
+	sales, err := s.priceRepo.GetSales(ctx, productID)
+	if err != nil {
+		return payload.GetProductPricesResp{}, err
+	}
+
+	return payload.GetProductPricesResp{Prices: prices, Sales: sales}, nil
+}
+
+// SchedulePrice changes the regular price of the product at a later time. The
+// scheduler applies it to the product once it is due.
+func (s *productService) SchedulePrice(ctx context.Context, req payload.ScheduleProductPriceReq) (res model.ProductPrice, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.SchedulePrice")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if !req.EffectiveFrom.After(time.Now()) {
+		return model.ProductPrice{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "effective_from must be in the future")
+	}
+
+	product, err := s.getProduct(ctx, req.ProductID.String())
+	if err != nil {
+		return model.ProductPrice{}, err
+	}
+
+	price := model.ProductPrice{
+		ProductID:     product.ID,
+		Price:         req.Price,
+		EffectiveFrom: req.EffectiveFrom,
+	}
+	if err := s.priceRepo.CreatePrice(ctx, &price); err != nil {
+		return model.ProductPrice{}, err
+	}
+
+	return price, nil
+}
+
+// CancelScheduledPrice drops a price change that is not in effect yet, prices
+// already in effect stay in the history.
+func (s *productService) CancelScheduledPrice(ctx context.Context, productID, priceID string) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.CancelScheduledPrice")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	return s.priceRepo.DeleteScheduledPrice(ctx, productID, priceID, time.Now())
+}
+
+// CreateSale sells the product at a sale price for a while. Sales of a product
+// may not overlap, so at most one sale price applies at a time.
+func (s *productService) CreateSale(ctx context.Context, req payload.CreateProductSaleReq) (res model.ProductSale, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.CreateSale")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if !req.EndsAt.After(req.StartsAt) {
+		return model.ProductSale{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "ends_at must be after starts_at")
+	}
+	if !req.EndsAt.After(time.Now()) {
+		return model.ProductSale{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "ends_at must be in the future")
+	}
+
+	product, err := s.getProduct(ctx, req.ProductID.String())
+	if err != nil {
+		return model.ProductSale{}, err
+	}
+
+	sales, err := s.priceRepo.GetSales(ctx, product.ID.String())
+	if err != nil {
+		return model.ProductSale{}, err
+	}
+	for _, sale := range sales {
+		if sale.StartsAt.Before(req.EndsAt) && sale.EndsAt.After(req.StartsAt) {
+			return model.ProductSale{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "sale overlaps another sale of the product")
+		}
+	}
+
+	sale := model.ProductSale{
+		ProductID: product.ID,
+		Price:     req.Price,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+	}
+	if err := s.priceRepo.CreateSale(ctx, &sale); err != nil {
+		return model.ProductSale{}, err
+	}
+
+	return sale, nil
+}
+
+// EndSale cancels a sale that has not started, and ends a running one now.
+// Sales that are over stay as they were.
+func (s *productService) EndSale(ctx context.Context, productID, saleID string) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.EndSale")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	sale, err := s.priceRepo.GetSaleByID(ctx, productID, saleID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	switch {
+	case !sale.EndsAt.After(now):
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "sale has already ended")
+	case sale.StartsAt.After(now):
+		return s.priceRepo.DeleteSale(ctx, saleID)
+	default:
+		return s.priceRepo.UpdateSaleEndsAt(ctx, saleID, now)
+	}
+}
+
+// GetEffectivePrice returns the price the product, or one of its variants,
+// sells for at the given time: its regular price then, or the sale price when
+// a sale runs. A variant with its own price always sells for it.
+func (s *productService) GetEffectivePrice(ctx context.Context, req payload.GetEffectivePriceReq) (res payload.GetEffectivePriceResp, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.GetEffectivePrice")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	at := req.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	product, err := s.productRepo.GetProductByID(ctx, req.ProductID.String())
+	if err != nil {
+		return payload.GetEffectivePriceResp{}, err
+	}
+
+	res = payload.GetEffectivePriceResp{
+		ProductID: product.ID,
+		At:        at,
+	}
+
+	if req.VariantID != "" {
+		var variant model.ProductVariant
+		for _, v := range product.Variants {
+			if v.ID.String() == req.VariantID {
+				variant = v
+			}
+		}
+		if variant.ID == uuid.Nil {
+			return payload.GetEffectivePriceResp{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product variant not found")
+		}
+		res.VariantID = &variant.ID
+		if variant.Price != nil {
+			res.RegularPrice = *variant.Price
+			res.Price = *variant.Price
+			return res, nil
+		}
+	}
+
+	res.RegularPrice = product.Price
+	price, err := s.priceRepo.GetPriceAt(ctx, product.ID.String(), at)
+	switch {
+	case err == nil:
+		res.RegularPrice = price.Price
+	case apperr.ErrCode(err) != apperr.CodeHTTPNotFound:
+		return payload.GetEffectivePriceResp{}, err
+	}
+	res.Price = res.RegularPrice
+
+	sale, err := s.priceRepo.GetSaleAt(ctx, product.ID.String(), at)
+	switch {
+	case err == nil:
+		res.SalePrice = &sale.Price
+		res.Price = sale.Price
+	case apperr.ErrCode(err) != apperr.CodeHTTPNotFound:
+		return payload.GetEffectivePriceResp{}, err
+	}
+
+	return res, nil
+}
+
+// ApplyScheduledPrices sets the price of the products whose scheduled price
+// change is due.
+func (s *productService) ApplyScheduledPrices(ctx context.Context) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.ApplyScheduledPrices")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	return s.priceRepo.ApplyScheduledPrices(ctx, time.Now())
+}
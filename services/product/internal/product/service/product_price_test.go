@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	productRepoMock "github.com/alifmufthi91/ecommerce-system/services/product/internal/product/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSchedulePrice(t *testing.T) {
+	type dependencyMocks struct {
+		productRepo *productRepoMock.ProductRepository
+		priceRepo   *productRepoMock.ProductPriceRepository
+	}
+
+	productID := uuid.New()
+	effectiveFrom := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		req      payload.ScheduleProductPriceReq
+		setup    func(m dependencyMocks)
+		wantCode apperr.Code
+	}{
+		{
+			name: "success",
+			req:  payload.ScheduleProductPriceReq{ProductID: productID, Price: 80, EffectiveFrom: effectiveFrom},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID, Price: 100}, nil)
+				m.priceRepo.On("CreatePrice", mock.Anything, &model.ProductPrice{ProductID: productID, Price: 80, EffectiveFrom: effectiveFrom}).
+					Return(nil)
+			},
+		},
+		{
+			name:     "error - effective_from in the past",
+			req:      payload.ScheduleProductPriceReq{ProductID: productID, Price: 80, EffectiveFrom: time.Now().Add(-time.Hour)},
+			setup:    func(m dependencyMocks) {},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - product not found",
+			req:  payload.ScheduleProductPriceReq{ProductID: productID, Price: 80, EffectiveFrom: effectiveFrom},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product not found"))
+			},
+			wantCode: apperr.CodeHTTPNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				productRepo: productRepoMock.NewProductRepository(t),
+				priceRepo:   productRepoMock.NewProductPriceRepository(t),
+			}
+			productSvc := productService{
+				productRepo: mocks.productRepo,
+				priceRepo:   mocks.priceRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			result, err := productSvc.SchedulePrice(context.Background(), tt.req)
+
+			// Then
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 80.0, result.Price)
+		})
+	}
+}
+
+func TestCreateSale(t *testing.T) {
+	type dependencyMocks struct {
+		productRepo *productRepoMock.ProductRepository
+		priceRepo   *productRepoMock.ProductPriceRepository
+	}
+
+	productID := uuid.New()
+	startsAt := time.Now().Add(24 * time.Hour)
+	endsAt := startsAt.Add(7 * 24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		req      payload.CreateProductSaleReq
+		setup    func(m dependencyMocks)
+		wantCode apperr.Code
+	}{
+		{
+			name: "success - after an earlier sale",
+			req:  payload.CreateProductSaleReq{ProductID: productID, Price: 70, StartsAt: startsAt, EndsAt: endsAt},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID, Price: 100}, nil)
+				m.priceRepo.On("GetSales", mock.Anything, productID.String()).
+					Return([]model.ProductSale{{ProductID: productID, Price: 60, StartsAt: startsAt.Add(-48 * time.Hour), EndsAt: startsAt}}, nil)
+				m.priceRepo.On("CreateSale", mock.Anything, &model.ProductSale{ProductID: productID, Price: 70, StartsAt: startsAt, EndsAt: endsAt}).
+					Return(nil)
+			},
+		},
+		{
+			name: "error - overlaps another sale",
+			req:  payload.CreateProductSaleReq{ProductID: productID, Price: 70, StartsAt: startsAt, EndsAt: endsAt},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID, Price: 100}, nil)
+				m.priceRepo.On("GetSales", mock.Anything, productID.String()).
+					Return([]model.ProductSale{{ProductID: productID, Price: 60, StartsAt: endsAt.Add(-time.Hour), EndsAt: endsAt.Add(time.Hour)}}, nil)
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name:     "error - ends before it starts",
+			req:      payload.CreateProductSaleReq{ProductID: productID, Price: 70, StartsAt: endsAt, EndsAt: startsAt},
+			setup:    func(m dependencyMocks) {},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name:     "error - already over",
+			req:      payload.CreateProductSaleReq{ProductID: productID, Price: 70, StartsAt: time.Now().Add(-48 * time.Hour), EndsAt: time.Now().Add(-24 * time.Hour)},
+			setup:    func(m dependencyMocks) {},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				productRepo: productRepoMock.NewProductRepository(t),
+				priceRepo:   productRepoMock.NewProductPriceRepository(t),
+			}
+			productSvc := productService{
+				productRepo: mocks.productRepo,
+				priceRepo:   mocks.priceRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			_, err := productSvc.CreateSale(context.Background(), tt.req)
+
+			// Then
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestEndSale(t *testing.T) {
+	productID := uuid.New()
+	saleID := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		sale     model.ProductSale
+		setup    func(m *productRepoMock.ProductPriceRepository)
+		wantCode apperr.Code
+	}{
+		{
+			name: "success - upcoming sale is deleted",
+			sale: model.ProductSale{ID: saleID, StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)},
+			setup: func(m *productRepoMock.ProductPriceRepository) {
+				m.On("DeleteSale", mock.Anything, saleID.String()).Return(nil)
+			},
+		},
+		{
+			name: "success - running sale ends now",
+			sale: model.ProductSale{ID: saleID, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+			setup: func(m *productRepoMock.ProductPriceRepository) {
+				m.On("UpdateSaleEndsAt", mock.Anything, saleID.String(), mock.MatchedBy(func(endsAt time.Time) bool {
+					return !endsAt.Before(now)
+				})).Return(nil)
+			},
+		},
+		{
+			name:     "error - sale is over",
+			sale:     model.ProductSale{ID: saleID, StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)},
+			setup:    func(m *productRepoMock.ProductPriceRepository) {},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			priceRepo := productRepoMock.NewProductPriceRepository(t)
+			priceRepo.On("GetSaleByID", mock.Anything, productID.String(), saleID.String()).Return(tt.sale, nil)
+			tt.setup(priceRepo)
+			productSvc := productService{priceRepo: priceRepo}
+
+			// When
+			err := productSvc.EndSale(context.Background(), productID.String(), saleID.String())
+
+			// Then
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGetEffectivePrice(t *testing.T) {
+	type dependencyMocks struct {
+		productRepo *productRepoMock.ProductRepository
+		priceRepo   *productRepoMock.ProductPriceRepository
+	}
+
+	productID := uuid.New()
+	variantID := uuid.New()
+	pricedVariantID := uuid.New()
+	variantPrice := 130.0
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	salePrice := 70.0
+
+	product := model.Product{
+		ID:    productID,
+		Price: 100,
+		Variants: []model.ProductVariant{
+			{ID: variantID, ProductID: productID, SKU: "SHIRT-M"},
+			{ID: pricedVariantID, ProductID: productID, SKU: "SHIRT-XXL", Price: &variantPrice},
+		},
+	}
+	notFound := apperr.NewWithCode(apperr.CodeHTTPNotFound, "not found")
+
+	tests := []struct {
+		name     string
+		req      payload.GetEffectivePriceReq
+		setup    func(m dependencyMocks)
+		want     payload.GetEffectivePriceResp
+		wantCode apperr.Code
+	}{
+		{
+			name: "success - regular price at the time",
+			req:  payload.GetEffectivePriceReq{ProductID: productID, At: at},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.priceRepo.On("GetPriceAt", mock.Anything, productID.String(), at).
+					Return(model.ProductPrice{ProductID: productID, Price: 90}, nil)
+				m.priceRepo.On("GetSaleAt", mock.Anything, productID.String(), at).
+					Return(model.ProductSale{}, notFound)
+			},
+			want: payload.GetEffectivePriceResp{ProductID: productID, At: at, RegularPrice: 90, Price: 90},
+		},
+		{
+			name: "success - sale price of a variant",
+			req:  payload.GetEffectivePriceReq{ProductID: productID, VariantID: variantID.String(), At: at},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.priceRepo.On("GetPriceAt", mock.Anything, productID.String(), at).
+					Return(model.ProductPrice{ProductID: productID, Price: 90}, nil)
+				m.priceRepo.On("GetSaleAt", mock.Anything, productID.String(), at).
+					Return(model.ProductSale{ProductID: productID, Price: salePrice}, nil)
+			},
+			want: payload.GetEffectivePriceResp{ProductID: productID, VariantID: &variantID, At: at, RegularPrice: 90, SalePrice: &salePrice, Price: salePrice},
+		},
+		{
+			name: "success - no price history",
+			req:  payload.GetEffectivePriceReq{ProductID: productID, At: at},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.priceRepo.On("GetPriceAt", mock.Anything, productID.String(), at).
+					Return(model.ProductPrice{}, notFound)
+				m.priceRepo.On("GetSaleAt", mock.Anything, productID.String(), at).
+					Return(model.ProductSale{}, notFound)
+			},
+			want: payload.GetEffectivePriceResp{ProductID: productID, At: at, RegularPrice: 100, Price: 100},
+		},
+		{
+			name: "success - variant with its own price",
+			req:  payload.GetEffectivePriceReq{ProductID: productID, VariantID: pricedVariantID.String(), At: at},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+			},
+			want: payload.GetEffectivePriceResp{ProductID: productID, VariantID: &pricedVariantID, At: at, RegularPrice: variantPrice, Price: variantPrice},
+		},
+		{
+			name: "error - variant not found",
+			req:  payload.GetEffectivePriceReq{ProductID: productID, VariantID: uuid.New().String(), At: at},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+			},
+			wantCode: apperr.CodeHTTPNotFound,
+		},
+		{
+			name: "error - failed to get price",
+			req:  payload.GetEffectivePriceReq{ProductID: productID, At: at},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.priceRepo.On("GetPriceAt", mock.Anything, productID.String(), at).
+					Return(model.ProductPrice{}, apperr.NewWithCode(apperr.CodeSQLRead, "failed to get product price"))
+			},
+			wantCode: apperr.CodeSQLRead,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				productRepo: productRepoMock.NewProductRepository(t),
+				priceRepo:   productRepoMock.NewProductPriceRepository(t),
+			}
+			productSvc := productService{
+				productRepo: mocks.productRepo,
+				priceRepo:   mocks.priceRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			result, err := productSvc.GetEffectivePrice(context.Background(), tt.req)
+
+			// Then
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
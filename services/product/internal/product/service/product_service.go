@@ -2,34 +2,84 @@ package service
 
 import (
 	"context"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/alifmufthi91/ecommerce-system/services/product/config"
 	warehouseservice "github.com/alifmufthi91/ecommerce-system/services/product/external/warehouse_service"
+	categoryservice "github.com/alifmufthi91/ecommerce-system/services/product/internal/category/service"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/storage"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/repository"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
 )
 
 //go:generate mockery --name=ProductService --case underscore
 type ProductService interface {
 	CreateProduct(ctx context.Context, req payload.CreateProductReq) error
-	GetProducts(ctx context.Context, token string) ([]payload.GetProductsResp, error)
+	GetProducts(ctx context.Context, req payload.GetProductsReq) ([]payload.GetProductsResp, int64, error)
 	GetProductByID(ctx context.Context, productID string) (model.Product, error)
+	UpdateProduct(ctx context.Context, req payload.UpdateProductReq) (model.Product, error)
+	PatchProduct(ctx context.Context, req payload.PatchProductReq) (model.Product, error)
+	ArchiveProduct(ctx context.Context, productID string) error
+	DeleteProduct(ctx context.Context, productID string) error
+	SearchProducts(ctx context.Context, req payload.SearchProductsReq) (payload.SearchProductsResp, int64, error)
+	CreateVariant(ctx context.Context, req payload.CreateProductVariantReq) (model.ProductVariant, error)
+	UpdateVariant(ctx context.Context, req payload.UpdateProductVariantReq) (model.ProductVariant, error)
+	DeleteVariant(ctx context.Context, req payload.DeleteProductVariantReq) error
+	UploadImage(ctx context.Context, req payload.UploadProductImageReq) (model.ProductImage, error)
+	ReorderImages(ctx context.Context, req payload.ReorderProductImagesReq) ([]model.ProductImage, error)
+	DeleteImage(ctx context.Context, productID, imageID string) error
+	GetPrices(ctx context.Context, productID string) (payload.GetProductPricesResp, error)
+	SchedulePrice(ctx context.Context, req payload.ScheduleProductPriceReq) (model.ProductPrice, error)
+	CancelScheduledPrice(ctx context.Context, productID, priceID string) error
+	CreateSale(ctx context.Context, req payload.CreateProductSaleReq) (model.ProductSale, error)
+	EndSale(ctx context.Context, productID, saleID string) error
+	GetEffectivePrice(ctx context.Context, req payload.GetEffectivePriceReq) (payload.GetEffectivePriceResp, error)
+	ApplyScheduledPrices(ctx context.Context) error
+	ImportProducts(ctx context.Context, req payload.ImportProductsReq, file io.Reader) (payload.ImportProductsResult, error)
+	ExportProducts(ctx context.Context, req payload.ExportProductsReq, w io.Writer) error
 }
 
 type productService struct {
 	config       *config.Config
+	db           *gorm.DB
 	warehouseSvc warehouseservice.IWarehouseSvc
+	categorySvc  categoryservice.CategoryService
 	productRepo  repository.ProductRepository
+	variantRepo  repository.ProductVariantRepository
+	imageRepo    repository.ProductImageRepository
+	priceRepo    repository.ProductPriceRepository
+	storage      storage.Storage
 }
 
-func NewProductService(config *config.Config, whSvc warehouseservice.IWarehouseSvc, productRepo repository.ProductRepository) ProductService {
+func NewProductService(
+	config *config.Config,
+	db *gorm.DB,
+	whSvc warehouseservice.IWarehouseSvc,
+	categorySvc categoryservice.CategoryService,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	imageRepo repository.ProductImageRepository,
+	priceRepo repository.ProductPriceRepository,
+	storage storage.Storage,
+) ProductService {
 	return &productService{
 		config:       config,
+		db:           db,
 		warehouseSvc: whSvc,
+		categorySvc:  categorySvc,
 		productRepo:  productRepo,
+		variantRepo:  variantRepo,
+		imageRepo:    imageRepo,
+		priceRepo:    priceRepo,
+		storage:      storage,
 	}
 }
 
@@ -43,19 +93,119 @@ func (s *productService) CreateProduct(ctx context.Context, req payload.CreatePr
 		}
 	}()
 
+	if err := s.validateCategory(ctx, req.CategoryID); err != nil {
+		return err
+	}
+
 	product := &model.Product{
 		Name:        req.Name,
 		Description: req.Description,
 		Price:       req.Price,
 		ShopID:      req.ShopID,
+		CategoryID:  req.CategoryID,
+	}
+	if req.SKU != "" {
+		if err := s.validateSKU(ctx, *product, req.SKU); err != nil {
+			return err
+		}
+		product.SKU = &req.SKU
 	}
+	tags := normalizeTags(req.Tags)
 
-	err = s.productRepo.CreateProduct(ctx, product)
+	if len(req.BundleItems) > 0 {
+		if err := s.validateBundleItems(ctx, req); err != nil {
+			return err
+		}
+	}
 
-	return err
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	if err := s.productRepo.WithTX(tx).CreateProduct(ctx, product); err != nil {
+		return err
+	}
+
+	// the first price starts the price history
+	if err := s.priceRepo.WithTX(tx).CreatePrice(ctx, &model.ProductPrice{
+		ProductID:     product.ID,
+		Price:         product.Price,
+		EffectiveFrom: product.CreatedAt,
+	}); err != nil {
+		return err
+	}
+
+	if len(req.BundleItems) > 0 {
+		items := make([]model.ProductBundleItem, 0, len(req.BundleItems))
+		for _, item := range req.BundleItems {
+			items = append(items, model.ProductBundleItem{
+				BundleID:    product.ID,
+				ComponentID: item.ComponentID,
+				Quantity:    item.Quantity,
+			})
+		}
+		if err := s.productRepo.WithTX(tx).CreateBundleItems(ctx, items); err != nil {
+			return err
+		}
+	}
+
+	if len(tags) > 0 {
+		if err := s.productRepo.WithTX(tx).ReplaceProductTags(ctx, product.ID, tags); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return nil
 }
 
-func (s *productService) GetProducts(ctx context.Context, token string) (result []payload.GetProductsResp, err error) {
+// validateBundleItems checks the components of a bundle are plain products of
+// the bundle's shop, without variants.
+func (s *productService) validateBundleItems(ctx context.Context, req payload.CreateProductReq) error {
+	componentIDs := make([]string, 0, len(req.BundleItems))
+	for _, item := range req.BundleItems {
+		componentIDs = append(componentIDs, item.ComponentID.String())
+	}
+
+	components, err := s.productRepo.GetProductsByIDs(ctx, componentIDs)
+	if err != nil {
+		return err
+	}
+
+	componentMap := make(map[string]model.Product, len(components))
+	for _, component := range components {
+		componentMap[component.ID.String()] = component
+	}
+
+	for _, componentID := range componentIDs {
+		component, ok := componentMap[componentID]
+		if !ok {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "bundle component "+componentID+" not found")
+		}
+		if !component.IsAvailable() {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "bundle component "+componentID+" is archived")
+		}
+		if component.IsBundle() {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "bundle component "+componentID+" is a bundle")
+		}
+		if component.HasVariants() {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "bundle component "+componentID+" has variants")
+		}
+		if component.ShopID != req.ShopID {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "bundle component "+componentID+" belongs to another shop")
+		}
+	}
+
+	return nil
+}
+
+// GetProducts returns a page of the products and the number of all listed
+// products, with the availability fetched for the page alone. Unless out of
+// stock products are included, products the warehouses keep no stock of are
+// left out.
+func (s *productService) GetProducts(ctx context.Context, req payload.GetProductsReq) (result []payload.GetProductsResp, total int64, err error) {
 	ctx, span := observ.GetTracer().Start(ctx, "productService.GetProducts")
 	defer span.End()
 	defer func() {
@@ -65,42 +215,92 @@ func (s *productService) GetProducts(ctx context.Context, token string) (result
 		}
 	}()
 
-	products, err := s.productRepo.GetProducts(ctx)
+	if req.CategoryID != "" {
+		req.CategoryIDIN, err = s.categorySvc.GetSubtreeIDs(ctx, req.CategoryID)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	req.Page, req.PageSize = req.Pagination()
+	products, total, err := s.productRepo.GetProducts(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	var availableStockMap map[string]int
+	if len(products) > 0 {
+		availableStockMap, err = s.availableStocks(ctx, products, req.Token)
+		if err != nil {
+			return nil, 0, err
+		}
 	}
 
-	var productIDs []string
-	var productIndexMap = make(map[string]int)
-	for i, product := range products {
-		productIDs = append(productIDs, product.ID.String())
-		productIndexMap[product.ID.String()] = i
+	result = make([]payload.GetProductsResp, 0, len(products))
+	for _, product := range products {
+		result = append(result, s.newGetProductsResp(product, availableStockMap))
 	}
 
-	availableStocks, err := s.warehouseSvc.GetStockAvailables(ctx, warehouseservice.GetStockAvailablesReq{
-		ProductIDIN: productIDs,
-		Token:       token,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	for _, stock := range availableStocks.Data {
-		if index, exists := productIndexMap[stock.ProductID]; exists {
-			result = append(result, payload.GetProductsResp{
-				ID:             products[index].ID,
-				Name:           products[index].Name,
-				Description:    products[index].Description,
-				Price:          products[index].Price,
-				ShopID:         products[index].ShopID,
-				AvailableStock: stock.AvailableStock,
-				CreatedAt:      products[index].CreatedAt,
-				UpdatedAt:      products[index].UpdatedAt,
-			})
+	return result, total, nil
+}
+
+func (s *productService) newGetProductsResp(product model.Product, availableStockMap map[string]int) payload.GetProductsResp {
+	s.setImageURLs(product.Images)
+	resp := payload.GetProductsResp{
+		ID:             product.ID,
+		Name:           product.Name,
+		Description:    product.Description,
+		Price:          product.Price,
+		ShopID:         product.ShopID,
+		CategoryID:     product.CategoryID,
+		SKU:            product.SKU,
+		AvailableStock: productAvailableStock(product, availableStockMap),
+		BundleItems:    product.BundleItems,
+		Tags:           product.Tags,
+		Images:         product.Images,
+		CreatedAt:      product.CreatedAt,
+		UpdatedAt:      product.UpdatedAt,
+	}
+	for _, variant := range product.Variants {
+		resp.Variants = append(resp.Variants, payload.GetProductsRespVariant{
+			ID:             variant.ID,
+			SKU:            variant.SKU,
+			Options:        variant.Options,
+			Price:          variant.EffectivePrice(product),
+			AvailableStock: availableStockMap[variant.ID.String()],
+		})
+	}
+	return resp
+}
+
+// productAvailableStock is the available stock of the product. A product with
+// variants has the stock of all its variants.
+func productAvailableStock(product model.Product, availableStockMap map[string]int) int {
+	switch {
+	case product.IsBundle():
+		return bundleAvailableStock(product, availableStockMap)
+	case product.HasVariants():
+		var available int
+		for _, variant := range product.Variants {
+			available += max(availableStockMap[variant.ID.String()], 0)
 		}
+		return available
+	default:
+		return availableStockMap[product.ID.String()]
 	}
+}
 
-	return result, nil
+// bundleAvailableStock is how many whole bundles the available stock of the
+// components makes up.
+func bundleAvailableStock(bundle model.Product, availableStockMap map[string]int) int {
+	available := -1
+	for _, item := range bundle.BundleItems {
+		count := availableStockMap[item.ComponentID.String()] / item.Quantity
+		if available < 0 || count < available {
+			available = count
+		}
+	}
+	return max(available, 0)
 }
 
 func (s *productService) GetProductByID(ctx context.Context, productID string) (model.Product, error) {
@@ -114,5 +314,227 @@ func (s *productService) GetProductByID(ctx context.Context, productID string) (
 		return model.Product{}, err
 	}
 
+	s.setImageURLs(product.Images)
+	return product, nil
+}
+
+func (s *productService) UpdateProduct(ctx context.Context, req payload.UpdateProductReq) (res model.Product, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.UpdateProduct")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if err := s.validateCategory(ctx, req.CategoryID); err != nil {
+		return model.Product{}, err
+	}
+
+	product, err := s.getProduct(ctx, req.ID.String())
+	if err != nil {
+		return model.Product{}, err
+	}
+
+	priceChanged := product.Price != req.Price
+	product.Name = req.Name
+	product.Description = req.Description
+	product.Price = req.Price
+	product.CategoryID = req.CategoryID
+	if err := s.saveProduct(ctx, &product, priceChanged, normalizeTags(req.Tags), true); err != nil {
+		return model.Product{}, err
+	}
+
+	return product, nil
+}
+
+func (s *productService) PatchProduct(ctx context.Context, req payload.PatchProductReq) (res model.Product, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.PatchProduct")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if req.Name == nil && req.Description == nil && req.Price == nil && req.CategoryID == nil && !req.ClearCategory && req.SKU == nil && req.Tags == nil {
+		return model.Product{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "no product detail to update")
+	}
+
+	if err := s.validateCategory(ctx, req.CategoryID); err != nil {
+		return model.Product{}, err
+	}
+
+	product, err := s.getProduct(ctx, req.ID.String())
+	if err != nil {
+		return model.Product{}, err
+	}
+
+	if req.Name != nil {
+		product.Name = *req.Name
+	}
+	if req.Description != nil {
+		product.Description = *req.Description
+	}
+	priceChanged := req.Price != nil && *req.Price != product.Price
+	if req.Price != nil {
+		product.Price = *req.Price
+	}
+	if req.CategoryID != nil || req.ClearCategory {
+		product.CategoryID = req.CategoryID
+	}
+	if req.SKU != nil {
+		if err := s.validateSKU(ctx, product, *req.SKU); err != nil {
+			return model.Product{}, err
+		}
+		product.SKU = req.SKU
+	}
+	if err := s.saveProduct(ctx, &product, priceChanged, normalizeTags(req.Tags), req.Tags != nil); err != nil {
+		return model.Product{}, err
+	}
+
+	return product, nil
+}
+
+// ArchiveProduct stops the product from being listed and ordered. Its orders
+// stay readable.
+func (s *productService) ArchiveProduct(ctx context.Context, productID string) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.ArchiveProduct")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	product, err := s.getProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	if product.ArchivedAt != nil {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product is already archived")
+	}
+
+	now := time.Now()
+	product.ArchivedAt = &now
+	return s.productRepo.UpdateProduct(ctx, &product)
+}
+
+// DeleteProduct soft deletes the product, it is still found by ID for the
+// orders placed before.
+func (s *productService) DeleteProduct(ctx context.Context, productID string) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.DeleteProduct")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	return s.productRepo.DeleteProduct(ctx, productID)
+}
+
+// saveProduct updates the product. In the same transaction it records a
+// changed price in the price history, and replaces its tags when replaceTags
+// is set.
+func (s *productService) saveProduct(ctx context.Context, product *model.Product, priceChanged bool, tags []string, replaceTags bool) error {
+	if !priceChanged && !replaceTags {
+		return s.productRepo.UpdateProduct(ctx, product)
+	}
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	if err := s.productRepo.WithTX(tx).UpdateProduct(ctx, product); err != nil {
+		return err
+	}
+	if priceChanged {
+		if err := s.priceRepo.WithTX(tx).CreatePrice(ctx, &model.ProductPrice{
+			ProductID:     product.ID,
+			Price:         product.Price,
+			EffectiveFrom: time.Now(),
+		}); err != nil {
+			return err
+		}
+	}
+	if replaceTags {
+		if err := s.productRepo.WithTX(tx).ReplaceProductTags(ctx, product.ID, tags); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	if !replaceTags {
+		return nil
+	}
+	product.Tags = make([]model.ProductTag, 0, len(tags))
+	for _, tag := range tags {
+		product.Tags = append(product.Tags, model.ProductTag{ProductID: product.ID, Tag: tag})
+	}
+	return nil
+}
+
+// validateCategory checks the category of a product exists, no category is
+// valid too.
+func (s *productService) validateCategory(ctx context.Context, categoryID *uuid.UUID) error {
+	if categoryID == nil {
+		return nil
+	}
+
+	_, err := s.categorySvc.GetCategoryByID(ctx, categoryID.String())
+	if apperr.ErrCode(err) == apperr.CodeHTTPNotFound {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "category not found")
+	}
+	return err
+}
+
+// validateSKU checks no other product of the shop has the SKU.
+func (s *productService) validateSKU(ctx context.Context, product model.Product, sku string) error {
+	products, err := s.productRepo.GetProductsBySKUs(ctx, product.ShopID.String(), []string{sku})
+	if err != nil {
+		return err
+	}
+	for _, other := range products {
+		if other.ID != product.ID {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "sku "+sku+" already exists")
+		}
+	}
+	return nil
+}
+
+// normalizeTags lowercases and trims the tags, dropping empty and repeated
+// ones.
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// getProduct gets a product that is not deleted.
+func (s *productService) getProduct(ctx context.Context, productID string) (model.Product, error) {
+	product, err := s.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		return model.Product{}, err
+	}
+	if product.DeletedAt.Valid {
+		return model.Product{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product not found")
+	}
+	s.setImageURLs(product.Images)
 	return product, nil
 }
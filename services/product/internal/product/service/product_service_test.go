@@ -3,20 +3,36 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	warehouseservice "github.com/alifmufthi91/ecommerce-system/services/product/external/warehouse_service"
 	warehouseSvcMock "github.com/alifmufthi91/ecommerce-system/services/product/external/warehouse_service/mocks"
+	categorySvcMock "github.com/alifmufthi91/ecommerce-system/services/product/internal/category/service/mocks"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
 	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
 	productRepoMock "github.com/alifmufthi91/ecommerce-system/services/product/internal/product/repository/mocks"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func TestCreateProduct_ShouldSuccess(t *testing.T) {
 	type dependencyMocks struct {
+		db          sqlmock.Sqlmock
 		productRepo *productRepoMock.ProductRepository
+		priceRepo   *productRepoMock.ProductPriceRepository
+	}
+
+	shopID := uuid.New()
+	componentID := uuid.New()
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
 	}
 
 	tests := []struct {
@@ -35,8 +51,98 @@ func TestCreateProduct_ShouldSuccess(t *testing.T) {
 				ShopID:      uuid.New(),
 			},
 			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
 				m.productRepo.On("CreateProduct", mock.Anything, mock.Anything).
 					Return(nil)
+				m.priceRepo.On("WithTX", mock.Anything).
+					Return(m.priceRepo)
+				m.priceRepo.On("CreatePrice", mock.Anything, mock.MatchedBy(func(price *model.ProductPrice) bool {
+					return price.Price == 100.0
+				})).
+					Return(nil)
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - with tags",
+			req: payload.CreateProductReq{
+				Name:        "Test Product",
+				Description: "Test Description",
+				Price:       100.0,
+				ShopID:      shopID,
+				Tags:        []string{"Summer", "sale", "summer"},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("CreateProduct", mock.Anything, mock.Anything).
+					Return(nil)
+				m.priceRepo.On("WithTX", mock.Anything).
+					Return(m.priceRepo)
+				m.priceRepo.On("CreatePrice", mock.Anything, mock.Anything).
+					Return(nil)
+				m.productRepo.On("ReplaceProductTags", mock.Anything, mock.Anything, []string{"summer", "sale"}).
+					Return(nil)
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - with SKU",
+			req: payload.CreateProductReq{
+				Name:        "Test Product",
+				Description: "Test Description",
+				Price:       100.0,
+				ShopID:      shopID,
+				SKU:         "TP-001",
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductsBySKUs", mock.Anything, shopID.String(), []string{"TP-001"}).
+					Return(nil, nil)
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("CreateProduct", mock.Anything, mock.MatchedBy(func(product *model.Product) bool {
+					return product.SKU != nil && *product.SKU == "TP-001"
+				})).
+					Return(nil)
+				m.priceRepo.On("WithTX", mock.Anything).
+					Return(m.priceRepo)
+				m.priceRepo.On("CreatePrice", mock.Anything, mock.Anything).
+					Return(nil)
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - bundle",
+			req: payload.CreateProductReq{
+				Name:        "Starter Kit",
+				Description: "Test Description",
+				Price:       250.0,
+				ShopID:      shopID,
+				BundleItems: []payload.CreateBundleItemReq{
+					{ComponentID: componentID, Quantity: 3},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductsByIDs", mock.Anything, []string{componentID.String()}).
+					Return([]model.Product{{ID: componentID, ShopID: shopID}}, nil)
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("CreateProduct", mock.Anything, mock.Anything).
+					Return(nil)
+				m.priceRepo.On("WithTX", mock.Anything).
+					Return(m.priceRepo)
+				m.priceRepo.On("CreatePrice", mock.Anything, mock.Anything).
+					Return(nil)
+				m.productRepo.On("CreateBundleItems", mock.Anything, mock.MatchedBy(func(items []model.ProductBundleItem) bool {
+					return len(items) == 1 && items[0].ComponentID == componentID && items[0].Quantity == 3
+				})).
+					Return(nil)
+				m.db.ExpectCommit()
 			},
 		},
 	}
@@ -44,10 +150,14 @@ func TestCreateProduct_ShouldSuccess(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Given
 			mocks := dependencyMocks{
+				db:          mockDb.Mock,
 				productRepo: productRepoMock.NewProductRepository(t),
+				priceRepo:   productRepoMock.NewProductPriceRepository(t),
 			}
 			productSvc := productService{
+				db:          mockDb.Db,
 				productRepo: mocks.productRepo,
+				priceRepo:   mocks.priceRepo,
 			}
 
 			tt.setup(mocks)
@@ -58,13 +168,25 @@ func TestCreateProduct_ShouldSuccess(t *testing.T) {
 			// Then
 			assert.NoError(t, err)
 			mocks.productRepo.AssertExpectations(t)
+			mocks.priceRepo.AssertExpectations(t)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
 		})
 	}
 }
 
 func TestCreateProduct_ShouldReturnError(t *testing.T) {
 	type dependencyMocks struct {
+		db          sqlmock.Sqlmock
 		productRepo *productRepoMock.ProductRepository
+		priceRepo   *productRepoMock.ProductPriceRepository
+	}
+
+	shopID := uuid.New()
+	componentID := uuid.New()
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
 	}
 
 	tests := []struct {
@@ -74,6 +196,20 @@ func TestCreateProduct_ShouldReturnError(t *testing.T) {
 			m dependencyMocks,
 		)
 	}{
+		{
+			name: "error - SKU taken by another product of the shop",
+			req: payload.CreateProductReq{
+				Name:        "Test Product",
+				Description: "Test Description",
+				Price:       100.0,
+				ShopID:      shopID,
+				SKU:         "TP-001",
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductsBySKUs", mock.Anything, shopID.String(), []string{"TP-001"}).
+					Return([]model.Product{{ID: uuid.New(), ShopID: shopID}}, nil)
+			},
+		},
 		{
 			name: "error - failed to create product",
 			req: payload.CreateProductReq{
@@ -83,8 +219,150 @@ func TestCreateProduct_ShouldReturnError(t *testing.T) {
 				ShopID:      uuid.New(),
 			},
 			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
 				m.productRepo.On("CreateProduct", mock.Anything, mock.Anything).
 					Return(assert.AnError)
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - failed to create price history",
+			req: payload.CreateProductReq{
+				Name:        "Test Product",
+				Description: "Test Description",
+				Price:       100.0,
+				ShopID:      uuid.New(),
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("CreateProduct", mock.Anything, mock.Anything).
+					Return(nil)
+				m.priceRepo.On("WithTX", mock.Anything).
+					Return(m.priceRepo)
+				m.priceRepo.On("CreatePrice", mock.Anything, mock.Anything).
+					Return(assert.AnError)
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - bundle component not found",
+			req: payload.CreateProductReq{
+				Name:        "Starter Kit",
+				Description: "Test Description",
+				Price:       250.0,
+				ShopID:      shopID,
+				BundleItems: []payload.CreateBundleItemReq{
+					{ComponentID: componentID, Quantity: 3},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductsByIDs", mock.Anything, mock.Anything).
+					Return([]model.Product{}, nil)
+			},
+		},
+		{
+			name: "error - bundle component is a bundle",
+			req: payload.CreateProductReq{
+				Name:        "Starter Kit",
+				Description: "Test Description",
+				Price:       250.0,
+				ShopID:      shopID,
+				BundleItems: []payload.CreateBundleItemReq{
+					{ComponentID: componentID, Quantity: 3},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductsByIDs", mock.Anything, mock.Anything).
+					Return([]model.Product{{
+						ID:          componentID,
+						ShopID:      shopID,
+						BundleItems: []model.ProductBundleItem{{BundleID: componentID, ComponentID: uuid.New(), Quantity: 1}},
+					}}, nil)
+			},
+		},
+		{
+			name: "error - bundle component has variants",
+			req: payload.CreateProductReq{
+				Name:        "Starter Kit",
+				Description: "Test Description",
+				Price:       250.0,
+				ShopID:      shopID,
+				BundleItems: []payload.CreateBundleItemReq{
+					{ComponentID: componentID, Quantity: 3},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductsByIDs", mock.Anything, mock.Anything).
+					Return([]model.Product{{
+						ID:       componentID,
+						ShopID:   shopID,
+						Variants: []model.ProductVariant{{ID: uuid.New(), ProductID: componentID, SKU: "KIT-PART-M"}},
+					}}, nil)
+			},
+		},
+		{
+			name: "error - bundle component is archived",
+			req: payload.CreateProductReq{
+				Name:        "Starter Kit",
+				Description: "Test Description",
+				Price:       250.0,
+				ShopID:      shopID,
+				BundleItems: []payload.CreateBundleItemReq{
+					{ComponentID: componentID, Quantity: 3},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				archivedAt := time.Now()
+				m.productRepo.On("GetProductsByIDs", mock.Anything, mock.Anything).
+					Return([]model.Product{{ID: componentID, ShopID: shopID, ArchivedAt: &archivedAt}}, nil)
+			},
+		},
+		{
+			name: "error - bundle component of another shop",
+			req: payload.CreateProductReq{
+				Name:        "Starter Kit",
+				Description: "Test Description",
+				Price:       250.0,
+				ShopID:      shopID,
+				BundleItems: []payload.CreateBundleItemReq{
+					{ComponentID: componentID, Quantity: 3},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductsByIDs", mock.Anything, mock.Anything).
+					Return([]model.Product{{ID: componentID, ShopID: uuid.New()}}, nil)
+			},
+		},
+		{
+			name: "error - failed to create bundle items",
+			req: payload.CreateProductReq{
+				Name:        "Starter Kit",
+				Description: "Test Description",
+				Price:       250.0,
+				ShopID:      shopID,
+				BundleItems: []payload.CreateBundleItemReq{
+					{ComponentID: componentID, Quantity: 3},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductsByIDs", mock.Anything, mock.Anything).
+					Return([]model.Product{{ID: componentID, ShopID: shopID}}, nil)
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("CreateProduct", mock.Anything, mock.Anything).
+					Return(nil)
+				m.priceRepo.On("WithTX", mock.Anything).
+					Return(m.priceRepo)
+				m.priceRepo.On("CreatePrice", mock.Anything, mock.Anything).
+					Return(nil)
+				m.productRepo.On("CreateBundleItems", mock.Anything, mock.Anything).
+					Return(assert.AnError)
+				m.db.ExpectRollback()
 			},
 		},
 	}
@@ -92,10 +370,14 @@ func TestCreateProduct_ShouldReturnError(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Given
 			mocks := dependencyMocks{
+				db:          mockDb.Mock,
 				productRepo: productRepoMock.NewProductRepository(t),
+				priceRepo:   productRepoMock.NewProductPriceRepository(t),
 			}
 			productSvc := productService{
+				db:          mockDb.Db,
 				productRepo: mocks.productRepo,
+				priceRepo:   mocks.priceRepo,
 			}
 
 			tt.setup(mocks)
@@ -106,6 +388,7 @@ func TestCreateProduct_ShouldReturnError(t *testing.T) {
 			// Then
 			assert.Error(t, err)
 			mocks.productRepo.AssertExpectations(t)
+			mocks.priceRepo.AssertExpectations(t)
 		})
 	}
 }
@@ -114,21 +397,71 @@ func TestGetProducts_ShouldSuccess(t *testing.T) {
 	type dependencyMocks struct {
 		productRepo  *productRepoMock.ProductRepository
 		warehouseSvc *warehouseSvcMock.IWarehouseSvc
+		categorySvc  *categorySvcMock.CategoryService
 	}
 
 	productID1 := uuid.New()
 	productID2 := uuid.New()
+	bundleID := uuid.New()
+	variantID1 := uuid.New()
+	variantID2 := uuid.New()
+	categoryID := uuid.New()
+	subcategoryID := uuid.New()
 
 	tests := []struct {
 		name  string
+		req   payload.GetProductsReq
 		setup func(
 			m dependencyMocks,
 		)
+		want      map[uuid.UUID]int
+		wantTotal int64
 	}{
 		{
-			name: "success",
+			name: "success - stocked products by default, availability of the page only",
 			setup: func(m dependencyMocks) {
-				m.productRepo.On("GetProducts", mock.Anything).
+				m.productRepo.On("GetProducts", mock.Anything, payload.GetProductsReq{
+					Token:    "test-token",
+					Page:     1,
+					PageSize: payload.DefaultProductPageSize,
+				}).
+					Return([]model.Product{
+						{
+							ID:    productID1,
+							Name:  "Product One",
+							Price: 100.0,
+						},
+						{
+							ID:    productID2,
+							Name:  "Product Two",
+							Price: 200.0,
+						},
+					}, int64(2), nil)
+				m.warehouseSvc.On("GetStockAvailables", mock.Anything, warehouseservice.GetStockAvailablesReq{
+					ProductIDIN: []string{productID1.String(), productID2.String()},
+					Token:       "test-token",
+				}).
+					Return(warehouseservice.GetStockAvailablesResp{
+						Data: []warehouseservice.GetStockAvailablesData{
+							{
+								ProductID:      productID1.String(),
+								AvailableStock: 50,
+							},
+							{
+								ProductID:      productID2.String(),
+								AvailableStock: 0,
+							},
+						},
+					}, nil)
+			},
+			want:      map[uuid.UUID]int{productID1: 50, productID2: 0},
+			wantTotal: 2,
+		},
+		{
+			name: "success - out of stock products included with zero availability",
+			req:  payload.GetProductsReq{IncludeOutOfStock: true, Page: 2, PageSize: 2},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProducts", mock.Anything, mock.Anything).
 					Return([]model.Product{
 						{
 							ID:          productID1,
@@ -144,22 +477,134 @@ func TestGetProducts_ShouldSuccess(t *testing.T) {
 							Description: "Description for product two",
 							Price:       200.0,
 						},
-					}, nil)
+					}, int64(4), nil)
 
-				m.warehouseSvc.On("GetStockAvailables", mock.Anything, mock.Anything).
+				m.warehouseSvc.On("GetStockAvailables", mock.Anything, warehouseservice.GetStockAvailablesReq{
+					ProductIDIN: []string{productID1.String(), productID2.String()},
+					Token:       "test-token",
+				}).
 					Return(warehouseservice.GetStockAvailablesResp{
 						Data: []warehouseservice.GetStockAvailablesData{
 							{
 								ProductID:      productID1.String(),
 								AvailableStock: 50,
 							},
+						},
+					}, nil)
+			},
+			want:      map[uuid.UUID]int{productID1: 50, productID2: 0},
+			wantTotal: 4,
+		},
+		{
+			name: "success - bundle available from its components",
+			req:  payload.GetProductsReq{IncludeOutOfStock: true},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProducts", mock.Anything, mock.Anything).
+					Return([]model.Product{
+						{
+							ID:   productID1,
+							Name: "Product One",
+						},
+						{
+							ID:   bundleID,
+							Name: "Starter Kit",
+							BundleItems: []model.ProductBundleItem{
+								{BundleID: bundleID, ComponentID: productID1, Quantity: 3},
+								{BundleID: bundleID, ComponentID: productID2, Quantity: 2},
+							},
+						},
+					}, int64(2), nil)
+
+				m.warehouseSvc.On("GetStockAvailables", mock.Anything, warehouseservice.GetStockAvailablesReq{
+					ProductIDIN: []string{productID1.String(), bundleID.String(), productID2.String()},
+					Token:       "test-token",
+				}).
+					Return(warehouseservice.GetStockAvailablesResp{
+						Data: []warehouseservice.GetStockAvailablesData{
+							{
+								ProductID:      productID1.String(),
+								AvailableStock: 10,
+							},
 							{
 								ProductID:      productID2.String(),
-								AvailableStock: 30,
+								AvailableStock: 5,
+							},
+						},
+					}, nil)
+			},
+			want:      map[uuid.UUID]int{productID1: 10, bundleID: 2},
+			wantTotal: 2,
+		},
+		{
+			name: "success - product available from its variants",
+			req:  payload.GetProductsReq{IncludeOutOfStock: true},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProducts", mock.Anything, mock.Anything).
+					Return([]model.Product{
+						{
+							ID:   productID1,
+							Name: "Shirt",
+							Variants: []model.ProductVariant{
+								{ID: variantID1, ProductID: productID1, SKU: "SHIRT-M"},
+								{ID: variantID2, ProductID: productID1, SKU: "SHIRT-L"},
+							},
+						},
+					}, int64(1), nil)
+
+				m.warehouseSvc.On("GetStockAvailables", mock.Anything, warehouseservice.GetStockAvailablesReq{
+					ProductIDIN: []string{productID1.String(), variantID1.String(), variantID2.String()},
+					Token:       "test-token",
+				}).
+					Return(warehouseservice.GetStockAvailablesResp{
+						Data: []warehouseservice.GetStockAvailablesData{
+							{
+								ProductID:      variantID1.String(),
+								AvailableStock: 4,
+							},
+							{
+								ProductID:      variantID2.String(),
+								AvailableStock: 6,
+							},
+						},
+					}, nil)
+			},
+			want:      map[uuid.UUID]int{productID1: 10},
+			wantTotal: 1,
+		},
+		{
+			name: "success - products of a category subtree",
+			req:  payload.GetProductsReq{CategoryID: categoryID.String(), IncludeOutOfStock: true},
+			setup: func(m dependencyMocks) {
+				m.categorySvc.On("GetSubtreeIDs", mock.Anything, categoryID.String()).
+					Return([]string{categoryID.String(), subcategoryID.String()}, nil)
+				m.productRepo.On("GetProducts", mock.Anything, payload.GetProductsReq{
+					Token:             "test-token",
+					CategoryID:        categoryID.String(),
+					IncludeOutOfStock: true,
+					Page:              1,
+					PageSize:          payload.DefaultProductPageSize,
+					CategoryIDIN:      []string{categoryID.String(), subcategoryID.String()},
+				}).
+					Return([]model.Product{
+						{
+							ID:         productID1,
+							Name:       "Product One",
+							CategoryID: &subcategoryID,
+						},
+					}, int64(1), nil)
+
+				m.warehouseSvc.On("GetStockAvailables", mock.Anything, mock.Anything).
+					Return(warehouseservice.GetStockAvailablesResp{
+						Data: []warehouseservice.GetStockAvailablesData{
+							{
+								ProductID:      productID1.String(),
+								AvailableStock: 10,
 							},
 						},
 					}, nil)
 			},
+			want:      map[uuid.UUID]int{productID1: 10},
+			wantTotal: 1,
 		},
 	}
 	for _, tt := range tests {
@@ -168,20 +613,27 @@ func TestGetProducts_ShouldSuccess(t *testing.T) {
 			mocks := dependencyMocks{
 				productRepo:  productRepoMock.NewProductRepository(t),
 				warehouseSvc: warehouseSvcMock.NewIWarehouseSvc(t),
+				categorySvc:  categorySvcMock.NewCategoryService(t),
 			}
 			productSvc := productService{
 				productRepo:  mocks.productRepo,
 				warehouseSvc: mocks.warehouseSvc,
+				categorySvc:  mocks.categorySvc,
 			}
 
 			tt.setup(mocks)
 
 			// When
-			resp, err := productSvc.GetProducts(context.Background(), "test-token")
+			tt.req.Token = "test-token"
+			resp, total, err := productSvc.GetProducts(context.Background(), tt.req)
 
 			// Then
 			assert.NoError(t, err)
-			assert.Equal(t, 2, len(resp))
+			assert.Equal(t, tt.wantTotal, total)
+			assert.Equal(t, len(tt.want), len(resp))
+			for _, product := range resp {
+				assert.Equal(t, tt.want[product.ID], product.AvailableStock)
+			}
 			mocks.productRepo.AssertExpectations(t)
 		})
 	}
@@ -202,14 +654,14 @@ func TestGetProducts_ShouldReturnError(t *testing.T) {
 		{
 			name: "error - failed to get products",
 			setup: func(m dependencyMocks) {
-				m.productRepo.On("GetProducts", mock.Anything).
-					Return(nil, assert.AnError)
+				m.productRepo.On("GetProducts", mock.Anything, mock.Anything).
+					Return(nil, int64(0), assert.AnError)
 			},
 		},
 		{
 			name: "error - failed to get stock availables",
 			setup: func(m dependencyMocks) {
-				m.productRepo.On("GetProducts", mock.Anything).
+				m.productRepo.On("GetProducts", mock.Anything, mock.Anything).
 					Return([]model.Product{
 						{
 							ID:          uuid.New(),
@@ -218,7 +670,7 @@ func TestGetProducts_ShouldReturnError(t *testing.T) {
 							Description: "Description for product one",
 							Price:       100.0,
 						},
-					}, nil)
+					}, int64(1), nil)
 
 				m.warehouseSvc.On("GetStockAvailables", mock.Anything, mock.Anything).
 					Return(warehouseservice.GetStockAvailablesResp{}, assert.AnError)
@@ -240,7 +692,10 @@ func TestGetProducts_ShouldReturnError(t *testing.T) {
 			tt.setup(mocks)
 
 			// When
-			resp, err := productSvc.GetProducts(context.Background(), "test-token")
+			resp, _, err := productSvc.GetProducts(context.Background(), payload.GetProductsReq{
+				Token:             "test-token",
+				IncludeOutOfStock: true,
+			})
 
 			// Then
 			assert.Error(t, err)
@@ -344,3 +799,418 @@ func TestGetProductByID_ShouldReturnError(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateProduct_ShouldSuccess(t *testing.T) {
+	type dependencyMocks struct {
+		db          sqlmock.Sqlmock
+		categorySvc *categorySvcMock.CategoryService
+		productRepo *productRepoMock.ProductRepository
+		priceRepo   *productRepoMock.ProductPriceRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	categoryID := uuid.New()
+	shopID := uuid.New()
+	name := "Renamed Product"
+	price := 120.0
+	sku := "TP-001"
+
+	tests := []struct {
+		name   string
+		update func(svc productService) (model.Product, error)
+		setup  func(m dependencyMocks)
+		want   model.Product
+	}{
+		{
+			name: "success - update",
+			update: func(svc productService) (model.Product, error) {
+				return svc.UpdateProduct(context.Background(), payload.UpdateProductReq{
+					ID:          productID,
+					Name:        name,
+					Description: "New Description",
+					Price:       price,
+					CategoryID:  &categoryID,
+					Tags:        []string{" Summer ", "summer", "Sale"},
+				})
+			},
+			setup: func(m dependencyMocks) {
+				m.categorySvc.On("GetCategoryByID", mock.Anything, categoryID.String()).
+					Return(model.Category{ID: categoryID}, nil)
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID, Name: "Test Product", Description: "Test Description", Price: 100.0}, nil)
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("UpdateProduct", mock.Anything, mock.Anything).
+					Return(nil)
+				m.priceRepo.On("WithTX", mock.Anything).
+					Return(m.priceRepo)
+				m.priceRepo.On("CreatePrice", mock.Anything, mock.MatchedBy(func(p *model.ProductPrice) bool {
+					return p.ProductID == productID && p.Price == price
+				})).
+					Return(nil)
+				m.productRepo.On("ReplaceProductTags", mock.Anything, productID, []string{"summer", "sale"}).
+					Return(nil)
+				m.db.ExpectCommit()
+			},
+			want: model.Product{
+				ID:          productID,
+				CategoryID:  &categoryID,
+				Name:        name,
+				Description: "New Description",
+				Price:       price,
+				Tags:        []model.ProductTag{{ProductID: productID, Tag: "summer"}, {ProductID: productID, Tag: "sale"}},
+			},
+		},
+		{
+			name: "success - patch",
+			update: func(svc productService) (model.Product, error) {
+				return svc.PatchProduct(context.Background(), payload.PatchProductReq{
+					ID:    productID,
+					Name:  &name,
+					Price: &price,
+				})
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID, Name: "Test Product", Description: "Test Description", Price: 100.0}, nil)
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("UpdateProduct", mock.Anything, mock.Anything).
+					Return(nil)
+				m.priceRepo.On("WithTX", mock.Anything).
+					Return(m.priceRepo)
+				m.priceRepo.On("CreatePrice", mock.Anything, mock.MatchedBy(func(p *model.ProductPrice) bool {
+					return p.ProductID == productID && p.Price == price
+				})).
+					Return(nil)
+				m.db.ExpectCommit()
+			},
+			want: model.Product{ID: productID, Name: name, Description: "Test Description", Price: price},
+		},
+		{
+			name: "success - patch same price",
+			update: func(svc productService) (model.Product, error) {
+				return svc.PatchProduct(context.Background(), payload.PatchProductReq{
+					ID:    productID,
+					Price: &price,
+				})
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID, Price: price}, nil)
+				m.productRepo.On("UpdateProduct", mock.Anything, mock.Anything).
+					Return(nil)
+			},
+			want: model.Product{ID: productID, Price: price},
+		},
+		{
+			name: "success - patch SKU",
+			update: func(svc productService) (model.Product, error) {
+				return svc.PatchProduct(context.Background(), payload.PatchProductReq{
+					ID:  productID,
+					SKU: &sku,
+				})
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID, ShopID: shopID}, nil)
+				m.productRepo.On("GetProductsBySKUs", mock.Anything, shopID.String(), []string{sku}).
+					Return([]model.Product{{ID: productID, ShopID: shopID, SKU: &sku}}, nil)
+				m.productRepo.On("UpdateProduct", mock.Anything, mock.Anything).
+					Return(nil)
+			},
+			want: model.Product{ID: productID, ShopID: shopID, SKU: &sku},
+		},
+		{
+			name: "success - patch clears category",
+			update: func(svc productService) (model.Product, error) {
+				return svc.PatchProduct(context.Background(), payload.PatchProductReq{
+					ID:            productID,
+					ClearCategory: true,
+				})
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID, CategoryID: &categoryID}, nil)
+				m.productRepo.On("UpdateProduct", mock.Anything, mock.MatchedBy(func(p *model.Product) bool {
+					return p.CategoryID == nil
+				})).
+					Return(nil)
+			},
+			want: model.Product{ID: productID},
+		},
+		{
+			name: "success - patch clears tags",
+			update: func(svc productService) (model.Product, error) {
+				return svc.PatchProduct(context.Background(), payload.PatchProductReq{
+					ID:   productID,
+					Tags: []string{},
+				})
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID, Tags: []model.ProductTag{{ProductID: productID, Tag: "summer"}}}, nil)
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("UpdateProduct", mock.Anything, mock.Anything).
+					Return(nil)
+				m.productRepo.On("ReplaceProductTags", mock.Anything, productID, []string{}).
+					Return(nil)
+				m.db.ExpectCommit()
+			},
+			want: model.Product{ID: productID, Tags: []model.ProductTag{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:          mockDb.Mock,
+				categorySvc: categorySvcMock.NewCategoryService(t),
+				productRepo: productRepoMock.NewProductRepository(t),
+				priceRepo:   productRepoMock.NewProductPriceRepository(t),
+			}
+			productSvc := productService{
+				db:          mockDb.Db,
+				categorySvc: mocks.categorySvc,
+				productRepo: mocks.productRepo,
+				priceRepo:   mocks.priceRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			result, err := tt.update(productSvc)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+			mocks.productRepo.AssertExpectations(t)
+			mocks.priceRepo.AssertExpectations(t)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateProduct_ShouldReturnError(t *testing.T) {
+	type dependencyMocks struct {
+		db          sqlmock.Sqlmock
+		categorySvc *categorySvcMock.CategoryService
+		productRepo *productRepoMock.ProductRepository
+		priceRepo   *productRepoMock.ProductPriceRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	categoryID := uuid.New()
+	shopID := uuid.New()
+	price := 120.0
+	sku := "TP-001"
+
+	tests := []struct {
+		name   string
+		update func(svc productService) (model.Product, error)
+		setup  func(m dependencyMocks)
+	}{
+		{
+			name: "error - product not found",
+			update: func(svc productService) (model.Product, error) {
+				return svc.UpdateProduct(context.Background(), payload.UpdateProductReq{ID: productID, Name: "Test Product", Description: "Test Description", Price: price})
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{}, assert.AnError)
+			},
+		},
+		{
+			name: "error - product is deleted",
+			update: func(svc productService) (model.Product, error) {
+				return svc.PatchProduct(context.Background(), payload.PatchProductReq{ID: productID, Price: &price})
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}, nil)
+			},
+		},
+		{
+			name: "error - category not found",
+			update: func(svc productService) (model.Product, error) {
+				return svc.PatchProduct(context.Background(), payload.PatchProductReq{ID: productID, CategoryID: &categoryID})
+			},
+			setup: func(m dependencyMocks) {
+				m.categorySvc.On("GetCategoryByID", mock.Anything, categoryID.String()).
+					Return(model.Category{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "category not found"))
+			},
+		},
+		{
+			name: "error - SKU taken by another product of the shop",
+			update: func(svc productService) (model.Product, error) {
+				return svc.PatchProduct(context.Background(), payload.PatchProductReq{ID: productID, SKU: &sku})
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID, ShopID: shopID}, nil)
+				m.productRepo.On("GetProductsBySKUs", mock.Anything, shopID.String(), []string{sku}).
+					Return([]model.Product{{ID: uuid.New(), ShopID: shopID, SKU: &sku}}, nil)
+			},
+		},
+		{
+			name: "error - nothing to patch",
+			update: func(svc productService) (model.Product, error) {
+				return svc.PatchProduct(context.Background(), payload.PatchProductReq{ID: productID})
+			},
+			setup: func(m dependencyMocks) {},
+		},
+		{
+			name: "error - failed to update product",
+			update: func(svc productService) (model.Product, error) {
+				return svc.PatchProduct(context.Background(), payload.PatchProductReq{ID: productID, Price: &price})
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID}, nil)
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("UpdateProduct", mock.Anything, mock.Anything).
+					Return(assert.AnError)
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - failed to record price",
+			update: func(svc productService) (model.Product, error) {
+				return svc.PatchProduct(context.Background(), payload.PatchProductReq{ID: productID, Price: &price})
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID}, nil)
+				m.db.ExpectBegin()
+				m.productRepo.On("WithTX", mock.Anything).
+					Return(m.productRepo)
+				m.productRepo.On("UpdateProduct", mock.Anything, mock.Anything).
+					Return(nil)
+				m.priceRepo.On("WithTX", mock.Anything).
+					Return(m.priceRepo)
+				m.priceRepo.On("CreatePrice", mock.Anything, mock.Anything).
+					Return(assert.AnError)
+				m.db.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:          mockDb.Mock,
+				categorySvc: categorySvcMock.NewCategoryService(t),
+				productRepo: productRepoMock.NewProductRepository(t),
+				priceRepo:   productRepoMock.NewProductPriceRepository(t),
+			}
+			productSvc := productService{
+				db:          mockDb.Db,
+				categorySvc: mocks.categorySvc,
+				productRepo: mocks.productRepo,
+				priceRepo:   mocks.priceRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			_, err := tt.update(productSvc)
+
+			// Then
+			assert.Error(t, err)
+			mocks.productRepo.AssertExpectations(t)
+			mocks.priceRepo.AssertExpectations(t)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestArchiveProduct(t *testing.T) {
+	type dependencyMocks struct {
+		productRepo *productRepoMock.ProductRepository
+	}
+
+	productID := uuid.New()
+	archivedAt := time.Now()
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID}, nil)
+				m.productRepo.On("UpdateProduct", mock.Anything, mock.MatchedBy(func(product *model.Product) bool {
+					return product.ArchivedAt != nil && !product.IsAvailable()
+				})).
+					Return(nil)
+			},
+		},
+		{
+			name: "error - already archived",
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{ID: productID, ArchivedAt: &archivedAt}, nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				productRepo: productRepoMock.NewProductRepository(t),
+			}
+			productSvc := productService{
+				productRepo: mocks.productRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := productSvc.ArchiveProduct(context.Background(), productID.String())
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mocks.productRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteProduct(t *testing.T) {
+	productID := uuid.New()
+
+	productRepo := productRepoMock.NewProductRepository(t)
+	productRepo.On("DeleteProduct", mock.Anything, productID.String()).
+		Return(nil)
+
+	productSvc := productService{
+		productRepo: productRepo,
+	}
+
+	err := productSvc.DeleteProduct(context.Background(), productID.String())
+
+	assert.NoError(t, err)
+}
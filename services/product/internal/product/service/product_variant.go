@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	warehouseservice "github.com/alifmufthi91/ecommerce-system/services/product/external/warehouse_service"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func (s *productService) CreateVariant(ctx context.Context, req payload.CreateProductVariantReq) (res model.ProductVariant, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.CreateVariant")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	product, err := s.getProduct(ctx, req.ProductID.String())
+	if err != nil {
+		return model.ProductVariant{}, err
+	}
+
+	variant := model.ProductVariant{
+		ProductID: product.ID,
+		SKU:       strings.TrimSpace(req.SKU),
+		Price:     req.Price,
+	}
+	variant.Options, err = normalizeVariantOptions(req.Options)
+	if err != nil {
+		return model.ProductVariant{}, err
+	}
+	if err := s.validateVariant(ctx, product, variant); err != nil {
+		return model.ProductVariant{}, err
+	}
+
+	// once it has variants the product is stocked by variant, so stock of
+	// the product itself could no longer be ordered
+	if !product.HasVariants() {
+		hasStock, err := s.hasStock(ctx, product.ID.String(), req.Token)
+		if err != nil {
+			return model.ProductVariant{}, err
+		}
+		if hasStock {
+			return model.ProductVariant{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product has stock of its own, take it out before adding variants")
+		}
+	}
+
+	if err := s.saveVariant(ctx, &variant, true); err != nil {
+		return model.ProductVariant{}, err
+	}
+
+	return variant, nil
+}
+
+func (s *productService) UpdateVariant(ctx context.Context, req payload.UpdateProductVariantReq) (res model.ProductVariant, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.UpdateVariant")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	product, err := s.getProduct(ctx, req.ProductID.String())
+	if err != nil {
+		return model.ProductVariant{}, err
+	}
+
+	var variant model.ProductVariant
+	for _, v := range product.Variants {
+		if v.ID == req.ID {
+			variant = v
+		}
+	}
+	if variant.ID == uuid.Nil {
+		return model.ProductVariant{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product variant not found")
+	}
+
+	variant.SKU = strings.TrimSpace(req.SKU)
+	variant.Price = req.Price
+	variant.Options, err = normalizeVariantOptions(req.Options)
+	if err != nil {
+		return model.ProductVariant{}, err
+	}
+	if err := s.validateVariant(ctx, product, variant); err != nil {
+		return model.ProductVariant{}, err
+	}
+
+	if err := s.saveVariant(ctx, &variant, false); err != nil {
+		return model.ProductVariant{}, err
+	}
+
+	return variant, nil
+}
+
+// DeleteVariant removes the variant, unless the warehouses still hold stock of
+// it that nothing could order anymore.
+func (s *productService) DeleteVariant(ctx context.Context, req payload.DeleteProductVariantReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.DeleteVariant")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	hasStock, err := s.hasStock(ctx, req.ID.String(), req.Token)
+	if err != nil {
+		return err
+	}
+	if hasStock {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "variant has stock, take it out before deleting the variant")
+	}
+
+	return s.variantRepo.DeleteVariant(ctx, req.ProductID.String(), req.ID.String())
+}
+
+// hasStock reports whether any warehouse holds stock of the product or
+// variant. Reserved stock and stock in warehouses that aren't active count, as
+// it is on hand all the same.
+func (s *productService) hasStock(ctx context.Context, productID, token string) (bool, error) {
+	stocks, err := s.warehouseSvc.GetStocks(ctx, warehouseservice.GetStocksReq{
+		ProductIDIN: []string{productID},
+		Token:       token,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, stock := range stocks.Data {
+		if stock.ProductID == productID && (stock.Quantity > 0 || stock.Reserved > 0) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validateVariant checks the variant can be sold. Bundles and their components
+// are stocked by product, so they can't have variants, and no two variants
+// share a SKU or a set of option values.
+func (s *productService) validateVariant(ctx context.Context, product model.Product, variant model.ProductVariant) error {
+	if product.IsBundle() {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "a bundle cannot have variants")
+	}
+
+	isComponent, err := s.productRepo.IsBundleComponent(ctx, product.ID.String())
+	if err != nil {
+		return err
+	}
+	if isComponent {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "a bundle component cannot have variants")
+	}
+
+	existing, err := s.variantRepo.GetVariantBySKU(ctx, variant.SKU)
+	if err != nil && apperr.ErrCode(err) != apperr.CodeHTTPNotFound {
+		return err
+	}
+	if err == nil && existing.ID != variant.ID {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "sku "+variant.SKU+" already exists")
+	}
+
+	key := variantOptionsKey(variant.Options)
+	for _, other := range product.Variants {
+		if other.ID != variant.ID && variantOptionsKey(other.Options) == key {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "variant "+other.SKU+" has the same options")
+		}
+	}
+
+	return nil
+}
+
+func (s *productService) saveVariant(ctx context.Context, variant *model.ProductVariant, create bool) error {
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	if create {
+		if err := s.variantRepo.WithTX(tx).CreateVariant(ctx, variant); err != nil {
+			return err
+		}
+	} else {
+		if err := s.variantRepo.WithTX(tx).UpdateVariant(ctx, variant); err != nil {
+			return err
+		}
+	}
+	if err := s.variantRepo.WithTX(tx).ReplaceVariantOptions(ctx, variant.ID, variant.Options); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+	return nil
+}
+
+// normalizeVariantOptions trims the options and lowercases their names, sorted
+// by name. An option can be given only once.
+func normalizeVariantOptions(options []payload.ProductVariantOptionReq) ([]model.ProductVariantOption, error) {
+	normalized := make([]model.ProductVariantOption, 0, len(options))
+	seen := make(map[string]bool, len(options))
+	for _, option := range options {
+		name := strings.ToLower(strings.TrimSpace(option.Name))
+		value := strings.TrimSpace(option.Value)
+		if name == "" || value == "" {
+			return nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "variant option name and value are required")
+		}
+		if seen[name] {
+			return nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "variant option "+name+" is given more than once")
+		}
+		seen[name] = true
+		normalized = append(normalized, model.ProductVariantOption{Name: name, Value: value})
+	}
+
+	sort.Slice(normalized, func(i, j int) bool {
+		return normalized[i].Name < normalized[j].Name
+	})
+	return normalized, nil
+}
+
+// variantOptionsKey identifies a set of option values, regardless of order
+// and case of the values.
+func variantOptionsKey(options []model.ProductVariantOption) string {
+	pairs := make([]string, 0, len(options))
+	for _, option := range options {
+		pairs = append(pairs, option.Name+"="+strings.ToLower(option.Value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\x00")
+}
@@ -0,0 +1,434 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	warehouseservice "github.com/alifmufthi91/ecommerce-system/services/product/external/warehouse_service"
+	warehouseSvcMock "github.com/alifmufthi91/ecommerce-system/services/product/external/warehouse_service/mocks"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	productRepoMock "github.com/alifmufthi91/ecommerce-system/services/product/internal/product/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateVariant(t *testing.T) {
+	type dependencyMocks struct {
+		db           sqlmock.Sqlmock
+		warehouseSvc *warehouseSvcMock.IWarehouseSvc
+		productRepo  *productRepoMock.ProductRepository
+		variantRepo  *productRepoMock.ProductVariantRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	variantID := uuid.New()
+	price := 25.0
+
+	product := model.Product{
+		ID: productID,
+		Variants: []model.ProductVariant{
+			{
+				ID:      variantID,
+				SKU:     "SHIRT-M-RED",
+				Options: []model.ProductVariantOption{{Name: "colour", Value: "red"}, {Name: "size", Value: "M"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		req      payload.CreateProductVariantReq
+		setup    func(m dependencyMocks)
+		want     model.ProductVariant
+		wantCode apperr.Code
+	}{
+		{
+			name: "success",
+			req: payload.CreateProductVariantReq{
+				ProductID: productID,
+				SKU:       " SHIRT-L-RED ",
+				Price:     &price,
+				Options: []payload.ProductVariantOptionReq{
+					{Name: "Size", Value: " L "},
+					{Name: "colour", Value: "red"},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.productRepo.On("IsBundleComponent", mock.Anything, productID.String()).Return(false, nil)
+				m.variantRepo.On("GetVariantBySKU", mock.Anything, "SHIRT-L-RED").
+					Return(model.ProductVariant{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product variant not found"))
+				m.db.ExpectBegin()
+				m.variantRepo.On("WithTX", mock.Anything).Return(m.variantRepo)
+				m.variantRepo.On("CreateVariant", mock.Anything, mock.Anything).Return(nil)
+				m.variantRepo.On("ReplaceVariantOptions", mock.Anything, uuid.Nil, []model.ProductVariantOption{
+					{Name: "colour", Value: "red"},
+					{Name: "size", Value: "L"},
+				}).Return(nil)
+				m.db.ExpectCommit()
+			},
+			want: model.ProductVariant{
+				ProductID: productID,
+				SKU:       "SHIRT-L-RED",
+				Price:     &price,
+				Options:   []model.ProductVariantOption{{Name: "colour", Value: "red"}, {Name: "size", Value: "L"}},
+			},
+		},
+		{
+			name: "success - first variant",
+			req: payload.CreateProductVariantReq{
+				ProductID: productID,
+				Token:     "test-token",
+				SKU:       "SHIRT-M",
+				Options:   []payload.ProductVariantOptionReq{{Name: "size", Value: "M"}},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(model.Product{ID: productID}, nil)
+				m.productRepo.On("IsBundleComponent", mock.Anything, productID.String()).Return(false, nil)
+				m.variantRepo.On("GetVariantBySKU", mock.Anything, "SHIRT-M").
+					Return(model.ProductVariant{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product variant not found"))
+				m.warehouseSvc.On("GetStocks", mock.Anything, warehouseservice.GetStocksReq{
+					ProductIDIN: []string{productID.String()},
+					Token:       "test-token",
+				}).Return(warehouseservice.GetStocksResp{
+					Data: []warehouseservice.GetStocksData{{ProductID: productID.String(), Quantity: 0}},
+				}, nil)
+				m.db.ExpectBegin()
+				m.variantRepo.On("WithTX", mock.Anything).Return(m.variantRepo)
+				m.variantRepo.On("CreateVariant", mock.Anything, mock.Anything).Return(nil)
+				m.variantRepo.On("ReplaceVariantOptions", mock.Anything, uuid.Nil, []model.ProductVariantOption{
+					{Name: "size", Value: "M"},
+				}).Return(nil)
+				m.db.ExpectCommit()
+			},
+			want: model.ProductVariant{
+				ProductID: productID,
+				SKU:       "SHIRT-M",
+				Options:   []model.ProductVariantOption{{Name: "size", Value: "M"}},
+			},
+		},
+		{
+			name: "error - first variant of a product with stock",
+			req: payload.CreateProductVariantReq{
+				ProductID: productID,
+				Token:     "test-token",
+				SKU:       "SHIRT-M",
+				Options:   []payload.ProductVariantOptionReq{{Name: "size", Value: "M"}},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(model.Product{ID: productID}, nil)
+				m.productRepo.On("IsBundleComponent", mock.Anything, productID.String()).Return(false, nil)
+				m.variantRepo.On("GetVariantBySKU", mock.Anything, "SHIRT-M").
+					Return(model.ProductVariant{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product variant not found"))
+				m.warehouseSvc.On("GetStocks", mock.Anything, mock.Anything).
+					Return(warehouseservice.GetStocksResp{
+						Data: []warehouseservice.GetStocksData{{ProductID: productID.String(), Quantity: 3}},
+					}, nil)
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - product is a bundle",
+			req: payload.CreateProductVariantReq{
+				ProductID: productID,
+				SKU:       "KIT-L",
+				Options:   []payload.ProductVariantOptionReq{{Name: "size", Value: "L"}},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(model.Product{
+					ID:          productID,
+					BundleItems: []model.ProductBundleItem{{BundleID: productID, ComponentID: uuid.New(), Quantity: 1}},
+				}, nil)
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - product is a bundle component",
+			req: payload.CreateProductVariantReq{
+				ProductID: productID,
+				SKU:       "SHIRT-L",
+				Options:   []payload.ProductVariantOptionReq{{Name: "size", Value: "L"}},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(model.Product{ID: productID}, nil)
+				m.productRepo.On("IsBundleComponent", mock.Anything, productID.String()).Return(true, nil)
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - sku already exists",
+			req: payload.CreateProductVariantReq{
+				ProductID: productID,
+				SKU:       "SHIRT-M-RED",
+				Options:   []payload.ProductVariantOptionReq{{Name: "size", Value: "L"}},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.productRepo.On("IsBundleComponent", mock.Anything, productID.String()).Return(false, nil)
+				m.variantRepo.On("GetVariantBySKU", mock.Anything, "SHIRT-M-RED").Return(product.Variants[0], nil)
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - same options as another variant",
+			req: payload.CreateProductVariantReq{
+				ProductID: productID,
+				SKU:       "SHIRT-M-RED-2",
+				Options: []payload.ProductVariantOptionReq{
+					{Name: "size", Value: "M"},
+					{Name: "Colour", Value: "Red"},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.productRepo.On("IsBundleComponent", mock.Anything, productID.String()).Return(false, nil)
+				m.variantRepo.On("GetVariantBySKU", mock.Anything, "SHIRT-M-RED-2").
+					Return(model.ProductVariant{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product variant not found"))
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - option given twice",
+			req: payload.CreateProductVariantReq{
+				ProductID: productID,
+				SKU:       "SHIRT-L",
+				Options: []payload.ProductVariantOptionReq{
+					{Name: "size", Value: "L"},
+					{Name: "SIZE", Value: "XL"},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - product not found",
+			req: payload.CreateProductVariantReq{
+				ProductID: productID,
+				SKU:       "SHIRT-L",
+				Options:   []payload.ProductVariantOptionReq{{Name: "size", Value: "L"}},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).
+					Return(model.Product{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "product not found"))
+			},
+			wantCode: apperr.CodeHTTPNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:           mockDb.Mock,
+				warehouseSvc: warehouseSvcMock.NewIWarehouseSvc(t),
+				productRepo:  productRepoMock.NewProductRepository(t),
+				variantRepo:  productRepoMock.NewProductVariantRepository(t),
+			}
+			productSvc := productService{
+				db:           mockDb.Db,
+				warehouseSvc: mocks.warehouseSvc,
+				productRepo:  mocks.productRepo,
+				variantRepo:  mocks.variantRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			result, err := productSvc.CreateVariant(context.Background(), tt.req)
+
+			// Then
+			if tt.wantCode != 0 {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateVariant(t *testing.T) {
+	type dependencyMocks struct {
+		db          sqlmock.Sqlmock
+		productRepo *productRepoMock.ProductRepository
+		variantRepo *productRepoMock.ProductVariantRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	variantID := uuid.New()
+
+	product := model.Product{
+		ID: productID,
+		Variants: []model.ProductVariant{
+			{
+				ID:        variantID,
+				ProductID: productID,
+				SKU:       "SHIRT-M",
+				Options:   []model.ProductVariantOption{{Name: "size", Value: "M"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		req      payload.UpdateProductVariantReq
+		setup    func(m dependencyMocks)
+		want     model.ProductVariant
+		wantCode apperr.Code
+	}{
+		{
+			name: "success - keeps its own sku and options",
+			req: payload.UpdateProductVariantReq{
+				ProductID: productID,
+				ID:        variantID,
+				SKU:       "SHIRT-M",
+				Options:   []payload.ProductVariantOptionReq{{Name: "size", Value: "M"}},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+				m.productRepo.On("IsBundleComponent", mock.Anything, productID.String()).Return(false, nil)
+				m.variantRepo.On("GetVariantBySKU", mock.Anything, "SHIRT-M").Return(product.Variants[0], nil)
+				m.db.ExpectBegin()
+				m.variantRepo.On("WithTX", mock.Anything).Return(m.variantRepo)
+				m.variantRepo.On("UpdateVariant", mock.Anything, mock.Anything).Return(nil)
+				m.variantRepo.On("ReplaceVariantOptions", mock.Anything, variantID, mock.Anything).Return(nil)
+				m.db.ExpectCommit()
+			},
+			want: product.Variants[0],
+		},
+		{
+			name: "error - variant of another product",
+			req: payload.UpdateProductVariantReq{
+				ProductID: productID,
+				ID:        uuid.New(),
+				SKU:       "SHIRT-M",
+				Options:   []payload.ProductVariantOptionReq{{Name: "size", Value: "M"}},
+			},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("GetProductByID", mock.Anything, productID.String()).Return(product, nil)
+			},
+			wantCode: apperr.CodeHTTPNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:          mockDb.Mock,
+				productRepo: productRepoMock.NewProductRepository(t),
+				variantRepo: productRepoMock.NewProductVariantRepository(t),
+			}
+			productSvc := productService{
+				db:          mockDb.Db,
+				productRepo: mocks.productRepo,
+				variantRepo: mocks.variantRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			result, err := productSvc.UpdateVariant(context.Background(), tt.req)
+
+			// Then
+			if tt.wantCode != 0 {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteVariant(t *testing.T) {
+	productID := uuid.New()
+	variantID := uuid.New()
+	req := payload.DeleteProductVariantReq{ProductID: productID, ID: variantID, Token: "test-token"}
+
+	tests := []struct {
+		name     string
+		setup    func(warehouseSvc *warehouseSvcMock.IWarehouseSvc, variantRepo *productRepoMock.ProductVariantRepository)
+		wantCode apperr.Code
+	}{
+		{
+			name: "success",
+			setup: func(warehouseSvc *warehouseSvcMock.IWarehouseSvc, variantRepo *productRepoMock.ProductVariantRepository) {
+				warehouseSvc.On("GetStocks", mock.Anything, warehouseservice.GetStocksReq{
+					ProductIDIN: []string{variantID.String()},
+					Token:       "test-token",
+				}).Return(warehouseservice.GetStocksResp{}, nil)
+				variantRepo.On("DeleteVariant", mock.Anything, productID.String(), variantID.String()).Return(nil)
+			},
+		},
+		{
+			name: "error - variant has stock",
+			setup: func(warehouseSvc *warehouseSvcMock.IWarehouseSvc, variantRepo *productRepoMock.ProductVariantRepository) {
+				warehouseSvc.On("GetStocks", mock.Anything, mock.Anything).
+					Return(warehouseservice.GetStocksResp{
+						Data: []warehouseservice.GetStocksData{{ProductID: variantID.String(), Quantity: 2}},
+					}, nil)
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - variant stock is all reserved",
+			setup: func(warehouseSvc *warehouseSvcMock.IWarehouseSvc, variantRepo *productRepoMock.ProductVariantRepository) {
+				warehouseSvc.On("GetStocks", mock.Anything, mock.Anything).
+					Return(warehouseservice.GetStocksResp{
+						Data: []warehouseservice.GetStocksData{{ProductID: variantID.String(), Quantity: 0, Reserved: 2}},
+					}, nil)
+			},
+			wantCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - variant not found",
+			setup: func(warehouseSvc *warehouseSvcMock.IWarehouseSvc, variantRepo *productRepoMock.ProductVariantRepository) {
+				warehouseSvc.On("GetStocks", mock.Anything, mock.Anything).
+					Return(warehouseservice.GetStocksResp{}, nil)
+				variantRepo.On("DeleteVariant", mock.Anything, productID.String(), variantID.String()).
+					Return(apperr.NewWithCode(apperr.CodeHTTPNotFound, "product variant not found"))
+			},
+			wantCode: apperr.CodeHTTPNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			warehouseSvc := warehouseSvcMock.NewIWarehouseSvc(t)
+			variantRepo := productRepoMock.NewProductVariantRepository(t)
+			productSvc := productService{warehouseSvc: warehouseSvc, variantRepo: variantRepo}
+
+			tt.setup(warehouseSvc, variantRepo)
+
+			// When
+			err := productSvc.DeleteVariant(context.Background(), req)
+
+			// Then
+			if tt.wantCode != 0 {
+				assert.Equal(t, tt.wantCode, apperr.ErrCode(err))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
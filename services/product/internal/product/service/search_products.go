@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+
+	warehouseservice "github.com/alifmufthi91/ecommerce-system/services/product/external/warehouse_service"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// SearchProducts returns a page of the products matching the search, best
+// match first, with the facet counts and the number of all matching products.
+func (s *productService) SearchProducts(ctx context.Context, req payload.SearchProductsReq) (result payload.SearchProductsResp, total int64, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "productService.SearchProducts")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if req.CategoryID != "" {
+		req.CategoryIDIN, err = s.categorySvc.GetSubtreeIDs(ctx, req.CategoryID)
+		if err != nil {
+			return payload.SearchProductsResp{}, 0, err
+		}
+	}
+
+	req.Page, req.PageSize = req.Pagination()
+	products, total, err := s.productRepo.SearchProducts(ctx, req)
+	if err != nil {
+		return payload.SearchProductsResp{}, 0, err
+	}
+
+	facets, err := s.productRepo.GetSearchFacets(ctx, req)
+	if err != nil {
+		return payload.SearchProductsResp{}, 0, err
+	}
+
+	var availableStockMap map[string]int
+	if len(products) > 0 {
+		availableStockMap, err = s.availableStocks(ctx, products, req.Token)
+		if err != nil {
+			return payload.SearchProductsResp{}, 0, err
+		}
+	}
+
+	result = payload.SearchProductsResp{
+		Products: make([]payload.GetProductsResp, 0, len(products)),
+		Facets:   facets,
+	}
+	for _, product := range products {
+		result.Products = append(result.Products, s.newGetProductsResp(product, availableStockMap))
+	}
+
+	return result, total, nil
+}
+
+// availableStocks returns the available stock of the products, of the
+// components of the bundles among them and of their variants.
+func (s *productService) availableStocks(ctx context.Context, products []model.Product, token string) (map[string]int, error) {
+	var productIDs []string
+	seen := make(map[string]bool)
+	for _, product := range products {
+		ids := []string{product.ID.String()}
+		for _, item := range product.BundleItems {
+			ids = append(ids, item.ComponentID.String())
+		}
+		for _, variant := range product.Variants {
+			ids = append(ids, variant.ID.String())
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				productIDs = append(productIDs, id)
+			}
+		}
+	}
+
+	availableStocks, err := s.warehouseSvc.GetStockAvailables(ctx, warehouseservice.GetStockAvailablesReq{
+		ProductIDIN: productIDs,
+		Token:       token,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	availableStockMap := make(map[string]int, len(availableStocks.Data))
+	for _, stock := range availableStocks.Data {
+		availableStockMap[stock.ProductID] = stock.AvailableStock
+	}
+	return availableStockMap, nil
+}
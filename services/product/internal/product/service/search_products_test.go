@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	warehouseservice "github.com/alifmufthi91/ecommerce-system/services/product/external/warehouse_service"
+	warehouseSvcMock "github.com/alifmufthi91/ecommerce-system/services/product/external/warehouse_service/mocks"
+	categorySvcMock "github.com/alifmufthi91/ecommerce-system/services/product/internal/category/service/mocks"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/product/internal/product/payload"
+	productRepoMock "github.com/alifmufthi91/ecommerce-system/services/product/internal/product/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSearchProducts(t *testing.T) {
+	type dependencyMocks struct {
+		productRepo  *productRepoMock.ProductRepository
+		warehouseSvc *warehouseSvcMock.IWarehouseSvc
+		categorySvc  *categorySvcMock.CategoryService
+	}
+
+	productID1 := uuid.New()
+	productID2 := uuid.New()
+	bundleID := uuid.New()
+	categoryID := uuid.New().String()
+	subcategoryID := uuid.New().String()
+
+	bundle := model.Product{
+		ID:   bundleID,
+		Name: "Bundle",
+		BundleItems: []model.ProductBundleItem{
+			{BundleID: bundleID, ComponentID: productID1, Quantity: 2},
+		},
+	}
+	variantID1 := uuid.New()
+	variantID2 := uuid.New()
+	withVariants := model.Product{
+		ID:   productID2,
+		Name: "Shirt",
+		Variants: []model.ProductVariant{
+			{ID: variantID1, ProductID: productID2, SKU: "SHIRT-M"},
+			{ID: variantID2, ProductID: productID2, SKU: "SHIRT-L"},
+		},
+	}
+	facets := payload.SearchProductsFacets{
+		Shops: []payload.FacetCount{{Value: uuid.New().String(), Count: 3}},
+	}
+
+	tests := []struct {
+		name      string
+		req       payload.SearchProductsReq
+		setup     func(m dependencyMocks)
+		want      []int
+		wantTotal int64
+		wantErr   bool
+	}{
+		{
+			name: "success",
+			req:  payload.SearchProductsReq{Query: "shirt", CategoryID: categoryID},
+			setup: func(m dependencyMocks) {
+				m.categorySvc.On("GetSubtreeIDs", mock.Anything, categoryID).
+					Return([]string{categoryID, subcategoryID}, nil)
+
+				m.productRepo.On("SearchProducts", mock.Anything, mock.MatchedBy(func(req payload.SearchProductsReq) bool {
+					return req.Page == 1 && req.PageSize == payload.DefaultSearchPageSize &&
+						assert.ObjectsAreEqual([]string{categoryID, subcategoryID}, req.CategoryIDIN)
+				})).Return([]model.Product{{ID: productID2}, bundle}, int64(3), nil)
+				m.productRepo.On("GetSearchFacets", mock.Anything, mock.Anything).Return(facets, nil)
+
+				m.warehouseSvc.On("GetStockAvailables", mock.Anything, warehouseservice.GetStockAvailablesReq{
+					ProductIDIN: []string{productID2.String(), bundleID.String(), productID1.String()},
+					Token:       "test-token",
+				}).Return(warehouseservice.GetStockAvailablesResp{
+					Data: []warehouseservice.GetStockAvailablesData{
+						{ProductID: productID1.String(), AvailableStock: 5},
+					},
+				}, nil)
+			},
+			want:      []int{0, 2},
+			wantTotal: 3,
+		},
+		{
+			name: "success - in stock only",
+			req:  payload.SearchProductsReq{InStock: true, Page: 2, PageSize: 1},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("SearchProducts", mock.Anything, mock.MatchedBy(func(req payload.SearchProductsReq) bool {
+					return req.InStock && req.Page == 2 && req.PageSize == 1
+				})).Return([]model.Product{bundle}, int64(2), nil)
+				m.productRepo.On("GetSearchFacets", mock.Anything, mock.Anything).Return(facets, nil)
+
+				m.warehouseSvc.On("GetStockAvailables", mock.Anything, warehouseservice.GetStockAvailablesReq{
+					ProductIDIN: []string{bundleID.String(), productID1.String()},
+					Token:       "test-token",
+				}).Return(warehouseservice.GetStockAvailablesResp{
+					Data: []warehouseservice.GetStockAvailablesData{
+						{ProductID: productID1.String(), AvailableStock: 5},
+					},
+				}, nil)
+			},
+			want:      []int{2},
+			wantTotal: 2,
+		},
+		{
+			name: "success - in stock with variants",
+			req:  payload.SearchProductsReq{InStock: true},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("SearchProducts", mock.Anything, mock.Anything).Return([]model.Product{withVariants}, int64(1), nil)
+				m.productRepo.On("GetSearchFacets", mock.Anything, mock.Anything).Return(facets, nil)
+
+				m.warehouseSvc.On("GetStockAvailables", mock.Anything, warehouseservice.GetStockAvailablesReq{
+					ProductIDIN: []string{productID2.String(), variantID1.String(), variantID2.String()},
+					Token:       "test-token",
+				}).Return(warehouseservice.GetStockAvailablesResp{
+					Data: []warehouseservice.GetStockAvailablesData{
+						{ProductID: variantID1.String(), AvailableStock: 0},
+						{ProductID: variantID2.String(), AvailableStock: 4},
+					},
+				}, nil)
+			},
+			want:      []int{4},
+			wantTotal: 1,
+		},
+		{
+			name: "success - no match",
+			req:  payload.SearchProductsReq{Query: "shirt"},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("SearchProducts", mock.Anything, mock.Anything).Return(nil, int64(0), nil)
+				m.productRepo.On("GetSearchFacets", mock.Anything, mock.Anything).Return(facets, nil)
+			},
+			want: []int{},
+		},
+		{
+			name: "error - failed to get subcategories",
+			req:  payload.SearchProductsReq{CategoryID: categoryID},
+			setup: func(m dependencyMocks) {
+				m.categorySvc.On("GetSubtreeIDs", mock.Anything, categoryID).Return(nil, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - failed to get stock availables",
+			req:  payload.SearchProductsReq{InStock: true},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("SearchProducts", mock.Anything, mock.Anything).Return([]model.Product{{ID: productID1}}, int64(1), nil)
+				m.productRepo.On("GetSearchFacets", mock.Anything, mock.Anything).Return(facets, nil)
+				m.warehouseSvc.On("GetStockAvailables", mock.Anything, mock.Anything).
+					Return(warehouseservice.GetStockAvailablesResp{}, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - failed to search products",
+			req:  payload.SearchProductsReq{Query: "shirt"},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("SearchProducts", mock.Anything, mock.Anything).Return(nil, int64(0), assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - failed to get facets",
+			req:  payload.SearchProductsReq{Query: "shirt"},
+			setup: func(m dependencyMocks) {
+				m.productRepo.On("SearchProducts", mock.Anything, mock.Anything).Return([]model.Product{{ID: productID1}}, int64(1), nil)
+				m.productRepo.On("GetSearchFacets", mock.Anything, mock.Anything).Return(payload.SearchProductsFacets{}, assert.AnError)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				productRepo:  productRepoMock.NewProductRepository(t),
+				warehouseSvc: warehouseSvcMock.NewIWarehouseSvc(t),
+				categorySvc:  categorySvcMock.NewCategoryService(t),
+			}
+			productSvc := productService{
+				productRepo:  mocks.productRepo,
+				warehouseSvc: mocks.warehouseSvc,
+				categorySvc:  mocks.categorySvc,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			tt.req.Token = "test-token"
+			resp, total, err := productSvc.SearchProducts(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, total)
+			assert.Equal(t, facets, resp.Facets)
+			assert.Len(t, resp.Products, len(tt.want))
+			for i, product := range resp.Products {
+				assert.Equal(t, tt.want[i], product.AvailableStock)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/_options"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/httpclient"
 	"github.com/spf13/cobra"
 )
 
@@ -55,7 +56,7 @@ func startServer(config *config.Config) {
 	)
 
 	// returned value can be used later when adding external connection like pubsub
-	_ = internal.InitModules(internal.InitOptions{
+	m := internal.InitModules(internal.InitOptions{
 		DefaultOptions: defaultOpt,
 	})
 
@@ -70,6 +71,8 @@ func startServer(config *config.Config) {
 		Handler: router,
 	}
 
+	go startScheduler(logger, m)
+
 	go func() {
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.Fatal("Failed to start server:", err)
@@ -105,11 +108,17 @@ func initDefaultOptions(config *config.Config) _options.DefaultOptions {
 		logger.Fatal("Failed to connect to the database:", err)
 	}
 
-	defaultOpts := _options.DefaultOptions{
-		Logger: logger,
-		Router: router,
-		Db:     db,
+	httpClient := httpclient.Init(httpclient.Options{
 		Config: config,
+		Logger: logger,
+	})
+
+	defaultOpts := _options.DefaultOptions{
+		Logger:     logger,
+		Router:     router,
+		Db:         db,
+		Config:     config,
+		HttpClient: httpClient,
 	}
 
 	return defaultOpts
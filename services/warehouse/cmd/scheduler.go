@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/go-co-op/gocron/v2"
+)
+
+func startScheduler(logger *pkg.Logger, modules *internal.Modules) {
+	s, err := gocron.NewScheduler()
+	if err != nil {
+		logger.Fatal("Failed to create scheduler:", err)
+	}
+
+	_, err = s.NewJob(gocron.CronJob("0 0 * * *", false), gocron.NewTask(func() {
+		ctx, cancelCtx := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancelCtx()
+
+		logger.Info("Taking stock snapshot...")
+		if err := modules.Stock.StockService.TakeStockSnapshot(ctx); err != nil {
+			logger.Error("Failed to take stock snapshot:", err)
+		}
+	}))
+	if err != nil {
+		logger.Fatal("Failed to create job:", err)
+	}
+
+	logger.Info("Scheduler started, taking stock snapshots every midnight")
+	s.Start()
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/_options"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/httpclient"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -77,11 +78,17 @@ func initDefaultOptionsForTesting(config *config.Config) _options.DefaultOptions
 		logger.Fatal("Failed to connect to the database:", err)
 	}
 
-	defaultOpts := _options.DefaultOptions{
-		Logger: logger,
-		Router: router,
-		Db:     db,
+	httpClient := httpclient.Init(httpclient.Options{
 		Config: config,
+		Logger: logger,
+	})
+
+	defaultOpts := _options.DefaultOptions{
+		Logger:     logger,
+		Router:     router,
+		Db:         db,
+		Config:     config,
+		HttpClient: httpClient,
 	}
 
 	return defaultOpts
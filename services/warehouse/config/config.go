@@ -6,9 +6,11 @@ import (
 
 // Configuration struct to hold environment variables
 type Config struct {
-	DB    DB
-	Token Token
-	App   App
+	DB          DB
+	Token       Token
+	App         App
+	External    External
+	Reservation Reservation
 }
 
 type App struct {
@@ -26,6 +28,14 @@ type Token struct {
 	JWTStatic string
 }
 
+type External struct {
+	PurchasingWebhookURL string
+}
+
+type Reservation struct {
+	AllocationStrategy string
+}
+
 func LoadConfig() (*Config, error) {
 
 	viper.SetConfigType("env")
@@ -49,6 +59,12 @@ func LoadConfig() (*Config, error) {
 			JWTSecret: []byte(viper.GetString("JWT_SECRET")),
 			JWTStatic: viper.GetString("JWT_STATIC"),
 		},
+		External: External{
+			PurchasingWebhookURL: viper.GetString("PURCHASING_WEBHOOK_URL"),
+		},
+		Reservation: Reservation{
+			AllocationStrategy: viper.GetString("RESERVATION_ALLOCATION_STRATEGY"),
+		},
 	}
 
 	return c, nil
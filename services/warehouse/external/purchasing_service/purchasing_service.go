@@ -0,0 +1,88 @@
+package purchasingservice
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/_options"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/httpclient"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"go.opentelemetry.io/otel/codes"
+)
+
+//go:generate mockery --name=IPurchasingSvc --case underscore
+type IPurchasingSvc interface {
+	NotifyStockAlerts(ctx context.Context, req NotifyStockAlertsReq) error
+}
+
+type PurchasingSvc struct {
+	WebhookURL string
+	httpClient httpclient.IHTTPClient
+}
+
+const (
+	PurchasingServicePrefix = "purchasingservice: "
+)
+
+var _ IPurchasingSvc = (*PurchasingSvc)(nil)
+
+func Init(opts _options.DefaultOptions) *PurchasingSvc {
+	return &PurchasingSvc{
+		WebhookURL: opts.Config.External.PurchasingWebhookURL,
+		httpClient: opts.HttpClient,
+	}
+}
+
+// NotifyStockAlerts posts stock alert transitions to the purchasing webhook.
+// It is a no-op when no webhook URL is configured.
+func (p *PurchasingSvc) NotifyStockAlerts(ctx context.Context, req NotifyStockAlertsReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "purchasingsvc.NotifyStockAlerts")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if p.WebhookURL == "" {
+		return nil
+	}
+
+	resp, err := p.httpClient.Post(ctx, &httpclient.PropRequest{
+		URI:  p.WebhookURL,
+		Body: req,
+	})
+
+	if err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, PurchasingServicePrefix+`internal server error`)
+	}
+
+	defer resp.Body.Close()
+
+	rawData, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		if err := handleErrorResponse(rawData, resp.StatusCode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func handleErrorResponse(rawData []byte, statusCode int) error {
+	if len(rawData) == 0 {
+		return apperr.NewWithCode(apperr.MapStatusCodeToErrorCode(statusCode), PurchasingServicePrefix+http.StatusText(statusCode))
+	}
+
+	var failResp ErrorResponse
+
+	if err := json.Unmarshal(rawData, &failResp); err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, PurchasingServicePrefix+"failed to unmarshal error response")
+	}
+
+	return apperr.NewWithCode(apperr.MapStatusCodeToErrorCode(statusCode), PurchasingServicePrefix+failResp.Metadata.Message)
+}
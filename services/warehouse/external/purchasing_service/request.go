@@ -0,0 +1,22 @@
+package purchasingservice
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotifyStockAlertsReq struct {
+	Alerts []NotifyStockAlertsReqData `json:"alerts"`
+}
+
+type NotifyStockAlertsReqData struct {
+	ID          uuid.UUID  `json:"id"`
+	Scope       string     `json:"scope"`
+	WarehouseID *uuid.UUID `json:"warehouse_id"`
+	ProductID   uuid.UUID  `json:"product_id"`
+	Status      string     `json:"status"`
+	Available   int        `json:"available"`
+	Threshold   int        `json:"threshold"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
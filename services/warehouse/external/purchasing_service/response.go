@@ -0,0 +1,11 @@
+package purchasingservice
+
+type ErrorResponse struct {
+	Metadata ErrorMetadata `json:"metadata"`
+}
+
+type ErrorMetadata struct {
+	Path    string `json:"path"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,8 @@
+package constant
+
+const (
+	AllocationStrategyPriority     = "priority"
+	AllocationStrategyFewestSplits = "fewest_splits"
+	AllocationStrategyLargestStock = "largest_stock"
+	AllocationStrategyNearest      = "nearest"
+)
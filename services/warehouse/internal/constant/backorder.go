@@ -0,0 +1,10 @@
+package constant
+
+const (
+	BackorderModeBackorder = "backorder"
+	BackorderModePreorder  = "preorder"
+
+	BackorderStatusWaiting   = "waiting"
+	BackorderStatusAllocated = "allocated"
+	BackorderStatusCancelled = "cancelled"
+)
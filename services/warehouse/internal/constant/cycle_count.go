@@ -0,0 +1,11 @@
+package constant
+
+const (
+	CycleCountStatusOpen      = "open"
+	CycleCountStatusPosted    = "posted"
+	CycleCountStatusCancelled = "cancelled"
+
+	AdjustmentReasonDamaged = "damaged"
+	AdjustmentReasonLost    = "lost"
+	AdjustmentReasonFound   = "found"
+)
@@ -0,0 +1,12 @@
+package constant
+
+const (
+	PurchaseOrderStatusDraft             = "draft"
+	PurchaseOrderStatusOrdered           = "ordered"
+	PurchaseOrderStatusPartiallyReceived = "partially_received"
+	PurchaseOrderStatusClosed            = "closed"
+
+	DiscrepancyTypeOver    = "over"
+	DiscrepancyTypeShort   = "short"
+	DiscrepancyTypeDamaged = "damaged"
+)
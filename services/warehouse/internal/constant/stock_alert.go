@@ -0,0 +1,10 @@
+package constant
+
+const (
+	StockAlertScopeWarehouse = "warehouse"
+	StockAlertScopeProduct   = "product"
+
+	StockAlertStatusLow       = "low"
+	StockAlertStatusOut       = "out"
+	StockAlertStatusRecovered = "recovered"
+)
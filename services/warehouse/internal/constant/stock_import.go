@@ -0,0 +1,8 @@
+package constant
+
+const (
+	StockImportModeSingle  = "single"
+	StockImportModeChunked = "chunked"
+
+	DefaultStockImportChunkSize = 500
+)
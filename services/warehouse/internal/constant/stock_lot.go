@@ -0,0 +1,7 @@
+package constant
+
+const (
+	StockLotExpiryDateLayout = "2006-01-02"
+
+	DefaultExpiringStockLotsWithinDays = 30
+)
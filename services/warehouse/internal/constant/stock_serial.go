@@ -0,0 +1,11 @@
+package constant
+
+const (
+	StockSerialStatusInStock = "in_stock"
+	StockSerialStatusSold    = "sold"
+
+	StockSerialEventReceived    = "received"
+	StockSerialEventSold        = "sold"
+	StockSerialEventReturned    = "returned"
+	StockSerialEventTransferred = "transferred"
+)
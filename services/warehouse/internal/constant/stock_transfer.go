@@ -0,0 +1,8 @@
+package constant
+
+const (
+	StockTransferStatusDispatched        = "dispatched"
+	StockTransferStatusInTransit         = "in_transit"
+	StockTransferStatusPartiallyReceived = "partially_received"
+	StockTransferStatusReceived          = "received"
+)
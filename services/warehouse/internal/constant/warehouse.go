@@ -2,5 +2,7 @@ package constant
 
 const (
 	WarehouseStatusActive   = "active"
+	WarehouseStatusDraining = "draining"
 	WarehouseStatusInactive = "inactive"
+	WarehouseStatusArchived = "archived"
 )
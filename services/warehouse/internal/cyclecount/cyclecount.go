@@ -0,0 +1,37 @@
+package cyclecount
+
+import (
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/_options"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount/handler"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount/repository"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount/service"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/registry"
+	stockrepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository"
+	stockservice "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service"
+	warehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
+)
+
+type CycleCountModule struct {
+	CycleCountService service.CycleCountService
+}
+
+type Options struct {
+	_options.DefaultOptions
+	StockService stockservice.StockService
+}
+
+func NewCycleCountModule(opts Options) *CycleCountModule {
+
+	cycleCountRepo := repository.NewCycleCountRepository(opts.Db)
+	stockRepo := stockrepository.NewStockRepository(opts.Db)
+	stockSerialRepo := stockrepository.NewStockSerialRepository(opts.Db)
+	warehouseRepo := warehouserepository.NewWarehouseRepository(opts.Db)
+
+	cycleCountService := service.NewCycleCountService(opts.Config, opts.Logger, opts.Db, cycleCountRepo, stockRepo, stockSerialRepo, warehouseRepo, opts.StockService)
+
+	registry.RegisterRouter(handler.NewHandler(opts.Router, opts.Config, opts.Logger, cycleCountService))
+
+	return &CycleCountModule{
+		CycleCountService: cycleCountService,
+	}
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/httpresp"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// @Summary		Cycle Count - Get Cycle Counts
+// @Description	get cycle count sessions
+// @Tags		Cycle Count
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetCycleCountsReq	false	"get cycle counts request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]model.CycleCount}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/cycle-counts [get]
+func (h *cycleCountHandler) GetCycleCounts(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "cycleCountHandler.GetCycleCounts")
+	defer span.End()
+
+	var req payload.GetCycleCountsReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	cycleCounts, err := h.cycleCountService.GetCycleCounts(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, cycleCounts, nil)
+}
+
+// @Summary		Cycle Count - Create Cycle Count
+// @Description	open a cycle count session for a warehouse
+// @Tags		Cycle Count
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.CreateCycleCountReq	true	"create cycle count request body"
+// @Success		200	{object}	httpresp.Response{data=model.CycleCount}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/cycle-counts [post]
+func (h *cycleCountHandler) CreateCycleCount(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "cycleCountHandler.CreateCycleCount")
+	defer span.End()
+
+	var req payload.CreateCycleCountReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	cycleCount, err := h.cycleCountService.CreateCycleCount(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, cycleCount, nil)
+}
+
+// @Summary		Cycle Count - Get Cycle Count
+// @Description	get a cycle count session with its lines and posted adjustments
+// @Tags		Cycle Count
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"cycle count ID"
+// @Success		200	{object}	httpresp.Response{data=model.CycleCount}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/cycle-counts/{id} [get]
+func (h *cycleCountHandler) GetCycleCountByID(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "cycleCountHandler.GetCycleCountByID")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid cycle count ID"))
+		return
+	}
+
+	cycleCount, err := h.cycleCountService.GetCycleCountByID(ctx, id.String())
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, cycleCount, nil)
+}
+
+// @Summary		Cycle Count - Submit Counts
+// @Description	submit counted quantities and compute their variance against warehouse stock
+// @Tags		Cycle Count
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"cycle count ID"
+// @Param		request	body	payload.SubmitCycleCountReq	true	"submit cycle count request body"
+// @Success		200	{object}	httpresp.Response{data=model.CycleCount}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/cycle-counts/{id}/counts [put]
+func (h *cycleCountHandler) SubmitCycleCount(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "cycleCountHandler.SubmitCycleCount")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid cycle count ID"))
+		return
+	}
+
+	var req payload.SubmitCycleCountReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ID = id
+	cycleCount, err := h.cycleCountService.SubmitCycleCount(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, cycleCount, nil)
+}
+
+// @Summary		Cycle Count - Post Adjustments
+// @Description	post approved adjustments with their reason codes and close the cycle count
+// @Tags		Cycle Count
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"cycle count ID"
+// @Param		request	body	payload.PostCycleCountReq	true	"post cycle count request body"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/cycle-counts/{id}/post [patch]
+func (h *cycleCountHandler) PostCycleCount(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "cycleCountHandler.PostCycleCount")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid cycle count ID"))
+		return
+	}
+
+	var req payload.PostCycleCountReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ID = id
+	if err := h.cycleCountService.PostCycleCount(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Cycle Count - Cancel Cycle Count
+// @Description	cancel an open cycle count without adjusting stock
+// @Tags		Cycle Count
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"cycle count ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/cycle-counts/{id}/cancel [patch]
+func (h *cycleCountHandler) CancelCycleCount(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "cycleCountHandler.CancelCycleCount")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid cycle count ID"))
+		return
+	}
+
+	if err := h.cycleCountService.CancelCycleCount(ctx, id.String()); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
@@ -0,0 +1,430 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount/service/mocks"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetCycleCounts_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		queries            string
+		mockResult         []model.CycleCount
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			queries:            "?status_in=open",
+			statusCodeExpected: http.StatusOK,
+			mockResult: []model.CycleCount{
+				{
+					ID:          uuid.New(),
+					WarehouseID: uuid.New(),
+					Status:      "open",
+				},
+			},
+		},
+		{
+			testName:           "failed - invalid status",
+			queries:            "?status_in=unknown",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get cycle counts",
+			queries:            "",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockCycleCountSvc := &mocks.CycleCountService{}
+			mockCycleCountSvc.
+				On("GetCycleCounts", mock.Anything, mock.Anything).
+				Return(scenario.mockResult, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/cycle-counts"+scenario.queries, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &cycleCountHandler{
+				router:            r,
+				config:            mockConfig,
+				cycleCountService: mockCycleCountSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestCreateCycleCount_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockReq:            `{"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "product_ids": ["9a2b7c93-7c27-4e20-842f-24bf4df95bf0"]}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "success - count whole warehouse",
+			mockReq:            `{"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0"}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - missing warehouse id",
+			mockReq:            `{"product_ids": ["9a2b7c93-7c27-4e20-842f-24bf4df95bf0"]}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle create cycle count",
+			mockReq:            `{"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0"}`,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockCycleCountSvc := &mocks.CycleCountService{}
+			mockCycleCountSvc.
+				On("CreateCycleCount", mock.Anything, mock.Anything).
+				Return(model.CycleCount{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/cycle-counts", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &cycleCountHandler{
+				router:            r,
+				config:            mockConfig,
+				cycleCountService: mockCycleCountSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetCycleCountByID_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		id                 string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			id:                 uuid.New().String(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid id",
+			id:                 "invalid-uuid",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get cycle count",
+			id:                 uuid.New().String(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockCycleCountSvc := &mocks.CycleCountService{}
+			mockCycleCountSvc.
+				On("GetCycleCountByID", mock.Anything, mock.Anything).
+				Return(model.CycleCount{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/cycle-counts/"+scenario.id, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &cycleCountHandler{
+				router:            r,
+				config:            mockConfig,
+				cycleCountService: mockCycleCountSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestSubmitCycleCount_ShouldReturnExpectedStatusCode(t *testing.T) {
+	payload := `{"lines": [{"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "counted_quantity": 0}]}`
+	testScenarios := []struct {
+		testName           string
+		id                 string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			id:                 uuid.New().String(),
+			mockReq:            payload,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid id",
+			id:                 "invalid-uuid",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - missing counted quantity",
+			id:                 uuid.New().String(),
+			mockReq:            `{"lines": [{"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0"}]}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - negative counted quantity",
+			id:                 uuid.New().String(),
+			mockReq:            `{"lines": [{"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "counted_quantity": -1}]}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle submit cycle count",
+			id:                 uuid.New().String(),
+			mockReq:            payload,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockCycleCountSvc := &mocks.CycleCountService{}
+			mockCycleCountSvc.
+				On("SubmitCycleCount", mock.Anything, mock.Anything).
+				Return(model.CycleCount{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/cycle-counts/"+scenario.id+"/counts", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &cycleCountHandler{
+				router:            r,
+				config:            mockConfig,
+				cycleCountService: mockCycleCountSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestPostCycleCount_ShouldReturnExpectedStatusCode(t *testing.T) {
+	payload := `{"adjustments": [{"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "reason": "damaged"}]}`
+	testScenarios := []struct {
+		testName           string
+		id                 string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			id:                 uuid.New().String(),
+			mockReq:            payload,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid id",
+			id:                 "invalid-uuid",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - invalid reason",
+			id:                 uuid.New().String(),
+			mockReq:            `{"adjustments": [{"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "reason": "stolen"}]}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle post cycle count",
+			id:                 uuid.New().String(),
+			mockReq:            payload,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockCycleCountSvc := &mocks.CycleCountService{}
+			mockCycleCountSvc.
+				On("PostCycleCount", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/cycle-counts/"+scenario.id+"/post", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &cycleCountHandler{
+				router:            r,
+				config:            mockConfig,
+				cycleCountService: mockCycleCountSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestCancelCycleCount_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		id                 string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			id:                 uuid.New().String(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid id",
+			id:                 "invalid-uuid",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle cancel cycle count",
+			id:                 uuid.New().String(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockCycleCountSvc := &mocks.CycleCountService{}
+			mockCycleCountSvc.
+				On("CancelCycleCount", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/cycle-counts/"+scenario.id+"/cancel", nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &cycleCountHandler{
+				router:            r,
+				config:            mockConfig,
+				cycleCountService: mockCycleCountSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
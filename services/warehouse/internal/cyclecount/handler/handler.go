@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount/service"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/middleware"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/registry"
+	"github.com/gin-gonic/gin"
+)
+
+type cycleCountHandler struct {
+	router            *gin.Engine
+	config            *config.Config
+	logger            *pkg.Logger
+	cycleCountService service.CycleCountService
+}
+
+func NewHandler(rt *gin.Engine, cfg *config.Config, logger *pkg.Logger, cycleCountSvc service.CycleCountService) registry.Router {
+	return &cycleCountHandler{
+		cycleCountService: cycleCountSvc,
+		router:            rt,
+		config:            cfg,
+		logger:            logger,
+	}
+}
+
+func (h cycleCountHandler) RegisterRoutes(base *gin.RouterGroup) {
+	g := base.Group("/cycle-counts")
+
+	g.Use(middleware.JwtMiddleware(h.config))
+
+	g.GET("", h.GetCycleCounts)
+	g.POST("", h.CreateCycleCount)
+	g.GET("/:id", h.GetCycleCountByID)
+	g.PUT("/:id/counts", h.SubmitCycleCount)
+	g.PATCH("/:id/post", h.PostCycleCount)
+	g.PATCH("/:id/cancel", h.CancelCycleCount)
+}
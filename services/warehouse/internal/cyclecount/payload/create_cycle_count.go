@@ -0,0 +1,11 @@
+package payload
+
+import "github.com/google/uuid"
+
+// CreateCycleCountReq opens a count session for a warehouse. When ProductIDs is
+// empty every product stocked in the warehouse is counted.
+type CreateCycleCountReq struct {
+	WarehouseID uuid.UUID   `json:"warehouse_id" binding:"required"`
+	ProductIDs  []uuid.UUID `json:"product_ids" binding:"omitempty,dive,required"`
+	Notes       *string     `json:"notes"`
+}
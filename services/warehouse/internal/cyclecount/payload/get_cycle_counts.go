@@ -0,0 +1,6 @@
+package payload
+
+type GetCycleCountsReq struct {
+	WarehouseIDIN []string `form:"warehouse_id_in" binding:"omitempty"`
+	StatusIN      []string `form:"status_in" binding:"omitempty,dive,oneof=open posted cancelled"`
+}
@@ -0,0 +1,15 @@
+package payload
+
+import "github.com/google/uuid"
+
+// PostCycleCountReq approves the variance of the listed products. Only approved
+// products are adjusted, the rest of the count is kept as a record.
+type PostCycleCountReq struct {
+	ID          uuid.UUID                  `json:"-"`
+	Adjustments []PostCycleCountAdjustment `json:"adjustments" binding:"omitempty,dive"`
+}
+
+type PostCycleCountAdjustment struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Reason    string    `json:"reason" binding:"required,oneof=damaged lost found"`
+}
@@ -0,0 +1,13 @@
+package payload
+
+import "github.com/google/uuid"
+
+type SubmitCycleCountReq struct {
+	ID    uuid.UUID              `json:"-"`
+	Lines []SubmitCycleCountLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+type SubmitCycleCountLine struct {
+	ProductID       uuid.UUID `json:"product_id" binding:"required"`
+	CountedQuantity *int      `json:"counted_quantity" binding:"required,min=0"`
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name=CycleCountRepository --case underscore
+type CycleCountRepository interface {
+	WithTX(tx *gorm.DB) CycleCountRepository
+	WithLockForUpdate() CycleCountRepository
+	CreateCycleCount(ctx context.Context, cycleCount *model.CycleCount) error
+	GetCycleCounts(ctx context.Context, req payload.GetCycleCountsReq) ([]model.CycleCount, error)
+	GetCycleCountByID(ctx context.Context, id string) (model.CycleCount, error)
+	UpdateCycleCount(ctx context.Context, cycleCount *model.CycleCount) error
+	UpdateCycleCountLine(ctx context.Context, line *model.CycleCountLine) error
+	CreateStockAdjustments(ctx context.Context, adjustments []model.StockAdjustment) error
+}
+
+type cycleCountRepository struct {
+	db *gorm.DB
+}
+
+func NewCycleCountRepository(db *gorm.DB) CycleCountRepository {
+	return &cycleCountRepository{db: db}
+}
+
+func (r *cycleCountRepository) WithTX(tx *gorm.DB) CycleCountRepository {
+	if tx == nil {
+		return r
+	}
+	return &cycleCountRepository{db: tx}
+}
+
+func (r *cycleCountRepository) WithLockForUpdate() CycleCountRepository {
+	return &cycleCountRepository{
+		db: r.db.Clauses(clause.Locking{Strength: "UPDATE"}),
+	}
+}
+
+func (r *cycleCountRepository) CreateCycleCount(ctx context.Context, cycleCount *model.CycleCount) error {
+	ctx, span := observ.GetTracer().Start(ctx, "cycleCountRepository.CreateCycleCount")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(cycleCount).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create cycle count")
+	}
+	return nil
+}
+
+func (r *cycleCountRepository) GetCycleCounts(ctx context.Context, req payload.GetCycleCountsReq) ([]model.CycleCount, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "cycleCountRepository.GetCycleCounts")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx).Model(&model.CycleCount{})
+	if len(req.WarehouseIDIN) > 0 {
+		stmt = stmt.Where("warehouse_id IN ?", req.WarehouseIDIN)
+	}
+
+	if len(req.StatusIN) > 0 {
+		stmt = stmt.Where("status IN ?", req.StatusIN)
+	}
+
+	var cycleCounts []model.CycleCount
+	if err := stmt.Order("created_at DESC").Find(&cycleCounts).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get cycle counts")
+	}
+	return cycleCounts, nil
+}
+
+func (r *cycleCountRepository) GetCycleCountByID(ctx context.Context, id string) (model.CycleCount, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "cycleCountRepository.GetCycleCountByID")
+	defer span.End()
+
+	var cycleCount model.CycleCount
+	if err := r.db.WithContext(ctx).
+		Preload("Lines").
+		Preload("Adjustments").
+		Where("id = ?", id).
+		First(&cycleCount).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if err == gorm.ErrRecordNotFound {
+			return model.CycleCount{}, apperr.WrapWithCode(err, apperr.CodeHTTPNotFound, "cycle count not found")
+		}
+		return model.CycleCount{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get cycle count by ID")
+	}
+	return cycleCount, nil
+}
+
+func (r *cycleCountRepository) UpdateCycleCount(ctx context.Context, cycleCount *model.CycleCount) error {
+	ctx, span := observ.GetTracer().Start(ctx, "cycleCountRepository.UpdateCycleCount")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Save(cycleCount).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update cycle count")
+	}
+	return nil
+}
+
+func (r *cycleCountRepository) UpdateCycleCountLine(ctx context.Context, line *model.CycleCountLine) error {
+	ctx, span := observ.GetTracer().Start(ctx, "cycleCountRepository.UpdateCycleCountLine")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Save(line).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update cycle count line")
+	}
+	return nil
+}
+
+func (r *cycleCountRepository) CreateStockAdjustments(ctx context.Context, adjustments []model.StockAdjustment) error {
+	ctx, span := observ.GetTracer().Start(ctx, "cycleCountRepository.CreateStockAdjustments")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(&adjustments).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create stock adjustments")
+	}
+	return nil
+}
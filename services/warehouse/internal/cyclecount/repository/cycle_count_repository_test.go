@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCycleCount(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, data model.CycleCount)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name string
+		data model.CycleCount
+		sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success",
+			data: model.CycleCount{
+				WarehouseID: uuid.New(),
+				Status:      constant.CycleCountStatusOpen,
+				Lines: []model.CycleCountLine{
+					{ProductID: uuid.New(), ExpectedQuantity: 10},
+				},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.CycleCount) {
+					cycleCountID := uuid.New()
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "cycle_counts" ("warehouse_id","status","notes","posted_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
+						),
+					).WithArgs(
+						data.WarehouseID,
+						data.Status,
+						data.Notes,
+						data.PostedAt,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}).AddRow(cycleCountID),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "cycle_count_lines" ("cycle_count_id","product_id","expected_quantity","counted_quantity","variance","reason","counted_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`,
+						),
+					).WithArgs(
+						cycleCountID,
+						data.Lines[0].ProductID,
+						data.Lines[0].ExpectedQuantity,
+						nil,
+						nil,
+						nil,
+						nil,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to create cycle count",
+			data: model.CycleCount{
+				WarehouseID: uuid.New(),
+				Status:      constant.CycleCountStatusOpen,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.CycleCount) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "cycle_counts" ("warehouse_id","status","notes","posted_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.data)
+
+			repo := NewCycleCountRepository(mockDb.Db)
+
+			err := repo.CreateCycleCount(context.Background(), &tt.data)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetCycleCounts(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetCycleCountsReq)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		req     payload.GetCycleCountsReq
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get cycle counts",
+			req: payload.GetCycleCountsReq{
+				WarehouseIDIN: []string{uuid.New().String()},
+				StatusIN:      []string{constant.CycleCountStatusOpen},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetCycleCountsReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "cycle_counts" WHERE warehouse_id IN ($1) AND status IN ($2) ORDER BY created_at DESC`,
+						),
+					).WithArgs(req.WarehouseIDIN[0], req.StatusIN[0]).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "warehouse_id", "status", "created_at", "updated_at"}).
+							AddRow(uuid.New(), req.WarehouseIDIN[0], req.StatusIN[0], time.Now(), time.Now()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get cycle counts",
+			req:  payload.GetCycleCountsReq{},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetCycleCountsReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "cycle_counts" ORDER BY created_at DESC`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.req)
+
+			repo := NewCycleCountRepository(mockDb.Db)
+
+			cycleCounts, err := repo.GetCycleCounts(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, cycleCounts, 1)
+		})
+	}
+}
+
+func TestGetCycleCountByID(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, id uuid.UUID)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get cycle count by id",
+			id:   uuid.New(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, id uuid.UUID) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "cycle_counts" WHERE id = $1 ORDER BY "cycle_counts"."id" LIMIT $2`,
+						),
+					).WithArgs(id.String(), 1).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "warehouse_id", "status"}).
+							AddRow(id, uuid.New(), constant.CycleCountStatusPosted),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_adjustments" WHERE "stock_adjustments"."cycle_count_id" = $1`,
+						),
+					).WithArgs(id).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "warehouse_id", "product_id", "cycle_count_id", "quantity", "reason"}).
+							AddRow(uuid.New(), uuid.New(), uuid.New(), id, -2, constant.AdjustmentReasonDamaged),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "cycle_count_lines" WHERE "cycle_count_lines"."cycle_count_id" = $1`,
+						),
+					).WithArgs(id).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "cycle_count_id", "product_id", "expected_quantity", "counted_quantity", "variance"}).
+							AddRow(uuid.New(), id, uuid.New(), 10, 8, -2),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - cycle count not found",
+			id:   uuid.New(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, id uuid.UUID) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "cycle_counts" WHERE id = $1 ORDER BY "cycle_counts"."id" LIMIT $2`,
+						),
+					).WithArgs(id.String(), 1).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}),
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.id)
+
+			repo := NewCycleCountRepository(mockDb.Db)
+
+			cycleCount, err := repo.GetCycleCountByID(context.Background(), tt.id.String())
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, cycleCount.Lines, 1)
+			assert.Len(t, cycleCount.Adjustments, 1)
+		})
+	}
+}
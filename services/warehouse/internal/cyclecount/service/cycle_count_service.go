@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount/repository"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	stockpayload "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	stockrepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository"
+	stockservice "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service"
+	warehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=CycleCountService --case underscore
+type CycleCountService interface {
+	CreateCycleCount(ctx context.Context, req payload.CreateCycleCountReq) (model.CycleCount, error)
+	GetCycleCounts(ctx context.Context, req payload.GetCycleCountsReq) ([]model.CycleCount, error)
+	GetCycleCountByID(ctx context.Context, id string) (model.CycleCount, error)
+	SubmitCycleCount(ctx context.Context, req payload.SubmitCycleCountReq) (model.CycleCount, error)
+	PostCycleCount(ctx context.Context, req payload.PostCycleCountReq) error
+	CancelCycleCount(ctx context.Context, id string) error
+}
+
+type cycleCountService struct {
+	config          *config.Config
+	logger          *pkg.Logger
+	db              *gorm.DB
+	cycleCountRepo  repository.CycleCountRepository
+	stockRepo       stockrepository.StockRepository
+	stockSerialRepo stockrepository.StockSerialRepository
+	warehouseRepo   warehouserepository.WarehouseRepository
+	stockService    stockservice.StockService
+}
+
+func NewCycleCountService(
+	config *config.Config,
+	logger *pkg.Logger,
+	db *gorm.DB,
+	cycleCountRepo repository.CycleCountRepository,
+	stockRepo stockrepository.StockRepository,
+	stockSerialRepo stockrepository.StockSerialRepository,
+	warehouseRepo warehouserepository.WarehouseRepository,
+	stockService stockservice.StockService,
+) CycleCountService {
+	return &cycleCountService{
+		config:          config,
+		logger:          logger,
+		db:              db,
+		cycleCountRepo:  cycleCountRepo,
+		stockRepo:       stockRepo,
+		stockSerialRepo: stockSerialRepo,
+		warehouseRepo:   warehouseRepo,
+		stockService:    stockService,
+	}
+}
+
+// CreateCycleCount opens a count session and snapshots the quantity currently
+// recorded for every product to count.
+func (s *cycleCountService) CreateCycleCount(ctx context.Context, req payload.CreateCycleCountReq) (result model.CycleCount, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "cycleCountService.CreateCycleCount")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	// the warehouse row is share-locked, so it cannot be archived while the
+	// count is opened
+	warehouse, err := s.warehouseRepo.WithTX(tx).WithLockForShare().GetWarehouseByID(ctx, req.WarehouseID.String())
+	if err != nil {
+		return model.CycleCount{}, err
+	}
+
+	if warehouse.Status != constant.WarehouseStatusActive {
+		return model.CycleCount{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse is not active")
+	}
+
+	var productIDs []string
+	for _, productID := range req.ProductIDs {
+		productIDs = append(productIDs, productID.String())
+	}
+
+	stocks, err := s.stockRepo.WithTX(tx).GetStocks(ctx, stockpayload.GetStocksReq{
+		WarehouseIDIN: []string{req.WarehouseID.String()},
+		ProductIDIN:   productIDs,
+	})
+	if err != nil {
+		return model.CycleCount{}, err
+	}
+
+	quantities := make(map[uuid.UUID]int)
+	for _, stock := range stocks {
+		quantities[stock.ProductID] = stock.Quantity
+	}
+
+	cycleCount := model.CycleCount{
+		WarehouseID: req.WarehouseID,
+		Status:      constant.CycleCountStatusOpen,
+		Notes:       req.Notes,
+	}
+
+	if len(req.ProductIDs) > 0 {
+		seen := make(map[uuid.UUID]bool)
+		for _, productID := range req.ProductIDs {
+			if seen[productID] {
+				continue
+			}
+			seen[productID] = true
+
+			// products missing from the warehouse are counted too, anything found is a gain
+			cycleCount.Lines = append(cycleCount.Lines, model.CycleCountLine{
+				ProductID:        productID,
+				ExpectedQuantity: quantities[productID],
+			})
+		}
+	} else {
+		for _, stock := range stocks {
+			cycleCount.Lines = append(cycleCount.Lines, model.CycleCountLine{
+				ProductID:        stock.ProductID,
+				ExpectedQuantity: stock.Quantity,
+			})
+		}
+	}
+
+	if len(cycleCount.Lines) == 0 {
+		return model.CycleCount{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse has no stock to count")
+	}
+
+	if err := s.cycleCountRepo.WithTX(tx).CreateCycleCount(ctx, &cycleCount); err != nil {
+		return model.CycleCount{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return model.CycleCount{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return cycleCount, nil
+}
+
+func (s *cycleCountService) GetCycleCounts(ctx context.Context, req payload.GetCycleCountsReq) (result []model.CycleCount, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "cycleCountService.GetCycleCounts")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	cycleCounts, err := s.cycleCountRepo.GetCycleCounts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return cycleCounts, nil
+}
+
+func (s *cycleCountService) GetCycleCountByID(ctx context.Context, id string) (result model.CycleCount, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "cycleCountService.GetCycleCountByID")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	cycleCount, err := s.cycleCountRepo.GetCycleCountByID(ctx, id)
+	if err != nil {
+		return model.CycleCount{}, err
+	}
+
+	return cycleCount, nil
+}
+
+// SubmitCycleCount records counted quantities and computes their variance
+// against the current warehouse stock. Counts can be resubmitted while the
+// session is open, the latest submission wins.
+func (s *cycleCountService) SubmitCycleCount(ctx context.Context, req payload.SubmitCycleCountReq) (result model.CycleCount, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "cycleCountService.SubmitCycleCount")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	cycleCount, err := s.cycleCountRepo.WithTX(tx).WithLockForUpdate().GetCycleCountByID(ctx, req.ID.String())
+	if err != nil {
+		return model.CycleCount{}, err
+	}
+
+	if cycleCount.Status != constant.CycleCountStatusOpen {
+		return model.CycleCount{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "cycle count is not open")
+	}
+
+	lines := make(map[uuid.UUID]*model.CycleCountLine)
+	for i := range cycleCount.Lines {
+		lines[cycleCount.Lines[i].ProductID] = &cycleCount.Lines[i]
+	}
+
+	var productIDs []string
+	for _, reqLine := range req.Lines {
+		if _, ok := lines[reqLine.ProductID]; !ok {
+			return model.CycleCount{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product is not part of the cycle count")
+		}
+		productIDs = append(productIDs, reqLine.ProductID.String())
+	}
+
+	stocks, err := s.stockRepo.WithTX(tx).GetStocks(ctx, stockpayload.GetStocksReq{
+		WarehouseIDIN: []string{cycleCount.WarehouseID.String()},
+		ProductIDIN:   productIDs,
+	})
+	if err != nil {
+		return model.CycleCount{}, err
+	}
+
+	quantities := make(map[uuid.UUID]int)
+	for _, stock := range stocks {
+		quantities[stock.ProductID] = stock.Quantity
+	}
+
+	now := time.Now()
+	for _, reqLine := range req.Lines {
+		line := lines[reqLine.ProductID]
+		counted := *reqLine.CountedQuantity
+		variance := counted - quantities[reqLine.ProductID]
+
+		line.CountedQuantity = &counted
+		line.Variance = &variance
+		line.CountedAt = &now
+		if err := s.cycleCountRepo.WithTX(tx).UpdateCycleCountLine(ctx, line); err != nil {
+			return model.CycleCount{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return model.CycleCount{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return cycleCount, nil
+}
+
+// PostCycleCount applies the variance of the approved products to the warehouse
+// stock and closes the session. A loss can never take the stock quantity below
+// what is already reserved.
+func (s *cycleCountService) PostCycleCount(ctx context.Context, req payload.PostCycleCountReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "cycleCountService.PostCycleCount")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	cycleCount, err := s.cycleCountRepo.WithTX(tx).WithLockForUpdate().GetCycleCountByID(ctx, req.ID.String())
+	if err != nil {
+		return err
+	}
+
+	if cycleCount.Status != constant.CycleCountStatusOpen {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "cycle count is not open")
+	}
+
+	// found stock must not land in a warehouse archived since the count was
+	// opened, the share lock holds an archive off until the count is posted
+	warehouse, err := s.warehouseRepo.WithTX(tx).WithLockForShare().GetWarehouseByID(ctx, cycleCount.WarehouseID.String())
+	if err != nil {
+		return err
+	}
+
+	if warehouse.Status == constant.WarehouseStatusArchived {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse is archived")
+	}
+
+	lines := make(map[uuid.UUID]*model.CycleCountLine)
+	for i := range cycleCount.Lines {
+		lines[cycleCount.Lines[i].ProductID] = &cycleCount.Lines[i]
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var adjustedProductIDs []string
+	for _, approval := range req.Adjustments {
+		if seen[approval.ProductID] {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "duplicate product in cycle count adjustments")
+		}
+		seen[approval.ProductID] = true
+		adjustedProductIDs = append(adjustedProductIDs, approval.ProductID.String())
+	}
+
+	// serialized stock follows its serials, a count cannot add or remove
+	// units without knowing which ones
+	if len(adjustedProductIDs) > 0 {
+		serialized, err := s.stockSerialRepo.WithTX(tx).GetSerializedProducts(ctx, adjustedProductIDs)
+		if err != nil {
+			return err
+		}
+		if len(serialized) > 0 {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product "+serialized[0].ProductID.String()+" is serialized, its stock follows its serials")
+		}
+	}
+
+	var adjustments []model.StockAdjustment
+	var productIDs []string
+	for _, approval := range req.Adjustments {
+		line, ok := lines[approval.ProductID]
+		if !ok {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product is not part of the cycle count")
+		}
+
+		if line.Variance == nil {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product has not been counted")
+		}
+
+		variance := *line.Variance
+		switch {
+		case variance == 0:
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product has no variance to adjust")
+		case variance > 0 && approval.Reason != constant.AdjustmentReasonFound:
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "stock gain must use the found reason")
+		case variance < 0 && approval.Reason == constant.AdjustmentReasonFound:
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "stock loss must use the damaged or lost reason")
+		}
+
+		if variance > 0 {
+			err = s.stockRepo.WithTX(tx).IncreaseStockQty(ctx, line.ProductID.String(), cycleCount.WarehouseID.String(), variance)
+		} else {
+			err = s.stockRepo.WithTX(tx).DecreaseStockQty(ctx, line.ProductID.String(), cycleCount.WarehouseID.String(), -variance)
+		}
+		if err != nil {
+			return err
+		}
+
+		reason := approval.Reason
+		line.Reason = &reason
+		if err := s.cycleCountRepo.WithTX(tx).UpdateCycleCountLine(ctx, line); err != nil {
+			return err
+		}
+
+		adjustments = append(adjustments, model.StockAdjustment{
+			WarehouseID:  cycleCount.WarehouseID,
+			ProductID:    line.ProductID,
+			CycleCountID: &cycleCount.ID,
+			Quantity:     variance,
+			Reason:       reason,
+		})
+		productIDs = append(productIDs, line.ProductID.String())
+	}
+
+	if len(adjustments) > 0 {
+		if err := s.cycleCountRepo.WithTX(tx).CreateStockAdjustments(ctx, adjustments); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	cycleCount.Status = constant.CycleCountStatusPosted
+	cycleCount.PostedAt = &now
+	if err := s.cycleCountRepo.WithTX(tx).UpdateCycleCount(ctx, &cycleCount); err != nil {
+		return err
+	}
+
+	var alerts []model.StockAlert
+	if len(productIDs) > 0 {
+		alerts, err = s.stockService.SettleStockChanges(ctx, tx, productIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.logger.WithContext(ctx).Infow("Cycle count posted",
+		"cycle_count_id", cycleCount.ID,
+		"warehouse_id", cycleCount.WarehouseID,
+		"adjustments", len(adjustments),
+	)
+
+	if len(productIDs) > 0 {
+		s.stockService.PublishStockChanges(ctx, productIDs, alerts)
+	}
+
+	return nil
+}
+
+func (s *cycleCountService) CancelCycleCount(ctx context.Context, id string) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "cycleCountService.CancelCycleCount")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	cycleCount, err := s.cycleCountRepo.WithTX(tx).WithLockForUpdate().GetCycleCountByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if cycleCount.Status != constant.CycleCountStatusOpen {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "cycle count is not open")
+	}
+
+	cycleCount.Status = constant.CycleCountStatusCancelled
+	if err := s.cycleCountRepo.WithTX(tx).UpdateCycleCount(ctx, &cycleCount); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return nil
+}
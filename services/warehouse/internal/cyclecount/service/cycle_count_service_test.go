@@ -0,0 +1,915 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount/payload"
+	cycleCountRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount/repository/mocks"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	stockRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository/mocks"
+	stockSvcMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service/mocks"
+	warehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository/mocks"
+)
+
+func TestCreateCycleCount_ShouldSuccess(t *testing.T) {
+	type dependencyMocks struct {
+		db             sqlmock.Sqlmock
+		cycleCountRepo *cycleCountRepoMock.CycleCountRepository
+		stockRepo      *stockRepoMock.StockRepository
+		warehouseRepo  *warehouseRepoMock.WarehouseRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	productID2 := uuid.New()
+	missingProductID := uuid.New()
+
+	tests := []struct {
+		name          string
+		req           payload.CreateCycleCountReq
+		setup         func(m dependencyMocks)
+		expectedLines map[uuid.UUID]int
+	}{
+		{
+			name: "success - count selected products",
+			req: payload.CreateCycleCountReq{
+				WarehouseID: warehouseID,
+				ProductIDs:  []uuid.UUID{productID, missingProductID, productID},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 10},
+					}, nil)
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("CreateCycleCount", mock.Anything, mock.Anything).
+					Return(nil)
+				m.db.ExpectCommit()
+			},
+			expectedLines: map[uuid.UUID]int{
+				productID:        10,
+				missingProductID: 0,
+			},
+		},
+		{
+			name: "success - count whole warehouse",
+			req: payload.CreateCycleCountReq{
+				WarehouseID: warehouseID,
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 10},
+						{WarehouseID: warehouseID, ProductID: productID2, Quantity: 3},
+					}, nil)
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("CreateCycleCount", mock.Anything, mock.Anything).
+					Return(nil)
+				m.db.ExpectCommit()
+			},
+			expectedLines: map[uuid.UUID]int{
+				productID:  10,
+				productID2: 3,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:             mockDb.Mock,
+				cycleCountRepo: cycleCountRepoMock.NewCycleCountRepository(t),
+				stockRepo:      stockRepoMock.NewStockRepository(t),
+				warehouseRepo:  warehouseRepoMock.NewWarehouseRepository(t),
+			}
+			cycleCountSvc := cycleCountService{
+				logger:         pkg.InitLogger(&config.Config{}),
+				db:             mockDb.Db,
+				cycleCountRepo: mocks.cycleCountRepo,
+				stockRepo:      mocks.stockRepo,
+				warehouseRepo:  mocks.warehouseRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			result, err := cycleCountSvc.CreateCycleCount(context.Background(), tt.req)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, constant.CycleCountStatusOpen, result.Status)
+			assert.Len(t, result.Lines, len(tt.expectedLines))
+			for _, line := range result.Lines {
+				assert.Equal(t, tt.expectedLines[line.ProductID], line.ExpectedQuantity)
+			}
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestCreateCycleCount_ShouldReturnError(t *testing.T) {
+	type dependencyMocks struct {
+		db             sqlmock.Sqlmock
+		cycleCountRepo *cycleCountRepoMock.CycleCountRepository
+		stockRepo      *stockRepoMock.StockRepository
+		warehouseRepo  *warehouseRepoMock.WarehouseRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	req := payload.CreateCycleCountReq{
+		WarehouseID: warehouseID,
+	}
+
+	tests := []struct {
+		name  string
+		setup func(m dependencyMocks)
+	}{
+		{
+			name: "error - warehouse not found",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{}, errors.New("warehouse not found"))
+
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - warehouse is not active",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusInactive}, nil)
+
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - warehouse has no stock",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - create cycle count failure",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 10},
+					}, nil)
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("CreateCycleCount", mock.Anything, mock.Anything).
+					Return(errors.New("database error"))
+
+				m.db.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:             mockDb.Mock,
+				cycleCountRepo: cycleCountRepoMock.NewCycleCountRepository(t),
+				stockRepo:      stockRepoMock.NewStockRepository(t),
+				warehouseRepo:  warehouseRepoMock.NewWarehouseRepository(t),
+			}
+			cycleCountSvc := cycleCountService{
+				logger:         pkg.InitLogger(&config.Config{}),
+				db:             mockDb.Db,
+				cycleCountRepo: mocks.cycleCountRepo,
+				stockRepo:      mocks.stockRepo,
+				warehouseRepo:  mocks.warehouseRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			_, err := cycleCountSvc.CreateCycleCount(context.Background(), req)
+
+			// Then
+			assert.Error(t, err)
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestSubmitCycleCount(t *testing.T) {
+	type dependencyMocks struct {
+		db             sqlmock.Sqlmock
+		cycleCountRepo *cycleCountRepoMock.CycleCountRepository
+		stockRepo      *stockRepoMock.StockRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	cycleCountID := uuid.New()
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	counted := 7
+
+	openCycleCount := model.CycleCount{
+		ID:          cycleCountID,
+		WarehouseID: warehouseID,
+		Status:      constant.CycleCountStatusOpen,
+		Lines: []model.CycleCountLine{
+			{ID: uuid.New(), ProductID: productID, ExpectedQuantity: 10},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		req     payload.SubmitCycleCountReq
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - variance against current stock",
+			req: payload.SubmitCycleCountReq{
+				ID: cycleCountID,
+				Lines: []payload.SubmitCycleCountLine{
+					{ProductID: productID, CountedQuantity: &counted},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(openCycleCount, nil)
+
+				// stock moved since the count was opened, variance follows the current quantity
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 8},
+					}, nil)
+
+				m.cycleCountRepo.On("UpdateCycleCountLine", mock.Anything, mock.MatchedBy(func(line *model.CycleCountLine) bool {
+					return *line.CountedQuantity == 7 && *line.Variance == -1 && line.CountedAt != nil
+				})).
+					Return(nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - cycle count is not open",
+			req: payload.SubmitCycleCountReq{
+				ID: cycleCountID,
+				Lines: []payload.SubmitCycleCountLine{
+					{ProductID: productID, CountedQuantity: &counted},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(model.CycleCount{ID: cycleCountID, Status: constant.CycleCountStatusPosted}, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - product is not part of the cycle count",
+			req: payload.SubmitCycleCountReq{
+				ID: cycleCountID,
+				Lines: []payload.SubmitCycleCountLine{
+					{ProductID: uuid.New(), CountedQuantity: &counted},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(openCycleCount, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:             mockDb.Mock,
+				cycleCountRepo: cycleCountRepoMock.NewCycleCountRepository(t),
+				stockRepo:      stockRepoMock.NewStockRepository(t),
+			}
+			cycleCountSvc := cycleCountService{
+				logger:         pkg.InitLogger(&config.Config{}),
+				db:             mockDb.Db,
+				cycleCountRepo: mocks.cycleCountRepo,
+				stockRepo:      mocks.stockRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			_, err := cycleCountSvc.SubmitCycleCount(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestPostCycleCount(t *testing.T) {
+	type dependencyMocks struct {
+		db              sqlmock.Sqlmock
+		cycleCountRepo  *cycleCountRepoMock.CycleCountRepository
+		stockRepo       *stockRepoMock.StockRepository
+		stockSerialRepo *stockRepoMock.StockSerialRepository
+		stockService    *stockSvcMock.StockService
+		warehouseRepo   *warehouseRepoMock.WarehouseRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	cycleCountID := uuid.New()
+	warehouseID := uuid.New()
+	productID := uuid.New()
+
+	countedCycleCount := func(variance *int) model.CycleCount {
+		return model.CycleCount{
+			ID:          cycleCountID,
+			WarehouseID: warehouseID,
+			Status:      constant.CycleCountStatusOpen,
+			Lines: []model.CycleCountLine{
+				{ID: uuid.New(), ProductID: productID, ExpectedQuantity: 10, Variance: variance},
+			},
+		}
+	}
+	intPtr := func(v int) *int { return &v }
+	expectWarehouse := func(m dependencyMocks, status string) {
+		m.warehouseRepo.On("WithTX", mock.Anything).
+			Return(m.warehouseRepo)
+		m.warehouseRepo.On("WithLockForShare").
+			Return(m.warehouseRepo)
+		m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+			Return(model.Warehouse{ID: warehouseID, Status: status}, nil)
+	}
+	expectSerialized := func(m dependencyMocks, products ...model.SerializedProduct) {
+		m.stockSerialRepo.On("WithTX", mock.Anything).
+			Return(m.stockSerialRepo)
+		m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, []string{productID.String()}).
+			Return(products, nil)
+	}
+
+	tests := []struct {
+		name    string
+		req     payload.PostCycleCountReq
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - found stock is added",
+			req: payload.PostCycleCountReq{
+				ID: cycleCountID,
+				Adjustments: []payload.PostCycleCountAdjustment{
+					{ProductID: productID, Reason: constant.AdjustmentReasonFound},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(countedCycleCount(intPtr(3)), nil)
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectSerialized(m)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 3).
+					Return(nil)
+
+				m.cycleCountRepo.On("UpdateCycleCountLine", mock.Anything, mock.MatchedBy(func(line *model.CycleCountLine) bool {
+					return *line.Reason == constant.AdjustmentReasonFound
+				})).
+					Return(nil)
+				m.cycleCountRepo.On("CreateStockAdjustments", mock.Anything, mock.MatchedBy(func(adjustments []model.StockAdjustment) bool {
+					return len(adjustments) == 1 &&
+						adjustments[0].Quantity == 3 &&
+						adjustments[0].Reason == constant.AdjustmentReasonFound &&
+						*adjustments[0].CycleCountID == cycleCountID
+				})).
+					Return(nil)
+				m.cycleCountRepo.On("UpdateCycleCount", mock.Anything, mock.MatchedBy(func(cycleCount *model.CycleCount) bool {
+					return cycleCount.Status == constant.CycleCountStatusPosted && cycleCount.PostedAt != nil
+				})).
+					Return(nil)
+
+				m.stockService.On("SettleStockChanges", mock.Anything, mock.Anything, []string{productID.String()}).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+
+				m.stockService.On("PublishStockChanges", mock.Anything, []string{productID.String()}, []model.StockAlert{}).
+					Return()
+			},
+		},
+		{
+			name: "success - damaged stock is written off",
+			req: payload.PostCycleCountReq{
+				ID: cycleCountID,
+				Adjustments: []payload.PostCycleCountAdjustment{
+					{ProductID: productID, Reason: constant.AdjustmentReasonDamaged},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(countedCycleCount(intPtr(-2)), nil)
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectSerialized(m)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("DecreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 2).
+					Return(nil)
+
+				m.cycleCountRepo.On("UpdateCycleCountLine", mock.Anything, mock.Anything).
+					Return(nil)
+				m.cycleCountRepo.On("CreateStockAdjustments", mock.Anything, mock.MatchedBy(func(adjustments []model.StockAdjustment) bool {
+					return len(adjustments) == 1 && adjustments[0].Quantity == -2
+				})).
+					Return(nil)
+				m.cycleCountRepo.On("UpdateCycleCount", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockService.On("SettleStockChanges", mock.Anything, mock.Anything, []string{productID.String()}).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+
+				m.stockService.On("PublishStockChanges", mock.Anything, []string{productID.String()}, []model.StockAlert{}).
+					Return()
+			},
+		},
+		{
+			name: "success - post without adjustments",
+			req: payload.PostCycleCountReq{
+				ID: cycleCountID,
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(countedCycleCount(intPtr(-2)), nil)
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				m.cycleCountRepo.On("UpdateCycleCount", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - gain must use the found reason",
+			req: payload.PostCycleCountReq{
+				ID: cycleCountID,
+				Adjustments: []payload.PostCycleCountAdjustment{
+					{ProductID: productID, Reason: constant.AdjustmentReasonLost},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(countedCycleCount(intPtr(3)), nil)
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectSerialized(m)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - loss cannot use the found reason",
+			req: payload.PostCycleCountReq{
+				ID: cycleCountID,
+				Adjustments: []payload.PostCycleCountAdjustment{
+					{ProductID: productID, Reason: constant.AdjustmentReasonFound},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(countedCycleCount(intPtr(-2)), nil)
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectSerialized(m)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - product has not been counted",
+			req: payload.PostCycleCountReq{
+				ID: cycleCountID,
+				Adjustments: []payload.PostCycleCountAdjustment{
+					{ProductID: productID, Reason: constant.AdjustmentReasonLost},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(countedCycleCount(nil), nil)
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectSerialized(m)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - product has no variance",
+			req: payload.PostCycleCountReq{
+				ID: cycleCountID,
+				Adjustments: []payload.PostCycleCountAdjustment{
+					{ProductID: productID, Reason: constant.AdjustmentReasonLost},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(countedCycleCount(intPtr(0)), nil)
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectSerialized(m)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - loss would drop below reserved quantity",
+			req: payload.PostCycleCountReq{
+				ID: cycleCountID,
+				Adjustments: []payload.PostCycleCountAdjustment{
+					{ProductID: productID, Reason: constant.AdjustmentReasonLost},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(countedCycleCount(intPtr(-2)), nil)
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectSerialized(m)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("DecreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 2).
+					Return(apperr.NewWithCode(apperr.CodeHTTPBadRequest, "stock quantity cannot drop below reserved quantity"))
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - serialized product cannot be adjusted",
+			req: payload.PostCycleCountReq{
+				ID: cycleCountID,
+				Adjustments: []payload.PostCycleCountAdjustment{
+					{ProductID: productID, Reason: constant.AdjustmentReasonFound},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(countedCycleCount(intPtr(3)), nil)
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectSerialized(m, model.SerializedProduct{ProductID: productID})
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - duplicate product in adjustments",
+			req: payload.PostCycleCountReq{
+				ID: cycleCountID,
+				Adjustments: []payload.PostCycleCountAdjustment{
+					{ProductID: productID, Reason: constant.AdjustmentReasonFound},
+					{ProductID: productID, Reason: constant.AdjustmentReasonFound},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(countedCycleCount(intPtr(3)), nil)
+				expectWarehouse(m, constant.WarehouseStatusActive)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - warehouse archived since the count was opened",
+			req: payload.PostCycleCountReq{
+				ID: cycleCountID,
+				Adjustments: []payload.PostCycleCountAdjustment{
+					{ProductID: productID, Reason: constant.AdjustmentReasonFound},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(countedCycleCount(intPtr(3)), nil)
+				expectWarehouse(m, constant.WarehouseStatusArchived)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - cycle count is not open",
+			req: payload.PostCycleCountReq{
+				ID: cycleCountID,
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(model.CycleCount{ID: cycleCountID, Status: constant.CycleCountStatusCancelled}, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:              mockDb.Mock,
+				cycleCountRepo:  cycleCountRepoMock.NewCycleCountRepository(t),
+				stockRepo:       stockRepoMock.NewStockRepository(t),
+				stockSerialRepo: stockRepoMock.NewStockSerialRepository(t),
+				stockService:    stockSvcMock.NewStockService(t),
+				warehouseRepo:   warehouseRepoMock.NewWarehouseRepository(t),
+			}
+			cycleCountSvc := cycleCountService{
+				logger:          pkg.InitLogger(&config.Config{}),
+				db:              mockDb.Db,
+				cycleCountRepo:  mocks.cycleCountRepo,
+				stockRepo:       mocks.stockRepo,
+				stockSerialRepo: mocks.stockSerialRepo,
+				stockService:    mocks.stockService,
+				warehouseRepo:   mocks.warehouseRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := cycleCountSvc.PostCycleCount(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestCancelCycleCount(t *testing.T) {
+	type dependencyMocks struct {
+		db             sqlmock.Sqlmock
+		cycleCountRepo *cycleCountRepoMock.CycleCountRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	cycleCountID := uuid.New()
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - open cycle count is cancelled",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(model.CycleCount{ID: cycleCountID, Status: constant.CycleCountStatusOpen}, nil)
+				m.cycleCountRepo.On("UpdateCycleCount", mock.Anything, mock.MatchedBy(func(cycleCount *model.CycleCount) bool {
+					return cycleCount.Status == constant.CycleCountStatusCancelled
+				})).
+					Return(nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - cycle count is already posted",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.cycleCountRepo.On("WithTX", mock.Anything).
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("WithLockForUpdate").
+					Return(m.cycleCountRepo)
+				m.cycleCountRepo.On("GetCycleCountByID", mock.Anything, cycleCountID.String()).
+					Return(model.CycleCount{ID: cycleCountID, Status: constant.CycleCountStatusPosted}, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:             mockDb.Mock,
+				cycleCountRepo: cycleCountRepoMock.NewCycleCountRepository(t),
+			}
+			cycleCountSvc := cycleCountService{
+				logger:         pkg.InitLogger(&config.Config{}),
+				db:             mockDb.Db,
+				cycleCountRepo: mocks.cycleCountRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := cycleCountSvc.CancelCycleCount(context.Background(), cycleCountID.String())
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BackorderProduct marks a product that accepts orders beyond its stock.
+type BackorderProduct struct {
+	ProductID uuid.UUID `json:"product_id" gorm:"column:product_id;primaryKey"`
+	Mode      string    `json:"mode"` // e.g., "backorder", "preorder"
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Backorder is an order quantity waiting for stock. Stock is allocated to the
+// oldest waiting backorder of a product first.
+type Backorder struct {
+	ID                uuid.UUID             `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	OrderRef          string                `json:"order_ref"`
+	ProductID         uuid.UUID             `json:"product_id"`
+	ShopID            *uuid.UUID            `json:"shop_id"`
+	Mode              string                `json:"mode"` // e.g., "backorder", "preorder"
+	Quantity          int                   `json:"quantity"`
+	AllocatedQuantity int                   `json:"allocated_quantity"`
+	Status            string                `json:"status"` // e.g., "waiting", "allocated"
+	AllocatedAt       *time.Time            `json:"allocated_at"`
+	CreatedAt         time.Time             `json:"created_at"`
+	UpdatedAt         time.Time             `json:"updated_at"`
+	Allocations       []BackorderAllocation `json:"allocations,omitempty" gorm:"foreignKey:BackorderID"`
+}
+
+// BackorderAllocation is a quantity reserved for a backorder in a warehouse.
+type BackorderAllocation struct {
+	ID          uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	BackorderID uuid.UUID `json:"backorder_id"`
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	Quantity    int       `json:"quantity"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CycleCount struct {
+	ID          uuid.UUID         `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	WarehouseID uuid.UUID         `json:"warehouse_id"`
+	Status      string            `json:"status"` // e.g., "open", "posted", "cancelled"
+	Notes       *string           `json:"notes"`
+	PostedAt    *time.Time        `json:"posted_at"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	Lines       []CycleCountLine  `json:"lines,omitempty" gorm:"foreignKey:CycleCountID"`
+	Adjustments []StockAdjustment `json:"adjustments,omitempty" gorm:"foreignKey:CycleCountID"`
+}
+
+type CycleCountLine struct {
+	ID               uuid.UUID  `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	CycleCountID     uuid.UUID  `json:"cycle_count_id"`
+	ProductID        uuid.UUID  `json:"product_id"`
+	ExpectedQuantity int        `json:"expected_quantity"`
+	CountedQuantity  *int       `json:"counted_quantity"`
+	Variance         *int       `json:"variance"`
+	Reason           *string    `json:"reason"` // e.g., "damaged", "lost", "found"
+	CountedAt        *time.Time `json:"counted_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GoodsReceipt struct {
+	ID              uuid.UUID          `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	PurchaseOrderID uuid.UUID          `json:"purchase_order_id"`
+	WarehouseID     uuid.UUID          `json:"warehouse_id"`
+	Notes           *string            `json:"notes"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Lines           []GoodsReceiptLine `json:"lines,omitempty" gorm:"foreignKey:GoodsReceiptID"`
+}
+
+type GoodsReceiptLine struct {
+	ID                  uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	GoodsReceiptID      uuid.UUID `json:"goods_receipt_id"`
+	PurchaseOrderLineID uuid.UUID `json:"purchase_order_line_id"`
+	ProductID           uuid.UUID `json:"product_id"`
+	ReceivedQuantity    int       `json:"received_quantity"`
+	RejectedQuantity    int       `json:"rejected_quantity"`
+	CreatedAt           time.Time `json:"created_at"`
+}
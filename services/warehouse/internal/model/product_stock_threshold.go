@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProductStockThreshold struct {
+	ProductID uuid.UUID `json:"product_id" gorm:"column:product_id;primaryKey"`
+	Threshold int       `json:"threshold"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PurchaseOrder struct {
+	ID            uuid.UUID                  `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	SupplierName  string                     `json:"supplier_name"`
+	Notes         *string                    `json:"notes"`
+	Status        string                     `json:"status"` // e.g., "draft", "ordered", "partially_received", "closed"
+	OrderedAt     *time.Time                 `json:"ordered_at"`
+	ClosedAt      *time.Time                 `json:"closed_at"`
+	CreatedAt     time.Time                  `json:"created_at"`
+	UpdatedAt     time.Time                  `json:"updated_at"`
+	Lines         []PurchaseOrderLine        `json:"lines,omitempty" gorm:"foreignKey:PurchaseOrderID"`
+	Receipts      []GoodsReceipt             `json:"receipts,omitempty" gorm:"foreignKey:PurchaseOrderID"`
+	Discrepancies []PurchaseOrderDiscrepancy `json:"discrepancies,omitempty" gorm:"foreignKey:PurchaseOrderID"`
+}
+
+type PurchaseOrderLine struct {
+	ID               uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	PurchaseOrderID  uuid.UUID `json:"purchase_order_id"`
+	ProductID        uuid.UUID `json:"product_id"`
+	ExpectedQuantity int       `json:"expected_quantity"`
+	ReceivedQuantity int       `json:"received_quantity"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type PurchaseOrderDiscrepancy struct {
+	ID              uuid.UUID  `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	PurchaseOrderID uuid.UUID  `json:"purchase_order_id"`
+	GoodsReceiptID  *uuid.UUID `json:"goods_receipt_id"`
+	ProductID       uuid.UUID  `json:"product_id"`
+	Type            string     `json:"type"` // e.g., "over", "short", "damaged"
+	Quantity        int        `json:"quantity"`
+	Note            *string    `json:"note"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ShopWarehouse struct {
+	ID          uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	ShopID      uuid.UUID `json:"shop_id"`
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	Priority    int       `json:"priority"` // lower value is reserved from first
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StockAdjustment struct {
+	ID           uuid.UUID  `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	WarehouseID  uuid.UUID  `json:"warehouse_id"`
+	ProductID    uuid.UUID  `json:"product_id"`
+	CycleCountID *uuid.UUID `json:"cycle_count_id"`
+	Quantity     int        `json:"quantity"`
+	Reason       string     `json:"reason"` // e.g., "damaged", "lost", "found"
+	CreatedAt    time.Time  `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StockAlert struct {
+	ID          uuid.UUID  `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	Scope       string     `json:"scope"` // e.g., "warehouse", "product"
+	WarehouseID *uuid.UUID `json:"warehouse_id"`
+	ProductID   uuid.UUID  `json:"product_id"`
+	Status      string     `json:"status"` // e.g., "low", "out", "recovered"
+	Available   int        `json:"available"`
+	Threshold   int        `json:"threshold"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
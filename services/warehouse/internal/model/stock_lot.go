@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockLot is a lot of a warehouse stock. Quantity and Reserved are part of
+// the quantity and reserved of the warehouse stock, the rest of it is not
+// tracked by lot.
+type StockLot struct {
+	ID          uuid.UUID  `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	WarehouseID uuid.UUID  `json:"warehouse_id"`
+	ProductID   uuid.UUID  `json:"product_id"`
+	LotNumber   string     `json:"lot_number"`
+	ExpiryDate  *time.Time `json:"expiry_date"`
+	Quantity    int        `json:"quantity"`
+	Reserved    int        `json:"reserved"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// StockLotReservation is the quantity of a lot reserved for an order, so the
+// order releases the lots it took. Reservations made without an order share an
+// empty OrderRef.
+type StockLotReservation struct {
+	StockLotID uuid.UUID `json:"stock_lot_id" gorm:"primaryKey"`
+	OrderRef   string    `json:"order_ref" gorm:"primaryKey"`
+	Quantity   int       `json:"quantity"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
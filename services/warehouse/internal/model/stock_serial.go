@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SerializedProduct marks a product whose units are tracked by serial number.
+type SerializedProduct struct {
+	ProductID uuid.UUID `json:"product_id" gorm:"column:product_id;primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type StockSerial struct {
+	ID           uuid.UUID          `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	ProductID    uuid.UUID          `json:"product_id"`
+	SerialNumber string             `json:"serial_number"`
+	WarehouseID  uuid.UUID          `json:"warehouse_id"`
+	Status       string             `json:"status"` // e.g., "in_stock", "sold"
+	OrderRef     *string            `json:"order_ref"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+	Events       []StockSerialEvent `json:"events,omitempty" gorm:"foreignKey:StockSerialID"`
+}
+
+type StockSerialEvent struct {
+	ID            uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	StockSerialID uuid.UUID `json:"stock_serial_id"`
+	Type          string    `json:"type"` // e.g., "received", "sold", "returned"
+	WarehouseID   uuid.UUID `json:"warehouse_id"`
+	OrderRef      *string   `json:"order_ref"`
+	CreatedAt     time.Time `json:"created_at"`
+}
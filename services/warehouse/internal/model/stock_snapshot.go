@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockSnapshot is the stock of a product in a warehouse at the time the
+// snapshot was taken.
+type StockSnapshot struct {
+	ID          uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	TakenAt     time.Time `json:"taken_at"`
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	Quantity    int       `json:"quantity"`
+	Reserved    int       `json:"reserved"`
+}
+
+// StockMovement is a change to a warehouse stock, recorded by the database
+// whenever warehouse_stocks is written.
+type StockMovement struct {
+	ID            int64     `json:"id" gorm:"column:id;primaryKey"`
+	WarehouseID   uuid.UUID `json:"warehouse_id"`
+	ProductID     uuid.UUID `json:"product_id"`
+	QuantityDelta int       `json:"quantity_delta"`
+	ReservedDelta int       `json:"reserved_delta"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -7,11 +7,45 @@ import (
 )
 
 type StockTransfer struct {
-	ID              uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
-	FromWarehouseID uuid.UUID `json:"from_warehouse_id"`
-	ToWarehouseID   uuid.UUID `json:"to_warehouse_id"`
-	ProductID       uuid.UUID `json:"product_id"`
-	Quantity        int       `json:"quantity"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID               uuid.UUID              `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	FromWarehouseID  uuid.UUID              `json:"from_warehouse_id"`
+	ToWarehouseID    uuid.UUID              `json:"to_warehouse_id"`
+	ProductID        uuid.UUID              `json:"product_id"`
+	Quantity         int                    `json:"quantity"`
+	Status           string                 `json:"status"` // e.g., "dispatched", "in_transit", "partially_received", "received"
+	ReceivedQuantity int                    `json:"received_quantity"`
+	LostQuantity     int                    `json:"lost_quantity"`
+	Notes            *string                `json:"notes"`
+	DispatchedAt     *time.Time             `json:"dispatched_at"`
+	InTransitAt      *time.Time             `json:"in_transit_at"`
+	ReceivedAt       *time.Time             `json:"received_at"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+	Receipts         []StockTransferReceipt `json:"receipts,omitempty" gorm:"foreignKey:StockTransferID"`
+}
+
+type StockTransferReceipt struct {
+	ID               uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	StockTransferID  uuid.UUID `json:"stock_transfer_id"`
+	ReceivedQuantity int       `json:"received_quantity"`
+	LostQuantity     int       `json:"lost_quantity"`
+	Note             *string   `json:"note"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type InTransitStock struct {
+	ProductID     uuid.UUID `json:"product_id"`
+	ToWarehouseID uuid.UUID `json:"to_warehouse_id"`
+	Quantity      int       `json:"quantity"`
+}
+
+// StockTransferLot is what the transfer carries of a lot, Quantity is what is
+// still in transit.
+type StockTransferLot struct {
+	StockTransferID uuid.UUID  `json:"stock_transfer_id" gorm:"primaryKey"`
+	LotNumber       string     `json:"lot_number" gorm:"primaryKey"`
+	ExpiryDate      *time.Time `json:"expiry_date"`
+	Quantity        int        `json:"quantity"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
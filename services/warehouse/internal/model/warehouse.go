@@ -7,10 +7,13 @@ import (
 )
 
 type Warehouse struct {
-	ID        uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
-	Name      string    `json:"name"`
-	Address   string    `json:"address"`
-	Status    string    `json:"status"` // e.g., "active", "inactive"
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uuid.UUID  `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	Name       string     `json:"name"`
+	Address    string     `json:"address"`
+	Status     string     `json:"status"` // e.g., "active", "draining", "inactive", "archived"
+	Latitude   *float64   `json:"latitude"`
+	Longitude  *float64   `json:"longitude"`
+	ArchivedAt *time.Time `json:"archived_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
 }
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WarehouseBin is a shelf location inside a warehouse, grouped by zone.
+type WarehouseBin struct {
+	ID          uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	Zone        string    `json:"zone"`
+	Code        string    `json:"code"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BinStock is the part of a warehouse stock shelved in a bin.
+type BinStock struct {
+	ID          uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	BinID       uuid.UUID `json:"bin_id"`
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	Quantity    int       `json:"quantity"`
+	Zone        string    `json:"zone" gorm:"->"`
+	BinCode     string    `json:"bin_code" gorm:"->"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type PickList struct {
+	ID          uuid.UUID      `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	WarehouseID uuid.UUID      `json:"warehouse_id"`
+	OrderRef    *string        `json:"order_ref"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Items       []PickListItem `json:"items" gorm:"foreignKey:PickListID"`
+}
+
+// PickListItem tells the picker how much of a product to take from a bin. An
+// item without a bin is stock that was never shelved.
+type PickListItem struct {
+	ID         uuid.UUID  `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	PickListID uuid.UUID  `json:"pick_list_id"`
+	ProductID  uuid.UUID  `json:"product_id"`
+	BinID      *uuid.UUID `json:"bin_id"`
+	Zone       *string    `json:"zone"`
+	BinCode    *string    `json:"bin_code"`
+	Quantity   int        `json:"quantity"`
+}
@@ -6,12 +6,15 @@ import (
 	"github.com/google/uuid"
 )
 
+// WarehouseStock is the stock of a product in a warehouse. A product sold in
+// variants is stocked by variant, its variant IDs are used as product IDs.
 type WarehouseStock struct {
-	ID          uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
-	WarehouseID uuid.UUID `json:"warehouse_id"`
-	ProductID   uuid.UUID `json:"product_id"`
-	Quantity    int       `json:"quantity"`
-	Reserved    int       `json:"reserved"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID               uuid.UUID `json:"id" gorm:"column:id;primaryKey;default:uuid_generate_v4()"`
+	WarehouseID      uuid.UUID `json:"warehouse_id"`
+	ProductID        uuid.UUID `json:"product_id"`
+	Quantity         int       `json:"quantity"`
+	Reserved         int       `json:"reserved"`
+	ReorderThreshold *int      `json:"reorder_threshold"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
@@ -1,14 +1,23 @@
 package internal
 
 import (
+	purchasingservice "github.com/alifmufthi91/ecommerce-system/services/warehouse/external/purchasing_service"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/_options"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/cyclecount"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse"
 )
 
 type Modules struct {
-	Warehouse *warehouse.WarehouseModule
-	Stock     *stock.StockModule
+	Warehouse     *warehouse.WarehouseModule
+	Stock         *stock.StockModule
+	PurchaseOrder *purchaseorder.PurchaseOrderModule
+	CycleCount    *cyclecount.CycleCountModule
+	ShopWarehouse *shopwarehouse.ShopWarehouseModule
+	Transfer      *transfer.TransferModule
 }
 
 type InitOptions struct {
@@ -17,16 +26,43 @@ type InitOptions struct {
 
 func InitModules(opts InitOptions) *Modules {
 
-	warehouseModule := warehouse.NewWarehouseModule(warehouse.Options{
+	purchasingSvc := purchasingservice.Init(opts.DefaultOptions)
+
+	shopWarehouseModule := shopwarehouse.NewShopWarehouseModule(shopwarehouse.Options{
 		DefaultOptions: opts.DefaultOptions,
 	})
 
 	stockModule := stock.NewStockModule(stock.Options{
+		DefaultOptions:    opts.DefaultOptions,
+		PurchasingService: purchasingSvc,
+	})
+
+	warehouseModule := warehouse.NewWarehouseModule(warehouse.Options{
+		DefaultOptions: opts.DefaultOptions,
+		StockService:   stockModule.StockService,
+	})
+
+	purchaseOrderModule := purchaseorder.NewPurchaseOrderModule(purchaseorder.Options{
+		DefaultOptions: opts.DefaultOptions,
+		StockService:   stockModule.StockService,
+	})
+
+	cycleCountModule := cyclecount.NewCycleCountModule(cyclecount.Options{
+		DefaultOptions: opts.DefaultOptions,
+		StockService:   stockModule.StockService,
+	})
+
+	transferModule := transfer.NewTransferModule(transfer.Options{
 		DefaultOptions: opts.DefaultOptions,
+		StockService:   stockModule.StockService,
 	})
 
 	return &Modules{
-		Warehouse: warehouseModule,
-		Stock:     stockModule,
+		Warehouse:     warehouseModule,
+		Stock:         stockModule,
+		PurchaseOrder: purchaseOrderModule,
+		CycleCount:    cycleCountModule,
+		ShopWarehouse: shopWarehouseModule,
+		Transfer:      transferModule,
 	}
 }
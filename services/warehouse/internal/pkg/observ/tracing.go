@@ -2,6 +2,7 @@ package observ
 
 import (
 	"context"
+	"net/http"
 	"sync"
 
 	"go.opentelemetry.io/otel"
@@ -30,3 +31,11 @@ func ReadTraceID(ctx context.Context) (traceID, spanID string) {
 	span := trace.SpanFromContext(ctx)
 	return span.SpanContext().TraceID().String(), span.SpanContext().SpanID().String()
 }
+
+func InjectTraceHeader(ctx context.Context, header http.Header) {
+	once.Do(func() {
+		reusablePropogator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	})
+
+	reusablePropogator.Inject(ctx, propagation.HeaderCarrier(header))
+}
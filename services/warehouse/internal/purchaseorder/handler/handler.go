@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/middleware"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/registry"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder/service"
+	"github.com/gin-gonic/gin"
+)
+
+type purchaseOrderHandler struct {
+	router               *gin.Engine
+	config               *config.Config
+	logger               *pkg.Logger
+	purchaseOrderService service.PurchaseOrderService
+}
+
+func NewHandler(rt *gin.Engine, cfg *config.Config, logger *pkg.Logger, purchaseOrderSvc service.PurchaseOrderService) registry.Router {
+	return &purchaseOrderHandler{
+		purchaseOrderService: purchaseOrderSvc,
+		router:               rt,
+		config:               cfg,
+		logger:               logger,
+	}
+}
+
+func (h purchaseOrderHandler) RegisterRoutes(base *gin.RouterGroup) {
+	g := base.Group("/purchase-orders")
+
+	g.Use(middleware.JwtMiddleware(h.config))
+
+	g.GET("", h.GetPurchaseOrders)
+	g.POST("", h.CreatePurchaseOrder)
+	g.GET("/:id", h.GetPurchaseOrderByID)
+	g.PATCH("/:id/submit", h.SubmitPurchaseOrder)
+	g.POST("/:id/receipts", h.ReceiveGoods)
+	g.PATCH("/:id/close", h.ClosePurchaseOrder)
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/httpresp"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/utils"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder/payload"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// @Summary		Purchase Order - Get Purchase Orders
+// @Description	get purchase orders with their lines
+// @Tags		Purchase Order
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetPurchaseOrdersReq	false	"get purchase orders request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]model.PurchaseOrder}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/purchase-orders [get]
+func (h *purchaseOrderHandler) GetPurchaseOrders(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "purchaseOrderHandler.GetPurchaseOrders")
+	defer span.End()
+
+	var req payload.GetPurchaseOrdersReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	purchaseOrders, err := h.purchaseOrderService.GetPurchaseOrders(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, purchaseOrders, nil)
+}
+
+// @Summary		Purchase Order - Create Purchase Order
+// @Description	create a draft purchase order against a supplier
+// @Tags		Purchase Order
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.CreatePurchaseOrderReq	true	"create purchase order request body"
+// @Success		200	{object}	httpresp.Response{data=model.PurchaseOrder}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/purchase-orders [post]
+func (h *purchaseOrderHandler) CreatePurchaseOrder(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "purchaseOrderHandler.CreatePurchaseOrder")
+	defer span.End()
+
+	var req payload.CreatePurchaseOrderReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	purchaseOrder, err := h.purchaseOrderService.CreatePurchaseOrder(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, purchaseOrder, nil)
+}
+
+// @Summary		Purchase Order - Get Purchase Order
+// @Description	get a purchase order with its lines, receipts and discrepancies
+// @Tags		Purchase Order
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"purchase order ID"
+// @Success		200	{object}	httpresp.Response{data=model.PurchaseOrder}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/purchase-orders/{id} [get]
+func (h *purchaseOrderHandler) GetPurchaseOrderByID(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "purchaseOrderHandler.GetPurchaseOrderByID")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid purchase order ID"))
+		return
+	}
+
+	purchaseOrder, err := h.purchaseOrderService.GetPurchaseOrderByID(ctx, id.String())
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, purchaseOrder, nil)
+}
+
+// @Summary		Purchase Order - Submit Purchase Order
+// @Description	submit a draft purchase order to the supplier
+// @Tags		Purchase Order
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"purchase order ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/purchase-orders/{id}/submit [patch]
+func (h *purchaseOrderHandler) SubmitPurchaseOrder(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "purchaseOrderHandler.SubmitPurchaseOrder")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid purchase order ID"))
+		return
+	}
+
+	if err := h.purchaseOrderService.SubmitPurchaseOrder(ctx, id.String()); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Purchase Order - Receive Goods
+// @Description	record a full or partial goods receipt into a warehouse
+// @Tags		Purchase Order
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"purchase order ID"
+// @Param		request	body	payload.ReceiveGoodsReq	true	"receive goods request body"
+// @Success		200	{object}	httpresp.Response{data=model.GoodsReceipt}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/purchase-orders/{id}/receipts [post]
+func (h *purchaseOrderHandler) ReceiveGoods(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "purchaseOrderHandler.ReceiveGoods")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid purchase order ID"))
+		return
+	}
+
+	var req payload.ReceiveGoodsReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.PurchaseOrderID = id
+	receipt, err := h.purchaseOrderService.ReceiveGoods(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, receipt, nil)
+}
+
+// @Summary		Purchase Order - Close Purchase Order
+// @Description	close an open purchase order, recording unreceived quantities as short deliveries
+// @Tags		Purchase Order
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"purchase order ID"
+// @Param		request	body	payload.ClosePurchaseOrderReq	false	"close purchase order request body"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/purchase-orders/{id}/close [patch]
+func (h *purchaseOrderHandler) ClosePurchaseOrder(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "purchaseOrderHandler.ClosePurchaseOrder")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid purchase order ID"))
+		return
+	}
+
+	var req payload.ClosePurchaseOrderReq
+	if c.Request.ContentLength > 0 {
+		if err := c.BindJSON(&req); err != nil {
+			errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+			httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+			return
+		}
+	}
+
+	req.ID = id
+	if err := h.purchaseOrderService.ClosePurchaseOrder(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
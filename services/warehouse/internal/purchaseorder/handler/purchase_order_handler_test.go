@@ -0,0 +1,426 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetPurchaseOrders_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		queries            string
+		mockResult         []model.PurchaseOrder
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			queries:            "?status_in=ordered&status_in=partially_received",
+			statusCodeExpected: http.StatusOK,
+			mockResult: []model.PurchaseOrder{
+				{
+					ID:           uuid.New(),
+					SupplierName: "Acme",
+					Status:       "ordered",
+				},
+			},
+		},
+		{
+			testName:           "failed - invalid status",
+			queries:            "?status_in=unknown",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get purchase orders",
+			queries:            "",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockPurchaseOrderSvc := &mocks.PurchaseOrderService{}
+			mockPurchaseOrderSvc.
+				On("GetPurchaseOrders", mock.Anything, mock.Anything).
+				Return(scenario.mockResult, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/purchase-orders"+scenario.queries, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &purchaseOrderHandler{
+				router:               r,
+				config:               mockConfig,
+				purchaseOrderService: mockPurchaseOrderSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestCreatePurchaseOrder_ShouldReturnExpectedStatusCode(t *testing.T) {
+	payload := `{
+		"supplier_name": "Acme",
+		"lines": [
+			{"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "expected_quantity": 10}
+		]
+	}`
+	testScenarios := []struct {
+		testName           string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - error handle create purchase order",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - invalid request body",
+			mockReq:            `{"supplier_name": "Acme", "lines": []}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockPurchaseOrderSvc := &mocks.PurchaseOrderService{}
+			mockPurchaseOrderSvc.
+				On("CreatePurchaseOrder", mock.Anything, mock.Anything).
+				Return(model.PurchaseOrder{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/purchase-orders", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &purchaseOrderHandler{
+				router:               r,
+				config:               mockConfig,
+				purchaseOrderService: mockPurchaseOrderSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetPurchaseOrderByID_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		id                 string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			id:                 uuid.New().String(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid id",
+			id:                 "invalid-uuid",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get purchase order",
+			id:                 uuid.New().String(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockPurchaseOrderSvc := &mocks.PurchaseOrderService{}
+			mockPurchaseOrderSvc.
+				On("GetPurchaseOrderByID", mock.Anything, scenario.id).
+				Return(model.PurchaseOrder{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/purchase-orders/"+scenario.id, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &purchaseOrderHandler{
+				router:               r,
+				config:               mockConfig,
+				purchaseOrderService: mockPurchaseOrderSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestSubmitPurchaseOrder_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		id                 string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			id:                 uuid.New().String(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid id",
+			id:                 "invalid-uuid",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle submit purchase order",
+			id:                 uuid.New().String(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockPurchaseOrderSvc := &mocks.PurchaseOrderService{}
+			mockPurchaseOrderSvc.
+				On("SubmitPurchaseOrder", mock.Anything, scenario.id).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/purchase-orders/"+scenario.id+"/submit", nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &purchaseOrderHandler{
+				router:               r,
+				config:               mockConfig,
+				purchaseOrderService: mockPurchaseOrderSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestReceiveGoods_ShouldReturnExpectedStatusCode(t *testing.T) {
+	payload := `{
+		"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0",
+		"lines": [
+			{"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "received_quantity": 8, "rejected_quantity": 2, "note": "2 boxes crushed"}
+		]
+	}`
+	testScenarios := []struct {
+		testName           string
+		id                 string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			id:                 uuid.New().String(),
+			mockReq:            payload,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid id",
+			id:                 "invalid-uuid",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - invalid request body",
+			id:                 uuid.New().String(),
+			mockReq:            `{"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "lines": [{"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "received_quantity": -1}]}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle receive goods",
+			id:                 uuid.New().String(),
+			mockReq:            payload,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockPurchaseOrderSvc := &mocks.PurchaseOrderService{}
+			mockPurchaseOrderSvc.
+				On("ReceiveGoods", mock.Anything, mock.Anything).
+				Return(model.GoodsReceipt{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/purchase-orders/"+scenario.id+"/receipts", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &purchaseOrderHandler{
+				router:               r,
+				config:               mockConfig,
+				purchaseOrderService: mockPurchaseOrderSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestClosePurchaseOrder_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		id                 string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			id:                 uuid.New().String(),
+			mockReq:            `{"note": "supplier out of stock"}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "success without body",
+			id:                 uuid.New().String(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid id",
+			id:                 "invalid-uuid",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle close purchase order",
+			id:                 uuid.New().String(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockPurchaseOrderSvc := &mocks.PurchaseOrderService{}
+			mockPurchaseOrderSvc.
+				On("ClosePurchaseOrder", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/purchase-orders/"+scenario.id+"/close", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &purchaseOrderHandler{
+				router:               r,
+				config:               mockConfig,
+				purchaseOrderService: mockPurchaseOrderSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package payload
+
+import "github.com/google/uuid"
+
+type ClosePurchaseOrderReq struct {
+	ID   uuid.UUID `json:"-"`
+	Note *string   `json:"note"`
+}
@@ -0,0 +1,14 @@
+package payload
+
+import "github.com/google/uuid"
+
+type CreatePurchaseOrderReq struct {
+	SupplierName string                    `json:"supplier_name" binding:"required"`
+	Notes        *string                   `json:"notes"`
+	Lines        []CreatePurchaseOrderLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+type CreatePurchaseOrderLine struct {
+	ProductID        uuid.UUID `json:"product_id" binding:"required"`
+	ExpectedQuantity int       `json:"expected_quantity" binding:"required,min=1"`
+}
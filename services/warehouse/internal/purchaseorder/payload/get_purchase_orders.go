@@ -0,0 +1,6 @@
+package payload
+
+type GetPurchaseOrdersReq struct {
+	StatusIN     []string `form:"status_in" binding:"omitempty,dive,oneof=draft ordered partially_received closed"`
+	SupplierName string   `form:"supplier_name" binding:"omitempty"`
+}
@@ -0,0 +1,20 @@
+package payload
+
+import "github.com/google/uuid"
+
+// ReceiveGoodsReq records a delivery against a purchase order. Received
+// quantities are put into stock of the warehouse, while rejected quantities
+// (e.g. damaged goods) are only recorded as a discrepancy.
+type ReceiveGoodsReq struct {
+	PurchaseOrderID uuid.UUID          `json:"-"`
+	WarehouseID     uuid.UUID          `json:"warehouse_id" binding:"required"`
+	Notes           *string            `json:"notes"`
+	Lines           []ReceiveGoodsLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+type ReceiveGoodsLine struct {
+	ProductID        uuid.UUID `json:"product_id" binding:"required"`
+	ReceivedQuantity int       `json:"received_quantity" binding:"min=0"`
+	RejectedQuantity int       `json:"rejected_quantity" binding:"min=0"`
+	Note             *string   `json:"note"`
+}
@@ -0,0 +1,37 @@
+package purchaseorder
+
+import (
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/_options"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/registry"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder/handler"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder/repository"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder/service"
+	stockrepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository"
+	stockservice "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service"
+	warehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
+)
+
+type PurchaseOrderModule struct {
+	PurchaseOrderService service.PurchaseOrderService
+}
+
+type Options struct {
+	_options.DefaultOptions
+	StockService stockservice.StockService
+}
+
+func NewPurchaseOrderModule(opts Options) *PurchaseOrderModule {
+
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(opts.Db)
+	stockRepo := stockrepository.NewStockRepository(opts.Db)
+	stockSerialRepo := stockrepository.NewStockSerialRepository(opts.Db)
+	warehouseRepo := warehouserepository.NewWarehouseRepository(opts.Db)
+
+	purchaseOrderService := service.NewPurchaseOrderService(opts.Config, opts.Logger, opts.Db, purchaseOrderRepo, stockRepo, stockSerialRepo, warehouseRepo, opts.StockService)
+
+	registry.RegisterRouter(handler.NewHandler(opts.Router, opts.Config, opts.Logger, purchaseOrderService))
+
+	return &PurchaseOrderModule{
+		PurchaseOrderService: purchaseOrderService,
+	}
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder/payload"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name=PurchaseOrderRepository --case underscore
+type PurchaseOrderRepository interface {
+	WithTX(tx *gorm.DB) PurchaseOrderRepository
+	WithLockForUpdate() PurchaseOrderRepository
+	CreatePurchaseOrder(ctx context.Context, purchaseOrder *model.PurchaseOrder) error
+	GetPurchaseOrders(ctx context.Context, req payload.GetPurchaseOrdersReq) ([]model.PurchaseOrder, error)
+	GetPurchaseOrderByID(ctx context.Context, id string) (model.PurchaseOrder, error)
+	UpdatePurchaseOrder(ctx context.Context, purchaseOrder *model.PurchaseOrder) error
+	AddLineReceivedQty(ctx context.Context, lineID string, quantity int) error
+	CreateGoodsReceipt(ctx context.Context, receipt *model.GoodsReceipt) error
+	CreateDiscrepancies(ctx context.Context, discrepancies []model.PurchaseOrderDiscrepancy) error
+}
+
+type purchaseOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewPurchaseOrderRepository(db *gorm.DB) PurchaseOrderRepository {
+	return &purchaseOrderRepository{db: db}
+}
+
+func (r *purchaseOrderRepository) WithTX(tx *gorm.DB) PurchaseOrderRepository {
+	if tx == nil {
+		return r
+	}
+	return &purchaseOrderRepository{db: tx}
+}
+
+func (r *purchaseOrderRepository) WithLockForUpdate() PurchaseOrderRepository {
+	return &purchaseOrderRepository{
+		db: r.db.Clauses(clause.Locking{Strength: "UPDATE"}),
+	}
+}
+
+func (r *purchaseOrderRepository) CreatePurchaseOrder(ctx context.Context, purchaseOrder *model.PurchaseOrder) error {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderRepository.CreatePurchaseOrder")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(purchaseOrder).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create purchase order")
+	}
+	return nil
+}
+
+func (r *purchaseOrderRepository) GetPurchaseOrders(ctx context.Context, req payload.GetPurchaseOrdersReq) ([]model.PurchaseOrder, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderRepository.GetPurchaseOrders")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx).Model(&model.PurchaseOrder{})
+	if len(req.StatusIN) > 0 {
+		stmt = stmt.Where("status IN ?", req.StatusIN)
+	}
+
+	if req.SupplierName != "" {
+		stmt = stmt.Where("supplier_name = ?", req.SupplierName)
+	}
+
+	var purchaseOrders []model.PurchaseOrder
+	if err := stmt.Preload("Lines").Order("created_at DESC").Find(&purchaseOrders).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get purchase orders")
+	}
+	return purchaseOrders, nil
+}
+
+func (r *purchaseOrderRepository) GetPurchaseOrderByID(ctx context.Context, id string) (model.PurchaseOrder, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderRepository.GetPurchaseOrderByID")
+	defer span.End()
+
+	var purchaseOrder model.PurchaseOrder
+	if err := r.db.WithContext(ctx).
+		Preload("Lines").
+		Preload("Receipts.Lines").
+		Preload("Discrepancies").
+		Where("id = ?", id).
+		First(&purchaseOrder).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if err == gorm.ErrRecordNotFound {
+			return model.PurchaseOrder{}, apperr.WrapWithCode(err, apperr.CodeHTTPNotFound, "purchase order not found")
+		}
+		return model.PurchaseOrder{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get purchase order by ID")
+	}
+	return purchaseOrder, nil
+}
+
+func (r *purchaseOrderRepository) UpdatePurchaseOrder(ctx context.Context, purchaseOrder *model.PurchaseOrder) error {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderRepository.UpdatePurchaseOrder")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Save(purchaseOrder).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update purchase order")
+	}
+	return nil
+}
+
+func (r *purchaseOrderRepository) AddLineReceivedQty(ctx context.Context, lineID string, quantity int) error {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderRepository.AddLineReceivedQty")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Model(&model.PurchaseOrderLine{}).
+		Where("id = ?", lineID).
+		Update("received_quantity", gorm.Expr("received_quantity + ?", quantity)).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update purchase order line received quantity")
+	}
+	return nil
+}
+
+func (r *purchaseOrderRepository) CreateGoodsReceipt(ctx context.Context, receipt *model.GoodsReceipt) error {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderRepository.CreateGoodsReceipt")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(receipt).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create goods receipt")
+	}
+	return nil
+}
+
+func (r *purchaseOrderRepository) CreateDiscrepancies(ctx context.Context, discrepancies []model.PurchaseOrderDiscrepancy) error {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderRepository.CreateDiscrepancies")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(&discrepancies).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create purchase order discrepancies")
+	}
+	return nil
+}
@@ -0,0 +1,381 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder/payload"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePurchaseOrder(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, data model.PurchaseOrder)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name string
+		data model.PurchaseOrder
+		sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success",
+			data: model.PurchaseOrder{
+				SupplierName: "Acme",
+				Status:       constant.PurchaseOrderStatusDraft,
+				Lines: []model.PurchaseOrderLine{
+					{ProductID: uuid.New(), ExpectedQuantity: 10},
+				},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.PurchaseOrder) {
+					purchaseOrderID := uuid.New()
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "purchase_orders" ("supplier_name","notes","status","ordered_at","closed_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`,
+						),
+					).WithArgs(
+						data.SupplierName,
+						data.Notes,
+						data.Status,
+						data.OrderedAt,
+						data.ClosedAt,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}).AddRow(purchaseOrderID),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "purchase_order_lines" ("purchase_order_id","product_id","expected_quantity","received_quantity","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6)`,
+						),
+					).WithArgs(
+						purchaseOrderID,
+						data.Lines[0].ProductID,
+						data.Lines[0].ExpectedQuantity,
+						0,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to create purchase order",
+			data: model.PurchaseOrder{
+				SupplierName: "Acme",
+				Status:       constant.PurchaseOrderStatusDraft,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.PurchaseOrder) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "purchase_orders" ("supplier_name","notes","status","ordered_at","closed_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.data)
+
+			repo := NewPurchaseOrderRepository(mockDb.Db)
+
+			err := repo.CreatePurchaseOrder(context.Background(), &tt.data)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetPurchaseOrders(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetPurchaseOrdersReq)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		req     payload.GetPurchaseOrdersReq
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get purchase orders",
+			req: payload.GetPurchaseOrdersReq{
+				StatusIN:     []string{constant.PurchaseOrderStatusOrdered},
+				SupplierName: "Acme",
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetPurchaseOrdersReq) {
+					purchaseOrderID := uuid.New()
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "purchase_orders" WHERE status IN ($1) AND supplier_name = $2 ORDER BY created_at DESC`,
+						),
+					).WithArgs(req.StatusIN[0], req.SupplierName).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "supplier_name", "status", "created_at", "updated_at"}).
+							AddRow(purchaseOrderID, req.SupplierName, req.StatusIN[0], time.Now(), time.Now()),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "purchase_order_lines" WHERE "purchase_order_lines"."purchase_order_id" = $1`,
+						),
+					).WithArgs(purchaseOrderID).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "purchase_order_id", "product_id", "expected_quantity", "received_quantity"}).
+							AddRow(uuid.New(), purchaseOrderID, uuid.New(), 10, 0),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get purchase orders",
+			req:  payload.GetPurchaseOrdersReq{},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetPurchaseOrdersReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "purchase_orders" ORDER BY created_at DESC`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.req)
+
+			repo := NewPurchaseOrderRepository(mockDb.Db)
+
+			purchaseOrders, err := repo.GetPurchaseOrders(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, purchaseOrders, 1)
+			assert.Len(t, purchaseOrders[0].Lines, 1)
+		})
+	}
+}
+
+func TestGetPurchaseOrderByID(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, id uuid.UUID)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get purchase order by id",
+			id:   uuid.New(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, id uuid.UUID) {
+					receiptID := uuid.New()
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "purchase_orders" WHERE id = $1 ORDER BY "purchase_orders"."id" LIMIT $2`,
+						),
+					).WithArgs(id.String(), 1).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "supplier_name", "status"}).
+							AddRow(id, "Acme", constant.PurchaseOrderStatusPartiallyReceived),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "purchase_order_discrepancies" WHERE "purchase_order_discrepancies"."purchase_order_id" = $1`,
+						),
+					).WithArgs(id).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "purchase_order_id", "product_id", "type", "quantity"}).
+							AddRow(uuid.New(), id, uuid.New(), constant.DiscrepancyTypeDamaged, 2),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "purchase_order_lines" WHERE "purchase_order_lines"."purchase_order_id" = $1`,
+						),
+					).WithArgs(id).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "purchase_order_id", "product_id", "expected_quantity", "received_quantity"}).
+							AddRow(uuid.New(), id, uuid.New(), 10, 4),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "goods_receipts" WHERE "goods_receipts"."purchase_order_id" = $1`,
+						),
+					).WithArgs(id).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "purchase_order_id", "warehouse_id"}).
+							AddRow(receiptID, id, uuid.New()),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "goods_receipt_lines" WHERE "goods_receipt_lines"."goods_receipt_id" = $1`,
+						),
+					).WithArgs(receiptID).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "goods_receipt_id", "product_id", "received_quantity", "rejected_quantity"}).
+							AddRow(uuid.New(), receiptID, uuid.New(), 4, 2),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - purchase order not found",
+			id:   uuid.New(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, id uuid.UUID) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "purchase_orders" WHERE id = $1 ORDER BY "purchase_orders"."id" LIMIT $2`,
+						),
+					).WithArgs(id.String(), 1).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}),
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.id)
+
+			repo := NewPurchaseOrderRepository(mockDb.Db)
+
+			purchaseOrder, err := repo.GetPurchaseOrderByID(context.Background(), tt.id.String())
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, purchaseOrder.Lines, 1)
+			assert.Len(t, purchaseOrder.Receipts, 1)
+			assert.Len(t, purchaseOrder.Receipts[0].Lines, 1)
+			assert.Len(t, purchaseOrder.Discrepancies, 1)
+		})
+	}
+}
+
+func TestAddLineReceivedQty(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, lineID string, quantity int)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name     string
+		lineID   string
+		quantity int
+		sqlMock  sqlMock
+		wantErr  bool
+	}{
+		{
+			name:     "success - add line received quantity",
+			lineID:   uuid.New().String(),
+			quantity: 4,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, lineID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "purchase_order_lines" SET "received_quantity"=received_quantity + $1,"updated_at"=$2 WHERE id = $3`,
+						),
+					).WithArgs(quantity, sqlmock.AnyArg(), lineID).WillReturnResult(
+						sqlmock.NewResult(0, 1),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "error - failed to add line received quantity",
+			lineID:   uuid.New().String(),
+			quantity: 4,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, lineID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "purchase_order_lines" SET "received_quantity"=received_quantity + $1,"updated_at"=$2 WHERE id = $3`,
+						),
+					).WithArgs(quantity, sqlmock.AnyArg(), lineID).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.lineID, tt.quantity)
+
+			repo := NewPurchaseOrderRepository(mockDb.Db)
+
+			err := repo.AddLineReceivedQty(context.Background(), tt.lineID, tt.quantity)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
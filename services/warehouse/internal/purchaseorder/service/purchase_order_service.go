@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder/repository"
+	stockrepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository"
+	stockservice "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service"
+	warehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=PurchaseOrderService --case underscore
+type PurchaseOrderService interface {
+	CreatePurchaseOrder(ctx context.Context, req payload.CreatePurchaseOrderReq) (model.PurchaseOrder, error)
+	GetPurchaseOrders(ctx context.Context, req payload.GetPurchaseOrdersReq) ([]model.PurchaseOrder, error)
+	GetPurchaseOrderByID(ctx context.Context, id string) (model.PurchaseOrder, error)
+	SubmitPurchaseOrder(ctx context.Context, id string) error
+	ReceiveGoods(ctx context.Context, req payload.ReceiveGoodsReq) (model.GoodsReceipt, error)
+	ClosePurchaseOrder(ctx context.Context, req payload.ClosePurchaseOrderReq) error
+}
+
+type purchaseOrderService struct {
+	config            *config.Config
+	logger            *pkg.Logger
+	db                *gorm.DB
+	purchaseOrderRepo repository.PurchaseOrderRepository
+	stockRepo         stockrepository.StockRepository
+	stockSerialRepo   stockrepository.StockSerialRepository
+	warehouseRepo     warehouserepository.WarehouseRepository
+	stockService      stockservice.StockService
+}
+
+func NewPurchaseOrderService(
+	config *config.Config,
+	logger *pkg.Logger,
+	db *gorm.DB,
+	purchaseOrderRepo repository.PurchaseOrderRepository,
+	stockRepo stockrepository.StockRepository,
+	stockSerialRepo stockrepository.StockSerialRepository,
+	warehouseRepo warehouserepository.WarehouseRepository,
+	stockService stockservice.StockService,
+) PurchaseOrderService {
+	return &purchaseOrderService{
+		config:            config,
+		logger:            logger,
+		db:                db,
+		purchaseOrderRepo: purchaseOrderRepo,
+		stockRepo:         stockRepo,
+		stockSerialRepo:   stockSerialRepo,
+		warehouseRepo:     warehouseRepo,
+		stockService:      stockService,
+	}
+}
+
+func (s *purchaseOrderService) CreatePurchaseOrder(ctx context.Context, req payload.CreatePurchaseOrderReq) (result model.PurchaseOrder, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderService.CreatePurchaseOrder")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	purchaseOrder := model.PurchaseOrder{
+		SupplierName: req.SupplierName,
+		Notes:        req.Notes,
+		Status:       constant.PurchaseOrderStatusDraft,
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for _, line := range req.Lines {
+		if seen[line.ProductID] {
+			return model.PurchaseOrder{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "duplicate product in purchase order lines")
+		}
+		seen[line.ProductID] = true
+
+		purchaseOrder.Lines = append(purchaseOrder.Lines, model.PurchaseOrderLine{
+			ProductID:        line.ProductID,
+			ExpectedQuantity: line.ExpectedQuantity,
+		})
+	}
+
+	if err := s.purchaseOrderRepo.CreatePurchaseOrder(ctx, &purchaseOrder); err != nil {
+		return model.PurchaseOrder{}, err
+	}
+
+	return purchaseOrder, nil
+}
+
+func (s *purchaseOrderService) GetPurchaseOrders(ctx context.Context, req payload.GetPurchaseOrdersReq) (result []model.PurchaseOrder, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderService.GetPurchaseOrders")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	purchaseOrders, err := s.purchaseOrderRepo.GetPurchaseOrders(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return purchaseOrders, nil
+}
+
+func (s *purchaseOrderService) GetPurchaseOrderByID(ctx context.Context, id string) (result model.PurchaseOrder, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderService.GetPurchaseOrderByID")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	purchaseOrder, err := s.purchaseOrderRepo.GetPurchaseOrderByID(ctx, id)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+
+	return purchaseOrder, nil
+}
+
+func (s *purchaseOrderService) SubmitPurchaseOrder(ctx context.Context, id string) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderService.SubmitPurchaseOrder")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	purchaseOrder, err := s.purchaseOrderRepo.WithTX(tx).WithLockForUpdate().GetPurchaseOrderByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if purchaseOrder.Status != constant.PurchaseOrderStatusDraft {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "only draft purchase orders can be submitted")
+	}
+
+	now := time.Now()
+	purchaseOrder.Status = constant.PurchaseOrderStatusOrdered
+	purchaseOrder.OrderedAt = &now
+	if err := s.purchaseOrderRepo.WithTX(tx).UpdatePurchaseOrder(ctx, &purchaseOrder); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// ReceiveGoods books a delivery into the warehouse stock. Quantities received
+// beyond what is still expected are accepted but recorded as an over delivery,
+// and rejected quantities are recorded as damaged. The purchase order is closed
+// once every line is fully received.
+func (s *purchaseOrderService) ReceiveGoods(ctx context.Context, req payload.ReceiveGoodsReq) (result model.GoodsReceipt, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderService.ReceiveGoods")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	purchaseOrder, err := s.purchaseOrderRepo.WithTX(tx).WithLockForUpdate().GetPurchaseOrderByID(ctx, req.PurchaseOrderID.String())
+	if err != nil {
+		return model.GoodsReceipt{}, err
+	}
+
+	if purchaseOrder.Status != constant.PurchaseOrderStatusOrdered && purchaseOrder.Status != constant.PurchaseOrderStatusPartiallyReceived {
+		return model.GoodsReceipt{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "purchase order is not open for receiving")
+	}
+
+	warehouse, err := s.warehouseRepo.WithTX(tx).WithLockForShare().GetWarehouseByID(ctx, req.WarehouseID.String())
+	if err != nil {
+		return model.GoodsReceipt{}, err
+	}
+
+	if warehouse.Status != constant.WarehouseStatusActive {
+		return model.GoodsReceipt{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse is not active")
+	}
+
+	var receivedProductIDs []string
+	for _, reqLine := range req.Lines {
+		if reqLine.ReceivedQuantity > 0 {
+			receivedProductIDs = append(receivedProductIDs, reqLine.ProductID.String())
+		}
+	}
+
+	// serialized units are received with their serial numbers, a receipt
+	// only carries quantities
+	if len(receivedProductIDs) > 0 {
+		serialized, err := s.stockSerialRepo.WithTX(tx).GetSerializedProducts(ctx, receivedProductIDs)
+		if err != nil {
+			return model.GoodsReceipt{}, err
+		}
+		if len(serialized) > 0 {
+			return model.GoodsReceipt{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product "+serialized[0].ProductID.String()+" is serialized, receive it by serial number")
+		}
+	}
+
+	lines := make(map[uuid.UUID]*model.PurchaseOrderLine)
+	for i := range purchaseOrder.Lines {
+		lines[purchaseOrder.Lines[i].ProductID] = &purchaseOrder.Lines[i]
+	}
+
+	receipt := model.GoodsReceipt{
+		PurchaseOrderID: purchaseOrder.ID,
+		WarehouseID:     req.WarehouseID,
+		Notes:           req.Notes,
+	}
+
+	var discrepancies []model.PurchaseOrderDiscrepancy
+	var productIDs []string
+	for _, reqLine := range req.Lines {
+		line, ok := lines[reqLine.ProductID]
+		if !ok {
+			return model.GoodsReceipt{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product is not on the purchase order")
+		}
+
+		if reqLine.ReceivedQuantity+reqLine.RejectedQuantity <= 0 {
+			return model.GoodsReceipt{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "received or rejected quantity must be greater than zero")
+		}
+
+		if reqLine.ReceivedQuantity > 0 {
+			remaining := max(line.ExpectedQuantity-line.ReceivedQuantity, 0)
+			if over := reqLine.ReceivedQuantity - remaining; over > 0 {
+				discrepancies = append(discrepancies, model.PurchaseOrderDiscrepancy{
+					PurchaseOrderID: purchaseOrder.ID,
+					ProductID:       reqLine.ProductID,
+					Type:            constant.DiscrepancyTypeOver,
+					Quantity:        over,
+					Note:            reqLine.Note,
+				})
+			}
+
+			if err := s.purchaseOrderRepo.WithTX(tx).AddLineReceivedQty(ctx, line.ID.String(), reqLine.ReceivedQuantity); err != nil {
+				return model.GoodsReceipt{}, err
+			}
+
+			if err := s.stockRepo.WithTX(tx).IncreaseStockQty(ctx, reqLine.ProductID.String(), req.WarehouseID.String(), reqLine.ReceivedQuantity); err != nil {
+				return model.GoodsReceipt{}, err
+			}
+
+			line.ReceivedQuantity += reqLine.ReceivedQuantity
+			productIDs = append(productIDs, reqLine.ProductID.String())
+		}
+
+		if reqLine.RejectedQuantity > 0 {
+			discrepancies = append(discrepancies, model.PurchaseOrderDiscrepancy{
+				PurchaseOrderID: purchaseOrder.ID,
+				ProductID:       reqLine.ProductID,
+				Type:            constant.DiscrepancyTypeDamaged,
+				Quantity:        reqLine.RejectedQuantity,
+				Note:            reqLine.Note,
+			})
+		}
+
+		receipt.Lines = append(receipt.Lines, model.GoodsReceiptLine{
+			PurchaseOrderLineID: line.ID,
+			ProductID:           reqLine.ProductID,
+			ReceivedQuantity:    reqLine.ReceivedQuantity,
+			RejectedQuantity:    reqLine.RejectedQuantity,
+		})
+	}
+
+	if err := s.purchaseOrderRepo.WithTX(tx).CreateGoodsReceipt(ctx, &receipt); err != nil {
+		return model.GoodsReceipt{}, err
+	}
+
+	if len(discrepancies) > 0 {
+		for i := range discrepancies {
+			discrepancies[i].GoodsReceiptID = &receipt.ID
+		}
+		if err := s.purchaseOrderRepo.WithTX(tx).CreateDiscrepancies(ctx, discrepancies); err != nil {
+			return model.GoodsReceipt{}, err
+		}
+	}
+
+	purchaseOrder.Status = constant.PurchaseOrderStatusClosed
+	for _, line := range purchaseOrder.Lines {
+		if line.ReceivedQuantity < line.ExpectedQuantity {
+			purchaseOrder.Status = constant.PurchaseOrderStatusPartiallyReceived
+			break
+		}
+	}
+	if purchaseOrder.Status == constant.PurchaseOrderStatusClosed {
+		now := time.Now()
+		purchaseOrder.ClosedAt = &now
+	}
+
+	if err := s.purchaseOrderRepo.WithTX(tx).UpdatePurchaseOrder(ctx, &purchaseOrder); err != nil {
+		return model.GoodsReceipt{}, err
+	}
+
+	// backorders and alerts are settled with the receipt, so the received
+	// stock is never committed without them
+	var alerts []model.StockAlert
+	if len(productIDs) > 0 {
+		alerts, err = s.stockService.SettleStockChanges(ctx, tx, productIDs)
+		if err != nil {
+			return model.GoodsReceipt{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return model.GoodsReceipt{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.logger.WithContext(ctx).Infow("Goods received",
+		"purchase_order_id", purchaseOrder.ID,
+		"goods_receipt_id", receipt.ID,
+		"warehouse_id", req.WarehouseID,
+		"status", purchaseOrder.Status,
+	)
+
+	if len(productIDs) > 0 {
+		s.stockService.PublishStockChanges(ctx, productIDs, alerts)
+	}
+
+	return receipt, nil
+}
+
+// ClosePurchaseOrder closes an open purchase order before it is fully received
+// and records the outstanding quantity of every line as a short delivery.
+func (s *purchaseOrderService) ClosePurchaseOrder(ctx context.Context, req payload.ClosePurchaseOrderReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "purchaseOrderService.ClosePurchaseOrder")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	purchaseOrder, err := s.purchaseOrderRepo.WithTX(tx).WithLockForUpdate().GetPurchaseOrderByID(ctx, req.ID.String())
+	if err != nil {
+		return err
+	}
+
+	if purchaseOrder.Status != constant.PurchaseOrderStatusOrdered && purchaseOrder.Status != constant.PurchaseOrderStatusPartiallyReceived {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "purchase order is not open for closing")
+	}
+
+	var discrepancies []model.PurchaseOrderDiscrepancy
+	for _, line := range purchaseOrder.Lines {
+		if short := line.ExpectedQuantity - line.ReceivedQuantity; short > 0 {
+			discrepancies = append(discrepancies, model.PurchaseOrderDiscrepancy{
+				PurchaseOrderID: purchaseOrder.ID,
+				ProductID:       line.ProductID,
+				Type:            constant.DiscrepancyTypeShort,
+				Quantity:        short,
+				Note:            req.Note,
+			})
+		}
+	}
+
+	if len(discrepancies) > 0 {
+		if err := s.purchaseOrderRepo.WithTX(tx).CreateDiscrepancies(ctx, discrepancies); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	purchaseOrder.Status = constant.PurchaseOrderStatusClosed
+	purchaseOrder.ClosedAt = &now
+	if err := s.purchaseOrderRepo.WithTX(tx).UpdatePurchaseOrder(ctx, &purchaseOrder); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return nil
+}
@@ -0,0 +1,832 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder/payload"
+	purchaseOrderRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/purchaseorder/repository/mocks"
+	stockRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository/mocks"
+	stockSvcMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service/mocks"
+	warehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository/mocks"
+)
+
+func TestCreatePurchaseOrder_ShouldSuccess(t *testing.T) {
+	productID := uuid.New()
+	req := payload.CreatePurchaseOrderReq{
+		SupplierName: "Acme",
+		Lines: []payload.CreatePurchaseOrderLine{
+			{
+				ProductID:        productID,
+				ExpectedQuantity: 10,
+			},
+		},
+	}
+
+	purchaseOrderRepo := purchaseOrderRepoMock.NewPurchaseOrderRepository(t)
+	purchaseOrderRepo.On("CreatePurchaseOrder", mock.Anything, &model.PurchaseOrder{
+		SupplierName: "Acme",
+		Status:       constant.PurchaseOrderStatusDraft,
+		Lines: []model.PurchaseOrderLine{
+			{
+				ProductID:        productID,
+				ExpectedQuantity: 10,
+			},
+		},
+	}).Return(nil)
+
+	purchaseOrderSvc := purchaseOrderService{
+		logger:            pkg.InitLogger(&config.Config{}),
+		purchaseOrderRepo: purchaseOrderRepo,
+	}
+
+	result, err := purchaseOrderSvc.CreatePurchaseOrder(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, constant.PurchaseOrderStatusDraft, result.Status)
+	assert.Len(t, result.Lines, 1)
+}
+
+func TestCreatePurchaseOrder_ShouldReturnError(t *testing.T) {
+	productID := uuid.New()
+
+	tests := []struct {
+		name  string
+		req   payload.CreatePurchaseOrderReq
+		setup func(purchaseOrderRepo *purchaseOrderRepoMock.PurchaseOrderRepository)
+	}{
+		{
+			name: "error - duplicate product",
+			req: payload.CreatePurchaseOrderReq{
+				SupplierName: "Acme",
+				Lines: []payload.CreatePurchaseOrderLine{
+					{ProductID: productID, ExpectedQuantity: 10},
+					{ProductID: productID, ExpectedQuantity: 5},
+				},
+			},
+			setup: func(purchaseOrderRepo *purchaseOrderRepoMock.PurchaseOrderRepository) {},
+		},
+		{
+			name: "error - create purchase order failure",
+			req: payload.CreatePurchaseOrderReq{
+				SupplierName: "Acme",
+				Lines: []payload.CreatePurchaseOrderLine{
+					{ProductID: productID, ExpectedQuantity: 10},
+				},
+			},
+			setup: func(purchaseOrderRepo *purchaseOrderRepoMock.PurchaseOrderRepository) {
+				purchaseOrderRepo.On("CreatePurchaseOrder", mock.Anything, mock.Anything).
+					Return(errors.New("database error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			purchaseOrderRepo := purchaseOrderRepoMock.NewPurchaseOrderRepository(t)
+			purchaseOrderSvc := purchaseOrderService{
+				logger:            pkg.InitLogger(&config.Config{}),
+				purchaseOrderRepo: purchaseOrderRepo,
+			}
+
+			tt.setup(purchaseOrderRepo)
+
+			// When
+			_, err := purchaseOrderSvc.CreatePurchaseOrder(context.Background(), tt.req)
+
+			// Then
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSubmitPurchaseOrder(t *testing.T) {
+	type dependencyMocks struct {
+		db                sqlmock.Sqlmock
+		purchaseOrderRepo *purchaseOrderRepoMock.PurchaseOrderRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	purchaseOrderID := uuid.New()
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - draft purchase order is ordered",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(model.PurchaseOrder{ID: purchaseOrderID, Status: constant.PurchaseOrderStatusDraft}, nil)
+				m.purchaseOrderRepo.On("UpdatePurchaseOrder", mock.Anything, mock.MatchedBy(func(po *model.PurchaseOrder) bool {
+					return po.Status == constant.PurchaseOrderStatusOrdered && po.OrderedAt != nil
+				})).
+					Return(nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - purchase order is not a draft",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(model.PurchaseOrder{ID: purchaseOrderID, Status: constant.PurchaseOrderStatusClosed}, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:                mockDb.Mock,
+				purchaseOrderRepo: purchaseOrderRepoMock.NewPurchaseOrderRepository(t),
+			}
+			purchaseOrderSvc := purchaseOrderService{
+				logger:            pkg.InitLogger(&config.Config{}),
+				db:                mockDb.Db,
+				purchaseOrderRepo: mocks.purchaseOrderRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := purchaseOrderSvc.SubmitPurchaseOrder(context.Background(), purchaseOrderID.String())
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestReceiveGoods_ShouldSuccess(t *testing.T) {
+	type dependencyMocks struct {
+		db                sqlmock.Sqlmock
+		purchaseOrderRepo *purchaseOrderRepoMock.PurchaseOrderRepository
+		stockRepo         *stockRepoMock.StockRepository
+		stockSerialRepo   *stockRepoMock.StockSerialRepository
+		warehouseRepo     *warehouseRepoMock.WarehouseRepository
+		stockService      *stockSvcMock.StockService
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	purchaseOrderID := uuid.New()
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	productID2 := uuid.New()
+	lineID := uuid.New()
+	lineID2 := uuid.New()
+	note := "2 boxes crushed"
+
+	purchaseOrder := func(status string, received int) model.PurchaseOrder {
+		return model.PurchaseOrder{
+			ID:     purchaseOrderID,
+			Status: status,
+			Lines: []model.PurchaseOrderLine{
+				{ID: lineID, ProductID: productID, ExpectedQuantity: 10, ReceivedQuantity: received},
+				{ID: lineID2, ProductID: productID2, ExpectedQuantity: 5, ReceivedQuantity: 5},
+			},
+		}
+	}
+
+	expectSerialized := func(m dependencyMocks, products ...model.SerializedProduct) {
+		m.stockSerialRepo.On("WithTX", mock.Anything).
+			Return(m.stockSerialRepo)
+		m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, mock.Anything).
+			Return(products, nil)
+	}
+
+	tests := []struct {
+		name           string
+		req            payload.ReceiveGoodsReq
+		setup          func(m dependencyMocks)
+		expectedStatus string
+	}{
+		{
+			name: "success - partial receipt",
+			req: payload.ReceiveGoodsReq{
+				PurchaseOrderID: purchaseOrderID,
+				WarehouseID:     warehouseID,
+				Lines: []payload.ReceiveGoodsLine{
+					{ProductID: productID, ReceivedQuantity: 4},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(purchaseOrder(constant.PurchaseOrderStatusOrdered, 0), nil)
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				expectSerialized(m)
+
+				m.purchaseOrderRepo.On("AddLineReceivedQty", mock.Anything, lineID.String(), 4).
+					Return(nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 4).
+					Return(nil)
+
+				m.purchaseOrderRepo.On("CreateGoodsReceipt", mock.Anything, mock.Anything).
+					Return(nil)
+				m.purchaseOrderRepo.On("UpdatePurchaseOrder", mock.Anything, mock.MatchedBy(func(po *model.PurchaseOrder) bool {
+					return po.Status == constant.PurchaseOrderStatusPartiallyReceived && po.ClosedAt == nil
+				})).
+					Return(nil)
+
+				m.stockService.On("SettleStockChanges", mock.Anything, mock.Anything, []string{productID.String()}).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+
+				m.stockService.On("PublishStockChanges", mock.Anything, []string{productID.String()}, []model.StockAlert{}).
+					Return()
+			},
+			expectedStatus: constant.PurchaseOrderStatusPartiallyReceived,
+		},
+		{
+			name: "success - full receipt with over delivery and damaged goods closes purchase order",
+			req: payload.ReceiveGoodsReq{
+				PurchaseOrderID: purchaseOrderID,
+				WarehouseID:     warehouseID,
+				Lines: []payload.ReceiveGoodsLine{
+					{ProductID: productID, ReceivedQuantity: 8, RejectedQuantity: 2, Note: &note},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(purchaseOrder(constant.PurchaseOrderStatusPartiallyReceived, 4), nil)
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				expectSerialized(m)
+
+				m.purchaseOrderRepo.On("AddLineReceivedQty", mock.Anything, lineID.String(), 8).
+					Return(nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 8).
+					Return(nil)
+
+				m.purchaseOrderRepo.On("CreateGoodsReceipt", mock.Anything, mock.Anything).
+					Return(nil)
+				m.purchaseOrderRepo.On("CreateDiscrepancies", mock.Anything, mock.MatchedBy(func(d []model.PurchaseOrderDiscrepancy) bool {
+					return len(d) == 2 &&
+						d[0].Type == constant.DiscrepancyTypeOver && d[0].Quantity == 2 &&
+						d[1].Type == constant.DiscrepancyTypeDamaged && d[1].Quantity == 2 &&
+						d[1].GoodsReceiptID != nil
+				})).
+					Return(nil)
+				m.purchaseOrderRepo.On("UpdatePurchaseOrder", mock.Anything, mock.MatchedBy(func(po *model.PurchaseOrder) bool {
+					return po.Status == constant.PurchaseOrderStatusClosed && po.ClosedAt != nil
+				})).
+					Return(nil)
+
+				m.stockService.On("SettleStockChanges", mock.Anything, mock.Anything, []string{productID.String()}).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+
+				m.stockService.On("PublishStockChanges", mock.Anything, []string{productID.String()}, []model.StockAlert{}).
+					Return()
+			},
+			expectedStatus: constant.PurchaseOrderStatusClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:                mockDb.Mock,
+				purchaseOrderRepo: purchaseOrderRepoMock.NewPurchaseOrderRepository(t),
+				stockRepo:         stockRepoMock.NewStockRepository(t),
+				stockSerialRepo:   stockRepoMock.NewStockSerialRepository(t),
+				warehouseRepo:     warehouseRepoMock.NewWarehouseRepository(t),
+				stockService:      stockSvcMock.NewStockService(t),
+			}
+			purchaseOrderSvc := purchaseOrderService{
+				logger:            pkg.InitLogger(&config.Config{}),
+				db:                mockDb.Db,
+				purchaseOrderRepo: mocks.purchaseOrderRepo,
+				stockRepo:         mocks.stockRepo,
+				stockSerialRepo:   mocks.stockSerialRepo,
+				warehouseRepo:     mocks.warehouseRepo,
+				stockService:      mocks.stockService,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			receipt, err := purchaseOrderSvc.ReceiveGoods(context.Background(), tt.req)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, warehouseID, receipt.WarehouseID)
+			assert.Len(t, receipt.Lines, len(tt.req.Lines))
+			mocks.purchaseOrderRepo.AssertExpectations(t)
+			mocks.stockRepo.AssertExpectations(t)
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestReceiveGoods_ShouldReturnError(t *testing.T) {
+	type dependencyMocks struct {
+		db                sqlmock.Sqlmock
+		purchaseOrderRepo *purchaseOrderRepoMock.PurchaseOrderRepository
+		stockRepo         *stockRepoMock.StockRepository
+		stockSerialRepo   *stockRepoMock.StockSerialRepository
+		warehouseRepo     *warehouseRepoMock.WarehouseRepository
+		stockService      *stockSvcMock.StockService
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	purchaseOrderID := uuid.New()
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	lineID := uuid.New()
+
+	openPurchaseOrder := model.PurchaseOrder{
+		ID:     purchaseOrderID,
+		Status: constant.PurchaseOrderStatusOrdered,
+		Lines: []model.PurchaseOrderLine{
+			{ID: lineID, ProductID: productID, ExpectedQuantity: 10},
+		},
+	}
+	req := payload.ReceiveGoodsReq{
+		PurchaseOrderID: purchaseOrderID,
+		WarehouseID:     warehouseID,
+		Lines: []payload.ReceiveGoodsLine{
+			{ProductID: productID, ReceivedQuantity: 4},
+		},
+	}
+
+	expectSerialized := func(m dependencyMocks, products ...model.SerializedProduct) {
+		m.stockSerialRepo.On("WithTX", mock.Anything).
+			Return(m.stockSerialRepo)
+		m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, mock.Anything).
+			Return(products, nil)
+	}
+
+	tests := []struct {
+		name  string
+		req   payload.ReceiveGoodsReq
+		setup func(m dependencyMocks)
+	}{
+		{
+			name: "error - purchase order not found",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(model.PurchaseOrder{}, errors.New("purchase order not found"))
+
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - purchase order is still a draft",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(model.PurchaseOrder{ID: purchaseOrderID, Status: constant.PurchaseOrderStatusDraft}, nil)
+
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - warehouse is not active",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(openPurchaseOrder, nil)
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusInactive}, nil)
+
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - product is not on the purchase order",
+			req: payload.ReceiveGoodsReq{
+				PurchaseOrderID: purchaseOrderID,
+				WarehouseID:     warehouseID,
+				Lines: []payload.ReceiveGoodsLine{
+					{ProductID: uuid.New(), ReceivedQuantity: 4},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(openPurchaseOrder, nil)
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				expectSerialized(m)
+
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - nothing received or rejected",
+			req: payload.ReceiveGoodsReq{
+				PurchaseOrderID: purchaseOrderID,
+				WarehouseID:     warehouseID,
+				Lines: []payload.ReceiveGoodsLine{
+					{ProductID: productID},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(openPurchaseOrder, nil)
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - product is serialized",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(openPurchaseOrder, nil)
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				expectSerialized(m, model.SerializedProduct{ProductID: productID})
+
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - increase stock failure",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(openPurchaseOrder, nil)
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				expectSerialized(m)
+
+				m.purchaseOrderRepo.On("AddLineReceivedQty", mock.Anything, lineID.String(), 4).
+					Return(nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 4).
+					Return(errors.New("database error"))
+
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - settling backorders and alerts failure rolls the receipt back",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(openPurchaseOrder, nil)
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				expectSerialized(m)
+
+				m.purchaseOrderRepo.On("AddLineReceivedQty", mock.Anything, lineID.String(), 4).
+					Return(nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 4).
+					Return(nil)
+				m.purchaseOrderRepo.On("CreateGoodsReceipt", mock.Anything, mock.Anything).
+					Return(nil)
+				m.purchaseOrderRepo.On("UpdatePurchaseOrder", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockService.On("SettleStockChanges", mock.Anything, mock.Anything, []string{productID.String()}).
+					Return(nil, errors.New("database error"))
+
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - transaction commit failure",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(openPurchaseOrder, nil)
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				expectSerialized(m)
+
+				m.purchaseOrderRepo.On("AddLineReceivedQty", mock.Anything, lineID.String(), 4).
+					Return(nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 4).
+					Return(nil)
+				m.purchaseOrderRepo.On("CreateGoodsReceipt", mock.Anything, mock.Anything).
+					Return(nil)
+				m.purchaseOrderRepo.On("UpdatePurchaseOrder", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockService.On("SettleStockChanges", mock.Anything, mock.Anything, []string{productID.String()}).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit().WillReturnError(errors.New("commit failed"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:                mockDb.Mock,
+				purchaseOrderRepo: purchaseOrderRepoMock.NewPurchaseOrderRepository(t),
+				stockRepo:         stockRepoMock.NewStockRepository(t),
+				stockSerialRepo:   stockRepoMock.NewStockSerialRepository(t),
+				warehouseRepo:     warehouseRepoMock.NewWarehouseRepository(t),
+				stockService:      stockSvcMock.NewStockService(t),
+			}
+			purchaseOrderSvc := purchaseOrderService{
+				logger:            pkg.InitLogger(&config.Config{}),
+				db:                mockDb.Db,
+				purchaseOrderRepo: mocks.purchaseOrderRepo,
+				stockRepo:         mocks.stockRepo,
+				stockSerialRepo:   mocks.stockSerialRepo,
+				warehouseRepo:     mocks.warehouseRepo,
+				stockService:      mocks.stockService,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			_, err := purchaseOrderSvc.ReceiveGoods(context.Background(), tt.req)
+
+			// Then
+			assert.Error(t, err)
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestClosePurchaseOrder(t *testing.T) {
+	type dependencyMocks struct {
+		db                sqlmock.Sqlmock
+		purchaseOrderRepo *purchaseOrderRepoMock.PurchaseOrderRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	purchaseOrderID := uuid.New()
+	productID := uuid.New()
+	productID2 := uuid.New()
+	note := "supplier out of stock"
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - outstanding lines are recorded as short",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(model.PurchaseOrder{
+						ID:     purchaseOrderID,
+						Status: constant.PurchaseOrderStatusPartiallyReceived,
+						Lines: []model.PurchaseOrderLine{
+							{ProductID: productID, ExpectedQuantity: 10, ReceivedQuantity: 4},
+							{ProductID: productID2, ExpectedQuantity: 5, ReceivedQuantity: 5},
+						},
+					}, nil)
+				m.purchaseOrderRepo.On("CreateDiscrepancies", mock.Anything, []model.PurchaseOrderDiscrepancy{
+					{
+						PurchaseOrderID: purchaseOrderID,
+						ProductID:       productID,
+						Type:            constant.DiscrepancyTypeShort,
+						Quantity:        6,
+						Note:            &note,
+					},
+				}).
+					Return(nil)
+				m.purchaseOrderRepo.On("UpdatePurchaseOrder", mock.Anything, mock.MatchedBy(func(po *model.PurchaseOrder) bool {
+					return po.Status == constant.PurchaseOrderStatusClosed && po.ClosedAt != nil
+				})).
+					Return(nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - purchase order is already closed",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.purchaseOrderRepo.On("WithTX", mock.Anything).
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("WithLockForUpdate").
+					Return(m.purchaseOrderRepo)
+				m.purchaseOrderRepo.On("GetPurchaseOrderByID", mock.Anything, purchaseOrderID.String()).
+					Return(model.PurchaseOrder{ID: purchaseOrderID, Status: constant.PurchaseOrderStatusClosed}, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:                mockDb.Mock,
+				purchaseOrderRepo: purchaseOrderRepoMock.NewPurchaseOrderRepository(t),
+			}
+			purchaseOrderSvc := purchaseOrderService{
+				logger:            pkg.InitLogger(&config.Config{}),
+				db:                mockDb.Db,
+				purchaseOrderRepo: mocks.purchaseOrderRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := purchaseOrderSvc.ClosePurchaseOrder(context.Background(), payload.ClosePurchaseOrderReq{
+				ID:   purchaseOrderID,
+				Note: &note,
+			})
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
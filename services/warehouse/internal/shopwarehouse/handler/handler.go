@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/middleware"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/registry"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/service"
+	"github.com/gin-gonic/gin"
+)
+
+type shopWarehouseHandler struct {
+	router               *gin.Engine
+	config               *config.Config
+	logger               *pkg.Logger
+	shopWarehouseService service.ShopWarehouseService
+}
+
+func NewHandler(rt *gin.Engine, cfg *config.Config, logger *pkg.Logger, shopWarehouseSvc service.ShopWarehouseService) registry.Router {
+	return &shopWarehouseHandler{
+		shopWarehouseService: shopWarehouseSvc,
+		router:               rt,
+		config:               cfg,
+		logger:               logger,
+	}
+}
+
+func (h shopWarehouseHandler) RegisterRoutes(base *gin.RouterGroup) {
+	g := base.Group("/shop-warehouses")
+
+	g.Use(middleware.JwtMiddleware(h.config))
+
+	g.GET("", h.GetShopWarehouses)
+	g.POST("", h.CreateShopWarehouse)
+	g.PUT("/:id", h.UpdateShopWarehouse)
+	g.DELETE("/:id", h.DeleteShopWarehouse)
+}
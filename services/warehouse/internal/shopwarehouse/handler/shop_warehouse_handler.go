@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/httpresp"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/utils"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/payload"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// @Summary		Shop Warehouse - Get Shop Warehouses
+// @Description	get shop to warehouse assignments ordered by shop and priority
+// @Tags		Shop Warehouse
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetShopWarehousesReq	false	"get shop warehouses request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]model.ShopWarehouse}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/shop-warehouses [get]
+func (h *shopWarehouseHandler) GetShopWarehouses(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "shopWarehouseHandler.GetShopWarehouses")
+	defer span.End()
+
+	var req payload.GetShopWarehousesReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	shopWarehouses, err := h.shopWarehouseService.GetShopWarehouses(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, shopWarehouses, nil)
+}
+
+// @Summary		Shop Warehouse - Create Shop Warehouse
+// @Description	assign a warehouse to a shop
+// @Tags		Shop Warehouse
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.CreateShopWarehouseReq	true	"create shop warehouse request body"
+// @Success		200	{object}	httpresp.Response{data=model.ShopWarehouse}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/shop-warehouses [post]
+func (h *shopWarehouseHandler) CreateShopWarehouse(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "shopWarehouseHandler.CreateShopWarehouse")
+	defer span.End()
+
+	var req payload.CreateShopWarehouseReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	shopWarehouse, err := h.shopWarehouseService.CreateShopWarehouse(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, shopWarehouse, nil)
+}
+
+// @Summary		Shop Warehouse - Update Shop Warehouse
+// @Description	update the reservation priority of a shop warehouse assignment
+// @Tags		Shop Warehouse
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"shop warehouse ID"
+// @Param		request	body	payload.UpdateShopWarehouseReq	true	"update shop warehouse request body"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/shop-warehouses/{id} [put]
+func (h *shopWarehouseHandler) UpdateShopWarehouse(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "shopWarehouseHandler.UpdateShopWarehouse")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid shop warehouse ID"))
+		return
+	}
+
+	var req payload.UpdateShopWarehouseReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ID = id
+	if err := h.shopWarehouseService.UpdateShopWarehouse(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Shop Warehouse - Delete Shop Warehouse
+// @Description	remove a warehouse from a shop
+// @Tags		Shop Warehouse
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"shop warehouse ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/shop-warehouses/{id} [delete]
+func (h *shopWarehouseHandler) DeleteShopWarehouse(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "shopWarehouseHandler.DeleteShopWarehouse")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid shop warehouse ID"))
+		return
+	}
+
+	if err := h.shopWarehouseService.DeleteShopWarehouse(ctx, id.String()); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetShopWarehouses_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		queries            string
+		mockResult         []model.ShopWarehouse
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			queries:            "?shop_id_in=8f1cc115-4434-4829-81c4-23fb01aa0dc0",
+			statusCodeExpected: http.StatusOK,
+			mockResult: []model.ShopWarehouse{
+				{
+					ID:          uuid.New(),
+					ShopID:      uuid.New(),
+					WarehouseID: uuid.New(),
+					Priority:    1,
+				},
+			},
+		},
+		{
+			testName:           "failed - invalid shop id",
+			queries:            "?shop_id_in=invalid-uuid",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get shop warehouses",
+			queries:            "",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockShopWarehouseSvc := &mocks.ShopWarehouseService{}
+			mockShopWarehouseSvc.
+				On("GetShopWarehouses", mock.Anything, mock.Anything).
+				Return(scenario.mockResult, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/shop-warehouses"+scenario.queries, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &shopWarehouseHandler{
+				router:               r,
+				config:               mockConfig,
+				shopWarehouseService: mockShopWarehouseSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestCreateShopWarehouse_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockReq:            `{"shop_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "warehouse_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "priority": 1}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - missing warehouse id",
+			mockReq:            `{"shop_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0"}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - negative priority",
+			mockReq:            `{"shop_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "warehouse_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "priority": -1}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle create shop warehouse",
+			mockReq:            `{"shop_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "warehouse_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0"}`,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockShopWarehouseSvc := &mocks.ShopWarehouseService{}
+			mockShopWarehouseSvc.
+				On("CreateShopWarehouse", mock.Anything, mock.Anything).
+				Return(model.ShopWarehouse{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/shop-warehouses", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &shopWarehouseHandler{
+				router:               r,
+				config:               mockConfig,
+				shopWarehouseService: mockShopWarehouseSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestUpdateShopWarehouse_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		id                 string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			id:                 uuid.New().String(),
+			mockReq:            `{"priority": 0}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid id",
+			id:                 "invalid-uuid",
+			mockReq:            `{"priority": 0}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - missing priority",
+			id:                 uuid.New().String(),
+			mockReq:            `{}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle update shop warehouse",
+			id:                 uuid.New().String(),
+			mockReq:            `{"priority": 2}`,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockShopWarehouseSvc := &mocks.ShopWarehouseService{}
+			mockShopWarehouseSvc.
+				On("UpdateShopWarehouse", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/shop-warehouses/"+scenario.id, strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &shopWarehouseHandler{
+				router:               r,
+				config:               mockConfig,
+				shopWarehouseService: mockShopWarehouseSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestDeleteShopWarehouse_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		id                 string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			id:                 uuid.New().String(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid id",
+			id:                 "invalid-uuid",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle delete shop warehouse",
+			id:                 uuid.New().String(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockShopWarehouseSvc := &mocks.ShopWarehouseService{}
+			mockShopWarehouseSvc.
+				On("DeleteShopWarehouse", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodDelete, "/shop-warehouses/"+scenario.id, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &shopWarehouseHandler{
+				router:               r,
+				config:               mockConfig,
+				shopWarehouseService: mockShopWarehouseSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
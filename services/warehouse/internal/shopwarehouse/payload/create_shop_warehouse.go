@@ -0,0 +1,9 @@
+package payload
+
+import "github.com/google/uuid"
+
+type CreateShopWarehouseReq struct {
+	ShopID      uuid.UUID `json:"shop_id" binding:"required"`
+	WarehouseID uuid.UUID `json:"warehouse_id" binding:"required"`
+	Priority    int       `json:"priority" binding:"omitempty,min=0"`
+}
@@ -0,0 +1,6 @@
+package payload
+
+type GetShopWarehousesReq struct {
+	ShopIDIN      []string `form:"shop_id_in" binding:"omitempty,dive,uuid"`
+	WarehouseIDIN []string `form:"warehouse_id_in" binding:"omitempty,dive,uuid"`
+}
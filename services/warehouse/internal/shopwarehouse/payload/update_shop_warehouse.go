@@ -0,0 +1,8 @@
+package payload
+
+import "github.com/google/uuid"
+
+type UpdateShopWarehouseReq struct {
+	ID       uuid.UUID `json:"-"`
+	Priority *int      `json:"priority" binding:"required,min=0"`
+}
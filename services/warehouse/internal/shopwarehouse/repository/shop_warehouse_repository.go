@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/payload"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=ShopWarehouseRepository --case underscore
+type ShopWarehouseRepository interface {
+	WithTX(tx *gorm.DB) ShopWarehouseRepository
+	CreateShopWarehouse(ctx context.Context, shopWarehouse *model.ShopWarehouse) error
+	GetShopWarehouses(ctx context.Context, req payload.GetShopWarehousesReq) ([]model.ShopWarehouse, error)
+	GetShopWarehouseByID(ctx context.Context, id string) (model.ShopWarehouse, error)
+	UpdateShopWarehouse(ctx context.Context, shopWarehouse *model.ShopWarehouse) error
+	DeleteShopWarehouse(ctx context.Context, id string) error
+}
+
+type shopWarehouseRepository struct {
+	db *gorm.DB
+}
+
+func NewShopWarehouseRepository(db *gorm.DB) ShopWarehouseRepository {
+	return &shopWarehouseRepository{db: db}
+}
+
+func (r *shopWarehouseRepository) WithTX(tx *gorm.DB) ShopWarehouseRepository {
+	if tx == nil {
+		return r
+	}
+	return &shopWarehouseRepository{db: tx}
+}
+
+func (r *shopWarehouseRepository) CreateShopWarehouse(ctx context.Context, shopWarehouse *model.ShopWarehouse) error {
+	ctx, span := observ.GetTracer().Start(ctx, "shopWarehouseRepository.CreateShopWarehouse")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(shopWarehouse).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create shop warehouse")
+	}
+	return nil
+}
+
+// GetShopWarehouses returns assignments ordered by shop and then by priority,
+// which is the order reservations try the warehouses in.
+func (r *shopWarehouseRepository) GetShopWarehouses(ctx context.Context, req payload.GetShopWarehousesReq) ([]model.ShopWarehouse, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "shopWarehouseRepository.GetShopWarehouses")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx).Model(&model.ShopWarehouse{})
+	if len(req.ShopIDIN) > 0 {
+		stmt = stmt.Where("shop_id IN ?", req.ShopIDIN)
+	}
+
+	if len(req.WarehouseIDIN) > 0 {
+		stmt = stmt.Where("warehouse_id IN ?", req.WarehouseIDIN)
+	}
+
+	var shopWarehouses []model.ShopWarehouse
+	if err := stmt.Order("shop_id, priority, created_at").Find(&shopWarehouses).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get shop warehouses")
+	}
+	return shopWarehouses, nil
+}
+
+func (r *shopWarehouseRepository) GetShopWarehouseByID(ctx context.Context, id string) (model.ShopWarehouse, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "shopWarehouseRepository.GetShopWarehouseByID")
+	defer span.End()
+
+	var shopWarehouse model.ShopWarehouse
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&shopWarehouse).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if err == gorm.ErrRecordNotFound {
+			return model.ShopWarehouse{}, apperr.WrapWithCode(err, apperr.CodeHTTPNotFound, "shop warehouse not found")
+		}
+		return model.ShopWarehouse{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get shop warehouse by ID")
+	}
+	return shopWarehouse, nil
+}
+
+func (r *shopWarehouseRepository) UpdateShopWarehouse(ctx context.Context, shopWarehouse *model.ShopWarehouse) error {
+	ctx, span := observ.GetTracer().Start(ctx, "shopWarehouseRepository.UpdateShopWarehouse")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Save(shopWarehouse).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update shop warehouse")
+	}
+	return nil
+}
+
+func (r *shopWarehouseRepository) DeleteShopWarehouse(ctx context.Context, id string) error {
+	ctx, span := observ.GetTracer().Start(ctx, "shopWarehouseRepository.DeleteShopWarehouse")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.ShopWarehouse{})
+	if result.Error != nil {
+		span.SetStatus(codes.Error, result.Error.Error())
+		return apperr.WrapWithCode(result.Error, apperr.CodeHTTPInternalServerError, "failed to delete shop warehouse")
+	}
+
+	if result.RowsAffected == 0 {
+		span.SetStatus(codes.Error, "shop warehouse not found")
+		return apperr.NewWithCode(apperr.CodeHTTPNotFound, "shop warehouse not found")
+	}
+	return nil
+}
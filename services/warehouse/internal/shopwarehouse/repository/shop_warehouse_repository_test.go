@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/payload"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateShopWarehouse(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, data model.ShopWarehouse)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name string
+		data model.ShopWarehouse
+		sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success",
+			data: model.ShopWarehouse{
+				ShopID:      uuid.New(),
+				WarehouseID: uuid.New(),
+				Priority:    1,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.ShopWarehouse) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "shop_warehouses" ("shop_id","warehouse_id","priority","created_at","updated_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`,
+						),
+					).WithArgs(
+						data.ShopID,
+						data.WarehouseID,
+						data.Priority,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to create shop warehouse",
+			data: model.ShopWarehouse{
+				ShopID:      uuid.New(),
+				WarehouseID: uuid.New(),
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.ShopWarehouse) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "shop_warehouses" ("shop_id","warehouse_id","priority","created_at","updated_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.data)
+
+			repo := NewShopWarehouseRepository(mockDb.Db)
+
+			err := repo.CreateShopWarehouse(context.Background(), &tt.data)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetShopWarehouses(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetShopWarehousesReq)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		req     payload.GetShopWarehousesReq
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get shop warehouses",
+			req: payload.GetShopWarehousesReq{
+				ShopIDIN:      []string{uuid.New().String()},
+				WarehouseIDIN: []string{uuid.New().String()},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetShopWarehousesReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "shop_warehouses" WHERE shop_id IN ($1) AND warehouse_id IN ($2) ORDER BY shop_id, priority, created_at`,
+						),
+					).WithArgs(req.ShopIDIN[0], req.WarehouseIDIN[0]).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "shop_id", "warehouse_id", "priority"}).
+							AddRow(uuid.New(), req.ShopIDIN[0], req.WarehouseIDIN[0], 0),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get shop warehouses",
+			req:  payload.GetShopWarehousesReq{},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetShopWarehousesReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "shop_warehouses" ORDER BY shop_id, priority, created_at`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.req)
+
+			repo := NewShopWarehouseRepository(mockDb.Db)
+
+			shopWarehouses, err := repo.GetShopWarehouses(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, shopWarehouses, 1)
+		})
+	}
+}
+
+func TestGetShopWarehouseByID(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, id uuid.UUID)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get shop warehouse by id",
+			id:   uuid.New(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, id uuid.UUID) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "shop_warehouses" WHERE id = $1 ORDER BY "shop_warehouses"."id" LIMIT $2`,
+						),
+					).WithArgs(id.String(), 1).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "shop_id", "warehouse_id", "priority"}).
+							AddRow(id, uuid.New(), uuid.New(), 1),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - shop warehouse not found",
+			id:   uuid.New(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, id uuid.UUID) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "shop_warehouses" WHERE id = $1 ORDER BY "shop_warehouses"."id" LIMIT $2`,
+						),
+					).WithArgs(id.String(), 1).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}),
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.id)
+
+			repo := NewShopWarehouseRepository(mockDb.Db)
+
+			shopWarehouse, err := repo.GetShopWarehouseByID(context.Background(), tt.id.String())
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.id, shopWarehouse.ID)
+		})
+	}
+}
+
+func TestDeleteShopWarehouse(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, id uuid.UUID)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - delete shop warehouse",
+			id:   uuid.New(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, id uuid.UUID) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`DELETE FROM "shop_warehouses" WHERE id = $1`,
+						),
+					).WithArgs(id.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - shop warehouse not found",
+			id:   uuid.New(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, id uuid.UUID) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`DELETE FROM "shop_warehouses" WHERE id = $1`,
+						),
+					).WithArgs(id.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.id)
+
+			repo := NewShopWarehouseRepository(mockDb.Db)
+
+			err := repo.DeleteShopWarehouse(context.Background(), tt.id.String())
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/repository"
+	warehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
+	"go.opentelemetry.io/otel/codes"
+)
+
+//go:generate mockery --name=ShopWarehouseService --case underscore
+type ShopWarehouseService interface {
+	CreateShopWarehouse(ctx context.Context, req payload.CreateShopWarehouseReq) (model.ShopWarehouse, error)
+	GetShopWarehouses(ctx context.Context, req payload.GetShopWarehousesReq) ([]model.ShopWarehouse, error)
+	UpdateShopWarehouse(ctx context.Context, req payload.UpdateShopWarehouseReq) error
+	DeleteShopWarehouse(ctx context.Context, id string) error
+}
+
+type shopWarehouseService struct {
+	config            *config.Config
+	logger            *pkg.Logger
+	shopWarehouseRepo repository.ShopWarehouseRepository
+	warehouseRepo     warehouserepository.WarehouseRepository
+}
+
+func NewShopWarehouseService(
+	config *config.Config,
+	logger *pkg.Logger,
+	shopWarehouseRepo repository.ShopWarehouseRepository,
+	warehouseRepo warehouserepository.WarehouseRepository,
+) ShopWarehouseService {
+	return &shopWarehouseService{
+		config:            config,
+		logger:            logger,
+		shopWarehouseRepo: shopWarehouseRepo,
+		warehouseRepo:     warehouseRepo,
+	}
+}
+
+func (s *shopWarehouseService) CreateShopWarehouse(ctx context.Context, req payload.CreateShopWarehouseReq) (result model.ShopWarehouse, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "shopWarehouseService.CreateShopWarehouse")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	warehouse, err := s.warehouseRepo.GetWarehouseByID(ctx, req.WarehouseID.String())
+	if err != nil {
+		return model.ShopWarehouse{}, err
+	}
+
+	if warehouse.Status == constant.WarehouseStatusArchived {
+		return model.ShopWarehouse{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse is archived")
+	}
+
+	existing, err := s.shopWarehouseRepo.GetShopWarehouses(ctx, payload.GetShopWarehousesReq{
+		ShopIDIN:      []string{req.ShopID.String()},
+		WarehouseIDIN: []string{req.WarehouseID.String()},
+	})
+	if err != nil {
+		return model.ShopWarehouse{}, err
+	}
+
+	if len(existing) > 0 {
+		return model.ShopWarehouse{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse is already assigned to the shop")
+	}
+
+	shopWarehouse := model.ShopWarehouse{
+		ShopID:      req.ShopID,
+		WarehouseID: req.WarehouseID,
+		Priority:    req.Priority,
+	}
+	if err := s.shopWarehouseRepo.CreateShopWarehouse(ctx, &shopWarehouse); err != nil {
+		return model.ShopWarehouse{}, err
+	}
+
+	return shopWarehouse, nil
+}
+
+func (s *shopWarehouseService) GetShopWarehouses(ctx context.Context, req payload.GetShopWarehousesReq) (result []model.ShopWarehouse, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "shopWarehouseService.GetShopWarehouses")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	shopWarehouses, err := s.shopWarehouseRepo.GetShopWarehouses(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return shopWarehouses, nil
+}
+
+func (s *shopWarehouseService) UpdateShopWarehouse(ctx context.Context, req payload.UpdateShopWarehouseReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "shopWarehouseService.UpdateShopWarehouse")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	shopWarehouse, err := s.shopWarehouseRepo.GetShopWarehouseByID(ctx, req.ID.String())
+	if err != nil {
+		return err
+	}
+
+	shopWarehouse.Priority = *req.Priority
+	if err := s.shopWarehouseRepo.UpdateShopWarehouse(ctx, &shopWarehouse); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *shopWarehouseService) DeleteShopWarehouse(ctx context.Context, id string) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "shopWarehouseService.DeleteShopWarehouse")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if err := s.shopWarehouseRepo.DeleteShopWarehouse(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
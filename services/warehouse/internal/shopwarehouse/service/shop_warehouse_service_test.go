@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/payload"
+	shopWarehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/repository/mocks"
+	warehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository/mocks"
+)
+
+func TestCreateShopWarehouse(t *testing.T) {
+	type dependencyMocks struct {
+		shopWarehouseRepo *shopWarehouseRepoMock.ShopWarehouseRepository
+		warehouseRepo     *warehouseRepoMock.WarehouseRepository
+	}
+
+	shopID := uuid.New()
+	warehouseID := uuid.New()
+	req := payload.CreateShopWarehouseReq{
+		ShopID:      shopID,
+		WarehouseID: warehouseID,
+		Priority:    2,
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr string
+	}{
+		{
+			name: "success",
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.shopWarehouseRepo.On("GetShopWarehouses", mock.Anything, payload.GetShopWarehousesReq{
+					ShopIDIN:      []string{shopID.String()},
+					WarehouseIDIN: []string{warehouseID.String()},
+				}).Return([]model.ShopWarehouse{}, nil)
+				m.shopWarehouseRepo.On("CreateShopWarehouse", mock.Anything, mock.MatchedBy(func(sw *model.ShopWarehouse) bool {
+					return sw.ShopID == shopID && sw.WarehouseID == warehouseID && sw.Priority == 2
+				})).Return(nil)
+			},
+		},
+		{
+			name: "error - warehouse not found",
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{}, errors.New("warehouse not found"))
+			},
+			wantErr: "warehouse not found",
+		},
+		{
+			name: "error - warehouse is archived",
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusArchived}, nil)
+			},
+			wantErr: "warehouse is archived",
+		},
+		{
+			name: "error - warehouse already assigned",
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.shopWarehouseRepo.On("GetShopWarehouses", mock.Anything, mock.Anything).
+					Return([]model.ShopWarehouse{{ID: uuid.New(), ShopID: shopID, WarehouseID: warehouseID}}, nil)
+			},
+			wantErr: "warehouse is already assigned to the shop",
+		},
+		{
+			name: "error - create shop warehouse failure",
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusInactive}, nil)
+				m.shopWarehouseRepo.On("GetShopWarehouses", mock.Anything, mock.Anything).
+					Return([]model.ShopWarehouse{}, nil)
+				m.shopWarehouseRepo.On("CreateShopWarehouse", mock.Anything, mock.Anything).
+					Return(errors.New("database error"))
+			},
+			wantErr: "database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				shopWarehouseRepo: shopWarehouseRepoMock.NewShopWarehouseRepository(t),
+				warehouseRepo:     warehouseRepoMock.NewWarehouseRepository(t),
+			}
+			shopWarehouseSvc := shopWarehouseService{
+				logger:            pkg.InitLogger(&config.Config{}),
+				shopWarehouseRepo: mocks.shopWarehouseRepo,
+				warehouseRepo:     mocks.warehouseRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			result, err := shopWarehouseSvc.CreateShopWarehouse(context.Background(), req)
+
+			// Then
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, shopID, result.ShopID)
+			assert.Equal(t, warehouseID, result.WarehouseID)
+			assert.Equal(t, 2, result.Priority)
+		})
+	}
+}
+
+func TestUpdateShopWarehouse(t *testing.T) {
+	type dependencyMocks struct {
+		shopWarehouseRepo *shopWarehouseRepoMock.ShopWarehouseRepository
+	}
+
+	id := uuid.New()
+	priority := 5
+	req := payload.UpdateShopWarehouseReq{
+		ID:       id,
+		Priority: &priority,
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(m dependencyMocks) {
+				m.shopWarehouseRepo.On("GetShopWarehouseByID", mock.Anything, id.String()).
+					Return(model.ShopWarehouse{ID: id, Priority: 1}, nil)
+				m.shopWarehouseRepo.On("UpdateShopWarehouse", mock.Anything, mock.MatchedBy(func(sw *model.ShopWarehouse) bool {
+					return sw.ID == id && sw.Priority == priority
+				})).Return(nil)
+			},
+		},
+		{
+			name: "error - shop warehouse not found",
+			setup: func(m dependencyMocks) {
+				m.shopWarehouseRepo.On("GetShopWarehouseByID", mock.Anything, id.String()).
+					Return(model.ShopWarehouse{}, errors.New("shop warehouse not found"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - update shop warehouse failure",
+			setup: func(m dependencyMocks) {
+				m.shopWarehouseRepo.On("GetShopWarehouseByID", mock.Anything, id.String()).
+					Return(model.ShopWarehouse{ID: id, Priority: 1}, nil)
+				m.shopWarehouseRepo.On("UpdateShopWarehouse", mock.Anything, mock.Anything).
+					Return(errors.New("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				shopWarehouseRepo: shopWarehouseRepoMock.NewShopWarehouseRepository(t),
+			}
+			shopWarehouseSvc := shopWarehouseService{
+				logger:            pkg.InitLogger(&config.Config{}),
+				shopWarehouseRepo: mocks.shopWarehouseRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := shopWarehouseSvc.UpdateShopWarehouse(context.Background(), req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDeleteShopWarehouse(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name    string
+		repoErr error
+		wantErr bool
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:    "error - shop warehouse not found",
+			repoErr: errors.New("shop warehouse not found"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			shopWarehouseRepo := shopWarehouseRepoMock.NewShopWarehouseRepository(t)
+			shopWarehouseRepo.On("DeleteShopWarehouse", mock.Anything, id.String()).
+				Return(tt.repoErr)
+			shopWarehouseSvc := shopWarehouseService{
+				logger:            pkg.InitLogger(&config.Config{}),
+				shopWarehouseRepo: shopWarehouseRepo,
+			}
+
+			// When
+			err := shopWarehouseSvc.DeleteShopWarehouse(context.Background(), id.String())
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package shopwarehouse
+
+import (
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/_options"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/registry"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/handler"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/repository"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/service"
+	warehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
+)
+
+type ShopWarehouseModule struct {
+	ShopWarehouseService service.ShopWarehouseService
+}
+
+type Options struct {
+	_options.DefaultOptions
+}
+
+func NewShopWarehouseModule(opts Options) *ShopWarehouseModule {
+
+	shopWarehouseRepo := repository.NewShopWarehouseRepository(opts.Db)
+	warehouseRepo := warehouserepository.NewWarehouseRepository(opts.Db)
+
+	shopWarehouseService := service.NewShopWarehouseService(opts.Config, opts.Logger, shopWarehouseRepo, warehouseRepo)
+
+	registry.RegisterRouter(handler.NewHandler(opts.Router, opts.Config, opts.Logger, shopWarehouseService))
+
+	return &ShopWarehouseModule{
+		ShopWarehouseService: shopWarehouseService,
+	}
+}
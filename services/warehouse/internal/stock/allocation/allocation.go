@@ -0,0 +1,167 @@
+package allocation
+
+import (
+	"math"
+	"sort"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/google/uuid"
+)
+
+const earthRadiusKm = 6371.0
+
+// Source is a warehouse stock a reservation can be filled from.
+type Source struct {
+	WarehouseID uuid.UUID
+	Available   int
+	// Priority ranks the warehouse for the shop, lower goes first.
+	Priority int
+	// Distance to the destination in kilometres, nil when unknown.
+	Distance *float64
+}
+
+type Allocation struct {
+	WarehouseID uuid.UUID
+	Quantity    int
+}
+
+// Strategy decides which sources a demand is filled from. Allocate returns
+// false when the sources cannot cover the whole demand.
+type Strategy interface {
+	Allocate(sources []Source, demand int) ([]Allocation, bool)
+}
+
+func New(name string) (Strategy, error) {
+	switch name {
+	case "", constant.AllocationStrategyPriority:
+		return priorityStrategy{}, nil
+	case constant.AllocationStrategyFewestSplits:
+		return fewestSplitsStrategy{}, nil
+	case constant.AllocationStrategyLargestStock:
+		return largestStockStrategy{}, nil
+	case constant.AllocationStrategyNearest:
+		return nearestStrategy{}, nil
+	default:
+		return nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "unsupported allocation strategy "+name)
+	}
+}
+
+// priorityStrategy fills from the highest ranked warehouses first.
+type priorityStrategy struct{}
+
+func (priorityStrategy) Allocate(sources []Source, demand int) ([]Allocation, bool) {
+	sorted := available(sources)
+	sort.Slice(sorted, func(i, j int) bool {
+		return byPriority(sorted[i], sorted[j])
+	})
+	return fill(sorted, demand)
+}
+
+// largestStockStrategy fills from the warehouses holding the most stock first.
+type largestStockStrategy struct{}
+
+func (largestStockStrategy) Allocate(sources []Source, demand int) ([]Allocation, bool) {
+	sorted := available(sources)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Available != sorted[j].Available {
+			return sorted[i].Available > sorted[j].Available
+		}
+		return byPriority(sorted[i], sorted[j])
+	})
+	return fill(sorted, demand)
+}
+
+// fewestSplitsStrategy uses as few warehouses as possible. The largest stocks
+// are taken until the rest of the demand fits in one warehouse, which is then
+// the smallest one that can cover it so larger stocks are kept intact.
+type fewestSplitsStrategy struct{}
+
+func (fewestSplitsStrategy) Allocate(sources []Source, demand int) ([]Allocation, bool) {
+	sorted := available(sources)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Available != sorted[j].Available {
+			return sorted[i].Available > sorted[j].Available
+		}
+		return byPriority(sorted[i], sorted[j])
+	})
+
+	var allocations []Allocation
+	for i := range sorted {
+		best := -1
+		for j := i; j < len(sorted); j++ {
+			if sorted[j].Available >= demand {
+				best = j
+			}
+		}
+		if best >= 0 {
+			return append(allocations, Allocation{WarehouseID: sorted[best].WarehouseID, Quantity: demand}), true
+		}
+
+		allocations = append(allocations, Allocation{WarehouseID: sorted[i].WarehouseID, Quantity: sorted[i].Available})
+		demand -= sorted[i].Available
+	}
+	return allocations, demand == 0
+}
+
+// nearestStrategy fills from the warehouses closest to the destination first.
+// Warehouses with an unknown distance go last.
+type nearestStrategy struct{}
+
+func (nearestStrategy) Allocate(sources []Source, demand int) ([]Allocation, bool) {
+	sorted := available(sources)
+	sort.Slice(sorted, func(i, j int) bool {
+		di, dj := sorted[i].Distance, sorted[j].Distance
+		if (di == nil) != (dj == nil) {
+			return di != nil
+		}
+		if di != nil && *di != *dj {
+			return *di < *dj
+		}
+		return byPriority(sorted[i], sorted[j])
+	})
+	return fill(sorted, demand)
+}
+
+// Distance returns the great-circle distance in kilometres between two points.
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// byPriority is the tie-breaker shared by all strategies so equal sources
+// always come out in the same order.
+func byPriority(a, b Source) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	return a.WarehouseID.String() < b.WarehouseID.String()
+}
+
+func available(sources []Source) []Source {
+	result := make([]Source, 0, len(sources))
+	for _, source := range sources {
+		if source.Available > 0 {
+			result = append(result, source)
+		}
+	}
+	return result
+}
+
+func fill(sources []Source, demand int) ([]Allocation, bool) {
+	var allocations []Allocation
+	for _, source := range sources {
+		if demand == 0 {
+			break
+		}
+		qty := min(demand, source.Available)
+		allocations = append(allocations, Allocation{WarehouseID: source.WarehouseID, Quantity: qty})
+		demand -= qty
+	}
+	return allocations, demand == 0
+}
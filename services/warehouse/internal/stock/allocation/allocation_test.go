@@ -0,0 +1,204 @@
+package allocation
+
+import (
+	"testing"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	warehouseA = uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	warehouseB = uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	warehouseC = uuid.MustParse("00000000-0000-0000-0000-00000000000c")
+	warehouseD = uuid.MustParse("00000000-0000-0000-0000-00000000000d")
+)
+
+func distance(km float64) *float64 {
+	return &km
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		want     Strategy
+		wantErr  bool
+	}{
+		{name: "default", strategy: "", want: priorityStrategy{}},
+		{name: "priority", strategy: constant.AllocationStrategyPriority, want: priorityStrategy{}},
+		{name: "fewest splits", strategy: constant.AllocationStrategyFewestSplits, want: fewestSplitsStrategy{}},
+		{name: "largest stock", strategy: constant.AllocationStrategyLargestStock, want: largestStockStrategy{}},
+		{name: "nearest", strategy: constant.AllocationStrategyNearest, want: nearestStrategy{}},
+		{name: "error - unsupported strategy", strategy: "random", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := New(tt.strategy)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, strategy)
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		sources  []Source
+		demand   int
+		want     []Allocation
+		wantOK   bool
+	}{
+		{
+			name:     "priority - lowest rank first",
+			strategy: constant.AllocationStrategyPriority,
+			sources: []Source{
+				{WarehouseID: warehouseA, Available: 100, Priority: 2},
+				{WarehouseID: warehouseB, Available: 20, Priority: 0},
+				{WarehouseID: warehouseC, Available: 20, Priority: 1},
+			},
+			demand: 30,
+			want: []Allocation{
+				{WarehouseID: warehouseB, Quantity: 20},
+				{WarehouseID: warehouseC, Quantity: 10},
+			},
+			wantOK: true,
+		},
+		{
+			name:     "priority - equal ranks ordered by warehouse ID",
+			strategy: constant.AllocationStrategyPriority,
+			sources: []Source{
+				{WarehouseID: warehouseC, Available: 10},
+				{WarehouseID: warehouseA, Available: 10},
+				{WarehouseID: warehouseB, Available: 10},
+			},
+			demand: 15,
+			want: []Allocation{
+				{WarehouseID: warehouseA, Quantity: 10},
+				{WarehouseID: warehouseB, Quantity: 5},
+			},
+			wantOK: true,
+		},
+		{
+			name:     "largest stock - most stock first",
+			strategy: constant.AllocationStrategyLargestStock,
+			sources: []Source{
+				{WarehouseID: warehouseA, Available: 10},
+				{WarehouseID: warehouseB, Available: 40},
+				{WarehouseID: warehouseC, Available: 25},
+			},
+			demand: 50,
+			want: []Allocation{
+				{WarehouseID: warehouseB, Quantity: 40},
+				{WarehouseID: warehouseC, Quantity: 10},
+			},
+			wantOK: true,
+		},
+		{
+			name:     "fewest splits - smallest single warehouse that fits",
+			strategy: constant.AllocationStrategyFewestSplits,
+			sources: []Source{
+				{WarehouseID: warehouseA, Available: 10},
+				{WarehouseID: warehouseB, Available: 100},
+				{WarehouseID: warehouseC, Available: 30},
+				{WarehouseID: warehouseD, Available: 40},
+			},
+			demand: 30,
+			want: []Allocation{
+				{WarehouseID: warehouseC, Quantity: 30},
+			},
+			wantOK: true,
+		},
+		{
+			name:     "fewest splits - largest stocks then best fit",
+			strategy: constant.AllocationStrategyFewestSplits,
+			sources: []Source{
+				{WarehouseID: warehouseA, Available: 10},
+				{WarehouseID: warehouseB, Available: 50},
+				{WarehouseID: warehouseC, Available: 30},
+				{WarehouseID: warehouseD, Available: 40},
+			},
+			demand: 60,
+			want: []Allocation{
+				{WarehouseID: warehouseB, Quantity: 50},
+				{WarehouseID: warehouseA, Quantity: 10},
+			},
+			wantOK: true,
+		},
+		{
+			name:     "nearest - closest first and unknown distance last",
+			strategy: constant.AllocationStrategyNearest,
+			sources: []Source{
+				{WarehouseID: warehouseA, Available: 50},
+				{WarehouseID: warehouseB, Available: 20, Distance: distance(120)},
+				{WarehouseID: warehouseC, Available: 20, Distance: distance(5)},
+			},
+			demand: 60,
+			want: []Allocation{
+				{WarehouseID: warehouseC, Quantity: 20},
+				{WarehouseID: warehouseB, Quantity: 20},
+				{WarehouseID: warehouseA, Quantity: 20},
+			},
+			wantOK: true,
+		},
+		{
+			name:     "skips sources without available stock",
+			strategy: constant.AllocationStrategyPriority,
+			sources: []Source{
+				{WarehouseID: warehouseA, Available: 0},
+				{WarehouseID: warehouseB, Available: -5},
+				{WarehouseID: warehouseC, Available: 10},
+			},
+			demand: 10,
+			want: []Allocation{
+				{WarehouseID: warehouseC, Quantity: 10},
+			},
+			wantOK: true,
+		},
+		{
+			name:     "insufficient stock",
+			strategy: constant.AllocationStrategyFewestSplits,
+			sources: []Source{
+				{WarehouseID: warehouseA, Available: 10},
+				{WarehouseID: warehouseB, Available: 5},
+			},
+			demand: 20,
+			wantOK: false,
+		},
+		{
+			name:     "no sources",
+			strategy: constant.AllocationStrategyLargestStock,
+			demand:   1,
+			wantOK:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := New(tt.strategy)
+			assert.NoError(t, err)
+
+			allocations, ok := strategy.Allocate(tt.sources, tt.demand)
+
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, allocations)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// Jakarta to Surabaya is roughly 660 km
+	assert.InDelta(t, 660, Distance(-6.2, 106.8, -7.25, 112.75), 10)
+	assert.Zero(t, Distance(-6.2, 106.8, -6.2, 106.8))
+}
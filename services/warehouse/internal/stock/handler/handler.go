@@ -33,8 +33,30 @@ func (h stockHandler) RegisterRoutes(base *gin.RouterGroup) {
 	g.GET("", h.GetStocks)
 	g.POST("", h.CreateStock)
 	g.POST("/transfer", h.TransferStock)
+	g.POST("/import", h.ImportStocks)
 	g.GET("/availables", h.GetAvailableStocksByProduct)
+	g.GET("/availables/stream", h.StreamStockAvailability)
 	g.POST("/reserve", h.ReserveStocks)
 	g.POST("/rollback", h.RollbackReserves)
 	g.POST("/commit", h.CommitReserves)
+	g.PUT("/thresholds", h.SetStockThreshold)
+	g.GET("/alerts", h.GetStockAlerts)
+	g.GET("/lots", h.GetStockLots)
+	g.POST("/lots", h.ReceiveStockLot)
+	g.GET("/lots/expiring", h.GetExpiringStockLots)
+	g.PUT("/serialized-products/:product_id", h.SetSerializedProduct)
+	g.PUT("/backorder-products/:product_id", h.SetBackorderProduct)
+	g.GET("/backorders", h.GetBackorders)
+	g.POST("/backorders/cancel", h.CancelBackorders)
+	g.GET("/serials", h.GetStockSerials)
+	g.GET("/serials/:serial_number", h.GetStockSerialHistory)
+	g.POST("/serials/receipts", h.ReceiveStockSerials)
+	g.POST("/serials/returns", h.ReturnStockSerials)
+	g.GET("/bins", h.GetBins)
+	g.POST("/bins", h.CreateBin)
+	g.GET("/bins/stocks", h.GetBinStocks)
+	g.POST("/bins/putaway", h.PutAwayStock)
+	g.POST("/bins/move", h.MoveBinStock)
+	g.GET("/pick-lists", h.GetPickLists)
+	g.GET("/snapshot", h.GetStockSnapshot)
 }
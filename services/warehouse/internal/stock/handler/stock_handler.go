@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/httpresp"
@@ -9,6 +14,7 @@ import (
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/utils"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/codes"
 )
 
@@ -46,7 +52,7 @@ func (h *stockHandler) GetStocks(c *gin.Context) {
 }
 
 // @Summary		Stock - Transfer Stock
-// @Description	transfer stock between warehouses
+// @Description	transfer stock between warehouses instantly, use /transfers for transfers that spend time in transit
 // @Tags		Stock
 // @Accept		json
 // @Produce		json
@@ -236,3 +242,775 @@ func (h *stockHandler) CreateStock(c *gin.Context) {
 	}
 	httpresp.HttpRespSuccess(c, "success", nil)
 }
+
+// @Summary		Stock - Set Stock Threshold
+// @Description	set the reorder threshold of a warehouse stock, or of a product when warehouse_id is omitted
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.SetStockThresholdReq	true	"set stock threshold request body"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/thresholds [put]
+func (h *stockHandler) SetStockThreshold(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.SetStockThreshold")
+	defer span.End()
+
+	var req payload.SetStockThresholdReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	if err := h.stockService.SetStockThreshold(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Stock - Get Stock Alerts
+// @Description	get low-stock, out-of-stock and recovered alert transitions
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetStockAlertsReq	false	"get stock alerts request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]model.StockAlert}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/alerts [get]
+func (h *stockHandler) GetStockAlerts(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.GetStockAlerts")
+	defer span.End()
+
+	var req payload.GetStockAlertsReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	alerts, err := h.stockService.GetStockAlerts(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, alerts, nil)
+}
+
+// @Summary		Stock - Import Stocks
+// @Description	bulk upsert stocks from a CSV file with warehouse_id, product_id and quantity columns
+// @Tags		Stock
+// @Accept		multipart/form-data
+// @Produce		json
+// @Param		file	formData	file	true	"CSV file"
+// @Param		request	query	payload.ImportStocksReq	false	"import stocks request query parameters"
+// @Success		200	{object}	httpresp.Response{data=payload.ImportStocksResult}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/import [post]
+func (h *stockHandler) ImportStocks(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.ImportStocks")
+	defer span.End()
+
+	var req payload.ImportStocksReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "csv file is required"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "failed to open csv file"))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.stockService.ImportStocks(ctx, req, file)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, result, nil)
+}
+
+// @Summary		Stock - Receive Stock Lot
+// @Description	put stock on hand against a lot, creating the lot when it does not exist
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.ReceiveStockLotReq	true	"receive stock lot request body"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/lots [post]
+func (h *stockHandler) ReceiveStockLot(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.ReceiveStockLot")
+	defer span.End()
+
+	var req payload.ReceiveStockLotReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	if err := h.stockService.ReceiveStockLot(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Stock - Get Stock Lots
+// @Description	get stock lots ordered first expiry first
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetStockLotsReq	false	"get stock lots request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]model.StockLot}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/lots [get]
+func (h *stockHandler) GetStockLots(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.GetStockLots")
+	defer span.End()
+
+	var req payload.GetStockLotsReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	lots, err := h.stockService.GetStockLots(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, lots, nil)
+}
+
+// @Summary		Stock - Get Expiring Stock Lots
+// @Description	report the lots in stock that expired or expire within within_days days, 30 by default
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetExpiringStockLotsReq	false	"get expiring stock lots request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]payload.ExpiringStockLot}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/lots/expiring [get]
+func (h *stockHandler) GetExpiringStockLots(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.GetExpiringStockLots")
+	defer span.End()
+
+	var req payload.GetExpiringStockLotsReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	lots, err := h.stockService.GetExpiringStockLots(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, lots, nil)
+}
+
+// @Summary		Stock - Set Serialized Product
+// @Description	turn serial number tracking of a product on or off
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		product_id	path	string	true	"product ID"
+// @Param		request	body	payload.SetSerializedProductReq	true	"set serialized product request body"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/serialized-products/{product_id} [put]
+func (h *stockHandler) SetSerializedProduct(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.SetSerializedProduct")
+	defer span.End()
+
+	productID, err := uuid.Parse(c.Param("product_id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	var req payload.SetSerializedProductReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+	req.ProductID = productID
+
+	if err := h.stockService.SetSerializedProduct(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Stock - Receive Stock Serials
+// @Description	register serial numbers of a serialized product and put one unit on hand for each
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.ReceiveStockSerialsReq	true	"receive stock serials request body"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/serials/receipts [post]
+func (h *stockHandler) ReceiveStockSerials(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.ReceiveStockSerials")
+	defer span.End()
+
+	var req payload.ReceiveStockSerialsReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	if err := h.stockService.ReceiveStockSerials(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Stock - Return Stock Serials
+// @Description	put sold serials back on hand in a warehouse
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.ReturnStockSerialsReq	true	"return stock serials request body"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/serials/returns [post]
+func (h *stockHandler) ReturnStockSerials(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.ReturnStockSerials")
+	defer span.End()
+
+	var req payload.ReturnStockSerialsReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	if err := h.stockService.ReturnStockSerials(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Stock - Set Backorder Product
+// @Description	let a product take orders beyond its stock as backorders or pre-orders, an empty mode turns it off
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		product_id	path	string	true	"product ID"
+// @Param		request	body	payload.SetBackorderProductReq	true	"set backorder product request body"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/backorder-products/{product_id} [put]
+func (h *stockHandler) SetBackorderProduct(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.SetBackorderProduct")
+	defer span.End()
+
+	productID, err := uuid.Parse(c.Param("product_id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid product ID"))
+		return
+	}
+
+	var req payload.SetBackorderProductReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+	req.ProductID = productID
+
+	if err := h.stockService.SetBackorderProduct(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Stock - Get Backorders
+// @Description	get backorders oldest first with the stock allocated to them
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetBackordersReq	false	"get backorders request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]model.Backorder}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/backorders [get]
+func (h *stockHandler) GetBackorders(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.GetBackorders")
+	defer span.End()
+
+	var req payload.GetBackordersReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	backorders, err := h.stockService.GetBackorders(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, backorders, nil)
+}
+
+// @Summary		Stock - Cancel Backorders
+// @Description	cancel the backorders of an order still waiting for them and release the stock allocated to them
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.CancelBackordersReq	true	"cancel backorders request body"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/backorders/cancel [post]
+func (h *stockHandler) CancelBackorders(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.CancelBackorders")
+	defer span.End()
+
+	var req payload.CancelBackordersReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	if err := h.stockService.CancelBackorders(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Stock - Get Stock Serials
+// @Description	get stock serials
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetStockSerialsReq	false	"get stock serials request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]model.StockSerial}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/serials [get]
+func (h *stockHandler) GetStockSerials(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.GetStockSerials")
+	defer span.End()
+
+	var req payload.GetStockSerialsReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	serials, err := h.stockService.GetStockSerials(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, serials, nil)
+}
+
+// @Summary		Stock - Get Stock Serial History
+// @Description	look up a serial number with its receipts, sales and returns
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		serial_number	path	string	true	"serial number"
+// @Success		200	{object}	httpresp.Response{data=[]model.StockSerial}
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/serials/{serial_number} [get]
+func (h *stockHandler) GetStockSerialHistory(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.GetStockSerialHistory")
+	defer span.End()
+
+	serials, err := h.stockService.GetStockSerialHistory(ctx, c.Param("serial_number"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, serials, nil)
+}
+
+// @Summary		Stock - Create Bin
+// @Description	create a bin in a zone of a warehouse
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.CreateBinReq	true	"create bin request body"
+// @Success		200	{object}	httpresp.Response{data=model.WarehouseBin}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/bins [post]
+func (h *stockHandler) CreateBin(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.CreateBin")
+	defer span.End()
+
+	var req payload.CreateBinReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	bin, err := h.stockService.CreateBin(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, bin, nil)
+}
+
+// @Summary		Stock - Get Bins
+// @Description	get warehouse bins ordered by zone and code
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetBinsReq	false	"get bins request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]model.WarehouseBin}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/bins [get]
+func (h *stockHandler) GetBins(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.GetBins")
+	defer span.End()
+
+	var req payload.GetBinsReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	bins, err := h.stockService.GetBins(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, bins, nil)
+}
+
+// @Summary		Stock - Get Bin Stocks
+// @Description	get the stock shelved in each bin
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetBinStocksReq	false	"get bin stocks request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]model.BinStock}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/bins/stocks [get]
+func (h *stockHandler) GetBinStocks(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.GetBinStocks")
+	defer span.End()
+
+	var req payload.GetBinStocksReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	binStocks, err := h.stockService.GetBinStocks(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, binStocks, nil)
+}
+
+// @Summary		Stock - Put Away Stock
+// @Description	shelve on-hand stock that is not in a bin yet
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.PutAwayStockReq	true	"put away stock request body"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/bins/putaway [post]
+func (h *stockHandler) PutAwayStock(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.PutAwayStock")
+	defer span.End()
+
+	var req payload.PutAwayStockReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	if err := h.stockService.PutAwayStock(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Stock - Move Bin Stock
+// @Description	move stock between two bins of a warehouse
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.MoveBinStockReq	true	"move bin stock request body"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/bins/move [post]
+func (h *stockHandler) MoveBinStock(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.MoveBinStock")
+	defer span.End()
+
+	var req payload.MoveBinStockReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	if err := h.stockService.MoveBinStock(ctx, req); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Stock - Get Pick Lists
+// @Description	get the pick lists generated for committed reservations, newest first
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetPickListsReq	false	"get pick lists request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]model.PickList}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/pick-lists [get]
+func (h *stockHandler) GetPickLists(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.GetPickLists")
+	defer span.End()
+
+	var req payload.GetPickListsReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	pickLists, err := h.stockService.GetPickLists(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, pickLists, nil)
+}
+
+// @Summary		Stock - Get Stock Snapshot
+// @Description	get the stocks as they were at a past time, replayed from the latest snapshot before it
+// @Tags		Stock
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetStockSnapshotReq	true	"get stock snapshot request query parameters"
+// @Success		200	{object}	httpresp.Response{data=payload.StockSnapshotResp}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/snapshot [get]
+func (h *stockHandler) GetStockSnapshot(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.GetStockSnapshot")
+	defer span.End()
+
+	var req payload.GetStockSnapshotReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	snapshot, err := h.stockService.GetStockSnapshot(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, snapshot, nil)
+}
+
+// availabilityHeartbeat keeps idle availability streams from being closed by
+// proxies.
+const availabilityHeartbeat = 15 * time.Second
+
+// @Summary		Stock - Stream Stock Availability
+// @Description	stream the available stock of the products as server-sent events, starting with the current availability. Reconnecting with the Last-Event-ID header replays the availability of the products whose stock moved after the last received event
+// @Tags		Stock
+// @Produce		text/event-stream
+// @Param		request			query	payload.StreamStockAvailabilityReq	true	"stream stock availability request query parameters"
+// @Param		Last-Event-ID	header	int									false	"last event received"
+// @Success		200	{object}	payload.StockAvailabilityEvent
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/stocks/availables/stream [get]
+func (h *stockHandler) StreamStockAvailability(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "stockHandler.StreamStockAvailability")
+	defer span.End()
+
+	var req payload.StreamStockAvailabilityReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	if lastEventID := c.GetHeader("Last-Event-ID"); lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || id < 0 {
+			httpresp.HttpRespError(c, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "invalid Last-Event-ID header"))
+			return
+		}
+		req.LastEventID = id
+	}
+
+	events, err := h.stockService.SubscribeStockAvailability(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(availabilityHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				span.SetStatus(codes.Error, err.Error())
+				return
+			}
+			fmt.Fprintf(c.Writer, "id: %d\nevent: availability\ndata: %s\n\n", event.ID, data)
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"bytes"
 	"errors"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -10,6 +12,7 @@ import (
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service/mocks"
 	"github.com/google/uuid"
@@ -285,6 +288,21 @@ func TestReserveStocks_ShouldReturnExpectedStatusCode(t *testing.T) {
 			mockResult:         nil,
 			mockError:          errors.New("something went wrong"),
 		},
+		{
+			testName:           "success - with strategy and destination",
+			mockReq:            `{"strategy": "nearest", "destination": {"latitude": -6.2, "longitude": 106.8}, "stocks": [{"product_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "quantity": 5}]}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - unsupported strategy",
+			mockReq:            `{"strategy": "random", "stocks": [{"product_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "quantity": 5}]}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - destination without longitude",
+			mockReq:            `{"destination": {"latitude": -6.2}, "stocks": [{"product_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "quantity": 5}]}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
 		{
 			testName:           "failed - invalid request body",
 			mockReq:            `{"stocks": [{"product_id": "invalid-uuid"}]}`,
@@ -481,3 +499,1348 @@ func TestCommitReserves_ShouldReturnExpectedStatusCode(t *testing.T) {
 		})
 	}
 }
+
+func TestSetStockThreshold_ShouldReturnExpectedStatusCode(t *testing.T) {
+	payload := `{
+		"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0",
+		"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0",
+		"threshold": 10
+	}`
+	testScenarios := []struct {
+		testName           string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - error handle set stock threshold",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - invalid request body",
+			mockReq:            `{"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "threshold": -1}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("SetStockThreshold", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/stocks/thresholds", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetStockAlerts_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		queries            string
+		mockResult         []model.StockAlert
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			queries:            "",
+			statusCodeExpected: http.StatusOK,
+			mockResult: []model.StockAlert{
+				{
+					ID:        uuid.New(),
+					Scope:     "product",
+					ProductID: uuid.New(),
+					Status:    "low",
+					Available: 3,
+					Threshold: 5,
+				},
+			},
+			mockError: nil,
+		},
+		{
+			testName:           "success with query",
+			queries:            "?product_id_in=some-product-id&status_in=low&status_in=out&active_only=true",
+			statusCodeExpected: http.StatusOK,
+			mockResult:         []model.StockAlert{},
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - invalid status",
+			queries:            "?status_in=unknown",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get stock alerts",
+			queries:            "",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("GetStockAlerts", mock.Anything, mock.Anything).
+				Return(scenario.mockResult, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/stocks/alerts"+scenario.queries, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestImportStocks_ShouldReturnExpectedStatusCode(t *testing.T) {
+	csvFile := "warehouse_id,product_id,quantity\n8f1cc115-4434-4829-81c4-23fb01aa0dc0,9a2b7c93-7c27-4e20-842f-24bf4df95bf0,10\n"
+	testScenarios := []struct {
+		testName           string
+		queries            string
+		withFile           bool
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			queries:            "?dry_run=true&mode=chunked&chunk_size=100",
+			withFile:           true,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid mode",
+			queries:            "?mode=parallel",
+			withFile:           true,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - missing file",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle import stocks",
+			withFile:           true,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("ImportStocks", mock.Anything, mock.Anything, mock.Anything).
+				Return(payload.ImportStocksResult{}, scenario.mockError)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			if scenario.withFile {
+				part, err := writer.CreateFormFile("file", "stocks.csv")
+				assert.NoError(t, err)
+				_, err = part.Write([]byte(csvFile))
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, writer.Close())
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/stocks/import"+scenario.queries, body)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+			ctx.Request.Header.Set("Content-Type", writer.FormDataContentType())
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestReceiveStockLot_ShouldReturnExpectedStatusCode(t *testing.T) {
+	payload := `{
+		"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0",
+		"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0",
+		"lot_number": "LOT-001",
+		"expiry_date": "2027-03-31",
+		"quantity": 10
+	}`
+	testScenarios := []struct {
+		testName           string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - error handle receive stock lot",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - invalid expiry date",
+			mockReq:            `{"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "lot_number": "LOT-001", "expiry_date": "31/03/2027", "quantity": 10}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - missing lot number",
+			mockReq:            `{"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "quantity": 10}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("ReceiveStockLot", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/stocks/lots", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetStockLots_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		queries            string
+		mockResult         []model.StockLot
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			queries:            "?product_id_in=some-product-id&in_stock_only=true",
+			statusCodeExpected: http.StatusOK,
+			mockResult: []model.StockLot{
+				{
+					ID:        uuid.New(),
+					ProductID: uuid.New(),
+					LotNumber: "LOT-001",
+					Quantity:  10,
+				},
+			},
+		},
+		{
+			testName:           "failed - invalid in stock only",
+			queries:            "?in_stock_only=maybe",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get stock lots",
+			queries:            "",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("GetStockLots", mock.Anything, mock.Anything).
+				Return(scenario.mockResult, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/stocks/lots"+scenario.queries, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetExpiringStockLots_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		queries            string
+		mockResult         []payload.ExpiringStockLot
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			queries:            "?within_days=14",
+			statusCodeExpected: http.StatusOK,
+			mockResult: []payload.ExpiringStockLot{
+				{
+					ID:           uuid.New(),
+					LotNumber:    "LOT-001",
+					Quantity:     10,
+					DaysToExpiry: 3,
+				},
+			},
+		},
+		{
+			testName:           "failed - invalid within days",
+			queries:            "?within_days=-1",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get expiring stock lots",
+			queries:            "",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("GetExpiringStockLots", mock.Anything, mock.Anything).
+				Return(scenario.mockResult, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/stocks/lots/expiring"+scenario.queries, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestSetSerializedProduct_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockParam          string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockParam:          "9a2b7c93-7c27-4e20-842f-24bf4df95bf0",
+			mockReq:            `{"serialized": true}`,
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - error handle set serialized product",
+			mockParam:          "9a2b7c93-7c27-4e20-842f-24bf4df95bf0",
+			mockReq:            `{"serialized": false}`,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - invalid product ID",
+			mockParam:          "invalid",
+			mockReq:            `{"serialized": true}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("SetSerializedProduct", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/stocks/serialized-products/"+scenario.mockParam, strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestSetBackorderProduct_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockParam          string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockParam:          "9a2b7c93-7c27-4e20-842f-24bf4df95bf0",
+			mockReq:            `{"mode": "preorder"}`,
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - error handle set backorder product",
+			mockParam:          "9a2b7c93-7c27-4e20-842f-24bf4df95bf0",
+			mockReq:            `{"mode": ""}`,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - invalid mode",
+			mockParam:          "9a2b7c93-7c27-4e20-842f-24bf4df95bf0",
+			mockReq:            `{"mode": "always"}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - invalid product ID",
+			mockParam:          "invalid",
+			mockReq:            `{"mode": "backorder"}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("SetBackorderProduct", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/stocks/backorder-products/"+scenario.mockParam, strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetBackorders_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockQuery          string
+		mockResult         []model.Backorder
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockQuery:          "?order_ref_in=order-1&status_in=waiting",
+			mockResult:         []model.Backorder{{ID: uuid.New()}},
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - invalid status",
+			mockQuery:          "?status_in=shipped",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get backorders",
+			mockQuery:          "",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("GetBackorders", mock.Anything, mock.Anything).
+				Return(scenario.mockResult, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/stocks/backorders"+scenario.mockQuery, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestReceiveStockSerials_ShouldReturnExpectedStatusCode(t *testing.T) {
+	payload := `{
+		"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0",
+		"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0",
+		"serial_numbers": ["SN-1", "SN-2"]
+	}`
+	testScenarios := []struct {
+		testName           string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - error handle receive stock serials",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - duplicate serial numbers",
+			mockReq:            `{"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "serial_numbers": ["SN-1", "SN-1"]}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - missing serial numbers",
+			mockReq:            `{"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0"}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("ReceiveStockSerials", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/stocks/serials/receipts", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestReturnStockSerials_ShouldReturnExpectedStatusCode(t *testing.T) {
+	payload := `{
+		"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0",
+		"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0",
+		"serial_numbers": ["SN-1"],
+		"order_ref": "order-1"
+	}`
+	testScenarios := []struct {
+		testName           string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - error handle return stock serials",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - missing warehouse ID",
+			mockReq:            `{"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "serial_numbers": ["SN-1"]}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("ReturnStockSerials", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/stocks/serials/returns", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetStockSerialHistory_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockResult         []model.StockSerial
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockResult:         []model.StockSerial{{SerialNumber: "SN-1"}},
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - serial not found",
+			statusCodeExpected: http.StatusNotFound,
+			mockError:          apperr.NewWithCode(apperr.CodeHTTPNotFound, "serial not found"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("GetStockSerialHistory", mock.Anything, "SN-1").
+				Return(scenario.mockResult, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/stocks/serials/SN-1", nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestCreateBin_ShouldReturnExpectedStatusCode(t *testing.T) {
+	payload := `{
+		"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0",
+		"zone": "A",
+		"code": "A-01-01"
+	}`
+	testScenarios := []struct {
+		testName           string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - error handle create bin",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - missing code",
+			mockReq:            `{"warehouse_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "zone": "A"}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("CreateBin", mock.Anything, mock.Anything).
+				Return(model.WarehouseBin{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/stocks/bins", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestPutAwayStock_ShouldReturnExpectedStatusCode(t *testing.T) {
+	payload := `{
+		"bin_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0",
+		"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0",
+		"quantity": 5
+	}`
+	testScenarios := []struct {
+		testName           string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - error handle put away stock",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - invalid quantity",
+			mockReq:            `{"bin_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "quantity": 0}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("PutAwayStock", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/stocks/bins/putaway", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestMoveBinStock_ShouldReturnExpectedStatusCode(t *testing.T) {
+	payload := `{
+		"from_bin_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0",
+		"to_bin_id": "5d8e7c1a-2b3f-4a6d-9e0f-1a2b3c4d5e6f",
+		"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0",
+		"quantity": 2
+	}`
+	testScenarios := []struct {
+		testName           string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - error handle move bin stock",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - missing destination bin",
+			mockReq:            `{"from_bin_id": "8f1cc115-4434-4829-81c4-23fb01aa0dc0", "product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "quantity": 2}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("MoveBinStock", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/stocks/bins/move", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetPickLists_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockQuery          string
+		mockResult         []model.PickList
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockQuery:          "?order_ref=order-1",
+			mockResult:         []model.PickList{{ID: uuid.New()}},
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - error handle get pick lists",
+			mockQuery:          "",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("GetPickLists", mock.Anything, mock.Anything).
+				Return(scenario.mockResult, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/stocks/pick-lists"+scenario.mockQuery, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetStockSnapshot_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockQuery          string
+		mockResult         payload.StockSnapshotResp
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockQuery:          "?at=2026-10-01T12:00:00Z",
+			mockResult:         payload.StockSnapshotResp{Stocks: []payload.StockSnapshotData{{Quantity: 10}}},
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - missing at",
+			mockQuery:          "",
+			statusCodeExpected: http.StatusBadRequest,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - invalid at",
+			mockQuery:          "?at=yesterday",
+			statusCodeExpected: http.StatusBadRequest,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - error handle get stock snapshot",
+			mockQuery:          "?at=2026-10-01T12:00:00Z",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("GetStockSnapshot", mock.Anything, mock.Anything).
+				Return(scenario.mockResult, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/stocks/snapshot"+scenario.mockQuery, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestStreamStockAvailability_ShouldReturnExpectedStatusCode(t *testing.T) {
+	productID := uuid.New()
+
+	testScenarios := []struct {
+		testName           string
+		mockQuery          string
+		mockLastEventID    string
+		mockEvents         []payload.StockAvailabilityEvent
+		mockError          error
+		statusCodeExpected int
+		bodyExpected       string
+	}{
+		{
+			testName:           "success",
+			mockQuery:          "?product_id_in=" + productID.String(),
+			mockLastEventID:    "2",
+			mockEvents:         []payload.StockAvailabilityEvent{{ID: 3, ProductID: productID, AvailableStock: 7}},
+			statusCodeExpected: http.StatusOK,
+			bodyExpected:       "id: 3\nevent: availability\ndata: {\"product_id\":\"" + productID.String() + "\",\"available_stock\":7}\n\n",
+		},
+		{
+			testName:           "failed - missing product ids",
+			mockQuery:          "",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - invalid last event id",
+			mockQuery:          "?product_id_in=" + productID.String(),
+			mockLastEventID:    "abc",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle subscribe stock availability",
+			mockQuery:          "?product_id_in=" + productID.String(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			events := make(chan payload.StockAvailabilityEvent, len(scenario.mockEvents))
+			for _, event := range scenario.mockEvents {
+				events <- event
+			}
+			close(events)
+
+			mockStockSvc := &mocks.StockService{}
+			mockStockSvc.
+				On("SubscribeStockAvailability", mock.Anything, mock.Anything).
+				Return((<-chan payload.StockAvailabilityEvent)(events), scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/stocks/availables/stream"+scenario.mockQuery, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+			if scenario.mockLastEventID != "" {
+				ctx.Request.Header.Set("Last-Event-ID", scenario.mockLastEventID)
+			}
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+			if scenario.bodyExpected != "" {
+				assert.Equal(t, scenario.bodyExpected, rr.Body.String())
+				mockStockSvc.AssertCalled(t, "SubscribeStockAvailability", mock.Anything, payload.StreamStockAvailabilityReq{
+					ProductIDIN: []string{productID.String()},
+					LastEventID: 2,
+				})
+			}
+		})
+	}
+}
+
+func TestCancelBackorders_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockReq:            `{"order_ref": "order-1"}`,
+			statusCodeExpected: http.StatusOK,
+			mockError:          nil,
+		},
+		{
+			testName:           "failed - error handle cancel backorders",
+			mockReq:            `{"order_ref": "order-1"}`,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - missing order ref",
+			mockReq:            `{}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockStockSvc := &mocks.StockService{}
+			if scenario.statusCodeExpected != http.StatusBadRequest {
+				mockStockSvc.
+					On("CancelBackorders", mock.Anything, mock.Anything).
+					Return(scenario.mockError)
+			}
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/stocks/backorders/cancel", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &stockHandler{
+				router:       r,
+				config:       mockConfig,
+				stockService: mockStockSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package payload
+
+import "github.com/google/uuid"
+
+// SetBackorderProductReq lets a product take orders beyond its stock, as
+// backorders or pre-orders. An empty Mode turns it off.
+type SetBackorderProductReq struct {
+	ProductID uuid.UUID `json:"-"`
+	Mode      string    `json:"mode" binding:"omitempty,oneof=backorder preorder"`
+}
+
+type GetBackordersReq struct {
+	OrderRefIN  []string `form:"order_ref_in" binding:"omitempty"`
+	ProductIDIN []string `form:"product_id_in" binding:"omitempty"`
+	StatusIN    []string `form:"status_in" binding:"omitempty,dive,oneof=waiting allocated cancelled"`
+}
+
+// CancelBackordersReq cancels the backorders of an order that is still waiting
+// for them, releasing the stock already allocated.
+type CancelBackordersReq struct {
+	OrderRef string `json:"order_ref" binding:"required"`
+}
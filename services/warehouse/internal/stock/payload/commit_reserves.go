@@ -1,10 +1,17 @@
 package payload
 
+// CommitReservesReq commits reserved stocks. OrderRef is required when a
+// serialized product is committed, the committed serials are sold to it.
 type CommitReservesReq struct {
-	Stocks []CommitReservesData `json:"stocks" binding:"required,dive"`
+	Stocks   []CommitReservesData `json:"stocks" binding:"required,dive"`
+	OrderRef string               `json:"order_ref" binding:"omitempty,max=100"`
 }
+
+// CommitReservesData commits a reserved stock. SerialNumbers picks the units of
+// a serialized product, otherwise the oldest serials in stock are used.
 type CommitReservesData struct {
-	ProductID   string `json:"product_id" binding:"required"`
-	WarehouseID string `json:"warehouse_id" binding:"required"`
-	Quantity    int    `json:"quantity" binding:"required,gt=0"`
+	ProductID     string   `json:"product_id" binding:"required"`
+	WarehouseID   string   `json:"warehouse_id" binding:"required"`
+	Quantity      int      `json:"quantity" binding:"required,gt=0"`
+	SerialNumbers []string `json:"serial_numbers" binding:"omitempty,unique"`
 }
@@ -0,0 +1,8 @@
+package payload
+
+type GetStockAlertsReq struct {
+	WarehouseIDIN []string `form:"warehouse_id_in" binding:"omitempty"`
+	ProductIDIN   []string `form:"product_id_in" binding:"omitempty"`
+	StatusIN      []string `form:"status_in" binding:"omitempty,dive,oneof=low out recovered"`
+	ActiveOnly    bool     `form:"active_only" binding:"omitempty"`
+}
@@ -0,0 +1,27 @@
+package payload
+
+// ImportStocksReq controls how a CSV of warehouse_id, product_id and quantity
+// rows is imported. In single mode the whole file is applied in one
+// transaction and nothing is written if any row is invalid. In chunked mode
+// every chunk is committed on its own and invalid rows are skipped.
+type ImportStocksReq struct {
+	DryRun    bool   `form:"dry_run"`
+	Mode      string `form:"mode" binding:"omitempty,oneof=single chunked"`
+	ChunkSize int    `form:"chunk_size" binding:"omitempty,min=1,max=5000"`
+}
+
+type ImportStocksResult struct {
+	DryRun       bool                `json:"dry_run"`
+	Mode         string              `json:"mode"`
+	TotalRows    int                 `json:"total_rows"`
+	ValidRows    int                 `json:"valid_rows"`
+	ImportedRows int                 `json:"imported_rows"`
+	Errors       []ImportStockRowErr `json:"errors"`
+}
+
+// ImportStockRowErr reports why a row was not imported. Row is the line number
+// in the CSV file, the header being line 1.
+type ImportStockRowErr struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
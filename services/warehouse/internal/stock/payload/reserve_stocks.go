@@ -1,16 +1,47 @@
 package payload
 
+import "time"
+
+// ReserveStocksReq reserves stocks using Strategy, or the configured default
+// allocation strategy when empty. Destination is used by the nearest strategy.
+// OrderRef is required to backorder products that are short of stock.
 type ReserveStocksReq struct {
-	Stocks []ReserveStocksData `json:"stocks" binding:"required,dive"`
+	Stocks      []ReserveStocksData  `json:"stocks" binding:"required,dive"`
+	OrderRef    string               `json:"order_ref" binding:"omitempty,max=100"`
+	Strategy    string               `json:"strategy" binding:"omitempty,oneof=priority fewest_splits largest_stock nearest"`
+	Destination *ReserveStocksCoords `json:"destination" binding:"omitempty"`
+}
+
+type ReserveStocksCoords struct {
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180"`
 }
 
+// ReserveStocksData reserves a product, or a variant of it by variant ID. When
+// ShopID is set only the warehouses assigned to that shop are used, ranked by
+// their assigned priority.
 type ReserveStocksData struct {
 	ProductID string `json:"product_id" binding:"required"`
+	ShopID    string `json:"shop_id" binding:"omitempty,uuid"`
 	Quantity  int    `json:"quantity" binding:"required,gt=0"`
 }
 
+// ReserveStocksResp is a quantity reserved in a warehouse, or a backorder
+// when the product was short of stock.
 type ReserveStocksResp struct {
-	ProductID        string `json:"product_id"`
-	WarehouseID      string `json:"warehouse_id"`
-	ReservedQuantity int    `json:"reserved_quantity"`
+	ProductID         string `json:"product_id"`
+	WarehouseID       string `json:"warehouse_id,omitempty"`
+	ReservedQuantity  int    `json:"reserved_quantity"`
+	BackorderID       string `json:"backorder_id,omitempty"`
+	BackorderQuantity int    `json:"backorder_quantity,omitempty"`
+	// Lots the quantity was reserved from, first expiry first. The rest of
+	// the quantity is not tracked by lot.
+	Lots []ReservedStockLot `json:"lots,omitempty"`
+}
+
+type ReservedStockLot struct {
+	LotID      string     `json:"lot_id"`
+	LotNumber  string     `json:"lot_number"`
+	ExpiryDate *time.Time `json:"expiry_date"`
+	Quantity   int        `json:"quantity"`
 }
@@ -1,7 +1,10 @@
 package payload
 
+// RollbackReservesReq releases reserved stocks. OrderRef releases the lots the
+// order reserved.
 type RollbackReservesReq struct {
-	Stocks []RollbackReservesData `json:"stocks" binding:"required,dive"`
+	Stocks   []RollbackReservesData `json:"stocks" binding:"required,dive"`
+	OrderRef string                 `json:"order_ref" binding:"omitempty,max=100"`
 }
 type RollbackReservesData struct {
 	ProductID   string `json:"product_id" binding:"required"`
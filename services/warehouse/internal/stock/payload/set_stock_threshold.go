@@ -0,0 +1,12 @@
+package payload
+
+import "github.com/google/uuid"
+
+// SetStockThresholdReq sets the reorder threshold of a single warehouse stock
+// when WarehouseID is given, or the product-wide threshold otherwise.
+// A nil Threshold removes the threshold.
+type SetStockThresholdReq struct {
+	ProductID   uuid.UUID  `json:"product_id" binding:"required"`
+	WarehouseID *uuid.UUID `json:"warehouse_id" binding:"omitempty"`
+	Threshold   *int       `json:"threshold" binding:"omitempty,min=0"`
+}
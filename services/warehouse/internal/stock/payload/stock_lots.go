@@ -0,0 +1,53 @@
+package payload
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReceiveStockLotReq adds quantity to a lot of the product in the warehouse,
+// creating the lot when it does not exist yet. ExpiryDate is formatted as
+// 2006-01-02.
+type ReceiveStockLotReq struct {
+	WarehouseID uuid.UUID `json:"warehouse_id" binding:"required"`
+	ProductID   uuid.UUID `json:"product_id" binding:"required"`
+	LotNumber   string    `json:"lot_number" binding:"required,max=64"`
+	ExpiryDate  string    `json:"expiry_date" binding:"omitempty,datetime=2006-01-02"`
+	Quantity    int       `json:"quantity" binding:"required,min=1"`
+}
+
+type GetStockLotsReq struct {
+	WarehouseIDIN []string `form:"warehouse_id_in" binding:"omitempty"`
+	ProductIDIN   []string `form:"product_id_in" binding:"omitempty"`
+	LotNumberIN   []string `form:"lot_number_in" binding:"omitempty"`
+	InStockOnly   bool     `form:"in_stock_only" binding:"omitempty"`
+	// ExpiresBefore only keeps the lots expiring before the date
+	ExpiresBefore *time.Time `form:"-"`
+}
+
+// GetExpiringStockLotsReq reports the lots in stock expiring within WithinDays
+// days, expired lots included. WithinDays defaults to 30.
+type GetExpiringStockLotsReq struct {
+	WarehouseIDIN []string `form:"warehouse_id_in" binding:"omitempty"`
+	ProductIDIN   []string `form:"product_id_in" binding:"omitempty"`
+	WithinDays    *int     `form:"within_days" binding:"omitempty,min=0,max=3650"`
+}
+
+type ExpiringStockLot struct {
+	ID          uuid.UUID `json:"id"`
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	LotNumber   string    `json:"lot_number"`
+	ExpiryDate  time.Time `json:"expiry_date"`
+	Quantity    int       `json:"quantity"`
+	Reserved    int       `json:"reserved"`
+	Expired     bool      `json:"expired"`
+	// DaysToExpiry is negative once the lot has expired
+	DaysToExpiry int `json:"days_to_expiry"`
+}
+
+type GetStockLotReservationsReq struct {
+	StockLotIDIN []string
+	OrderRefIN   []string
+}
@@ -0,0 +1,35 @@
+package payload
+
+import "github.com/google/uuid"
+
+// SetSerializedProductReq turns serial number tracking of a product on or off.
+type SetSerializedProductReq struct {
+	ProductID  uuid.UUID `json:"-"`
+	Serialized bool      `json:"serialized"`
+}
+
+// ReceiveStockSerialsReq puts one unit on hand for every serial number.
+type ReceiveStockSerialsReq struct {
+	WarehouseID   uuid.UUID `json:"warehouse_id" binding:"required"`
+	ProductID     uuid.UUID `json:"product_id" binding:"required"`
+	SerialNumbers []string  `json:"serial_numbers" binding:"required,min=1,unique,dive,required,max=100"`
+}
+
+// ReturnStockSerialsReq puts sold serials back on hand in the warehouse. When
+// OrderRef is set the serials must have been sold to that order.
+type ReturnStockSerialsReq struct {
+	WarehouseID   uuid.UUID `json:"warehouse_id" binding:"required"`
+	ProductID     uuid.UUID `json:"product_id" binding:"required"`
+	SerialNumbers []string  `json:"serial_numbers" binding:"required,min=1,unique,dive,required,max=100"`
+	OrderRef      string    `json:"order_ref" binding:"omitempty,max=100"`
+}
+
+type GetStockSerialsReq struct {
+	WarehouseIDIN  []string `form:"warehouse_id_in" binding:"omitempty"`
+	ProductIDIN    []string `form:"product_id_in" binding:"omitempty"`
+	SerialNumberIN []string `form:"serial_number_in" binding:"omitempty"`
+	StatusIN       []string `form:"status_in" binding:"omitempty,dive,oneof=in_stock sold"`
+	OrderRef       string   `form:"order_ref" binding:"omitempty"`
+	// Limit caps the number of serials returned, 0 means no limit
+	Limit int `form:"-"`
+}
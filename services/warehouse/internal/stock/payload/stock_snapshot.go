@@ -0,0 +1,29 @@
+package payload
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetStockSnapshotReq asks for the stocks as they were at At, formatted as
+// RFC 3339.
+type GetStockSnapshotReq struct {
+	At            time.Time `form:"at" binding:"required"`
+	WarehouseIDIN []string  `form:"warehouse_id_in" binding:"omitempty"`
+	ProductIDIN   []string  `form:"product_id_in" binding:"omitempty"`
+}
+
+type StockSnapshotResp struct {
+	At time.Time `json:"at"`
+	// SnapshotTakenAt is the snapshot the movements were replayed from
+	SnapshotTakenAt time.Time           `json:"snapshot_taken_at"`
+	Stocks          []StockSnapshotData `json:"stocks"`
+}
+
+type StockSnapshotData struct {
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	Quantity    int       `json:"quantity"`
+	Reserved    int       `json:"reserved"`
+}
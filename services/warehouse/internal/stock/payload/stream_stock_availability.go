@@ -0,0 +1,20 @@
+package payload
+
+import "github.com/google/uuid"
+
+// StreamStockAvailabilityReq subscribes to the available stock of the products.
+// LastEventID resumes a stream after the last event the client received, it is
+// taken from the Last-Event-ID header when the client reconnects.
+type StreamStockAvailabilityReq struct {
+	ProductIDIN []string `form:"product_id_in" binding:"required,min=1,max=100,dive,uuid"`
+	LastEventID int64    `form:"last_event_id" binding:"omitempty,gte=0"`
+}
+
+// StockAvailabilityEvent is the available stock of a product. ID is the last
+// stock movement the availability was read after, so it orders events across
+// instances and a stream can be resumed from it.
+type StockAvailabilityEvent struct {
+	ID             int64     `json:"-"`
+	ProductID      uuid.UUID `json:"product_id"`
+	AvailableStock int       `json:"available_stock"`
+}
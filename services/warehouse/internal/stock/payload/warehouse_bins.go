@@ -0,0 +1,44 @@
+package payload
+
+import "github.com/google/uuid"
+
+type CreateBinReq struct {
+	WarehouseID uuid.UUID `json:"warehouse_id" binding:"required"`
+	Zone        string    `json:"zone" binding:"required,max=64"`
+	Code        string    `json:"code" binding:"required,max=64"`
+}
+
+type GetBinsReq struct {
+	WarehouseIDIN []string `form:"warehouse_id_in" binding:"omitempty"`
+	IDIN          []string `form:"id_in" binding:"omitempty"`
+	ZoneIN        []string `form:"zone_in" binding:"omitempty"`
+	CodeIN        []string `form:"code_in" binding:"omitempty"`
+}
+
+type GetBinStocksReq struct {
+	WarehouseIDIN []string `form:"warehouse_id_in" binding:"omitempty"`
+	ProductIDIN   []string `form:"product_id_in" binding:"omitempty"`
+	BinIDIN       []string `form:"bin_id_in" binding:"omitempty"`
+	InStockOnly   bool     `form:"in_stock_only" binding:"omitempty"`
+}
+
+// PutAwayStockReq shelves on-hand quantity of the bin's warehouse that is not
+// in any bin yet.
+type PutAwayStockReq struct {
+	BinID     uuid.UUID `json:"bin_id" binding:"required"`
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required,min=1"`
+}
+
+// MoveBinStockReq moves quantity between two bins of the same warehouse.
+type MoveBinStockReq struct {
+	FromBinID uuid.UUID `json:"from_bin_id" binding:"required"`
+	ToBinID   uuid.UUID `json:"to_bin_id" binding:"required"`
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required,min=1"`
+}
+
+type GetPickListsReq struct {
+	WarehouseIDIN []string `form:"warehouse_id_in" binding:"omitempty"`
+	OrderRef      string   `form:"order_ref" binding:"omitempty"`
+}
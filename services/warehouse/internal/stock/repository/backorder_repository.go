@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name=BackorderRepository --case underscore
+type BackorderRepository interface {
+	WithTX(tx *gorm.DB) BackorderRepository
+	WithLockForUpdate() BackorderRepository
+	UpsertBackorderProduct(ctx context.Context, product *model.BackorderProduct) error
+	DeleteBackorderProduct(ctx context.Context, productID string) error
+	GetBackorderProducts(ctx context.Context, productIDs []string) ([]model.BackorderProduct, error)
+	CreateBackorder(ctx context.Context, backorder *model.Backorder) error
+	GetBackorders(ctx context.Context, req payload.GetBackordersReq) ([]model.Backorder, error)
+	UpdateBackorder(ctx context.Context, backorder *model.Backorder) error
+	AllocateBackorderQty(ctx context.Context, backorderID string, quantity int) (bool, error)
+	CreateBackorderAllocations(ctx context.Context, allocations []model.BackorderAllocation) error
+}
+
+type backorderRepository struct {
+	db *gorm.DB
+}
+
+func NewBackorderRepository(db *gorm.DB) BackorderRepository {
+	return &backorderRepository{db: db}
+}
+
+func (r *backorderRepository) WithTX(tx *gorm.DB) BackorderRepository {
+	if tx == nil {
+		return r
+	}
+	return &backorderRepository{db: tx}
+}
+
+func (r *backorderRepository) WithLockForUpdate() BackorderRepository {
+	return &backorderRepository{
+		db: r.db.Clauses(clause.Locking{Strength: "UPDATE"}),
+	}
+}
+
+// UpsertBackorderProduct flags the product, changing the mode when it already
+// is.
+func (r *backorderRepository) UpsertBackorderProduct(ctx context.Context, product *model.BackorderProduct) error {
+	ctx, span := observ.GetTracer().Start(ctx, "backorderRepository.UpsertBackorderProduct")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mode", "updated_at"}),
+	}).Create(product).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to upsert backorder product")
+	}
+	return nil
+}
+
+func (r *backorderRepository) DeleteBackorderProduct(ctx context.Context, productID string) error {
+	ctx, span := observ.GetTracer().Start(ctx, "backorderRepository.DeleteBackorderProduct")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Delete(&model.BackorderProduct{}).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to delete backorder product")
+	}
+	return nil
+}
+
+func (r *backorderRepository) GetBackorderProducts(ctx context.Context, productIDs []string) ([]model.BackorderProduct, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "backorderRepository.GetBackorderProducts")
+	defer span.End()
+
+	var products []model.BackorderProduct
+	if err := r.db.WithContext(ctx).Where("product_id IN ?", productIDs).Find(&products).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get backorder products")
+	}
+	return products, nil
+}
+
+func (r *backorderRepository) CreateBackorder(ctx context.Context, backorder *model.Backorder) error {
+	ctx, span := observ.GetTracer().Start(ctx, "backorderRepository.CreateBackorder")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(backorder).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create backorder")
+	}
+	return nil
+}
+
+// GetBackorders returns the backorders oldest first, with their allocations.
+func (r *backorderRepository) GetBackorders(ctx context.Context, req payload.GetBackordersReq) ([]model.Backorder, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "backorderRepository.GetBackorders")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx).Preload("Allocations", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	})
+	if len(req.OrderRefIN) > 0 {
+		stmt = stmt.Where("order_ref IN ?", req.OrderRefIN)
+	}
+
+	if len(req.ProductIDIN) > 0 {
+		stmt = stmt.Where("product_id IN ?", req.ProductIDIN)
+	}
+
+	if len(req.StatusIN) > 0 {
+		stmt = stmt.Where("status IN ?", req.StatusIN)
+	}
+
+	var backorders []model.Backorder
+	if err := stmt.Order("created_at, id").Find(&backorders).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get backorders")
+	}
+	return backorders, nil
+}
+
+func (r *backorderRepository) UpdateBackorder(ctx context.Context, backorder *model.Backorder) error {
+	ctx, span := observ.GetTracer().Start(ctx, "backorderRepository.UpdateBackorder")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Save(backorder).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update backorder")
+	}
+	return nil
+}
+
+// AllocateBackorderQty adds the quantity to what is allocated to a waiting
+// backorder, marking it allocated once it is complete. It reports false when
+// the backorder is no longer waiting or the quantity would exceed it.
+func (r *backorderRepository) AllocateBackorderQty(ctx context.Context, backorderID string, quantity int) (bool, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "backorderRepository.AllocateBackorderQty")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(&model.Backorder{}).
+		Where("id = ? AND status = ? AND allocated_quantity + ? <= quantity", backorderID, constant.BackorderStatusWaiting, quantity).
+		Updates(map[string]any{
+			"allocated_quantity": gorm.Expr("allocated_quantity + ?", quantity),
+			"status":             gorm.Expr("CASE WHEN allocated_quantity + ? = quantity THEN ? ELSE status END", quantity, constant.BackorderStatusAllocated),
+			"allocated_at":       gorm.Expr("CASE WHEN allocated_quantity + ? = quantity THEN CURRENT_TIMESTAMP ELSE allocated_at END", quantity),
+		})
+	if err := result.Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return false, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to allocate backorder quantity")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *backorderRepository) CreateBackorderAllocations(ctx context.Context, allocations []model.BackorderAllocation) error {
+	ctx, span := observ.GetTracer().Start(ctx, "backorderRepository.CreateBackorderAllocations")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(&allocations).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create backorder allocations")
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertBackorderProduct(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, data model.BackorderProduct)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name string
+		data model.BackorderProduct
+		sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success",
+			data: model.BackorderProduct{
+				ProductID: uuid.New(),
+				Mode:      constant.BackorderModePreorder,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.BackorderProduct) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`INSERT INTO "backorder_products" ("product_id","mode","created_at","updated_at") VALUES ($1,$2,$3,$4) ON CONFLICT ("product_id") DO UPDATE SET "mode"="excluded"."mode","updated_at"="excluded"."updated_at"`,
+						),
+					).WithArgs(
+						data.ProductID,
+						data.Mode,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					).WillReturnResult(
+						sqlmock.NewResult(1, 1),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to upsert backorder product",
+			data: model.BackorderProduct{
+				ProductID: uuid.New(),
+				Mode:      constant.BackorderModeBackorder,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.BackorderProduct) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`INSERT INTO "backorder_products" ("product_id","mode","created_at","updated_at") VALUES ($1,$2,$3,$4) ON CONFLICT ("product_id") DO UPDATE SET "mode"="excluded"."mode","updated_at"="excluded"."updated_at"`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.data)
+
+			repo := NewBackorderRepository(mockDb.Db)
+
+			err := repo.UpsertBackorderProduct(context.Background(), &tt.data)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetBackorders(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetBackordersReq)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		req     payload.GetBackordersReq
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get backorders",
+			req: payload.GetBackordersReq{
+				OrderRefIN:  []string{"order-1"},
+				ProductIDIN: []string{uuid.New().String()},
+				StatusIN:    []string{constant.BackorderStatusAllocated},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetBackordersReq) {
+					backorderID := uuid.New()
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "backorders" WHERE order_ref IN ($1) AND product_id IN ($2) AND status IN ($3) ORDER BY created_at, id`,
+						),
+					).WithArgs(req.OrderRefIN[0], req.ProductIDIN[0], req.StatusIN[0]).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "order_ref", "product_id", "quantity", "allocated_quantity", "status"}).
+							AddRow(backorderID, req.OrderRefIN[0], req.ProductIDIN[0], 5, 5, req.StatusIN[0]),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "backorder_allocations" WHERE "backorder_allocations"."backorder_id" = $1 ORDER BY created_at`,
+						),
+					).WithArgs(backorderID).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "backorder_id", "warehouse_id", "quantity"}).
+							AddRow(uuid.New(), backorderID, uuid.New(), 2).
+							AddRow(uuid.New(), backorderID, uuid.New(), 3),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get backorders",
+			req: payload.GetBackordersReq{
+				StatusIN: []string{constant.BackorderStatusWaiting},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetBackordersReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "backorders" WHERE status IN ($1) ORDER BY created_at, id`,
+						),
+					).WithArgs(req.StatusIN[0]).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.req)
+
+			repo := NewBackorderRepository(mockDb.Db)
+
+			backorders, err := repo.GetBackorders(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, backorders, 1)
+			assert.Len(t, backorders[0].Allocations, 2)
+		})
+	}
+}
+
+func TestAllocateBackorderQty(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, backorderID string, quantity int)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name        string
+		backorderID string
+		quantity    int
+		sqlMock     sqlMock
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "success - backorder quantity allocated",
+			backorderID: uuid.New().String(),
+			quantity:    5,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, backorderID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "backorders" SET "allocated_at"=CASE WHEN allocated_quantity + $1 = quantity THEN CURRENT_TIMESTAMP ELSE allocated_at END,"allocated_quantity"=allocated_quantity + $2,"status"=CASE WHEN allocated_quantity + $3 = quantity THEN $4 ELSE status END,"updated_at"=$5 WHERE id = $6 AND status = $7 AND allocated_quantity + $8 <= quantity`,
+						),
+					).WithArgs(
+						quantity, quantity, quantity, constant.BackorderStatusAllocated, sqlmock.AnyArg(),
+						backorderID, constant.BackorderStatusWaiting, quantity,
+					).WillReturnResult(
+						sqlmock.NewResult(0, 1),
+					)
+				},
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name:        "success - backorder no longer waiting",
+			backorderID: uuid.New().String(),
+			quantity:    5,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, backorderID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "backorders" SET "allocated_at"=CASE WHEN allocated_quantity + $1 = quantity THEN CURRENT_TIMESTAMP ELSE allocated_at END,"allocated_quantity"=allocated_quantity + $2,"status"=CASE WHEN allocated_quantity + $3 = quantity THEN $4 ELSE status END,"updated_at"=$5 WHERE id = $6 AND status = $7 AND allocated_quantity + $8 <= quantity`,
+						),
+					).WillReturnResult(
+						sqlmock.NewResult(0, 0),
+					)
+				},
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name:        "error - failed to allocate backorder quantity",
+			backorderID: uuid.New().String(),
+			quantity:    5,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, backorderID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "backorders" SET "allocated_at"=CASE WHEN allocated_quantity + $1 = quantity THEN CURRENT_TIMESTAMP ELSE allocated_at END,"allocated_quantity"=allocated_quantity + $2,"status"=CASE WHEN allocated_quantity + $3 = quantity THEN $4 ELSE status END,"updated_at"=$5 WHERE id = $6 AND status = $7 AND allocated_quantity + $8 <= quantity`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.backorderID, tt.quantity)
+
+			repo := NewBackorderRepository(mockDb.Db)
+
+			allocated, err := repo.AllocateBackorderQty(context.Background(), tt.backorderID, tt.quantity)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, allocated)
+		})
+	}
+}
@@ -75,6 +75,24 @@ func (_m *StockRepository) CreateStockTransfer(ctx context.Context, transferStoc
 	return r0
 }
 
+// DecreaseStockQty provides a mock function with given fields: ctx, productID, warehouseID, quantity
+func (_m *StockRepository) DecreaseStockQty(ctx context.Context, productID string, warehouseID string, quantity int) error {
+	ret := _m.Called(ctx, productID, warehouseID, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DecreaseStockQty")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) error); ok {
+		r0 = rf(ctx, productID, warehouseID, quantity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetAvailableStocksByProduct provides a mock function with given fields: ctx, req
 func (_m *StockRepository) GetAvailableStocksByProduct(ctx context.Context, req payload.GetStockAvailablesByProductReq) ([]model.GetStockAvailablesByProduct, error) {
 	ret := _m.Called(ctx, req)
@@ -135,6 +153,82 @@ func (_m *StockRepository) GetStocks(ctx context.Context, req payload.GetStocksR
 	return r0, r1
 }
 
+// GetWarehouseStocks provides a mock function with given fields: ctx, warehouseID
+func (_m *StockRepository) GetWarehouseStocks(ctx context.Context, warehouseID string) ([]model.WarehouseStock, error) {
+	ret := _m.Called(ctx, warehouseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWarehouseStocks")
+	}
+
+	var r0 []model.WarehouseStock
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]model.WarehouseStock, error)); ok {
+		return rf(ctx, warehouseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []model.WarehouseStock); ok {
+		r0 = rf(ctx, warehouseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.WarehouseStock)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, warehouseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncreaseStockQty provides a mock function with given fields: ctx, productID, warehouseID, quantity
+func (_m *StockRepository) IncreaseStockQty(ctx context.Context, productID string, warehouseID string, quantity int) error {
+	ret := _m.Called(ctx, productID, warehouseID, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncreaseStockQty")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) error); ok {
+		r0 = rf(ctx, productID, warehouseID, quantity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReserveStockQty provides a mock function with given fields: ctx, productID, warehouseID, quantity
+func (_m *StockRepository) ReserveStockQty(ctx context.Context, productID string, warehouseID string, quantity int) (bool, error) {
+	ret := _m.Called(ctx, productID, warehouseID, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveStockQty")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (bool, error)); ok {
+		return rf(ctx, productID, warehouseID, quantity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) bool); ok {
+		r0 = rf(ctx, productID, warehouseID, quantity)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, productID, warehouseID, quantity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateStock provides a mock function with given fields: ctx, stock
 func (_m *StockRepository) UpdateStock(ctx context.Context, stock *model.WarehouseStock) error {
 	ret := _m.Called(ctx, stock)
@@ -153,6 +247,42 @@ func (_m *StockRepository) UpdateStock(ctx context.Context, stock *model.Warehou
 	return r0
 }
 
+// UpdateStockThreshold provides a mock function with given fields: ctx, productID, warehouseID, threshold
+func (_m *StockRepository) UpdateStockThreshold(ctx context.Context, productID string, warehouseID string, threshold *int) error {
+	ret := _m.Called(ctx, productID, warehouseID, threshold)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStockThreshold")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *int) error); ok {
+		r0 = rf(ctx, productID, warehouseID, threshold)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertStocks provides a mock function with given fields: ctx, stocks
+func (_m *StockRepository) UpsertStocks(ctx context.Context, stocks []model.WarehouseStock) error {
+	ret := _m.Called(ctx, stocks)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertStocks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []model.WarehouseStock) error); ok {
+		r0 = rf(ctx, stocks)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // WithLockForUpdate provides a mock function with no fields
 func (_m *StockRepository) WithLockForUpdate() repository.StockRepository {
 	ret := _m.Called()
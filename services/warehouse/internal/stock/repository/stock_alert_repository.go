@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name=StockAlertRepository --case underscore
+type StockAlertRepository interface {
+	WithTX(tx *gorm.DB) StockAlertRepository
+	GetProductThresholds(ctx context.Context, productIDs []string) ([]model.ProductStockThreshold, error)
+	UpsertProductThreshold(ctx context.Context, threshold *model.ProductStockThreshold) error
+	DeleteProductThreshold(ctx context.Context, productID string) error
+	CreateStockAlerts(ctx context.Context, alerts []model.StockAlert) error
+	GetLatestStockAlerts(ctx context.Context, productIDs []string) ([]model.StockAlert, error)
+	GetStockAlerts(ctx context.Context, req payload.GetStockAlertsReq) ([]model.StockAlert, error)
+}
+
+type stockAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewStockAlertRepository(db *gorm.DB) StockAlertRepository {
+	return &stockAlertRepository{db: db}
+}
+
+func (r *stockAlertRepository) WithTX(tx *gorm.DB) StockAlertRepository {
+	if tx == nil {
+		return r
+	}
+	return &stockAlertRepository{db: tx}
+}
+
+func (r *stockAlertRepository) GetProductThresholds(ctx context.Context, productIDs []string) ([]model.ProductStockThreshold, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockAlertRepository.GetProductThresholds")
+	defer span.End()
+
+	var thresholds []model.ProductStockThreshold
+	if err := r.db.WithContext(ctx).Where("product_id IN ?", productIDs).Find(&thresholds).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get product stock thresholds")
+	}
+	return thresholds, nil
+}
+
+func (r *stockAlertRepository) UpsertProductThreshold(ctx context.Context, threshold *model.ProductStockThreshold) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockAlertRepository.UpsertProductThreshold")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"threshold", "updated_at"}),
+	}).Create(threshold).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to upsert product stock threshold")
+	}
+	return nil
+}
+
+func (r *stockAlertRepository) DeleteProductThreshold(ctx context.Context, productID string) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockAlertRepository.DeleteProductThreshold")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Delete(&model.ProductStockThreshold{}).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to delete product stock threshold")
+	}
+	return nil
+}
+
+func (r *stockAlertRepository) CreateStockAlerts(ctx context.Context, alerts []model.StockAlert) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockAlertRepository.CreateStockAlerts")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(&alerts).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create stock alerts")
+	}
+	return nil
+}
+
+// GetLatestStockAlerts returns the most recent alert of every warehouse and
+// product scope for the given products, which represents their current state.
+func (r *stockAlertRepository) GetLatestStockAlerts(ctx context.Context, productIDs []string) ([]model.StockAlert, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockAlertRepository.GetLatestStockAlerts")
+	defer span.End()
+
+	var alerts []model.StockAlert
+	if err := r.latestStockAlerts().WithContext(ctx).
+		Where("product_id IN ?", productIDs).
+		Find(&alerts).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get latest stock alerts")
+	}
+	return alerts, nil
+}
+
+func (r *stockAlertRepository) GetStockAlerts(ctx context.Context, req payload.GetStockAlertsReq) ([]model.StockAlert, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockAlertRepository.GetStockAlerts")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx).Model(&model.StockAlert{})
+	if req.ActiveOnly {
+		stmt = r.db.WithContext(ctx).
+			Table("(?) AS stock_alerts", r.latestStockAlerts()).
+			Where("status <> ?", constant.StockAlertStatusRecovered)
+	}
+
+	if len(req.WarehouseIDIN) > 0 {
+		stmt = stmt.Where("warehouse_id IN ?", req.WarehouseIDIN)
+	}
+
+	if len(req.ProductIDIN) > 0 {
+		stmt = stmt.Where("product_id IN ?", req.ProductIDIN)
+	}
+
+	if len(req.StatusIN) > 0 {
+		stmt = stmt.Where("status IN ?", req.StatusIN)
+	}
+
+	var alerts []model.StockAlert
+	if err := stmt.Order("created_at DESC").Find(&alerts).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get stock alerts")
+	}
+	return alerts, nil
+}
+
+func (r *stockAlertRepository) latestStockAlerts() *gorm.DB {
+	return r.db.Model(&model.StockAlert{}).
+		Select("DISTINCT ON (scope, warehouse_id, product_id) *").
+		Order("scope, warehouse_id, product_id, created_at DESC")
+}
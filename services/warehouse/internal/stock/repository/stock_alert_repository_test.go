@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertProductThreshold(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, data model.ProductStockThreshold)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name string
+		data model.ProductStockThreshold
+		sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success",
+			data: model.ProductStockThreshold{
+				ProductID: uuid.New(),
+				Threshold: 10,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.ProductStockThreshold) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`INSERT INTO "product_stock_thresholds" ("product_id","threshold","created_at","updated_at") VALUES ($1,$2,$3,$4) ON CONFLICT ("product_id") DO UPDATE SET "threshold"="excluded"."threshold","updated_at"="excluded"."updated_at"`,
+						),
+					).WithArgs(
+						data.ProductID,
+						data.Threshold,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					).WillReturnResult(
+						sqlmock.NewResult(1, 1),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to upsert product threshold",
+			data: model.ProductStockThreshold{
+				ProductID: uuid.New(),
+				Threshold: 10,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.ProductStockThreshold) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`INSERT INTO "product_stock_thresholds" ("product_id","threshold","created_at","updated_at") VALUES ($1,$2,$3,$4) ON CONFLICT ("product_id") DO UPDATE SET "threshold"="excluded"."threshold","updated_at"="excluded"."updated_at"`,
+						),
+					).WithArgs(
+						data.ProductID,
+						data.Threshold,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.data)
+
+			repo := NewStockAlertRepository(mockDb.Db)
+
+			err := repo.UpsertProductThreshold(context.Background(), &tt.data)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestCreateStockAlerts(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, data []model.StockAlert)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	warehouseID := uuid.New()
+	tests := []struct {
+		name string
+		data []model.StockAlert
+		sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success",
+			data: []model.StockAlert{
+				{
+					Scope:       constant.StockAlertScopeWarehouse,
+					WarehouseID: &warehouseID,
+					ProductID:   uuid.New(),
+					Status:      constant.StockAlertStatusLow,
+					Available:   2,
+					Threshold:   5,
+				},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data []model.StockAlert) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "stock_alerts" ("scope","warehouse_id","product_id","status","available","threshold","created_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`,
+						),
+					).WithArgs(
+						data[0].Scope,
+						data[0].WarehouseID,
+						data[0].ProductID,
+						data[0].Status,
+						data[0].Available,
+						data[0].Threshold,
+						sqlmock.AnyArg(),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to create stock alerts",
+			data: []model.StockAlert{
+				{
+					Scope:     constant.StockAlertScopeProduct,
+					ProductID: uuid.New(),
+					Status:    constant.StockAlertStatusOut,
+					Available: 0,
+					Threshold: 5,
+				},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data []model.StockAlert) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "stock_alerts" ("scope","warehouse_id","product_id","status","available","threshold","created_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`,
+						),
+					).WithArgs(
+						data[0].Scope,
+						data[0].WarehouseID,
+						data[0].ProductID,
+						data[0].Status,
+						data[0].Available,
+						data[0].Threshold,
+						sqlmock.AnyArg(),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.data)
+
+			repo := NewStockAlertRepository(mockDb.Db)
+
+			err := repo.CreateStockAlerts(context.Background(), tt.data)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestGetStockAlerts(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetStockAlertsReq)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		req     payload.GetStockAlertsReq
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get stock alerts history",
+			req: payload.GetStockAlertsReq{
+				ProductIDIN: []string{uuid.New().String()},
+				StatusIN:    []string{constant.StockAlertStatusLow},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStockAlertsReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_alerts" WHERE product_id IN ($1) AND status IN ($2) ORDER BY created_at DESC`,
+						),
+					).WithArgs(req.ProductIDIN[0], req.StatusIN[0]).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "scope", "product_id", "status", "available", "threshold", "created_at"}).
+							AddRow(uuid.New(), constant.StockAlertScopeProduct, req.ProductIDIN[0], constant.StockAlertStatusLow, 2, 5, time.Now()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success - get active stock alerts",
+			req: payload.GetStockAlertsReq{
+				WarehouseIDIN: []string{uuid.New().String()},
+				ActiveOnly:    true,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStockAlertsReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM (SELECT DISTINCT ON (scope, warehouse_id, product_id) * FROM "stock_alerts" ORDER BY scope, warehouse_id, product_id, created_at DESC) AS stock_alerts WHERE status <> $1 AND warehouse_id IN ($2) ORDER BY created_at DESC`,
+						),
+					).WithArgs(constant.StockAlertStatusRecovered, req.WarehouseIDIN[0]).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "scope", "warehouse_id", "product_id", "status", "available", "threshold", "created_at"}).
+							AddRow(uuid.New(), constant.StockAlertScopeWarehouse, req.WarehouseIDIN[0], uuid.New(), constant.StockAlertStatusOut, 0, 5, time.Now()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get stock alerts",
+			req:  payload.GetStockAlertsReq{},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStockAlertsReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_alerts" ORDER BY created_at DESC`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.req)
+
+			repo := NewStockAlertRepository(mockDb.Db)
+
+			alerts, err := repo.GetStockAlerts(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NotEmpty(t, alerts)
+		})
+	}
+}
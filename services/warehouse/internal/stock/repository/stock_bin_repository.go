@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name=StockBinRepository --case underscore
+type StockBinRepository interface {
+	WithTX(tx *gorm.DB) StockBinRepository
+	WithLockForUpdate() StockBinRepository
+	CreateBin(ctx context.Context, bin *model.WarehouseBin) error
+	GetBins(ctx context.Context, req payload.GetBinsReq) ([]model.WarehouseBin, error)
+	GetBinStocks(ctx context.Context, req payload.GetBinStocksReq) ([]model.BinStock, error)
+	AddBinStockQty(ctx context.Context, binID string, warehouseID string, productID string, quantity int) error
+	CreatePickList(ctx context.Context, pickList *model.PickList) error
+	GetPickLists(ctx context.Context, req payload.GetPickListsReq) ([]model.PickList, error)
+}
+
+type stockBinRepository struct {
+	db *gorm.DB
+}
+
+func NewStockBinRepository(db *gorm.DB) StockBinRepository {
+	return &stockBinRepository{db: db}
+}
+
+func (r *stockBinRepository) WithTX(tx *gorm.DB) StockBinRepository {
+	if tx == nil {
+		return r
+	}
+	return &stockBinRepository{db: tx}
+}
+
+func (r *stockBinRepository) WithLockForUpdate() StockBinRepository {
+	return &stockBinRepository{
+		db: r.db.Clauses(clause.Locking{Strength: "UPDATE"}),
+	}
+}
+
+func (r *stockBinRepository) CreateBin(ctx context.Context, bin *model.WarehouseBin) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockBinRepository.CreateBin")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(bin).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create bin")
+	}
+	return nil
+}
+
+func (r *stockBinRepository) GetBins(ctx context.Context, req payload.GetBinsReq) ([]model.WarehouseBin, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockBinRepository.GetBins")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx)
+	if len(req.WarehouseIDIN) > 0 {
+		stmt = stmt.Where("warehouse_id IN ?", req.WarehouseIDIN)
+	}
+
+	if len(req.IDIN) > 0 {
+		stmt = stmt.Where("id IN ?", req.IDIN)
+	}
+
+	if len(req.ZoneIN) > 0 {
+		stmt = stmt.Where("zone IN ?", req.ZoneIN)
+	}
+
+	if len(req.CodeIN) > 0 {
+		stmt = stmt.Where("code IN ?", req.CodeIN)
+	}
+
+	var bins []model.WarehouseBin
+	if err := stmt.Order("zone, code").Find(&bins).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get bins")
+	}
+	return bins, nil
+}
+
+// GetBinStocks returns the bin stocks with the zone and code of their bin, in
+// the order pickers walk the bins.
+func (r *stockBinRepository) GetBinStocks(ctx context.Context, req payload.GetBinStocksReq) ([]model.BinStock, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockBinRepository.GetBinStocks")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx).
+		Select("bin_stocks.*, warehouse_bins.zone, warehouse_bins.code AS bin_code").
+		Joins("JOIN warehouse_bins ON warehouse_bins.id = bin_stocks.bin_id")
+	if len(req.WarehouseIDIN) > 0 {
+		stmt = stmt.Where("bin_stocks.warehouse_id IN ?", req.WarehouseIDIN)
+	}
+
+	if len(req.ProductIDIN) > 0 {
+		stmt = stmt.Where("bin_stocks.product_id IN ?", req.ProductIDIN)
+	}
+
+	if len(req.BinIDIN) > 0 {
+		stmt = stmt.Where("bin_stocks.bin_id IN ?", req.BinIDIN)
+	}
+
+	if req.InStockOnly {
+		stmt = stmt.Where("bin_stocks.quantity > 0")
+	}
+
+	var binStocks []model.BinStock
+	if err := stmt.Order("warehouse_bins.zone, warehouse_bins.code").Find(&binStocks).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get bin stocks")
+	}
+	return binStocks, nil
+}
+
+// AddBinStockQty adds the quantity to the product in the bin, shelving it
+// there when the bin does not hold the product yet. A negative quantity takes
+// it out.
+func (r *stockBinRepository) AddBinStockQty(ctx context.Context, binID string, warehouseID string, productID string, quantity int) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockBinRepository.AddBinStockQty")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Model(&model.BinStock{}).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "bin_id"}, {Name: "product_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"quantity":   gorm.Expr("bin_stocks.quantity + excluded.quantity"),
+			"updated_at": gorm.Expr("excluded.updated_at"),
+		}),
+	}).Create(map[string]any{
+		"bin_id":       binID,
+		"warehouse_id": warehouseID,
+		"product_id":   productID,
+		"quantity":     quantity,
+	}).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update bin stock quantity")
+	}
+	return nil
+}
+
+func (r *stockBinRepository) CreatePickList(ctx context.Context, pickList *model.PickList) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockBinRepository.CreatePickList")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(pickList).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create pick list")
+	}
+	return nil
+}
+
+// GetPickLists returns the pick lists newest first, with their items in
+// picking order.
+func (r *stockBinRepository) GetPickLists(ctx context.Context, req payload.GetPickListsReq) ([]model.PickList, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockBinRepository.GetPickLists")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("zone NULLS LAST, bin_code")
+		})
+	if len(req.WarehouseIDIN) > 0 {
+		stmt = stmt.Where("warehouse_id IN ?", req.WarehouseIDIN)
+	}
+
+	if req.OrderRef != "" {
+		stmt = stmt.Where("order_ref = ?", req.OrderRef)
+	}
+
+	var pickLists []model.PickList
+	if err := stmt.Order("created_at DESC").Find(&pickLists).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get pick lists")
+	}
+	return pickLists, nil
+}
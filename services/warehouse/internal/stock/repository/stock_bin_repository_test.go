@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBinStocks(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetBinStocksReq)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		req     payload.GetBinStocksReq
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get bin stocks",
+			req: payload.GetBinStocksReq{
+				WarehouseIDIN: []string{uuid.New().String()},
+				ProductIDIN:   []string{uuid.New().String()},
+				BinIDIN:       []string{uuid.New().String()},
+				InStockOnly:   true,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetBinStocksReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT bin_stocks.*, warehouse_bins.zone, warehouse_bins.code AS bin_code FROM "bin_stocks" JOIN warehouse_bins ON warehouse_bins.id = bin_stocks.bin_id WHERE bin_stocks.warehouse_id IN ($1) AND bin_stocks.product_id IN ($2) AND bin_stocks.bin_id IN ($3) AND bin_stocks.quantity > 0 ORDER BY warehouse_bins.zone, warehouse_bins.code`,
+						),
+					).WithArgs(req.WarehouseIDIN[0], req.ProductIDIN[0], req.BinIDIN[0]).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "bin_id", "warehouse_id", "product_id", "quantity", "zone", "bin_code"}).
+							AddRow(uuid.New(), req.BinIDIN[0], req.WarehouseIDIN[0], req.ProductIDIN[0], 4, "A", "A-01-01"),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get bin stocks",
+			req:  payload.GetBinStocksReq{},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetBinStocksReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT bin_stocks.*, warehouse_bins.zone, warehouse_bins.code AS bin_code FROM "bin_stocks" JOIN warehouse_bins ON warehouse_bins.id = bin_stocks.bin_id ORDER BY warehouse_bins.zone, warehouse_bins.code`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.req)
+
+			repo := NewStockBinRepository(mockDb.Db)
+
+			binStocks, err := repo.GetBinStocks(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, binStocks, 1)
+			assert.Equal(t, "A-01-01", binStocks[0].BinCode)
+		})
+	}
+}
+
+func TestAddBinStockQty(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, binID, warehouseID, productID string, quantity int)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		binID       string
+		warehouseID string
+		productID   string
+		quantity    int
+		sqlMock     sqlMock
+		wantErr     bool
+	}{
+		{
+			name:        "success - add bin stock quantity",
+			binID:       uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			productID:   uuid.New().String(),
+			quantity:    5,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, binID, warehouseID, productID string, quantity int) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "bin_stocks" ("bin_id","product_id","quantity","warehouse_id") VALUES ($1,$2,$3,$4) ON CONFLICT ("bin_id","product_id") DO UPDATE SET "quantity"=bin_stocks.quantity + excluded.quantity,"updated_at"=excluded.updated_at RETURNING "id"`,
+						),
+					).WithArgs(
+						binID,
+						productID,
+						quantity,
+						warehouseID,
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "error - failed to add bin stock quantity",
+			binID:       uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			productID:   uuid.New().String(),
+			quantity:    -5,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, binID, warehouseID, productID string, quantity int) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "bin_stocks" ("bin_id","product_id","quantity","warehouse_id") VALUES ($1,$2,$3,$4) ON CONFLICT ("bin_id","product_id") DO UPDATE SET "quantity"=bin_stocks.quantity + excluded.quantity,"updated_at"=excluded.updated_at RETURNING "id"`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.binID, tt.warehouseID, tt.productID, tt.quantity)
+
+			repo := NewStockBinRepository(mockDb.Db)
+
+			err := repo.AddBinStockQty(context.Background(), tt.binID, tt.warehouseID, tt.productID, tt.quantity)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestGetPickLists(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetPickListsReq)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		req     payload.GetPickListsReq
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get pick lists",
+			req:  payload.GetPickListsReq{OrderRef: "order-1"},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetPickListsReq) {
+					pickListID := uuid.New()
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "pick_lists" WHERE order_ref = $1 ORDER BY created_at DESC`,
+						),
+					).WithArgs(req.OrderRef).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "warehouse_id", "order_ref"}).
+							AddRow(pickListID, uuid.New(), req.OrderRef),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "pick_list_items" WHERE "pick_list_items"."pick_list_id" = $1 ORDER BY zone NULLS LAST, bin_code`,
+						),
+					).WithArgs(pickListID).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "pick_list_id", "product_id", "bin_id", "zone", "bin_code", "quantity"}).
+							AddRow(uuid.New(), pickListID, uuid.New(), uuid.New(), "A", "A-01-01", 2),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get pick lists",
+			req:  payload.GetPickListsReq{},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetPickListsReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "pick_lists" ORDER BY created_at DESC`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.req)
+
+			repo := NewStockBinRepository(mockDb.Db)
+
+			pickLists, err := repo.GetPickLists(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, pickLists, 1)
+			assert.Len(t, pickLists[0].Items, 1)
+		})
+	}
+}
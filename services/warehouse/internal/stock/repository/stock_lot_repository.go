@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name=StockLotRepository --case underscore
+type StockLotRepository interface {
+	WithTX(tx *gorm.DB) StockLotRepository
+	WithLockForUpdate() StockLotRepository
+	CreateStockLot(ctx context.Context, lot *model.StockLot) error
+	GetStockLots(ctx context.Context, req payload.GetStockLotsReq) ([]model.StockLot, error)
+	AddLotQtyAndReserveQty(ctx context.Context, lotID string, quantity int, reserved int) error
+	ReserveLotQty(ctx context.Context, lotID string, quantity int) (bool, error)
+	GetStockLotReservations(ctx context.Context, req payload.GetStockLotReservationsReq) ([]model.StockLotReservation, error)
+	AddLotReservationQty(ctx context.Context, lotID string, orderRef string, quantity int) error
+	MoveStockLots(ctx context.Context, productID string, fromWarehouseID string, toWarehouseID string, quantity int) (int, error)
+	DispatchStockLots(ctx context.Context, stockTransferID string, productID string, fromWarehouseID string, quantity int) (int, error)
+	ReceiveStockLots(ctx context.Context, stockTransferID string, productID string, toWarehouseID string, quantity int) error
+}
+
+// takeStockLotsSQL takes the quantity moved out of a stock off the unreserved
+// quantity of its lots: unexpired lots first expiry first, then the quantity
+// not tracked by lot, and expired lots only once nothing else is left. It
+// returns what was taken of each lot. It runs before the stock itself is
+// decreased, so the lots are not trimmed a second time.
+const takeStockLotsSQL = `UPDATE stock_lots SET quantity = stock_lots.quantity - t.qty, updated_at = CURRENT_TIMESTAMP
+	FROM (
+		SELECT id, LEAST(free, @quantity - preceding_free) AS qty
+		FROM (
+			SELECT l.id, l.quantity - l.reserved AS free,
+				COALESCE(SUM(l.quantity - l.reserved) OVER (ORDER BY COALESCE(l.expiry_date < CURRENT_DATE, false), l.expiry_date ASC NULLS LAST, l.lot_number ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0)
+					+ CASE WHEN l.expiry_date < CURRENT_DATE THEN u.untracked ELSE 0 END AS preceding_free
+			FROM stock_lots l
+			CROSS JOIN (
+				SELECT GREATEST(ws.quantity - ws.reserved - COALESCE(SUM(sl.quantity - sl.reserved), 0), 0) AS untracked
+				FROM warehouse_stocks ws
+				LEFT JOIN stock_lots sl ON sl.warehouse_id = ws.warehouse_id AND sl.product_id = ws.product_id
+				WHERE ws.warehouse_id = @from_warehouse_id AND ws.product_id = @product_id
+				GROUP BY ws.quantity, ws.reserved
+			) u
+			WHERE l.warehouse_id = @from_warehouse_id AND l.product_id = @product_id AND l.quantity > l.reserved
+		) f
+		WHERE preceding_free < @quantity
+	) t
+	WHERE stock_lots.id = t.id
+	RETURNING stock_lots.lot_number, stock_lots.expiry_date, t.qty`
+
+// moveStockLotsSQL moves the lots taken off a stock into the same lots of the
+// destination stock, which must already exist, and returns how much of what
+// was taken had expired.
+const moveStockLotsSQL = `WITH taken AS (
+	` + takeStockLotsSQL + `
+), moved AS (
+	INSERT INTO stock_lots (warehouse_id, product_id, lot_number, expiry_date, quantity)
+	SELECT @to_warehouse_id, @product_id, lot_number, expiry_date, qty FROM taken
+	ON CONFLICT (warehouse_id, product_id, lot_number) DO UPDATE SET quantity = stock_lots.quantity + excluded.quantity, updated_at = CURRENT_TIMESTAMP
+)
+SELECT COALESCE(SUM(qty), 0) FROM taken WHERE expiry_date < CURRENT_DATE`
+
+// dispatchStockLotsSQL records the lots taken off a stock as in transit with
+// the transfer, and returns how much of what was taken had expired.
+const dispatchStockLotsSQL = `WITH taken AS (
+	` + takeStockLotsSQL + `
+), dispatched AS (
+	INSERT INTO stock_transfer_lots (stock_transfer_id, lot_number, expiry_date, quantity)
+	SELECT @stock_transfer_id, lot_number, expiry_date, qty FROM taken
+)
+SELECT COALESCE(SUM(qty), 0) FROM taken WHERE expiry_date < CURRENT_DATE`
+
+// receiveStockLotsSQL books the received quantity of a transfer into the lots
+// of the destination stock, first expiry first, out of the lots still in
+// transit. What the transfer carried beyond its lots arrives untracked.
+const receiveStockLotsSQL = `WITH received AS (
+	UPDATE stock_transfer_lots SET quantity = stock_transfer_lots.quantity - t.qty, updated_at = CURRENT_TIMESTAMP
+	FROM (
+		SELECT lot_number, LEAST(quantity, @quantity - preceding_qty) AS qty
+		FROM (
+			SELECT lot_number, quantity,
+				COALESCE(SUM(quantity) OVER (ORDER BY expiry_date ASC NULLS LAST, lot_number ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS preceding_qty
+			FROM stock_transfer_lots
+			WHERE stock_transfer_id = @stock_transfer_id AND quantity > 0
+		) f
+		WHERE preceding_qty < @quantity
+	) t
+	WHERE stock_transfer_lots.stock_transfer_id = @stock_transfer_id AND stock_transfer_lots.lot_number = t.lot_number
+	RETURNING stock_transfer_lots.lot_number, stock_transfer_lots.expiry_date, t.qty
+)
+INSERT INTO stock_lots (warehouse_id, product_id, lot_number, expiry_date, quantity)
+SELECT @to_warehouse_id, @product_id, lot_number, expiry_date, qty FROM received
+ON CONFLICT (warehouse_id, product_id, lot_number) DO UPDATE SET quantity = stock_lots.quantity + excluded.quantity, updated_at = CURRENT_TIMESTAMP`
+
+type stockLotRepository struct {
+	db *gorm.DB
+}
+
+func NewStockLotRepository(db *gorm.DB) StockLotRepository {
+	return &stockLotRepository{db: db}
+}
+
+func (r *stockLotRepository) WithTX(tx *gorm.DB) StockLotRepository {
+	if tx == nil {
+		return r
+	}
+	return &stockLotRepository{db: tx}
+}
+
+func (r *stockLotRepository) WithLockForUpdate() StockLotRepository {
+	return &stockLotRepository{
+		db: r.db.Clauses(clause.Locking{Strength: "UPDATE"}),
+	}
+}
+
+func (r *stockLotRepository) CreateStockLot(ctx context.Context, lot *model.StockLot) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockLotRepository.CreateStockLot")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(lot).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create stock lot")
+	}
+	return nil
+}
+
+// GetStockLots returns the lots first expiry first, lots without an expiry
+// date last.
+func (r *stockLotRepository) GetStockLots(ctx context.Context, req payload.GetStockLotsReq) ([]model.StockLot, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockLotRepository.GetStockLots")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx)
+	if len(req.WarehouseIDIN) > 0 {
+		stmt = stmt.Where("warehouse_id IN ?", req.WarehouseIDIN)
+	}
+
+	if len(req.ProductIDIN) > 0 {
+		stmt = stmt.Where("product_id IN ?", req.ProductIDIN)
+	}
+
+	if len(req.LotNumberIN) > 0 {
+		stmt = stmt.Where("lot_number IN ?", req.LotNumberIN)
+	}
+
+	if req.InStockOnly {
+		stmt = stmt.Where("quantity > 0")
+	}
+
+	if req.ExpiresBefore != nil {
+		stmt = stmt.Where("expiry_date < ?", req.ExpiresBefore)
+	}
+
+	var lots []model.StockLot
+	if err := stmt.Order("expiry_date ASC NULLS LAST, lot_number").Find(&lots).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get stock lots")
+	}
+	return lots, nil
+}
+
+func (r *stockLotRepository) AddLotQtyAndReserveQty(ctx context.Context, lotID string, quantity int, reserved int) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockLotRepository.AddLotQtyAndReserveQty")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Model(&model.StockLot{}).
+		Where("id = ?", lotID).
+		Updates(map[string]any{
+			"quantity": gorm.Expr("quantity + ?", quantity),
+			"reserved": gorm.Expr("reserved + ?", reserved),
+		}).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update lot quantity and reserved quantity")
+	}
+	return nil
+}
+
+// ReserveLotQty reserves the quantity of the lot in a single conditional
+// update, only when that much of the lot is still unreserved.
+func (r *stockLotRepository) ReserveLotQty(ctx context.Context, lotID string, quantity int) (bool, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockLotRepository.ReserveLotQty")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(&model.StockLot{}).
+		Where("id = ? AND reserved + ? <= quantity", lotID, quantity).
+		Update("reserved", gorm.Expr("reserved + ?", quantity))
+	if err := result.Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return false, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to reserve lot quantity")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *stockLotRepository) GetStockLotReservations(ctx context.Context, req payload.GetStockLotReservationsReq) ([]model.StockLotReservation, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockLotRepository.GetStockLotReservations")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx)
+	if len(req.StockLotIDIN) > 0 {
+		stmt = stmt.Where("stock_lot_id IN ?", req.StockLotIDIN)
+	}
+
+	if len(req.OrderRefIN) > 0 {
+		stmt = stmt.Where("order_ref IN ?", req.OrderRefIN)
+	}
+
+	var reservations []model.StockLotReservation
+	if err := stmt.Find(&reservations).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get stock lot reservations")
+	}
+	return reservations, nil
+}
+
+// AddLotReservationQty adds the quantity to what the order reserved of the
+// lot, a negative quantity releases it. A reservation released in full is
+// removed.
+func (r *stockLotRepository) AddLotReservationQty(ctx context.Context, lotID string, orderRef string, quantity int) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockLotRepository.AddLotReservationQty")
+	defer span.End()
+
+	stockLotID, err := uuid.Parse(lotID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid lot ID")
+	}
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "stock_lot_id"}, {Name: "order_ref"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"quantity":   gorm.Expr("stock_lot_reservations.quantity + excluded.quantity"),
+			"updated_at": gorm.Expr("excluded.updated_at"),
+		}),
+	}).Create(&model.StockLotReservation{
+		StockLotID: stockLotID,
+		OrderRef:   orderRef,
+		Quantity:   quantity,
+	}).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update lot reservation")
+	}
+
+	if quantity < 0 {
+		if err := r.db.WithContext(ctx).
+			Where("stock_lot_id = ? AND order_ref = ? AND quantity = 0", lotID, orderRef).
+			Delete(&model.StockLotReservation{}).Error; err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to delete lot reservation")
+		}
+	}
+	return nil
+}
+
+// MoveStockLots moves the lots of the quantity moved between the warehouses
+// along with it, keeping their lot numbers and expiry dates. It runs once the
+// destination stock exists and before the source stock is decreased, and
+// returns how much expired quantity had to be moved.
+func (r *stockLotRepository) MoveStockLots(ctx context.Context, productID string, fromWarehouseID string, toWarehouseID string, quantity int) (int, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockLotRepository.MoveStockLots")
+	defer span.End()
+
+	var expired int
+	if err := r.db.WithContext(ctx).Raw(moveStockLotsSQL, map[string]any{
+		"product_id":        productID,
+		"from_warehouse_id": fromWarehouseID,
+		"to_warehouse_id":   toWarehouseID,
+		"quantity":          quantity,
+	}).Scan(&expired).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return 0, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to move stock lots")
+	}
+	return expired, nil
+}
+
+// DispatchStockLots takes the lots of the dispatched quantity out of the
+// source warehouse and keeps them with the transfer until it is received. It
+// runs before the source stock is decreased, and returns how much expired
+// quantity had to be dispatched.
+func (r *stockLotRepository) DispatchStockLots(ctx context.Context, stockTransferID string, productID string, fromWarehouseID string, quantity int) (int, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockLotRepository.DispatchStockLots")
+	defer span.End()
+
+	var expired int
+	if err := r.db.WithContext(ctx).Raw(dispatchStockLotsSQL, map[string]any{
+		"stock_transfer_id": stockTransferID,
+		"product_id":        productID,
+		"from_warehouse_id": fromWarehouseID,
+		"quantity":          quantity,
+	}).Scan(&expired).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return 0, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to dispatch stock lots")
+	}
+	return expired, nil
+}
+
+// ReceiveStockLots books the lots of the received quantity of the transfer
+// into the destination warehouse. It runs once the destination stock exists.
+func (r *stockLotRepository) ReceiveStockLots(ctx context.Context, stockTransferID string, productID string, toWarehouseID string, quantity int) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockLotRepository.ReceiveStockLots")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Exec(receiveStockLotsSQL, map[string]any{
+		"stock_transfer_id": stockTransferID,
+		"product_id":        productID,
+		"to_warehouse_id":   toWarehouseID,
+		"quantity":          quantity,
+	}).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to receive stock lots")
+	}
+	return nil
+}
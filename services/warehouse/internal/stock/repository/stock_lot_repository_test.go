@@ -0,0 +1,567 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateStockLot(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, data model.StockLot)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	expiryDate := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		data model.StockLot
+		sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success",
+			data: model.StockLot{
+				WarehouseID: uuid.New(),
+				ProductID:   uuid.New(),
+				LotNumber:   "LOT-001",
+				ExpiryDate:  &expiryDate,
+				Quantity:    10,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.StockLot) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "stock_lots" ("warehouse_id","product_id","lot_number","expiry_date","quantity","reserved","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`,
+						),
+					).WithArgs(
+						data.WarehouseID,
+						data.ProductID,
+						data.LotNumber,
+						data.ExpiryDate,
+						data.Quantity,
+						data.Reserved,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to create stock lot",
+			data: model.StockLot{
+				WarehouseID: uuid.New(),
+				ProductID:   uuid.New(),
+				LotNumber:   "LOT-002",
+				Quantity:    10,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.StockLot) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "stock_lots" ("warehouse_id","product_id","lot_number","expiry_date","quantity","reserved","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.data)
+
+			repo := NewStockLotRepository(mockDb.Db)
+
+			err := repo.CreateStockLot(context.Background(), &tt.data)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetStockLots(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetStockLotsReq)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	expiresBefore := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		req     payload.GetStockLotsReq
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get stock lots",
+			req: payload.GetStockLotsReq{
+				WarehouseIDIN: []string{uuid.New().String()},
+				ProductIDIN:   []string{uuid.New().String()},
+				LotNumberIN:   []string{"LOT-001"},
+				InStockOnly:   true,
+				ExpiresBefore: &expiresBefore,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStockLotsReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_lots" WHERE warehouse_id IN ($1) AND product_id IN ($2) AND lot_number IN ($3) AND quantity > 0 AND expiry_date < $4 ORDER BY expiry_date ASC NULLS LAST, lot_number`,
+						),
+					).WithArgs(req.WarehouseIDIN[0], req.ProductIDIN[0], req.LotNumberIN[0], req.ExpiresBefore).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "warehouse_id", "product_id", "lot_number", "expiry_date", "quantity", "reserved"}).
+							AddRow(uuid.New(), req.WarehouseIDIN[0], req.ProductIDIN[0], req.LotNumberIN[0], time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), 10, 0),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get stock lots",
+			req:  payload.GetStockLotsReq{},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStockLotsReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_lots" ORDER BY expiry_date ASC NULLS LAST, lot_number`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.req)
+
+			repo := NewStockLotRepository(mockDb.Db)
+
+			lots, err := repo.GetStockLots(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, lots, 1)
+		})
+	}
+}
+
+func TestAddLotQtyAndReserveQty(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, lotID string, quantity int, reserved int)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name     string
+		lotID    string
+		quantity int
+		reserved int
+		sqlMock  sqlMock
+		wantErr  bool
+	}{
+		{
+			name:     "success - add lot quantity and reserved quantity",
+			lotID:    uuid.New().String(),
+			quantity: -2,
+			reserved: -2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, lotID string, quantity int, reserved int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "stock_lots" SET "quantity"=quantity + $1,"reserved"=reserved + $2,"updated_at"=$3 WHERE id = $4`,
+						),
+					).WithArgs(quantity, reserved, sqlmock.AnyArg(), lotID).WillReturnResult(
+						sqlmock.NewResult(1, 1),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "error - failed to update lot",
+			lotID:    uuid.New().String(),
+			reserved: 1,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, lotID string, quantity int, reserved int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "stock_lots" SET "quantity"=quantity + $1,"reserved"=reserved + $2,"updated_at"=$3 WHERE id = $4`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.lotID, tt.quantity, tt.reserved)
+
+			repo := NewStockLotRepository(mockDb.Db)
+
+			err := repo.AddLotQtyAndReserveQty(context.Background(), tt.lotID, tt.quantity, tt.reserved)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAddLotReservationQty(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, lotID string, orderRef string, quantity int)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name     string
+		lotID    string
+		orderRef string
+		quantity int
+		sqlMock  sqlMock
+		wantErr  bool
+	}{
+		{
+			name:     "success - reserve lot for order",
+			lotID:    uuid.New().String(),
+			orderRef: "order-1",
+			quantity: 2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, lotID string, orderRef string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`INSERT INTO "stock_lot_reservations" ("stock_lot_id","order_ref","quantity","created_at","updated_at") VALUES ($1,$2,$3,$4,$5) ON CONFLICT ("stock_lot_id","order_ref") DO UPDATE SET "quantity"=stock_lot_reservations.quantity + excluded.quantity,"updated_at"=excluded.updated_at`,
+						),
+					).WithArgs(lotID, orderRef, quantity, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(
+						sqlmock.NewResult(1, 1),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "success - release lot of order",
+			lotID:    uuid.New().String(),
+			orderRef: "order-1",
+			quantity: -2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, lotID string, orderRef string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`INSERT INTO "stock_lot_reservations" ("stock_lot_id","order_ref","quantity","created_at","updated_at") VALUES ($1,$2,$3,$4,$5) ON CONFLICT ("stock_lot_id","order_ref") DO UPDATE SET "quantity"=stock_lot_reservations.quantity + excluded.quantity,"updated_at"=excluded.updated_at`,
+						),
+					).WithArgs(lotID, orderRef, quantity, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(
+						sqlmock.NewResult(1, 1),
+					)
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`DELETE FROM "stock_lot_reservations" WHERE stock_lot_id = $1 AND order_ref = $2 AND quantity = 0`,
+						),
+					).WithArgs(lotID, orderRef).WillReturnResult(
+						sqlmock.NewResult(0, 1),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "error - invalid lot ID",
+			lotID:    "invalid",
+			orderRef: "order-1",
+			quantity: 2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, lotID string, orderRef string, quantity int) {},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "error - failed to update lot reservation",
+			lotID:    uuid.New().String(),
+			orderRef: "order-1",
+			quantity: 2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, lotID string, orderRef string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`INSERT INTO "stock_lot_reservations"`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.lotID, tt.orderRef, tt.quantity)
+
+			repo := NewStockLotRepository(mockDb.Db)
+
+			err := repo.AddLotReservationQty(context.Background(), tt.lotID, tt.orderRef, tt.quantity)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMoveStockLots(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		sqlMock sqlMock
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "success - expired quantity moved is reported",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`WITH taken AS ( UPDATE stock_lots SET quantity = stock_lots.quantity - t.qty`),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"coalesce"}).AddRow(2),
+					)
+				},
+			},
+			want: 2,
+		},
+		{
+			name: "error - failed to move lots",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`WITH taken AS (`),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock)
+
+			repo := NewStockLotRepository(mockDb.Db)
+
+			expired, err := repo.MoveStockLots(context.Background(), uuid.New().String(), uuid.New().String(), uuid.New().String(), 5)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, expired)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDispatchStockLots(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		sqlMock sqlMock
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "success",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`INSERT INTO stock_transfer_lots (stock_transfer_id, lot_number, expiry_date, quantity)`),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"coalesce"}).AddRow(0),
+					)
+				},
+			},
+		},
+		{
+			name: "error - failed to dispatch lots",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`WITH taken AS (`),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock)
+
+			repo := NewStockLotRepository(mockDb.Db)
+
+			expired, err := repo.DispatchStockLots(context.Background(), uuid.New().String(), uuid.New().String(), uuid.New().String(), 5)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, expired)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReceiveStockLots(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, stockTransferID, productID, toWarehouseID string, quantity int)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, stockTransferID, productID, toWarehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`WITH received AS (`),
+					).WithArgs(
+						quantity, stockTransferID, quantity, stockTransferID, toWarehouseID, productID,
+					).WillReturnResult(
+						sqlmock.NewResult(0, 2),
+					)
+				},
+			},
+		},
+		{
+			name: "error - failed to receive lots",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, stockTransferID, productID, toWarehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`WITH received AS (`),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stockTransferID, productID, toWarehouseID := uuid.New().String(), uuid.New().String(), uuid.New().String()
+
+			tt.sqlMock.Setup(mockDb.Mock, stockTransferID, productID, toWarehouseID, 5)
+
+			repo := NewStockLotRepository(mockDb.Db)
+
+			err := repo.ReceiveStockLots(context.Background(), stockTransferID, productID, toWarehouseID, 5)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
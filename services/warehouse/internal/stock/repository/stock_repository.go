@@ -21,11 +21,56 @@ type StockRepository interface {
 	CreateStock(ctx context.Context, stock *model.WarehouseStock) error
 	CreateStockTransfer(ctx context.Context, transferStock *model.StockTransfer) error
 	GetStocks(ctx context.Context, req payload.GetStocksReq) ([]model.WarehouseStock, error)
+	GetWarehouseStocks(ctx context.Context, warehouseID string) ([]model.WarehouseStock, error)
 	UpdateStock(ctx context.Context, stock *model.WarehouseStock) error
 	GetAvailableStocksByProduct(ctx context.Context, req payload.GetStockAvailablesByProductReq) ([]model.GetStockAvailablesByProduct, error)
 	AddStockQtyAndReserveQty(ctx context.Context, productID string, warehouseID string, quantity int, reserved int) error
+	ReserveStockQty(ctx context.Context, productID string, warehouseID string, quantity int) (bool, error)
+	UpdateStockThreshold(ctx context.Context, productID string, warehouseID string, threshold *int) error
+	IncreaseStockQty(ctx context.Context, productID string, warehouseID string, quantity int) error
+	DecreaseStockQty(ctx context.Context, productID string, warehouseID string, quantity int) error
+	UpsertStocks(ctx context.Context, stocks []model.WarehouseStock) error
 }
 
+// trimStockLotsSQL takes what the stocks no longer have available off the
+// unreserved quantity of their lots, first expiry first, so the lots never
+// hold more than the stocks they are part of.
+const trimStockLotsSQL = `UPDATE stock_lots SET quantity = stock_lots.quantity - LEAST(t.free, t.excess - t.preceding_free), updated_at = CURRENT_TIMESTAMP
+FROM (
+	SELECT l.id, l.quantity - l.reserved AS free, e.excess,
+		COALESCE(SUM(l.quantity - l.reserved) OVER (PARTITION BY l.warehouse_id, l.product_id ORDER BY l.expiry_date ASC NULLS LAST, l.lot_number ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS preceding_free
+	FROM stock_lots l
+	JOIN (
+		SELECT ws.warehouse_id, ws.product_id, SUM(sl.quantity - sl.reserved) - (ws.quantity - ws.reserved) AS excess
+		FROM warehouse_stocks ws
+		JOIN stock_lots sl ON sl.warehouse_id = ws.warehouse_id AND sl.product_id = ws.product_id
+		WHERE ws.warehouse_id IN ? AND ws.product_id IN ?
+		GROUP BY ws.warehouse_id, ws.product_id, ws.quantity, ws.reserved
+	) e ON e.warehouse_id = l.warehouse_id AND e.product_id = l.product_id
+	WHERE e.excess > 0
+) t
+WHERE stock_lots.id = t.id AND t.free > 0 AND t.preceding_free < t.excess`
+
+// trimBinStocksSQL takes what the stocks no longer have on hand off their
+// bins, emptying the bins last in picking order first. Bins hold reserved
+// units too, so only quantity beyond the stock's whole quantity is excess.
+const trimBinStocksSQL = `UPDATE bin_stocks SET quantity = bin_stocks.quantity - LEAST(t.quantity, t.excess - t.preceding_qty), updated_at = CURRENT_TIMESTAMP
+FROM (
+	SELECT b.id, b.quantity, e.excess,
+		COALESCE(SUM(b.quantity) OVER (PARTITION BY b.warehouse_id, b.product_id ORDER BY wb.zone DESC, wb.code DESC ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS preceding_qty
+	FROM bin_stocks b
+	JOIN warehouse_bins wb ON wb.id = b.bin_id
+	JOIN (
+		SELECT ws.warehouse_id, ws.product_id, SUM(bs.quantity) - ws.quantity AS excess
+		FROM warehouse_stocks ws
+		JOIN bin_stocks bs ON bs.warehouse_id = ws.warehouse_id AND bs.product_id = ws.product_id
+		WHERE ws.warehouse_id IN ? AND ws.product_id IN ?
+		GROUP BY ws.warehouse_id, ws.product_id, ws.quantity
+	) e ON e.warehouse_id = b.warehouse_id AND e.product_id = b.product_id
+	WHERE e.excess > 0
+) t
+WHERE bin_stocks.id = t.id AND t.quantity > 0 AND t.preceding_qty < t.excess`
+
 type stockRepository struct {
 	db *gorm.DB
 }
@@ -91,14 +136,32 @@ func (r *stockRepository) GetStocks(ctx context.Context, req payload.GetStocksRe
 	stmt = stmt.Joins("JOIN warehouses w ON w.id = warehouse_id").
 		Where("w.status = ?", constant.WarehouseStatusActive)
 
+	// a fixed order makes concurrent locking reads take the row locks in the
+	// same order
 	var stocks []model.WarehouseStock
-	if err := stmt.Find(&stocks).Error; err != nil {
+	if err := stmt.Order("warehouse_id, product_id").Find(&stocks).Error; err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get stocks")
 	}
 	return stocks, nil
 }
 
+// GetWarehouseStocks returns every stock held by the warehouse whatever its
+// status, unlike GetStocks which only sees active warehouses.
+func (r *stockRepository) GetWarehouseStocks(ctx context.Context, warehouseID string) ([]model.WarehouseStock, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockRepository.GetWarehouseStocks")
+	defer span.End()
+
+	var stocks []model.WarehouseStock
+	if err := r.db.WithContext(ctx).Where("warehouse_id = ?", warehouseID).Order("product_id").Find(&stocks).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get warehouse stocks")
+	}
+	return stocks, nil
+}
+
+// UpdateStock saves the stock, its lots and bins are trimmed when it has less
+// than they hold.
 func (r *stockRepository) UpdateStock(ctx context.Context, stock *model.WarehouseStock) error {
 	ctx, span := observ.GetTracer().Start(ctx, "stockRepository.UpdateStock")
 	defer span.End()
@@ -107,25 +170,40 @@ func (r *stockRepository) UpdateStock(ctx context.Context, stock *model.Warehous
 		span.SetStatus(codes.Error, err.Error())
 		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update stock")
 	}
+
+	if err := r.trimStock(ctx, []string{stock.WarehouseID.String()}, []string{stock.ProductID.String()}); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
 	return nil
 }
 
+// GetAvailableStocksByProduct sums the available stock of each product in the
+// active warehouses. The product_available_stocks view computes the same for
+// product listings, a change here goes into the view too.
 func (r *stockRepository) GetAvailableStocksByProduct(ctx context.Context, req payload.GetStockAvailablesByProductReq) ([]model.GetStockAvailablesByProduct, error) {
 	ctx, span := observ.GetTracer().Start(ctx, "stockRepository.GetAvailableStocksByProduct")
 	defer span.End()
 
 	stmt := r.db.WithContext(ctx)
 
+	// unreserved quantity of expired lots cannot be reserved
+	expiredLots := r.db.Table("stock_lots").
+		Select("warehouse_id", "product_id", "sum(quantity - reserved) as expired").
+		Where("expiry_date < CURRENT_DATE").
+		Group("warehouse_id, product_id")
+
 	stmt = stmt.
 		Table("warehouse_stocks ws").
 		Joins("JOIN warehouses w ON ws.warehouse_id = w.id").
+		Joins("LEFT JOIN (?) el ON el.warehouse_id = ws.warehouse_id AND el.product_id = ws.product_id", expiredLots).
 		Where("w.status = ?", constant.WarehouseStatusActive)
 
-	stmt = stmt.Select("product_id", "sum(quantity) - sum(reserved) as available_stock").
-		Group("product_id")
+	stmt = stmt.Select("ws.product_id", "sum(ws.quantity) - sum(ws.reserved) - coalesce(sum(el.expired), 0) as available_stock").
+		Group("ws.product_id")
 
 	if len(req.ProductIDIN) > 0 {
-		stmt = stmt.Where("product_id IN ?", req.ProductIDIN)
+		stmt = stmt.Where("ws.product_id IN ?", req.ProductIDIN)
 	}
 
 	var stocks []model.GetStockAvailablesByProduct
@@ -151,3 +229,131 @@ func (r *stockRepository) AddStockQtyAndReserveQty(ctx context.Context, productI
 	}
 	return nil
 }
+
+// ReserveStockQty reserves the quantity in a single conditional update, only
+// when that much of the stock is still unreserved. It reports whether the stock
+// was reserved.
+func (r *stockRepository) ReserveStockQty(ctx context.Context, productID string, warehouseID string, quantity int) (bool, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockRepository.ReserveStockQty")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(&model.WarehouseStock{}).
+		Where("warehouse_id = ? AND product_id = ? AND reserved + ? <= quantity", warehouseID, productID, quantity).
+		Update("reserved", gorm.Expr("reserved + ?", quantity))
+	if err := result.Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return false, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to reserve stock quantity")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *stockRepository) UpdateStockThreshold(ctx context.Context, productID string, warehouseID string, threshold *int) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockRepository.UpdateStockThreshold")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(&model.WarehouseStock{}).
+		Where("warehouse_id = ? AND product_id = ?", warehouseID, productID).
+		Update("reorder_threshold", threshold)
+	if err := result.Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update stock threshold")
+	}
+	if result.RowsAffected == 0 {
+		return apperr.NewWithCode(apperr.CodeHTTPNotFound, "stock not found")
+	}
+	return nil
+}
+
+// IncreaseStockQty adds the quantity to the stock of the product in the warehouse,
+// creating the stock when the warehouse does not hold the product yet.
+func (r *stockRepository) IncreaseStockQty(ctx context.Context, productID string, warehouseID string, quantity int) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockRepository.IncreaseStockQty")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Model(&model.WarehouseStock{}).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "warehouse_id"}, {Name: "product_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"quantity":   gorm.Expr("warehouse_stocks.quantity + excluded.quantity"),
+			"updated_at": gorm.Expr("excluded.updated_at"),
+		}),
+	}).Create(map[string]any{
+		"warehouse_id": warehouseID,
+		"product_id":   productID,
+		"quantity":     quantity,
+		"reserved":     0,
+	}).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to increase stock quantity")
+	}
+	return nil
+}
+
+// DecreaseStockQty removes the quantity from the stock of the product in the
+// warehouse. It refuses to drop the quantity below what is already reserved,
+// and takes the quantity off the unreserved lots and the bins of the stock.
+func (r *stockRepository) DecreaseStockQty(ctx context.Context, productID string, warehouseID string, quantity int) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockRepository.DecreaseStockQty")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(&model.WarehouseStock{}).
+		Where("warehouse_id = ? AND product_id = ? AND quantity - ? >= reserved", warehouseID, productID, quantity).
+		Update("quantity", gorm.Expr("quantity - ?", quantity))
+	if err := result.Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to decrease stock quantity")
+	}
+	if result.RowsAffected == 0 {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "stock quantity cannot drop below reserved quantity")
+	}
+
+	if err := r.trimStock(ctx, []string{warehouseID}, []string{productID}); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// UpsertStocks creates the stocks, or overwrites the quantity of the ones that
+// already exist for the warehouse and product. Lots and bins of the stocks are
+// trimmed to the new quantity.
+func (r *stockRepository) UpsertStocks(ctx context.Context, stocks []model.WarehouseStock) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockRepository.UpsertStocks")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "warehouse_id"}, {Name: "product_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"quantity":   gorm.Expr("excluded.quantity"),
+			"updated_at": gorm.Expr("excluded.updated_at"),
+		}),
+	}).Create(&stocks).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to upsert stocks")
+	}
+
+	var warehouseIDs []string
+	var productIDs []string
+	for _, stock := range stocks {
+		warehouseIDs = append(warehouseIDs, stock.WarehouseID.String())
+		productIDs = append(productIDs, stock.ProductID.String())
+	}
+	if err := r.trimStock(ctx, warehouseIDs, productIDs); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
+
+// trimStock fits the lots and bins of the stocks back into what the stocks
+// have once quantity was taken off them. Stocks of other warehouse and product
+// pairs in the lists are left as they are, their lots and bins already fit.
+func (r *stockRepository) trimStock(ctx context.Context, warehouseIDs []string, productIDs []string) error {
+	if err := r.db.WithContext(ctx).Exec(trimStockLotsSQL, warehouseIDs, productIDs).Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to trim stock lots")
+	}
+
+	if err := r.db.WithContext(ctx).Exec(trimBinStocksSQL, warehouseIDs, productIDs).Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to trim bin stocks")
+	}
+	return nil
+}
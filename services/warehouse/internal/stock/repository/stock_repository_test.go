@@ -43,13 +43,14 @@ func TestCreateStock(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, data model.WarehouseStock) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`INSERT INTO "warehouse_stocks" ("warehouse_id","product_id","quantity","reserved","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
+							`INSERT INTO "warehouse_stocks" ("warehouse_id","product_id","quantity","reserved","reorder_threshold","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`,
 						),
 					).WithArgs(
 						data.WarehouseID,
 						data.ProductID,
 						data.Quantity,
 						data.Reserved,
+						data.ReorderThreshold,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
 					).WillReturnRows(
@@ -71,13 +72,14 @@ func TestCreateStock(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, data model.WarehouseStock) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`INSERT INTO "warehouse_stocks" ("warehouse_id","product_id","quantity","reserved","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
+							`INSERT INTO "warehouse_stocks" ("warehouse_id","product_id","quantity","reserved","reorder_threshold","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`,
 						),
 					).WithArgs(
 						data.WarehouseID,
 						data.ProductID,
 						data.Quantity,
 						data.Reserved,
+						data.ReorderThreshold,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
 					).WillReturnError(
@@ -131,18 +133,26 @@ func TestCreateStockTransfer(t *testing.T) {
 				FromWarehouseID: uuid.New(),
 				ToWarehouseID:   uuid.New(),
 				Quantity:        50,
+				Status:          constant.StockTransferStatusReceived,
 			},
 			sqlMock: sqlMock{
 				Setup: func(mockDB sqlmock.Sqlmock, data model.StockTransfer) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`INSERT INTO "stock_transfers" ("from_warehouse_id","to_warehouse_id","product_id","quantity","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
+							`INSERT INTO "stock_transfers" ("from_warehouse_id","to_warehouse_id","product_id","quantity","status","received_quantity","lost_quantity","notes","dispatched_at","in_transit_at","received_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13) RETURNING "id"`,
 						),
 					).WithArgs(
 						data.FromWarehouseID,
 						data.ToWarehouseID,
 						data.ProductID,
 						data.Quantity,
+						data.Status,
+						data.ReceivedQuantity,
+						data.LostQuantity,
+						data.Notes,
+						data.DispatchedAt,
+						data.InTransitAt,
+						data.ReceivedAt,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
 					).WillReturnRows(
@@ -159,18 +169,26 @@ func TestCreateStockTransfer(t *testing.T) {
 				FromWarehouseID: uuid.New(),
 				ToWarehouseID:   uuid.New(),
 				Quantity:        50,
+				Status:          constant.StockTransferStatusReceived,
 			},
 			sqlMock: sqlMock{
 				Setup: func(mockDB sqlmock.Sqlmock, data model.StockTransfer) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`INSERT INTO "stock_transfers" ("from_warehouse_id","to_warehouse_id","product_id","quantity","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
+							`INSERT INTO "stock_transfers" ("from_warehouse_id","to_warehouse_id","product_id","quantity","status","received_quantity","lost_quantity","notes","dispatched_at","in_transit_at","received_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13) RETURNING "id"`,
 						),
 					).WithArgs(
 						data.FromWarehouseID,
 						data.ToWarehouseID,
 						data.ProductID,
 						data.Quantity,
+						data.Status,
+						data.ReceivedQuantity,
+						data.LostQuantity,
+						data.Notes,
+						data.DispatchedAt,
+						data.InTransitAt,
+						data.ReceivedAt,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
 					).WillReturnError(
@@ -227,7 +245,7 @@ func TestGetStocks(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStocksReq) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`SELECT "warehouse_stocks"."id","warehouse_stocks"."warehouse_id","warehouse_stocks"."product_id","warehouse_stocks"."quantity","warehouse_stocks"."reserved","warehouse_stocks"."created_at","warehouse_stocks"."updated_at" FROM "warehouse_stocks" JOIN warehouses w ON w.id = warehouse_id WHERE warehouse_id IN ($1) AND product_id IN ($2) AND w.status = $3`,
+							`SELECT "warehouse_stocks"."id","warehouse_stocks"."warehouse_id","warehouse_stocks"."product_id","warehouse_stocks"."quantity","warehouse_stocks"."reserved","warehouse_stocks"."reorder_threshold","warehouse_stocks"."created_at","warehouse_stocks"."updated_at" FROM "warehouse_stocks" JOIN warehouses w ON w.id = warehouse_id WHERE warehouse_id IN ($1) AND product_id IN ($2) AND w.status = $3 ORDER BY warehouse_id, product_id`,
 						),
 					).WithArgs(req.WarehouseIDIN[0], req.ProductIDIN[0], constant.WarehouseStatusActive).WillReturnRows(
 						sqlmock.NewRows([]string{"id", "warehouse_id", "product_id", "quantity", "reserved", "created_at", "updated_at"}).
@@ -247,7 +265,7 @@ func TestGetStocks(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStocksReq) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`SELECT "warehouse_stocks"."id","warehouse_stocks"."warehouse_id","warehouse_stocks"."product_id","warehouse_stocks"."quantity","warehouse_stocks"."reserved","warehouse_stocks"."created_at","warehouse_stocks"."updated_at" FROM "warehouse_stocks" JOIN warehouses w ON w.id = warehouse_id WHERE warehouse_id IN ($1) AND product_id IN ($2) AND w.status = $3`,
+							`SELECT "warehouse_stocks"."id","warehouse_stocks"."warehouse_id","warehouse_stocks"."product_id","warehouse_stocks"."quantity","warehouse_stocks"."reserved","warehouse_stocks"."reorder_threshold","warehouse_stocks"."created_at","warehouse_stocks"."updated_at" FROM "warehouse_stocks" JOIN warehouses w ON w.id = warehouse_id WHERE warehouse_id IN ($1) AND product_id IN ($2) AND w.status = $3 ORDER BY warehouse_id, product_id`,
 						),
 					).WithArgs(req.WarehouseIDIN[0], req.ProductIDIN[0], constant.WarehouseStatusActive).WillReturnError(
 						sqlmock.ErrCancelled,
@@ -278,6 +296,77 @@ func TestGetStocks(t *testing.T) {
 	}
 }
 
+func TestGetWarehouseStocks(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, warehouseID string)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name        string
+		warehouseID string
+		sqlMock     sqlMock
+		wantErr     bool
+	}{
+		{
+			name:        "success - get warehouse stocks",
+			warehouseID: uuid.New().String(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, warehouseID string) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "warehouse_stocks" WHERE warehouse_id = $1 ORDER BY product_id`,
+						),
+					).WithArgs(warehouseID).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "warehouse_id", "product_id", "quantity", "reserved", "created_at", "updated_at"}).
+							AddRow(uuid.New(), warehouseID, uuid.New(), 100, 10, time.Now(), time.Now()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "error - failed to get warehouse stocks",
+			warehouseID: uuid.New().String(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, warehouseID string) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "warehouse_stocks" WHERE warehouse_id = $1 ORDER BY product_id`,
+						),
+					).WithArgs(warehouseID).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.warehouseID)
+
+			repo := NewStockRepository(mockDb.Db)
+
+			stocks, err := repo.GetWarehouseStocks(context.Background(), tt.warehouseID)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, stocks, 1)
+		})
+	}
+}
+
 func TestUpdateStock(t *testing.T) {
 	dmockDb, err := pkg.SetupMockDB()
 
@@ -308,19 +397,30 @@ func TestUpdateStock(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, stock model.WarehouseStock) {
 					mockDB.ExpectExec(
 						regexp.QuoteMeta(
-							`UPDATE "warehouse_stocks" SET "warehouse_id"=$1,"product_id"=$2,"quantity"=$3,"reserved"=$4,"created_at"=$5,"updated_at"=$6 WHERE "id" = $7`,
+							`UPDATE "warehouse_stocks" SET "warehouse_id"=$1,"product_id"=$2,"quantity"=$3,"reserved"=$4,"reorder_threshold"=$5,"created_at"=$6,"updated_at"=$7 WHERE "id" = $8`,
 						),
 					).WithArgs(
 						stock.WarehouseID,
 						stock.ProductID,
 						stock.Quantity,
 						stock.Reserved,
+						stock.ReorderThreshold,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
 						stock.ID,
 					).WillReturnResult(
 						sqlmock.NewResult(1, 1),
 					)
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`UPDATE stock_lots SET quantity = stock_lots.quantity - LEAST(t.free, t.excess - t.preceding_free)`),
+					).WithArgs(stock.WarehouseID.String(), stock.ProductID.String()).WillReturnResult(
+						sqlmock.NewResult(0, 0),
+					)
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`UPDATE bin_stocks SET quantity = bin_stocks.quantity - LEAST(t.quantity, t.excess - t.preceding_qty)`),
+					).WithArgs(stock.WarehouseID.String(), stock.ProductID.String()).WillReturnResult(
+						sqlmock.NewResult(0, 0),
+					)
 				},
 			},
 			wantErr: false,
@@ -338,13 +438,14 @@ func TestUpdateStock(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, stock model.WarehouseStock) {
 					mockDB.ExpectExec(
 						regexp.QuoteMeta(
-							`UPDATE "warehouse_stocks" SET "warehouse_id"=$1,"product_id"=$2,"quantity"=$3,"reserved"=$4,"created_at"=$5,"updated_at"=$6 WHERE "id" = $7`,
+							`UPDATE "warehouse_stocks" SET "warehouse_id"=$1,"product_id"=$2,"quantity"=$3,"reserved"=$4,"reorder_threshold"=$5,"created_at"=$6,"updated_at"=$7 WHERE "id" = $8`,
 						),
 					).WithArgs(
 						stock.WarehouseID,
 						stock.ProductID,
 						stock.Quantity,
 						stock.Reserved,
+						stock.ReorderThreshold,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
 						stock.ID,
@@ -401,7 +502,7 @@ func TestGetAvailableStocksByProduct(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStockAvailablesByProductReq) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`SELECT "product_id",sum(quantity) - sum(reserved) as available_stock FROM warehouse_stocks ws JOIN warehouses w ON ws.warehouse_id = w.id WHERE w.status = $1 AND product_id IN ($2) GROUP BY "product_id"`,
+							`SELECT ws.product_id,sum(ws.quantity) - sum(ws.reserved) - coalesce(sum(el.expired), 0) as available_stock FROM warehouse_stocks ws JOIN warehouses w ON ws.warehouse_id = w.id LEFT JOIN (SELECT warehouse_id,product_id,sum(quantity - reserved) as expired FROM "stock_lots" WHERE expiry_date < CURRENT_DATE GROUP BY warehouse_id, product_id) el ON el.warehouse_id = ws.warehouse_id AND el.product_id = ws.product_id WHERE w.status = $1 AND ws.product_id IN ($2) GROUP BY "ws"."product_id"`,
 						),
 					).WithArgs(constant.WarehouseStatusActive, req.ProductIDIN[0]).WillReturnRows(
 						sqlmock.NewRows([]string{"product_id", "available_stock"}).
@@ -420,7 +521,7 @@ func TestGetAvailableStocksByProduct(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStockAvailablesByProductReq) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`SELECT "product_id",sum(quantity) - sum(reserved) as available_stock FROM warehouse_stocks ws JOIN warehouses w ON ws.warehouse_id = w.id WHERE w.status = $1 AND product_id IN ($2) GROUP BY "product_id"`,
+							`SELECT ws.product_id,sum(ws.quantity) - sum(ws.reserved) - coalesce(sum(el.expired), 0) as available_stock FROM warehouse_stocks ws JOIN warehouses w ON ws.warehouse_id = w.id LEFT JOIN (SELECT warehouse_id,product_id,sum(quantity - reserved) as expired FROM "stock_lots" WHERE expiry_date < CURRENT_DATE GROUP BY warehouse_id, product_id) el ON el.warehouse_id = ws.warehouse_id AND el.product_id = ws.product_id WHERE w.status = $1 AND ws.product_id IN ($2) GROUP BY "ws"."product_id"`,
 						),
 					).WithArgs(constant.WarehouseStatusActive, req.ProductIDIN[0]).WillReturnError(
 						sqlmock.ErrCancelled,
@@ -528,3 +629,546 @@ func TestAddStockQtyAndReserveQty(t *testing.T) {
 		})
 	}
 }
+
+func TestReserveStockQty(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, productID string, warehouseID string, quantity int)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name        string
+		productID   string
+		warehouseID string
+		quantity    int
+		sqlMock     sqlMock
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "success - stock reserved",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			quantity:    10,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID string, warehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "warehouse_stocks" SET "reserved"=reserved + $1,"updated_at"=$2 WHERE warehouse_id = $3 AND product_id = $4 AND reserved + $5 <= quantity`,
+						),
+					).WithArgs(quantity, sqlmock.AnyArg(), warehouseID, productID, quantity).WillReturnResult(
+						sqlmock.NewResult(0, 1),
+					)
+				},
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name:        "success - not enough unreserved stock",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			quantity:    10,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID string, warehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "warehouse_stocks" SET "reserved"=reserved + $1,"updated_at"=$2 WHERE warehouse_id = $3 AND product_id = $4 AND reserved + $5 <= quantity`,
+						),
+					).WithArgs(quantity, sqlmock.AnyArg(), warehouseID, productID, quantity).WillReturnResult(
+						sqlmock.NewResult(0, 0),
+					)
+				},
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name:        "error - failed to reserve stock quantity",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			quantity:    10,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID string, warehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "warehouse_stocks" SET "reserved"=reserved + $1,"updated_at"=$2 WHERE warehouse_id = $3 AND product_id = $4 AND reserved + $5 <= quantity`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.productID, tt.warehouseID, tt.quantity)
+
+			repo := NewStockRepository(mockDb.Db)
+
+			reserved, err := repo.ReserveStockQty(context.Background(), tt.productID, tt.warehouseID, tt.quantity)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, reserved)
+		})
+	}
+}
+
+func TestUpdateStockThreshold(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, productID, warehouseID string, threshold *int)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	threshold := 10
+	tests := []struct {
+		name        string
+		productID   string
+		warehouseID string
+		threshold   *int
+		sqlMock     sqlMock
+		wantErr     bool
+	}{
+		{
+			name:        "success - update stock threshold",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			threshold:   &threshold,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, warehouseID string, threshold *int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "warehouse_stocks" SET "reorder_threshold"=$1,"updated_at"=$2 WHERE warehouse_id = $3 AND product_id = $4`,
+						),
+					).WithArgs(
+						threshold,
+						sqlmock.AnyArg(),
+						warehouseID,
+						productID,
+					).WillReturnResult(
+						sqlmock.NewResult(0, 1),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "error - stock not found",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			threshold:   nil,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, warehouseID string, threshold *int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "warehouse_stocks" SET "reorder_threshold"=$1,"updated_at"=$2 WHERE warehouse_id = $3 AND product_id = $4`,
+						),
+					).WithArgs(
+						nil,
+						sqlmock.AnyArg(),
+						warehouseID,
+						productID,
+					).WillReturnResult(
+						sqlmock.NewResult(0, 0),
+					)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "error - failed to update stock threshold",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			threshold:   &threshold,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, warehouseID string, threshold *int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "warehouse_stocks" SET "reorder_threshold"=$1,"updated_at"=$2 WHERE warehouse_id = $3 AND product_id = $4`,
+						),
+					).WithArgs(
+						threshold,
+						sqlmock.AnyArg(),
+						warehouseID,
+						productID,
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.productID, tt.warehouseID, tt.threshold)
+
+			repo := NewStockRepository(mockDb.Db)
+
+			err := repo.UpdateStockThreshold(context.Background(), tt.productID, tt.warehouseID, tt.threshold)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestIncreaseStockQty(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, productID, warehouseID string, quantity int)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		productID   string
+		warehouseID string
+		quantity    int
+		sqlMock     sqlMock
+		wantErr     bool
+	}{
+		{
+			name:        "success - increase stock quantity",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			quantity:    10,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, warehouseID string, quantity int) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "warehouse_stocks" ("product_id","quantity","reserved","warehouse_id") VALUES ($1,$2,$3,$4) ON CONFLICT ("warehouse_id","product_id") DO UPDATE SET "quantity"=warehouse_stocks.quantity + excluded.quantity,"updated_at"=excluded.updated_at RETURNING "id"`,
+						),
+					).WithArgs(
+						productID,
+						quantity,
+						0,
+						warehouseID,
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "error - failed to increase stock quantity",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			quantity:    10,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, warehouseID string, quantity int) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "warehouse_stocks" ("product_id","quantity","reserved","warehouse_id") VALUES ($1,$2,$3,$4) ON CONFLICT ("warehouse_id","product_id") DO UPDATE SET "quantity"=warehouse_stocks.quantity + excluded.quantity,"updated_at"=excluded.updated_at RETURNING "id"`,
+						),
+					).WithArgs(
+						productID,
+						quantity,
+						0,
+						warehouseID,
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.productID, tt.warehouseID, tt.quantity)
+
+			repo := NewStockRepository(mockDb.Db)
+
+			err := repo.IncreaseStockQty(context.Background(), tt.productID, tt.warehouseID, tt.quantity)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestDecreaseStockQty(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, productID, warehouseID string, quantity int)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		productID   string
+		warehouseID string
+		quantity    int
+		sqlMock     sqlMock
+		wantErr     bool
+	}{
+		{
+			name:        "success - decrease stock quantity",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			quantity:    2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, warehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "warehouse_stocks" SET "quantity"=quantity - $1,"updated_at"=$2 WHERE warehouse_id = $3 AND product_id = $4 AND quantity - $5 >= reserved`,
+						),
+					).WithArgs(quantity, sqlmock.AnyArg(), warehouseID, productID, quantity).WillReturnResult(
+						sqlmock.NewResult(0, 1),
+					)
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`UPDATE stock_lots SET quantity = stock_lots.quantity - LEAST(t.free, t.excess - t.preceding_free)`),
+					).WithArgs(warehouseID, productID).WillReturnResult(
+						sqlmock.NewResult(0, 1),
+					)
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`UPDATE bin_stocks SET quantity = bin_stocks.quantity - LEAST(t.quantity, t.excess - t.preceding_qty)`),
+					).WithArgs(warehouseID, productID).WillReturnResult(
+						sqlmock.NewResult(0, 0),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "error - failed to trim stock lots",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			quantity:    2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, warehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "warehouse_stocks" SET "quantity"=quantity - $1,"updated_at"=$2 WHERE warehouse_id = $3 AND product_id = $4 AND quantity - $5 >= reserved`,
+						),
+					).WithArgs(quantity, sqlmock.AnyArg(), warehouseID, productID, quantity).WillReturnResult(
+						sqlmock.NewResult(0, 1),
+					)
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`UPDATE stock_lots SET quantity`),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "error - failed to trim bin stocks",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			quantity:    2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, warehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "warehouse_stocks" SET "quantity"=quantity - $1,"updated_at"=$2 WHERE warehouse_id = $3 AND product_id = $4 AND quantity - $5 >= reserved`,
+						),
+					).WithArgs(quantity, sqlmock.AnyArg(), warehouseID, productID, quantity).WillReturnResult(
+						sqlmock.NewResult(0, 1),
+					)
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`UPDATE stock_lots SET quantity`),
+					).WillReturnResult(
+						sqlmock.NewResult(0, 0),
+					)
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`UPDATE bin_stocks SET quantity`),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "error - quantity would drop below reserved",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			quantity:    2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, warehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "warehouse_stocks" SET "quantity"=quantity - $1,"updated_at"=$2 WHERE warehouse_id = $3 AND product_id = $4 AND quantity - $5 >= reserved`,
+						),
+					).WithArgs(quantity, sqlmock.AnyArg(), warehouseID, productID, quantity).WillReturnResult(
+						sqlmock.NewResult(0, 0),
+					)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:        "error - failed to decrease stock quantity",
+			productID:   uuid.New().String(),
+			warehouseID: uuid.New().String(),
+			quantity:    2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, warehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`UPDATE "warehouse_stocks" SET "quantity"=quantity - $1,"updated_at"=$2 WHERE warehouse_id = $3 AND product_id = $4 AND quantity - $5 >= reserved`,
+						),
+					).WithArgs(quantity, sqlmock.AnyArg(), warehouseID, productID, quantity).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.productID, tt.warehouseID, tt.quantity)
+
+			repo := NewStockRepository(mockDb.Db)
+
+			err := repo.DecreaseStockQty(context.Background(), tt.productID, tt.warehouseID, tt.quantity)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestUpsertStocks(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, stocks []model.WarehouseStock)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	stocks := []model.WarehouseStock{
+		{WarehouseID: uuid.New(), ProductID: uuid.New(), Quantity: 10},
+		{WarehouseID: uuid.New(), ProductID: uuid.New(), Quantity: 0},
+	}
+
+	tests := []struct {
+		name    string
+		stocks  []model.WarehouseStock
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name:   "success - upsert stocks",
+			stocks: stocks,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, stocks []model.WarehouseStock) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "warehouse_stocks" ("warehouse_id","product_id","quantity","reserved","reorder_threshold","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7),($8,$9,$10,$11,$12,$13,$14) ON CONFLICT ("warehouse_id","product_id") DO UPDATE SET "quantity"=excluded.quantity,"updated_at"=excluded.updated_at RETURNING "id"`,
+						),
+					).WithArgs(
+						stocks[0].WarehouseID, stocks[0].ProductID, stocks[0].Quantity, 0, nil, sqlmock.AnyArg(), sqlmock.AnyArg(),
+						stocks[1].WarehouseID, stocks[1].ProductID, stocks[1].Quantity, 0, nil, sqlmock.AnyArg(), sqlmock.AnyArg(),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()).AddRow(uuid.New()),
+					)
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`UPDATE stock_lots SET quantity = stock_lots.quantity - LEAST(t.free, t.excess - t.preceding_free)`),
+					).WithArgs(
+						stocks[0].WarehouseID.String(), stocks[1].WarehouseID.String(),
+						stocks[0].ProductID.String(), stocks[1].ProductID.String(),
+					).WillReturnResult(
+						sqlmock.NewResult(0, 0),
+					)
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`UPDATE bin_stocks SET quantity = bin_stocks.quantity - LEAST(t.quantity, t.excess - t.preceding_qty)`),
+					).WithArgs(
+						stocks[0].WarehouseID.String(), stocks[1].WarehouseID.String(),
+						stocks[0].ProductID.String(), stocks[1].ProductID.String(),
+					).WillReturnResult(
+						sqlmock.NewResult(0, 0),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "error - failed to upsert stocks",
+			stocks: stocks,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, stocks []model.WarehouseStock) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "warehouse_stocks"`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.stocks)
+
+			repo := NewStockRepository(mockDb.Db)
+
+			err := repo.UpsertStocks(context.Background(), tt.stocks)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
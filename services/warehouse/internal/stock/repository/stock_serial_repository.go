@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name=StockSerialRepository --case underscore
+type StockSerialRepository interface {
+	WithTX(tx *gorm.DB) StockSerialRepository
+	WithLockForUpdate() StockSerialRepository
+	CreateSerializedProduct(ctx context.Context, product *model.SerializedProduct) error
+	DeleteSerializedProduct(ctx context.Context, productID string) error
+	GetSerializedProducts(ctx context.Context, productIDs []string) ([]model.SerializedProduct, error)
+	GetStockSerials(ctx context.Context, req payload.GetStockSerialsReq) ([]model.StockSerial, error)
+	GetStockSerialHistory(ctx context.Context, serialNumber string) ([]model.StockSerial, error)
+	CreateStockSerials(ctx context.Context, serials []model.StockSerial) error
+	UpdateStockSerial(ctx context.Context, serial *model.StockSerial) error
+	CreateStockSerialEvents(ctx context.Context, events []model.StockSerialEvent) error
+	MoveStockSerials(ctx context.Context, productID string, fromWarehouseID string, toWarehouseID string, quantity int) error
+}
+
+// moveStockSerialsSQL moves the oldest serials in stock to the warehouse and
+// records the transfer on each of them.
+const moveStockSerialsSQL = `WITH moved AS (
+	UPDATE stock_serials SET warehouse_id = @to_warehouse_id, updated_at = CURRENT_TIMESTAMP
+	WHERE id IN (
+		SELECT id FROM stock_serials
+		WHERE product_id = @product_id AND warehouse_id = @from_warehouse_id AND status = @status
+		ORDER BY created_at, serial_number
+		LIMIT @quantity
+		FOR UPDATE
+	)
+	RETURNING id
+)
+INSERT INTO stock_serial_events (stock_serial_id, type, warehouse_id)
+SELECT id, @type, @to_warehouse_id FROM moved`
+
+type stockSerialRepository struct {
+	db *gorm.DB
+}
+
+func NewStockSerialRepository(db *gorm.DB) StockSerialRepository {
+	return &stockSerialRepository{db: db}
+}
+
+func (r *stockSerialRepository) WithTX(tx *gorm.DB) StockSerialRepository {
+	if tx == nil {
+		return r
+	}
+	return &stockSerialRepository{db: tx}
+}
+
+func (r *stockSerialRepository) WithLockForUpdate() StockSerialRepository {
+	return &stockSerialRepository{
+		db: r.db.Clauses(clause.Locking{Strength: "UPDATE"}),
+	}
+}
+
+// CreateSerializedProduct marks the product as serialized, doing nothing when
+// it already is.
+func (r *stockSerialRepository) CreateSerializedProduct(ctx context.Context, product *model.SerializedProduct) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSerialRepository.CreateSerializedProduct")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(product).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create serialized product")
+	}
+	return nil
+}
+
+func (r *stockSerialRepository) DeleteSerializedProduct(ctx context.Context, productID string) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSerialRepository.DeleteSerializedProduct")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Delete(&model.SerializedProduct{}).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to delete serialized product")
+	}
+	return nil
+}
+
+func (r *stockSerialRepository) GetSerializedProducts(ctx context.Context, productIDs []string) ([]model.SerializedProduct, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSerialRepository.GetSerializedProducts")
+	defer span.End()
+
+	var products []model.SerializedProduct
+	if err := r.db.WithContext(ctx).Where("product_id IN ?", productIDs).Find(&products).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get serialized products")
+	}
+	return products, nil
+}
+
+// GetStockSerials returns the serials oldest first.
+func (r *stockSerialRepository) GetStockSerials(ctx context.Context, req payload.GetStockSerialsReq) ([]model.StockSerial, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSerialRepository.GetStockSerials")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx)
+	if len(req.WarehouseIDIN) > 0 {
+		stmt = stmt.Where("warehouse_id IN ?", req.WarehouseIDIN)
+	}
+
+	if len(req.ProductIDIN) > 0 {
+		stmt = stmt.Where("product_id IN ?", req.ProductIDIN)
+	}
+
+	if len(req.SerialNumberIN) > 0 {
+		stmt = stmt.Where("serial_number IN ?", req.SerialNumberIN)
+	}
+
+	if len(req.StatusIN) > 0 {
+		stmt = stmt.Where("status IN ?", req.StatusIN)
+	}
+
+	if req.OrderRef != "" {
+		stmt = stmt.Where("order_ref = ?", req.OrderRef)
+	}
+
+	if req.Limit > 0 {
+		stmt = stmt.Limit(req.Limit)
+	}
+
+	var serials []model.StockSerial
+	if err := stmt.Order("created_at, serial_number").Find(&serials).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get stock serials")
+	}
+	return serials, nil
+}
+
+// GetStockSerialHistory returns every serial with the serial number, with its
+// events oldest first.
+func (r *stockSerialRepository) GetStockSerialHistory(ctx context.Context, serialNumber string) ([]model.StockSerial, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSerialRepository.GetStockSerialHistory")
+	defer span.End()
+
+	var serials []model.StockSerial
+	if err := r.db.WithContext(ctx).
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		Where("serial_number = ?", serialNumber).
+		Order("product_id").
+		Find(&serials).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get stock serial history")
+	}
+	return serials, nil
+}
+
+func (r *stockSerialRepository) CreateStockSerials(ctx context.Context, serials []model.StockSerial) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSerialRepository.CreateStockSerials")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(&serials).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create stock serials")
+	}
+	return nil
+}
+
+func (r *stockSerialRepository) UpdateStockSerial(ctx context.Context, serial *model.StockSerial) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSerialRepository.UpdateStockSerial")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Save(serial).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update stock serial")
+	}
+	return nil
+}
+
+func (r *stockSerialRepository) CreateStockSerialEvents(ctx context.Context, events []model.StockSerialEvent) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSerialRepository.CreateStockSerialEvents")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(&events).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create stock serial events")
+	}
+	return nil
+}
+
+// MoveStockSerials moves the oldest serials in stock of the product along with
+// the quantity moved between the warehouses. It fails when the warehouse has
+// fewer serials in stock than the quantity.
+func (r *stockSerialRepository) MoveStockSerials(ctx context.Context, productID string, fromWarehouseID string, toWarehouseID string, quantity int) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSerialRepository.MoveStockSerials")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Exec(moveStockSerialsSQL, map[string]any{
+		"product_id":        productID,
+		"from_warehouse_id": fromWarehouseID,
+		"to_warehouse_id":   toWarehouseID,
+		"status":            constant.StockSerialStatusInStock,
+		"type":              constant.StockSerialEventTransferred,
+		"quantity":          quantity,
+	})
+	if err := result.Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to move stock serials")
+	}
+	if result.RowsAffected < int64(quantity) {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "not enough serials in stock for product "+productID)
+	}
+	return nil
+}
@@ -0,0 +1,428 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSerializedProduct(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, data model.SerializedProduct)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name string
+		data model.SerializedProduct
+		sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success",
+			data: model.SerializedProduct{
+				ProductID: uuid.New(),
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.SerializedProduct) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`INSERT INTO "serialized_products" ("product_id","created_at") VALUES ($1,$2) ON CONFLICT DO NOTHING`,
+						),
+					).WithArgs(
+						data.ProductID,
+						sqlmock.AnyArg(),
+					).WillReturnResult(
+						sqlmock.NewResult(1, 1),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to create serialized product",
+			data: model.SerializedProduct{
+				ProductID: uuid.New(),
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.SerializedProduct) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`INSERT INTO "serialized_products" ("product_id","created_at") VALUES ($1,$2) ON CONFLICT DO NOTHING`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.data)
+
+			repo := NewStockSerialRepository(mockDb.Db)
+
+			err := repo.CreateSerializedProduct(context.Background(), &tt.data)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetStockSerials(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetStockSerialsReq)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		req     payload.GetStockSerialsReq
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get stock serials",
+			req: payload.GetStockSerialsReq{
+				WarehouseIDIN: []string{uuid.New().String()},
+				ProductIDIN:   []string{uuid.New().String()},
+				StatusIN:      []string{constant.StockSerialStatusInStock},
+				Limit:         2,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStockSerialsReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_serials" WHERE warehouse_id IN ($1) AND product_id IN ($2) AND status IN ($3) ORDER BY created_at, serial_number LIMIT $4`,
+						),
+					).WithArgs(req.WarehouseIDIN[0], req.ProductIDIN[0], req.StatusIN[0], req.Limit).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "product_id", "serial_number", "warehouse_id", "status"}).
+							AddRow(uuid.New(), req.ProductIDIN[0], "SN-001", req.WarehouseIDIN[0], req.StatusIN[0]),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get stock serials",
+			req: payload.GetStockSerialsReq{
+				OrderRef: "order-1",
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStockSerialsReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_serials" WHERE order_ref = $1 ORDER BY created_at, serial_number`,
+						),
+					).WithArgs(req.OrderRef).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.req)
+
+			repo := NewStockSerialRepository(mockDb.Db)
+
+			serials, err := repo.GetStockSerials(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, serials, 1)
+		})
+	}
+}
+
+func TestGetStockSerialHistory(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, serialNumber string)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name         string
+		serialNumber string
+		sqlMock      sqlMock
+		wantErr      bool
+	}{
+		{
+			name:         "success - get stock serial history",
+			serialNumber: "SN-001",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, serialNumber string) {
+					serialID := uuid.New()
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_serials" WHERE serial_number = $1 ORDER BY product_id`,
+						),
+					).WithArgs(serialNumber).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "product_id", "serial_number", "warehouse_id", "status"}).
+							AddRow(serialID, uuid.New(), serialNumber, uuid.New(), constant.StockSerialStatusSold),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_serial_events" WHERE "stock_serial_events"."stock_serial_id" = $1 ORDER BY created_at`,
+						),
+					).WithArgs(serialID).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "stock_serial_id", "type", "warehouse_id"}).
+							AddRow(uuid.New(), serialID, constant.StockSerialEventReceived, uuid.New()).
+							AddRow(uuid.New(), serialID, constant.StockSerialEventSold, uuid.New()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:         "error - failed to get stock serial history",
+			serialNumber: "SN-002",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, serialNumber string) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_serials" WHERE serial_number = $1 ORDER BY product_id`,
+						),
+					).WithArgs(serialNumber).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.serialNumber)
+
+			repo := NewStockSerialRepository(mockDb.Db)
+
+			serials, err := repo.GetStockSerialHistory(context.Background(), tt.serialNumber)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, serials, 1)
+			assert.Len(t, serials[0].Events, 2)
+		})
+	}
+}
+
+func TestCreateStockSerials(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, data []model.StockSerial)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	tests := []struct {
+		name string
+		data []model.StockSerial
+		sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success",
+			data: []model.StockSerial{
+				{ProductID: productID, SerialNumber: "SN-001", WarehouseID: warehouseID, Status: constant.StockSerialStatusInStock},
+				{ProductID: productID, SerialNumber: "SN-002", WarehouseID: warehouseID, Status: constant.StockSerialStatusInStock},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data []model.StockSerial) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "stock_serials" ("product_id","serial_number","warehouse_id","status","order_ref","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7),($8,$9,$10,$11,$12,$13,$14) RETURNING "id"`,
+						),
+					).WithArgs(
+						productID, "SN-001", warehouseID, constant.StockSerialStatusInStock, nil, sqlmock.AnyArg(), sqlmock.AnyArg(),
+						productID, "SN-002", warehouseID, constant.StockSerialStatusInStock, nil, sqlmock.AnyArg(), sqlmock.AnyArg(),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()).AddRow(uuid.New()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to create stock serials",
+			data: []model.StockSerial{
+				{ProductID: productID, SerialNumber: "SN-003", WarehouseID: warehouseID, Status: constant.StockSerialStatusInStock},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data []model.StockSerial) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "stock_serials" ("product_id","serial_number","warehouse_id","status","order_ref","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.data)
+
+			repo := NewStockSerialRepository(mockDb.Db)
+
+			err := repo.CreateStockSerials(context.Background(), tt.data)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMoveStockSerials(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, productID, fromWarehouseID, toWarehouseID string, quantity int)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		productID       string
+		fromWarehouseID string
+		toWarehouseID   string
+		quantity        int
+		sqlMock         sqlMock
+		wantErr         bool
+	}{
+		{
+			name:            "success - move oldest serials",
+			productID:       uuid.New().String(),
+			fromWarehouseID: uuid.New().String(),
+			toWarehouseID:   uuid.New().String(),
+			quantity:        2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, fromWarehouseID, toWarehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`WITH moved AS (`),
+					).WithArgs(
+						toWarehouseID, productID, fromWarehouseID, constant.StockSerialStatusInStock, quantity,
+						constant.StockSerialEventTransferred, toWarehouseID,
+					).WillReturnResult(
+						sqlmock.NewResult(0, 2),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:            "error - not enough serials in stock",
+			productID:       uuid.New().String(),
+			fromWarehouseID: uuid.New().String(),
+			toWarehouseID:   uuid.New().String(),
+			quantity:        2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, fromWarehouseID, toWarehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`WITH moved AS (`),
+					).WillReturnResult(
+						sqlmock.NewResult(0, 1),
+					)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:            "error - failed to move serials",
+			productID:       uuid.New().String(),
+			fromWarehouseID: uuid.New().String(),
+			toWarehouseID:   uuid.New().String(),
+			quantity:        2,
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productID, fromWarehouseID, toWarehouseID string, quantity int) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`WITH moved AS (`),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.productID, tt.fromWarehouseID, tt.toWarehouseID, tt.quantity)
+
+			repo := NewStockSerialRepository(mockDb.Db)
+
+			err := repo.MoveStockSerials(context.Background(), tt.productID, tt.fromWarehouseID, tt.toWarehouseID, tt.quantity)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
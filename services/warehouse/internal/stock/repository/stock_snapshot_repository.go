@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=StockSnapshotRepository --case underscore
+type StockSnapshotRepository interface {
+	WithTX(tx *gorm.DB) StockSnapshotRepository
+	CreateStockSnapshot(ctx context.Context) error
+	GetLatestSnapshotTime(ctx context.Context, at time.Time) (*time.Time, error)
+	GetStockSnapshots(ctx context.Context, takenAt time.Time, req payload.GetStocksReq) ([]model.StockSnapshot, error)
+	GetStockMovements(ctx context.Context, after time.Time, until time.Time, req payload.GetStocksReq) ([]model.StockMovement, error)
+	GetLatestStockMovementID(ctx context.Context) (int64, error)
+	GetMovedProductIDs(ctx context.Context, afterID int64, productIDs []string) ([]string, error)
+}
+
+type stockSnapshotRepository struct {
+	db *gorm.DB
+}
+
+func NewStockSnapshotRepository(db *gorm.DB) StockSnapshotRepository {
+	return &stockSnapshotRepository{db: db}
+}
+
+func (r *stockSnapshotRepository) WithTX(tx *gorm.DB) StockSnapshotRepository {
+	if tx == nil {
+		return r
+	}
+	return &stockSnapshotRepository{db: tx}
+}
+
+// CreateStockSnapshot copies every warehouse stock into a snapshot. It must run
+// inside a transaction: the share lock waits for the stock changes in flight
+// to commit and holds new ones off until the snapshot commits, so every
+// movement stamped before the snapshot is part of it and every later one is
+// replayed on top of it.
+func (r *stockSnapshotRepository) CreateStockSnapshot(ctx context.Context) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSnapshotRepository.CreateStockSnapshot")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Exec(`LOCK TABLE warehouse_stocks IN SHARE MODE`).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to lock warehouse stocks")
+	}
+
+	if err := r.db.WithContext(ctx).Exec(
+		`INSERT INTO stock_snapshots (taken_at, warehouse_id, product_id, quantity, reserved) SELECT statement_timestamp(), warehouse_id, product_id, quantity, reserved FROM warehouse_stocks`,
+	).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create stock snapshot")
+	}
+	return nil
+}
+
+// GetLatestSnapshotTime returns when the last snapshot up to at was taken, nil
+// when there is none.
+func (r *stockSnapshotRepository) GetLatestSnapshotTime(ctx context.Context, at time.Time) (*time.Time, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSnapshotRepository.GetLatestSnapshotTime")
+	defer span.End()
+
+	var takenAt sql.NullTime
+	if err := r.db.WithContext(ctx).Model(&model.StockSnapshot{}).
+		Select("MAX(taken_at)").
+		Where("taken_at <= ?", at).
+		Scan(&takenAt).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get latest stock snapshot")
+	}
+	if !takenAt.Valid {
+		return nil, nil
+	}
+	return &takenAt.Time, nil
+}
+
+func (r *stockSnapshotRepository) GetStockSnapshots(ctx context.Context, takenAt time.Time, req payload.GetStocksReq) ([]model.StockSnapshot, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSnapshotRepository.GetStockSnapshots")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx).Where("taken_at = ?", takenAt)
+	if len(req.WarehouseIDIN) > 0 {
+		stmt = stmt.Where("warehouse_id IN ?", req.WarehouseIDIN)
+	}
+
+	if len(req.ProductIDIN) > 0 {
+		stmt = stmt.Where("product_id IN ?", req.ProductIDIN)
+	}
+
+	var snapshots []model.StockSnapshot
+	if err := stmt.Order("warehouse_id, product_id").Find(&snapshots).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get stock snapshots")
+	}
+	return snapshots, nil
+}
+
+// GetStockMovements returns the movements recorded after the first time up to
+// the second, oldest first.
+func (r *stockSnapshotRepository) GetStockMovements(ctx context.Context, after time.Time, until time.Time, req payload.GetStocksReq) ([]model.StockMovement, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSnapshotRepository.GetStockMovements")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx).Where("created_at > ? AND created_at <= ?", after, until)
+	if len(req.WarehouseIDIN) > 0 {
+		stmt = stmt.Where("warehouse_id IN ?", req.WarehouseIDIN)
+	}
+
+	if len(req.ProductIDIN) > 0 {
+		stmt = stmt.Where("product_id IN ?", req.ProductIDIN)
+	}
+
+	var movements []model.StockMovement
+	if err := stmt.Order("created_at, id").Find(&movements).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get stock movements")
+	}
+	return movements, nil
+}
+
+// GetLatestStockMovementID returns the id of the last recorded movement, 0 when
+// there is none.
+func (r *stockSnapshotRepository) GetLatestStockMovementID(ctx context.Context) (int64, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSnapshotRepository.GetLatestStockMovementID")
+	defer span.End()
+
+	var id int64
+	if err := r.db.WithContext(ctx).Model(&model.StockMovement{}).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&id).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return 0, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get latest stock movement")
+	}
+	return id, nil
+}
+
+// GetMovedProductIDs returns which of the products have movements recorded
+// after the movement id.
+func (r *stockSnapshotRepository) GetMovedProductIDs(ctx context.Context, afterID int64, productIDs []string) ([]string, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockSnapshotRepository.GetMovedProductIDs")
+	defer span.End()
+
+	var moved []string
+	if err := r.db.WithContext(ctx).Model(&model.StockMovement{}).
+		Distinct("product_id").
+		Where("id > ? AND product_id IN ?", afterID, productIDs).
+		Pluck("product_id", &moved).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get moved products")
+	}
+	return moved, nil
+}
@@ -0,0 +1,405 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateStockSnapshot(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - create stock snapshot",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`LOCK TABLE warehouse_stocks IN SHARE MODE`),
+					).WillReturnResult(
+						sqlmock.NewResult(0, 0),
+					)
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`INSERT INTO stock_snapshots (taken_at, warehouse_id, product_id, quantity, reserved) SELECT statement_timestamp(), warehouse_id, product_id, quantity, reserved FROM warehouse_stocks`,
+						),
+					).WillReturnResult(
+						sqlmock.NewResult(0, 3),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to lock warehouse stocks",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`LOCK TABLE warehouse_stocks IN SHARE MODE`),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - failed to create stock snapshot",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock) {
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(`LOCK TABLE warehouse_stocks IN SHARE MODE`),
+					).WillReturnResult(
+						sqlmock.NewResult(0, 0),
+					)
+					mockDB.ExpectExec(
+						regexp.QuoteMeta(
+							`INSERT INTO stock_snapshots (taken_at, warehouse_id, product_id, quantity, reserved) SELECT statement_timestamp(), warehouse_id, product_id, quantity, reserved FROM warehouse_stocks`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock)
+
+			repo := NewStockSnapshotRepository(mockDb.Db)
+
+			err := repo.CreateStockSnapshot(context.Background())
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestGetLatestSnapshotTime(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, at time.Time)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	takenAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		sqlMock sqlMock
+		want    *time.Time
+		wantErr bool
+	}{
+		{
+			name: "success - latest snapshot",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, at time.Time) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT MAX(taken_at) FROM "stock_snapshots" WHERE taken_at <= $1`,
+						),
+					).WithArgs(at).WillReturnRows(
+						sqlmock.NewRows([]string{"max"}).AddRow(takenAt),
+					)
+				},
+			},
+			want:    &takenAt,
+			wantErr: false,
+		},
+		{
+			name: "success - no snapshot",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, at time.Time) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT MAX(taken_at) FROM "stock_snapshots" WHERE taken_at <= $1`,
+						),
+					).WithArgs(at).WillReturnRows(
+						sqlmock.NewRows([]string{"max"}).AddRow(nil),
+					)
+				},
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get latest snapshot",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, at time.Time) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT MAX(taken_at) FROM "stock_snapshots" WHERE taken_at <= $1`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, at)
+
+			repo := NewStockSnapshotRepository(mockDb.Db)
+
+			got, err := repo.GetLatestSnapshotTime(context.Background(), at)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetStockMovements(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetStocksReq)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	after := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		req     payload.GetStocksReq
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get stock movements",
+			req: payload.GetStocksReq{
+				WarehouseIDIN: []string{uuid.New().String()},
+				ProductIDIN:   []string{uuid.New().String()},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStocksReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_movements" WHERE (created_at > $1 AND created_at <= $2) AND warehouse_id IN ($3) AND product_id IN ($4) ORDER BY created_at, id`,
+						),
+					).WithArgs(after, until, req.WarehouseIDIN[0], req.ProductIDIN[0]).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "warehouse_id", "product_id", "quantity_delta", "reserved_delta", "created_at"}).
+							AddRow(1, req.WarehouseIDIN[0], req.ProductIDIN[0], -2, -2, until),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get stock movements",
+			req:  payload.GetStocksReq{},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetStocksReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_movements" WHERE created_at > $1 AND created_at <= $2 ORDER BY created_at, id`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.req)
+
+			repo := NewStockSnapshotRepository(mockDb.Db)
+
+			movements, err := repo.GetStockMovements(context.Background(), after, until, tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, movements, 1)
+		})
+	}
+}
+
+func TestGetLatestStockMovementID(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		sqlMock sqlMock
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "success - latest stock movement",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT COALESCE(MAX(id), 0) FROM "stock_movements"`,
+						),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"coalesce"}).AddRow(42),
+					)
+				},
+			},
+			want:    42,
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get latest stock movement",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT COALESCE(MAX(id), 0) FROM "stock_movements"`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock)
+
+			repo := NewStockSnapshotRepository(mockDb.Db)
+
+			got, err := repo.GetLatestStockMovementID(context.Background())
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetMovedProductIDs(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, productIDs []string)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	productIDs := []string{uuid.New().String(), uuid.New().String()}
+	tests := []struct {
+		name    string
+		sqlMock sqlMock
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "success - moved products",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productIDs []string) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT DISTINCT "product_id" FROM "stock_movements" WHERE id > $1 AND product_id IN ($2,$3)`,
+						),
+					).WithArgs(int64(7), productIDs[0], productIDs[1]).WillReturnRows(
+						sqlmock.NewRows([]string{"product_id"}).AddRow(productIDs[1]),
+					)
+				},
+			},
+			want:    []string{productIDs[1]},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get moved products",
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, productIDs []string) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT DISTINCT "product_id" FROM "stock_movements" WHERE id > $1 AND product_id IN ($2,$3)`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, productIDs)
+
+			repo := NewStockSnapshotRepository(mockDb.Db)
+
+			got, err := repo.GetMovedProductIDs(context.Background(), 7, productIDs)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/allocation"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// SetBackorderProduct lets the product take orders beyond its stock, or stops
+// it. Backorders already waiting are still allocated.
+func (s *stockService) SetBackorderProduct(ctx context.Context, req payload.SetBackorderProductReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.SetBackorderProduct")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if req.Mode == "" {
+		return s.backorderRepo.DeleteBackorderProduct(ctx, req.ProductID.String())
+	}
+
+	return s.backorderRepo.UpsertBackorderProduct(ctx, &model.BackorderProduct{
+		ProductID: req.ProductID,
+		Mode:      req.Mode,
+	})
+}
+
+func (s *stockService) GetBackorders(ctx context.Context, req payload.GetBackordersReq) (result []model.Backorder, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.GetBackorders")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	backorders, err := s.backorderRepo.GetBackorders(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return backorders, nil
+}
+
+// createBackorder queues a reservation the stock cannot cover when its product
+// takes backorders or pre-orders.
+func (s *stockService) createBackorder(ctx context.Context, tx *gorm.DB, orderRef string, stock payload.ReserveStocksData, shopID string) (model.Backorder, error) {
+	products, err := s.backorderRepo.WithTX(tx).GetBackorderProducts(ctx, []string{stock.ProductID})
+	if err != nil {
+		return model.Backorder{}, err
+	}
+	if len(products) == 0 {
+		return model.Backorder{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "insufficient stock for product "+stock.ProductID)
+	}
+	if orderRef == "" {
+		return model.Backorder{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "order_ref is required to backorder product "+stock.ProductID)
+	}
+
+	backorder := model.Backorder{
+		OrderRef:  orderRef,
+		ProductID: products[0].ProductID,
+		Mode:      products[0].Mode,
+		Quantity:  stock.Quantity,
+		Status:    constant.BackorderStatusWaiting,
+	}
+	if shopID != "" {
+		id := uuid.MustParse(shopID)
+		backorder.ShopID = &id
+	}
+
+	if err := s.backorderRepo.WithTX(tx).CreateBackorder(ctx, &backorder); err != nil {
+		return model.Backorder{}, err
+	}
+	return backorder, nil
+}
+
+// CancelBackorders cancels the backorders of an order that is still waiting
+// for them and releases the stock allocated to them, which goes to the next
+// waiting backorders.
+func (s *stockService) CancelBackorders(ctx context.Context, req payload.CancelBackordersReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.CancelBackorders")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	backorders, err := s.backorderRepo.WithTX(tx).WithLockForUpdate().GetBackorders(ctx, payload.GetBackordersReq{
+		OrderRefIN: []string{req.OrderRef},
+		StatusIN:   []string{constant.BackorderStatusWaiting, constant.BackorderStatusAllocated},
+	})
+	if err != nil {
+		return err
+	}
+	if len(backorders) == 0 {
+		return nil
+	}
+
+	var productIDs []string
+	var warehouseIDs []string
+	for _, backorder := range backorders {
+		productIDs = append(productIDs, backorder.ProductID.String())
+		for _, allocation := range backorder.Allocations {
+			warehouseIDs = append(warehouseIDs, allocation.WarehouseID.String())
+		}
+	}
+
+	lots, err := s.stockLotRepo.WithTX(tx).WithLockForUpdate().GetStockLots(ctx, payload.GetStockLotsReq{
+		WarehouseIDIN: warehouseIDs,
+		ProductIDIN:   productIDs,
+	})
+	if err != nil {
+		return err
+	}
+	stockLots := groupStockLots(lots)
+
+	lotReservations, err := s.stockLotReservations(ctx, tx, lots, req.OrderRef)
+	if err != nil {
+		return err
+	}
+
+	for i := range backorders {
+		backorder := &backorders[i]
+		for _, allocation := range backorder.Allocations {
+			key := stockKey{warehouseID: allocation.WarehouseID.String(), productID: backorder.ProductID.String()}
+			err = s.stockRepo.WithTX(tx).AddStockQtyAndReserveQty(ctx, key.productID, key.warehouseID, 0, -allocation.Quantity)
+			if err != nil {
+				return err
+			}
+
+			for _, lot := range releaseStockLots(stockLots[key], lotReservations, req.OrderRef, allocation.Quantity) {
+				err = s.stockLotRepo.WithTX(tx).AddLotQtyAndReserveQty(ctx, lot.StockLotID.String(), 0, -lot.Quantity)
+				if err != nil {
+					return err
+				}
+
+				err = s.stockLotRepo.WithTX(tx).AddLotReservationQty(ctx, lot.StockLotID.String(), lot.OrderRef, -lot.Quantity)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		backorder.Status = constant.BackorderStatusCancelled
+		if err := s.backorderRepo.WithTX(tx).UpdateBackorder(ctx, backorder); err != nil {
+			return err
+		}
+	}
+
+	if err := s.allocateBackorders(ctx, tx, productIDs); err != nil {
+		return err
+	}
+
+	alerts, err := s.evaluateStockAlerts(ctx, tx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.publishStockAlerts(ctx, alerts)
+	s.publishStockAvailability(ctx, productIDs)
+
+	return nil
+}
+
+// allocateBackorders reserves the available stock of the products for their
+// waiting backorders, oldest first. A backorder takes what it can, so stock
+// never stays available while an older backorder waits for it. Like
+// ReserveStocks it plans from a plain read and reserves with conditional
+// updates, so it never waits on a concurrent reservation: a stock or lot taken
+// in between is left out of the allocation, and a backorder changed in between
+// is skipped. Neither fails the change that runs the allocation.
+func (s *stockService) allocateBackorders(ctx context.Context, tx *gorm.DB, productIDs []string) error {
+	backorders, err := s.backorderRepo.WithTX(tx).GetBackorders(ctx, payload.GetBackordersReq{
+		ProductIDIN: productIDs,
+		StatusIN:    []string{constant.BackorderStatusWaiting},
+	})
+	if err != nil {
+		return err
+	}
+	if len(backorders) == 0 {
+		return nil
+	}
+
+	var waitingProductIDs []string
+	var shopIDs []string
+	for _, backorder := range backorders {
+		waitingProductIDs = append(waitingProductIDs, backorder.ProductID.String())
+		if backorder.ShopID != nil {
+			shopIDs = append(shopIDs, backorder.ShopID.String())
+		}
+	}
+
+	stocks, err := s.stockRepo.WithTX(tx).GetStocks(ctx, payload.GetStocksReq{
+		ProductIDIN: waitingProductIDs,
+	})
+	if err != nil {
+		return err
+	}
+
+	lots, err := s.stockLotRepo.WithTX(tx).GetStockLots(ctx, payload.GetStockLotsReq{
+		ProductIDIN: waitingProductIDs,
+		InStockOnly: true,
+	})
+	if err != nil {
+		return err
+	}
+	stockLots := groupStockLots(lots)
+	today := startOfToday()
+
+	shopWarehouseRanks, err := s.shopWarehouseRanks(ctx, tx, shopIDs)
+	if err != nil {
+		return err
+	}
+
+	available := make(map[stockKey]int)
+	for _, stock := range stocks {
+		key := stockKey{warehouseID: stock.WarehouseID.String(), productID: stock.ProductID.String()}
+		available[key] = stock.Quantity - stock.Reserved - expiredStockLotQty(stockLots[key], today)
+	}
+
+	strategy, err := allocation.New(constant.AllocationStrategyPriority)
+	if err != nil {
+		return err
+	}
+
+	for _, backorder := range backorders {
+		var sources []allocation.Source
+		var total int
+		for _, stock := range stocks {
+			if stock.ProductID != backorder.ProductID {
+				continue
+			}
+
+			var priority int
+			if backorder.ShopID != nil {
+				rank, ok := shopWarehouseRanks[backorder.ShopID.String()][stock.WarehouseID]
+				if !ok {
+					continue
+				}
+				priority = rank
+			}
+
+			key := stockKey{warehouseID: stock.WarehouseID.String(), productID: stock.ProductID.String()}
+			if available[key] <= 0 {
+				continue
+			}
+			sources = append(sources, allocation.Source{
+				WarehouseID: stock.WarehouseID,
+				Available:   available[key],
+				Priority:    priority,
+			})
+			total += available[key]
+		}
+
+		quantity := min(backorder.Quantity-backorder.AllocatedQuantity, total)
+		if quantity == 0 {
+			continue
+		}
+
+		allocations, _ := strategy.Allocate(sources, quantity)
+		var allocated int
+		var reserves []backorderReserve
+		backorderAllocations := make([]model.BackorderAllocation, 0, len(allocations))
+		for _, a := range allocations {
+			key := stockKey{warehouseID: a.WarehouseID.String(), productID: backorder.ProductID.String()}
+			reserve, ok, err := s.reserveBackorderStock(ctx, tx, backorder.OrderRef, key, a.Quantity, reserveStockLots(stockLots[key], a.Quantity, today))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				available[key] = 0
+				continue
+			}
+
+			available[key] -= a.Quantity
+			allocated += a.Quantity
+			reserves = append(reserves, reserve)
+			backorderAllocations = append(backorderAllocations, model.BackorderAllocation{
+				BackorderID: backorder.ID,
+				WarehouseID: a.WarehouseID,
+				Quantity:    a.Quantity,
+			})
+		}
+		if allocated == 0 {
+			continue
+		}
+
+		// the backorder row is only written here, a concurrent allocation of
+		// the same backorder waits on it and then finds it changed. What was
+		// reserved for it is given back and left for the next allocation.
+		ok, err := s.backorderRepo.WithTX(tx).AllocateBackorderQty(ctx, backorder.ID.String(), allocated)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if err := s.releaseBackorderReserves(ctx, tx, backorder.OrderRef, reserves); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := s.backorderRepo.WithTX(tx).CreateBackorderAllocations(ctx, backorderAllocations); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backorderReserve is the stock, and the lots of it, reserved for one
+// allocation of a backorder.
+type backorderReserve struct {
+	key      stockKey
+	quantity int
+	lots     []payload.ReservedStockLot
+}
+
+// reserveBackorderStock reserves the stock and its lots for one allocation of
+// a backorder. It reports false, with nothing reserved, when the stock or one
+// of the lots was taken in between.
+func (s *stockService) reserveBackorderStock(ctx context.Context, tx *gorm.DB, orderRef string, key stockKey, quantity int, lots []payload.ReservedStockLot) (backorderReserve, bool, error) {
+	ok, err := s.stockRepo.WithTX(tx).ReserveStockQty(ctx, key.productID, key.warehouseID, quantity)
+	if err != nil || !ok {
+		return backorderReserve{}, false, err
+	}
+
+	reserve := backorderReserve{key: key, quantity: quantity}
+	for _, lot := range lots {
+		ok, err := s.stockLotRepo.WithTX(tx).ReserveLotQty(ctx, lot.LotID, lot.Quantity)
+		if err != nil {
+			return backorderReserve{}, false, err
+		}
+		if !ok {
+			err := s.releaseBackorderReserves(ctx, tx, orderRef, []backorderReserve{reserve})
+			return backorderReserve{}, false, err
+		}
+
+		err = s.stockLotRepo.WithTX(tx).AddLotReservationQty(ctx, lot.LotID, orderRef, lot.Quantity)
+		if err != nil {
+			return backorderReserve{}, false, err
+		}
+		reserve.lots = append(reserve.lots, lot)
+	}
+	return reserve, true, nil
+}
+
+// releaseBackorderReserves gives back what was reserved for a backorder whose
+// allocation lost to a concurrent writer.
+func (s *stockService) releaseBackorderReserves(ctx context.Context, tx *gorm.DB, orderRef string, reserves []backorderReserve) error {
+	for _, reserve := range reserves {
+		err := s.stockRepo.WithTX(tx).AddStockQtyAndReserveQty(ctx, reserve.key.productID, reserve.key.warehouseID, 0, -reserve.quantity)
+		if err != nil {
+			return err
+		}
+
+		for _, lot := range reserve.lots {
+			err = s.stockLotRepo.WithTX(tx).AddLotQtyAndReserveQty(ctx, lot.LotID, 0, -lot.Quantity)
+			if err != nil {
+				return err
+			}
+
+			err = s.stockLotRepo.WithTX(tx).AddLotReservationQty(ctx, lot.LotID, orderRef, -lot.Quantity)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,661 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	shopWarehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/repository/mocks"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	stockRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository/mocks"
+)
+
+func TestSetBackorderProduct(t *testing.T) {
+	productID := uuid.New()
+
+	tests := []struct {
+		name    string
+		req     payload.SetBackorderProductReq
+		setup   func(backorderRepo *stockRepoMock.BackorderRepository)
+		wantErr bool
+	}{
+		{
+			name: "success - take pre-orders",
+			req: payload.SetBackorderProductReq{
+				ProductID: productID,
+				Mode:      constant.BackorderModePreorder,
+			},
+			setup: func(backorderRepo *stockRepoMock.BackorderRepository) {
+				backorderRepo.On("UpsertBackorderProduct", mock.Anything, &model.BackorderProduct{
+					ProductID: productID,
+					Mode:      constant.BackorderModePreorder,
+				}).
+					Return(nil)
+			},
+		},
+		{
+			name: "success - stop taking backorders",
+			req: payload.SetBackorderProductReq{
+				ProductID: productID,
+			},
+			setup: func(backorderRepo *stockRepoMock.BackorderRepository) {
+				backorderRepo.On("DeleteBackorderProduct", mock.Anything, productID.String()).
+					Return(nil)
+			},
+		},
+		{
+			name: "error - failed to upsert backorder product",
+			req: payload.SetBackorderProductReq{
+				ProductID: productID,
+				Mode:      constant.BackorderModeBackorder,
+			},
+			setup: func(backorderRepo *stockRepoMock.BackorderRepository) {
+				backorderRepo.On("UpsertBackorderProduct", mock.Anything, mock.Anything).
+					Return(errors.New("failed to upsert backorder product"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			backorderRepo := stockRepoMock.NewBackorderRepository(t)
+			stockSvc := stockService{
+				logger:        pkg.InitLogger(&config.Config{}),
+				backorderRepo: backorderRepo,
+			}
+
+			tt.setup(backorderRepo)
+
+			// When
+			err := stockSvc.SetBackorderProduct(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReserveStocks_ShouldBackorder(t *testing.T) {
+	type dependencyMocks struct {
+		db             sqlmock.Sqlmock
+		stockRepo      *stockRepoMock.StockRepository
+		stockAlertRepo *stockRepoMock.StockAlertRepository
+		stockLotRepo   *stockRepoMock.StockLotRepository
+		backorderRepo  *stockRepoMock.BackorderRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	warehouseID := uuid.New()
+	backorderID := uuid.New()
+
+	expectStocks := func(m dependencyMocks) {
+		m.stockRepo.On("WithTX", mock.Anything).
+			Return(m.stockRepo)
+		m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+			Return([]model.WarehouseStock{
+				{WarehouseID: warehouseID, ProductID: productID, Quantity: 10, Reserved: 8},
+			}, nil)
+		m.stockLotRepo.On("WithTX", mock.Anything).
+			Return(m.stockLotRepo)
+		m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+			Return([]model.StockLot{}, nil)
+	}
+	expectBackorderProduct := func(m dependencyMocks, products []model.BackorderProduct) {
+		m.backorderRepo.On("WithTX", mock.Anything).
+			Return(m.backorderRepo)
+		m.backorderRepo.On("GetBackorderProducts", mock.Anything, []string{productID.String()}).
+			Return(products, nil)
+	}
+
+	tests := []struct {
+		name     string
+		req      payload.ReserveStocksReq
+		setup    func(m dependencyMocks)
+		expected []payload.ReserveStocksResp
+		wantErr  bool
+		errCode  apperr.Code
+	}{
+		{
+			name: "success - backorder takes the stock left",
+			req: payload.ReserveStocksReq{
+				OrderRef: "order-1",
+				Stocks:   []payload.ReserveStocksData{{ProductID: productID.String(), Quantity: 5}},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectStocks(m)
+				expectBackorderProduct(m, []model.BackorderProduct{{ProductID: productID, Mode: constant.BackorderModePreorder}})
+				m.backorderRepo.On("CreateBackorder", mock.Anything, mock.MatchedBy(func(backorder *model.Backorder) bool {
+					return backorder.OrderRef == "order-1" && backorder.Quantity == 5 &&
+						backorder.Mode == constant.BackorderModePreorder && backorder.Status == constant.BackorderStatusWaiting
+				})).
+					Run(func(args mock.Arguments) {
+						args.Get(1).(*model.Backorder).ID = backorderID
+					}).
+					Return(nil)
+
+				// the new backorder is the only one waiting, it takes the 2 left
+				m.backorderRepo.On("GetBackorders", mock.Anything, payload.GetBackordersReq{
+					ProductIDIN: []string{productID.String()},
+					StatusIN:    []string{constant.BackorderStatusWaiting},
+				}).
+					Return([]model.Backorder{{ID: backorderID, ProductID: productID, Quantity: 5, Status: constant.BackorderStatusWaiting}}, nil)
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 2).
+					Return(true, nil)
+				m.backorderRepo.On("AllocateBackorderQty", mock.Anything, backorderID.String(), 2).
+					Return(true, nil)
+				m.backorderRepo.On("CreateBackorderAllocations", mock.Anything, []model.BackorderAllocation{
+					{BackorderID: backorderID, WarehouseID: warehouseID, Quantity: 2},
+				}).
+					Return(nil)
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+				m.db.ExpectCommit()
+			},
+			expected: []payload.ReserveStocksResp{
+				{ProductID: productID.String(), BackorderID: backorderID.String(), BackorderQuantity: 5},
+			},
+		},
+		{
+			name: "error - product does not take backorders",
+			req: payload.ReserveStocksReq{
+				OrderRef: "order-1",
+				Stocks:   []payload.ReserveStocksData{{ProductID: productID.String(), Quantity: 5}},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectStocks(m)
+				expectBackorderProduct(m, []model.BackorderProduct{})
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+			errCode: apperr.CodeHTTPBadRequest,
+		},
+		{
+			name: "error - backorder without order ref",
+			req: payload.ReserveStocksReq{
+				Stocks: []payload.ReserveStocksData{{ProductID: productID.String(), Quantity: 5}},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectStocks(m)
+				expectBackorderProduct(m, []model.BackorderProduct{{ProductID: productID, Mode: constant.BackorderModeBackorder}})
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+			errCode: apperr.CodeHTTPBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:             mockDb.Mock,
+				stockRepo:      stockRepoMock.NewStockRepository(t),
+				stockAlertRepo: stockRepoMock.NewStockAlertRepository(t),
+				stockLotRepo:   stockRepoMock.NewStockLotRepository(t),
+				backorderRepo:  stockRepoMock.NewBackorderRepository(t),
+			}
+			stockSvc := stockService{
+				logger:         pkg.InitLogger(&config.Config{}),
+				db:             mockDb.Db,
+				availability:   newAvailabilityBroker(),
+				stockRepo:      mocks.stockRepo,
+				stockAlertRepo: mocks.stockAlertRepo,
+				stockLotRepo:   mocks.stockLotRepo,
+				backorderRepo:  mocks.backorderRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			result, err := stockSvc.ReserveStocks(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.errCode, apperr.ErrCode(err))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAllocateBackorders(t *testing.T) {
+	type dependencyMocks struct {
+		stockRepo         *stockRepoMock.StockRepository
+		stockLotRepo      *stockRepoMock.StockLotRepository
+		backorderRepo     *stockRepoMock.BackorderRepository
+		shopWarehouseRepo *shopWarehouseRepoMock.ShopWarehouseRepository
+	}
+
+	productID := uuid.New()
+	shopID := uuid.New()
+	warehouseID := uuid.New()
+	shopWarehouseID := uuid.New()
+	firstID, secondID, thirdID := uuid.New(), uuid.New(), uuid.New()
+	lotID, otherLotID := uuid.New(), uuid.New()
+
+	expectRepos := func(m dependencyMocks) {
+		m.backorderRepo.On("WithTX", mock.Anything).
+			Return(m.backorderRepo)
+		m.stockRepo.On("WithTX", mock.Anything).
+			Return(m.stockRepo)
+		m.stockLotRepo.On("WithTX", mock.Anything).
+			Return(m.stockLotRepo)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - oldest backorder first",
+			setup: func(m dependencyMocks) {
+				expectRepos(m)
+				m.backorderRepo.On("GetBackorders", mock.Anything, mock.Anything).
+					Return([]model.Backorder{
+						{ID: firstID, ProductID: productID, Quantity: 4, AllocatedQuantity: 1, Status: constant.BackorderStatusWaiting},
+						{ID: secondID, ProductID: productID, Quantity: 5, Status: constant.BackorderStatusWaiting},
+						{ID: thirdID, ProductID: productID, Quantity: 1, Status: constant.BackorderStatusWaiting},
+					}, nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 10, Reserved: 5},
+					}, nil)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				// the first takes the 3 it is short of, the second the 2 left
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 3).
+					Return(true, nil).Once()
+				m.backorderRepo.On("AllocateBackorderQty", mock.Anything, firstID.String(), 3).
+					Return(true, nil)
+				m.backorderRepo.On("CreateBackorderAllocations", mock.Anything, []model.BackorderAllocation{
+					{BackorderID: firstID, WarehouseID: warehouseID, Quantity: 3},
+				}).
+					Return(nil)
+
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 2).
+					Return(true, nil).Once()
+				m.backorderRepo.On("AllocateBackorderQty", mock.Anything, secondID.String(), 2).
+					Return(true, nil)
+				m.backorderRepo.On("CreateBackorderAllocations", mock.Anything, []model.BackorderAllocation{
+					{BackorderID: secondID, WarehouseID: warehouseID, Quantity: 2},
+				}).
+					Return(nil)
+			},
+		},
+		{
+			name: "success - shop backorder only takes its warehouses",
+			setup: func(m dependencyMocks) {
+				expectRepos(m)
+				m.backorderRepo.On("GetBackorders", mock.Anything, mock.Anything).
+					Return([]model.Backorder{
+						{ID: firstID, ProductID: productID, ShopID: &shopID, Quantity: 3, Status: constant.BackorderStatusWaiting},
+					}, nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 10},
+						{WarehouseID: shopWarehouseID, ProductID: productID, Quantity: 3},
+					}, nil)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.shopWarehouseRepo.On("WithTX", mock.Anything).
+					Return(m.shopWarehouseRepo)
+				m.shopWarehouseRepo.On("GetShopWarehouses", mock.Anything, mock.Anything).
+					Return([]model.ShopWarehouse{{ShopID: shopID, WarehouseID: shopWarehouseID}}, nil)
+
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), shopWarehouseID.String(), 3).
+					Return(true, nil)
+				m.backorderRepo.On("AllocateBackorderQty", mock.Anything, firstID.String(), 3).
+					Return(true, nil)
+				m.backorderRepo.On("CreateBackorderAllocations", mock.Anything, []model.BackorderAllocation{
+					{BackorderID: firstID, WarehouseID: shopWarehouseID, Quantity: 3},
+				}).
+					Return(nil)
+			},
+		},
+		{
+			name: "success - stock taken in between is left out",
+			setup: func(m dependencyMocks) {
+				expectRepos(m)
+				m.backorderRepo.On("GetBackorders", mock.Anything, mock.Anything).
+					Return([]model.Backorder{
+						{ID: firstID, ProductID: productID, Quantity: 3, Status: constant.BackorderStatusWaiting},
+					}, nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 3},
+					}, nil)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 3).
+					Return(false, nil)
+			},
+		},
+		{
+			name: "success - no waiting backorders",
+			setup: func(m dependencyMocks) {
+				m.backorderRepo.On("WithTX", mock.Anything).
+					Return(m.backorderRepo)
+				m.backorderRepo.On("GetBackorders", mock.Anything, mock.Anything).
+					Return([]model.Backorder{}, nil)
+			},
+		},
+		{
+			name: "error - failed to allocate",
+			setup: func(m dependencyMocks) {
+				expectRepos(m)
+				m.backorderRepo.On("GetBackorders", mock.Anything, mock.Anything).
+					Return([]model.Backorder{
+						{ID: firstID, ProductID: productID, Quantity: 1, Status: constant.BackorderStatusWaiting},
+					}, nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 1},
+					}, nil)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 1).
+					Return(false, errors.New("failed to reserve stock"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "success - backorder allocated in between gives its stock back",
+			setup: func(m dependencyMocks) {
+				expectRepos(m)
+				m.backorderRepo.On("GetBackorders", mock.Anything, mock.Anything).
+					Return([]model.Backorder{
+						{ID: firstID, OrderRef: "order-1", ProductID: productID, Quantity: 1, Status: constant.BackorderStatusWaiting},
+					}, nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 1},
+					}, nil)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{
+						{ID: lotID, WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-1", Quantity: 1},
+					}, nil)
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 1).
+					Return(true, nil)
+				m.stockLotRepo.On("ReserveLotQty", mock.Anything, lotID.String(), 1).
+					Return(true, nil)
+				m.stockLotRepo.On("AddLotReservationQty", mock.Anything, lotID.String(), "order-1", 1).
+					Return(nil)
+				m.backorderRepo.On("AllocateBackorderQty", mock.Anything, firstID.String(), 1).
+					Return(false, nil)
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -1).
+					Return(nil)
+				m.stockLotRepo.On("AddLotQtyAndReserveQty", mock.Anything, lotID.String(), 0, -1).
+					Return(nil)
+				m.stockLotRepo.On("AddLotReservationQty", mock.Anything, lotID.String(), "order-1", -1).
+					Return(nil)
+			},
+		},
+		{
+			name: "success - lot taken in between is left out",
+			setup: func(m dependencyMocks) {
+				expectRepos(m)
+				m.backorderRepo.On("GetBackorders", mock.Anything, mock.Anything).
+					Return([]model.Backorder{
+						{ID: firstID, OrderRef: "order-1", ProductID: productID, Quantity: 3, Status: constant.BackorderStatusWaiting},
+					}, nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 3},
+					}, nil)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{
+						{ID: lotID, WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-1", Quantity: 2},
+						{ID: otherLotID, WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-2", Quantity: 1},
+					}, nil)
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 3).
+					Return(true, nil)
+				m.stockLotRepo.On("ReserveLotQty", mock.Anything, lotID.String(), 2).
+					Return(true, nil)
+				m.stockLotRepo.On("AddLotReservationQty", mock.Anything, lotID.String(), "order-1", 2).
+					Return(nil)
+				m.stockLotRepo.On("ReserveLotQty", mock.Anything, otherLotID.String(), 1).
+					Return(false, nil)
+
+				// what the allocation took so far is given back
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -3).
+					Return(nil)
+				m.stockLotRepo.On("AddLotQtyAndReserveQty", mock.Anything, lotID.String(), 0, -2).
+					Return(nil)
+				m.stockLotRepo.On("AddLotReservationQty", mock.Anything, lotID.String(), "order-1", -2).
+					Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				stockRepo:         stockRepoMock.NewStockRepository(t),
+				stockLotRepo:      stockRepoMock.NewStockLotRepository(t),
+				backorderRepo:     stockRepoMock.NewBackorderRepository(t),
+				shopWarehouseRepo: shopWarehouseRepoMock.NewShopWarehouseRepository(t),
+			}
+			stockSvc := stockService{
+				logger:            pkg.InitLogger(&config.Config{}),
+				stockRepo:         mocks.stockRepo,
+				stockLotRepo:      mocks.stockLotRepo,
+				backorderRepo:     mocks.backorderRepo,
+				shopWarehouseRepo: mocks.shopWarehouseRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := stockSvc.allocateBackorders(context.Background(), nil, []string{productID.String()})
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCancelBackorders(t *testing.T) {
+	type dependencyMocks struct {
+		db             sqlmock.Sqlmock
+		stockRepo      *stockRepoMock.StockRepository
+		stockAlertRepo *stockRepoMock.StockAlertRepository
+		stockLotRepo   *stockRepoMock.StockLotRepository
+		backorderRepo  *stockRepoMock.BackorderRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	warehouseID := uuid.New()
+	backorderID := uuid.New()
+	lotID := uuid.New()
+	req := payload.CancelBackordersReq{OrderRef: "order-1"}
+
+	expectBackorders := func(m dependencyMocks, backorders []model.Backorder) {
+		m.backorderRepo.On("WithTX", mock.Anything).
+			Return(m.backorderRepo)
+		m.backorderRepo.On("WithLockForUpdate").
+			Return(m.backorderRepo)
+		m.backorderRepo.On("GetBackorders", mock.Anything, payload.GetBackordersReq{
+			OrderRefIN: []string{"order-1"},
+			StatusIN:   []string{constant.BackorderStatusWaiting, constant.BackorderStatusAllocated},
+		}).
+			Return(backorders, nil).Once()
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - allocated stock is released",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectBackorders(m, []model.Backorder{{
+					ID:                backorderID,
+					OrderRef:          "order-1",
+					ProductID:         productID,
+					Quantity:          5,
+					AllocatedQuantity: 2,
+					Status:            constant.BackorderStatusWaiting,
+					Allocations:       []model.BackorderAllocation{{BackorderID: backorderID, WarehouseID: warehouseID, Quantity: 2}},
+				}})
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, payload.GetStockLotsReq{
+					WarehouseIDIN: []string{warehouseID.String()},
+					ProductIDIN:   []string{productID.String()},
+				}).
+					Return([]model.StockLot{{ID: lotID, WarehouseID: warehouseID, ProductID: productID, Quantity: 10, Reserved: 2}}, nil)
+				m.stockLotRepo.On("GetStockLotReservations", mock.Anything, mock.Anything).
+					Return([]model.StockLotReservation{{StockLotID: lotID, OrderRef: "order-1", Quantity: 2}}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -2).
+					Return(nil)
+				m.stockLotRepo.On("AddLotQtyAndReserveQty", mock.Anything, lotID.String(), 0, -2).
+					Return(nil)
+				m.stockLotRepo.On("AddLotReservationQty", mock.Anything, lotID.String(), "order-1", -2).
+					Return(nil)
+				m.backorderRepo.On("UpdateBackorder", mock.Anything, mock.MatchedBy(func(backorder *model.Backorder) bool {
+					return backorder.ID == backorderID && backorder.Status == constant.BackorderStatusCancelled
+				})).
+					Return(nil)
+
+				// the released stock goes to the next waiting backorders
+				m.backorderRepo.On("GetBackorders", mock.Anything, payload.GetBackordersReq{
+					ProductIDIN: []string{productID.String()},
+					StatusIN:    []string{constant.BackorderStatusWaiting},
+				}).
+					Return([]model.Backorder{}, nil)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - nothing to cancel",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectBackorders(m, []model.Backorder{})
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - failed to release stock",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectBackorders(m, []model.Backorder{{
+					ID:          backorderID,
+					OrderRef:    "order-1",
+					ProductID:   productID,
+					Quantity:    5,
+					Status:      constant.BackorderStatusAllocated,
+					Allocations: []model.BackorderAllocation{{BackorderID: backorderID, WarehouseID: warehouseID, Quantity: 5}},
+				}})
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -5).
+					Return(errors.New("failed to release stock"))
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:             mockDb.Mock,
+				stockRepo:      stockRepoMock.NewStockRepository(t),
+				stockAlertRepo: stockRepoMock.NewStockAlertRepository(t),
+				stockLotRepo:   stockRepoMock.NewStockLotRepository(t),
+				backorderRepo:  stockRepoMock.NewBackorderRepository(t),
+			}
+			stockSvc := stockService{
+				logger:         pkg.InitLogger(&config.Config{}),
+				db:             mockDb.Db,
+				availability:   newAvailabilityBroker(),
+				stockRepo:      mocks.stockRepo,
+				stockAlertRepo: mocks.stockAlertRepo,
+				stockLotRepo:   mocks.stockLotRepo,
+				backorderRepo:  mocks.backorderRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := stockSvc.CancelBackorders(context.Background(), req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+var stockImportColumns = []string{"warehouse_id", "product_id", "quantity"}
+
+type stockImportKey struct {
+	warehouseID uuid.UUID
+	productID   uuid.UUID
+}
+
+type stockImportRow struct {
+	line  int
+	stock model.WarehouseStock
+}
+
+// ImportStocks upserts the stocks of a CSV file. The quantity of a row replaces
+// the quantity on hand and cannot go below what is already reserved.
+func (s *stockService) ImportStocks(ctx context.Context, req payload.ImportStocksReq, file io.Reader) (result payload.ImportStocksResult, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.ImportStocks")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if req.Mode == "" {
+		req.Mode = constant.StockImportModeSingle
+	}
+	if req.ChunkSize == 0 {
+		req.ChunkSize = constant.DefaultStockImportChunkSize
+	}
+
+	rows, rowErrs, err := parseStockImportCSV(file)
+	if err != nil {
+		return payload.ImportStocksResult{}, err
+	}
+
+	result = payload.ImportStocksResult{
+		DryRun:    req.DryRun,
+		Mode:      req.Mode,
+		TotalRows: len(rows) + len(rowErrs),
+		Errors:    rowErrs,
+	}
+
+	switch {
+	case req.DryRun:
+		valid, errs, err := s.checkStockImportRows(ctx, nil, rows)
+		if err != nil {
+			return payload.ImportStocksResult{}, err
+		}
+		result.ValidRows = len(valid)
+		result.Errors = append(result.Errors, errs...)
+
+	case req.Mode == constant.StockImportModeSingle:
+		tx := s.db.Begin()
+		defer tx.Rollback()
+
+		valid, errs, err := s.checkStockImportRows(ctx, tx, rows)
+		if err != nil {
+			return payload.ImportStocksResult{}, err
+		}
+		result.ValidRows = len(valid)
+		result.Errors = append(result.Errors, errs...)
+
+		// all or nothing, a single bad row keeps the whole file out
+		if len(result.Errors) > 0 {
+			break
+		}
+
+		alerts, err := s.upsertStockImportRows(ctx, tx, valid)
+		if err != nil {
+			return payload.ImportStocksResult{}, err
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			return payload.ImportStocksResult{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+		}
+		result.ImportedRows = len(valid)
+
+		s.publishStockAlerts(ctx, alerts)
+
+	default:
+		for start := 0; start < len(rows); start += req.ChunkSize {
+			chunk := rows[start:min(start+req.ChunkSize, len(rows))]
+
+			imported, errs := s.importStockChunk(ctx, chunk)
+			result.ValidRows += imported
+			result.ImportedRows += imported
+			result.Errors = append(result.Errors, errs...)
+		}
+	}
+
+	s.logger.WithContext(ctx).Infow("Stocks imported",
+		"mode", result.Mode,
+		"dry_run", result.DryRun,
+		"total_rows", result.TotalRows,
+		"imported_rows", result.ImportedRows,
+		"errors", len(result.Errors),
+	)
+
+	return result, nil
+}
+
+// importStockChunk commits one chunk on its own. When the chunk cannot be
+// written every row in it is reported with the failure.
+func (s *stockService) importStockChunk(ctx context.Context, rows []stockImportRow) (int, []payload.ImportStockRowErr) {
+	failAll := func(err error) (int, []payload.ImportStockRowErr) {
+		errs := make([]payload.ImportStockRowErr, 0, len(rows))
+		for _, row := range rows {
+			errs = append(errs, payload.ImportStockRowErr{Row: row.line, Message: err.Error()})
+		}
+		return 0, errs
+	}
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	valid, errs, err := s.checkStockImportRows(ctx, tx, rows)
+	if err != nil {
+		return failAll(err)
+	}
+
+	if len(valid) == 0 {
+		return 0, errs
+	}
+
+	alerts, err := s.upsertStockImportRows(ctx, tx, valid)
+	if err != nil {
+		return failAll(err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return failAll(apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction"))
+	}
+
+	s.publishStockAlerts(ctx, alerts)
+
+	return len(valid), errs
+}
+
+// checkStockImportRows validates the rows against the database. The target
+// warehouses must be active, the products must not be serialized and the new
+// quantity must still cover the reserved quantity. Warehouses and existing
+// stocks are locked when running inside tx.
+func (s *stockService) checkStockImportRows(ctx context.Context, tx *gorm.DB, rows []stockImportRow) ([]stockImportRow, []payload.ImportStockRowErr, error) {
+	if len(rows) == 0 {
+		return nil, nil, nil
+	}
+
+	var warehouseIDs, productIDs []string
+	seenWarehouses := make(map[uuid.UUID]bool)
+	seenProducts := make(map[uuid.UUID]bool)
+	for _, row := range rows {
+		if !seenWarehouses[row.stock.WarehouseID] {
+			seenWarehouses[row.stock.WarehouseID] = true
+			warehouseIDs = append(warehouseIDs, row.stock.WarehouseID.String())
+		}
+		if !seenProducts[row.stock.ProductID] {
+			seenProducts[row.stock.ProductID] = true
+			productIDs = append(productIDs, row.stock.ProductID.String())
+		}
+	}
+
+	// the share lock keeps the warehouses from being archived under the import
+	warehouseRepo := s.warehouseRepo.WithTX(tx)
+	if tx != nil {
+		warehouseRepo = warehouseRepo.WithLockForShare()
+	}
+
+	warehouses, err := warehouseRepo.GetWarehousesByIDs(ctx, warehouseIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	warehouseStatus := make(map[uuid.UUID]string, len(warehouses))
+	for _, warehouse := range warehouses {
+		warehouseStatus[warehouse.ID] = warehouse.Status
+	}
+
+	stockRepo := s.stockRepo.WithTX(tx)
+	if tx != nil {
+		stockRepo = stockRepo.WithLockForUpdate()
+	}
+
+	stocks, err := stockRepo.GetStocks(ctx, payload.GetStocksReq{
+		WarehouseIDIN: warehouseIDs,
+		ProductIDIN:   productIDs,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reserved := make(map[stockImportKey]int, len(stocks))
+	for _, stock := range stocks {
+		reserved[stockImportKey{stock.WarehouseID, stock.ProductID}] = stock.Reserved
+	}
+
+	serialized, err := s.serializedProducts(ctx, tx, productIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var valid []stockImportRow
+	var errs []payload.ImportStockRowErr
+	for _, row := range rows {
+		status, ok := warehouseStatus[row.stock.WarehouseID]
+		if !ok {
+			errs = append(errs, payload.ImportStockRowErr{Row: row.line, Message: "warehouse not found"})
+			continue
+		}
+
+		if status != constant.WarehouseStatusActive {
+			errs = append(errs, payload.ImportStockRowErr{Row: row.line, Message: "warehouse is not active"})
+			continue
+		}
+
+		if serialized[row.stock.ProductID.String()] {
+			errs = append(errs, payload.ImportStockRowErr{Row: row.line, Message: "product is serialized, its stock follows its serials"})
+			continue
+		}
+
+		if r := reserved[stockImportKey{row.stock.WarehouseID, row.stock.ProductID}]; row.stock.Quantity < r {
+			errs = append(errs, payload.ImportStockRowErr{Row: row.line, Message: fmt.Sprintf("quantity cannot be below the reserved quantity %d", r)})
+			continue
+		}
+
+		valid = append(valid, row)
+	}
+
+	return valid, errs, nil
+}
+
+func (s *stockService) upsertStockImportRows(ctx context.Context, tx *gorm.DB, rows []stockImportRow) ([]model.StockAlert, error) {
+	stocks := make([]model.WarehouseStock, 0, len(rows))
+	var productIDs []string
+	seen := make(map[uuid.UUID]bool)
+	for _, row := range rows {
+		stocks = append(stocks, row.stock)
+		if !seen[row.stock.ProductID] {
+			seen[row.stock.ProductID] = true
+			productIDs = append(productIDs, row.stock.ProductID.String())
+		}
+	}
+
+	if err := s.stockRepo.WithTX(tx).UpsertStocks(ctx, stocks); err != nil {
+		return nil, err
+	}
+
+	if err := s.allocateBackorders(ctx, tx, productIDs); err != nil {
+		return nil, err
+	}
+
+	return s.evaluateStockAlerts(ctx, tx, productIDs)
+}
+
+// parseStockImportCSV reads the rows of the file. Rows that cannot be parsed
+// are reported instead of failing the import, only an unreadable file or a
+// missing column is an error.
+func parseStockImportCSV(file io.Reader) ([]stockImportRow, []payload.ImportStockRowErr, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "csv file is empty")
+		}
+		return nil, nil, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "failed to read csv header")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range stockImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "csv file is missing the "+name+" column")
+		}
+	}
+
+	var rows []stockImportRow
+	var errs []payload.ImportStockRowErr
+	seen := make(map[stockImportKey]int)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			// FieldPos panics after a failed read, the parse error carries the line instead
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				errs = append(errs, payload.ImportStockRowErr{Row: parseErr.Line, Message: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "failed to read csv file")
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			if i := columns[name]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		warehouseID, err := uuid.Parse(field("warehouse_id"))
+		if err != nil {
+			errs = append(errs, payload.ImportStockRowErr{Row: line, Message: "invalid warehouse_id"})
+			continue
+		}
+
+		productID, err := uuid.Parse(field("product_id"))
+		if err != nil {
+			errs = append(errs, payload.ImportStockRowErr{Row: line, Message: "invalid product_id"})
+			continue
+		}
+
+		quantity, err := strconv.Atoi(field("quantity"))
+		if err != nil || quantity < 0 {
+			errs = append(errs, payload.ImportStockRowErr{Row: line, Message: "quantity must be a non-negative integer"})
+			continue
+		}
+
+		key := stockImportKey{warehouseID, productID}
+		if first, ok := seen[key]; ok {
+			errs = append(errs, payload.ImportStockRowErr{Row: line, Message: fmt.Sprintf("duplicate of row %d", first)})
+			continue
+		}
+		seen[key] = line
+
+		rows = append(rows, stockImportRow{
+			line: line,
+			stock: model.WarehouseStock{
+				WarehouseID: warehouseID,
+				ProductID:   productID,
+				Quantity:    quantity,
+			},
+		})
+	}
+
+	if len(rows)+len(errs) == 0 {
+		return nil, nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "csv file has no rows")
+	}
+
+	return rows, errs, nil
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	stockRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository/mocks"
+	warehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository/mocks"
+)
+
+func TestParseStockImportCSV(t *testing.T) {
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	productID2 := uuid.New()
+
+	tests := []struct {
+		name     string
+		csv      string
+		wantRows []stockImportRow
+		wantErrs []payload.ImportStockRowErr
+		wantErr  bool
+	}{
+		{
+			name: "success - columns in any order",
+			csv: "quantity,product_id,warehouse_id\n" +
+				"10," + productID.String() + "," + warehouseID.String() + "\n" +
+				" 0 , " + productID2.String() + " , " + warehouseID.String() + "\n",
+			wantRows: []stockImportRow{
+				{line: 2, stock: model.WarehouseStock{WarehouseID: warehouseID, ProductID: productID, Quantity: 10}},
+				{line: 3, stock: model.WarehouseStock{WarehouseID: warehouseID, ProductID: productID2, Quantity: 0}},
+			},
+		},
+		{
+			name: "success - invalid rows are reported",
+			csv: "warehouse_id,product_id,quantity\n" +
+				"not-a-uuid," + productID.String() + ",10\n" +
+				warehouseID.String() + ",," + "10\n" +
+				warehouseID.String() + "," + productID.String() + ",-1\n" +
+				warehouseID.String() + "," + productID.String() + ",5\n" +
+				warehouseID.String() + "," + productID.String() + ",7\n",
+			wantRows: []stockImportRow{
+				{line: 5, stock: model.WarehouseStock{WarehouseID: warehouseID, ProductID: productID, Quantity: 5}},
+			},
+			wantErrs: []payload.ImportStockRowErr{
+				{Row: 2, Message: "invalid warehouse_id"},
+				{Row: 3, Message: "invalid product_id"},
+				{Row: 4, Message: "quantity must be a non-negative integer"},
+				{Row: 6, Message: "duplicate of row 5"},
+			},
+		},
+		{
+			name: "success - malformed rows are reported",
+			csv: "warehouse_id,product_id,quantity\n" +
+				"a\"b,c,1\n" +
+				warehouseID.String() + "," + productID.String() + ",5\n",
+			wantRows: []stockImportRow{
+				{line: 3, stock: model.WarehouseStock{WarehouseID: warehouseID, ProductID: productID, Quantity: 5}},
+			},
+			wantErrs: []payload.ImportStockRowErr{
+				{Row: 2, Message: csv.ErrBareQuote.Error()},
+			},
+		},
+		{
+			name:    "error - missing column",
+			csv:     "warehouse_id,quantity\n" + warehouseID.String() + ",10\n",
+			wantErr: true,
+		},
+		{
+			name:    "error - header only",
+			csv:     "warehouse_id,product_id,quantity\n",
+			wantErr: true,
+		},
+		{
+			name:    "error - empty file",
+			csv:     "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, errs, err := parseStockImportCSV(strings.NewReader(tt.csv))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRows, rows)
+			assert.Equal(t, tt.wantErrs, errs)
+		})
+	}
+}
+
+func TestImportStocks(t *testing.T) {
+	type dependencyMocks struct {
+		db              sqlmock.Sqlmock
+		stockRepo       *stockRepoMock.StockRepository
+		stockAlertRepo  *stockRepoMock.StockAlertRepository
+		warehouseRepo   *warehouseRepoMock.WarehouseRepository
+		stockSerialRepo *stockRepoMock.StockSerialRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	warehouseID := uuid.New()
+	inactiveWarehouseID := uuid.New()
+	productID := uuid.New()
+	productID2 := uuid.New()
+
+	// row 3 goes below the 4 units already reserved
+	csvFile := "warehouse_id,product_id,quantity\n" +
+		warehouseID.String() + "," + productID.String() + ",10\n" +
+		warehouseID.String() + "," + productID2.String() + ",3\n"
+
+	warehouses := []model.Warehouse{
+		{ID: warehouseID, Status: constant.WarehouseStatusActive},
+		{ID: inactiveWarehouseID, Status: constant.WarehouseStatusInactive},
+	}
+	stocks := []model.WarehouseStock{
+		{WarehouseID: warehouseID, ProductID: productID2, Quantity: 8, Reserved: 4},
+	}
+
+	expectSerialized := func(m dependencyMocks, products ...model.SerializedProduct) {
+		m.stockSerialRepo.On("WithTX", mock.Anything).
+			Return(m.stockSerialRepo)
+		m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, mock.Anything).
+			Return(products, nil)
+	}
+
+	expectAlerts := func(m dependencyMocks) {
+		m.stockAlertRepo.On("WithTX", mock.Anything).
+			Return(m.stockAlertRepo)
+		m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+			Return([]model.ProductStockThreshold{}, nil)
+		m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+			Return([]model.StockAlert{}, nil)
+	}
+
+	tests := []struct {
+		name  string
+		req   payload.ImportStocksReq
+		csv   string
+		setup func(m dependencyMocks)
+		want  payload.ImportStocksResult
+	}{
+		{
+			name: "success - dry run only validates",
+			req:  payload.ImportStocksReq{DryRun: true},
+			csv:  csvFile,
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseID.String()}).
+					Return(warehouses, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return(stocks, nil)
+				expectSerialized(m)
+			},
+			want: payload.ImportStocksResult{
+				DryRun:    true,
+				Mode:      constant.StockImportModeSingle,
+				TotalRows: 2,
+				ValidRows: 1,
+				Errors: []payload.ImportStockRowErr{
+					{Row: 3, Message: "quantity cannot be below the reserved quantity 4"},
+				},
+			},
+		},
+		{
+			name: "success - serialized products are reported",
+			req:  payload.ImportStocksReq{DryRun: true},
+			csv:  csvFile,
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseID.String()}).
+					Return(warehouses, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return(stocks, nil)
+				expectSerialized(m, model.SerializedProduct{ProductID: productID})
+			},
+			want: payload.ImportStocksResult{
+				DryRun:    true,
+				Mode:      constant.StockImportModeSingle,
+				TotalRows: 2,
+				Errors: []payload.ImportStockRowErr{
+					{Row: 2, Message: "product is serialized, its stock follows its serials"},
+					{Row: 3, Message: "quantity cannot be below the reserved quantity 4"},
+				},
+			},
+		},
+		{
+			name: "success - single mode writes nothing when a row is invalid",
+			req:  payload.ImportStocksReq{Mode: constant.StockImportModeSingle},
+			csv:  csvFile,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseID.String()}).
+					Return(warehouses, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("WithLockForUpdate").
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return(stocks, nil)
+				expectSerialized(m)
+
+				m.db.ExpectRollback()
+			},
+			want: payload.ImportStocksResult{
+				Mode:      constant.StockImportModeSingle,
+				TotalRows: 2,
+				ValidRows: 1,
+				Errors: []payload.ImportStockRowErr{
+					{Row: 3, Message: "quantity cannot be below the reserved quantity 4"},
+				},
+			},
+		},
+		{
+			name: "success - single mode imports every row",
+			req:  payload.ImportStocksReq{},
+			csv: "warehouse_id,product_id,quantity\n" +
+				warehouseID.String() + "," + productID.String() + ",10\n" +
+				warehouseID.String() + "," + productID2.String() + ",4\n",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseID.String()}).
+					Return(warehouses, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("WithLockForUpdate").
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return(stocks, nil)
+				expectSerialized(m)
+				m.stockRepo.On("UpsertStocks", mock.Anything, []model.WarehouseStock{
+					{WarehouseID: warehouseID, ProductID: productID, Quantity: 10},
+					{WarehouseID: warehouseID, ProductID: productID2, Quantity: 4},
+				}).
+					Return(nil)
+				expectAlerts(m)
+
+				m.db.ExpectCommit()
+			},
+			want: payload.ImportStocksResult{
+				Mode:         constant.StockImportModeSingle,
+				TotalRows:    2,
+				ValidRows:    2,
+				ImportedRows: 2,
+			},
+		},
+		{
+			name: "success - chunked mode skips invalid rows",
+			req:  payload.ImportStocksReq{Mode: constant.StockImportModeChunked, ChunkSize: 1},
+			csv: csvFile +
+				inactiveWarehouseID.String() + "," + productID.String() + ",1\n",
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, mock.Anything).
+					Return(warehouses, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("WithLockForUpdate").
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return(stocks, nil)
+				expectSerialized(m)
+
+				// first chunk is committed
+				m.db.ExpectBegin()
+				m.stockRepo.On("UpsertStocks", mock.Anything, []model.WarehouseStock{
+					{WarehouseID: warehouseID, ProductID: productID, Quantity: 10},
+				}).
+					Return(nil).Once()
+				expectAlerts(m)
+				m.db.ExpectCommit()
+
+				// the other chunks only hold invalid rows
+				m.db.ExpectBegin()
+				m.db.ExpectRollback()
+				m.db.ExpectBegin()
+				m.db.ExpectRollback()
+			},
+			want: payload.ImportStocksResult{
+				Mode:         constant.StockImportModeChunked,
+				TotalRows:    3,
+				ValidRows:    1,
+				ImportedRows: 1,
+				Errors: []payload.ImportStockRowErr{
+					{Row: 3, Message: "quantity cannot be below the reserved quantity 4"},
+					{Row: 4, Message: "warehouse is not active"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:              mockDb.Mock,
+				stockRepo:       stockRepoMock.NewStockRepository(t),
+				stockAlertRepo:  stockRepoMock.NewStockAlertRepository(t),
+				warehouseRepo:   warehouseRepoMock.NewWarehouseRepository(t),
+				stockSerialRepo: stockRepoMock.NewStockSerialRepository(t),
+			}
+			stockSvc := stockService{
+				logger:          pkg.InitLogger(&config.Config{}),
+				db:              mockDb.Db,
+				backorderRepo:   noBackorders(t),
+				stockRepo:       mocks.stockRepo,
+				stockAlertRepo:  mocks.stockAlertRepo,
+				warehouseRepo:   mocks.warehouseRepo,
+				stockSerialRepo: mocks.stockSerialRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			result, err := stockSvc.ImportStocks(context.Background(), tt.req, strings.NewReader(tt.csv))
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -4,10 +4,15 @@ package mocks
 
 import (
 	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
 
 	model "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+
 	payload "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
-	mock "github.com/stretchr/testify/mock"
+
+	gorm "gorm.io/gorm"
 )
 
 // StockService is an autogenerated mock type for the StockService type
@@ -15,6 +20,24 @@ type StockService struct {
 	mock.Mock
 }
 
+// CancelBackorders provides a mock function with given fields: ctx, req
+func (_m *StockService) CancelBackorders(ctx context.Context, req payload.CancelBackordersReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelBackorders")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.CancelBackordersReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CommitReserves provides a mock function with given fields: ctx, req
 func (_m *StockService) CommitReserves(ctx context.Context, req payload.CommitReservesReq) error {
 	ret := _m.Called(ctx, req)
@@ -33,6 +56,34 @@ func (_m *StockService) CommitReserves(ctx context.Context, req payload.CommitRe
 	return r0
 }
 
+// CreateBin provides a mock function with given fields: ctx, req
+func (_m *StockService) CreateBin(ctx context.Context, req payload.CreateBinReq) (model.WarehouseBin, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBin")
+	}
+
+	var r0 model.WarehouseBin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.CreateBinReq) (model.WarehouseBin, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.CreateBinReq) model.WarehouseBin); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(model.WarehouseBin)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.CreateBinReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateStock provides a mock function with given fields: ctx, req
 func (_m *StockService) CreateStock(ctx context.Context, req payload.CreateStockReq) error {
 	ret := _m.Called(ctx, req)
@@ -51,6 +102,186 @@ func (_m *StockService) CreateStock(ctx context.Context, req payload.CreateStock
 	return r0
 }
 
+// GetBackorders provides a mock function with given fields: ctx, req
+func (_m *StockService) GetBackorders(ctx context.Context, req payload.GetBackordersReq) ([]model.Backorder, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBackorders")
+	}
+
+	var r0 []model.Backorder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetBackordersReq) ([]model.Backorder, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetBackordersReq) []model.Backorder); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Backorder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetBackordersReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBinStocks provides a mock function with given fields: ctx, req
+func (_m *StockService) GetBinStocks(ctx context.Context, req payload.GetBinStocksReq) ([]model.BinStock, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBinStocks")
+	}
+
+	var r0 []model.BinStock
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetBinStocksReq) ([]model.BinStock, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetBinStocksReq) []model.BinStock); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BinStock)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetBinStocksReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBins provides a mock function with given fields: ctx, req
+func (_m *StockService) GetBins(ctx context.Context, req payload.GetBinsReq) ([]model.WarehouseBin, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBins")
+	}
+
+	var r0 []model.WarehouseBin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetBinsReq) ([]model.WarehouseBin, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetBinsReq) []model.WarehouseBin); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.WarehouseBin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetBinsReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExpiringStockLots provides a mock function with given fields: ctx, req
+func (_m *StockService) GetExpiringStockLots(ctx context.Context, req payload.GetExpiringStockLotsReq) ([]payload.ExpiringStockLot, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExpiringStockLots")
+	}
+
+	var r0 []payload.ExpiringStockLot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetExpiringStockLotsReq) ([]payload.ExpiringStockLot, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetExpiringStockLotsReq) []payload.ExpiringStockLot); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]payload.ExpiringStockLot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetExpiringStockLotsReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPickLists provides a mock function with given fields: ctx, req
+func (_m *StockService) GetPickLists(ctx context.Context, req payload.GetPickListsReq) ([]model.PickList, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPickLists")
+	}
+
+	var r0 []model.PickList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetPickListsReq) ([]model.PickList, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetPickListsReq) []model.PickList); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.PickList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetPickListsReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStockAlerts provides a mock function with given fields: ctx, req
+func (_m *StockService) GetStockAlerts(ctx context.Context, req payload.GetStockAlertsReq) ([]model.StockAlert, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStockAlerts")
+	}
+
+	var r0 []model.StockAlert
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetStockAlertsReq) ([]model.StockAlert, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetStockAlertsReq) []model.StockAlert); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StockAlert)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetStockAlertsReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStockAvailablesByProduct provides a mock function with given fields: ctx, req
 func (_m *StockService) GetStockAvailablesByProduct(ctx context.Context, req payload.GetStockAvailablesByProductReq) ([]model.GetStockAvailablesByProduct, error) {
 	ret := _m.Called(ctx, req)
@@ -81,6 +312,124 @@ func (_m *StockService) GetStockAvailablesByProduct(ctx context.Context, req pay
 	return r0, r1
 }
 
+// GetStockLots provides a mock function with given fields: ctx, req
+func (_m *StockService) GetStockLots(ctx context.Context, req payload.GetStockLotsReq) ([]model.StockLot, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStockLots")
+	}
+
+	var r0 []model.StockLot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetStockLotsReq) ([]model.StockLot, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetStockLotsReq) []model.StockLot); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StockLot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetStockLotsReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStockSerialHistory provides a mock function with given fields: ctx, serialNumber
+func (_m *StockService) GetStockSerialHistory(ctx context.Context, serialNumber string) ([]model.StockSerial, error) {
+	ret := _m.Called(ctx, serialNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStockSerialHistory")
+	}
+
+	var r0 []model.StockSerial
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]model.StockSerial, error)); ok {
+		return rf(ctx, serialNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []model.StockSerial); ok {
+		r0 = rf(ctx, serialNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StockSerial)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, serialNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStockSerials provides a mock function with given fields: ctx, req
+func (_m *StockService) GetStockSerials(ctx context.Context, req payload.GetStockSerialsReq) ([]model.StockSerial, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStockSerials")
+	}
+
+	var r0 []model.StockSerial
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetStockSerialsReq) ([]model.StockSerial, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetStockSerialsReq) []model.StockSerial); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StockSerial)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetStockSerialsReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStockSnapshot provides a mock function with given fields: ctx, req
+func (_m *StockService) GetStockSnapshot(ctx context.Context, req payload.GetStockSnapshotReq) (payload.StockSnapshotResp, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStockSnapshot")
+	}
+
+	var r0 payload.StockSnapshotResp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetStockSnapshotReq) (payload.StockSnapshotResp, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetStockSnapshotReq) payload.StockSnapshotResp); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(payload.StockSnapshotResp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetStockSnapshotReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStocks provides a mock function with given fields: ctx, req
 func (_m *StockService) GetStocks(ctx context.Context, req payload.GetStocksReq) ([]model.WarehouseStock, error) {
 	ret := _m.Called(ctx, req)
@@ -111,6 +460,111 @@ func (_m *StockService) GetStocks(ctx context.Context, req payload.GetStocksReq)
 	return r0, r1
 }
 
+// ImportStocks provides a mock function with given fields: ctx, req, file
+func (_m *StockService) ImportStocks(ctx context.Context, req payload.ImportStocksReq, file io.Reader) (payload.ImportStocksResult, error) {
+	ret := _m.Called(ctx, req, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportStocks")
+	}
+
+	var r0 payload.ImportStocksResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.ImportStocksReq, io.Reader) (payload.ImportStocksResult, error)); ok {
+		return rf(ctx, req, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.ImportStocksReq, io.Reader) payload.ImportStocksResult); ok {
+		r0 = rf(ctx, req, file)
+	} else {
+		r0 = ret.Get(0).(payload.ImportStocksResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.ImportStocksReq, io.Reader) error); ok {
+		r1 = rf(ctx, req, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveBinStock provides a mock function with given fields: ctx, req
+func (_m *StockService) MoveBinStock(ctx context.Context, req payload.MoveBinStockReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveBinStock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.MoveBinStockReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishStockChanges provides a mock function with given fields: ctx, productIDs, alerts
+func (_m *StockService) PublishStockChanges(ctx context.Context, productIDs []string, alerts []model.StockAlert) {
+	_m.Called(ctx, productIDs, alerts)
+}
+
+// PutAwayStock provides a mock function with given fields: ctx, req
+func (_m *StockService) PutAwayStock(ctx context.Context, req payload.PutAwayStockReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutAwayStock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.PutAwayStockReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReceiveStockLot provides a mock function with given fields: ctx, req
+func (_m *StockService) ReceiveStockLot(ctx context.Context, req payload.ReceiveStockLotReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReceiveStockLot")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.ReceiveStockLotReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReceiveStockSerials provides a mock function with given fields: ctx, req
+func (_m *StockService) ReceiveStockSerials(ctx context.Context, req payload.ReceiveStockSerialsReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReceiveStockSerials")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.ReceiveStockSerialsReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ReserveStocks provides a mock function with given fields: ctx, req
 func (_m *StockService) ReserveStocks(ctx context.Context, req payload.ReserveStocksReq) ([]payload.ReserveStocksResp, error) {
 	ret := _m.Called(ctx, req)
@@ -141,6 +595,24 @@ func (_m *StockService) ReserveStocks(ctx context.Context, req payload.ReserveSt
 	return r0, r1
 }
 
+// ReturnStockSerials provides a mock function with given fields: ctx, req
+func (_m *StockService) ReturnStockSerials(ctx context.Context, req payload.ReturnStockSerialsReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReturnStockSerials")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.ReturnStockSerialsReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RollbackReserves provides a mock function with given fields: ctx, req
 func (_m *StockService) RollbackReserves(ctx context.Context, req payload.RollbackReservesReq) error {
 	ret := _m.Called(ctx, req)
@@ -159,6 +631,138 @@ func (_m *StockService) RollbackReserves(ctx context.Context, req payload.Rollba
 	return r0
 }
 
+// SetBackorderProduct provides a mock function with given fields: ctx, req
+func (_m *StockService) SetBackorderProduct(ctx context.Context, req payload.SetBackorderProductReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetBackorderProduct")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.SetBackorderProductReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSerializedProduct provides a mock function with given fields: ctx, req
+func (_m *StockService) SetSerializedProduct(ctx context.Context, req payload.SetSerializedProductReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSerializedProduct")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.SetSerializedProductReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetStockThreshold provides a mock function with given fields: ctx, req
+func (_m *StockService) SetStockThreshold(ctx context.Context, req payload.SetStockThresholdReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetStockThreshold")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.SetStockThresholdReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SettleStockChanges provides a mock function with given fields: ctx, tx, productIDs
+func (_m *StockService) SettleStockChanges(ctx context.Context, tx *gorm.DB, productIDs []string) ([]model.StockAlert, error) {
+	ret := _m.Called(ctx, tx, productIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SettleStockChanges")
+	}
+
+	var r0 []model.StockAlert
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, []string) ([]model.StockAlert, error)); ok {
+		return rf(ctx, tx, productIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *gorm.DB, []string) []model.StockAlert); ok {
+		r0 = rf(ctx, tx, productIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StockAlert)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *gorm.DB, []string) error); ok {
+		r1 = rf(ctx, tx, productIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubscribeStockAvailability provides a mock function with given fields: ctx, req
+func (_m *StockService) SubscribeStockAvailability(ctx context.Context, req payload.StreamStockAvailabilityReq) (<-chan payload.StockAvailabilityEvent, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeStockAvailability")
+	}
+
+	var r0 <-chan payload.StockAvailabilityEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.StreamStockAvailabilityReq) (<-chan payload.StockAvailabilityEvent, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.StreamStockAvailabilityReq) <-chan payload.StockAvailabilityEvent); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan payload.StockAvailabilityEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.StreamStockAvailabilityReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TakeStockSnapshot provides a mock function with given fields: ctx
+func (_m *StockService) TakeStockSnapshot(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TakeStockSnapshot")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // TransferStock provides a mock function with given fields: ctx, req
 func (_m *StockService) TransferStock(ctx context.Context, req payload.TransferStockReq) error {
 	ret := _m.Called(ctx, req)
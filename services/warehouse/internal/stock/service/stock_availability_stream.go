@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// availabilityPollInterval is how often the availability of the watched
+	// products is read back from the database
+	availabilityPollInterval = time.Second
+	// availabilityBuffer is how many events a subscriber may fall behind before
+	// it is dropped and has to reconnect
+	availabilityBuffer = 64
+)
+
+// availabilityBroker fans out available stock changes to the stream
+// subscribers of this instance. Changes can be made through any instance, so
+// the availability is polled from the database by a single poller, which
+// keeps the events of a product in the order they were read.
+type availabilityBroker struct {
+	mu          sync.Mutex
+	subscribers map[*availabilitySubscriber]struct{}
+	// published is the availability last sent out per watched product
+	published map[uuid.UUID]int
+	// nudge wakes the poller up early after a change made on this instance
+	nudge   chan struct{}
+	polling sync.Once
+}
+
+type availabilitySubscriber struct {
+	productIDs map[uuid.UUID]bool
+	events     chan payload.StockAvailabilityEvent
+}
+
+func newAvailabilityBroker() *availabilityBroker {
+	return &availabilityBroker{
+		subscribers: make(map[*availabilitySubscriber]struct{}),
+		published:   make(map[uuid.UUID]int),
+		nudge:       make(chan struct{}, 1),
+	}
+}
+
+// watched returns every product that has at least one subscriber.
+func (b *availabilityBroker) watched() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	seen := make(map[uuid.UUID]bool)
+	var watched []string
+	for sub := range b.subscribers {
+		for productID := range sub.productIDs {
+			if !seen[productID] {
+				seen[productID] = true
+				watched = append(watched, productID.String())
+			}
+		}
+	}
+	return watched
+}
+
+// watching reports whether any of the products has a subscriber.
+func (b *availabilityBroker) watching(productIDs []string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, productID := range productIDs {
+		id, err := uuid.Parse(productID)
+		if err != nil {
+			continue
+		}
+		for sub := range b.subscribers {
+			if sub.productIDs[id] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// notify wakes the poller up without waiting for it.
+func (b *availabilityBroker) notify() {
+	select {
+	case b.nudge <- struct{}{}:
+	default:
+	}
+}
+
+// publish sends the availability of each product that changed since it was
+// last published to its subscribers as of the stock movement eventID, and
+// forgets the products nobody watches anymore. A subscriber that cannot keep
+// up is dropped.
+func (b *availabilityBroker) publish(eventID int64, productIDs []string, availables []model.GetStockAvailablesByProduct) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	watched := make(map[uuid.UUID]bool, len(productIDs))
+	for _, productID := range productIDs {
+		if id, err := uuid.Parse(productID); err == nil {
+			watched[id] = true
+		}
+	}
+	for productID := range b.published {
+		if !watched[productID] {
+			delete(b.published, productID)
+		}
+	}
+
+	for _, available := range availables {
+		if last, ok := b.published[available.ProductID]; ok && last == available.AvailableStock {
+			continue
+		}
+		b.published[available.ProductID] = available.AvailableStock
+
+		event := payload.StockAvailabilityEvent{
+			ID:             eventID,
+			ProductID:      available.ProductID,
+			AvailableStock: available.AvailableStock,
+		}
+		for sub := range b.subscribers {
+			if !sub.productIDs[event.ProductID] {
+				continue
+			}
+			select {
+			case sub.events <- event:
+			default:
+				b.remove(sub)
+			}
+		}
+	}
+}
+
+// subscribe registers a subscriber for the products.
+func (b *availabilityBroker) subscribe(productIDs []uuid.UUID) *availabilitySubscriber {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &availabilitySubscriber{
+		productIDs: make(map[uuid.UUID]bool, len(productIDs)),
+		events:     make(chan payload.StockAvailabilityEvent, availabilityBuffer),
+	}
+	for _, productID := range productIDs {
+		sub.productIDs[productID] = true
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (b *availabilityBroker) unsubscribe(sub *availabilitySubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(sub)
+}
+
+func (b *availabilityBroker) remove(sub *availabilitySubscriber) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
+
+// SubscribeStockAvailability streams the available stock of the products
+// until ctx is done, starting with the current availability. Event ids are
+// stock movement ids, a stream resumed after LastEventID starts with the
+// current availability of only the products whose stock moved after it. The
+// channel is closed when the subscriber falls behind.
+func (s *stockService) SubscribeStockAvailability(ctx context.Context, req payload.StreamStockAvailabilityReq) (result <-chan payload.StockAvailabilityEvent, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.SubscribeStockAvailability")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	productIDs := make([]uuid.UUID, 0, len(req.ProductIDIN))
+	for _, productID := range req.ProductIDIN {
+		id, err := uuid.Parse(productID)
+		if err != nil {
+			return nil, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid product id "+productID)
+		}
+		productIDs = append(productIDs, id)
+	}
+
+	s.availability.polling.Do(func() {
+		go s.pollStockAvailability(context.Background())
+	})
+
+	// subscribe before reading the current availability, so a change made in
+	// between is still polled and sent after it
+	sub := s.availability.subscribe(productIDs)
+	initial, err := s.initialStockAvailability(ctx, req)
+	if err != nil {
+		s.availability.unsubscribe(sub)
+		return nil, err
+	}
+
+	events := make(chan payload.StockAvailabilityEvent)
+	go func() {
+		defer close(events)
+		defer s.availability.unsubscribe(sub)
+
+		for _, event := range initial {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		for {
+			select {
+			case event, ok := <-sub.events:
+				if !ok {
+					return
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// initialStockAvailability reads the availability a stream starts with, as of
+// the latest stock movement. The movement id is read first, so the
+// availability already holds every movement up to it.
+func (s *stockService) initialStockAvailability(ctx context.Context, req payload.StreamStockAvailabilityReq) ([]payload.StockAvailabilityEvent, error) {
+	eventID, err := s.stockSnapshotRepo.GetLatestStockMovementID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	productIDs := req.ProductIDIN
+	if req.LastEventID > 0 {
+		productIDs, err = s.stockSnapshotRepo.GetMovedProductIDs(ctx, req.LastEventID, req.ProductIDIN)
+		if err != nil {
+			return nil, err
+		}
+		if len(productIDs) == 0 {
+			return nil, nil
+		}
+	}
+
+	availables, err := s.stockRepo.GetAvailableStocksByProduct(ctx, payload.GetStockAvailablesByProductReq{
+		ProductIDIN: productIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var initial []payload.StockAvailabilityEvent
+	for _, available := range withMissingAvailables(productIDs, availables) {
+		initial = append(initial, payload.StockAvailabilityEvent{
+			ID:             eventID,
+			ProductID:      available.ProductID,
+			AvailableStock: available.AvailableStock,
+		})
+	}
+	return initial, nil
+}
+
+// publishStockAvailability wakes the availability poller up after a stock
+// mutation is committed, so subscribers of this instance hear of it right away.
+// Subscribers of other instances get it on their next poll.
+func (s *stockService) publishStockAvailability(ctx context.Context, productIDs []string) {
+	if s.availability.watching(productIDs) {
+		s.availability.notify()
+	}
+}
+
+// pollStockAvailability reads the availability of the watched products every
+// poll interval, or sooner when nudged, and publishes what changed. It runs
+// for the life of the instance once the first stream is opened.
+func (s *stockService) pollStockAvailability(ctx context.Context) {
+	ticker := time.NewTicker(availabilityPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.availability.nudge:
+		}
+
+		s.refreshStockAvailability(ctx)
+	}
+}
+
+// refreshStockAvailability reads and publishes the availability of the
+// watched products once, a failure is only logged.
+func (s *stockService) refreshStockAvailability(ctx context.Context) {
+	productIDs := s.availability.watched()
+	if len(productIDs) == 0 {
+		return
+	}
+
+	// the movement id is read first, so the availability already holds every
+	// movement up to it
+	eventID, err := s.stockSnapshotRepo.GetLatestStockMovementID(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorw("Failed to poll stock availability", "error", err)
+		return
+	}
+
+	availables, err := s.stockRepo.GetAvailableStocksByProduct(ctx, payload.GetStockAvailablesByProductReq{
+		ProductIDIN: productIDs,
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Errorw("Failed to poll stock availability", "error", err)
+		return
+	}
+
+	s.availability.publish(eventID, productIDs, withMissingAvailables(productIDs, availables))
+}
+
+// withMissingAvailables adds the products without any stock as unavailable.
+func withMissingAvailables(productIDs []string, availables []model.GetStockAvailablesByProduct) []model.GetStockAvailablesByProduct {
+	found := make(map[uuid.UUID]bool, len(availables))
+	for _, available := range availables {
+		found[available.ProductID] = true
+	}
+
+	for _, productID := range productIDs {
+		id, err := uuid.Parse(productID)
+		if err != nil || found[id] {
+			continue
+		}
+		found[id] = true
+		availables = append(availables, model.GetStockAvailablesByProduct{ProductID: id})
+	}
+	return availables
+}
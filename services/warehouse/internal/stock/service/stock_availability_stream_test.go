@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	stockRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository/mocks"
+)
+
+func receiveAvailabilityEvents(t *testing.T, events <-chan payload.StockAvailabilityEvent, n int) []payload.StockAvailabilityEvent {
+	t.Helper()
+
+	var received []payload.StockAvailabilityEvent
+	for len(received) < n {
+		select {
+		case event := <-events:
+			received = append(received, event)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d of %d availability events", len(received), n)
+		}
+	}
+	return received
+}
+
+// newDrivenAvailabilityBroker returns a broker whose poller is never started,
+// the test refreshes the availability itself.
+func newDrivenAvailabilityBroker() *availabilityBroker {
+	broker := newAvailabilityBroker()
+	broker.polling.Do(func() {})
+	return broker
+}
+
+func TestSubscribeStockAvailability(t *testing.T) {
+	productA := uuid.New()
+	productB := uuid.New()
+
+	tests := []struct {
+		name        string
+		productIDIN []string
+		lastEventID int64
+		setup       func(stockRepo *stockRepoMock.StockRepository, stockSnapshotRepo *stockRepoMock.StockSnapshotRepository)
+		want        []payload.StockAvailabilityEvent
+		wantErr     bool
+	}{
+		{
+			name:        "success - stream starts with current availability",
+			productIDIN: []string{productA.String(), productB.String()},
+			setup: func(stockRepo *stockRepoMock.StockRepository, stockSnapshotRepo *stockRepoMock.StockSnapshotRepository) {
+				stockSnapshotRepo.On("GetLatestStockMovementID", mock.Anything).Return(int64(9), nil)
+				stockRepo.On("GetAvailableStocksByProduct", mock.Anything, payload.GetStockAvailablesByProductReq{
+					ProductIDIN: []string{productA.String(), productB.String()},
+				}).
+					Return([]model.GetStockAvailablesByProduct{{ProductID: productA, AvailableStock: 5}}, nil)
+			},
+			want: []payload.StockAvailabilityEvent{
+				{ID: 9, ProductID: productA, AvailableStock: 5},
+				{ID: 9, ProductID: productB, AvailableStock: 0},
+			},
+		},
+		{
+			name:        "success - resumed stream replays the products moved after the last event",
+			productIDIN: []string{productA.String(), productB.String()},
+			lastEventID: 4,
+			setup: func(stockRepo *stockRepoMock.StockRepository, stockSnapshotRepo *stockRepoMock.StockSnapshotRepository) {
+				stockSnapshotRepo.On("GetLatestStockMovementID", mock.Anything).Return(int64(9), nil)
+				stockSnapshotRepo.On("GetMovedProductIDs", mock.Anything, int64(4), []string{productA.String(), productB.String()}).
+					Return([]string{productB.String()}, nil)
+				stockRepo.On("GetAvailableStocksByProduct", mock.Anything, payload.GetStockAvailablesByProductReq{
+					ProductIDIN: []string{productB.String()},
+				}).
+					Return([]model.GetStockAvailablesByProduct{{ProductID: productB, AvailableStock: 3}}, nil)
+			},
+			want: []payload.StockAvailabilityEvent{
+				{ID: 9, ProductID: productB, AvailableStock: 3},
+			},
+		},
+		{
+			name:        "success - resumed stream without moves replays nothing",
+			productIDIN: []string{productA.String()},
+			lastEventID: 9,
+			setup: func(stockRepo *stockRepoMock.StockRepository, stockSnapshotRepo *stockRepoMock.StockSnapshotRepository) {
+				stockSnapshotRepo.On("GetLatestStockMovementID", mock.Anything).Return(int64(9), nil)
+				stockSnapshotRepo.On("GetMovedProductIDs", mock.Anything, int64(9), []string{productA.String()}).
+					Return([]string{}, nil)
+			},
+		},
+		{
+			name:        "error - invalid product id",
+			productIDIN: []string{"not-a-uuid"},
+			setup: func(stockRepo *stockRepoMock.StockRepository, stockSnapshotRepo *stockRepoMock.StockSnapshotRepository) {
+			},
+			wantErr: true,
+		},
+		{
+			name:        "error - failed to get latest stock movement",
+			productIDIN: []string{productA.String()},
+			setup: func(stockRepo *stockRepoMock.StockRepository, stockSnapshotRepo *stockRepoMock.StockSnapshotRepository) {
+				stockSnapshotRepo.On("GetLatestStockMovementID", mock.Anything).Return(int64(0), assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name:        "error - failed to get moved products",
+			productIDIN: []string{productA.String()},
+			lastEventID: 4,
+			setup: func(stockRepo *stockRepoMock.StockRepository, stockSnapshotRepo *stockRepoMock.StockSnapshotRepository) {
+				stockSnapshotRepo.On("GetLatestStockMovementID", mock.Anything).Return(int64(9), nil)
+				stockSnapshotRepo.On("GetMovedProductIDs", mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, assert.AnError)
+			},
+			wantErr: true,
+		},
+		{
+			name:        "error - failed to get current availability",
+			productIDIN: []string{productA.String()},
+			setup: func(stockRepo *stockRepoMock.StockRepository, stockSnapshotRepo *stockRepoMock.StockSnapshotRepository) {
+				stockSnapshotRepo.On("GetLatestStockMovementID", mock.Anything).Return(int64(9), nil)
+				stockRepo.On("GetAvailableStocksByProduct", mock.Anything, mock.Anything).
+					Return(nil, assert.AnError)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			stockRepo := stockRepoMock.NewStockRepository(t)
+			stockSnapshotRepo := stockRepoMock.NewStockSnapshotRepository(t)
+			stockSvc := stockService{
+				logger:            pkg.InitLogger(&config.Config{}),
+				stockRepo:         stockRepo,
+				stockSnapshotRepo: stockSnapshotRepo,
+				availability:      newDrivenAvailabilityBroker(),
+			}
+			tt.setup(stockRepo, stockSnapshotRepo)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// When
+			events, err := stockSvc.SubscribeStockAvailability(ctx, payload.StreamStockAvailabilityReq{
+				ProductIDIN: tt.productIDIN,
+				LastEventID: tt.lastEventID,
+			})
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, stockSvc.availability.watched())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, receiveAvailabilityEvents(t, events, len(tt.want)))
+		})
+	}
+}
+
+func TestRefreshStockAvailability(t *testing.T) {
+	productA := uuid.New()
+	productB := uuid.New()
+
+	// Given
+	stockRepo := stockRepoMock.NewStockRepository(t)
+	stockSnapshotRepo := stockRepoMock.NewStockSnapshotRepository(t)
+	stockSvc := stockService{
+		logger:            pkg.InitLogger(&config.Config{}),
+		stockRepo:         stockRepo,
+		stockSnapshotRepo: stockSnapshotRepo,
+		availability:      newDrivenAvailabilityBroker(),
+	}
+
+	req := payload.GetStockAvailablesByProductReq{ProductIDIN: []string{productA.String()}}
+	stockSnapshotRepo.On("GetLatestStockMovementID", mock.Anything).Return(int64(1), nil).Once()
+	stockSnapshotRepo.On("GetLatestStockMovementID", mock.Anything).Return(int64(2), nil).Twice()
+	stockSnapshotRepo.On("GetLatestStockMovementID", mock.Anything).Return(int64(3), nil).Once()
+	stockRepo.On("GetAvailableStocksByProduct", mock.Anything, req).
+		Return([]model.GetStockAvailablesByProduct{}, nil).Once()
+	stockRepo.On("GetAvailableStocksByProduct", mock.Anything, req).
+		Return([]model.GetStockAvailablesByProduct{{ProductID: productA, AvailableStock: 2}}, nil).Twice()
+	stockRepo.On("GetAvailableStocksByProduct", mock.Anything, req).
+		Return([]model.GetStockAvailablesByProduct{{ProductID: productA, AvailableStock: 1}}, nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := stockSvc.SubscribeStockAvailability(ctx, payload.StreamStockAvailabilityReq{
+		ProductIDIN: []string{productA.String()},
+	})
+	assert.NoError(t, err)
+
+	// When
+	stockSvc.publishStockAvailability(ctx, []string{productB.String()})
+	assert.Len(t, stockSvc.availability.nudge, 0)
+	stockSvc.publishStockAvailability(ctx, []string{productA.String(), productB.String()})
+	assert.Len(t, stockSvc.availability.nudge, 1)
+
+	// the second read is unchanged and not sent again
+	stockSvc.refreshStockAvailability(ctx)
+	stockSvc.refreshStockAvailability(ctx)
+	stockSvc.refreshStockAvailability(ctx)
+
+	// Then
+	assert.Equal(t, []payload.StockAvailabilityEvent{
+		{ID: 1, ProductID: productA, AvailableStock: 0},
+		{ID: 2, ProductID: productA, AvailableStock: 2},
+		{ID: 3, ProductID: productA, AvailableStock: 1},
+	}, receiveAvailabilityEvents(t, events, 3))
+
+	cancel()
+	for range events {
+	}
+	assert.Empty(t, stockSvc.availability.watched())
+
+	// products nobody watches anymore are forgotten on the next publish
+	stockSvc.availability.publish(0, nil, nil)
+	assert.Empty(t, stockSvc.availability.published)
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func (s *stockService) CreateBin(ctx context.Context, req payload.CreateBinReq) (result model.WarehouseBin, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.CreateBin")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	warehouses, err := s.warehouseRepo.GetWarehousesByIDs(ctx, []string{req.WarehouseID.String()})
+	if err != nil {
+		return model.WarehouseBin{}, err
+	}
+	if len(warehouses) == 0 {
+		return model.WarehouseBin{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "warehouse not found")
+	}
+	if warehouses[0].Status == constant.WarehouseStatusArchived {
+		return model.WarehouseBin{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse is archived")
+	}
+
+	bins, err := s.stockBinRepo.GetBins(ctx, payload.GetBinsReq{
+		WarehouseIDIN: []string{req.WarehouseID.String()},
+		CodeIN:        []string{req.Code},
+	})
+	if err != nil {
+		return model.WarehouseBin{}, err
+	}
+	if len(bins) > 0 {
+		return model.WarehouseBin{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "bin "+req.Code+" already exists")
+	}
+
+	bin := model.WarehouseBin{
+		WarehouseID: req.WarehouseID,
+		Zone:        req.Zone,
+		Code:        req.Code,
+	}
+	if err := s.stockBinRepo.CreateBin(ctx, &bin); err != nil {
+		return model.WarehouseBin{}, err
+	}
+
+	return bin, nil
+}
+
+func (s *stockService) GetBins(ctx context.Context, req payload.GetBinsReq) (result []model.WarehouseBin, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.GetBins")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	return s.stockBinRepo.GetBins(ctx, req)
+}
+
+func (s *stockService) GetBinStocks(ctx context.Context, req payload.GetBinStocksReq) (result []model.BinStock, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.GetBinStocks")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	return s.stockBinRepo.GetBinStocks(ctx, req)
+}
+
+// PutAwayStock shelves quantity of the warehouse stock in the bin. Only the
+// quantity on hand that is not in a bin yet can be put away.
+func (s *stockService) PutAwayStock(ctx context.Context, req payload.PutAwayStockReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.PutAwayStock")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	bins, err := s.stockBinRepo.WithTX(tx).GetBins(ctx, payload.GetBinsReq{
+		IDIN: []string{req.BinID.String()},
+	})
+	if err != nil {
+		return err
+	}
+	if len(bins) == 0 {
+		return apperr.NewWithCode(apperr.CodeHTTPNotFound, "bin not found")
+	}
+	warehouseID := bins[0].WarehouseID.String()
+
+	stocks, err := s.stockRepo.WithTX(tx).WithLockForUpdate().GetStocks(ctx, payload.GetStocksReq{
+		WarehouseIDIN: []string{warehouseID},
+		ProductIDIN:   []string{req.ProductID.String()},
+	})
+	if err != nil {
+		return err
+	}
+	if len(stocks) == 0 {
+		return apperr.NewWithCode(apperr.CodeHTTPNotFound, "stock not found")
+	}
+
+	binStocks, err := s.stockBinRepo.WithTX(tx).GetBinStocks(ctx, payload.GetBinStocksReq{
+		WarehouseIDIN: []string{warehouseID},
+		ProductIDIN:   []string{req.ProductID.String()},
+	})
+	if err != nil {
+		return err
+	}
+
+	unbinned := stocks[0].Quantity
+	for _, binStock := range binStocks {
+		unbinned -= binStock.Quantity
+	}
+	if unbinned < req.Quantity {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "only "+strconv.Itoa(max(unbinned, 0))+" units are not in a bin")
+	}
+
+	err = s.stockBinRepo.WithTX(tx).AddBinStockQty(ctx, req.BinID.String(), warehouseID, req.ProductID.String(), req.Quantity)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// MoveBinStock moves quantity of a product between two bins of a warehouse.
+func (s *stockService) MoveBinStock(ctx context.Context, req payload.MoveBinStockReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.MoveBinStock")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if req.FromBinID == req.ToBinID {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "cannot move stock to the same bin")
+	}
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	bins, err := s.stockBinRepo.WithTX(tx).GetBins(ctx, payload.GetBinsReq{
+		IDIN: []string{req.FromBinID.String(), req.ToBinID.String()},
+	})
+	if err != nil {
+		return err
+	}
+	if len(bins) != 2 {
+		return apperr.NewWithCode(apperr.CodeHTTPNotFound, "bin not found")
+	}
+	if bins[0].WarehouseID != bins[1].WarehouseID {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "cannot move stock between bins of different warehouses")
+	}
+	warehouseID := bins[0].WarehouseID.String()
+
+	binStocks, err := s.stockBinRepo.WithTX(tx).WithLockForUpdate().GetBinStocks(ctx, payload.GetBinStocksReq{
+		ProductIDIN: []string{req.ProductID.String()},
+		BinIDIN:     []string{req.FromBinID.String()},
+	})
+	if err != nil {
+		return err
+	}
+	if len(binStocks) == 0 || binStocks[0].Quantity < req.Quantity {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "insufficient stock in bin")
+	}
+
+	err = s.stockBinRepo.WithTX(tx).AddBinStockQty(ctx, req.FromBinID.String(), warehouseID, req.ProductID.String(), -req.Quantity)
+	if err != nil {
+		return err
+	}
+
+	err = s.stockBinRepo.WithTX(tx).AddBinStockQty(ctx, req.ToBinID.String(), warehouseID, req.ProductID.String(), req.Quantity)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+func (s *stockService) GetPickLists(ctx context.Context, req payload.GetPickListsReq) (result []model.PickList, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.GetPickLists")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	return s.stockBinRepo.GetPickLists(ctx, req)
+}
+
+// groupBinStocks indexes the bin stocks by warehouse stock, keeping their order.
+func groupBinStocks(binStocks []model.BinStock) map[stockKey][]*model.BinStock {
+	grouped := make(map[stockKey][]*model.BinStock)
+	for i := range binStocks {
+		key := stockKey{warehouseID: binStocks[i].WarehouseID.String(), productID: binStocks[i].ProductID.String()}
+		grouped[key] = append(grouped[key], &binStocks[i])
+	}
+	return grouped
+}
+
+// pickBinStocks takes the quantity from the bins in picking order. What is
+// left is picked from stock that is not in a bin, listed without a bin.
+func pickBinStocks(productID uuid.UUID, binStocks []*model.BinStock, quantity int) []model.PickListItem {
+	var items []model.PickListItem
+	for _, binStock := range binStocks {
+		if quantity == 0 {
+			break
+		}
+
+		qty := min(quantity, binStock.Quantity)
+		if qty <= 0 {
+			continue
+		}
+
+		binStock.Quantity -= qty
+		quantity -= qty
+		items = append(items, model.PickListItem{
+			ProductID: binStock.ProductID,
+			BinID:     &binStock.BinID,
+			Zone:      &binStock.Zone,
+			BinCode:   &binStock.BinCode,
+			Quantity:  qty,
+		})
+	}
+
+	if quantity > 0 {
+		items = append(items, model.PickListItem{
+			ProductID: productID,
+			Quantity:  quantity,
+		})
+	}
+	return items
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	stockRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository/mocks"
+	warehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository/mocks"
+)
+
+func TestCreateBin(t *testing.T) {
+	warehouseID := uuid.New()
+	req := payload.CreateBinReq{WarehouseID: warehouseID, Zone: "A", Code: "A-01"}
+
+	tests := []struct {
+		name    string
+		setup   func(binRepo *stockRepoMock.StockBinRepository, warehouseRepo *warehouseRepoMock.WarehouseRepository)
+		wantErr bool
+	}{
+		{
+			name: "success - create bin",
+			setup: func(binRepo *stockRepoMock.StockBinRepository, warehouseRepo *warehouseRepoMock.WarehouseRepository) {
+				warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseID.String()}).
+					Return([]model.Warehouse{{ID: warehouseID, Status: constant.WarehouseStatusActive}}, nil)
+				binRepo.On("GetBins", mock.Anything, payload.GetBinsReq{
+					WarehouseIDIN: []string{warehouseID.String()},
+					CodeIN:        []string{"A-01"},
+				}).
+					Return([]model.WarehouseBin{}, nil)
+				binRepo.On("CreateBin", mock.Anything, &model.WarehouseBin{WarehouseID: warehouseID, Zone: "A", Code: "A-01"}).
+					Return(nil)
+			},
+		},
+		{
+			name: "error - warehouse is archived",
+			setup: func(binRepo *stockRepoMock.StockBinRepository, warehouseRepo *warehouseRepoMock.WarehouseRepository) {
+				warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseID.String()}).
+					Return([]model.Warehouse{{ID: warehouseID, Status: constant.WarehouseStatusArchived}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - bin already exists",
+			setup: func(binRepo *stockRepoMock.StockBinRepository, warehouseRepo *warehouseRepoMock.WarehouseRepository) {
+				warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseID.String()}).
+					Return([]model.Warehouse{{ID: warehouseID, Status: constant.WarehouseStatusActive}}, nil)
+				binRepo.On("GetBins", mock.Anything, mock.Anything).
+					Return([]model.WarehouseBin{{ID: uuid.New(), WarehouseID: warehouseID, Code: "A-01"}}, nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			stockBinRepo := stockRepoMock.NewStockBinRepository(t)
+			warehouseRepo := warehouseRepoMock.NewWarehouseRepository(t)
+			stockSvc := stockService{
+				logger:        pkg.InitLogger(&config.Config{}),
+				stockBinRepo:  stockBinRepo,
+				warehouseRepo: warehouseRepo,
+			}
+
+			tt.setup(stockBinRepo, warehouseRepo)
+
+			// When
+			bin, err := stockSvc.CreateBin(context.Background(), req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "A-01", bin.Code)
+		})
+	}
+}
+
+func TestPutAwayStock(t *testing.T) {
+	type dependencyMocks struct {
+		db           sqlmock.Sqlmock
+		stockRepo    *stockRepoMock.StockRepository
+		stockBinRepo *stockRepoMock.StockBinRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	binID := uuid.New()
+	req := payload.PutAwayStockReq{BinID: binID, ProductID: productID, Quantity: 5}
+
+	expectStock := func(m dependencyMocks, quantity int, binStocks []model.BinStock) {
+		m.stockBinRepo.On("WithTX", mock.Anything).
+			Return(m.stockBinRepo)
+		m.stockBinRepo.On("GetBins", mock.Anything, payload.GetBinsReq{IDIN: []string{binID.String()}}).
+			Return([]model.WarehouseBin{{ID: binID, WarehouseID: warehouseID, Zone: "A", Code: "A-01"}}, nil)
+
+		m.stockRepo.On("WithTX", mock.Anything).
+			Return(m.stockRepo)
+		m.stockRepo.On("WithLockForUpdate").
+			Return(m.stockRepo)
+		m.stockRepo.On("GetStocks", mock.Anything, payload.GetStocksReq{
+			WarehouseIDIN: []string{warehouseID.String()},
+			ProductIDIN:   []string{productID.String()},
+		}).
+			Return([]model.WarehouseStock{{WarehouseID: warehouseID, ProductID: productID, Quantity: quantity}}, nil)
+
+		m.stockBinRepo.On("GetBinStocks", mock.Anything, payload.GetBinStocksReq{
+			WarehouseIDIN: []string{warehouseID.String()},
+			ProductIDIN:   []string{productID.String()},
+		}).
+			Return(binStocks, nil)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - put away unbinned stock",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectStock(m, 12, []model.BinStock{{BinID: uuid.New(), Quantity: 7}})
+				m.stockBinRepo.On("AddBinStockQty", mock.Anything, binID.String(), warehouseID.String(), productID.String(), 5).
+					Return(nil)
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - not enough unbinned stock",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectStock(m, 10, []model.BinStock{{BinID: uuid.New(), Quantity: 7}})
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:           mockDb.Mock,
+				stockRepo:    stockRepoMock.NewStockRepository(t),
+				stockBinRepo: stockRepoMock.NewStockBinRepository(t),
+			}
+			stockSvc := stockService{
+				logger:        pkg.InitLogger(&config.Config{}),
+				db:            mockDb.Db,
+				backorderRepo: noBackorders(t),
+				stockRepo:     mocks.stockRepo,
+				stockBinRepo:  mocks.stockBinRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := stockSvc.PutAwayStock(context.Background(), req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMoveBinStock(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	fromBinID, toBinID := uuid.New(), uuid.New()
+	req := payload.MoveBinStockReq{FromBinID: fromBinID, ToBinID: toBinID, ProductID: productID, Quantity: 3}
+
+	expectBins := func(m *stockRepoMock.StockBinRepository, toWarehouseID uuid.UUID) {
+		m.On("WithTX", mock.Anything).
+			Return(m)
+		m.On("GetBins", mock.Anything, payload.GetBinsReq{IDIN: []string{fromBinID.String(), toBinID.String()}}).
+			Return([]model.WarehouseBin{
+				{ID: fromBinID, WarehouseID: warehouseID, Code: "A-01"},
+				{ID: toBinID, WarehouseID: toWarehouseID, Code: "B-01"},
+			}, nil)
+	}
+
+	tests := []struct {
+		name    string
+		req     payload.MoveBinStockReq
+		setup   func(m *stockRepoMock.StockBinRepository)
+		wantErr bool
+	}{
+		{
+			name: "success - move between bins",
+			req:  req,
+			setup: func(m *stockRepoMock.StockBinRepository) {
+				mockDb.Mock.ExpectBegin()
+				expectBins(m, warehouseID)
+				m.On("WithLockForUpdate").
+					Return(m)
+				m.On("GetBinStocks", mock.Anything, payload.GetBinStocksReq{
+					ProductIDIN: []string{productID.String()},
+					BinIDIN:     []string{fromBinID.String()},
+				}).
+					Return([]model.BinStock{{BinID: fromBinID, WarehouseID: warehouseID, ProductID: productID, Quantity: 5}}, nil)
+				m.On("AddBinStockQty", mock.Anything, fromBinID.String(), warehouseID.String(), productID.String(), -3).
+					Return(nil)
+				m.On("AddBinStockQty", mock.Anything, toBinID.String(), warehouseID.String(), productID.String(), 3).
+					Return(nil)
+				mockDb.Mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error - bins in different warehouses",
+			req:  req,
+			setup: func(m *stockRepoMock.StockBinRepository) {
+				mockDb.Mock.ExpectBegin()
+				expectBins(m, uuid.New())
+				mockDb.Mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - insufficient stock in bin",
+			req:  req,
+			setup: func(m *stockRepoMock.StockBinRepository) {
+				mockDb.Mock.ExpectBegin()
+				expectBins(m, warehouseID)
+				m.On("WithLockForUpdate").
+					Return(m)
+				m.On("GetBinStocks", mock.Anything, mock.Anything).
+					Return([]model.BinStock{{BinID: fromBinID, WarehouseID: warehouseID, ProductID: productID, Quantity: 2}}, nil)
+				mockDb.Mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name:    "error - same bin",
+			req:     payload.MoveBinStockReq{FromBinID: fromBinID, ToBinID: fromBinID, ProductID: productID, Quantity: 3},
+			setup:   func(m *stockRepoMock.StockBinRepository) {},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			stockBinRepo := stockRepoMock.NewStockBinRepository(t)
+			stockSvc := stockService{
+				logger:        pkg.InitLogger(&config.Config{}),
+				db:            mockDb.Db,
+				backorderRepo: noBackorders(t),
+				stockBinRepo:  stockBinRepo,
+			}
+
+			tt.setup(stockBinRepo)
+
+			// When
+			err := stockSvc.MoveBinStock(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
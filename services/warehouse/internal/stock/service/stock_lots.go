@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// ReceiveStockLot puts the quantity on hand and records it against the lot.
+func (s *stockService) ReceiveStockLot(ctx context.Context, req payload.ReceiveStockLotReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.ReceiveStockLot")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	var expiryDate *time.Time
+	if req.ExpiryDate != "" {
+		date, err := time.Parse(constant.StockLotExpiryDateLayout, req.ExpiryDate)
+		if err != nil {
+			return apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid expiry date")
+		}
+		expiryDate = &date
+	}
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	if err := s.checkWarehouseActive(ctx, tx, req.WarehouseID.String()); err != nil {
+		return err
+	}
+
+	if err := s.checkNotSerialized(ctx, tx, []string{req.ProductID.String()}); err != nil {
+		return err
+	}
+
+	err = s.stockRepo.WithTX(tx).IncreaseStockQty(ctx, req.ProductID.String(), req.WarehouseID.String(), req.Quantity)
+	if err != nil {
+		return err
+	}
+
+	lots, err := s.stockLotRepo.WithTX(tx).WithLockForUpdate().GetStockLots(ctx, payload.GetStockLotsReq{
+		WarehouseIDIN: []string{req.WarehouseID.String()},
+		ProductIDIN:   []string{req.ProductID.String()},
+		LotNumberIN:   []string{req.LotNumber},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(lots) > 0 {
+		lot := lots[0]
+		if (lot.ExpiryDate == nil) != (expiryDate == nil) || (expiryDate != nil && !lot.ExpiryDate.Equal(*expiryDate)) {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "lot "+req.LotNumber+" already exists with another expiry date")
+		}
+
+		err = s.stockLotRepo.WithTX(tx).AddLotQtyAndReserveQty(ctx, lot.ID.String(), req.Quantity, 0)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = s.stockLotRepo.WithTX(tx).CreateStockLot(ctx, &model.StockLot{
+			WarehouseID: req.WarehouseID,
+			ProductID:   req.ProductID,
+			LotNumber:   req.LotNumber,
+			ExpiryDate:  expiryDate,
+			Quantity:    req.Quantity,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.allocateBackorders(ctx, tx, []string{req.ProductID.String()}); err != nil {
+		return err
+	}
+
+	alerts, err := s.evaluateStockAlerts(ctx, tx, []string{req.ProductID.String()})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.publishStockAlerts(ctx, alerts)
+	s.publishStockAvailability(ctx, []string{req.ProductID.String()})
+
+	return nil
+}
+
+func (s *stockService) GetStockLots(ctx context.Context, req payload.GetStockLotsReq) (result []model.StockLot, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.GetStockLots")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	return s.stockLotRepo.GetStockLots(ctx, req)
+}
+
+// GetExpiringStockLots reports the lots in stock that expired or expire within
+// the requested number of days, first expiry first.
+func (s *stockService) GetExpiringStockLots(ctx context.Context, req payload.GetExpiringStockLotsReq) (result []payload.ExpiringStockLot, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.GetExpiringStockLots")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	withinDays := constant.DefaultExpiringStockLotsWithinDays
+	if req.WithinDays != nil {
+		withinDays = *req.WithinDays
+	}
+
+	today := startOfToday()
+	expiresBefore := today.AddDate(0, 0, withinDays+1)
+	lots, err := s.stockLotRepo.GetStockLots(ctx, payload.GetStockLotsReq{
+		WarehouseIDIN: req.WarehouseIDIN,
+		ProductIDIN:   req.ProductIDIN,
+		InStockOnly:   true,
+		ExpiresBefore: &expiresBefore,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result = make([]payload.ExpiringStockLot, 0, len(lots))
+	for _, lot := range lots {
+		if lot.ExpiryDate == nil {
+			continue
+		}
+
+		result = append(result, payload.ExpiringStockLot{
+			ID:           lot.ID,
+			WarehouseID:  lot.WarehouseID,
+			ProductID:    lot.ProductID,
+			LotNumber:    lot.LotNumber,
+			ExpiryDate:   *lot.ExpiryDate,
+			Quantity:     lot.Quantity,
+			Reserved:     lot.Reserved,
+			Expired:      lot.ExpiryDate.Before(today),
+			DaysToExpiry: int(lot.ExpiryDate.Sub(today).Hours() / 24),
+		})
+	}
+
+	return result, nil
+}
+
+// startOfToday is the day lots are checked against, a lot has expired once its
+// expiry date is before it.
+func startOfToday() time.Time {
+	year, month, day := time.Now().UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// groupStockLots indexes the lots by warehouse stock, keeping their order.
+func groupStockLots(lots []model.StockLot) map[stockKey][]*model.StockLot {
+	grouped := make(map[stockKey][]*model.StockLot)
+	for i := range lots {
+		key := stockKey{warehouseID: lots[i].WarehouseID.String(), productID: lots[i].ProductID.String()}
+		grouped[key] = append(grouped[key], &lots[i])
+	}
+	return grouped
+}
+
+// expiredStockLotQty is the unreserved quantity of the expired lots, which
+// cannot be reserved anymore.
+func expiredStockLotQty(lots []*model.StockLot, today time.Time) int {
+	var quantity int
+	for _, lot := range lots {
+		if lot.ExpiryDate != nil && lot.ExpiryDate.Before(today) {
+			quantity += lot.Quantity - lot.Reserved
+		}
+	}
+	return quantity
+}
+
+// reserveStockLots reserves up to the quantity from the unexpired lots, which
+// are ordered first expiry first. What is left is taken from the quantity not
+// tracked by lot.
+func reserveStockLots(lots []*model.StockLot, quantity int, today time.Time) []payload.ReservedStockLot {
+	var reserved []payload.ReservedStockLot
+	for _, lot := range lots {
+		if quantity == 0 {
+			break
+		}
+		if lot.ExpiryDate != nil && lot.ExpiryDate.Before(today) {
+			continue
+		}
+
+		qty := min(quantity, lot.Quantity-lot.Reserved)
+		if qty <= 0 {
+			continue
+		}
+
+		lot.Reserved += qty
+		quantity -= qty
+		reserved = append(reserved, payload.ReservedStockLot{
+			LotID:      lot.ID.String(),
+			LotNumber:  lot.LotNumber,
+			ExpiryDate: lot.ExpiryDate,
+			Quantity:   qty,
+		})
+	}
+	return reserved
+}
+
+// lotReservationKey is a lot reserved for an order.
+type lotReservationKey struct {
+	lotID    string
+	orderRef string
+}
+
+// stockLotReservations loads what the order, and the reservations made without
+// an order, hold of the lots.
+func (s *stockService) stockLotReservations(ctx context.Context, tx *gorm.DB, lots []model.StockLot, orderRef string) (map[lotReservationKey]int, error) {
+	reserved := make(map[lotReservationKey]int)
+
+	var lotIDs []string
+	for _, lot := range lots {
+		if lot.Reserved > 0 {
+			lotIDs = append(lotIDs, lot.ID.String())
+		}
+	}
+	if len(lotIDs) == 0 {
+		return reserved, nil
+	}
+
+	reservations, err := s.stockLotRepo.WithTX(tx).GetStockLotReservations(ctx, payload.GetStockLotReservationsReq{
+		StockLotIDIN: lotIDs,
+		OrderRefIN:   slices.Compact([]string{orderRef, ""}),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reservation := range reservations {
+		reserved[lotReservationKey{lotID: reservation.StockLotID.String(), orderRef: reservation.OrderRef}] = reservation.Quantity
+	}
+	return reserved, nil
+}
+
+// releaseStockLots takes up to the quantity off the lots the order reserved,
+// first expiry first. Lots reserved before reservations were kept per order,
+// or without an order, are released from the reservations without an order
+// once the order's own run out.
+func releaseStockLots(lots []*model.StockLot, reserved map[lotReservationKey]int, orderRef string, quantity int) []model.StockLotReservation {
+	var released []model.StockLotReservation
+	for _, ref := range slices.Compact([]string{orderRef, ""}) {
+		for _, lot := range lots {
+			if quantity == 0 {
+				return released
+			}
+
+			key := lotReservationKey{lotID: lot.ID.String(), orderRef: ref}
+			qty := min(quantity, reserved[key], lot.Reserved)
+			if qty <= 0 {
+				continue
+			}
+
+			reserved[key] -= qty
+			lot.Reserved -= qty
+			quantity -= qty
+			released = append(released, model.StockLotReservation{
+				StockLotID: lot.ID,
+				OrderRef:   ref,
+				Quantity:   qty,
+			})
+		}
+	}
+	return released
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	stockRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository/mocks"
+	warehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository/mocks"
+)
+
+func TestReceiveStockLot(t *testing.T) {
+	type dependencyMocks struct {
+		db              sqlmock.Sqlmock
+		stockRepo       *stockRepoMock.StockRepository
+		stockAlertRepo  *stockRepoMock.StockAlertRepository
+		stockLotRepo    *stockRepoMock.StockLotRepository
+		warehouseRepo   *warehouseRepoMock.WarehouseRepository
+		stockSerialRepo *stockRepoMock.StockSerialRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	lotID := uuid.New()
+	expiryDate := time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC)
+	req := payload.ReceiveStockLotReq{
+		WarehouseID: warehouseID,
+		ProductID:   productID,
+		LotNumber:   "LOT-001",
+		ExpiryDate:  "2027-03-31",
+		Quantity:    10,
+	}
+
+	expectWarehouse := func(m dependencyMocks, status string) {
+		m.warehouseRepo.On("WithTX", mock.Anything).
+			Return(m.warehouseRepo)
+		m.warehouseRepo.On("WithLockForShare").
+			Return(m.warehouseRepo)
+		m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseID.String()}).
+			Return([]model.Warehouse{{ID: warehouseID, Status: status}}, nil)
+	}
+	expectSerialized := func(m dependencyMocks, products ...model.SerializedProduct) {
+		m.stockSerialRepo.On("WithTX", mock.Anything).
+			Return(m.stockSerialRepo)
+		m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, []string{productID.String()}).
+			Return(products, nil)
+	}
+	expectLots := func(m dependencyMocks, lots []model.StockLot) {
+		expectSerialized(m)
+		m.stockRepo.On("WithTX", mock.Anything).
+			Return(m.stockRepo)
+		m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 10).
+			Return(nil)
+
+		m.stockLotRepo.On("WithTX", mock.Anything).
+			Return(m.stockLotRepo)
+		m.stockLotRepo.On("WithLockForUpdate").
+			Return(m.stockLotRepo)
+		m.stockLotRepo.On("GetStockLots", mock.Anything, payload.GetStockLotsReq{
+			WarehouseIDIN: []string{warehouseID.String()},
+			ProductIDIN:   []string{productID.String()},
+			LotNumberIN:   []string{"LOT-001"},
+		}).
+			Return(lots, nil)
+	}
+	expectAlerts := func(m dependencyMocks) {
+		m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+			Return([]model.WarehouseStock{}, nil)
+		m.stockAlertRepo.On("WithTX", mock.Anything).
+			Return(m.stockAlertRepo)
+		m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+			Return([]model.ProductStockThreshold{}, nil)
+		m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+			Return([]model.StockAlert{}, nil)
+	}
+
+	tests := []struct {
+		name    string
+		req     payload.ReceiveStockLotReq
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - create lot",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectLots(m, []model.StockLot{})
+				m.stockLotRepo.On("CreateStockLot", mock.Anything, &model.StockLot{
+					WarehouseID: warehouseID,
+					ProductID:   productID,
+					LotNumber:   "LOT-001",
+					ExpiryDate:  &expiryDate,
+					Quantity:    10,
+				}).
+					Return(nil)
+				expectAlerts(m)
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - add to existing lot",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectLots(m, []model.StockLot{
+					{ID: lotID, WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-001", ExpiryDate: &expiryDate, Quantity: 5},
+				})
+				m.stockLotRepo.On("AddLotQtyAndReserveQty", mock.Anything, lotID.String(), 10, 0).
+					Return(nil)
+				expectAlerts(m)
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - existing lot has another expiry date",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				otherExpiryDate := expiryDate.AddDate(0, 1, 0)
+
+				m.db.ExpectBegin()
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectLots(m, []model.StockLot{
+					{ID: lotID, WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-001", ExpiryDate: &otherExpiryDate, Quantity: 5},
+				})
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - warehouse is not active",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectWarehouse(m, constant.WarehouseStatusInactive)
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - product is serialized",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectSerialized(m, model.SerializedProduct{ProductID: productID})
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - failed to create lot",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectWarehouse(m, constant.WarehouseStatusActive)
+				expectLots(m, []model.StockLot{})
+				m.stockLotRepo.On("CreateStockLot", mock.Anything, mock.Anything).
+					Return(errors.New("failed to create stock lot"))
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:              mockDb.Mock,
+				stockRepo:       stockRepoMock.NewStockRepository(t),
+				stockAlertRepo:  stockRepoMock.NewStockAlertRepository(t),
+				stockLotRepo:    stockRepoMock.NewStockLotRepository(t),
+				warehouseRepo:   warehouseRepoMock.NewWarehouseRepository(t),
+				stockSerialRepo: stockRepoMock.NewStockSerialRepository(t),
+			}
+			stockSvc := stockService{
+				logger:          pkg.InitLogger(&config.Config{}),
+				db:              mockDb.Db,
+				backorderRepo:   noBackorders(t),
+				availability:    newAvailabilityBroker(),
+				stockRepo:       mocks.stockRepo,
+				stockAlertRepo:  mocks.stockAlertRepo,
+				stockLotRepo:    mocks.stockLotRepo,
+				warehouseRepo:   mocks.warehouseRepo,
+				stockSerialRepo: mocks.stockSerialRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := stockSvc.ReceiveStockLot(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetExpiringStockLots(t *testing.T) {
+	today := startOfToday()
+	expired := today.AddDate(0, 0, -2)
+	expiresSoon := today.AddDate(0, 0, 5)
+	withinDays := 7
+
+	tests := []struct {
+		name    string
+		req     payload.GetExpiringStockLotsReq
+		lots    []model.StockLot
+		err     error
+		want    []payload.ExpiringStockLot
+		wantErr bool
+	}{
+		{
+			name: "success - expired and expiring lots",
+			req:  payload.GetExpiringStockLotsReq{WithinDays: &withinDays},
+			lots: []model.StockLot{
+				{LotNumber: "LOT-1", ExpiryDate: &expired, Quantity: 3},
+				{LotNumber: "LOT-2", ExpiryDate: &expiresSoon, Quantity: 8, Reserved: 2},
+			},
+			want: []payload.ExpiringStockLot{
+				{LotNumber: "LOT-1", ExpiryDate: expired, Quantity: 3, Expired: true, DaysToExpiry: -2},
+				{LotNumber: "LOT-2", ExpiryDate: expiresSoon, Quantity: 8, Reserved: 2, DaysToExpiry: 5},
+			},
+		},
+		{
+			name:    "error - failed to get stock lots",
+			req:     payload.GetExpiringStockLotsReq{},
+			err:     errors.New("failed to get stock lots"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			withinDays := constant.DefaultExpiringStockLotsWithinDays
+			if tt.req.WithinDays != nil {
+				withinDays = *tt.req.WithinDays
+			}
+			expiresBefore := today.AddDate(0, 0, withinDays+1)
+
+			stockLotRepo := stockRepoMock.NewStockLotRepository(t)
+			stockLotRepo.On("GetStockLots", mock.Anything, payload.GetStockLotsReq{
+				InStockOnly:   true,
+				ExpiresBefore: &expiresBefore,
+			}).
+				Return(tt.lots, tt.err)
+
+			stockSvc := stockService{
+				logger:       pkg.InitLogger(&config.Config{}),
+				stockLotRepo: stockLotRepo,
+			}
+
+			// When
+			lots, err := stockSvc.GetExpiringStockLots(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, lots)
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+// SetSerializedProduct turns serial number tracking of the product on or off.
+// It cannot be turned on while the product has stock, which has no serials,
+// nor turned off while serials of the product are in stock.
+func (s *stockService) SetSerializedProduct(ctx context.Context, req payload.SetSerializedProductReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.SetSerializedProduct")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	if req.Serialized {
+		stocks, err := s.stockRepo.WithTX(tx).WithLockForUpdate().GetStocks(ctx, payload.GetStocksReq{
+			ProductIDIN: []string{req.ProductID.String()},
+		})
+		if err != nil {
+			return err
+		}
+		for _, stock := range stocks {
+			if stock.Quantity > 0 {
+				return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product has stock without serials")
+			}
+		}
+
+		err = s.stockSerialRepo.WithTX(tx).CreateSerializedProduct(ctx, &model.SerializedProduct{
+			ProductID: req.ProductID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+		}
+
+		return nil
+	}
+
+	serials, err := s.stockSerialRepo.WithTX(tx).WithLockForUpdate().GetStockSerials(ctx, payload.GetStockSerialsReq{
+		ProductIDIN: []string{req.ProductID.String()},
+		StatusIN:    []string{constant.StockSerialStatusInStock},
+		Limit:       1,
+	})
+	if err != nil {
+		return err
+	}
+	if len(serials) > 0 {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product still has serials in stock")
+	}
+
+	err = s.stockSerialRepo.WithTX(tx).DeleteSerializedProduct(ctx, req.ProductID.String())
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// ReceiveStockSerials registers the serials in the warehouse and puts one unit
+// on hand for each of them.
+func (s *stockService) ReceiveStockSerials(ctx context.Context, req payload.ReceiveStockSerialsReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.ReceiveStockSerials")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	serialized, err := s.serializedProducts(ctx, tx, []string{req.ProductID.String()})
+	if err != nil {
+		return err
+	}
+	if !serialized[req.ProductID.String()] {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product is not serialized")
+	}
+
+	if err := s.checkWarehouseActive(ctx, tx, req.WarehouseID.String()); err != nil {
+		return err
+	}
+
+	existing, err := s.stockSerialRepo.WithTX(tx).GetStockSerials(ctx, payload.GetStockSerialsReq{
+		ProductIDIN:    []string{req.ProductID.String()},
+		SerialNumberIN: req.SerialNumbers,
+	})
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "serial "+existing[0].SerialNumber+" is already registered")
+	}
+
+	serials := make([]model.StockSerial, 0, len(req.SerialNumbers))
+	for _, serialNumber := range req.SerialNumbers {
+		serials = append(serials, model.StockSerial{
+			ProductID:    req.ProductID,
+			SerialNumber: serialNumber,
+			WarehouseID:  req.WarehouseID,
+			Status:       constant.StockSerialStatusInStock,
+		})
+	}
+
+	err = s.stockSerialRepo.WithTX(tx).CreateStockSerials(ctx, serials)
+	if err != nil {
+		return err
+	}
+
+	events := make([]model.StockSerialEvent, 0, len(serials))
+	for _, serial := range serials {
+		events = append(events, model.StockSerialEvent{
+			StockSerialID: serial.ID,
+			Type:          constant.StockSerialEventReceived,
+			WarehouseID:   req.WarehouseID,
+		})
+	}
+
+	err = s.stockSerialRepo.WithTX(tx).CreateStockSerialEvents(ctx, events)
+	if err != nil {
+		return err
+	}
+
+	err = s.stockRepo.WithTX(tx).IncreaseStockQty(ctx, req.ProductID.String(), req.WarehouseID.String(), len(serials))
+	if err != nil {
+		return err
+	}
+
+	if err := s.allocateBackorders(ctx, tx, []string{req.ProductID.String()}); err != nil {
+		return err
+	}
+
+	alerts, err := s.evaluateStockAlerts(ctx, tx, []string{req.ProductID.String()})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.publishStockAlerts(ctx, alerts)
+	s.publishStockAvailability(ctx, []string{req.ProductID.String()})
+
+	return nil
+}
+
+// ReturnStockSerials puts sold serials back in stock in the warehouse.
+func (s *stockService) ReturnStockSerials(ctx context.Context, req payload.ReturnStockSerialsReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.ReturnStockSerials")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	if err := s.checkWarehouseActive(ctx, tx, req.WarehouseID.String()); err != nil {
+		return err
+	}
+
+	serials, err := s.stockSerialRepo.WithTX(tx).WithLockForUpdate().GetStockSerials(ctx, payload.GetStockSerialsReq{
+		ProductIDIN:    []string{req.ProductID.String()},
+		SerialNumberIN: req.SerialNumbers,
+	})
+	if err != nil {
+		return err
+	}
+
+	bySerialNumber := make(map[string]model.StockSerial, len(serials))
+	for _, serial := range serials {
+		bySerialNumber[serial.SerialNumber] = serial
+	}
+
+	events := make([]model.StockSerialEvent, 0, len(req.SerialNumbers))
+	for _, serialNumber := range req.SerialNumbers {
+		serial, ok := bySerialNumber[serialNumber]
+		if !ok {
+			return apperr.NewWithCode(apperr.CodeHTTPNotFound, "serial "+serialNumber+" not found")
+		}
+		if serial.Status != constant.StockSerialStatusSold {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "serial "+serialNumber+" is not sold")
+		}
+		if req.OrderRef != "" && (serial.OrderRef == nil || *serial.OrderRef != req.OrderRef) {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "serial "+serialNumber+" was not sold to order "+req.OrderRef)
+		}
+
+		events = append(events, model.StockSerialEvent{
+			StockSerialID: serial.ID,
+			Type:          constant.StockSerialEventReturned,
+			WarehouseID:   req.WarehouseID,
+			OrderRef:      serial.OrderRef,
+		})
+
+		serial.Status = constant.StockSerialStatusInStock
+		serial.WarehouseID = req.WarehouseID
+		serial.OrderRef = nil
+		err = s.stockSerialRepo.WithTX(tx).UpdateStockSerial(ctx, &serial)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.stockSerialRepo.WithTX(tx).CreateStockSerialEvents(ctx, events)
+	if err != nil {
+		return err
+	}
+
+	err = s.stockRepo.WithTX(tx).IncreaseStockQty(ctx, req.ProductID.String(), req.WarehouseID.String(), len(req.SerialNumbers))
+	if err != nil {
+		return err
+	}
+
+	if err := s.allocateBackorders(ctx, tx, []string{req.ProductID.String()}); err != nil {
+		return err
+	}
+
+	alerts, err := s.evaluateStockAlerts(ctx, tx, []string{req.ProductID.String()})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.publishStockAlerts(ctx, alerts)
+	s.publishStockAvailability(ctx, []string{req.ProductID.String()})
+
+	return nil
+}
+
+func (s *stockService) GetStockSerials(ctx context.Context, req payload.GetStockSerialsReq) (result []model.StockSerial, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.GetStockSerials")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	return s.stockSerialRepo.GetStockSerials(ctx, req)
+}
+
+// GetStockSerialHistory looks up a serial number across products, each serial
+// coming with its receipts, sales and returns.
+func (s *stockService) GetStockSerialHistory(ctx context.Context, serialNumber string) (result []model.StockSerial, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.GetStockSerialHistory")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	serials, err := s.stockSerialRepo.GetStockSerialHistory(ctx, serialNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(serials) == 0 {
+		return nil, apperr.NewWithCode(apperr.CodeHTTPNotFound, "serial not found")
+	}
+
+	return serials, nil
+}
+
+func (s *stockService) serializedProducts(ctx context.Context, tx *gorm.DB, productIDs []string) (map[string]bool, error) {
+	products, err := s.stockSerialRepo.WithTX(tx).GetSerializedProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	serialized := make(map[string]bool, len(products))
+	for _, product := range products {
+		serialized[product.ProductID.String()] = true
+	}
+	return serialized, nil
+}
+
+// sellStockSerials marks the serials of a committed stock as sold to the order.
+// Without serial numbers the oldest serials in stock in the warehouse are used.
+func (s *stockService) sellStockSerials(ctx context.Context, tx *gorm.DB, orderRef string, stock payload.CommitReservesData) error {
+	if orderRef == "" {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "order reference is required to commit serialized product "+stock.ProductID)
+	}
+	if len(stock.SerialNumbers) > 0 && len(stock.SerialNumbers) != stock.Quantity {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "expected "+strconv.Itoa(stock.Quantity)+" serial numbers for product "+stock.ProductID)
+	}
+
+	req := payload.GetStockSerialsReq{
+		WarehouseIDIN:  []string{stock.WarehouseID},
+		ProductIDIN:    []string{stock.ProductID},
+		SerialNumberIN: stock.SerialNumbers,
+		StatusIN:       []string{constant.StockSerialStatusInStock},
+	}
+	if len(stock.SerialNumbers) == 0 {
+		req.Limit = stock.Quantity
+	}
+
+	serials, err := s.stockSerialRepo.WithTX(tx).WithLockForUpdate().GetStockSerials(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(serials) < stock.Quantity {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "not enough serials in stock for product "+stock.ProductID)
+	}
+
+	events := make([]model.StockSerialEvent, 0, len(serials))
+	for i := range serials {
+		serials[i].Status = constant.StockSerialStatusSold
+		serials[i].OrderRef = &orderRef
+		err = s.stockSerialRepo.WithTX(tx).UpdateStockSerial(ctx, &serials[i])
+		if err != nil {
+			return err
+		}
+
+		events = append(events, model.StockSerialEvent{
+			StockSerialID: serials[i].ID,
+			Type:          constant.StockSerialEventSold,
+			WarehouseID:   serials[i].WarehouseID,
+			OrderRef:      &orderRef,
+		})
+	}
+
+	return s.stockSerialRepo.WithTX(tx).CreateStockSerialEvents(ctx, events)
+}
+
+// checkNotSerialized refuses stock changes of serialized products that do not
+// go through their serials, the serials in stock would no longer match the
+// stock.
+func (s *stockService) checkNotSerialized(ctx context.Context, tx *gorm.DB, productIDs []string) error {
+	serialized, err := s.serializedProducts(ctx, tx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, productID := range productIDs {
+		if serialized[productID] {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product "+productID+" is serialized, its stock follows its serials")
+		}
+	}
+	return nil
+}
+
+// checkWarehouseActive refuses stock coming into a warehouse that is not
+// active. The warehouse row is share-locked, so it cannot be archived before
+// the stock is committed.
+func (s *stockService) checkWarehouseActive(ctx context.Context, tx *gorm.DB, warehouseID string) error {
+	warehouses, err := s.warehouseRepo.WithTX(tx).WithLockForShare().GetWarehousesByIDs(ctx, []string{warehouseID})
+	if err != nil {
+		return err
+	}
+	if len(warehouses) == 0 {
+		return apperr.NewWithCode(apperr.CodeHTTPNotFound, "warehouse not found")
+	}
+	if warehouses[0].Status != constant.WarehouseStatusActive {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse is not active")
+	}
+	return nil
+}
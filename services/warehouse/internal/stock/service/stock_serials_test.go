@@ -0,0 +1,457 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	stockRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository/mocks"
+	warehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository/mocks"
+)
+
+func TestReceiveStockSerials(t *testing.T) {
+	type dependencyMocks struct {
+		db              sqlmock.Sqlmock
+		stockRepo       *stockRepoMock.StockRepository
+		stockAlertRepo  *stockRepoMock.StockAlertRepository
+		stockSerialRepo *stockRepoMock.StockSerialRepository
+		warehouseRepo   *warehouseRepoMock.WarehouseRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	req := payload.ReceiveStockSerialsReq{
+		WarehouseID:   warehouseID,
+		ProductID:     productID,
+		SerialNumbers: []string{"SN-1", "SN-2"},
+	}
+
+	expectSerialized := func(m dependencyMocks, serialized bool) {
+		products := []model.SerializedProduct{}
+		if serialized {
+			products = append(products, model.SerializedProduct{ProductID: productID})
+		}
+		m.stockSerialRepo.On("WithTX", mock.Anything).
+			Return(m.stockSerialRepo)
+		m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, []string{productID.String()}).
+			Return(products, nil)
+	}
+	expectWarehouse := func(m dependencyMocks) {
+		m.warehouseRepo.On("WithTX", mock.Anything).
+			Return(m.warehouseRepo)
+		m.warehouseRepo.On("WithLockForShare").
+			Return(m.warehouseRepo)
+		m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseID.String()}).
+			Return([]model.Warehouse{{ID: warehouseID, Status: constant.WarehouseStatusActive}}, nil)
+	}
+	expectExisting := func(m dependencyMocks, serials []model.StockSerial) {
+		m.stockSerialRepo.On("GetStockSerials", mock.Anything, payload.GetStockSerialsReq{
+			ProductIDIN:    []string{productID.String()},
+			SerialNumberIN: []string{"SN-1", "SN-2"},
+		}).
+			Return(serials, nil)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - receive serials",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectSerialized(m, true)
+				expectWarehouse(m)
+				expectExisting(m, []model.StockSerial{})
+				m.stockSerialRepo.On("CreateStockSerials", mock.Anything, mock.MatchedBy(func(serials []model.StockSerial) bool {
+					return len(serials) == 2 && serials[0].Status == constant.StockSerialStatusInStock
+				})).
+					Return(nil)
+				m.stockSerialRepo.On("CreateStockSerialEvents", mock.Anything, mock.MatchedBy(func(events []model.StockSerialEvent) bool {
+					return len(events) == 2 && events[0].Type == constant.StockSerialEventReceived
+				})).
+					Return(nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 2).
+					Return(nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - product is not serialized",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectSerialized(m, false)
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - serial is already registered",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectSerialized(m, true)
+				expectWarehouse(m)
+				expectExisting(m, []model.StockSerial{{ProductID: productID, SerialNumber: "SN-2"}})
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - failed to create serials",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectSerialized(m, true)
+				expectWarehouse(m)
+				expectExisting(m, []model.StockSerial{})
+				m.stockSerialRepo.On("CreateStockSerials", mock.Anything, mock.Anything).
+					Return(errors.New("failed to create stock serials"))
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:              mockDb.Mock,
+				stockRepo:       stockRepoMock.NewStockRepository(t),
+				stockAlertRepo:  stockRepoMock.NewStockAlertRepository(t),
+				stockSerialRepo: stockRepoMock.NewStockSerialRepository(t),
+				warehouseRepo:   warehouseRepoMock.NewWarehouseRepository(t),
+			}
+			stockSvc := stockService{
+				logger:          pkg.InitLogger(&config.Config{}),
+				db:              mockDb.Db,
+				backorderRepo:   noBackorders(t),
+				availability:    newAvailabilityBroker(),
+				stockRepo:       mocks.stockRepo,
+				stockAlertRepo:  mocks.stockAlertRepo,
+				stockSerialRepo: mocks.stockSerialRepo,
+				warehouseRepo:   mocks.warehouseRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := stockSvc.ReceiveStockSerials(context.Background(), req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReturnStockSerials(t *testing.T) {
+	type dependencyMocks struct {
+		db              sqlmock.Sqlmock
+		stockRepo       *stockRepoMock.StockRepository
+		stockAlertRepo  *stockRepoMock.StockAlertRepository
+		stockSerialRepo *stockRepoMock.StockSerialRepository
+		warehouseRepo   *warehouseRepoMock.WarehouseRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	warehouseID := uuid.New()
+	productID := uuid.New()
+	serialID := uuid.New()
+	orderRef := "order-1"
+
+	expectSerials := func(m dependencyMocks, serials []model.StockSerial) {
+		m.warehouseRepo.On("WithTX", mock.Anything).
+			Return(m.warehouseRepo)
+		m.warehouseRepo.On("WithLockForShare").
+			Return(m.warehouseRepo)
+		m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseID.String()}).
+			Return([]model.Warehouse{{ID: warehouseID, Status: constant.WarehouseStatusActive}}, nil)
+
+		m.stockSerialRepo.On("WithTX", mock.Anything).
+			Return(m.stockSerialRepo)
+		m.stockSerialRepo.On("WithLockForUpdate").
+			Return(m.stockSerialRepo)
+		m.stockSerialRepo.On("GetStockSerials", mock.Anything, payload.GetStockSerialsReq{
+			ProductIDIN:    []string{productID.String()},
+			SerialNumberIN: []string{"SN-1"},
+		}).
+			Return(serials, nil)
+	}
+
+	tests := []struct {
+		name    string
+		req     payload.ReturnStockSerialsReq
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - return sold serial",
+			req: payload.ReturnStockSerialsReq{
+				WarehouseID:   warehouseID,
+				ProductID:     productID,
+				SerialNumbers: []string{"SN-1"},
+				OrderRef:      orderRef,
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectSerials(m, []model.StockSerial{
+					{ID: serialID, ProductID: productID, SerialNumber: "SN-1", WarehouseID: uuid.New(), Status: constant.StockSerialStatusSold, OrderRef: &orderRef},
+				})
+				m.stockSerialRepo.On("UpdateStockSerial", mock.Anything, mock.MatchedBy(func(serial *model.StockSerial) bool {
+					return serial.Status == constant.StockSerialStatusInStock && serial.WarehouseID == warehouseID && serial.OrderRef == nil
+				})).
+					Return(nil)
+				m.stockSerialRepo.On("CreateStockSerialEvents", mock.Anything, []model.StockSerialEvent{
+					{StockSerialID: serialID, Type: constant.StockSerialEventReturned, WarehouseID: warehouseID, OrderRef: &orderRef},
+				}).
+					Return(nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 1).
+					Return(nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - serial not found",
+			req: payload.ReturnStockSerialsReq{
+				WarehouseID:   warehouseID,
+				ProductID:     productID,
+				SerialNumbers: []string{"SN-1"},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectSerials(m, []model.StockSerial{})
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - serial is not sold",
+			req: payload.ReturnStockSerialsReq{
+				WarehouseID:   warehouseID,
+				ProductID:     productID,
+				SerialNumbers: []string{"SN-1"},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectSerials(m, []model.StockSerial{
+					{ID: serialID, ProductID: productID, SerialNumber: "SN-1", WarehouseID: warehouseID, Status: constant.StockSerialStatusInStock},
+				})
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - serial was sold to another order",
+			req: payload.ReturnStockSerialsReq{
+				WarehouseID:   warehouseID,
+				ProductID:     productID,
+				SerialNumbers: []string{"SN-1"},
+				OrderRef:      "order-2",
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				expectSerials(m, []model.StockSerial{
+					{ID: serialID, ProductID: productID, SerialNumber: "SN-1", WarehouseID: warehouseID, Status: constant.StockSerialStatusSold, OrderRef: &orderRef},
+				})
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:              mockDb.Mock,
+				stockRepo:       stockRepoMock.NewStockRepository(t),
+				stockAlertRepo:  stockRepoMock.NewStockAlertRepository(t),
+				stockSerialRepo: stockRepoMock.NewStockSerialRepository(t),
+				warehouseRepo:   warehouseRepoMock.NewWarehouseRepository(t),
+			}
+			stockSvc := stockService{
+				logger:          pkg.InitLogger(&config.Config{}),
+				db:              mockDb.Db,
+				backorderRepo:   noBackorders(t),
+				availability:    newAvailabilityBroker(),
+				stockRepo:       mocks.stockRepo,
+				stockAlertRepo:  mocks.stockAlertRepo,
+				stockSerialRepo: mocks.stockSerialRepo,
+				warehouseRepo:   mocks.warehouseRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := stockSvc.ReturnStockSerials(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSetSerializedProduct(t *testing.T) {
+	type dependencyMocks struct {
+		stockRepo       *stockRepoMock.StockRepository
+		stockSerialRepo *stockRepoMock.StockSerialRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+
+	expectStocks := func(m dependencyMocks, stocks []model.WarehouseStock) {
+		m.stockRepo.On("WithTX", mock.Anything).
+			Return(m.stockRepo)
+		m.stockRepo.On("WithLockForUpdate").
+			Return(m.stockRepo)
+		m.stockRepo.On("GetStocks", mock.Anything, payload.GetStocksReq{ProductIDIN: []string{productID.String()}}).
+			Return(stocks, nil)
+	}
+
+	tests := []struct {
+		name    string
+		req     payload.SetSerializedProductReq
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - turn on",
+			req:  payload.SetSerializedProductReq{ProductID: productID, Serialized: true},
+			setup: func(m dependencyMocks) {
+				mockDb.Mock.ExpectBegin()
+				expectStocks(m, []model.WarehouseStock{{ProductID: productID, Quantity: 0}})
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("CreateSerializedProduct", mock.Anything, &model.SerializedProduct{ProductID: productID}).
+					Return(nil)
+				mockDb.Mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error - turn on while the product has stock",
+			req:  payload.SetSerializedProductReq{ProductID: productID, Serialized: true},
+			setup: func(m dependencyMocks) {
+				mockDb.Mock.ExpectBegin()
+				expectStocks(m, []model.WarehouseStock{{ProductID: productID, Quantity: 3}})
+				mockDb.Mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "success - turn off",
+			req:  payload.SetSerializedProductReq{ProductID: productID},
+			setup: func(m dependencyMocks) {
+				mockDb.Mock.ExpectBegin()
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("WithLockForUpdate").
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetStockSerials", mock.Anything, mock.Anything).
+					Return([]model.StockSerial{}, nil)
+				m.stockSerialRepo.On("DeleteSerializedProduct", mock.Anything, productID.String()).
+					Return(nil)
+				mockDb.Mock.ExpectCommit()
+			},
+		},
+		{
+			name: "error - serials still in stock",
+			req:  payload.SetSerializedProductReq{ProductID: productID},
+			setup: func(m dependencyMocks) {
+				mockDb.Mock.ExpectBegin()
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("WithLockForUpdate").
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetStockSerials", mock.Anything, mock.Anything).
+					Return([]model.StockSerial{{ProductID: productID, SerialNumber: "SN-1"}}, nil)
+				mockDb.Mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				stockRepo:       stockRepoMock.NewStockRepository(t),
+				stockSerialRepo: stockRepoMock.NewStockSerialRepository(t),
+			}
+			stockSvc := stockService{
+				logger:          pkg.InitLogger(&config.Config{}),
+				db:              mockDb.Db,
+				backorderRepo:   noBackorders(t),
+				availability:    newAvailabilityBroker(),
+				stockRepo:       mocks.stockRepo,
+				stockSerialRepo: mocks.stockSerialRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := stockSvc.SetSerializedProduct(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
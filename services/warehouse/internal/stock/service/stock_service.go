@@ -1,15 +1,28 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"io"
+	"slices"
+	"time"
 
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	purchasingservice "github.com/alifmufthi91/ecommerce-system/services/warehouse/external/purchasing_service"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	shopwarehousepayload "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/payload"
+	shopwarehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/repository"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/allocation"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository"
+	warehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.opentelemetry.io/otel/codes"
 	"gorm.io/gorm"
 )
@@ -23,21 +36,101 @@ type StockService interface {
 	RollbackReserves(ctx context.Context, req payload.RollbackReservesReq) error
 	CommitReserves(ctx context.Context, req payload.CommitReservesReq) error
 	CreateStock(ctx context.Context, req payload.CreateStockReq) error
+	SetStockThreshold(ctx context.Context, req payload.SetStockThresholdReq) error
+	GetStockAlerts(ctx context.Context, req payload.GetStockAlertsReq) ([]model.StockAlert, error)
+	SettleStockChanges(ctx context.Context, tx *gorm.DB, productIDs []string) ([]model.StockAlert, error)
+	PublishStockChanges(ctx context.Context, productIDs []string, alerts []model.StockAlert)
+	ImportStocks(ctx context.Context, req payload.ImportStocksReq, file io.Reader) (payload.ImportStocksResult, error)
+	ReceiveStockLot(ctx context.Context, req payload.ReceiveStockLotReq) error
+	GetStockLots(ctx context.Context, req payload.GetStockLotsReq) ([]model.StockLot, error)
+	GetExpiringStockLots(ctx context.Context, req payload.GetExpiringStockLotsReq) ([]payload.ExpiringStockLot, error)
+	SetSerializedProduct(ctx context.Context, req payload.SetSerializedProductReq) error
+	ReceiveStockSerials(ctx context.Context, req payload.ReceiveStockSerialsReq) error
+	ReturnStockSerials(ctx context.Context, req payload.ReturnStockSerialsReq) error
+	GetStockSerials(ctx context.Context, req payload.GetStockSerialsReq) ([]model.StockSerial, error)
+	GetStockSerialHistory(ctx context.Context, serialNumber string) ([]model.StockSerial, error)
+	CreateBin(ctx context.Context, req payload.CreateBinReq) (model.WarehouseBin, error)
+	GetBins(ctx context.Context, req payload.GetBinsReq) ([]model.WarehouseBin, error)
+	GetBinStocks(ctx context.Context, req payload.GetBinStocksReq) ([]model.BinStock, error)
+	PutAwayStock(ctx context.Context, req payload.PutAwayStockReq) error
+	MoveBinStock(ctx context.Context, req payload.MoveBinStockReq) error
+	GetPickLists(ctx context.Context, req payload.GetPickListsReq) ([]model.PickList, error)
+	TakeStockSnapshot(ctx context.Context) error
+	GetStockSnapshot(ctx context.Context, req payload.GetStockSnapshotReq) (payload.StockSnapshotResp, error)
+	SubscribeStockAvailability(ctx context.Context, req payload.StreamStockAvailabilityReq) (<-chan payload.StockAvailabilityEvent, error)
+	SetBackorderProduct(ctx context.Context, req payload.SetBackorderProductReq) error
+	GetBackorders(ctx context.Context, req payload.GetBackordersReq) ([]model.Backorder, error)
+	CancelBackorders(ctx context.Context, req payload.CancelBackordersReq) error
+}
+
+const (
+	// maxReserveAttempts bounds how many times a reservation is planned again
+	maxReserveAttempts = 3
+	// reserveRetryBackoff is the wait before the next attempt, growing with
+	// every attempt
+	reserveRetryBackoff = 10 * time.Millisecond
+)
+
+// errReserveConflict is the root cause when a stock was taken between planning
+// a reservation and reserving it.
+var errReserveConflict = errors.New("stock changed during reservation")
+
+// stockKey identifies the stock of a product in a warehouse.
+type stockKey struct {
+	warehouseID string
+	productID   string
 }
 
 type stockService struct {
-	config    *config.Config
-	logger    *pkg.Logger
-	stockRepo repository.StockRepository
-	db        *gorm.DB
+	config            *config.Config
+	logger            *pkg.Logger
+	stockRepo         repository.StockRepository
+	stockAlertRepo    repository.StockAlertRepository
+	stockLotRepo      repository.StockLotRepository
+	stockSerialRepo   repository.StockSerialRepository
+	stockBinRepo      repository.StockBinRepository
+	stockSnapshotRepo repository.StockSnapshotRepository
+	backorderRepo     repository.BackorderRepository
+	shopWarehouseRepo shopwarehouserepository.ShopWarehouseRepository
+	warehouseRepo     warehouserepository.WarehouseRepository
+	purchasingSvc     purchasingservice.IPurchasingSvc
+	db                *gorm.DB
+	availability      *availabilityBroker
+	// allocation strategy used when a reservation does not ask for one
+	defaultStrategy string
 }
 
-func NewStockService(config *config.Config, logger *pkg.Logger, db *gorm.DB, stockRepo repository.StockRepository) StockService {
+func NewStockService(
+	config *config.Config,
+	logger *pkg.Logger,
+	db *gorm.DB,
+	stockRepo repository.StockRepository,
+	stockAlertRepo repository.StockAlertRepository,
+	stockLotRepo repository.StockLotRepository,
+	stockSerialRepo repository.StockSerialRepository,
+	stockBinRepo repository.StockBinRepository,
+	stockSnapshotRepo repository.StockSnapshotRepository,
+	backorderRepo repository.BackorderRepository,
+	shopWarehouseRepo shopwarehouserepository.ShopWarehouseRepository,
+	warehouseRepo warehouserepository.WarehouseRepository,
+	purchasingSvc purchasingservice.IPurchasingSvc,
+) StockService {
 	return &stockService{
-		config:    config,
-		logger:    logger,
-		stockRepo: stockRepo,
-		db:        db,
+		config:            config,
+		logger:            logger,
+		stockRepo:         stockRepo,
+		stockAlertRepo:    stockAlertRepo,
+		stockLotRepo:      stockLotRepo,
+		stockSerialRepo:   stockSerialRepo,
+		stockBinRepo:      stockBinRepo,
+		stockSnapshotRepo: stockSnapshotRepo,
+		backorderRepo:     backorderRepo,
+		shopWarehouseRepo: shopWarehouseRepo,
+		warehouseRepo:     warehouseRepo,
+		purchasingSvc:     purchasingSvc,
+		db:                db,
+		availability:      newAvailabilityBroker(),
+		defaultStrategy:   config.Reservation.AllocationStrategy,
 	}
 }
 
@@ -69,7 +162,18 @@ func (s *stockService) CreateStock(ctx context.Context, req payload.CreateStockR
 		}
 	}()
 
-	err = s.stockRepo.CreateStock(ctx, &model.WarehouseStock{
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	if err := s.checkWarehouseActive(ctx, tx, req.WarehouseID.String()); err != nil {
+		return err
+	}
+
+	if err := s.checkNotSerialized(ctx, tx, []string{req.ProductID.String()}); err != nil {
+		return err
+	}
+
+	err = s.stockRepo.WithTX(tx).CreateStock(ctx, &model.WarehouseStock{
 		WarehouseID: req.WarehouseID,
 		ProductID:   req.ProductID,
 		Quantity:    req.Quantity,
@@ -78,6 +182,22 @@ func (s *stockService) CreateStock(ctx context.Context, req payload.CreateStockR
 		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create stock")
 	}
 
+	if err := s.allocateBackorders(ctx, tx, []string{req.ProductID.String()}); err != nil {
+		return err
+	}
+
+	alerts, err := s.evaluateStockAlerts(ctx, tx, []string{req.ProductID.String()})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.publishStockAlerts(ctx, alerts)
+	s.publishStockAvailability(ctx, []string{req.ProductID.String()})
+
 	return nil
 }
 
@@ -98,6 +218,10 @@ func (s *stockService) TransferStock(ctx context.Context, req payload.TransferSt
 	tx := s.db.Begin()
 	defer tx.Rollback()
 
+	if err := s.checkWarehouseActive(ctx, tx, req.ToWarehouseID.String()); err != nil {
+		return err
+	}
+
 	stocks, err := s.stockRepo.WithTX(tx).WithLockForUpdate().GetStocks(ctx, payload.GetStocksReq{
 		WarehouseIDIN: []string{req.FromWarehouseID.String(), req.ToWarehouseID.String()},
 		ProductIDIN:   []string{req.ProductID.String()},
@@ -126,11 +250,6 @@ func (s *stockService) TransferStock(ctx context.Context, req payload.TransferSt
 		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "insufficient stock in from warehouse")
 	}
 
-	err = s.stockRepo.WithTX(tx).UpdateStock(ctx, fromStock)
-	if err != nil {
-		return err
-	}
-
 	if toStock == nil {
 		toStock = &model.WarehouseStock{
 			WarehouseID: req.ToWarehouseID,
@@ -149,26 +268,66 @@ func (s *stockService) TransferStock(ctx context.Context, req payload.TransferSt
 		}
 	}
 
+	// the lots go with the stock before the source is decreased, expired
+	// quantity is only taken once the unexpired stock runs out
+	expired, err := s.stockLotRepo.WithTX(tx).MoveStockLots(ctx, req.ProductID.String(), req.FromWarehouseID.String(), req.ToWarehouseID.String(), req.Quantity)
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "insufficient unexpired stock in from warehouse")
+	}
+
+	err = s.stockRepo.WithTX(tx).UpdateStock(ctx, fromStock)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := s.serializedProducts(ctx, tx, []string{req.ProductID.String()})
+	if err != nil {
+		return err
+	}
+	if serialized[req.ProductID.String()] {
+		err = s.stockSerialRepo.WithTX(tx).MoveStockSerials(ctx, req.ProductID.String(), req.FromWarehouseID.String(), req.ToWarehouseID.String(), req.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+
 	s.logger.WithContext(ctx).Infow("Transferring stock",
 		"from_warehouse_id", req.FromWarehouseID,
 		"to_warehouse_id", req.ToWarehouseID,
 		"product_id", req.ProductID,
 		"quantity", req.Quantity,
 	)
+	// the stock moves instantly, so the transfer is dispatched and received at once
+	now := time.Now()
 	err = s.stockRepo.WithTX(tx).CreateStockTransfer(ctx, &model.StockTransfer{
-		FromWarehouseID: req.FromWarehouseID,
-		ToWarehouseID:   req.ToWarehouseID,
-		ProductID:       req.ProductID,
-		Quantity:        req.Quantity,
+		FromWarehouseID:  req.FromWarehouseID,
+		ToWarehouseID:    req.ToWarehouseID,
+		ProductID:        req.ProductID,
+		Quantity:         req.Quantity,
+		Status:           constant.StockTransferStatusReceived,
+		ReceivedQuantity: req.Quantity,
+		DispatchedAt:     &now,
+		ReceivedAt:       &now,
 	})
 	if err != nil {
 		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create stock transfer")
 	}
 
+	alerts, err := s.evaluateStockAlerts(ctx, tx, []string{req.ProductID.String()})
+	if err != nil {
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
 	}
 
+	s.publishStockAlerts(ctx, alerts)
+	s.publishStockAvailability(ctx, []string{req.ProductID.String()})
+
 	return nil
 }
 
@@ -190,6 +349,10 @@ func (s *stockService) GetStockAvailablesByProduct(ctx context.Context, req payl
 	return availableStocks, nil
 }
 
+// ReserveStocks plans the reservation from a plain read of the stocks and then
+// reserves each stock with a conditional update, so concurrent reservations
+// never wait on each other's reads. When a stock was taken in between, or the
+// database gives up on the transaction, the reservation is planned again.
 func (s *stockService) ReserveStocks(ctx context.Context, req payload.ReserveStocksReq) (result []payload.ReserveStocksResp, err error) {
 	ctx, span := observ.GetTracer().Start(ctx, "stockService.ReserveStocks")
 	defer span.End()
@@ -200,67 +363,207 @@ func (s *stockService) ReserveStocks(ctx context.Context, req payload.ReserveSto
 		}
 	}()
 
+	strategyName := req.Strategy
+	if strategyName == "" {
+		strategyName = s.defaultStrategy
+	}
+	strategy, err := allocation.New(strategyName)
+	if err != nil {
+		return result, err
+	}
+
+	var alerts []model.StockAlert
+	for attempt := 1; ; attempt++ {
+		result, alerts, err = s.tryReserveStocks(ctx, req, strategyName, strategy)
+		if err == nil {
+			break
+		}
+		if attempt == maxReserveAttempts || !isRetryableReserveErr(err) {
+			return nil, err
+		}
+
+		s.logger.WithContext(ctx).Debugw("Retrying stock reservation", "attempt", attempt, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, apperr.WrapWithCode(ctx.Err(), apperr.CodeHTTPInternalServerError, "failed to reserve stocks")
+		case <-time.After(time.Duration(attempt) * reserveRetryBackoff):
+		}
+	}
+
+	var productIDs []string
+	for _, stock := range req.Stocks {
+		productIDs = append(productIDs, stock.ProductID)
+	}
+
+	s.publishStockAlerts(ctx, alerts)
+	s.publishStockAvailability(ctx, productIDs)
+
+	return result, nil
+}
+
+func (s *stockService) tryReserveStocks(ctx context.Context, req payload.ReserveStocksReq, strategyName string, strategy allocation.Strategy) (result []payload.ReserveStocksResp, alerts []model.StockAlert, err error) {
 	tx := s.db.Begin()
 	defer tx.Rollback()
 
 	var productIDs []string
-	for _, stock := range req.Stocks {
+	var shopIDs []string
+	stockShopIDs := make([]string, len(req.Stocks))
+	for i, stock := range req.Stocks {
 		productIDs = append(productIDs, stock.ProductID)
+		if stock.ShopID == "" {
+			continue
+		}
+
+		shopID, err := uuid.Parse(stock.ShopID)
+		if err != nil {
+			return nil, nil, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid shop ID")
+		}
+		stockShopIDs[i] = shopID.String()
+		shopIDs = append(shopIDs, shopID.String())
 	}
 
-	stocks, err := s.stockRepo.WithTX(tx).WithLockForUpdate().GetStocks(ctx, payload.GetStocksReq{
+	stocks, err := s.stockRepo.WithTX(tx).GetStocks(ctx, payload.GetStocksReq{
 		ProductIDIN: productIDs,
 	})
 	if err != nil {
-		return result, err
+		return nil, nil, err
 	}
 
-	var reservedStocks []model.WarehouseStock
-	for _, stock := range req.Stocks {
-		demandQty := stock.Quantity
+	lots, err := s.stockLotRepo.WithTX(tx).GetStockLots(ctx, payload.GetStockLotsReq{
+		ProductIDIN: productIDs,
+		InStockOnly: true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	stockLots := groupStockLots(lots)
+	today := startOfToday()
+
+	shopWarehouseRanks, err := s.shopWarehouseRanks(ctx, tx, shopIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var distances map[uuid.UUID]*float64
+	if strategyName == constant.AllocationStrategyNearest {
+		distances, err = s.warehouseDistances(ctx, tx, req.Destination, stocks)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var backordered []string
+	for i, stock := range req.Stocks {
+		var productID uuid.UUID
+		var sources []allocation.Source
 		for _, s := range stocks {
-			if s.ProductID.String() == stock.ProductID {
-				availableQty := s.Quantity - s.Reserved
-				reserveQty := min(demandQty, availableQty)
-				if reserveQty == 0 {
+			if s.ProductID.String() != stock.ProductID {
+				continue
+			}
+
+			var priority int
+			if stockShopIDs[i] != "" {
+				rank, ok := shopWarehouseRanks[stockShopIDs[i]][s.WarehouseID]
+				if !ok {
 					continue
 				}
-				demandQty -= reserveQty
+				priority = rank
+			}
 
-				reservedStocks = append(reservedStocks, model.WarehouseStock{
-					ProductID:   s.ProductID,
-					WarehouseID: s.WarehouseID,
-					Reserved:    reserveQty,
-				})
+			productID = s.ProductID
+			key := stockKey{warehouseID: s.WarehouseID.String(), productID: s.ProductID.String()}
+			sources = append(sources, allocation.Source{
+				WarehouseID: s.WarehouseID,
+				Available:   s.Quantity - s.Reserved - expiredStockLotQty(stockLots[key], today),
+				Priority:    priority,
+				Distance:    distances[s.WarehouseID],
+			})
+		}
 
-				if demandQty == 0 {
-					break
-				}
+		allocations, ok := strategy.Allocate(sources, stock.Quantity)
+		if !ok {
+			backorder, err := s.createBackorder(ctx, tx, req.OrderRef, stock, stockShopIDs[i])
+			if err != nil {
+				return nil, nil, err
 			}
+
+			backordered = append(backordered, stock.ProductID)
+			result = append(result, payload.ReserveStocksResp{
+				ProductID:         stock.ProductID,
+				BackorderID:       backorder.ID.String(),
+				BackorderQuantity: backorder.Quantity,
+			})
+			continue
 		}
-		if demandQty > 0 {
-			return result, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "insufficient stock for product "+stock.ProductID)
+
+		for _, a := range allocations {
+			key := stockKey{warehouseID: a.WarehouseID.String(), productID: productID.String()}
+			result = append(result, payload.ReserveStocksResp{
+				ProductID:        productID.String(),
+				WarehouseID:      a.WarehouseID.String(),
+				ReservedQuantity: a.Quantity,
+				Lots:             reserveStockLots(stockLots[key], a.Quantity, today),
+			})
 		}
 	}
 
-	for _, reservedStock := range reservedStocks {
-		err = s.stockRepo.WithTX(tx).AddStockQtyAndReserveQty(ctx, reservedStock.ProductID.String(), reservedStock.WarehouseID.String(), 0, reservedStock.Reserved)
+	// stocks and lots are always updated in the same order, so concurrent
+	// reservations cannot deadlock on each other's row locks
+	reserves := slices.DeleteFunc(slices.Clone(result), func(r payload.ReserveStocksResp) bool {
+		return r.BackorderID != ""
+	})
+	slices.SortFunc(reserves, func(a, b payload.ReserveStocksResp) int {
+		return cmp.Or(cmp.Compare(a.WarehouseID, b.WarehouseID), cmp.Compare(a.ProductID, b.ProductID))
+	})
+
+	var lotReserves []payload.ReservedStockLot
+	for _, reserved := range reserves {
+		ok, err := s.stockRepo.WithTX(tx).ReserveStockQty(ctx, reserved.ProductID, reserved.WarehouseID, reserved.ReservedQuantity)
 		if err != nil {
-			return result, err
+			return nil, nil, err
 		}
+		if !ok {
+			return nil, nil, apperr.WrapWithCode(errReserveConflict, apperr.CodeHTTPBadRequest, "insufficient stock for product "+reserved.ProductID)
+		}
+		lotReserves = append(lotReserves, reserved.Lots...)
+	}
 
-		result = append(result, payload.ReserveStocksResp{
-			ProductID:        reservedStock.ProductID.String(),
-			WarehouseID:      reservedStock.WarehouseID.String(),
-			ReservedQuantity: reservedStock.Reserved,
-		})
+	slices.SortFunc(lotReserves, func(a, b payload.ReservedStockLot) int {
+		return cmp.Compare(a.LotID, b.LotID)
+	})
+	for _, lot := range lotReserves {
+		ok, err := s.stockLotRepo.WithTX(tx).ReserveLotQty(ctx, lot.LotID, lot.Quantity)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return nil, nil, apperr.WrapWithCode(errReserveConflict, apperr.CodeHTTPBadRequest, "insufficient stock in lot "+lot.LotNumber)
+		}
+
+		err = s.stockLotRepo.WithTX(tx).AddLotReservationQty(ctx, lot.LotID, req.OrderRef, lot.Quantity)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// a new backorder takes whatever stock is left once the earlier ones had
+	// theirs
+	if len(backordered) > 0 {
+		if err := s.allocateBackorders(ctx, tx, backordered); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	alerts, err = s.evaluateStockAlerts(ctx, tx, productIDs)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	if err := tx.Commit().Error; err != nil {
-		return result, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+		return nil, nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
 	}
 
-	return result, nil
+	return result, alerts, nil
 }
 
 func (s *stockService) RollbackReserves(ctx context.Context, req payload.RollbackReservesReq) (err error) {
@@ -276,17 +579,63 @@ func (s *stockService) RollbackReserves(ctx context.Context, req payload.Rollbac
 	tx := s.db.Begin()
 	defer tx.Rollback()
 
+	var productIDs []string
+	var warehouseIDs []string
+	for _, stock := range req.Stocks {
+		productIDs = append(productIDs, stock.ProductID)
+		warehouseIDs = append(warehouseIDs, stock.WarehouseID)
+	}
+
+	lots, err := s.stockLotRepo.WithTX(tx).WithLockForUpdate().GetStockLots(ctx, payload.GetStockLotsReq{
+		WarehouseIDIN: warehouseIDs,
+		ProductIDIN:   productIDs,
+	})
+	if err != nil {
+		return err
+	}
+	stockLots := groupStockLots(lots)
+
+	lotReservations, err := s.stockLotReservations(ctx, tx, lots, req.OrderRef)
+	if err != nil {
+		return err
+	}
+
 	for _, stock := range req.Stocks {
 		err = s.stockRepo.WithTX(tx).AddStockQtyAndReserveQty(ctx, stock.ProductID, stock.WarehouseID, 0, -stock.Quantity)
 		if err != nil {
 			return err
 		}
+
+		key := stockKey{warehouseID: stock.WarehouseID, productID: stock.ProductID}
+		for _, lot := range releaseStockLots(stockLots[key], lotReservations, req.OrderRef, stock.Quantity) {
+			err = s.stockLotRepo.WithTX(tx).AddLotQtyAndReserveQty(ctx, lot.StockLotID.String(), 0, -lot.Quantity)
+			if err != nil {
+				return err
+			}
+
+			err = s.stockLotRepo.WithTX(tx).AddLotReservationQty(ctx, lot.StockLotID.String(), lot.OrderRef, -lot.Quantity)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.allocateBackorders(ctx, tx, productIDs); err != nil {
+		return err
+	}
+
+	alerts, err := s.evaluateStockAlerts(ctx, tx, productIDs)
+	if err != nil {
+		return err
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
 	}
 
+	s.publishStockAlerts(ctx, alerts)
+	s.publishStockAvailability(ctx, productIDs)
+
 	return nil
 }
 
@@ -303,16 +652,440 @@ func (s *stockService) CommitReserves(ctx context.Context, req payload.CommitRes
 	tx := s.db.Begin()
 	defer tx.Rollback()
 
+	var productIDs []string
+	var warehouseIDs []string
+	for _, stock := range req.Stocks {
+		productIDs = append(productIDs, stock.ProductID)
+		warehouseIDs = append(warehouseIDs, stock.WarehouseID)
+	}
+
+	lots, err := s.stockLotRepo.WithTX(tx).WithLockForUpdate().GetStockLots(ctx, payload.GetStockLotsReq{
+		WarehouseIDIN: warehouseIDs,
+		ProductIDIN:   productIDs,
+	})
+	if err != nil {
+		return err
+	}
+	stockLots := groupStockLots(lots)
+
+	lotReservations, err := s.stockLotReservations(ctx, tx, lots, req.OrderRef)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := s.serializedProducts(ctx, tx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	binStocks, err := s.stockBinRepo.WithTX(tx).WithLockForUpdate().GetBinStocks(ctx, payload.GetBinStocksReq{
+		WarehouseIDIN: warehouseIDs,
+		ProductIDIN:   productIDs,
+		InStockOnly:   true,
+	})
+	if err != nil {
+		return err
+	}
+	stockBins := groupBinStocks(binStocks)
+
+	var pickLists []*model.PickList
+	pickListByWarehouse := make(map[string]*model.PickList)
 	for _, stock := range req.Stocks {
 		err = s.stockRepo.WithTX(tx).AddStockQtyAndReserveQty(ctx, stock.ProductID, stock.WarehouseID, -stock.Quantity, -stock.Quantity)
 		if err != nil {
 			return err
 		}
+
+		key := stockKey{warehouseID: stock.WarehouseID, productID: stock.ProductID}
+		for _, lot := range releaseStockLots(stockLots[key], lotReservations, req.OrderRef, stock.Quantity) {
+			err = s.stockLotRepo.WithTX(tx).AddLotQtyAndReserveQty(ctx, lot.StockLotID.String(), -lot.Quantity, -lot.Quantity)
+			if err != nil {
+				return err
+			}
+
+			err = s.stockLotRepo.WithTX(tx).AddLotReservationQty(ctx, lot.StockLotID.String(), lot.OrderRef, -lot.Quantity)
+			if err != nil {
+				return err
+			}
+		}
+
+		warehouseID, err := uuid.Parse(stock.WarehouseID)
+		if err != nil {
+			return apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid warehouse ID")
+		}
+		productID, err := uuid.Parse(stock.ProductID)
+		if err != nil {
+			return apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid product ID")
+		}
+
+		pickList, ok := pickListByWarehouse[stock.WarehouseID]
+		if !ok {
+			pickList = &model.PickList{WarehouseID: warehouseID}
+			if req.OrderRef != "" {
+				pickList.OrderRef = &req.OrderRef
+			}
+			pickListByWarehouse[stock.WarehouseID] = pickList
+			pickLists = append(pickLists, pickList)
+		}
+
+		for _, item := range pickBinStocks(productID, stockBins[key], stock.Quantity) {
+			if item.BinID != nil {
+				err = s.stockBinRepo.WithTX(tx).AddBinStockQty(ctx, item.BinID.String(), stock.WarehouseID, stock.ProductID, -item.Quantity)
+				if err != nil {
+					return err
+				}
+			}
+			pickList.Items = append(pickList.Items, item)
+		}
+
+		if !serialized[stock.ProductID] {
+			if len(stock.SerialNumbers) > 0 {
+				return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product "+stock.ProductID+" is not serialized")
+			}
+			continue
+		}
+
+		err = s.sellStockSerials(ctx, tx, req.OrderRef, stock)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, pickList := range pickLists {
+		err = s.stockBinRepo.WithTX(tx).CreatePickList(ctx, pickList)
+		if err != nil {
+			return err
+		}
+	}
+
+	alerts, err := s.evaluateStockAlerts(ctx, tx, productIDs)
+	if err != nil {
+		return err
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
 	}
 
+	s.publishStockAlerts(ctx, alerts)
+	s.publishStockAvailability(ctx, productIDs)
+
 	return nil
 }
+
+func (s *stockService) SetStockThreshold(ctx context.Context, req payload.SetStockThresholdReq) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.SetStockThreshold")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	switch {
+	case req.WarehouseID != nil:
+		err = s.stockRepo.WithTX(tx).UpdateStockThreshold(ctx, req.ProductID.String(), req.WarehouseID.String(), req.Threshold)
+	case req.Threshold != nil:
+		err = s.stockAlertRepo.WithTX(tx).UpsertProductThreshold(ctx, &model.ProductStockThreshold{
+			ProductID: req.ProductID,
+			Threshold: *req.Threshold,
+		})
+	default:
+		err = s.stockAlertRepo.WithTX(tx).DeleteProductThreshold(ctx, req.ProductID.String())
+	}
+	if err != nil {
+		return err
+	}
+
+	alerts, err := s.evaluateStockAlerts(ctx, tx, []string{req.ProductID.String()})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.publishStockAlerts(ctx, alerts)
+
+	return nil
+}
+
+func (s *stockService) GetStockAlerts(ctx context.Context, req payload.GetStockAlertsReq) (result []model.StockAlert, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.GetStockAlerts")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	alerts, err := s.stockAlertRepo.GetStockAlerts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// SettleStockChanges allocates backorders and re-evaluates the stock alerts of
+// the given products inside tx. It is meant for modules that change stock
+// quantities outside of this service, so the follow-up work commits or rolls
+// back with the change. The alerts are sent by PublishStockChanges once tx is
+// committed.
+func (s *stockService) SettleStockChanges(ctx context.Context, tx *gorm.DB, productIDs []string) (result []model.StockAlert, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.SettleStockChanges")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if err := s.allocateBackorders(ctx, tx, productIDs); err != nil {
+		return nil, err
+	}
+
+	alerts, err := s.evaluateStockAlerts(ctx, tx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// PublishStockChanges sends out the alerts and the availability of a stock
+// change settled by SettleStockChanges after it is committed.
+func (s *stockService) PublishStockChanges(ctx context.Context, productIDs []string, alerts []model.StockAlert) {
+	s.publishStockAlerts(ctx, alerts)
+	s.publishStockAvailability(ctx, productIDs)
+}
+
+// warehouseDistances returns the distance from each stocked warehouse to the
+// destination. Warehouses without coordinates are left out.
+func (s *stockService) warehouseDistances(ctx context.Context, tx *gorm.DB, destination *payload.ReserveStocksCoords, stocks []model.WarehouseStock) (map[uuid.UUID]*float64, error) {
+	distances := make(map[uuid.UUID]*float64)
+	if destination == nil || len(stocks) == 0 {
+		return distances, nil
+	}
+
+	var warehouseIDs []string
+	for _, stock := range stocks {
+		warehouseIDs = append(warehouseIDs, stock.WarehouseID.String())
+	}
+
+	warehouses, err := s.warehouseRepo.WithTX(tx).GetWarehousesByIDs(ctx, warehouseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, warehouse := range warehouses {
+		if warehouse.Latitude == nil || warehouse.Longitude == nil {
+			continue
+		}
+		distance := allocation.Distance(*destination.Latitude, *destination.Longitude, *warehouse.Latitude, *warehouse.Longitude)
+		distances[warehouse.ID] = &distance
+	}
+	return distances, nil
+}
+
+// shopWarehouseRanks returns the rank of each warehouse assigned to the shops,
+// lower is reserved from first.
+func (s *stockService) shopWarehouseRanks(ctx context.Context, tx *gorm.DB, shopIDs []string) (map[string]map[uuid.UUID]int, error) {
+	ranks := make(map[string]map[uuid.UUID]int)
+	if len(shopIDs) == 0 {
+		return ranks, nil
+	}
+
+	shopWarehouses, err := s.shopWarehouseRepo.WithTX(tx).GetShopWarehouses(ctx, shopwarehousepayload.GetShopWarehousesReq{
+		ShopIDIN: shopIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, shopWarehouse := range shopWarehouses {
+		shopID := shopWarehouse.ShopID.String()
+		if ranks[shopID] == nil {
+			ranks[shopID] = make(map[uuid.UUID]int)
+		}
+		ranks[shopID][shopWarehouse.WarehouseID] = len(ranks[shopID])
+	}
+	return ranks, nil
+}
+
+// evaluateStockAlerts compares the available stock of the given products against
+// their warehouse and product reorder thresholds, and records an alert for every
+// threshold whose state changed since its last alert. It must run inside the
+// transaction that mutated the stock so the recorded state stays consistent.
+func (s *stockService) evaluateStockAlerts(ctx context.Context, tx *gorm.DB, productIDs []string) ([]model.StockAlert, error) {
+	stocks, err := s.stockRepo.WithTX(tx).GetStocks(ctx, payload.GetStocksReq{
+		ProductIDIN: productIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	productThresholds, err := s.stockAlertRepo.WithTX(tx).GetProductThresholds(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	latestAlerts, err := s.stockAlertRepo.WithTX(tx).GetLatestStockAlerts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	lastStatuses := make(map[string]string)
+	for _, alert := range latestAlerts {
+		lastStatuses[stockAlertKey(alert.Scope, alert.WarehouseID, alert.ProductID)] = alert.Status
+	}
+
+	var alerts []model.StockAlert
+	evaluated := make(map[string]bool)
+	evaluate := func(scope string, warehouseID *uuid.UUID, productID uuid.UUID, available int, threshold int) {
+		key := stockAlertKey(scope, warehouseID, productID)
+		evaluated[key] = true
+
+		status, changed := nextStockAlertStatus(lastStatuses[key], available, threshold)
+		if !changed {
+			return
+		}
+		alerts = append(alerts, model.StockAlert{
+			Scope:       scope,
+			WarehouseID: warehouseID,
+			ProductID:   productID,
+			Status:      status,
+			Available:   available,
+			Threshold:   threshold,
+		})
+	}
+
+	productAvailables := make(map[uuid.UUID]int)
+	stockAvailables := make(map[string]int)
+	for _, stock := range stocks {
+		available := stock.Quantity - stock.Reserved
+		productAvailables[stock.ProductID] += available
+		stockAvailables[stockAlertKey(constant.StockAlertScopeWarehouse, &stock.WarehouseID, stock.ProductID)] = available
+
+		if stock.ReorderThreshold != nil {
+			evaluate(constant.StockAlertScopeWarehouse, &stock.WarehouseID, stock.ProductID, available, *stock.ReorderThreshold)
+		}
+	}
+
+	for _, threshold := range productThresholds {
+		evaluate(constant.StockAlertScopeProduct, nil, threshold.ProductID, productAvailables[threshold.ProductID], threshold.Threshold)
+	}
+
+	// alerts whose threshold has been removed are no longer actionable
+	for _, alert := range latestAlerts {
+		key := stockAlertKey(alert.Scope, alert.WarehouseID, alert.ProductID)
+		if evaluated[key] || alert.Status == constant.StockAlertStatusRecovered {
+			continue
+		}
+
+		available := productAvailables[alert.ProductID]
+		if alert.Scope == constant.StockAlertScopeWarehouse {
+			available = stockAvailables[key]
+		}
+		alerts = append(alerts, model.StockAlert{
+			Scope:       alert.Scope,
+			WarehouseID: alert.WarehouseID,
+			ProductID:   alert.ProductID,
+			Status:      constant.StockAlertStatusRecovered,
+			Available:   available,
+			Threshold:   alert.Threshold,
+		})
+	}
+
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+
+	if err := s.stockAlertRepo.WithTX(tx).CreateStockAlerts(ctx, alerts); err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// publishStockAlerts emits the recorded alerts to purchasing. It runs in the
+// background after the stock mutation is committed, so a failing webhook never
+// fails the mutation itself.
+func (s *stockService) publishStockAlerts(ctx context.Context, alerts []model.StockAlert) {
+	if len(alerts) == 0 {
+		return
+	}
+
+	var data []purchasingservice.NotifyStockAlertsReqData
+	for _, alert := range alerts {
+		s.logger.WithContext(ctx).Infow("Stock alert raised",
+			"scope", alert.Scope,
+			"warehouse_id", alert.WarehouseID,
+			"product_id", alert.ProductID,
+			"status", alert.Status,
+			"available", alert.Available,
+			"threshold", alert.Threshold,
+		)
+		data = append(data, purchasingservice.NotifyStockAlertsReqData{
+			ID:          alert.ID,
+			Scope:       alert.Scope,
+			WarehouseID: alert.WarehouseID,
+			ProductID:   alert.ProductID,
+			Status:      alert.Status,
+			Available:   alert.Available,
+			Threshold:   alert.Threshold,
+			CreatedAt:   alert.CreatedAt,
+		})
+	}
+
+	go func(ctx context.Context) {
+		if err := s.purchasingSvc.NotifyStockAlerts(ctx, purchasingservice.NotifyStockAlertsReq{Alerts: data}); err != nil {
+			s.logger.WithContext(ctx).Errorw("Failed to notify stock alerts", "error", err)
+		}
+	}(context.WithoutCancel(ctx))
+}
+
+func stockAlertKey(scope string, warehouseID *uuid.UUID, productID uuid.UUID) string {
+	if warehouseID == nil {
+		return scope + ":" + productID.String()
+	}
+	return scope + ":" + warehouseID.String() + ":" + productID.String()
+}
+
+// nextStockAlertStatus returns the status to record when the available quantity
+// moves relative to the threshold, and whether it differs from the last status.
+func nextStockAlertStatus(lastStatus string, available int, threshold int) (string, bool) {
+	status := constant.StockAlertStatusRecovered
+	switch {
+	case available <= 0:
+		status = constant.StockAlertStatusOut
+	case available <= threshold:
+		status = constant.StockAlertStatusLow
+	}
+
+	if lastStatus == "" {
+		lastStatus = constant.StockAlertStatusRecovered
+	}
+
+	return status, status != lastStatus
+}
+
+// isRetryableReserveErr reports whether the reservation may succeed when it is
+// planned again: the stock changed under it, or postgres aborted the
+// transaction on a serialization failure or deadlock.
+func isRetryableReserveErr(err error) bool {
+	cause := apperr.RootCause(err)
+	if errors.Is(cause, errReserveConflict) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	return errors.As(cause, &pgErr) && (pgErr.Code == "40001" || pgErr.Code == "40P01")
+}
@@ -4,19 +4,38 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	purchasingservice "github.com/alifmufthi91/ecommerce-system/services/warehouse/external/purchasing_service"
+	purchasingSvcMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/external/purchasing_service/mocks"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	shopWarehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/repository/mocks"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
 	stockRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository/mocks"
+	warehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository/mocks"
 )
 
+// noBackorders is a backorder repository without backorder products or
+// waiting backorders, for the tests that do not cover them.
+func noBackorders(t *testing.T) *stockRepoMock.BackorderRepository {
+	backorderRepo := stockRepoMock.NewBackorderRepository(t)
+	backorderRepo.On("WithTX", mock.Anything).Return(backorderRepo).Maybe()
+	backorderRepo.On("WithLockForUpdate").Return(backorderRepo).Maybe()
+	backorderRepo.On("GetBackorderProducts", mock.Anything, mock.Anything).Return([]model.BackorderProduct{}, nil).Maybe()
+	backorderRepo.On("GetBackorders", mock.Anything, mock.Anything).Return([]model.Backorder{}, nil).Maybe()
+	return backorderRepo
+}
+
 func TestGetStocks_ShouldSuccess(t *testing.T) {
 	type dependencyMocks struct {
 		stockRepo *stockRepoMock.StockRepository
@@ -136,8 +155,12 @@ func TestGetStocks_ShouldReturnError(t *testing.T) {
 
 func TestTransferStock_ShouldSuccess(t *testing.T) {
 	type dependencyMocks struct {
-		db        sqlmock.Sqlmock
-		stockRepo *stockRepoMock.StockRepository
+		db              sqlmock.Sqlmock
+		stockRepo       *stockRepoMock.StockRepository
+		stockAlertRepo  *stockRepoMock.StockAlertRepository
+		stockLotRepo    *stockRepoMock.StockLotRepository
+		stockSerialRepo *stockRepoMock.StockSerialRepository
+		warehouseRepo   *warehouseRepoMock.WarehouseRepository
 	}
 
 	mockDb, err := pkg.SetupMockDB()
@@ -167,6 +190,13 @@ func TestTransferStock_ShouldSuccess(t *testing.T) {
 			setup: func(m dependencyMocks) {
 				m.db.ExpectBegin()
 
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseToID.String()}).
+					Return([]model.Warehouse{{ID: warehouseToID, Status: constant.WarehouseStatusActive}}, nil)
+
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
 				m.stockRepo.On("WithLockForUpdate", mock.Anything).
@@ -193,9 +223,26 @@ func TestTransferStock_ShouldSuccess(t *testing.T) {
 				m.stockRepo.On("UpdateStock", mock.Anything, mock.Anything).
 					Return(nil)
 
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("MoveStockLots", mock.Anything, warehouseProductID.String(), warehouseFromID.String(), warehouseToID.String(), 50).
+					Return(0, nil)
+
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, []string{warehouseProductID.String()}).
+					Return([]model.SerializedProduct{}, nil)
+
 				m.stockRepo.On("CreateStockTransfer", mock.Anything, mock.Anything).
 					Return(nil)
 
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
 				m.db.ExpectCommit()
 			},
 		},
@@ -210,6 +257,13 @@ func TestTransferStock_ShouldSuccess(t *testing.T) {
 			setup: func(m dependencyMocks) {
 				m.db.ExpectBegin()
 
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseToID.String()}).
+					Return([]model.Warehouse{{ID: warehouseToID, Status: constant.WarehouseStatusActive}}, nil)
+
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
 				m.stockRepo.On("WithLockForUpdate", mock.Anything).
@@ -232,9 +286,90 @@ func TestTransferStock_ShouldSuccess(t *testing.T) {
 				m.stockRepo.On("CreateStock", mock.Anything, mock.Anything).
 					Return(nil)
 
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("MoveStockLots", mock.Anything, warehouseProductID.String(), warehouseFromID.String(), warehouseToID.String(), 50).
+					Return(0, nil)
+
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, []string{warehouseProductID.String()}).
+					Return([]model.SerializedProduct{}, nil)
+
+				m.stockRepo.On("CreateStockTransfer", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - serials move with the stock",
+			req: payload.TransferStockReq{
+				ProductID:       warehouseProductID,
+				FromWarehouseID: warehouseFromID,
+				ToWarehouseID:   warehouseToID,
+				Quantity:        2,
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseToID.String()}).
+					Return([]model.Warehouse{{ID: warehouseToID, Status: constant.WarehouseStatusActive}}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("WithLockForUpdate", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							ID:          uuid.New(),
+							WarehouseID: warehouseFromID,
+							ProductID:   warehouseProductID,
+							Quantity:    5,
+						},
+					}, nil)
+
+				m.stockRepo.On("UpdateStock", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockRepo.On("CreateStock", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("MoveStockLots", mock.Anything, warehouseProductID.String(), warehouseFromID.String(), warehouseToID.String(), 2).
+					Return(0, nil)
+
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, []string{warehouseProductID.String()}).
+					Return([]model.SerializedProduct{{ProductID: warehouseProductID}}, nil)
+				m.stockSerialRepo.On("MoveStockSerials", mock.Anything, warehouseProductID.String(), warehouseFromID.String(), warehouseToID.String(), 2).
+					Return(nil)
+
 				m.stockRepo.On("CreateStockTransfer", mock.Anything, mock.Anything).
 					Return(nil)
 
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
 				m.db.ExpectCommit()
 			},
 		},
@@ -243,14 +378,24 @@ func TestTransferStock_ShouldSuccess(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Given
 			mocks := dependencyMocks{
-				db:        mockDb.Mock,
-				stockRepo: stockRepoMock.NewStockRepository(t),
+				db:              mockDb.Mock,
+				stockRepo:       stockRepoMock.NewStockRepository(t),
+				stockAlertRepo:  stockRepoMock.NewStockAlertRepository(t),
+				stockLotRepo:    stockRepoMock.NewStockLotRepository(t),
+				stockSerialRepo: stockRepoMock.NewStockSerialRepository(t),
+				warehouseRepo:   warehouseRepoMock.NewWarehouseRepository(t),
 			}
 			logger := pkg.InitLogger(&config.Config{})
 			stockSvc := stockService{
-				logger:    logger,
-				db:        mockDb.Db,
-				stockRepo: mocks.stockRepo,
+				logger:          logger,
+				db:              mockDb.Db,
+				backorderRepo:   noBackorders(t),
+				availability:    newAvailabilityBroker(),
+				stockRepo:       mocks.stockRepo,
+				stockAlertRepo:  mocks.stockAlertRepo,
+				stockLotRepo:    mocks.stockLotRepo,
+				stockSerialRepo: mocks.stockSerialRepo,
+				warehouseRepo:   mocks.warehouseRepo,
 			}
 
 			tt.setup(mocks)
@@ -268,8 +413,11 @@ func TestTransferStock_ShouldSuccess(t *testing.T) {
 
 func TestTransferStock_ShouldReturnError(t *testing.T) {
 	type dependencyMocks struct {
-		db        sqlmock.Sqlmock
-		stockRepo *stockRepoMock.StockRepository
+		db             sqlmock.Sqlmock
+		stockRepo      *stockRepoMock.StockRepository
+		stockAlertRepo *stockRepoMock.StockAlertRepository
+		stockLotRepo   *stockRepoMock.StockLotRepository
+		warehouseRepo  *warehouseRepoMock.WarehouseRepository
 	}
 
 	mockDb, err := pkg.SetupMockDB()
@@ -300,6 +448,13 @@ func TestTransferStock_ShouldReturnError(t *testing.T) {
 			setup: func(m dependencyMocks) {
 				m.db.ExpectBegin()
 
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseToID.String()}).
+					Return([]model.Warehouse{{ID: warehouseToID, Status: constant.WarehouseStatusActive}}, nil)
+
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
 				m.stockRepo.On("WithLockForUpdate", mock.Anything).
@@ -332,6 +487,28 @@ func TestTransferStock_ShouldReturnError(t *testing.T) {
 			},
 			err: "from_warehouse_id and to_warehouse_id cannot be the same",
 		},
+		{
+			name: "error - to warehouse is not active",
+			req: payload.TransferStockReq{
+				ProductID:       warehouseProductID,
+				FromWarehouseID: warehouseFromID,
+				ToWarehouseID:   warehouseToID,
+				Quantity:        50,
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseToID.String()}).
+					Return([]model.Warehouse{{ID: warehouseToID, Status: constant.WarehouseStatusArchived}}, nil)
+
+				m.db.ExpectRollback()
+			},
+			err: "warehouse is not active",
+		},
 		{
 			name: "error - stock from warehouse_id not found",
 			req: payload.TransferStockReq{
@@ -343,6 +520,13 @@ func TestTransferStock_ShouldReturnError(t *testing.T) {
 			setup: func(m dependencyMocks) {
 				m.db.ExpectBegin()
 
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseToID.String()}).
+					Return([]model.Warehouse{{ID: warehouseToID, Status: constant.WarehouseStatusActive}}, nil)
+
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
 				m.stockRepo.On("WithLockForUpdate", mock.Anything).
@@ -355,19 +539,73 @@ func TestTransferStock_ShouldReturnError(t *testing.T) {
 			},
 			err: "stock from warehouse_id not found",
 		},
+		{
+			name: "error - only expired stock left to move",
+			req: payload.TransferStockReq{
+				ProductID:       warehouseProductID,
+				FromWarehouseID: warehouseFromID,
+				ToWarehouseID:   warehouseToID,
+				Quantity:        50,
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseToID.String()}).
+					Return([]model.Warehouse{{ID: warehouseToID, Status: constant.WarehouseStatusActive}}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("WithLockForUpdate", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							ID:          uuid.New(),
+							WarehouseID: warehouseFromID,
+							ProductID:   warehouseProductID,
+							Quantity:    100,
+							Reserved:    10,
+						},
+					}, nil)
+
+				m.stockRepo.On("CreateStock", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("MoveStockLots", mock.Anything, warehouseProductID.String(), warehouseFromID.String(), warehouseToID.String(), 50).
+					Return(5, nil)
+
+				m.db.ExpectRollback()
+			},
+			err: "insufficient unexpired stock in from warehouse",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Given
 			mocks := dependencyMocks{
-				db:        mockDb.Mock,
-				stockRepo: stockRepoMock.NewStockRepository(t),
+				db:             mockDb.Mock,
+				stockRepo:      stockRepoMock.NewStockRepository(t),
+				stockAlertRepo: stockRepoMock.NewStockAlertRepository(t),
+				stockLotRepo:   stockRepoMock.NewStockLotRepository(t),
+				warehouseRepo:  warehouseRepoMock.NewWarehouseRepository(t),
 			}
 			logger := pkg.InitLogger(&config.Config{})
 			stockSvc := stockService{
-				logger:    logger,
-				db:        mockDb.Db,
-				stockRepo: mocks.stockRepo,
+				logger:         logger,
+				db:             mockDb.Db,
+				backorderRepo:  noBackorders(t),
+				availability:   newAvailabilityBroker(),
+				stockRepo:      mocks.stockRepo,
+				stockAlertRepo: mocks.stockAlertRepo,
+				stockLotRepo:   mocks.stockLotRepo,
+				warehouseRepo:  mocks.warehouseRepo,
 			}
 
 			if tt.setup != nil {
@@ -497,8 +735,12 @@ func TestGetStockAvailablesByProduct_ShouldReturnError(t *testing.T) {
 
 func TestReserveStocks_ShouldSuccess(t *testing.T) {
 	type dependencyMocks struct {
-		db        sqlmock.Sqlmock
-		stockRepo *stockRepoMock.StockRepository
+		db                sqlmock.Sqlmock
+		stockRepo         *stockRepoMock.StockRepository
+		stockAlertRepo    *stockRepoMock.StockAlertRepository
+		stockLotRepo      *stockRepoMock.StockLotRepository
+		shopWarehouseRepo *shopWarehouseRepoMock.ShopWarehouseRepository
+		warehouseRepo     *warehouseRepoMock.WarehouseRepository
 	}
 
 	mockDb, err := pkg.SetupMockDB()
@@ -508,6 +750,12 @@ func TestReserveStocks_ShouldSuccess(t *testing.T) {
 
 	productID := uuid.New()
 	productID2 := uuid.New()
+	shopID := uuid.New()
+	// warehouse IDs are fixed because equally ranked warehouses are reserved
+	// from in warehouse ID order
+	warehouseID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	warehouseID2 := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	latitude, longitude := -6.2, 106.8
 
 	tests := []struct {
 		name  string
@@ -533,8 +781,6 @@ func TestReserveStocks_ShouldSuccess(t *testing.T) {
 
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
-				m.stockRepo.On("WithLockForUpdate", mock.Anything).
-					Return(m.stockRepo)
 
 				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
 					Return([]model.WarehouseStock{
@@ -547,9 +793,84 @@ func TestReserveStocks_ShouldSuccess(t *testing.T) {
 						},
 					}, nil)
 
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), mock.Anything, 0, req.Stocks[0].Quantity).
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), mock.Anything, req.Stocks[0].Quantity).
+					Return(true, nil)
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+			expectedLen: 1,
+		},
+		{
+			name: "success - first expiry first out",
+			req: payload.ReserveStocksReq{
+				Stocks: []payload.ReserveStocksData{
+					{
+						ProductID: productID.String(),
+						Quantity:  50,
+					},
+				},
+				OrderRef: "order-1",
+			},
+			setup: func(m dependencyMocks, req payload.ReserveStocksReq) {
+				expired := time.Now().AddDate(0, 0, -1)
+				expiresSoon := time.Now().AddDate(0, 0, 10)
+				expiresLater := time.Now().AddDate(0, 1, 0)
+				expiredLotID, soonLotID, laterLotID := uuid.New(), uuid.New(), uuid.New()
+
+				m.db.ExpectBegin()
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							ID:          uuid.New(),
+							WarehouseID: warehouseID,
+							ProductID:   productID,
+							Quantity:    100,
+						},
+					}, nil)
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{
+						{ID: expiredLotID, WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-1", ExpiryDate: &expired, Quantity: 30},
+						{ID: soonLotID, WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-2", ExpiryDate: &expiresSoon, Quantity: 20},
+						{ID: laterLotID, WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-3", ExpiryDate: &expiresLater, Quantity: 40},
+					}, nil)
+
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 50).
+					Return(true, nil)
+				m.stockLotRepo.On("ReserveLotQty", mock.Anything, soonLotID.String(), 20).
+					Return(true, nil)
+				m.stockLotRepo.On("ReserveLotQty", mock.Anything, laterLotID.String(), 30).
+					Return(true, nil)
+				m.stockLotRepo.On("AddLotReservationQty", mock.Anything, soonLotID.String(), "order-1", 20).
+					Return(nil)
+				m.stockLotRepo.On("AddLotReservationQty", mock.Anything, laterLotID.String(), "order-1", 30).
 					Return(nil)
 
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
 				m.db.ExpectCommit()
 			},
 			expectedLen: 1,
@@ -569,31 +890,41 @@ func TestReserveStocks_ShouldSuccess(t *testing.T) {
 
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
-				m.stockRepo.On("WithLockForUpdate", mock.Anything).
-					Return(m.stockRepo)
 
 				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
 					Return([]model.WarehouseStock{
 						{
 							ID:          uuid.New(),
-							WarehouseID: uuid.New(),
+							WarehouseID: warehouseID2,
 							ProductID:   productID,
 							Quantity:    30,
 							Reserved:    10,
 						},
 						{
 							ID:          uuid.New(),
-							WarehouseID: uuid.New(),
+							WarehouseID: warehouseID,
 							ProductID:   productID,
 							Quantity:    70,
 							Reserved:    20,
 						},
 					}, nil)
 
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), mock.Anything, 0, req.Stocks[0].Quantity-20).
-					Return(nil)
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), mock.Anything, 0, 20).
-					Return(nil)
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), req.Stocks[0].Quantity-20).
+					Return(true, nil)
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID2.String(), 20).
+					Return(true, nil)
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
 
 				m.db.ExpectCommit()
 			},
@@ -618,21 +949,19 @@ func TestReserveStocks_ShouldSuccess(t *testing.T) {
 
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
-				m.stockRepo.On("WithLockForUpdate", mock.Anything).
-					Return(m.stockRepo)
 
 				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
 					Return([]model.WarehouseStock{
 						{
 							ID:          uuid.New(),
-							WarehouseID: uuid.New(),
+							WarehouseID: warehouseID2,
 							ProductID:   productID,
 							Quantity:    30,
 							Reserved:    10,
 						},
 						{
 							ID:          uuid.New(),
-							WarehouseID: uuid.New(),
+							WarehouseID: warehouseID,
 							ProductID:   productID,
 							Quantity:    50,
 							Reserved:    10,
@@ -646,74 +975,36 @@ func TestReserveStocks_ShouldSuccess(t *testing.T) {
 						},
 					}, nil)
 
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), mock.Anything, 0, req.Stocks[0].Quantity-20).
-					Return(nil)
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), mock.Anything, 0, 20).
-					Return(nil)
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID2.String(), mock.Anything, 0, req.Stocks[1].Quantity).
-					Return(nil)
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), req.Stocks[0].Quantity-20).
+					Return(true, nil)
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID2.String(), 20).
+					Return(true, nil)
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID2.String(), mock.Anything, req.Stocks[1].Quantity).
+					Return(true, nil)
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
 
 				m.db.ExpectCommit()
 			},
 			expectedLen: 3,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mocks := dependencyMocks{
-				db:        mockDb.Mock,
-				stockRepo: stockRepoMock.NewStockRepository(t),
-			}
-			logger := pkg.InitLogger(&config.Config{})
-			stockSvc := stockService{
-				logger:    logger,
-				db:        mockDb.Db,
-				stockRepo: mocks.stockRepo,
-			}
-
-			if tt.setup != nil {
-				tt.setup(mocks, tt.req)
-			}
-
-			resp, err := stockSvc.ReserveStocks(context.Background(), tt.req)
-
-			assert.NoError(t, err)
-			assert.Equal(t, tt.expectedLen, len(resp))
-			mocks.stockRepo.AssertExpectations(t)
-			mockDb.Mock.ExpectationsWereMet()
-		})
-	}
-}
-
-func TestReserveStocks_ShouldReturnError(t *testing.T) {
-	type dependencyMocks struct {
-		db        sqlmock.Sqlmock
-		stockRepo *stockRepoMock.StockRepository
-	}
-
-	mockDb, err := pkg.SetupMockDB()
-	if err != nil {
-		t.Errorf("Failed to open mock sql db, got error: %v", err)
-	}
-
-	productID := uuid.New()
-
-	tests := []struct {
-		name  string
-		req   payload.ReserveStocksReq
-		setup func(
-			m dependencyMocks,
-			req payload.ReserveStocksReq,
-		)
-		err string
-	}{
 		{
-			name: "error - failed to get stocks",
+			name: "success - shop warehouses in priority order",
 			req: payload.ReserveStocksReq{
 				Stocks: []payload.ReserveStocksData{
 					{
 						ProductID: productID.String(),
+						ShopID:    shopID.String(),
 						Quantity:  50,
 					},
 				},
@@ -723,33 +1014,6 @@ func TestReserveStocks_ShouldReturnError(t *testing.T) {
 
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
-				m.stockRepo.On("WithLockForUpdate", mock.Anything).
-					Return(m.stockRepo)
-
-				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
-					Return(nil, errors.New("failed to get stocks"))
-
-				m.db.ExpectRollback()
-			},
-			err: "failed to get stocks",
-		},
-		{
-			name: "error - insufficient stock",
-			req: payload.ReserveStocksReq{
-				Stocks: []payload.ReserveStocksData{
-					{
-						ProductID: productID.String(),
-						Quantity:  150,
-					},
-				},
-			},
-			setup: func(m dependencyMocks, req payload.ReserveStocksReq) {
-				m.db.ExpectBegin()
-
-				m.stockRepo.On("WithTX", mock.Anything).
-					Return(m.stockRepo)
-				m.stockRepo.On("WithLockForUpdate", mock.Anything).
-					Return(m.stockRepo)
 
 				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
 					Return([]model.WarehouseStock{
@@ -757,18 +1021,56 @@ func TestReserveStocks_ShouldReturnError(t *testing.T) {
 							ID:          uuid.New(),
 							WarehouseID: uuid.New(),
 							ProductID:   productID,
+							Quantity:    500,
+						},
+						{
+							ID:          uuid.New(),
+							WarehouseID: warehouseID2,
+							ProductID:   productID,
 							Quantity:    100,
+						},
+						{
+							ID:          uuid.New(),
+							WarehouseID: warehouseID,
+							ProductID:   productID,
+							Quantity:    30,
 							Reserved:    10,
 						},
 					}, nil)
 
-				m.db.ExpectRollback()
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.shopWarehouseRepo.On("WithTX", mock.Anything).
+					Return(m.shopWarehouseRepo)
+				m.shopWarehouseRepo.On("GetShopWarehouses", mock.Anything, mock.Anything).
+					Return([]model.ShopWarehouse{
+						{ShopID: shopID, WarehouseID: warehouseID, Priority: 0},
+						{ShopID: shopID, WarehouseID: warehouseID2, Priority: 1},
+					}, nil)
+
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 20).
+					Return(true, nil)
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID2.String(), 30).
+					Return(true, nil)
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
 			},
-			err: "insufficient stock",
+			expectedLen: 2,
 		},
 		{
-			name: "error - failed to add stock quantity and reserve quantity",
+			name: "success - largest stock strategy",
 			req: payload.ReserveStocksReq{
+				Strategy: constant.AllocationStrategyLargestStock,
 				Stocks: []payload.ReserveStocksData{
 					{
 						ProductID: productID.String(),
@@ -781,62 +1083,1279 @@ func TestReserveStocks_ShouldReturnError(t *testing.T) {
 
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
-				m.stockRepo.On("WithLockForUpdate", mock.Anything).
-					Return(m.stockRepo)
 
 				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
 					Return([]model.WarehouseStock{
 						{
 							ID:          uuid.New(),
-							WarehouseID: uuid.New(),
+							WarehouseID: warehouseID2,
+							ProductID:   productID,
+							Quantity:    30,
+						},
+						{
+							ID:          uuid.New(),
+							WarehouseID: warehouseID,
+							ProductID:   productID,
+							Quantity:    60,
+						},
+					}, nil)
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 50).
+					Return(true, nil)
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+			expectedLen: 1,
+		},
+		{
+			name: "success - nearest strategy",
+			req: payload.ReserveStocksReq{
+				Strategy: constant.AllocationStrategyNearest,
+				Destination: &payload.ReserveStocksCoords{
+					Latitude:  &latitude,
+					Longitude: &longitude,
+				},
+				Stocks: []payload.ReserveStocksData{
+					{
+						ProductID: productID.String(),
+						Quantity:  50,
+					},
+				},
+			},
+			setup: func(m dependencyMocks, req payload.ReserveStocksReq) {
+				m.db.ExpectBegin()
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							ID:          uuid.New(),
+							WarehouseID: warehouseID2,
+							ProductID:   productID,
+							Quantity:    100,
+						},
+						{
+							ID:          uuid.New(),
+							WarehouseID: warehouseID,
+							ProductID:   productID,
+							Quantity:    100,
+						},
+					}, nil)
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				farLatitude, farLongitude := 3.6, 98.7
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehousesByIDs", mock.Anything, []string{warehouseID2.String(), warehouseID.String()}).
+					Return([]model.Warehouse{
+						{ID: warehouseID2, Latitude: &farLatitude, Longitude: &farLongitude},
+						{ID: warehouseID, Latitude: &latitude, Longitude: &longitude},
+					}, nil)
+
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 50).
+					Return(true, nil)
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+			expectedLen: 1,
+		},
+		{
+			name: "success - planned again after stock was taken",
+			req: payload.ReserveStocksReq{
+				Stocks: []payload.ReserveStocksData{
+					{
+						ProductID: productID.String(),
+						Quantity:  50,
+					},
+				},
+			},
+			setup: func(m dependencyMocks, req payload.ReserveStocksReq) {
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							ID:          uuid.New(),
+							WarehouseID: warehouseID,
+							ProductID:   productID,
+							Quantity:    100,
+						},
+					}, nil)
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.db.ExpectBegin()
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 50).
+					Return(false, nil).Once()
+				m.db.ExpectRollback()
+
+				m.db.ExpectBegin()
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 50).
+					Return(true, nil).Once()
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+			expectedLen: 1,
+		},
+		{
+			name: "success - retried after serialization failure",
+			req: payload.ReserveStocksReq{
+				Stocks: []payload.ReserveStocksData{
+					{
+						ProductID: productID.String(),
+						Quantity:  50,
+					},
+				},
+			},
+			setup: func(m dependencyMocks, req payload.ReserveStocksReq) {
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							ID:          uuid.New(),
+							WarehouseID: warehouseID,
+							ProductID:   productID,
+							Quantity:    100,
+						},
+					}, nil)
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.db.ExpectBegin()
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 50).
+					Return(false, apperr.WrapWithCode(&pgconn.PgError{Code: "40P01"}, apperr.CodeHTTPInternalServerError, "failed to reserve stock quantity")).Once()
+				m.db.ExpectRollback()
+
+				m.db.ExpectBegin()
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), warehouseID.String(), 50).
+					Return(true, nil).Once()
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+			expectedLen: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := dependencyMocks{
+				db:                mockDb.Mock,
+				stockRepo:         stockRepoMock.NewStockRepository(t),
+				stockAlertRepo:    stockRepoMock.NewStockAlertRepository(t),
+				stockLotRepo:      stockRepoMock.NewStockLotRepository(t),
+				shopWarehouseRepo: shopWarehouseRepoMock.NewShopWarehouseRepository(t),
+				warehouseRepo:     warehouseRepoMock.NewWarehouseRepository(t),
+			}
+			logger := pkg.InitLogger(&config.Config{})
+			stockSvc := stockService{
+				logger:            logger,
+				db:                mockDb.Db,
+				backorderRepo:     noBackorders(t),
+				availability:      newAvailabilityBroker(),
+				stockRepo:         mocks.stockRepo,
+				stockAlertRepo:    mocks.stockAlertRepo,
+				stockLotRepo:      mocks.stockLotRepo,
+				shopWarehouseRepo: mocks.shopWarehouseRepo,
+				warehouseRepo:     mocks.warehouseRepo,
+			}
+
+			if tt.setup != nil {
+				tt.setup(mocks, tt.req)
+			}
+
+			resp, err := stockSvc.ReserveStocks(context.Background(), tt.req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedLen, len(resp))
+			mocks.stockRepo.AssertExpectations(t)
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestReserveStocks_ShouldReturnError(t *testing.T) {
+	type dependencyMocks struct {
+		db                sqlmock.Sqlmock
+		stockRepo         *stockRepoMock.StockRepository
+		stockAlertRepo    *stockRepoMock.StockAlertRepository
+		stockLotRepo      *stockRepoMock.StockLotRepository
+		shopWarehouseRepo *shopWarehouseRepoMock.ShopWarehouseRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	shopID := uuid.New()
+
+	tests := []struct {
+		name  string
+		req   payload.ReserveStocksReq
+		setup func(
+			m dependencyMocks,
+			req payload.ReserveStocksReq,
+		)
+		err string
+	}{
+		{
+			name: "error - failed to get stocks",
+			req: payload.ReserveStocksReq{
+				Stocks: []payload.ReserveStocksData{
+					{
+						ProductID: productID.String(),
+						Quantity:  50,
+					},
+				},
+			},
+			setup: func(m dependencyMocks, req payload.ReserveStocksReq) {
+				m.db.ExpectBegin()
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return(nil, errors.New("failed to get stocks"))
+
+				m.db.ExpectRollback()
+			},
+			err: "failed to get stocks",
+		},
+		{
+			name: "error - expired lots cannot be reserved",
+			req: payload.ReserveStocksReq{
+				Stocks: []payload.ReserveStocksData{
+					{
+						ProductID: productID.String(),
+						Quantity:  20,
+					},
+				},
+			},
+			setup: func(m dependencyMocks, req payload.ReserveStocksReq) {
+				warehouseID := uuid.New()
+				expired := time.Now().AddDate(0, 0, -1)
+
+				m.db.ExpectBegin()
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							ID:          uuid.New(),
+							WarehouseID: warehouseID,
+							ProductID:   productID,
+							Quantity:    40,
+						},
+					}, nil)
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{
+						{ID: uuid.New(), WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-1", ExpiryDate: &expired, Quantity: 30},
+					}, nil)
+
+				m.db.ExpectRollback()
+			},
+			err: "insufficient stock",
+		},
+		{
+			name: "error - insufficient stock",
+			req: payload.ReserveStocksReq{
+				Stocks: []payload.ReserveStocksData{
+					{
+						ProductID: productID.String(),
+						Quantity:  150,
+					},
+				},
+			},
+			setup: func(m dependencyMocks, req payload.ReserveStocksReq) {
+				m.db.ExpectBegin()
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							ID:          uuid.New(),
+							WarehouseID: uuid.New(),
 							ProductID:   productID,
 							Quantity:    100,
 							Reserved:    10,
 						},
 					}, nil)
 
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), mock.Anything, 0, req.Stocks[0].Quantity).
-					Return(errors.New("failed to add stock quantity and reserve quantity"))
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.db.ExpectRollback()
+			},
+			err: "insufficient stock",
+		},
+		{
+			name: "error - failed to add stock quantity and reserve quantity",
+			req: payload.ReserveStocksReq{
+				Stocks: []payload.ReserveStocksData{
+					{
+						ProductID: productID.String(),
+						Quantity:  50,
+					},
+				},
+			},
+			setup: func(m dependencyMocks, req payload.ReserveStocksReq) {
+				m.db.ExpectBegin()
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							ID:          uuid.New(),
+							WarehouseID: uuid.New(),
+							ProductID:   productID,
+							Quantity:    100,
+							Reserved:    10,
+						},
+					}, nil)
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), mock.Anything, req.Stocks[0].Quantity).
+					Return(false, errors.New("failed to reserve stock quantity"))
+
+				m.db.ExpectRollback()
+			},
+			err: "failed to reserve stock quantity",
+		},
+		{
+			name: "error - stock keeps being taken by concurrent reservations",
+			req: payload.ReserveStocksReq{
+				Stocks: []payload.ReserveStocksData{
+					{
+						ProductID: productID.String(),
+						Quantity:  50,
+					},
+				},
+			},
+			setup: func(m dependencyMocks, req payload.ReserveStocksReq) {
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							ID:          uuid.New(),
+							WarehouseID: uuid.New(),
+							ProductID:   productID,
+							Quantity:    100,
+						},
+					}, nil)
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.stockRepo.On("ReserveStockQty", mock.Anything, productID.String(), mock.Anything, req.Stocks[0].Quantity).
+					Return(false, nil).Times(maxReserveAttempts)
+
+				for range maxReserveAttempts {
+					m.db.ExpectBegin()
+					m.db.ExpectRollback()
+				}
+			},
+			err: "insufficient stock for product",
+		},
+		{
+			name: "error - invalid shop id",
+			req: payload.ReserveStocksReq{
+				Stocks: []payload.ReserveStocksData{
+					{
+						ProductID: productID.String(),
+						ShopID:    "invalid-uuid",
+						Quantity:  50,
+					},
+				},
+			},
+			setup: func(m dependencyMocks, req payload.ReserveStocksReq) {
+				m.db.ExpectBegin()
+				m.db.ExpectRollback()
+			},
+			err: "invalid shop ID",
+		},
+		{
+			name: "error - no stock in shop warehouses",
+			req: payload.ReserveStocksReq{
+				Stocks: []payload.ReserveStocksData{
+					{
+						ProductID: productID.String(),
+						ShopID:    shopID.String(),
+						Quantity:  50,
+					},
+				},
+			},
+			setup: func(m dependencyMocks, req payload.ReserveStocksReq) {
+				m.db.ExpectBegin()
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							ID:          uuid.New(),
+							WarehouseID: uuid.New(),
+							ProductID:   productID,
+							Quantity:    100,
+						},
+					}, nil)
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.shopWarehouseRepo.On("WithTX", mock.Anything).
+					Return(m.shopWarehouseRepo)
+				m.shopWarehouseRepo.On("GetShopWarehouses", mock.Anything, mock.Anything).
+					Return([]model.ShopWarehouse{
+						{ShopID: shopID, WarehouseID: uuid.New()},
+					}, nil)
+
+				m.db.ExpectRollback()
+			},
+			err: "insufficient stock",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := dependencyMocks{
+				db:                mockDb.Mock,
+				stockRepo:         stockRepoMock.NewStockRepository(t),
+				stockAlertRepo:    stockRepoMock.NewStockAlertRepository(t),
+				stockLotRepo:      stockRepoMock.NewStockLotRepository(t),
+				shopWarehouseRepo: shopWarehouseRepoMock.NewShopWarehouseRepository(t),
+			}
+			logger := pkg.InitLogger(&config.Config{})
+			stockSvc := stockService{
+				logger:            logger,
+				db:                mockDb.Db,
+				backorderRepo:     noBackorders(t),
+				availability:      newAvailabilityBroker(),
+				stockRepo:         mocks.stockRepo,
+				stockAlertRepo:    mocks.stockAlertRepo,
+				stockLotRepo:      mocks.stockLotRepo,
+				shopWarehouseRepo: mocks.shopWarehouseRepo,
+			}
+
+			if tt.setup != nil {
+				tt.setup(mocks, tt.req)
+			}
+
+			resp, err := stockSvc.ReserveStocks(context.Background(), tt.req)
+
+			assert.Error(t, err)
+			assert.Nil(t, resp)
+			assert.Contains(t, err.Error(), tt.err)
+
+			mocks.stockRepo.AssertExpectations(t)
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestRollbackReserves_ShouldSuccess(t *testing.T) {
+	type dependencyMocks struct {
+		db             sqlmock.Sqlmock
+		stockRepo      *stockRepoMock.StockRepository
+		stockAlertRepo *stockRepoMock.StockAlertRepository
+		stockLotRepo   *stockRepoMock.StockLotRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	productID2 := uuid.New()
+	warehouseID := uuid.New()
+
+	tests := []struct {
+		name  string
+		req   payload.RollbackReservesReq
+		setup func(
+			m dependencyMocks,
+		)
+	}{
+		{
+			name: "success - single stock rollback",
+			req: payload.RollbackReservesReq{
+				Stocks: []payload.RollbackReservesData{
+					{
+						ProductID:   productID.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    20,
+					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -20).
+					Return(nil)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - release only the lots reserved by the order",
+			req: payload.RollbackReservesReq{
+				OrderRef: "order-1",
+				Stocks: []payload.RollbackReservesData{
+					{
+						ProductID:   productID.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    20,
+					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				expiresSoon := time.Now().AddDate(0, 0, 10)
+				expiresLater := time.Now().AddDate(0, 1, 0)
+				soonLotID, laterLotID := uuid.New(), uuid.New()
+
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{
+						{ID: soonLotID, WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-1", ExpiryDate: &expiresSoon, Quantity: 10, Reserved: 10},
+						{ID: laterLotID, WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-2", ExpiryDate: &expiresLater, Quantity: 40, Reserved: 15},
+					}, nil)
+				// the sooner lot is reserved by another order
+				m.stockLotRepo.On("GetStockLotReservations", mock.Anything, mock.Anything).
+					Return([]model.StockLotReservation{
+						{StockLotID: laterLotID, OrderRef: "order-1", Quantity: 15},
+					}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -20).
+					Return(nil)
+				m.stockLotRepo.On("AddLotQtyAndReserveQty", mock.Anything, laterLotID.String(), 0, -15).
+					Return(nil)
+				m.stockLotRepo.On("AddLotReservationQty", mock.Anything, laterLotID.String(), "order-1", -15).
+					Return(nil)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - multiple stocks rollback",
+			req: payload.RollbackReservesReq{
+				Stocks: []payload.RollbackReservesData{
+					{
+						ProductID:   productID.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    20,
+					},
+					{
+						ProductID:   productID2.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    15,
+					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -20).
+					Return(nil)
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID2.String(), warehouseID.String(), 0, -15).
+					Return(nil)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:             mockDb.Mock,
+				stockRepo:      stockRepoMock.NewStockRepository(t),
+				stockAlertRepo: stockRepoMock.NewStockAlertRepository(t),
+				stockLotRepo:   stockRepoMock.NewStockLotRepository(t),
+			}
+			logger := pkg.InitLogger(&config.Config{})
+			stockSvc := stockService{
+				logger:         logger,
+				db:             mockDb.Db,
+				backorderRepo:  noBackorders(t),
+				availability:   newAvailabilityBroker(),
+				stockRepo:      mocks.stockRepo,
+				stockAlertRepo: mocks.stockAlertRepo,
+				stockLotRepo:   mocks.stockLotRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := stockSvc.RollbackReserves(context.Background(), tt.req)
+
+			// Then
+			assert.NoError(t, err)
+			mocks.stockRepo.AssertExpectations(t)
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestRollbackReserves_ShouldReturnError(t *testing.T) {
+	type dependencyMocks struct {
+		db             sqlmock.Sqlmock
+		stockRepo      *stockRepoMock.StockRepository
+		stockAlertRepo *stockRepoMock.StockAlertRepository
+		stockLotRepo   *stockRepoMock.StockLotRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	warehouseID := uuid.New()
+
+	tests := []struct {
+		name  string
+		req   payload.RollbackReservesReq
+		setup func(
+			m dependencyMocks,
+		)
+		err string
+	}{
+		{
+			name: "error - failed to add stock quantity and reserve quantity",
+			req: payload.RollbackReservesReq{
+				Stocks: []payload.RollbackReservesData{
+					{
+						ProductID:   productID.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    20,
+					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -20).
+					Return(errors.New("failed to add stock quantity and reserve quantity"))
+
+				m.db.ExpectRollback()
+			},
+			err: "failed to add stock quantity and reserve quantity",
+		},
+		{
+			name: "error - transaction commit failure",
+			req: payload.RollbackReservesReq{
+				Stocks: []payload.RollbackReservesData{
+					{
+						ProductID:   productID.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    20,
+					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -20).
+					Return(nil)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit().WillReturnError(errors.New("failed to commit transaction"))
+			},
+			err: "failed to commit transaction",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+
+			mocks := dependencyMocks{
+				db:             mockDb.Mock,
+				stockRepo:      stockRepoMock.NewStockRepository(t),
+				stockAlertRepo: stockRepoMock.NewStockAlertRepository(t),
+				stockLotRepo:   stockRepoMock.NewStockLotRepository(t),
+			}
+			logger := pkg.InitLogger(&config.Config{})
+			stockSvc := stockService{
+				logger:         logger,
+				db:             mockDb.Db,
+				backorderRepo:  noBackorders(t),
+				availability:   newAvailabilityBroker(),
+				stockRepo:      mocks.stockRepo,
+				stockAlertRepo: mocks.stockAlertRepo,
+				stockLotRepo:   mocks.stockLotRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := stockSvc.RollbackReserves(context.Background(), tt.req)
+
+			// Then
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+			mocks.stockRepo.AssertExpectations(t)
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestCommitReserves_ShouldSuccess(t *testing.T) {
+	type dependencyMocks struct {
+		db              sqlmock.Sqlmock
+		stockRepo       *stockRepoMock.StockRepository
+		stockAlertRepo  *stockRepoMock.StockAlertRepository
+		stockLotRepo    *stockRepoMock.StockLotRepository
+		stockSerialRepo *stockRepoMock.StockSerialRepository
+		stockBinRepo    *stockRepoMock.StockBinRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	productID := uuid.New()
+	productID2 := uuid.New()
+	warehouseID := uuid.New()
+
+	tests := []struct {
+		name  string
+		req   payload.CommitReservesReq
+		setup func(
+			m dependencyMocks,
+		)
+	}{
+		{
+			name: "success - single stock commit",
+			req: payload.CommitReservesReq{
+				Stocks: []payload.CommitReservesData{
+					{
+						ProductID:   productID.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    20,
+					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, mock.Anything).
+					Return([]model.SerializedProduct{}, nil)
+				m.stockBinRepo.On("WithTX", mock.Anything).
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("WithLockForUpdate").
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("GetBinStocks", mock.Anything, mock.Anything).
+					Return([]model.BinStock{}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -20, -20).
+					Return(nil)
+
+				m.stockBinRepo.On("CreatePickList", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - multiple stocks commit",
+			req: payload.CommitReservesReq{
+				Stocks: []payload.CommitReservesData{
+					{
+						ProductID:   productID.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    20,
+					},
+					{
+						ProductID:   productID2.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    15,
+					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, mock.Anything).
+					Return([]model.SerializedProduct{}, nil)
+				m.stockBinRepo.On("WithTX", mock.Anything).
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("WithLockForUpdate").
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("GetBinStocks", mock.Anything, mock.Anything).
+					Return([]model.BinStock{}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -20, -20).
+					Return(nil)
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID2.String(), warehouseID.String(), -15, -15).
+					Return(nil)
+
+				m.stockBinRepo.On("CreatePickList", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - commit the lots reserved by the order",
+			req: payload.CommitReservesReq{
+				OrderRef: "order-1",
+				Stocks: []payload.CommitReservesData{
+					{
+						ProductID:   productID.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    20,
+					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				expiresSoon := time.Now().AddDate(0, 0, 10)
+				expiresLater := time.Now().AddDate(0, 1, 0)
+				soonLotID, laterLotID := uuid.New(), uuid.New()
+
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{
+						{ID: soonLotID, WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-1", ExpiryDate: &expiresSoon, Quantity: 5, Reserved: 5},
+						{ID: laterLotID, WarehouseID: warehouseID, ProductID: productID, LotNumber: "LOT-2", ExpiryDate: &expiresLater, Quantity: 40, Reserved: 25},
+					}, nil)
+				// the order reserved 10 of the later lot, 5 of the sooner one
+				// were reserved before lots were kept per order
+				m.stockLotRepo.On("GetStockLotReservations", mock.Anything, payload.GetStockLotReservationsReq{
+					StockLotIDIN: []string{soonLotID.String(), laterLotID.String()},
+					OrderRefIN:   []string{"order-1", ""},
+				}).Return([]model.StockLotReservation{
+					{StockLotID: laterLotID, OrderRef: "order-1", Quantity: 10},
+					{StockLotID: laterLotID, OrderRef: "order-2", Quantity: 15},
+					{StockLotID: soonLotID, OrderRef: "", Quantity: 5},
+				}, nil)
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, mock.Anything).
+					Return([]model.SerializedProduct{}, nil)
+				m.stockBinRepo.On("WithTX", mock.Anything).
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("WithLockForUpdate").
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("GetBinStocks", mock.Anything, mock.Anything).
+					Return([]model.BinStock{}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
 
-				m.db.ExpectRollback()
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -20, -20).
+					Return(nil)
+				m.stockLotRepo.On("AddLotQtyAndReserveQty", mock.Anything, laterLotID.String(), -10, -10).
+					Return(nil)
+				m.stockLotRepo.On("AddLotReservationQty", mock.Anything, laterLotID.String(), "order-1", -10).
+					Return(nil)
+				m.stockLotRepo.On("AddLotQtyAndReserveQty", mock.Anything, soonLotID.String(), -5, -5).
+					Return(nil)
+				m.stockLotRepo.On("AddLotReservationQty", mock.Anything, soonLotID.String(), "", -5).
+					Return(nil)
+
+				m.stockBinRepo.On("CreatePickList", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - pick committed stock from bins",
+			req: payload.CommitReservesReq{
+				OrderRef: "order-1",
+				Stocks: []payload.CommitReservesData{
+					{
+						ProductID:   productID.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    10,
+					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				firstBinID, secondBinID := uuid.New(), uuid.New()
+
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, mock.Anything).
+					Return([]model.SerializedProduct{}, nil)
+				m.stockBinRepo.On("WithTX", mock.Anything).
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("WithLockForUpdate").
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("GetBinStocks", mock.Anything, mock.Anything).
+					Return([]model.BinStock{
+						{BinID: firstBinID, WarehouseID: warehouseID, ProductID: productID, Quantity: 4, Zone: "A", BinCode: "A-01"},
+						{BinID: secondBinID, WarehouseID: warehouseID, ProductID: productID, Quantity: 3, Zone: "B", BinCode: "B-01"},
+					}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -10, -10).
+					Return(nil)
+				m.stockBinRepo.On("AddBinStockQty", mock.Anything, firstBinID.String(), warehouseID.String(), productID.String(), -4).
+					Return(nil)
+				m.stockBinRepo.On("AddBinStockQty", mock.Anything, secondBinID.String(), warehouseID.String(), productID.String(), -3).
+					Return(nil)
+				m.stockBinRepo.On("CreatePickList", mock.Anything, mock.MatchedBy(func(pickList *model.PickList) bool {
+					return pickList.WarehouseID == warehouseID &&
+						pickList.OrderRef != nil && *pickList.OrderRef == "order-1" &&
+						len(pickList.Items) == 3 &&
+						*pickList.Items[0].BinCode == "A-01" && pickList.Items[0].Quantity == 4 &&
+						*pickList.Items[1].BinCode == "B-01" && pickList.Items[1].Quantity == 3 &&
+						pickList.Items[2].BinID == nil && pickList.Items[2].Quantity == 3
+				})).
+					Return(nil)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - sell oldest serials of serialized product",
+			req: payload.CommitReservesReq{
+				OrderRef: "order-1",
+				Stocks: []payload.CommitReservesData{
+					{
+						ProductID:   productID.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    2,
+					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, []string{productID.String()}).
+					Return([]model.SerializedProduct{{ProductID: productID}}, nil)
+				m.stockBinRepo.On("WithTX", mock.Anything).
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("WithLockForUpdate").
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("GetBinStocks", mock.Anything, mock.Anything).
+					Return([]model.BinStock{}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -2, -2).
+					Return(nil)
+
+				m.stockSerialRepo.On("WithLockForUpdate").
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetStockSerials", mock.Anything, payload.GetStockSerialsReq{
+					WarehouseIDIN: []string{warehouseID.String()},
+					ProductIDIN:   []string{productID.String()},
+					StatusIN:      []string{constant.StockSerialStatusInStock},
+					Limit:         2,
+				}).
+					Return([]model.StockSerial{
+						{ID: uuid.New(), ProductID: productID, SerialNumber: "SN-1", WarehouseID: warehouseID, Status: constant.StockSerialStatusInStock},
+						{ID: uuid.New(), ProductID: productID, SerialNumber: "SN-2", WarehouseID: warehouseID, Status: constant.StockSerialStatusInStock},
+					}, nil)
+				m.stockSerialRepo.On("UpdateStockSerial", mock.Anything, mock.MatchedBy(func(serial *model.StockSerial) bool {
+					return serial.Status == constant.StockSerialStatusSold && serial.OrderRef != nil && *serial.OrderRef == "order-1"
+				})).
+					Return(nil).Times(2)
+				m.stockSerialRepo.On("CreateStockSerialEvents", mock.Anything, mock.MatchedBy(func(events []model.StockSerialEvent) bool {
+					return len(events) == 2 && events[0].Type == constant.StockSerialEventSold
+				})).
+					Return(nil)
+
+				m.stockBinRepo.On("CreatePickList", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
 			},
-			err: "failed to add stock quantity and reserve quantity",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// Given
 			mocks := dependencyMocks{
-				db:        mockDb.Mock,
-				stockRepo: stockRepoMock.NewStockRepository(t),
+				db:              mockDb.Mock,
+				stockRepo:       stockRepoMock.NewStockRepository(t),
+				stockAlertRepo:  stockRepoMock.NewStockAlertRepository(t),
+				stockLotRepo:    stockRepoMock.NewStockLotRepository(t),
+				stockSerialRepo: stockRepoMock.NewStockSerialRepository(t),
+				stockBinRepo:    stockRepoMock.NewStockBinRepository(t),
 			}
 			logger := pkg.InitLogger(&config.Config{})
 			stockSvc := stockService{
-				logger:    logger,
-				db:        mockDb.Db,
-				stockRepo: mocks.stockRepo,
-			}
-
-			if tt.setup != nil {
-				tt.setup(mocks, tt.req)
+				logger:          logger,
+				db:              mockDb.Db,
+				backorderRepo:   noBackorders(t),
+				availability:    newAvailabilityBroker(),
+				stockRepo:       mocks.stockRepo,
+				stockAlertRepo:  mocks.stockAlertRepo,
+				stockLotRepo:    mocks.stockLotRepo,
+				stockSerialRepo: mocks.stockSerialRepo,
+				stockBinRepo:    mocks.stockBinRepo,
 			}
 
-			resp, err := stockSvc.ReserveStocks(context.Background(), tt.req)
+			tt.setup(mocks)
 
-			assert.Error(t, err)
-			assert.Nil(t, resp)
-			assert.Contains(t, err.Error(), tt.err)
+			// When
+			err := stockSvc.CommitReserves(context.Background(), tt.req)
 
+			// Then
+			assert.NoError(t, err)
 			mocks.stockRepo.AssertExpectations(t)
 			mockDb.Mock.ExpectationsWereMet()
 		})
 	}
 }
 
-func TestRollbackReserves_ShouldSuccess(t *testing.T) {
+func TestCommitReserves_ShouldReturnError(t *testing.T) {
 	type dependencyMocks struct {
-		db        sqlmock.Sqlmock
-		stockRepo *stockRepoMock.StockRepository
+		db              sqlmock.Sqlmock
+		stockRepo       *stockRepoMock.StockRepository
+		stockAlertRepo  *stockRepoMock.StockAlertRepository
+		stockLotRepo    *stockRepoMock.StockLotRepository
+		stockSerialRepo *stockRepoMock.StockSerialRepository
+		stockBinRepo    *stockRepoMock.StockBinRepository
 	}
 
 	mockDb, err := pkg.SetupMockDB()
@@ -845,20 +2364,20 @@ func TestRollbackReserves_ShouldSuccess(t *testing.T) {
 	}
 
 	productID := uuid.New()
-	productID2 := uuid.New()
 	warehouseID := uuid.New()
 
 	tests := []struct {
 		name  string
-		req   payload.RollbackReservesReq
+		req   payload.CommitReservesReq
 		setup func(
 			m dependencyMocks,
 		)
+		err string
 	}{
 		{
-			name: "success - single stock rollback",
-			req: payload.RollbackReservesReq{
-				Stocks: []payload.RollbackReservesData{
+			name: "error - failed to add stock quantity and reserve quantity",
+			req: payload.CommitReservesReq{
+				Stocks: []payload.CommitReservesData{
 					{
 						ProductID:   productID.String(),
 						WarehouseID: warehouseID.String(),
@@ -869,44 +2388,218 @@ func TestRollbackReserves_ShouldSuccess(t *testing.T) {
 			setup: func(m dependencyMocks) {
 				m.db.ExpectBegin()
 
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, mock.Anything).
+					Return([]model.SerializedProduct{}, nil)
+				m.stockBinRepo.On("WithTX", mock.Anything).
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("WithLockForUpdate").
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("GetBinStocks", mock.Anything, mock.Anything).
+					Return([]model.BinStock{}, nil)
+
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
 
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -20).
-					Return(nil)
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -20, -20).
+					Return(errors.New("failed to add stock quantity and reserve quantity"))
 
-				m.db.ExpectCommit()
+				m.db.ExpectRollback()
 			},
+			err: "failed to add stock quantity and reserve quantity",
 		},
 		{
-			name: "success - multiple stocks rollback",
-			req: payload.RollbackReservesReq{
-				Stocks: []payload.RollbackReservesData{
+			name: "error - transaction commit failure",
+			req: payload.CommitReservesReq{
+				Stocks: []payload.CommitReservesData{
 					{
 						ProductID:   productID.String(),
 						WarehouseID: warehouseID.String(),
 						Quantity:    20,
 					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, mock.Anything).
+					Return([]model.SerializedProduct{}, nil)
+				m.stockBinRepo.On("WithTX", mock.Anything).
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("WithLockForUpdate").
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("GetBinStocks", mock.Anything, mock.Anything).
+					Return([]model.BinStock{}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -20, -20).
+					Return(nil)
+
+				m.stockBinRepo.On("CreatePickList", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit().WillReturnError(errors.New("failed to commit transaction"))
+			},
+			err: "failed to commit transaction",
+		},
+		{
+			name: "error - serialized product without order reference",
+			req: payload.CommitReservesReq{
+				Stocks: []payload.CommitReservesData{
 					{
-						ProductID:   productID2.String(),
+						ProductID:   productID.String(),
 						WarehouseID: warehouseID.String(),
-						Quantity:    15,
+						Quantity:    1,
 					},
 				},
 			},
 			setup: func(m dependencyMocks) {
 				m.db.ExpectBegin()
 
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, mock.Anything).
+					Return([]model.SerializedProduct{{ProductID: productID}}, nil)
+				m.stockBinRepo.On("WithTX", mock.Anything).
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("WithLockForUpdate").
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("GetBinStocks", mock.Anything, mock.Anything).
+					Return([]model.BinStock{}, nil)
+
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
 
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -20).
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -1, -1).
 					Return(nil)
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID2.String(), warehouseID.String(), 0, -15).
+
+				m.db.ExpectRollback()
+			},
+			err: "order reference is required",
+		},
+		{
+			name: "error - serial numbers for product that is not serialized",
+			req: payload.CommitReservesReq{
+				OrderRef: "order-1",
+				Stocks: []payload.CommitReservesData{
+					{
+						ProductID:     productID.String(),
+						WarehouseID:   warehouseID.String(),
+						Quantity:      1,
+						SerialNumbers: []string{"SN-1"},
+					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, mock.Anything).
+					Return([]model.SerializedProduct{}, nil)
+				m.stockBinRepo.On("WithTX", mock.Anything).
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("WithLockForUpdate").
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("GetBinStocks", mock.Anything, mock.Anything).
+					Return([]model.BinStock{}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -1, -1).
 					Return(nil)
 
-				m.db.ExpectCommit()
+				m.db.ExpectRollback()
+			},
+			err: "is not serialized",
+		},
+		{
+			name: "error - not enough serials in stock",
+			req: payload.CommitReservesReq{
+				OrderRef: "order-1",
+				Stocks: []payload.CommitReservesData{
+					{
+						ProductID:   productID.String(),
+						WarehouseID: warehouseID.String(),
+						Quantity:    2,
+					},
+				},
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("WithLockForUpdate").
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("GetStockLots", mock.Anything, mock.Anything).
+					Return([]model.StockLot{}, nil)
+				m.stockSerialRepo.On("WithTX", mock.Anything).
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, mock.Anything).
+					Return([]model.SerializedProduct{{ProductID: productID}}, nil)
+				m.stockBinRepo.On("WithTX", mock.Anything).
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("WithLockForUpdate").
+					Return(m.stockBinRepo)
+				m.stockBinRepo.On("GetBinStocks", mock.Anything, mock.Anything).
+					Return([]model.BinStock{}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+
+				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -2, -2).
+					Return(nil)
+
+				m.stockSerialRepo.On("WithLockForUpdate").
+					Return(m.stockSerialRepo)
+				m.stockSerialRepo.On("GetStockSerials", mock.Anything, mock.Anything).
+					Return([]model.StockSerial{
+						{ID: uuid.New(), ProductID: productID, SerialNumber: "SN-1", WarehouseID: warehouseID, Status: constant.StockSerialStatusInStock},
+					}, nil)
+
+				m.db.ExpectRollback()
 			},
+			err: "not enough serials in stock",
 		},
 	}
 
@@ -914,33 +2607,46 @@ func TestRollbackReserves_ShouldSuccess(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Given
 			mocks := dependencyMocks{
-				db:        mockDb.Mock,
-				stockRepo: stockRepoMock.NewStockRepository(t),
+				db:              mockDb.Mock,
+				stockRepo:       stockRepoMock.NewStockRepository(t),
+				stockAlertRepo:  stockRepoMock.NewStockAlertRepository(t),
+				stockLotRepo:    stockRepoMock.NewStockLotRepository(t),
+				stockSerialRepo: stockRepoMock.NewStockSerialRepository(t),
+				stockBinRepo:    stockRepoMock.NewStockBinRepository(t),
 			}
 			logger := pkg.InitLogger(&config.Config{})
 			stockSvc := stockService{
-				logger:    logger,
-				db:        mockDb.Db,
-				stockRepo: mocks.stockRepo,
+				logger:          logger,
+				db:              mockDb.Db,
+				backorderRepo:   noBackorders(t),
+				availability:    newAvailabilityBroker(),
+				stockRepo:       mocks.stockRepo,
+				stockAlertRepo:  mocks.stockAlertRepo,
+				stockLotRepo:    mocks.stockLotRepo,
+				stockSerialRepo: mocks.stockSerialRepo,
+				stockBinRepo:    mocks.stockBinRepo,
 			}
 
 			tt.setup(mocks)
 
 			// When
-			err := stockSvc.RollbackReserves(context.Background(), tt.req)
+			err := stockSvc.CommitReserves(context.Background(), tt.req)
 
 			// Then
-			assert.NoError(t, err)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
 			mocks.stockRepo.AssertExpectations(t)
 			mockDb.Mock.ExpectationsWereMet()
 		})
 	}
 }
 
-func TestRollbackReserves_ShouldReturnError(t *testing.T) {
+func TestSetStockThreshold_ShouldSuccess(t *testing.T) {
 	type dependencyMocks struct {
-		db        sqlmock.Sqlmock
-		stockRepo *stockRepoMock.StockRepository
+		db             sqlmock.Sqlmock
+		stockRepo      *stockRepoMock.StockRepository
+		stockAlertRepo *stockRepoMock.StockAlertRepository
+		purchasingSvc  *purchasingSvcMock.IPurchasingSvc
 	}
 
 	mockDb, err := pkg.SetupMockDB()
@@ -950,98 +2656,271 @@ func TestRollbackReserves_ShouldReturnError(t *testing.T) {
 
 	productID := uuid.New()
 	warehouseID := uuid.New()
+	threshold := 10
 
 	tests := []struct {
 		name  string
-		req   payload.RollbackReservesReq
+		req   payload.SetStockThresholdReq
 		setup func(
 			m dependencyMocks,
+			notified chan purchasingservice.NotifyStockAlertsReq,
 		)
-		err string
+		expectedAlert *model.StockAlert
 	}{
 		{
-			name: "error - failed to add stock quantity and reserve quantity",
-			req: payload.RollbackReservesReq{
-				Stocks: []payload.RollbackReservesData{
-					{
-						ProductID:   productID.String(),
-						WarehouseID: warehouseID.String(),
-						Quantity:    20,
-					},
-				},
+			name: "success - set warehouse threshold raises low alert",
+			req: payload.SetStockThresholdReq{
+				ProductID:   productID,
+				WarehouseID: &warehouseID,
+				Threshold:   &threshold,
 			},
-			setup: func(m dependencyMocks) {
+			setup: func(m dependencyMocks, notified chan purchasingservice.NotifyStockAlertsReq) {
 				m.db.ExpectBegin()
 
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
+				m.stockRepo.On("UpdateStockThreshold", mock.Anything, productID.String(), warehouseID.String(), &threshold).
+					Return(nil)
+				m.stockRepo.On("GetStocks", mock.Anything, payload.GetStocksReq{ProductIDIN: []string{productID.String()}}).
+					Return([]model.WarehouseStock{
+						{
+							WarehouseID:      warehouseID,
+							ProductID:        productID,
+							Quantity:         10,
+							Reserved:         2,
+							ReorderThreshold: &threshold,
+						},
+					}, nil)
 
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -20).
-					Return(errors.New("failed to add stock quantity and reserve quantity"))
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, []string{productID.String()}).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, []string{productID.String()}).
+					Return([]model.StockAlert{}, nil)
+				m.stockAlertRepo.On("CreateStockAlerts", mock.Anything, mock.Anything).
+					Return(nil)
 
-				m.db.ExpectRollback()
+				m.db.ExpectCommit()
+
+				m.purchasingSvc.On("NotifyStockAlerts", mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						notified <- args.Get(1).(purchasingservice.NotifyStockAlertsReq)
+					}).
+					Return(nil)
+			},
+			expectedAlert: &model.StockAlert{
+				Scope:       constant.StockAlertScopeWarehouse,
+				WarehouseID: &warehouseID,
+				ProductID:   productID,
+				Status:      constant.StockAlertStatusLow,
+				Available:   8,
+				Threshold:   threshold,
 			},
-			err: "failed to add stock quantity and reserve quantity",
 		},
 		{
-			name: "error - transaction commit failure",
-			req: payload.RollbackReservesReq{
-				Stocks: []payload.RollbackReservesData{
-					{
-						ProductID:   productID.String(),
-						WarehouseID: warehouseID.String(),
-						Quantity:    20,
-					},
-				},
+			name: "success - set product threshold raises out alert",
+			req: payload.SetStockThresholdReq{
+				ProductID: productID,
+				Threshold: &threshold,
 			},
-			setup: func(m dependencyMocks) {
+			setup: func(m dependencyMocks, notified chan purchasingservice.NotifyStockAlertsReq) {
 				m.db.ExpectBegin()
 
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("UpsertProductThreshold", mock.Anything, &model.ProductStockThreshold{
+					ProductID: productID,
+					Threshold: threshold,
+				}).
+					Return(nil)
+
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							WarehouseID: warehouseID,
+							ProductID:   productID,
+							Quantity:    5,
+							Reserved:    5,
+						},
+					}, nil)
 
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), 0, -20).
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{
+						{
+							ProductID: productID,
+							Threshold: threshold,
+						},
+					}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{
+						{
+							Scope:     constant.StockAlertScopeProduct,
+							ProductID: productID,
+							Status:    constant.StockAlertStatusLow,
+						},
+					}, nil)
+				m.stockAlertRepo.On("CreateStockAlerts", mock.Anything, mock.Anything).
 					Return(nil)
 
-				m.db.ExpectCommit().WillReturnError(errors.New("failed to commit transaction"))
+				m.db.ExpectCommit()
+
+				m.purchasingSvc.On("NotifyStockAlerts", mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						notified <- args.Get(1).(purchasingservice.NotifyStockAlertsReq)
+					}).
+					Return(nil)
+			},
+			expectedAlert: &model.StockAlert{
+				Scope:     constant.StockAlertScopeProduct,
+				ProductID: productID,
+				Status:    constant.StockAlertStatusOut,
+				Available: 0,
+				Threshold: threshold,
+			},
+		},
+		{
+			name: "success - remove product threshold recovers active alert",
+			req: payload.SetStockThresholdReq{
+				ProductID: productID,
+			},
+			setup: func(m dependencyMocks, notified chan purchasingservice.NotifyStockAlertsReq) {
+				m.db.ExpectBegin()
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("DeleteProductThreshold", mock.Anything, productID.String()).
+					Return(nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							WarehouseID: warehouseID,
+							ProductID:   productID,
+							Quantity:    3,
+						},
+					}, nil)
+
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{
+						{
+							Scope:     constant.StockAlertScopeProduct,
+							ProductID: productID,
+							Status:    constant.StockAlertStatusLow,
+							Threshold: threshold,
+						},
+					}, nil)
+				m.stockAlertRepo.On("CreateStockAlerts", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.db.ExpectCommit()
+
+				m.purchasingSvc.On("NotifyStockAlerts", mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						notified <- args.Get(1).(purchasingservice.NotifyStockAlertsReq)
+					}).
+					Return(nil)
+			},
+			expectedAlert: &model.StockAlert{
+				Scope:     constant.StockAlertScopeProduct,
+				ProductID: productID,
+				Status:    constant.StockAlertStatusRecovered,
+				Available: 3,
+				Threshold: threshold,
+			},
+		},
+		{
+			name: "success - unchanged status raises no alert",
+			req: payload.SetStockThresholdReq{
+				ProductID:   productID,
+				WarehouseID: &warehouseID,
+				Threshold:   &threshold,
+			},
+			setup: func(m dependencyMocks, notified chan purchasingservice.NotifyStockAlertsReq) {
+				m.db.ExpectBegin()
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("UpdateStockThreshold", mock.Anything, productID.String(), warehouseID.String(), &threshold).
+					Return(nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{
+							WarehouseID:      warehouseID,
+							ProductID:        productID,
+							Quantity:         100,
+							ReorderThreshold: &threshold,
+						},
+					}, nil)
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
 			},
-			err: "failed to commit transaction",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Given
-
 			mocks := dependencyMocks{
-				db:        mockDb.Mock,
-				stockRepo: stockRepoMock.NewStockRepository(t),
+				db:             mockDb.Mock,
+				stockRepo:      stockRepoMock.NewStockRepository(t),
+				stockAlertRepo: stockRepoMock.NewStockAlertRepository(t),
+				purchasingSvc:  purchasingSvcMock.NewIPurchasingSvc(t),
 			}
 			logger := pkg.InitLogger(&config.Config{})
 			stockSvc := stockService{
-				logger:    logger,
-				db:        mockDb.Db,
-				stockRepo: mocks.stockRepo,
+				logger:         logger,
+				db:             mockDb.Db,
+				backorderRepo:  noBackorders(t),
+				availability:   newAvailabilityBroker(),
+				stockRepo:      mocks.stockRepo,
+				stockAlertRepo: mocks.stockAlertRepo,
+				purchasingSvc:  mocks.purchasingSvc,
 			}
 
-			tt.setup(mocks)
+			notified := make(chan purchasingservice.NotifyStockAlertsReq, 1)
+			tt.setup(mocks, notified)
 
 			// When
-			err := stockSvc.RollbackReserves(context.Background(), tt.req)
+			err := stockSvc.SetStockThreshold(context.Background(), tt.req)
 
 			// Then
-			assert.Error(t, err)
-			assert.Contains(t, err.Error(), tt.err)
+			assert.NoError(t, err)
+			if tt.expectedAlert != nil {
+				req := <-notified
+				assert.Len(t, req.Alerts, 1)
+				assert.Equal(t, tt.expectedAlert.Scope, req.Alerts[0].Scope)
+				assert.Equal(t, tt.expectedAlert.WarehouseID, req.Alerts[0].WarehouseID)
+				assert.Equal(t, tt.expectedAlert.ProductID, req.Alerts[0].ProductID)
+				assert.Equal(t, tt.expectedAlert.Status, req.Alerts[0].Status)
+				assert.Equal(t, tt.expectedAlert.Available, req.Alerts[0].Available)
+				assert.Equal(t, tt.expectedAlert.Threshold, req.Alerts[0].Threshold)
+			}
 			mocks.stockRepo.AssertExpectations(t)
+			mocks.stockAlertRepo.AssertExpectations(t)
 			mockDb.Mock.ExpectationsWereMet()
 		})
 	}
 }
 
-func TestCommitReserves_ShouldSuccess(t *testing.T) {
+func TestSetStockThreshold_ShouldReturnError(t *testing.T) {
 	type dependencyMocks struct {
-		db        sqlmock.Sqlmock
-		stockRepo *stockRepoMock.StockRepository
+		db             sqlmock.Sqlmock
+		stockRepo      *stockRepoMock.StockRepository
+		stockAlertRepo *stockRepoMock.StockAlertRepository
 	}
 
 	mockDb, err := pkg.SetupMockDB()
@@ -1050,67 +2929,85 @@ func TestCommitReserves_ShouldSuccess(t *testing.T) {
 	}
 
 	productID := uuid.New()
-	productID2 := uuid.New()
 	warehouseID := uuid.New()
+	threshold := 10
 
 	tests := []struct {
 		name  string
-		req   payload.CommitReservesReq
+		req   payload.SetStockThresholdReq
 		setup func(
 			m dependencyMocks,
 		)
 	}{
 		{
-			name: "success - single stock commit",
-			req: payload.CommitReservesReq{
-				Stocks: []payload.CommitReservesData{
-					{
-						ProductID:   productID.String(),
-						WarehouseID: warehouseID.String(),
-						Quantity:    20,
-					},
-				},
+			name: "error - update stock threshold failure",
+			req: payload.SetStockThresholdReq{
+				ProductID:   productID,
+				WarehouseID: &warehouseID,
+				Threshold:   &threshold,
 			},
 			setup: func(m dependencyMocks) {
 				m.db.ExpectBegin()
 
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
+				m.stockRepo.On("UpdateStockThreshold", mock.Anything, productID.String(), warehouseID.String(), &threshold).
+					Return(errors.New("stock not found"))
 
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -20, -20).
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - get latest stock alerts failure",
+			req: payload.SetStockThresholdReq{
+				ProductID: productID,
+				Threshold: &threshold,
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("UpsertProductThreshold", mock.Anything, mock.Anything).
 					Return(nil)
 
-				m.db.ExpectCommit()
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return(nil, errors.New("database error"))
+
+				m.db.ExpectRollback()
 			},
 		},
 		{
-			name: "success - multiple stocks commit",
-			req: payload.CommitReservesReq{
-				Stocks: []payload.CommitReservesData{
-					{
-						ProductID:   productID.String(),
-						WarehouseID: warehouseID.String(),
-						Quantity:    20,
-					},
-					{
-						ProductID:   productID2.String(),
-						WarehouseID: warehouseID.String(),
-						Quantity:    15,
-					},
-				},
+			name: "error - transaction commit failure",
+			req: payload.SetStockThresholdReq{
+				ProductID: productID,
 			},
 			setup: func(m dependencyMocks) {
 				m.db.ExpectBegin()
 
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("DeleteProductThreshold", mock.Anything, productID.String()).
+					Return(nil)
+
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
 
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -20, -20).
-					Return(nil)
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID2.String(), warehouseID.String(), -15, -15).
-					Return(nil)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{}, nil)
 
-				m.db.ExpectCommit()
+				m.db.ExpectCommit().WillReturnError(errors.New("commit failed"))
 			},
 		},
 	}
@@ -1119,33 +3016,86 @@ func TestCommitReserves_ShouldSuccess(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Given
 			mocks := dependencyMocks{
-				db:        mockDb.Mock,
-				stockRepo: stockRepoMock.NewStockRepository(t),
+				db:             mockDb.Mock,
+				stockRepo:      stockRepoMock.NewStockRepository(t),
+				stockAlertRepo: stockRepoMock.NewStockAlertRepository(t),
 			}
 			logger := pkg.InitLogger(&config.Config{})
 			stockSvc := stockService{
-				logger:    logger,
-				db:        mockDb.Db,
-				stockRepo: mocks.stockRepo,
+				logger:         logger,
+				db:             mockDb.Db,
+				backorderRepo:  noBackorders(t),
+				availability:   newAvailabilityBroker(),
+				stockRepo:      mocks.stockRepo,
+				stockAlertRepo: mocks.stockAlertRepo,
 			}
 
 			tt.setup(mocks)
 
 			// When
-			err := stockSvc.CommitReserves(context.Background(), tt.req)
+			err := stockSvc.SetStockThreshold(context.Background(), tt.req)
 
 			// Then
-			assert.NoError(t, err)
-			mocks.stockRepo.AssertExpectations(t)
+			assert.Error(t, err)
 			mockDb.Mock.ExpectationsWereMet()
 		})
 	}
 }
 
-func TestCommitReserves_ShouldReturnError(t *testing.T) {
+func TestGetStockAlerts_ShouldSuccess(t *testing.T) {
+	productID := uuid.New()
+	expected := []model.StockAlert{
+		{
+			ID:        uuid.New(),
+			Scope:     constant.StockAlertScopeProduct,
+			ProductID: productID,
+			Status:    constant.StockAlertStatusLow,
+			Available: 3,
+			Threshold: 5,
+		},
+	}
+	req := payload.GetStockAlertsReq{
+		ProductIDIN: []string{productID.String()},
+		ActiveOnly:  true,
+	}
+
+	stockAlertRepo := stockRepoMock.NewStockAlertRepository(t)
+	stockAlertRepo.On("GetStockAlerts", mock.Anything, req).
+		Return(expected, nil)
+
+	stockSvc := stockService{
+		logger:         pkg.InitLogger(&config.Config{}),
+		stockAlertRepo: stockAlertRepo,
+	}
+
+	result, err := stockSvc.GetStockAlerts(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestGetStockAlerts_ShouldReturnError(t *testing.T) {
+	stockAlertRepo := stockRepoMock.NewStockAlertRepository(t)
+	stockAlertRepo.On("GetStockAlerts", mock.Anything, mock.Anything).
+		Return(nil, errors.New("database error"))
+
+	stockSvc := stockService{
+		logger:         pkg.InitLogger(&config.Config{}),
+		stockAlertRepo: stockAlertRepo,
+	}
+
+	result, err := stockSvc.GetStockAlerts(context.Background(), payload.GetStockAlertsReq{})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestSettleStockChanges(t *testing.T) {
 	type dependencyMocks struct {
-		db        sqlmock.Sqlmock
-		stockRepo *stockRepoMock.StockRepository
+		db             sqlmock.Sqlmock
+		stockRepo      *stockRepoMock.StockRepository
+		stockAlertRepo *stockRepoMock.StockAlertRepository
+		purchasingSvc  *purchasingSvcMock.IPurchasingSvc
 	}
 
 	mockDb, err := pkg.SetupMockDB()
@@ -1158,59 +3108,74 @@ func TestCommitReserves_ShouldReturnError(t *testing.T) {
 
 	tests := []struct {
 		name  string
-		req   payload.CommitReservesReq
 		setup func(
 			m dependencyMocks,
+			notified chan purchasingservice.NotifyStockAlertsReq,
 		)
-		err string
+		wantErr  bool
+		wantSent bool
 	}{
 		{
-			name: "error - failed to add stock quantity and reserve quantity",
-			req: payload.CommitReservesReq{
-				Stocks: []payload.CommitReservesData{
-					{
-						ProductID:   productID.String(),
-						WarehouseID: warehouseID.String(),
-						Quantity:    20,
-					},
-				},
-			},
-			setup: func(m dependencyMocks) {
+			name: "success - replenished stock recovers product alert",
+			setup: func(m dependencyMocks, notified chan purchasingservice.NotifyStockAlertsReq) {
 				m.db.ExpectBegin()
 
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, payload.GetStocksReq{ProductIDIN: []string{productID.String()}}).
+					Return([]model.WarehouseStock{
+						{
+							WarehouseID: warehouseID,
+							ProductID:   productID,
+							Quantity:    50,
+						},
+					}, nil)
 
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -20, -20).
-					Return(errors.New("failed to add stock quantity and reserve quantity"))
+				m.stockAlertRepo.On("WithTX", mock.Anything).
+					Return(m.stockAlertRepo)
+				m.stockAlertRepo.On("GetProductThresholds", mock.Anything, mock.Anything).
+					Return([]model.ProductStockThreshold{
+						{
+							ProductID: productID,
+							Threshold: 10,
+						},
+					}, nil)
+				m.stockAlertRepo.On("GetLatestStockAlerts", mock.Anything, mock.Anything).
+					Return([]model.StockAlert{
+						{
+							Scope:     constant.StockAlertScopeProduct,
+							ProductID: productID,
+							Status:    constant.StockAlertStatusOut,
+						},
+					}, nil)
+				m.stockAlertRepo.On("CreateStockAlerts", mock.Anything, mock.MatchedBy(func(alerts []model.StockAlert) bool {
+					return len(alerts) == 1 && alerts[0].Status == constant.StockAlertStatusRecovered && alerts[0].Available == 50
+				})).
+					Return(nil)
 
-				m.db.ExpectRollback()
+				m.db.ExpectCommit()
+
+				m.purchasingSvc.On("NotifyStockAlerts", mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						notified <- args.Get(1).(purchasingservice.NotifyStockAlertsReq)
+					}).
+					Return(nil)
 			},
-			err: "failed to add stock quantity and reserve quantity",
+			wantSent: true,
 		},
 		{
-			name: "error - transaction commit failure",
-			req: payload.CommitReservesReq{
-				Stocks: []payload.CommitReservesData{
-					{
-						ProductID:   productID.String(),
-						WarehouseID: warehouseID.String(),
-						Quantity:    20,
-					},
-				},
-			},
-			setup: func(m dependencyMocks) {
+			name: "error - get stocks failure",
+			setup: func(m dependencyMocks, notified chan purchasingservice.NotifyStockAlertsReq) {
 				m.db.ExpectBegin()
 
 				m.stockRepo.On("WithTX", mock.Anything).
 					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return(nil, errors.New("database error"))
 
-				m.stockRepo.On("AddStockQtyAndReserveQty", mock.Anything, productID.String(), warehouseID.String(), -20, -20).
-					Return(nil)
-
-				m.db.ExpectCommit().WillReturnError(errors.New("failed to commit transaction"))
+				m.db.ExpectRollback()
 			},
-			err: "failed to commit transaction",
+			wantErr: true,
 		},
 	}
 
@@ -1218,25 +3183,46 @@ func TestCommitReserves_ShouldReturnError(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Given
 			mocks := dependencyMocks{
-				db:        mockDb.Mock,
-				stockRepo: stockRepoMock.NewStockRepository(t),
+				db:             mockDb.Mock,
+				stockRepo:      stockRepoMock.NewStockRepository(t),
+				stockAlertRepo: stockRepoMock.NewStockAlertRepository(t),
+				purchasingSvc:  purchasingSvcMock.NewIPurchasingSvc(t),
 			}
 			logger := pkg.InitLogger(&config.Config{})
 			stockSvc := stockService{
-				logger:    logger,
-				db:        mockDb.Db,
-				stockRepo: mocks.stockRepo,
+				logger:         logger,
+				db:             mockDb.Db,
+				backorderRepo:  noBackorders(t),
+				availability:   newAvailabilityBroker(),
+				stockRepo:      mocks.stockRepo,
+				stockAlertRepo: mocks.stockAlertRepo,
+				purchasingSvc:  mocks.purchasingSvc,
 			}
 
-			tt.setup(mocks)
+			notified := make(chan purchasingservice.NotifyStockAlertsReq, 1)
+			tt.setup(mocks, notified)
 
 			// When
-			err := stockSvc.CommitReserves(context.Background(), tt.req)
+			productIDs := []string{productID.String()}
+			tx := mockDb.Db.Begin()
+			alerts, err := stockSvc.SettleStockChanges(context.Background(), tx, productIDs)
+			if err != nil {
+				tx.Rollback()
+			} else {
+				tx.Commit()
+				stockSvc.PublishStockChanges(context.Background(), productIDs, alerts)
+			}
 
 			// Then
-			assert.Error(t, err)
-			assert.Contains(t, err.Error(), tt.err)
-			mocks.stockRepo.AssertExpectations(t)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantSent {
+				req := <-notified
+				assert.Len(t, req.Alerts, 1)
+			}
 			mockDb.Mock.ExpectationsWereMet()
 		})
 	}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// TakeStockSnapshot records the current stock of every product in every
+// warehouse.
+func (s *stockService) TakeStockSnapshot(ctx context.Context) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.TakeStockSnapshot")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	if err := s.stockSnapshotRepo.WithTX(tx).CreateStockSnapshot(ctx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// GetStockSnapshot answers what the stocks were at the requested time by
+// replaying the movements recorded since the latest snapshot before it.
+func (s *stockService) GetStockSnapshot(ctx context.Context, req payload.GetStockSnapshotReq) (result payload.StockSnapshotResp, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockService.GetStockSnapshot")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if req.At.After(time.Now()) {
+		return payload.StockSnapshotResp{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "at must not be in the future")
+	}
+
+	takenAt, err := s.stockSnapshotRepo.GetLatestSnapshotTime(ctx, req.At)
+	if err != nil {
+		return payload.StockSnapshotResp{}, err
+	}
+	if takenAt == nil {
+		return payload.StockSnapshotResp{}, apperr.NewWithCode(apperr.CodeHTTPNotFound, "no stock snapshot on or before "+req.At.Format(time.RFC3339))
+	}
+
+	filter := payload.GetStocksReq{
+		WarehouseIDIN: req.WarehouseIDIN,
+		ProductIDIN:   req.ProductIDIN,
+	}
+	snapshots, err := s.stockSnapshotRepo.GetStockSnapshots(ctx, *takenAt, filter)
+	if err != nil {
+		return payload.StockSnapshotResp{}, err
+	}
+
+	movements, err := s.stockSnapshotRepo.GetStockMovements(ctx, *takenAt, req.At, filter)
+	if err != nil {
+		return payload.StockSnapshotResp{}, err
+	}
+
+	stocks := make([]payload.StockSnapshotData, 0, len(snapshots))
+	index := make(map[stockKey]int, len(snapshots))
+	for _, snapshot := range snapshots {
+		index[stockKey{warehouseID: snapshot.WarehouseID.String(), productID: snapshot.ProductID.String()}] = len(stocks)
+		stocks = append(stocks, payload.StockSnapshotData{
+			WarehouseID: snapshot.WarehouseID,
+			ProductID:   snapshot.ProductID,
+			Quantity:    snapshot.Quantity,
+			Reserved:    snapshot.Reserved,
+		})
+	}
+
+	for _, movement := range movements {
+		key := stockKey{warehouseID: movement.WarehouseID.String(), productID: movement.ProductID.String()}
+		i, ok := index[key]
+		if !ok {
+			i = len(stocks)
+			index[key] = i
+			stocks = append(stocks, payload.StockSnapshotData{
+				WarehouseID: movement.WarehouseID,
+				ProductID:   movement.ProductID,
+			})
+		}
+		stocks[i].Quantity += movement.QuantityDelta
+		stocks[i].Reserved += movement.ReservedDelta
+	}
+
+	return payload.StockSnapshotResp{
+		At:              req.At,
+		SnapshotTakenAt: *takenAt,
+		Stocks:          stocks,
+	}, nil
+}
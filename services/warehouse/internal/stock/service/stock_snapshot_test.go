@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	stockRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository/mocks"
+)
+
+func TestTakeStockSnapshot(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(db sqlmock.Sqlmock, snapshotRepo *stockRepoMock.StockSnapshotRepository)
+		wantErr bool
+	}{
+		{
+			name: "success - snapshot taken in its own transaction",
+			setup: func(db sqlmock.Sqlmock, snapshotRepo *stockRepoMock.StockSnapshotRepository) {
+				db.ExpectBegin()
+				snapshotRepo.On("WithTX", mock.Anything).
+					Return(snapshotRepo)
+				snapshotRepo.On("CreateStockSnapshot", mock.Anything).
+					Return(nil)
+				db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - failed to create snapshot",
+			setup: func(db sqlmock.Sqlmock, snapshotRepo *stockRepoMock.StockSnapshotRepository) {
+				db.ExpectBegin()
+				snapshotRepo.On("WithTX", mock.Anything).
+					Return(snapshotRepo)
+				snapshotRepo.On("CreateStockSnapshot", mock.Anything).
+					Return(errors.New("failed to create stock snapshot"))
+				db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			snapshotRepo := stockRepoMock.NewStockSnapshotRepository(t)
+			stockSvc := stockService{
+				logger:            pkg.InitLogger(&config.Config{}),
+				db:                mockDb.Db,
+				stockSnapshotRepo: snapshotRepo,
+			}
+
+			tt.setup(mockDb.Mock, snapshotRepo)
+
+			// When
+			err := stockSvc.TakeStockSnapshot(context.Background())
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetStockSnapshot(t *testing.T) {
+	warehouseID := uuid.New()
+	productA := uuid.New()
+	productB := uuid.New()
+	at := time.Now().Add(-time.Hour)
+	takenAt := at.Add(-12 * time.Hour)
+
+	tests := []struct {
+		name    string
+		req     payload.GetStockSnapshotReq
+		setup   func(snapshotRepo *stockRepoMock.StockSnapshotRepository)
+		want    []payload.StockSnapshotData
+		wantErr bool
+	}{
+		{
+			name: "success - replay movements on snapshot",
+			req:  payload.GetStockSnapshotReq{At: at},
+			setup: func(snapshotRepo *stockRepoMock.StockSnapshotRepository) {
+				snapshotRepo.On("GetLatestSnapshotTime", mock.Anything, at).
+					Return(&takenAt, nil)
+				snapshotRepo.On("GetStockSnapshots", mock.Anything, takenAt, payload.GetStocksReq{}).
+					Return([]model.StockSnapshot{
+						{TakenAt: takenAt, WarehouseID: warehouseID, ProductID: productA, Quantity: 10, Reserved: 2},
+					}, nil)
+				snapshotRepo.On("GetStockMovements", mock.Anything, takenAt, at, payload.GetStocksReq{}).
+					Return([]model.StockMovement{
+						{WarehouseID: warehouseID, ProductID: productA, QuantityDelta: 0, ReservedDelta: 3},
+						{WarehouseID: warehouseID, ProductID: productA, QuantityDelta: -3, ReservedDelta: -3},
+						{WarehouseID: warehouseID, ProductID: productB, QuantityDelta: 5},
+					}, nil)
+			},
+			want: []payload.StockSnapshotData{
+				{WarehouseID: warehouseID, ProductID: productA, Quantity: 7, Reserved: 2},
+				{WarehouseID: warehouseID, ProductID: productB, Quantity: 5},
+			},
+		},
+		{
+			name:    "error - at is in the future",
+			req:     payload.GetStockSnapshotReq{At: time.Now().Add(time.Hour)},
+			setup:   func(snapshotRepo *stockRepoMock.StockSnapshotRepository) {},
+			wantErr: true,
+		},
+		{
+			name: "error - no snapshot before at",
+			req:  payload.GetStockSnapshotReq{At: at},
+			setup: func(snapshotRepo *stockRepoMock.StockSnapshotRepository) {
+				snapshotRepo.On("GetLatestSnapshotTime", mock.Anything, at).
+					Return(nil, nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			stockSnapshotRepo := stockRepoMock.NewStockSnapshotRepository(t)
+			stockSvc := stockService{
+				logger:            pkg.InitLogger(&config.Config{}),
+				stockSnapshotRepo: stockSnapshotRepo,
+			}
+
+			tt.setup(stockSnapshotRepo)
+
+			// When
+			snapshot, err := stockSvc.GetStockSnapshot(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, takenAt, snapshot.SnapshotTakenAt)
+			assert.Equal(t, tt.want, snapshot.Stocks)
+		})
+	}
+}
@@ -1,30 +1,48 @@
 package stock
 
 import (
+	purchasingservice "github.com/alifmufthi91/ecommerce-system/services/warehouse/external/purchasing_service"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/_options"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/registry"
+	shopwarehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/repository"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/allocation"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/handler"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service"
+	warehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
 )
 
 type StockModule struct {
-	stockService service.StockService
+	StockService service.StockService
 }
 
 type Options struct {
 	_options.DefaultOptions
+	PurchasingService purchasingservice.IPurchasingSvc
 }
 
 func NewStockModule(opts Options) *StockModule {
 
+	// a misconfigured default strategy would otherwise fail every reservation
+	if _, err := allocation.New(opts.Config.Reservation.AllocationStrategy); err != nil {
+		opts.Logger.Fatal("Invalid reservation allocation strategy:", err)
+	}
+
 	stockRepo := repository.NewStockRepository(opts.Db)
+	stockAlertRepo := repository.NewStockAlertRepository(opts.Db)
+	stockLotRepo := repository.NewStockLotRepository(opts.Db)
+	stockSerialRepo := repository.NewStockSerialRepository(opts.Db)
+	stockBinRepo := repository.NewStockBinRepository(opts.Db)
+	stockSnapshotRepo := repository.NewStockSnapshotRepository(opts.Db)
+	backorderRepo := repository.NewBackorderRepository(opts.Db)
+	shopWarehouseRepo := shopwarehouserepository.NewShopWarehouseRepository(opts.Db)
+	warehouseRepo := warehouserepository.NewWarehouseRepository(opts.Db)
 
-	stockService := service.NewStockService(opts.Config, opts.Logger, opts.Db, stockRepo)
+	stockService := service.NewStockService(opts.Config, opts.Logger, opts.Db, stockRepo, stockAlertRepo, stockLotRepo, stockSerialRepo, stockBinRepo, stockSnapshotRepo, backorderRepo, shopWarehouseRepo, warehouseRepo, opts.PurchasingService)
 
 	registry.RegisterRouter(handler.NewHandler(opts.Router, opts.Config, opts.Logger, stockService))
 
 	return &StockModule{
-		stockService: stockService,
+		StockService: stockService,
 	}
 }
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/middleware"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/registry"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer/service"
+	"github.com/gin-gonic/gin"
+)
+
+type transferHandler struct {
+	router          *gin.Engine
+	config          *config.Config
+	logger          *pkg.Logger
+	transferService service.TransferService
+}
+
+func NewHandler(rt *gin.Engine, cfg *config.Config, logger *pkg.Logger, transferSvc service.TransferService) registry.Router {
+	return &transferHandler{
+		transferService: transferSvc,
+		router:          rt,
+		config:          cfg,
+		logger:          logger,
+	}
+}
+
+func (h transferHandler) RegisterRoutes(base *gin.RouterGroup) {
+	g := base.Group("/transfers")
+
+	g.Use(middleware.JwtMiddleware(h.config))
+
+	g.GET("", h.GetTransfers)
+	g.POST("", h.DispatchTransfer)
+	g.GET("/in-transit", h.GetInTransitStocks)
+	g.GET("/:id", h.GetTransferByID)
+	g.PATCH("/:id/in-transit", h.MarkInTransit)
+	g.POST("/:id/receipts", h.ReceiveTransfer)
+}
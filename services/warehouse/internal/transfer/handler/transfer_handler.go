@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/httpresp"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/utils"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer/payload"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// @Summary		Transfer - Get Transfers
+// @Description	get stock transfers between warehouses
+// @Tags		Transfer
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetTransfersReq	false	"get transfers request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]model.StockTransfer}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/transfers [get]
+func (h *transferHandler) GetTransfers(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "transferHandler.GetTransfers")
+	defer span.End()
+
+	var req payload.GetTransfersReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	transfers, err := h.transferService.GetTransfers(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, transfers, nil)
+}
+
+// @Summary		Transfer - Dispatch Transfer
+// @Description	dispatch stock from one warehouse to another, taking it out of the source warehouse
+// @Tags		Transfer
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.DispatchTransferReq	true	"dispatch transfer request body"
+// @Success		200	{object}	httpresp.Response{data=model.StockTransfer}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/transfers [post]
+func (h *transferHandler) DispatchTransfer(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "transferHandler.DispatchTransfer")
+	defer span.End()
+
+	var req payload.DispatchTransferReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	transfer, err := h.transferService.DispatchTransfer(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, transfer, nil)
+}
+
+// @Summary		Transfer - Get In-Transit Stocks
+// @Description	get the quantity still in transit per product and destination warehouse
+// @Tags		Transfer
+// @Accept		json
+// @Produce		json
+// @Param		request	query	payload.GetInTransitStocksReq	false	"get in-transit stocks request query parameters"
+// @Success		200	{object}	httpresp.Response{data=[]model.InTransitStock}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/transfers/in-transit [get]
+func (h *transferHandler) GetInTransitStocks(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "transferHandler.GetInTransitStocks")
+	defer span.End()
+
+	var req payload.GetInTransitStocksReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	stocks, err := h.transferService.GetInTransitStocks(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, stocks, nil)
+}
+
+// @Summary		Transfer - Get Transfer
+// @Description	get a stock transfer with its receipts
+// @Tags		Transfer
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"stock transfer ID"
+// @Success		200	{object}	httpresp.Response{data=model.StockTransfer}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/transfers/{id} [get]
+func (h *transferHandler) GetTransferByID(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "transferHandler.GetTransferByID")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid stock transfer ID"))
+		return
+	}
+
+	transfer, err := h.transferService.GetTransferByID(ctx, id.String())
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, transfer, nil)
+}
+
+// @Summary		Transfer - Mark In Transit
+// @Description	mark a dispatched stock transfer as on its way to the destination warehouse
+// @Tags		Transfer
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"stock transfer ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/transfers/{id}/in-transit [patch]
+func (h *transferHandler) MarkInTransit(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "transferHandler.MarkInTransit")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid stock transfer ID"))
+		return
+	}
+
+	if err := h.transferService.MarkInTransit(ctx, id.String()); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Transfer - Receive Transfer
+// @Description	record a full or partial receipt of a stock transfer, including lost quantities
+// @Tags		Transfer
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"stock transfer ID"
+// @Param		request	body	payload.ReceiveTransferReq	true	"receive transfer request body"
+// @Success		200	{object}	httpresp.Response{data=model.StockTransferReceipt}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/transfers/{id}/receipts [post]
+func (h *transferHandler) ReceiveTransfer(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "transferHandler.ReceiveTransfer")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid stock transfer ID"))
+		return
+	}
+
+	var req payload.ReceiveTransferReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ID = id
+	receipt, err := h.transferService.ReceiveTransfer(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, receipt, nil)
+}
@@ -0,0 +1,400 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetTransfers_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		queries            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			queries:            "?status_in=dispatched&status_in=in_transit",
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid status",
+			queries:            "?status_in=unknown",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get transfers",
+			queries:            "",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockTransferSvc := &mocks.TransferService{}
+			mockTransferSvc.
+				On("GetTransfers", mock.Anything, mock.Anything).
+				Return([]model.StockTransfer{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/transfers"+scenario.queries, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &transferHandler{
+				router:          r,
+				config:          mockConfig,
+				transferService: mockTransferSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestDispatchTransfer_ShouldReturnExpectedStatusCode(t *testing.T) {
+	payload := `{
+		"from_warehouse_id": "0f8fad5b-d9cb-469f-a165-70867728950e",
+		"to_warehouse_id": "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0",
+		"quantity": 10
+	}`
+	testScenarios := []struct {
+		testName           string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - error handle dispatch transfer",
+			mockReq:            payload,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+		{
+			testName:           "failed - invalid request body",
+			mockReq:            `{"product_id": "9a2b7c93-7c27-4e20-842f-24bf4df95bf0", "quantity": 0}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockTransferSvc := &mocks.TransferService{}
+			mockTransferSvc.
+				On("DispatchTransfer", mock.Anything, mock.Anything).
+				Return(model.StockTransfer{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/transfers", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &transferHandler{
+				router:          r,
+				config:          mockConfig,
+				transferService: mockTransferSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetInTransitStocks_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		queries            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			queries:            "?product_id_in=" + uuid.NewString(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - error handle get in-transit stocks",
+			queries:            "",
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockTransferSvc := &mocks.TransferService{}
+			mockTransferSvc.
+				On("GetInTransitStocks", mock.Anything, mock.Anything).
+				Return([]model.InTransitStock{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/transfers/in-transit"+scenario.queries, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &transferHandler{
+				router:          r,
+				config:          mockConfig,
+				transferService: mockTransferSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetTransferByID_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockParam          string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid stock transfer ID",
+			mockParam:          "invalid-id",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get transfer",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockTransferSvc := &mocks.TransferService{}
+			mockTransferSvc.
+				On("GetTransferByID", mock.Anything, mock.Anything).
+				Return(model.StockTransfer{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/transfers/"+scenario.mockParam, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &transferHandler{
+				router:          r,
+				config:          mockConfig,
+				transferService: mockTransferSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestMarkInTransit_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockParam          string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid stock transfer ID",
+			mockParam:          "invalid-id",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle mark in transit",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockTransferSvc := &mocks.TransferService{}
+			mockTransferSvc.
+				On("MarkInTransit", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/transfers/"+scenario.mockParam+"/in-transit", nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &transferHandler{
+				router:          r,
+				config:          mockConfig,
+				transferService: mockTransferSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestReceiveTransfer_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockParam          string
+		mockReq            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockParam:          uuid.NewString(),
+			mockReq:            `{"received_quantity": 8, "lost_quantity": 2}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid stock transfer ID",
+			mockParam:          "invalid-id",
+			mockReq:            `{"received_quantity": 8}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - invalid request body",
+			mockParam:          uuid.NewString(),
+			mockReq:            `{"received_quantity": -1}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle receive transfer",
+			mockParam:          uuid.NewString(),
+			mockReq:            `{"received_quantity": 8}`,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockTransferSvc := &mocks.TransferService{}
+			mockTransferSvc.
+				On("ReceiveTransfer", mock.Anything, mock.Anything).
+				Return(model.StockTransferReceipt{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/transfers/"+scenario.mockParam+"/receipts", strings.NewReader(scenario.mockReq))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &transferHandler{
+				router:          r,
+				config:          mockConfig,
+				transferService: mockTransferSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package payload
+
+import "github.com/google/uuid"
+
+// DispatchTransferReq sends stock from one warehouse to another. The quantity
+// leaves the source warehouse right away and only arrives at the destination
+// once it is received.
+type DispatchTransferReq struct {
+	FromWarehouseID uuid.UUID `json:"from_warehouse_id" binding:"required"`
+	ToWarehouseID   uuid.UUID `json:"to_warehouse_id" binding:"required"`
+	ProductID       uuid.UUID `json:"product_id" binding:"required"`
+	Quantity        int       `json:"quantity" binding:"required,min=1"`
+	Notes           *string   `json:"notes"`
+}
@@ -0,0 +1,6 @@
+package payload
+
+type GetInTransitStocksReq struct {
+	ProductIDIN     []string `form:"product_id_in" binding:"omitempty"`
+	ToWarehouseIDIN []string `form:"to_warehouse_id_in" binding:"omitempty"`
+}
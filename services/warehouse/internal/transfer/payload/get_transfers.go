@@ -0,0 +1,8 @@
+package payload
+
+type GetTransfersReq struct {
+	FromWarehouseIDIN []string `form:"from_warehouse_id_in" binding:"omitempty"`
+	ToWarehouseIDIN   []string `form:"to_warehouse_id_in" binding:"omitempty"`
+	ProductIDIN       []string `form:"product_id_in" binding:"omitempty"`
+	StatusIN          []string `form:"status_in" binding:"omitempty,dive,oneof=dispatched in_transit partially_received received"`
+}
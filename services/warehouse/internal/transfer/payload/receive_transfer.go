@@ -0,0 +1,13 @@
+package payload
+
+import "github.com/google/uuid"
+
+// ReceiveTransferReq books a delivery of a transfer into the destination
+// warehouse. Lost quantities (e.g. damaged or missing on the way) never reach
+// the destination stock and are only recorded on the transfer.
+type ReceiveTransferReq struct {
+	ID               uuid.UUID `json:"-"`
+	ReceivedQuantity int       `json:"received_quantity" binding:"min=0"`
+	LostQuantity     int       `json:"lost_quantity" binding:"min=0"`
+	Note             *string   `json:"note"`
+}
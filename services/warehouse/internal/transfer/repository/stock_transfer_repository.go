@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer/payload"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockery --name=StockTransferRepository --case underscore
+type StockTransferRepository interface {
+	WithTX(tx *gorm.DB) StockTransferRepository
+	WithLockForUpdate() StockTransferRepository
+	GetStockTransfers(ctx context.Context, req payload.GetTransfersReq) ([]model.StockTransfer, error)
+	GetStockTransferByID(ctx context.Context, id string) (model.StockTransfer, error)
+	UpdateStockTransfer(ctx context.Context, transfer *model.StockTransfer) error
+	CreateStockTransferReceipt(ctx context.Context, receipt *model.StockTransferReceipt) error
+	GetInTransitStocks(ctx context.Context, req payload.GetInTransitStocksReq) ([]model.InTransitStock, error)
+}
+
+type stockTransferRepository struct {
+	db *gorm.DB
+}
+
+func NewStockTransferRepository(db *gorm.DB) StockTransferRepository {
+	return &stockTransferRepository{db: db}
+}
+
+func (r *stockTransferRepository) WithTX(tx *gorm.DB) StockTransferRepository {
+	if tx == nil {
+		return r
+	}
+	return &stockTransferRepository{db: tx}
+}
+
+func (r *stockTransferRepository) WithLockForUpdate() StockTransferRepository {
+	return &stockTransferRepository{
+		db: r.db.Clauses(clause.Locking{Strength: "UPDATE"}),
+	}
+}
+
+func (r *stockTransferRepository) GetStockTransfers(ctx context.Context, req payload.GetTransfersReq) ([]model.StockTransfer, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockTransferRepository.GetStockTransfers")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx).Model(&model.StockTransfer{})
+	if len(req.FromWarehouseIDIN) > 0 {
+		stmt = stmt.Where("from_warehouse_id IN ?", req.FromWarehouseIDIN)
+	}
+
+	if len(req.ToWarehouseIDIN) > 0 {
+		stmt = stmt.Where("to_warehouse_id IN ?", req.ToWarehouseIDIN)
+	}
+
+	if len(req.ProductIDIN) > 0 {
+		stmt = stmt.Where("product_id IN ?", req.ProductIDIN)
+	}
+
+	if len(req.StatusIN) > 0 {
+		stmt = stmt.Where("status IN ?", req.StatusIN)
+	}
+
+	var transfers []model.StockTransfer
+	if err := stmt.Order("created_at DESC").Find(&transfers).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get stock transfers")
+	}
+	return transfers, nil
+}
+
+func (r *stockTransferRepository) GetStockTransferByID(ctx context.Context, id string) (model.StockTransfer, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockTransferRepository.GetStockTransferByID")
+	defer span.End()
+
+	var transfer model.StockTransfer
+	if err := r.db.WithContext(ctx).
+		Preload("Receipts").
+		Where("id = ?", id).
+		First(&transfer).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		if err == gorm.ErrRecordNotFound {
+			return model.StockTransfer{}, apperr.WrapWithCode(err, apperr.CodeHTTPNotFound, "stock transfer not found")
+		}
+		return model.StockTransfer{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get stock transfer by ID")
+	}
+	return transfer, nil
+}
+
+func (r *stockTransferRepository) UpdateStockTransfer(ctx context.Context, transfer *model.StockTransfer) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockTransferRepository.UpdateStockTransfer")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Save(transfer).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to update stock transfer")
+	}
+	return nil
+}
+
+func (r *stockTransferRepository) CreateStockTransferReceipt(ctx context.Context, receipt *model.StockTransferReceipt) error {
+	ctx, span := observ.GetTracer().Start(ctx, "stockTransferRepository.CreateStockTransferReceipt")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(receipt).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to create stock transfer receipt")
+	}
+	return nil
+}
+
+// GetInTransitStocks sums the quantity still on the way per product and
+// destination warehouse over all transfers that are not fully received.
+func (r *stockTransferRepository) GetInTransitStocks(ctx context.Context, req payload.GetInTransitStocksReq) ([]model.InTransitStock, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "stockTransferRepository.GetInTransitStocks")
+	defer span.End()
+
+	stmt := r.db.WithContext(ctx).
+		Model(&model.StockTransfer{}).
+		Select("product_id", "to_warehouse_id", "sum(quantity - received_quantity - lost_quantity) as quantity").
+		Where("status IN ?", []string{
+			constant.StockTransferStatusDispatched,
+			constant.StockTransferStatusInTransit,
+			constant.StockTransferStatusPartiallyReceived,
+		})
+
+	if len(req.ProductIDIN) > 0 {
+		stmt = stmt.Where("product_id IN ?", req.ProductIDIN)
+	}
+
+	if len(req.ToWarehouseIDIN) > 0 {
+		stmt = stmt.Where("to_warehouse_id IN ?", req.ToWarehouseIDIN)
+	}
+
+	var stocks []model.InTransitStock
+	if err := stmt.Group("product_id, to_warehouse_id").Order("product_id, to_warehouse_id").Scan(&stocks).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get in-transit stocks")
+	}
+	return stocks, nil
+}
@@ -0,0 +1,336 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer/payload"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStockTransfers(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetTransfersReq)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		req     payload.GetTransfersReq
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get stock transfers",
+			req: payload.GetTransfersReq{
+				FromWarehouseIDIN: []string{uuid.New().String()},
+				ToWarehouseIDIN:   []string{uuid.New().String()},
+				ProductIDIN:       []string{uuid.New().String()},
+				StatusIN:          []string{constant.StockTransferStatusInTransit},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetTransfersReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_transfers" WHERE from_warehouse_id IN ($1) AND to_warehouse_id IN ($2) AND product_id IN ($3) AND status IN ($4) ORDER BY created_at DESC`,
+						),
+					).WithArgs(req.FromWarehouseIDIN[0], req.ToWarehouseIDIN[0], req.ProductIDIN[0], req.StatusIN[0]).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "from_warehouse_id", "to_warehouse_id", "product_id", "quantity", "status", "created_at", "updated_at"}).
+							AddRow(uuid.New(), req.FromWarehouseIDIN[0], req.ToWarehouseIDIN[0], req.ProductIDIN[0], 10, req.StatusIN[0], time.Now(), time.Now()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get stock transfers",
+			req:  payload.GetTransfersReq{},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetTransfersReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_transfers" ORDER BY created_at DESC`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.req)
+
+			repo := NewStockTransferRepository(mockDb.Db)
+
+			transfers, err := repo.GetStockTransfers(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, transfers, 1)
+		})
+	}
+}
+
+func TestGetStockTransferByID(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, id uuid.UUID)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get stock transfer by id",
+			id:   uuid.New(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, id uuid.UUID) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_transfers" WHERE id = $1 ORDER BY "stock_transfers"."id" LIMIT $2`,
+						),
+					).WithArgs(id.String(), 1).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "quantity", "status", "received_quantity"}).
+							AddRow(id, 10, constant.StockTransferStatusPartiallyReceived, 4),
+					)
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_transfer_receipts" WHERE "stock_transfer_receipts"."stock_transfer_id" = $1`,
+						),
+					).WithArgs(id).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "stock_transfer_id", "received_quantity", "lost_quantity"}).
+							AddRow(uuid.New(), id, 4, 0),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - stock transfer not found",
+			id:   uuid.New(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, id uuid.UUID) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT * FROM "stock_transfers" WHERE id = $1 ORDER BY "stock_transfers"."id" LIMIT $2`,
+						),
+					).WithArgs(id.String(), 1).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}),
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.id)
+
+			repo := NewStockTransferRepository(mockDb.Db)
+
+			transfer, err := repo.GetStockTransferByID(context.Background(), tt.id.String())
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Len(t, transfer.Receipts, 1)
+		})
+	}
+}
+
+func TestCreateStockTransferReceipt(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, data model.StockTransferReceipt)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name string
+		data model.StockTransferReceipt
+		sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success",
+			data: model.StockTransferReceipt{
+				StockTransferID:  uuid.New(),
+				ReceivedQuantity: 8,
+				LostQuantity:     2,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.StockTransferReceipt) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "stock_transfer_receipts" ("stock_transfer_id","received_quantity","lost_quantity","note","created_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`,
+						),
+					).WithArgs(
+						data.StockTransferID,
+						data.ReceivedQuantity,
+						data.LostQuantity,
+						data.Note,
+						sqlmock.AnyArg(),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to create stock transfer receipt",
+			data: model.StockTransferReceipt{
+				StockTransferID:  uuid.New(),
+				ReceivedQuantity: 8,
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, data model.StockTransferReceipt) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`INSERT INTO "stock_transfer_receipts" ("stock_transfer_id","received_quantity","lost_quantity","note","created_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.data)
+
+			repo := NewStockTransferRepository(mockDb.Db)
+
+			err := repo.CreateStockTransferReceipt(context.Background(), &tt.data)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetInTransitStocks(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetInTransitStocksReq)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		req     payload.GetInTransitStocksReq
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success - get in-transit stocks",
+			req: payload.GetInTransitStocksReq{
+				ProductIDIN:     []string{uuid.New().String()},
+				ToWarehouseIDIN: []string{uuid.New().String()},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetInTransitStocksReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT "product_id","to_warehouse_id",sum(quantity - received_quantity - lost_quantity) as quantity FROM "stock_transfers" WHERE status IN ($1,$2,$3) AND product_id IN ($4) AND to_warehouse_id IN ($5) GROUP BY product_id, to_warehouse_id ORDER BY product_id, to_warehouse_id`,
+						),
+					).WithArgs(
+						constant.StockTransferStatusDispatched,
+						constant.StockTransferStatusInTransit,
+						constant.StockTransferStatusPartiallyReceived,
+						req.ProductIDIN[0],
+						req.ToWarehouseIDIN[0],
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"product_id", "to_warehouse_id", "quantity"}).
+							AddRow(req.ProductIDIN[0], req.ToWarehouseIDIN[0], 6),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get in-transit stocks",
+			req:  payload.GetInTransitStocksReq{},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetInTransitStocksReq) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(
+							`SELECT "product_id","to_warehouse_id",sum(quantity - received_quantity - lost_quantity) as quantity FROM "stock_transfers" WHERE status IN ($1,$2,$3) GROUP BY product_id, to_warehouse_id ORDER BY product_id, to_warehouse_id`,
+						),
+					).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.req)
+
+			repo := NewStockTransferRepository(mockDb.Db)
+
+			stocks, err := repo.GetInTransitStocks(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, []model.InTransitStock{
+				{ProductID: uuid.MustParse(tt.req.ProductIDIN[0]), ToWarehouseID: uuid.MustParse(tt.req.ToWarehouseIDIN[0]), Quantity: 6},
+			}, stocks)
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	stockrepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository"
+	stockservice "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer/repository"
+	warehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
+	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --name=TransferService --case underscore
+type TransferService interface {
+	DispatchTransfer(ctx context.Context, req payload.DispatchTransferReq) (model.StockTransfer, error)
+	GetTransfers(ctx context.Context, req payload.GetTransfersReq) ([]model.StockTransfer, error)
+	GetTransferByID(ctx context.Context, id string) (model.StockTransfer, error)
+	MarkInTransit(ctx context.Context, id string) error
+	ReceiveTransfer(ctx context.Context, req payload.ReceiveTransferReq) (model.StockTransferReceipt, error)
+	GetInTransitStocks(ctx context.Context, req payload.GetInTransitStocksReq) ([]model.InTransitStock, error)
+}
+
+type transferService struct {
+	config          *config.Config
+	logger          *pkg.Logger
+	db              *gorm.DB
+	transferRepo    repository.StockTransferRepository
+	stockRepo       stockrepository.StockRepository
+	stockLotRepo    stockrepository.StockLotRepository
+	stockSerialRepo stockrepository.StockSerialRepository
+	warehouseRepo   warehouserepository.WarehouseRepository
+	stockService    stockservice.StockService
+}
+
+func NewTransferService(
+	config *config.Config,
+	logger *pkg.Logger,
+	db *gorm.DB,
+	transferRepo repository.StockTransferRepository,
+	stockRepo stockrepository.StockRepository,
+	stockLotRepo stockrepository.StockLotRepository,
+	stockSerialRepo stockrepository.StockSerialRepository,
+	warehouseRepo warehouserepository.WarehouseRepository,
+	stockService stockservice.StockService,
+) TransferService {
+	return &transferService{
+		config:          config,
+		logger:          logger,
+		db:              db,
+		transferRepo:    transferRepo,
+		stockRepo:       stockRepo,
+		stockLotRepo:    stockLotRepo,
+		stockSerialRepo: stockSerialRepo,
+		warehouseRepo:   warehouseRepo,
+		stockService:    stockService,
+	}
+}
+
+// DispatchTransfer takes the quantity out of the source warehouse and records
+// it as dispatched. Draining warehouses may still send stock away. Both
+// warehouses are share-locked so neither can be archived under the transfer.
+func (s *transferService) DispatchTransfer(ctx context.Context, req payload.DispatchTransferReq) (result model.StockTransfer, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "transferService.DispatchTransfer")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if req.FromWarehouseID == req.ToWarehouseID {
+		return model.StockTransfer{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "from_warehouse_id and to_warehouse_id cannot be the same")
+	}
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	fromWarehouse, err := s.warehouseRepo.WithTX(tx).WithLockForShare().GetWarehouseByID(ctx, req.FromWarehouseID.String())
+	if err != nil {
+		return model.StockTransfer{}, err
+	}
+
+	if fromWarehouse.Status != constant.WarehouseStatusActive && fromWarehouse.Status != constant.WarehouseStatusDraining {
+		return model.StockTransfer{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "from warehouse is not active")
+	}
+
+	toWarehouse, err := s.warehouseRepo.WithTX(tx).WithLockForShare().GetWarehouseByID(ctx, req.ToWarehouseID.String())
+	if err != nil {
+		return model.StockTransfer{}, err
+	}
+
+	if toWarehouse.Status != constant.WarehouseStatusActive {
+		return model.StockTransfer{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "to warehouse is not active")
+	}
+
+	// in-transit quantities have no serials to carry, serialized units are
+	// moved with an immediate stock transfer instead
+	serialized, err := s.stockSerialRepo.WithTX(tx).GetSerializedProducts(ctx, []string{req.ProductID.String()})
+	if err != nil {
+		return model.StockTransfer{}, err
+	}
+	if len(serialized) > 0 {
+		return model.StockTransfer{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "product is serialized, move it with an immediate stock transfer")
+	}
+
+	now := time.Now()
+	transfer := model.StockTransfer{
+		FromWarehouseID: req.FromWarehouseID,
+		ToWarehouseID:   req.ToWarehouseID,
+		ProductID:       req.ProductID,
+		Quantity:        req.Quantity,
+		Status:          constant.StockTransferStatusDispatched,
+		Notes:           req.Notes,
+		DispatchedAt:    &now,
+	}
+	if err := s.stockRepo.WithTX(tx).CreateStockTransfer(ctx, &transfer); err != nil {
+		return model.StockTransfer{}, err
+	}
+
+	// the lots travel with the transfer and are taken before the source is
+	// decreased, expired quantity is only taken once the unexpired stock runs out
+	expired, err := s.stockLotRepo.WithTX(tx).DispatchStockLots(ctx, transfer.ID.String(), req.ProductID.String(), req.FromWarehouseID.String(), req.Quantity)
+	if err != nil {
+		return model.StockTransfer{}, err
+	}
+	if expired > 0 {
+		return model.StockTransfer{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "insufficient unexpired stock in from warehouse")
+	}
+
+	if err := s.stockRepo.WithTX(tx).DecreaseStockQty(ctx, req.ProductID.String(), req.FromWarehouseID.String(), req.Quantity); err != nil {
+		return model.StockTransfer{}, err
+	}
+
+	productIDs := []string{req.ProductID.String()}
+	alerts, err := s.stockService.SettleStockChanges(ctx, tx, productIDs)
+	if err != nil {
+		return model.StockTransfer{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return model.StockTransfer{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.logger.WithContext(ctx).Infow("Stock transfer dispatched",
+		"stock_transfer_id", transfer.ID,
+		"from_warehouse_id", req.FromWarehouseID,
+		"to_warehouse_id", req.ToWarehouseID,
+		"product_id", req.ProductID,
+		"quantity", req.Quantity,
+	)
+
+	s.stockService.PublishStockChanges(ctx, productIDs, alerts)
+
+	return transfer, nil
+}
+
+func (s *transferService) GetTransfers(ctx context.Context, req payload.GetTransfersReq) (result []model.StockTransfer, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "transferService.GetTransfers")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	transfers, err := s.transferRepo.GetStockTransfers(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfers, nil
+}
+
+func (s *transferService) GetTransferByID(ctx context.Context, id string) (result model.StockTransfer, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "transferService.GetTransferByID")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	transfer, err := s.transferRepo.GetStockTransferByID(ctx, id)
+	if err != nil {
+		return model.StockTransfer{}, err
+	}
+
+	return transfer, nil
+}
+
+func (s *transferService) MarkInTransit(ctx context.Context, id string) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "transferService.MarkInTransit")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	transfer, err := s.transferRepo.WithTX(tx).WithLockForUpdate().GetStockTransferByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if transfer.Status != constant.StockTransferStatusDispatched {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "only dispatched stock transfers can be marked in transit")
+	}
+
+	now := time.Now()
+	transfer.Status = constant.StockTransferStatusInTransit
+	transfer.InTransitAt = &now
+	if err := s.transferRepo.WithTX(tx).UpdateStockTransfer(ctx, &transfer); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// ReceiveTransfer books a full or partial delivery into the destination
+// warehouse, along with the lots it carries. Only transfers marked in transit
+// can be received. Lost quantities settle the transfer without adding stock. The transfer is received once
+// nothing is left in transit.
+func (s *transferService) ReceiveTransfer(ctx context.Context, req payload.ReceiveTransferReq) (result model.StockTransferReceipt, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "transferService.ReceiveTransfer")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	if req.ReceivedQuantity+req.LostQuantity <= 0 {
+		return model.StockTransferReceipt{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "received or lost quantity must be greater than zero")
+	}
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	transfer, err := s.transferRepo.WithTX(tx).WithLockForUpdate().GetStockTransferByID(ctx, req.ID.String())
+	if err != nil {
+		return model.StockTransferReceipt{}, err
+	}
+
+	switch transfer.Status {
+	case constant.StockTransferStatusInTransit, constant.StockTransferStatusPartiallyReceived:
+	case constant.StockTransferStatusReceived:
+		return model.StockTransferReceipt{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "stock transfer is already received")
+	default:
+		return model.StockTransferReceipt{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "stock transfer must be in transit before it can be received")
+	}
+
+	outstanding := transfer.Quantity - transfer.ReceivedQuantity - transfer.LostQuantity
+	if req.ReceivedQuantity+req.LostQuantity > outstanding {
+		return model.StockTransferReceipt{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "received and lost quantity exceed the quantity in transit")
+	}
+
+	if req.ReceivedQuantity > 0 {
+		toWarehouse, err := s.warehouseRepo.WithTX(tx).WithLockForShare().GetWarehouseByID(ctx, transfer.ToWarehouseID.String())
+		if err != nil {
+			return model.StockTransferReceipt{}, err
+		}
+
+		if toWarehouse.Status != constant.WarehouseStatusActive && toWarehouse.Status != constant.WarehouseStatusDraining {
+			return model.StockTransferReceipt{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "to warehouse is not active")
+		}
+
+		if err := s.stockRepo.WithTX(tx).IncreaseStockQty(ctx, transfer.ProductID.String(), transfer.ToWarehouseID.String(), req.ReceivedQuantity); err != nil {
+			return model.StockTransferReceipt{}, err
+		}
+
+		if err := s.stockLotRepo.WithTX(tx).ReceiveStockLots(ctx, transfer.ID.String(), transfer.ProductID.String(), transfer.ToWarehouseID.String(), req.ReceivedQuantity); err != nil {
+			return model.StockTransferReceipt{}, err
+		}
+	}
+
+	receipt := model.StockTransferReceipt{
+		StockTransferID:  transfer.ID,
+		ReceivedQuantity: req.ReceivedQuantity,
+		LostQuantity:     req.LostQuantity,
+		Note:             req.Note,
+	}
+	if err := s.transferRepo.WithTX(tx).CreateStockTransferReceipt(ctx, &receipt); err != nil {
+		return model.StockTransferReceipt{}, err
+	}
+
+	transfer.ReceivedQuantity += req.ReceivedQuantity
+	transfer.LostQuantity += req.LostQuantity
+	transfer.Status = constant.StockTransferStatusPartiallyReceived
+	if req.ReceivedQuantity+req.LostQuantity == outstanding {
+		now := time.Now()
+		transfer.Status = constant.StockTransferStatusReceived
+		transfer.ReceivedAt = &now
+	}
+
+	if err := s.transferRepo.WithTX(tx).UpdateStockTransfer(ctx, &transfer); err != nil {
+		return model.StockTransferReceipt{}, err
+	}
+
+	var productIDs []string
+	var alerts []model.StockAlert
+	if req.ReceivedQuantity > 0 {
+		productIDs = []string{transfer.ProductID.String()}
+		alerts, err = s.stockService.SettleStockChanges(ctx, tx, productIDs)
+		if err != nil {
+			return model.StockTransferReceipt{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return model.StockTransferReceipt{}, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.logger.WithContext(ctx).Infow("Stock transfer received",
+		"stock_transfer_id", transfer.ID,
+		"to_warehouse_id", transfer.ToWarehouseID,
+		"received_quantity", req.ReceivedQuantity,
+		"lost_quantity", req.LostQuantity,
+		"status", transfer.Status,
+	)
+
+	if len(productIDs) > 0 {
+		s.stockService.PublishStockChanges(ctx, productIDs, alerts)
+	}
+
+	return receipt, nil
+}
+
+func (s *transferService) GetInTransitStocks(ctx context.Context, req payload.GetInTransitStocksReq) (result []model.InTransitStock, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "transferService.GetInTransitStocks")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	stocks, err := s.transferRepo.GetInTransitStocks(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return stocks, nil
+}
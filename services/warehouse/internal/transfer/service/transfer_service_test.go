@@ -0,0 +1,611 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	stockRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository/mocks"
+	stockSvcMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service/mocks"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer/payload"
+	transferRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer/repository/mocks"
+	warehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository/mocks"
+)
+
+func TestDispatchTransfer(t *testing.T) {
+	type dependencyMocks struct {
+		db              sqlmock.Sqlmock
+		stockRepo       *stockRepoMock.StockRepository
+		stockLotRepo    *stockRepoMock.StockLotRepository
+		stockSerialRepo *stockRepoMock.StockSerialRepository
+		warehouseRepo   *warehouseRepoMock.WarehouseRepository
+		stockService    *stockSvcMock.StockService
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	fromWarehouseID := uuid.New()
+	toWarehouseID := uuid.New()
+	productID := uuid.New()
+	req := payload.DispatchTransferReq{
+		FromWarehouseID: fromWarehouseID,
+		ToWarehouseID:   toWarehouseID,
+		ProductID:       productID,
+		Quantity:        10,
+	}
+
+	expectSerialized := func(m dependencyMocks, products ...model.SerializedProduct) {
+		m.stockSerialRepo.On("WithTX", mock.Anything).
+			Return(m.stockSerialRepo)
+		m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, []string{productID.String()}).
+			Return(products, nil)
+	}
+
+	expectLots := func(m dependencyMocks, expired int) {
+		m.stockLotRepo.On("WithTX", mock.Anything).
+			Return(m.stockLotRepo)
+		m.stockLotRepo.On("DispatchStockLots", mock.Anything, mock.Anything, productID.String(), fromWarehouseID.String(), 10).
+			Return(expired, nil)
+	}
+
+	tests := []struct {
+		name    string
+		req     payload.DispatchTransferReq
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - stock leaves the source warehouse",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, fromWarehouseID.String()).
+					Return(model.Warehouse{ID: fromWarehouseID, Status: constant.WarehouseStatusDraining}, nil)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, toWarehouseID.String()).
+					Return(model.Warehouse{ID: toWarehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				expectSerialized(m)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("CreateStockTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.StockTransfer) bool {
+					return transfer.Status == constant.StockTransferStatusDispatched &&
+						transfer.Quantity == 10 &&
+						transfer.ReceivedQuantity == 0 &&
+						transfer.DispatchedAt != nil
+				})).
+					Return(nil)
+				expectLots(m, 0)
+				m.stockRepo.On("DecreaseStockQty", mock.Anything, productID.String(), fromWarehouseID.String(), 10).
+					Return(nil)
+
+				m.stockService.On("SettleStockChanges", mock.Anything, mock.Anything, []string{productID.String()}).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+
+				m.stockService.On("PublishStockChanges", mock.Anything, []string{productID.String()}, []model.StockAlert{}).
+					Return()
+			},
+		},
+		{
+			name: "error - same source and destination",
+			req: payload.DispatchTransferReq{
+				FromWarehouseID: fromWarehouseID,
+				ToWarehouseID:   fromWarehouseID,
+				ProductID:       productID,
+				Quantity:        10,
+			},
+			setup:   func(m dependencyMocks) {},
+			wantErr: true,
+		},
+		{
+			name: "error - destination warehouse is not active",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, fromWarehouseID.String()).
+					Return(model.Warehouse{ID: fromWarehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, toWarehouseID.String()).
+					Return(model.Warehouse{ID: toWarehouseID, Status: constant.WarehouseStatusDraining}, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - product is serialized",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, fromWarehouseID.String()).
+					Return(model.Warehouse{ID: fromWarehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, toWarehouseID.String()).
+					Return(model.Warehouse{ID: toWarehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				expectSerialized(m, model.SerializedProduct{ProductID: productID})
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - not enough unreserved stock",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, fromWarehouseID.String()).
+					Return(model.Warehouse{ID: fromWarehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, toWarehouseID.String()).
+					Return(model.Warehouse{ID: toWarehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				expectSerialized(m)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("CreateStockTransfer", mock.Anything, mock.Anything).
+					Return(nil)
+				expectLots(m, 0)
+				m.stockRepo.On("DecreaseStockQty", mock.Anything, productID.String(), fromWarehouseID.String(), 10).
+					Return(apperr.NewWithCode(apperr.CodeHTTPBadRequest, "stock quantity cannot drop below reserved quantity"))
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - only expired stock left to dispatch",
+			req:  req,
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, fromWarehouseID.String()).
+					Return(model.Warehouse{ID: fromWarehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, toWarehouseID.String()).
+					Return(model.Warehouse{ID: toWarehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				expectSerialized(m)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("CreateStockTransfer", mock.Anything, mock.Anything).
+					Return(nil)
+				expectLots(m, 3)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:              mockDb.Mock,
+				stockRepo:       stockRepoMock.NewStockRepository(t),
+				stockLotRepo:    stockRepoMock.NewStockLotRepository(t),
+				stockSerialRepo: stockRepoMock.NewStockSerialRepository(t),
+				warehouseRepo:   warehouseRepoMock.NewWarehouseRepository(t),
+				stockService:    stockSvcMock.NewStockService(t),
+			}
+			transferSvc := transferService{
+				logger:          pkg.InitLogger(&config.Config{}),
+				db:              mockDb.Db,
+				stockRepo:       mocks.stockRepo,
+				stockLotRepo:    mocks.stockLotRepo,
+				stockSerialRepo: mocks.stockSerialRepo,
+				warehouseRepo:   mocks.warehouseRepo,
+				stockService:    mocks.stockService,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			transfer, err := transferSvc.DispatchTransfer(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, constant.StockTransferStatusDispatched, transfer.Status)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMarkInTransit(t *testing.T) {
+	type dependencyMocks struct {
+		db           sqlmock.Sqlmock
+		transferRepo *transferRepoMock.StockTransferRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	transferID := uuid.New()
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - dispatched transfer is in transit",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.transferRepo.On("WithTX", mock.Anything).
+					Return(m.transferRepo)
+				m.transferRepo.On("WithLockForUpdate").
+					Return(m.transferRepo)
+				m.transferRepo.On("GetStockTransferByID", mock.Anything, transferID.String()).
+					Return(model.StockTransfer{ID: transferID, Status: constant.StockTransferStatusDispatched}, nil)
+				m.transferRepo.On("UpdateStockTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.StockTransfer) bool {
+					return transfer.Status == constant.StockTransferStatusInTransit && transfer.InTransitAt != nil
+				})).
+					Return(nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - transfer is already partially received",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.transferRepo.On("WithTX", mock.Anything).
+					Return(m.transferRepo)
+				m.transferRepo.On("WithLockForUpdate").
+					Return(m.transferRepo)
+				m.transferRepo.On("GetStockTransferByID", mock.Anything, transferID.String()).
+					Return(model.StockTransfer{ID: transferID, Status: constant.StockTransferStatusPartiallyReceived}, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:           mockDb.Mock,
+				transferRepo: transferRepoMock.NewStockTransferRepository(t),
+			}
+			transferSvc := transferService{
+				logger:       pkg.InitLogger(&config.Config{}),
+				db:           mockDb.Db,
+				transferRepo: mocks.transferRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := transferSvc.MarkInTransit(context.Background(), transferID.String())
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReceiveTransfer(t *testing.T) {
+	type dependencyMocks struct {
+		db            sqlmock.Sqlmock
+		transferRepo  *transferRepoMock.StockTransferRepository
+		stockRepo     *stockRepoMock.StockRepository
+		stockLotRepo  *stockRepoMock.StockLotRepository
+		warehouseRepo *warehouseRepoMock.WarehouseRepository
+		stockService  *stockSvcMock.StockService
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	transferID := uuid.New()
+	toWarehouseID := uuid.New()
+	productID := uuid.New()
+
+	transfer := func(status string, received, lost int) model.StockTransfer {
+		return model.StockTransfer{
+			ID:               transferID,
+			ToWarehouseID:    toWarehouseID,
+			ProductID:        productID,
+			Quantity:         10,
+			Status:           status,
+			ReceivedQuantity: received,
+			LostQuantity:     lost,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		req     payload.ReceiveTransferReq
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - partial receipt",
+			req:  payload.ReceiveTransferReq{ID: transferID, ReceivedQuantity: 4},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.transferRepo.On("WithTX", mock.Anything).
+					Return(m.transferRepo)
+				m.transferRepo.On("WithLockForUpdate").
+					Return(m.transferRepo)
+				m.transferRepo.On("GetStockTransferByID", mock.Anything, transferID.String()).
+					Return(transfer(constant.StockTransferStatusInTransit, 0, 0), nil)
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, toWarehouseID.String()).
+					Return(model.Warehouse{ID: toWarehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), toWarehouseID.String(), 4).
+					Return(nil)
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("ReceiveStockLots", mock.Anything, transferID.String(), productID.String(), toWarehouseID.String(), 4).
+					Return(nil)
+
+				m.transferRepo.On("CreateStockTransferReceipt", mock.Anything, mock.Anything).
+					Return(nil)
+				m.transferRepo.On("UpdateStockTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.StockTransfer) bool {
+					return transfer.Status == constant.StockTransferStatusPartiallyReceived &&
+						transfer.ReceivedQuantity == 4 &&
+						transfer.ReceivedAt == nil
+				})).
+					Return(nil)
+
+				m.stockService.On("SettleStockChanges", mock.Anything, mock.Anything, []string{productID.String()}).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+
+				m.stockService.On("PublishStockChanges", mock.Anything, []string{productID.String()}, []model.StockAlert{}).
+					Return()
+			},
+		},
+		{
+			name: "success - rest received with loss",
+			req:  payload.ReceiveTransferReq{ID: transferID, ReceivedQuantity: 4, LostQuantity: 2},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.transferRepo.On("WithTX", mock.Anything).
+					Return(m.transferRepo)
+				m.transferRepo.On("WithLockForUpdate").
+					Return(m.transferRepo)
+				m.transferRepo.On("GetStockTransferByID", mock.Anything, transferID.String()).
+					Return(transfer(constant.StockTransferStatusPartiallyReceived, 4, 0), nil)
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, toWarehouseID.String()).
+					Return(model.Warehouse{ID: toWarehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), toWarehouseID.String(), 4).
+					Return(nil)
+				m.stockLotRepo.On("WithTX", mock.Anything).
+					Return(m.stockLotRepo)
+				m.stockLotRepo.On("ReceiveStockLots", mock.Anything, transferID.String(), productID.String(), toWarehouseID.String(), 4).
+					Return(nil)
+
+				m.transferRepo.On("CreateStockTransferReceipt", mock.Anything, mock.MatchedBy(func(receipt *model.StockTransferReceipt) bool {
+					return receipt.ReceivedQuantity == 4 && receipt.LostQuantity == 2
+				})).
+					Return(nil)
+				m.transferRepo.On("UpdateStockTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.StockTransfer) bool {
+					return transfer.Status == constant.StockTransferStatusReceived &&
+						transfer.ReceivedQuantity == 8 &&
+						transfer.LostQuantity == 2 &&
+						transfer.ReceivedAt != nil
+				})).
+					Return(nil)
+
+				m.stockService.On("SettleStockChanges", mock.Anything, mock.Anything, []string{productID.String()}).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+
+				m.stockService.On("PublishStockChanges", mock.Anything, []string{productID.String()}, []model.StockAlert{}).
+					Return()
+			},
+		},
+		{
+			name: "success - everything lost",
+			req:  payload.ReceiveTransferReq{ID: transferID, LostQuantity: 10},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.transferRepo.On("WithTX", mock.Anything).
+					Return(m.transferRepo)
+				m.transferRepo.On("WithLockForUpdate").
+					Return(m.transferRepo)
+				m.transferRepo.On("GetStockTransferByID", mock.Anything, transferID.String()).
+					Return(transfer(constant.StockTransferStatusInTransit, 0, 0), nil)
+				m.transferRepo.On("CreateStockTransferReceipt", mock.Anything, mock.Anything).
+					Return(nil)
+				m.transferRepo.On("UpdateStockTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.StockTransfer) bool {
+					return transfer.Status == constant.StockTransferStatusReceived && transfer.LostQuantity == 10
+				})).
+					Return(nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name:    "error - nothing received or lost",
+			req:     payload.ReceiveTransferReq{ID: transferID},
+			setup:   func(m dependencyMocks) {},
+			wantErr: true,
+		},
+		{
+			name: "error - more than in transit",
+			req:  payload.ReceiveTransferReq{ID: transferID, ReceivedQuantity: 5, LostQuantity: 1},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.transferRepo.On("WithTX", mock.Anything).
+					Return(m.transferRepo)
+				m.transferRepo.On("WithLockForUpdate").
+					Return(m.transferRepo)
+				m.transferRepo.On("GetStockTransferByID", mock.Anything, transferID.String()).
+					Return(transfer(constant.StockTransferStatusPartiallyReceived, 3, 2), nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - transfer is not in transit yet",
+			req:  payload.ReceiveTransferReq{ID: transferID, ReceivedQuantity: 1},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.transferRepo.On("WithTX", mock.Anything).
+					Return(m.transferRepo)
+				m.transferRepo.On("WithLockForUpdate").
+					Return(m.transferRepo)
+				m.transferRepo.On("GetStockTransferByID", mock.Anything, transferID.String()).
+					Return(transfer(constant.StockTransferStatusDispatched, 0, 0), nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - transfer already received",
+			req:  payload.ReceiveTransferReq{ID: transferID, ReceivedQuantity: 1},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.transferRepo.On("WithTX", mock.Anything).
+					Return(m.transferRepo)
+				m.transferRepo.On("WithLockForUpdate").
+					Return(m.transferRepo)
+				m.transferRepo.On("GetStockTransferByID", mock.Anything, transferID.String()).
+					Return(transfer(constant.StockTransferStatusReceived, 10, 0), nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - destination warehouse is inactive",
+			req:  payload.ReceiveTransferReq{ID: transferID, ReceivedQuantity: 4},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.transferRepo.On("WithTX", mock.Anything).
+					Return(m.transferRepo)
+				m.transferRepo.On("WithLockForUpdate").
+					Return(m.transferRepo)
+				m.transferRepo.On("GetStockTransferByID", mock.Anything, transferID.String()).
+					Return(transfer(constant.StockTransferStatusInTransit, 0, 0), nil)
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForShare").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, toWarehouseID.String()).
+					Return(model.Warehouse{ID: toWarehouseID, Status: constant.WarehouseStatusInactive}, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:            mockDb.Mock,
+				transferRepo:  transferRepoMock.NewStockTransferRepository(t),
+				stockRepo:     stockRepoMock.NewStockRepository(t),
+				stockLotRepo:  stockRepoMock.NewStockLotRepository(t),
+				warehouseRepo: warehouseRepoMock.NewWarehouseRepository(t),
+				stockService:  stockSvcMock.NewStockService(t),
+			}
+			transferSvc := transferService{
+				logger:        pkg.InitLogger(&config.Config{}),
+				db:            mockDb.Db,
+				transferRepo:  mocks.transferRepo,
+				stockRepo:     mocks.stockRepo,
+				stockLotRepo:  mocks.stockLotRepo,
+				warehouseRepo: mocks.warehouseRepo,
+				stockService:  mocks.stockService,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			receipt, err := transferSvc.ReceiveTransfer(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, transferID, receipt.StockTransferID)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
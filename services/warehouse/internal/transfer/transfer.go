@@ -0,0 +1,38 @@
+package transfer
+
+import (
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/_options"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/registry"
+	stockrepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository"
+	stockservice "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer/handler"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer/repository"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/transfer/service"
+	warehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
+)
+
+type TransferModule struct {
+	TransferService service.TransferService
+}
+
+type Options struct {
+	_options.DefaultOptions
+	StockService stockservice.StockService
+}
+
+func NewTransferModule(opts Options) *TransferModule {
+
+	transferRepo := repository.NewStockTransferRepository(opts.Db)
+	stockRepo := stockrepository.NewStockRepository(opts.Db)
+	stockLotRepo := stockrepository.NewStockLotRepository(opts.Db)
+	stockSerialRepo := stockrepository.NewStockSerialRepository(opts.Db)
+	warehouseRepo := warehouserepository.NewWarehouseRepository(opts.Db)
+
+	transferService := service.NewTransferService(opts.Config, opts.Logger, opts.Db, transferRepo, stockRepo, stockLotRepo, stockSerialRepo, warehouseRepo, opts.StockService)
+
+	registry.RegisterRouter(handler.NewHandler(opts.Router, opts.Config, opts.Logger, transferService))
+
+	return &TransferModule{
+		TransferService: transferService,
+	}
+}
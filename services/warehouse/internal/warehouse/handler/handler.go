@@ -31,5 +31,12 @@ func (h warehouseHandler) RegisterRoutes(base *gin.RouterGroup) {
 	g.Use(middleware.JwtMiddleware(h.config))
 
 	g.GET("", h.GetWarehouses)
+	g.POST("", h.CreateWarehouse)
+	g.GET("/:id", h.GetWarehouseByID)
 	g.PUT("/:id", h.UpdateWarehouse)
+	g.PATCH("/:id/archive", h.ArchiveWarehouse)
+	g.PATCH("/:id/drain", h.StartDrain)
+	g.GET("/:id/drain", h.GetDrainPlan)
+	g.POST("/:id/drain/transfers", h.ExecuteDrainTransfers)
+	g.PATCH("/:id/drain/complete", h.CompleteDrain)
 }
@@ -15,10 +15,11 @@ import (
 )
 
 // @Summary		Warehouse - Get Warehouses
-// @Description	get all warehouses
+// @Description	get warehouses, archived warehouses are only listed when filtered by status
 // @Tags		Warehouse
 // @Accept		json
 // @Produce		json
+// @Param		request	query	payload.GetWarehousesReq	false	"get warehouses request query parameters"
 // @Success		200	{object}	httpresp.Response{data=[]model.Warehouse}
 // @Failure		400	{object}	httpresp.HTTPErrResp
 // @Failure		404	{object}	httpresp.HTTPErrResp
@@ -29,14 +30,93 @@ func (h *warehouseHandler) GetWarehouses(c *gin.Context) {
 	ctx, span := observ.GetTracer().Start(c.Request.Context(), "warehouseHandler.GetWarehouses")
 	defer span.End()
 
-	warehouses, err := h.warehouseService.GetWarehouses(ctx)
+	var req payload.GetWarehousesReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	warehouses, total, err := h.warehouseService.GetWarehouses(ctx, req)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		httpresp.HttpRespError(c, err)
 		return
 	}
 
-	httpresp.HttpRespSuccess(c, warehouses, nil)
+	page, pageSize := req.Pagination()
+	httpresp.HttpRespSuccess(c, warehouses, &httpresp.Pagination{
+		CurrentPage:     int64(page),
+		CurrentElements: int64(len(warehouses)),
+		TotalPages:      (total + int64(pageSize) - 1) / int64(pageSize),
+		TotalElements:   total,
+		SortBy:          "created_at",
+	})
+}
+
+// @Summary		Warehouse - Create Warehouse
+// @Description	create a warehouse, the status defaults to active
+// @Tags		Warehouse
+// @Accept		json
+// @Produce		json
+// @Param		request	body	payload.CreateWarehouseReq	true	"create warehouse request body"
+// @Success		200	{object}	httpresp.Response{data=model.Warehouse}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/warehouses [post]
+func (h *warehouseHandler) CreateWarehouse(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "warehouseHandler.CreateWarehouse")
+	defer span.End()
+
+	var req payload.CreateWarehouseReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	warehouse, err := h.warehouseService.CreateWarehouse(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, warehouse, nil)
+}
+
+// @Summary		Warehouse - Get Warehouse
+// @Description	get warehouse by ID
+// @Tags		Warehouse
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"warehouse ID"
+// @Success		200	{object}	httpresp.Response{data=model.Warehouse}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/warehouses/{id} [get]
+func (h *warehouseHandler) GetWarehouseByID(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "warehouseHandler.GetWarehouseByID")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid warehouse ID"))
+		return
+	}
+
+	warehouse, err := h.warehouseService.GetWarehouseByID(ctx, id.String())
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, warehouse, nil)
 }
 
 // @Summary		Warehouse - Update Warehouse
@@ -83,3 +163,183 @@ func (h *warehouseHandler) UpdateWarehouse(c *gin.Context) {
 
 	httpresp.HttpRespSuccess(c, "success", nil)
 }
+
+// @Summary		Warehouse - Archive Warehouse
+// @Description	archive a warehouse that no longer holds stock or reservations
+// @Tags		Warehouse
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"warehouse ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/warehouses/{id}/archive [patch]
+func (h *warehouseHandler) ArchiveWarehouse(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "warehouseHandler.ArchiveWarehouse")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid warehouse ID"))
+		return
+	}
+
+	if err := h.warehouseService.ArchiveWarehouse(ctx, id.String()); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Warehouse - Start Drain
+// @Description	stop new reservations in an active warehouse so it can be emptied
+// @Tags		Warehouse
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"warehouse ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/warehouses/{id}/drain [patch]
+func (h *warehouseHandler) StartDrain(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "warehouseHandler.StartDrain")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid warehouse ID"))
+		return
+	}
+
+	if err := h.warehouseService.StartDrain(ctx, id.String()); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
+
+// @Summary		Warehouse - Get Drain Plan
+// @Description	get the reservations left in a draining warehouse and the transfers proposed to empty it
+// @Tags		Warehouse
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"warehouse ID"
+// @Param		request	query	payload.DrainWarehouseReq	false	"drain plan request query parameters"
+// @Success		200	{object}	httpresp.Response{data=payload.DrainPlan}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/warehouses/{id}/drain [get]
+func (h *warehouseHandler) GetDrainPlan(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "warehouseHandler.GetDrainPlan")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid warehouse ID"))
+		return
+	}
+
+	var req payload.DrainWarehouseReq
+	if err := c.BindQuery(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ID = id
+	plan, err := h.warehouseService.GetDrainPlan(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, plan, nil)
+}
+
+// @Summary		Warehouse - Execute Drain Transfers
+// @Description	move the unreserved stock of a draining warehouse to other active warehouses
+// @Tags		Warehouse
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"warehouse ID"
+// @Param		request	body	payload.DrainWarehouseReq	true	"drain transfers request body"
+// @Success		200	{object}	httpresp.Response{data=[]payload.DrainTransfer}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/warehouses/{id}/drain/transfers [post]
+func (h *warehouseHandler) ExecuteDrainTransfers(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "warehouseHandler.ExecuteDrainTransfers")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid warehouse ID"))
+		return
+	}
+
+	var req payload.DrainWarehouseReq
+	if err := c.BindJSON(&req); err != nil {
+		errResp := strings.Join(utils.ParseBindErrors(err), "; ")
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, errResp))
+		return
+	}
+
+	req.ID = id
+	transfers, err := h.warehouseService.ExecuteDrainTransfers(ctx, req)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, transfers, nil)
+}
+
+// @Summary		Warehouse - Complete Drain
+// @Description	deactivate a draining warehouse once it no longer holds stock or reservations
+// @Tags		Warehouse
+// @Accept		json
+// @Produce		json
+// @Param		id	path	string	true	"warehouse ID"
+// @Success		200	{object}	httpresp.Response{data=string}
+// @Failure		400	{object}	httpresp.HTTPErrResp
+// @Failure		404	{object}	httpresp.HTTPErrResp
+// @Failure		500	{object}	httpresp.HTTPErrResp
+// @Security	BearerAuth
+// @Router		/warehouses/{id}/drain/complete [patch]
+func (h *warehouseHandler) CompleteDrain(c *gin.Context) {
+	ctx, span := observ.GetTracer().Start(c.Request.Context(), "warehouseHandler.CompleteDrain")
+	defer span.End()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, apperr.WrapWithCode(err, apperr.CodeHTTPBadRequest, "invalid warehouse ID"))
+		return
+	}
+
+	if err := h.warehouseService.CompleteDrain(ctx, id.String()); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		httpresp.HttpRespError(c, err)
+		return
+	}
+
+	httpresp.HttpRespSuccess(c, "success", nil)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/payload"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/service/mocks"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -20,6 +21,7 @@ import (
 func TestGetWarehouses_ShouldReturnExpectedStatusCode(t *testing.T) {
 	testScenarios := []struct {
 		testName           string
+		queries            string
 		mockResult         []model.Warehouse
 		mockError          error
 		statusCodeExpected int
@@ -33,6 +35,24 @@ func TestGetWarehouses_ShouldReturnExpectedStatusCode(t *testing.T) {
 			},
 			mockError: nil,
 		},
+		{
+			testName:           "success - filter and paging",
+			queries:            "?status_in=archived&name=central&page=2&page_size=10",
+			statusCodeExpected: http.StatusOK,
+			mockResult: []model.Warehouse{
+				{Name: "Central Warehouse"},
+			},
+		},
+		{
+			testName:           "failed - invalid status",
+			queries:            "?status_in=unknown",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - invalid page size",
+			queries:            "?page_size=1000",
+			statusCodeExpected: http.StatusBadRequest,
+		},
 		{
 			testName:           "failed - error handle get warehouses",
 			statusCodeExpected: http.StatusInternalServerError,
@@ -53,12 +73,12 @@ func TestGetWarehouses_ShouldReturnExpectedStatusCode(t *testing.T) {
 
 			mockWarehouseSvc := &mocks.WarehouseService{}
 			mockWarehouseSvc.
-				On("GetWarehouses", mock.Anything).
-				Return(scenario.mockResult, scenario.mockError)
+				On("GetWarehouses", mock.Anything, mock.Anything).
+				Return(scenario.mockResult, int64(len(scenario.mockResult)), scenario.mockError)
 
 			rr := httptest.NewRecorder()
 			ctx := pkg.GetTestGinContext(rr)
-			ctx.Request = httptest.NewRequest(http.MethodGet, "/warehouses", nil)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/warehouses"+scenario.queries, nil)
 			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
 
 			h := &warehouseHandler{
@@ -155,3 +175,487 @@ func TestUpdateWarehouse_ShouldReturnExpectedStatusCode(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateWarehouse_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockRequest        string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockRequest:        `{"name": "Warehouse One", "address": "123 Warehouse St"}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "success - with coordinates",
+			mockRequest:        `{"name": "Warehouse One", "address": "123 Warehouse St", "latitude": 0, "longitude": 106.8}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - missing address",
+			mockRequest:        `{"name": "Warehouse One"}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - latitude without longitude",
+			mockRequest:        `{"name": "Warehouse One", "address": "123 Warehouse St", "latitude": -6.2}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - latitude out of range",
+			mockRequest:        `{"name": "Warehouse One", "address": "123 Warehouse St", "latitude": 91, "longitude": 106.8}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - cannot create archived warehouse",
+			mockRequest:        `{"name": "Warehouse One", "address": "123 Warehouse St", "status": "archived"}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle create warehouse",
+			mockRequest:        `{"name": "Warehouse One", "address": "123 Warehouse St"}`,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockWarehouseSvc := &mocks.WarehouseService{}
+			mockWarehouseSvc.
+				On("CreateWarehouse", mock.Anything, mock.Anything).
+				Return(model.Warehouse{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/warehouses", strings.NewReader(scenario.mockRequest))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &warehouseHandler{
+				router:           r,
+				config:           mockConfig,
+				warehouseService: mockWarehouseSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetWarehouseByID_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockParam          string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid warehouse ID",
+			mockParam:          "invalid-id",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get warehouse",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockWarehouseSvc := &mocks.WarehouseService{}
+			mockWarehouseSvc.
+				On("GetWarehouseByID", mock.Anything, mock.Anything).
+				Return(model.Warehouse{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/warehouses/"+scenario.mockParam, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &warehouseHandler{
+				router:           r,
+				config:           mockConfig,
+				warehouseService: mockWarehouseSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestArchiveWarehouse_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockParam          string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid warehouse ID",
+			mockParam:          "invalid-id",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle archive warehouse",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockWarehouseSvc := &mocks.WarehouseService{}
+			mockWarehouseSvc.
+				On("ArchiveWarehouse", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/warehouses/"+scenario.mockParam+"/archive", nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &warehouseHandler{
+				router:           r,
+				config:           mockConfig,
+				warehouseService: mockWarehouseSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestStartDrain_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockParam          string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid warehouse ID",
+			mockParam:          "invalid-id",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle start drain",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockWarehouseSvc := &mocks.WarehouseService{}
+			mockWarehouseSvc.
+				On("StartDrain", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/warehouses/"+scenario.mockParam+"/drain", nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &warehouseHandler{
+				router:           r,
+				config:           mockConfig,
+				warehouseService: mockWarehouseSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestGetDrainPlan_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockParam          string
+		queries            string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "success - with target warehouse",
+			mockParam:          uuid.NewString(),
+			queries:            "?to_warehouse_id=" + uuid.NewString(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid warehouse ID",
+			mockParam:          "invalid-id",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - invalid target warehouse ID",
+			mockParam:          uuid.NewString(),
+			queries:            "?to_warehouse_id=invalid-id",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle get drain plan",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockWarehouseSvc := &mocks.WarehouseService{}
+			mockWarehouseSvc.
+				On("GetDrainPlan", mock.Anything, mock.Anything).
+				Return(payload.DrainPlan{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/warehouses/"+scenario.mockParam+"/drain"+scenario.queries, nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &warehouseHandler{
+				router:           r,
+				config:           mockConfig,
+				warehouseService: mockWarehouseSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestExecuteDrainTransfers_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockParam          string
+		mockRequest        string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockParam:          uuid.NewString(),
+			mockRequest:        `{}`,
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "success - with target warehouse",
+			mockParam:          uuid.NewString(),
+			mockRequest:        fmt.Sprintf(`{"to_warehouse_id": "%s"}`, uuid.NewString()),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid warehouse ID",
+			mockParam:          "invalid-id",
+			mockRequest:        `{}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - invalid request body",
+			mockParam:          uuid.NewString(),
+			mockRequest:        `{"to_warehouse_id": "invalid-id"}`,
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle execute drain transfers",
+			mockParam:          uuid.NewString(),
+			mockRequest:        `{}`,
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockWarehouseSvc := &mocks.WarehouseService{}
+			mockWarehouseSvc.
+				On("ExecuteDrainTransfers", mock.Anything, mock.Anything).
+				Return([]payload.DrainTransfer{}, scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/warehouses/"+scenario.mockParam+"/drain/transfers", strings.NewReader(scenario.mockRequest))
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+			ctx.Request.Header.Set("Content-Type", "application/json")
+
+			h := &warehouseHandler{
+				router:           r,
+				config:           mockConfig,
+				warehouseService: mockWarehouseSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
+
+func TestCompleteDrain_ShouldReturnExpectedStatusCode(t *testing.T) {
+	testScenarios := []struct {
+		testName           string
+		mockParam          string
+		mockError          error
+		statusCodeExpected int
+	}{
+		{
+			testName:           "success",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusOK,
+		},
+		{
+			testName:           "failed - invalid warehouse ID",
+			mockParam:          "invalid-id",
+			statusCodeExpected: http.StatusBadRequest,
+		},
+		{
+			testName:           "failed - error handle complete drain",
+			mockParam:          uuid.NewString(),
+			statusCodeExpected: http.StatusInternalServerError,
+			mockError:          errors.New("something went wrong"),
+		},
+	}
+
+	for _, scenario := range testScenarios {
+		t.Run(scenario.testName, func(t *testing.T) {
+			// Given
+			r := pkg.GinTest()
+			mockConfig := &config.Config{
+				Token: config.Token{
+					JWTSecret: []byte("secret"),
+					JWTStatic: "static-token",
+				},
+			}
+
+			mockWarehouseSvc := &mocks.WarehouseService{}
+			mockWarehouseSvc.
+				On("CompleteDrain", mock.Anything, mock.Anything).
+				Return(scenario.mockError)
+
+			rr := httptest.NewRecorder()
+			ctx := pkg.GetTestGinContext(rr)
+			ctx.Request = httptest.NewRequest(http.MethodPatch, "/warehouses/"+scenario.mockParam+"/drain/complete", nil)
+			ctx.Request.Header.Set("Authorization", "Bearer "+mockConfig.Token.JWTStatic)
+
+			h := &warehouseHandler{
+				router:           r,
+				config:           mockConfig,
+				warehouseService: mockWarehouseSvc,
+			}
+			h.RegisterRoutes(r.Group(""))
+
+			// When
+			r.ServeHTTP(rr, ctx.Request)
+
+			// Then
+			assert.Equal(t, scenario.statusCodeExpected, rr.Code)
+		})
+	}
+}
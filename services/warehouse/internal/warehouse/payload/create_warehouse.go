@@ -0,0 +1,9 @@
+package payload
+
+type CreateWarehouseReq struct {
+	Name      string   `json:"name" binding:"required,max=255"`
+	Address   string   `json:"address" binding:"required"`
+	Status    string   `json:"status" binding:"omitempty,oneof=active inactive"`
+	Latitude  *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,min=-180,max=180"`
+}
@@ -0,0 +1,37 @@
+package payload
+
+import "github.com/google/uuid"
+
+// DrainWarehouseReq selects where the free stock of a draining warehouse goes.
+// Without ToWarehouseID each product goes to the active warehouse already
+// holding the most of it.
+type DrainWarehouseReq struct {
+	ID            uuid.UUID `json:"-" form:"-"`
+	ToWarehouseID string    `json:"to_warehouse_id" form:"to_warehouse_id" binding:"omitempty,uuid"`
+}
+
+type DrainPlan struct {
+	WarehouseID  uuid.UUID          `json:"warehouse_id"`
+	Status       string             `json:"status"`
+	Reservations []DrainReservation `json:"reservations"`
+	Transfers    []DrainTransfer    `json:"transfers"`
+	// Ready is set once no stock or reservations are left and the drain can be completed.
+	Ready bool `json:"ready"`
+}
+
+// DrainReservation is an outstanding reservation that has to be committed or
+// rolled back before the warehouse can be deactivated.
+type DrainReservation struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Reserved  int       `json:"reserved"`
+}
+
+// DrainTransfer moves the unreserved stock of a product out of the warehouse.
+// ToWarehouseID is nil when no destination could be proposed. StockTransferID
+// is only set once the transfer has been executed.
+type DrainTransfer struct {
+	ProductID       uuid.UUID  `json:"product_id"`
+	ToWarehouseID   *uuid.UUID `json:"to_warehouse_id"`
+	Quantity        int        `json:"quantity"`
+	StockTransferID *uuid.UUID `json:"stock_transfer_id,omitempty"`
+}
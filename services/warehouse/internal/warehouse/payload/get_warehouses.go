@@ -0,0 +1,26 @@
+package payload
+
+const (
+	DefaultWarehousePageSize = 20
+)
+
+// GetWarehousesReq filters the warehouse list. Archived warehouses are only
+// returned when asked for explicitly through StatusIN.
+type GetWarehousesReq struct {
+	StatusIN []string `form:"status_in" binding:"omitempty,dive,oneof=active draining inactive archived"`
+	Name     string   `form:"name" binding:"omitempty"`
+	Page     int      `form:"page" binding:"omitempty,min=1"`
+	PageSize int      `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// Pagination returns the requested page and page size with defaults applied.
+func (r GetWarehousesReq) Pagination() (page, pageSize int) {
+	page, pageSize = r.Page, r.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = DefaultWarehousePageSize
+	}
+	return page, pageSize
+}
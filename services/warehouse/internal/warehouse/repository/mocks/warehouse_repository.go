@@ -11,6 +11,8 @@ import (
 
 	model "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
 
+	payload "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/payload"
+
 	repository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
 )
 
@@ -65,29 +67,122 @@ func (_m *WarehouseRepository) GetWarehouseByID(ctx context.Context, id string)
 	return r0, r1
 }
 
-// GetWarehouses provides a mock function with given fields: ctx
-func (_m *WarehouseRepository) GetWarehouses(ctx context.Context) ([]model.Warehouse, error) {
-	ret := _m.Called(ctx)
+// GetWarehouses provides a mock function with given fields: ctx, req
+func (_m *WarehouseRepository) GetWarehouses(ctx context.Context, req payload.GetWarehousesReq) ([]model.Warehouse, int64, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetWarehouses")
 	}
 
+	var r0 []model.Warehouse
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetWarehousesReq) ([]model.Warehouse, int64, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetWarehousesReq) []model.Warehouse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Warehouse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetWarehousesReq) int64); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, payload.GetWarehousesReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetWarehousesByIDs provides a mock function with given fields: ctx, ids
+func (_m *WarehouseRepository) GetWarehousesByIDs(ctx context.Context, ids []string) ([]model.Warehouse, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWarehousesByIDs")
+	}
+
 	var r0 []model.Warehouse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]model.Warehouse, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]model.Warehouse, error)); ok {
+		return rf(ctx, ids)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []model.Warehouse); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []model.Warehouse); ok {
+		r0 = rf(ctx, ids)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Warehouse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasInboundTransfers provides a mock function with given fields: ctx, warehouseID
+func (_m *WarehouseRepository) HasInboundTransfers(ctx context.Context, warehouseID string) (bool, error) {
+	ret := _m.Called(ctx, warehouseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasInboundTransfers")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, warehouseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, warehouseID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, warehouseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasStockOnHand provides a mock function with given fields: ctx, warehouseID
+func (_m *WarehouseRepository) HasStockOnHand(ctx context.Context, warehouseID string) (bool, error) {
+	ret := _m.Called(ctx, warehouseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasStockOnHand")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, warehouseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, warehouseID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, warehouseID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -113,6 +208,46 @@ func (_m *WarehouseRepository) UpdateWarehouse(ctx context.Context, warehouse *m
 	return r0
 }
 
+// WithLockForShare provides a mock function with no fields
+func (_m *WarehouseRepository) WithLockForShare() repository.WarehouseRepository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithLockForShare")
+	}
+
+	var r0 repository.WarehouseRepository
+	if rf, ok := ret.Get(0).(func() repository.WarehouseRepository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.WarehouseRepository)
+		}
+	}
+
+	return r0
+}
+
+// WithLockForUpdate provides a mock function with no fields
+func (_m *WarehouseRepository) WithLockForUpdate() repository.WarehouseRepository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithLockForUpdate")
+	}
+
+	var r0 repository.WarehouseRepository
+	if rf, ok := ret.Get(0).(func() repository.WarehouseRepository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.WarehouseRepository)
+		}
+	}
+
+	return r0
+}
+
 // WithReturning provides a mock function with no fields
 func (_m *WarehouseRepository) WithReturning() repository.WarehouseRepository {
 	ret := _m.Called()
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/payload"
 	"go.opentelemetry.io/otel/codes"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -15,10 +17,15 @@ import (
 type WarehouseRepository interface {
 	WithTX(tx *gorm.DB) WarehouseRepository
 	WithReturning() WarehouseRepository
+	WithLockForUpdate() WarehouseRepository
+	WithLockForShare() WarehouseRepository
 	CreateWarehouse(ctx context.Context, warehouse *model.Warehouse) error
-	GetWarehouses(ctx context.Context) ([]model.Warehouse, error)
+	GetWarehouses(ctx context.Context, req payload.GetWarehousesReq) ([]model.Warehouse, int64, error)
 	GetWarehouseByID(ctx context.Context, id string) (model.Warehouse, error)
+	GetWarehousesByIDs(ctx context.Context, ids []string) ([]model.Warehouse, error)
 	UpdateWarehouse(ctx context.Context, warehouse *model.Warehouse) error
+	HasStockOnHand(ctx context.Context, warehouseID string) (bool, error)
+	HasInboundTransfers(ctx context.Context, warehouseID string) (bool, error)
 }
 
 type warehouseRepository struct {
@@ -42,6 +49,18 @@ func (r *warehouseRepository) WithReturning() WarehouseRepository {
 	}
 }
 
+func (r *warehouseRepository) WithLockForUpdate() WarehouseRepository {
+	return &warehouseRepository{
+		db: r.db.Clauses(clause.Locking{Strength: "UPDATE"}),
+	}
+}
+
+func (r *warehouseRepository) WithLockForShare() WarehouseRepository {
+	return &warehouseRepository{
+		db: r.db.Clauses(clause.Locking{Strength: "SHARE"}),
+	}
+}
+
 func (r *warehouseRepository) CreateWarehouse(ctx context.Context, warehouse *model.Warehouse) error {
 	ctx, span := observ.GetTracer().Start(ctx, "warehouseRepository.CreateWarehouse")
 	defer span.End()
@@ -53,16 +72,35 @@ func (r *warehouseRepository) CreateWarehouse(ctx context.Context, warehouse *mo
 	return nil
 }
 
-func (r *warehouseRepository) GetWarehouses(ctx context.Context) ([]model.Warehouse, error) {
+func (r *warehouseRepository) GetWarehouses(ctx context.Context, req payload.GetWarehousesReq) ([]model.Warehouse, int64, error) {
 	ctx, span := observ.GetTracer().Start(ctx, "warehouseRepository.GetWarehouses")
 	defer span.End()
 
+	stmt := r.db.WithContext(ctx).Model(&model.Warehouse{})
+	if len(req.StatusIN) > 0 {
+		stmt = stmt.Where("status IN ?", req.StatusIN)
+	}
+
+	if req.Name != "" {
+		stmt = stmt.Where("name ILIKE ?", "%"+req.Name+"%")
+	}
+
+	var total int64
+	if err := stmt.Count(&total).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, 0, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to count warehouses")
+	}
+
+	if req.PageSize > 0 {
+		stmt = stmt.Limit(req.PageSize).Offset((req.Page - 1) * req.PageSize)
+	}
+
 	var warehouses []model.Warehouse
-	if err := r.db.WithContext(ctx).Find(&warehouses).Error; err != nil {
+	if err := stmt.Order("created_at DESC").Find(&warehouses).Error; err != nil {
 		span.SetStatus(codes.Error, err.Error())
-		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get warehouses")
+		return nil, 0, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get warehouses")
 	}
-	return warehouses, nil
+	return warehouses, total, nil
 }
 
 func (r *warehouseRepository) GetWarehouseByID(ctx context.Context, id string) (model.Warehouse, error) {
@@ -80,6 +118,18 @@ func (r *warehouseRepository) GetWarehouseByID(ctx context.Context, id string) (
 	return warehouse, nil
 }
 
+func (r *warehouseRepository) GetWarehousesByIDs(ctx context.Context, ids []string) ([]model.Warehouse, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehouseRepository.GetWarehousesByIDs")
+	defer span.End()
+
+	var warehouses []model.Warehouse
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&warehouses).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to get warehouses by IDs")
+	}
+	return warehouses, nil
+}
+
 func (r *warehouseRepository) UpdateWarehouse(ctx context.Context, warehouse *model.Warehouse) error {
 	ctx, span := observ.GetTracer().Start(ctx, "warehouseRepository.UpdateWarehouse")
 	defer span.End()
@@ -90,3 +140,39 @@ func (r *warehouseRepository) UpdateWarehouse(ctx context.Context, warehouse *mo
 	}
 	return nil
 }
+
+func (r *warehouseRepository) HasStockOnHand(ctx context.Context, warehouseID string) (bool, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehouseRepository.HasStockOnHand")
+	defer span.End()
+
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.WarehouseStock{}).
+		Where("warehouse_id = ? AND (quantity > 0 OR reserved > 0)", warehouseID).
+		Count(&count).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return false, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to check warehouse stock")
+	}
+	return count > 0, nil
+}
+
+// HasInboundTransfers reports whether stock is still on its way to the
+// warehouse, dispatched but not fully received.
+func (r *warehouseRepository) HasInboundTransfers(ctx context.Context, warehouseID string) (bool, error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehouseRepository.HasInboundTransfers")
+	defer span.End()
+
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.StockTransfer{}).
+		Where("to_warehouse_id = ? AND status IN ?", warehouseID, []string{
+			constant.StockTransferStatusDispatched,
+			constant.StockTransferStatusInTransit,
+			constant.StockTransferStatusPartiallyReceived,
+		}).
+		Count(&count).Error; err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return false, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to check inbound transfers")
+	}
+	return count > 0, nil
+}
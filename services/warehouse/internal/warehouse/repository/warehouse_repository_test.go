@@ -10,6 +10,7 @@ import (
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/payload"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -42,12 +43,15 @@ func TestCreateWarehouse(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, data model.Warehouse) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`INSERT INTO "warehouses" ("name","address","status","created_at","updated_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`,
+							`INSERT INTO "warehouses" ("name","address","status","latitude","longitude","archived_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`,
 						),
 					).WithArgs(
 						data.Name,
 						data.Address,
 						data.Status,
+						data.Latitude,
+						data.Longitude,
+						data.ArchivedAt,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
 					).WillReturnRows(
@@ -68,12 +72,15 @@ func TestCreateWarehouse(t *testing.T) {
 				Setup: func(mockDB sqlmock.Sqlmock, data model.Warehouse) {
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(
-							`INSERT INTO "warehouses" ("name","address","status","created_at","updated_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`,
+							`INSERT INTO "warehouses" ("name","address","status","latitude","longitude","archived_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`,
 						),
 					).WithArgs(
 						data.Name,
 						data.Address,
 						data.Status,
+						data.Latitude,
+						data.Longitude,
+						data.ArchivedAt,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
 					).WillReturnError(
@@ -108,42 +115,104 @@ func TestGetWarehouses(t *testing.T) {
 	mockDb, err := pkg.SetupMockDB()
 
 	type sqlMock struct {
-		Setup func(mockDB sqlmock.Sqlmock, data []model.Warehouse)
+		Setup func(mockDB sqlmock.Sqlmock, req payload.GetWarehousesReq, data []model.Warehouse)
 	}
 
 	if err != nil {
 		t.Errorf("Failed to open mock sql db, got error: %v", err)
 	}
 	tests := []struct {
-		name    string
-		data    []model.Warehouse
-		sqlMock sqlMock
-		wantErr bool
+		name      string
+		req       payload.GetWarehousesReq
+		data      []model.Warehouse
+		sqlMock   sqlMock
+		wantTotal int64
+		wantErr   bool
 	}{
 		{
 			name: "success",
+			req: payload.GetWarehousesReq{
+				StatusIN: []string{constant.WarehouseStatusActive},
+				Page:     2,
+				PageSize: 2,
+			},
 			data: []model.Warehouse{
 				{Name: "Test Warehouse 1", Address: "123 Warehouse St, City, Country", Status: constant.WarehouseStatusActive},
 				{Name: "Test Warehouse 2", Address: "456 Warehouse Ave, City, Country", Status: constant.WarehouseStatusActive},
 			},
 			sqlMock: sqlMock{
-				Setup: func(mockDB sqlmock.Sqlmock, data []model.Warehouse) {
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetWarehousesReq, data []model.Warehouse) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT count(*) FROM "warehouses" WHERE status IN ($1)`),
+					).WithArgs(req.StatusIN[0]).WillReturnRows(
+						sqlmock.NewRows([]string{"count"}).AddRow(5),
+					)
+
 					rows := sqlmock.NewRows([]string{"id", "name", "address", "status", "created_at", "updated_at"})
 					for _, warehouse := range data {
 						rows.AddRow(uuid.New(), warehouse.Name, warehouse.Address, warehouse.Status, time.Now(), time.Now())
 					}
 					mockDB.ExpectQuery(
-						regexp.QuoteMeta(`SELECT * FROM "warehouses"`),
-					).WillReturnRows(rows)
+						regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE status IN ($1) ORDER BY created_at DESC LIMIT $2 OFFSET $3`),
+					).WithArgs(req.StatusIN[0], req.PageSize, (req.Page-1)*req.PageSize).WillReturnRows(rows)
 				},
 			},
-			wantErr: false,
+			wantTotal: 5,
+			wantErr:   false,
+		},
+		{
+			name: "success - filter by name",
+			req: payload.GetWarehousesReq{
+				Name:     "central",
+				Page:     1,
+				PageSize: 20,
+			},
+			data: []model.Warehouse{
+				{Name: "Central Warehouse", Address: "123 Warehouse St, City, Country", Status: constant.WarehouseStatusActive},
+			},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetWarehousesReq, data []model.Warehouse) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT count(*) FROM "warehouses" WHERE name ILIKE $1`),
+					).WithArgs("%central%").WillReturnRows(
+						sqlmock.NewRows([]string{"count"}).AddRow(1),
+					)
+
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE name ILIKE $1 ORDER BY created_at DESC LIMIT $2`),
+					).WithArgs("%central%", req.PageSize).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name", "address", "status", "created_at", "updated_at"}).
+							AddRow(uuid.New(), data[0].Name, data[0].Address, data[0].Status, time.Now(), time.Now()),
+					)
+				},
+			},
+			wantTotal: 1,
+			wantErr:   false,
+		},
+		{
+			name: "error - failed to count warehouses",
+			req:  payload.GetWarehousesReq{},
+			data: []model.Warehouse{},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetWarehousesReq, data []model.Warehouse) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT count(*) FROM "warehouses"`),
+					).WillReturnError(sqlmock.ErrCancelled)
+				},
+			},
+			wantErr: true,
 		},
 		{
 			name: "error - failed to get warehouses",
+			req:  payload.GetWarehousesReq{},
 			data: []model.Warehouse{},
 			sqlMock: sqlMock{
-				Setup: func(mockDB sqlmock.Sqlmock, data []model.Warehouse) {
+				Setup: func(mockDB sqlmock.Sqlmock, req payload.GetWarehousesReq, data []model.Warehouse) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT count(*) FROM "warehouses"`),
+					).WillReturnRows(
+						sqlmock.NewRows([]string{"count"}).AddRow(1),
+					)
 					mockDB.ExpectQuery(
 						regexp.QuoteMeta(`SELECT * FROM "warehouses"`),
 					).WillReturnError(sqlmock.ErrCancelled)
@@ -156,11 +225,11 @@ func TestGetWarehouses(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			tt.sqlMock.Setup(mockDb.Mock, tt.data)
+			tt.sqlMock.Setup(mockDb.Mock, tt.req, tt.data)
 
 			repo := NewWarehouseRepository(mockDb.Db)
 
-			result, err := repo.GetWarehouses(context.Background())
+			result, total, err := repo.GetWarehouses(context.Background(), tt.req)
 
 			if tt.wantErr {
 				assert.NotNil(t, err)
@@ -168,10 +237,12 @@ func TestGetWarehouses(t *testing.T) {
 			}
 
 			assert.Nil(t, err)
+			assert.Equal(t, tt.wantTotal, total)
 			assert.Equal(t, len(tt.data), len(result))
 			for i, warehouse := range result {
 				assert.Equal(t, tt.data[i].Name, warehouse.Name)
 			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -249,6 +320,75 @@ func TestGetWarehouseByID(t *testing.T) {
 	}
 }
 
+func TestGetWarehousesByIDs(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, ids []string)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		ids     []string
+		sqlMock sqlMock
+		wantErr bool
+	}{
+		{
+			name: "success",
+			ids:  []string{uuid.New().String(), uuid.New().String()},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, ids []string) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE id IN ($1,$2)`),
+					).WithArgs(ids[0], ids[1]).WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name", "latitude", "longitude"}).
+							AddRow(ids[0], "Warehouse A", -6.2, 106.8).
+							AddRow(ids[1], "Warehouse B", nil, nil),
+					)
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - failed to get warehouses",
+			ids:  []string{uuid.New().String()},
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, ids []string) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT * FROM "warehouses" WHERE id IN ($1)`),
+					).WithArgs(ids[0]).WillReturnError(
+						sqlmock.ErrCancelled,
+					)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.ids)
+
+			repo := NewWarehouseRepository(mockDb.Db)
+
+			result, err := repo.GetWarehousesByIDs(context.Background(), tt.ids)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Len(t, result, len(tt.ids))
+			assert.NotNil(t, result[0].Latitude)
+			assert.Nil(t, result[1].Latitude)
+		})
+	}
+}
+
 func TestUpdateWarehouse(t *testing.T) {
 	mockDb, err := pkg.SetupMockDB()
 
@@ -276,11 +416,14 @@ func TestUpdateWarehouse(t *testing.T) {
 			sqlMock: sqlMock{
 				Setup: func(mockDB sqlmock.Sqlmock, data model.Warehouse) {
 					mockDB.ExpectExec(
-						regexp.QuoteMeta(`UPDATE "warehouses" SET "name"=$1,"address"=$2,"status"=$3,"created_at"=$4,"updated_at"=$5 WHERE "id" = $6`),
+						regexp.QuoteMeta(`UPDATE "warehouses" SET "name"=$1,"address"=$2,"status"=$3,"latitude"=$4,"longitude"=$5,"archived_at"=$6,"created_at"=$7,"updated_at"=$8 WHERE "id" = $9`),
 					).WithArgs(
 						data.Name,
 						data.Address,
 						data.Status,
+						data.Latitude,
+						data.Longitude,
+						data.ArchivedAt,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
 						data.ID,
@@ -302,11 +445,14 @@ func TestUpdateWarehouse(t *testing.T) {
 			sqlMock: sqlMock{
 				Setup: func(mockDB sqlmock.Sqlmock, data model.Warehouse) {
 					mockDB.ExpectExec(
-						regexp.QuoteMeta(`UPDATE "warehouses" SET "name"=$1,"address"=$2,"status"=$3,"created_at"=$4,"updated_at"=$5 WHERE "id" = $6`),
+						regexp.QuoteMeta(`UPDATE "warehouses" SET "name"=$1,"address"=$2,"status"=$3,"latitude"=$4,"longitude"=$5,"archived_at"=$6,"created_at"=$7,"updated_at"=$8 WHERE "id" = $9`),
 					).WithArgs(
 						data.Name,
 						data.Address,
 						data.Status,
+						data.Latitude,
+						data.Longitude,
+						data.ArchivedAt,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
 						data.ID,
@@ -336,3 +482,161 @@ func TestUpdateWarehouse(t *testing.T) {
 		})
 	}
 }
+
+func TestHasStockOnHand(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, warehouseID string)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	tests := []struct {
+		name        string
+		warehouseID string
+		sqlMock     sqlMock
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "success - warehouse holds stock",
+			warehouseID: uuid.New().String(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, warehouseID string) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT count(*) FROM "warehouse_stocks" WHERE warehouse_id = $1 AND (quantity > 0 OR reserved > 0)`),
+					).WithArgs(warehouseID).WillReturnRows(
+						sqlmock.NewRows([]string{"count"}).AddRow(2),
+					)
+				},
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name:        "success - warehouse is empty",
+			warehouseID: uuid.New().String(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, warehouseID string) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT count(*) FROM "warehouse_stocks" WHERE warehouse_id = $1 AND (quantity > 0 OR reserved > 0)`),
+					).WithArgs(warehouseID).WillReturnRows(
+						sqlmock.NewRows([]string{"count"}).AddRow(0),
+					)
+				},
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name:        "error - failed to check warehouse stock",
+			warehouseID: uuid.New().String(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, warehouseID string) {
+					mockDB.ExpectQuery(
+						regexp.QuoteMeta(`SELECT count(*) FROM "warehouse_stocks" WHERE warehouse_id = $1 AND (quantity > 0 OR reserved > 0)`),
+					).WithArgs(warehouseID).WillReturnError(sqlmock.ErrCancelled)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.warehouseID)
+
+			repo := NewWarehouseRepository(mockDb.Db)
+
+			result, err := repo.HasStockOnHand(context.Background(), tt.warehouseID)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestHasInboundTransfers(t *testing.T) {
+	mockDb, err := pkg.SetupMockDB()
+
+	type sqlMock struct {
+		Setup func(mockDB sqlmock.Sqlmock, warehouseID string)
+	}
+
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+	query := `SELECT count(*) FROM "stock_transfers" WHERE to_warehouse_id = $1 AND status IN ($2,$3,$4)`
+	tests := []struct {
+		name        string
+		warehouseID string
+		sqlMock     sqlMock
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "success - transfers on the way",
+			warehouseID: uuid.New().String(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, warehouseID string) {
+					mockDB.ExpectQuery(regexp.QuoteMeta(query)).
+						WithArgs(warehouseID, "dispatched", "in_transit", "partially_received").
+						WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				},
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name:        "success - nothing on the way",
+			warehouseID: uuid.New().String(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, warehouseID string) {
+					mockDB.ExpectQuery(regexp.QuoteMeta(query)).
+						WithArgs(warehouseID, "dispatched", "in_transit", "partially_received").
+						WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				},
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name:        "error - failed to check inbound transfers",
+			warehouseID: uuid.New().String(),
+			sqlMock: sqlMock{
+				Setup: func(mockDB sqlmock.Sqlmock, warehouseID string) {
+					mockDB.ExpectQuery(regexp.QuoteMeta(query)).
+						WillReturnError(sqlmock.ErrCancelled)
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.sqlMock.Setup(mockDb.Mock, tt.warehouseID)
+
+			repo := NewWarehouseRepository(mockDb.Db)
+
+			result, err := repo.HasInboundTransfers(context.Background(), tt.warehouseID)
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
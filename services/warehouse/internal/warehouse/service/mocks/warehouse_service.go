@@ -15,29 +15,121 @@ type WarehouseService struct {
 	mock.Mock
 }
 
-// GetWarehouses provides a mock function with given fields: ctx
-func (_m *WarehouseService) GetWarehouses(ctx context.Context) ([]model.Warehouse, error) {
-	ret := _m.Called(ctx)
+// ArchiveWarehouse provides a mock function with given fields: ctx, id
+func (_m *WarehouseService) ArchiveWarehouse(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetWarehouses")
+		panic("no return value specified for ArchiveWarehouse")
 	}
 
-	var r0 []model.Warehouse
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompleteDrain provides a mock function with given fields: ctx, id
+func (_m *WarehouseService) CompleteDrain(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteDrain")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateWarehouse provides a mock function with given fields: ctx, req
+func (_m *WarehouseService) CreateWarehouse(ctx context.Context, req payload.CreateWarehouseReq) (model.Warehouse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWarehouse")
+	}
+
+	var r0 model.Warehouse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.CreateWarehouseReq) (model.Warehouse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.CreateWarehouseReq) model.Warehouse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(model.Warehouse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.CreateWarehouseReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExecuteDrainTransfers provides a mock function with given fields: ctx, req
+func (_m *WarehouseService) ExecuteDrainTransfers(ctx context.Context, req payload.DrainWarehouseReq) ([]payload.DrainTransfer, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecuteDrainTransfers")
+	}
+
+	var r0 []payload.DrainTransfer
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]model.Warehouse, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, payload.DrainWarehouseReq) ([]payload.DrainTransfer, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []model.Warehouse); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, payload.DrainWarehouseReq) []payload.DrainTransfer); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.Warehouse)
+			r0 = ret.Get(0).([]payload.DrainTransfer)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, payload.DrainWarehouseReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDrainPlan provides a mock function with given fields: ctx, req
+func (_m *WarehouseService) GetDrainPlan(ctx context.Context, req payload.DrainWarehouseReq) (payload.DrainPlan, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDrainPlan")
+	}
+
+	var r0 payload.DrainPlan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.DrainWarehouseReq) (payload.DrainPlan, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.DrainWarehouseReq) payload.DrainPlan); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(payload.DrainPlan)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.DrainWarehouseReq) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -45,6 +137,89 @@ func (_m *WarehouseService) GetWarehouses(ctx context.Context) ([]model.Warehous
 	return r0, r1
 }
 
+// GetWarehouseByID provides a mock function with given fields: ctx, id
+func (_m *WarehouseService) GetWarehouseByID(ctx context.Context, id string) (model.Warehouse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWarehouseByID")
+	}
+
+	var r0 model.Warehouse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.Warehouse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.Warehouse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.Warehouse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWarehouses provides a mock function with given fields: ctx, req
+func (_m *WarehouseService) GetWarehouses(ctx context.Context, req payload.GetWarehousesReq) ([]model.Warehouse, int64, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWarehouses")
+	}
+
+	var r0 []model.Warehouse
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetWarehousesReq) ([]model.Warehouse, int64, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, payload.GetWarehousesReq) []model.Warehouse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Warehouse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, payload.GetWarehousesReq) int64); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, payload.GetWarehousesReq) error); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// StartDrain provides a mock function with given fields: ctx, id
+func (_m *WarehouseService) StartDrain(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartDrain")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateWarehouse provides a mock function with given fields: ctx, req
 func (_m *WarehouseService) UpdateWarehouse(ctx context.Context, req payload.UpdateWarehouseReq) error {
 	ret := _m.Called(ctx, req)
@@ -2,34 +2,99 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/observ"
+	stockpayload "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	stockrepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository"
+	stockservice "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/payload"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/codes"
+	"gorm.io/gorm"
 )
 
 //go:generate mockery --name=WarehouseService --case underscore
 type WarehouseService interface {
-	GetWarehouses(ctx context.Context) ([]model.Warehouse, error)
+	CreateWarehouse(ctx context.Context, req payload.CreateWarehouseReq) (model.Warehouse, error)
+	GetWarehouses(ctx context.Context, req payload.GetWarehousesReq) ([]model.Warehouse, int64, error)
+	GetWarehouseByID(ctx context.Context, id string) (model.Warehouse, error)
 	UpdateWarehouse(ctx context.Context, req payload.UpdateWarehouseReq) error
+	ArchiveWarehouse(ctx context.Context, id string) error
+	StartDrain(ctx context.Context, id string) error
+	GetDrainPlan(ctx context.Context, req payload.DrainWarehouseReq) (payload.DrainPlan, error)
+	ExecuteDrainTransfers(ctx context.Context, req payload.DrainWarehouseReq) ([]payload.DrainTransfer, error)
+	CompleteDrain(ctx context.Context, id string) error
 }
 
 type warehouseService struct {
-	config        *config.Config
-	warehouseRepo repository.WarehouseRepository
+	config          *config.Config
+	logger          *pkg.Logger
+	db              *gorm.DB
+	warehouseRepo   repository.WarehouseRepository
+	stockRepo       stockrepository.StockRepository
+	stockLotRepo    stockrepository.StockLotRepository
+	stockSerialRepo stockrepository.StockSerialRepository
+	stockService    stockservice.StockService
 }
 
-func NewWarehouseService(config *config.Config, warehouseRepo repository.WarehouseRepository) WarehouseService {
+func NewWarehouseService(
+	config *config.Config,
+	logger *pkg.Logger,
+	db *gorm.DB,
+	warehouseRepo repository.WarehouseRepository,
+	stockRepo stockrepository.StockRepository,
+	stockLotRepo stockrepository.StockLotRepository,
+	stockSerialRepo stockrepository.StockSerialRepository,
+	stockService stockservice.StockService,
+) WarehouseService {
 	return &warehouseService{
-		config:        config,
-		warehouseRepo: warehouseRepo,
+		config:          config,
+		logger:          logger,
+		db:              db,
+		warehouseRepo:   warehouseRepo,
+		stockRepo:       stockRepo,
+		stockLotRepo:    stockLotRepo,
+		stockSerialRepo: stockSerialRepo,
+		stockService:    stockService,
 	}
 }
 
-func (s *warehouseService) GetWarehouses(ctx context.Context) (result []model.Warehouse, err error) {
+func (s *warehouseService) CreateWarehouse(ctx context.Context, req payload.CreateWarehouseReq) (result model.Warehouse, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehouseService.CreateWarehouse")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	warehouse := model.Warehouse{
+		Name:      req.Name,
+		Address:   req.Address,
+		Status:    req.Status,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	}
+	if warehouse.Status == "" {
+		warehouse.Status = constant.WarehouseStatusActive
+	}
+
+	if err := s.warehouseRepo.CreateWarehouse(ctx, &warehouse); err != nil {
+		return model.Warehouse{}, err
+	}
+
+	return warehouse, nil
+}
+
+func (s *warehouseService) GetWarehouses(ctx context.Context, req payload.GetWarehousesReq) (result []model.Warehouse, total int64, err error) {
 	ctx, span := observ.GetTracer().Start(ctx, "warehouseService.GetWarehouses")
 	defer span.End()
 	defer func() {
@@ -39,14 +104,40 @@ func (s *warehouseService) GetWarehouses(ctx context.Context) (result []model.Wa
 		}
 	}()
 
-	warehouses, err := s.warehouseRepo.GetWarehouses(ctx)
+	if len(req.StatusIN) == 0 {
+		req.StatusIN = []string{constant.WarehouseStatusActive, constant.WarehouseStatusDraining, constant.WarehouseStatusInactive}
+	}
+
+	req.Page, req.PageSize = req.Pagination()
+
+	warehouses, total, err := s.warehouseRepo.GetWarehouses(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	return warehouses, total, nil
+}
+
+func (s *warehouseService) GetWarehouseByID(ctx context.Context, id string) (result model.Warehouse, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehouseService.GetWarehouseByID")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	warehouse, err := s.warehouseRepo.GetWarehouseByID(ctx, id)
+	if err != nil {
+		return model.Warehouse{}, err
 	}
 
-	return warehouses, nil
+	return warehouse, nil
 }
 
+// UpdateWarehouse switches a warehouse between active and inactive. A warehouse
+// still holding stock or reservations has to be drained before deactivating it.
 func (s *warehouseService) UpdateWarehouse(ctx context.Context, req payload.UpdateWarehouseReq) (err error) {
 	ctx, span := observ.GetTracer().Start(ctx, "warehouseService.UpdateWarehouse")
 	defer span.End()
@@ -57,16 +148,463 @@ func (s *warehouseService) UpdateWarehouse(ctx context.Context, req payload.Upda
 		}
 	}()
 
-	warehouse, err := s.warehouseRepo.GetWarehouseByID(ctx, req.ID.String())
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	warehouse, err := s.warehouseRepo.WithTX(tx).WithLockForUpdate().GetWarehouseByID(ctx, req.ID.String())
 	if err != nil {
 		return err
 	}
 
+	if warehouse.Status == constant.WarehouseStatusArchived {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse is archived")
+	}
+
+	if req.Status == constant.WarehouseStatusInactive && warehouse.Status != constant.WarehouseStatusInactive {
+		hasStock, err := s.warehouseRepo.WithTX(tx).HasStockOnHand(ctx, req.ID.String())
+		if err != nil {
+			return err
+		}
+
+		if hasStock {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse still holds stock or reservations, drain it before deactivating")
+		}
+
+		inbound, err := s.warehouseRepo.WithTX(tx).HasInboundTransfers(ctx, req.ID.String())
+		if err != nil {
+			return err
+		}
+
+		if inbound {
+			return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse still has stock transfers on the way in, receive them before deactivating")
+		}
+	}
+
 	warehouse.Status = req.Status
-	err = s.warehouseRepo.UpdateWarehouse(ctx, &warehouse)
+	if err := s.warehouseRepo.WithTX(tx).UpdateWarehouse(ctx, &warehouse); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// ArchiveWarehouse retires a warehouse for good. The warehouse row is locked
+// while checking its stock, every path that brings stock in share-locks the
+// same row first so it waits for the archive.
+func (s *warehouseService) ArchiveWarehouse(ctx context.Context, id string) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehouseService.ArchiveWarehouse")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	warehouse, err := s.warehouseRepo.WithTX(tx).WithLockForUpdate().GetWarehouseByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if warehouse.Status == constant.WarehouseStatusArchived {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse is already archived")
+	}
+
+	hasStock, err := s.warehouseRepo.WithTX(tx).HasStockOnHand(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if hasStock {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse still holds stock or reservations")
+	}
+
+	inbound, err := s.warehouseRepo.WithTX(tx).HasInboundTransfers(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if inbound {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse still has stock transfers on the way in")
+	}
+
+	now := time.Now()
+	warehouse.Status = constant.WarehouseStatusArchived
+	warehouse.ArchivedAt = &now
+	if err := s.warehouseRepo.WithTX(tx).UpdateWarehouse(ctx, &warehouse); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.logger.WithContext(ctx).Infow("Warehouse archived", "warehouse_id", warehouse.ID)
+
+	return nil
+}
+
+// StartDrain stops new reservations from being placed in an active warehouse.
+// Outstanding reservations can still be committed or rolled back.
+func (s *warehouseService) StartDrain(ctx context.Context, id string) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehouseService.StartDrain")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	warehouse, err := s.warehouseRepo.WithTX(tx).WithLockForUpdate().GetWarehouseByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	if warehouse.Status != constant.WarehouseStatusActive {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "only active warehouses can be drained")
+	}
+
+	warehouse.Status = constant.WarehouseStatusDraining
+	if err := s.warehouseRepo.WithTX(tx).UpdateWarehouse(ctx, &warehouse); err != nil {
+		return err
+	}
+
+	stocks, err := s.stockRepo.WithTX(tx).GetWarehouseStocks(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// the stock of a draining warehouse no longer counts as available
+	var productIDs []string
+	for _, stock := range stocks {
+		productIDs = append(productIDs, stock.ProductID.String())
+	}
+	alerts, err := s.settleStockChanges(ctx, tx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.logger.WithContext(ctx).Infow("Warehouse drain started", "warehouse_id", warehouse.ID)
+
+	s.stockService.PublishStockChanges(ctx, productIDs, alerts)
+
 	return nil
 }
+
+func (s *warehouseService) GetDrainPlan(ctx context.Context, req payload.DrainWarehouseReq) (result payload.DrainPlan, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehouseService.GetDrainPlan")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	warehouse, err := s.warehouseRepo.GetWarehouseByID(ctx, req.ID.String())
+	if err != nil {
+		return payload.DrainPlan{}, err
+	}
+
+	if warehouse.Status != constant.WarehouseStatusDraining {
+		return payload.DrainPlan{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse is not draining")
+	}
+
+	stocks, err := s.stockRepo.GetWarehouseStocks(ctx, req.ID.String())
+	if err != nil {
+		return payload.DrainPlan{}, err
+	}
+
+	return s.drainPlan(ctx, nil, warehouse, stocks, req.ToWarehouseID)
+}
+
+// ExecuteDrainTransfers moves the unreserved stock of a draining warehouse out
+// as proposed by its drain plan and records each move as a received stock
+// transfer. Reserved stock stays until it is committed or rolled back.
+func (s *warehouseService) ExecuteDrainTransfers(ctx context.Context, req payload.DrainWarehouseReq) (result []payload.DrainTransfer, err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehouseService.ExecuteDrainTransfers")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	warehouse, err := s.warehouseRepo.WithTX(tx).WithLockForUpdate().GetWarehouseByID(ctx, req.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if warehouse.Status != constant.WarehouseStatusDraining {
+		return nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse is not draining")
+	}
+
+	stocks, err := s.stockRepo.WithTX(tx).WithLockForUpdate().GetWarehouseStocks(ctx, req.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.drainPlan(ctx, tx, warehouse, stocks, req.ToWarehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	planProductIDs := make([]string, 0, len(plan.Transfers))
+	for _, transfer := range plan.Transfers {
+		planProductIDs = append(planProductIDs, transfer.ProductID.String())
+	}
+
+	serialized := make(map[uuid.UUID]bool)
+	if len(planProductIDs) > 0 {
+		serializedProducts, err := s.stockSerialRepo.WithTX(tx).GetSerializedProducts(ctx, planProductIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range serializedProducts {
+			serialized[product.ProductID] = true
+		}
+	}
+
+	var productIDs []string
+	destinations := make(map[uuid.UUID]bool)
+	for i, transfer := range plan.Transfers {
+		if transfer.ToWarehouseID == nil {
+			return nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "no destination warehouse for product "+transfer.ProductID.String()+", set to_warehouse_id")
+		}
+
+		// the destination is share-locked like any other receiving warehouse,
+		// so it cannot be archived before the drain commits
+		if !destinations[*transfer.ToWarehouseID] {
+			destination, err := s.warehouseRepo.WithTX(tx).WithLockForShare().GetWarehouseByID(ctx, transfer.ToWarehouseID.String())
+			if err != nil {
+				return nil, err
+			}
+			if destination.Status != constant.WarehouseStatusActive {
+				return nil, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "destination warehouse "+destination.ID.String()+" is not active")
+			}
+			destinations[*transfer.ToWarehouseID] = true
+		}
+
+		err = s.stockRepo.WithTX(tx).IncreaseStockQty(ctx, transfer.ProductID.String(), transfer.ToWarehouseID.String(), transfer.Quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		// everything unreserved leaves, expired lots too, and they keep their
+		// expiry date so the destination never reserves them
+		_, err = s.stockLotRepo.WithTX(tx).MoveStockLots(ctx, transfer.ProductID.String(), warehouse.ID.String(), transfer.ToWarehouseID.String(), transfer.Quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.stockRepo.WithTX(tx).DecreaseStockQty(ctx, transfer.ProductID.String(), warehouse.ID.String(), transfer.Quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		if serialized[transfer.ProductID] {
+			err = s.stockSerialRepo.WithTX(tx).MoveStockSerials(ctx, transfer.ProductID.String(), warehouse.ID.String(), transfer.ToWarehouseID.String(), transfer.Quantity)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// the stock moves instantly, so the transfer is dispatched and received at once
+		now := time.Now()
+		notes := "warehouse drain"
+		stockTransfer := model.StockTransfer{
+			FromWarehouseID:  warehouse.ID,
+			ToWarehouseID:    *transfer.ToWarehouseID,
+			ProductID:        transfer.ProductID,
+			Quantity:         transfer.Quantity,
+			Status:           constant.StockTransferStatusReceived,
+			ReceivedQuantity: transfer.Quantity,
+			Notes:            &notes,
+			DispatchedAt:     &now,
+			ReceivedAt:       &now,
+		}
+		if err := s.stockRepo.WithTX(tx).CreateStockTransfer(ctx, &stockTransfer); err != nil {
+			return nil, err
+		}
+		plan.Transfers[i].StockTransferID = &stockTransfer.ID
+		productIDs = append(productIDs, transfer.ProductID.String())
+	}
+
+	alerts, err := s.settleStockChanges(ctx, tx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.logger.WithContext(ctx).Infow("Warehouse drain transfers executed",
+		"warehouse_id", warehouse.ID,
+		"transfers", len(plan.Transfers),
+	)
+
+	s.stockService.PublishStockChanges(ctx, productIDs, alerts)
+
+	return plan.Transfers, nil
+}
+
+// CompleteDrain deactivates a draining warehouse once nothing is left in it
+// and nothing is on its way to it.
+func (s *warehouseService) CompleteDrain(ctx context.Context, id string) (err error) {
+	ctx, span := observ.GetTracer().Start(ctx, "warehouseService.CompleteDrain")
+	defer span.End()
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			span.RecordError(err)
+		}
+	}()
+
+	tx := s.db.Begin()
+	defer tx.Rollback()
+
+	warehouse, err := s.warehouseRepo.WithTX(tx).WithLockForUpdate().GetWarehouseByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if warehouse.Status != constant.WarehouseStatusDraining {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse is not draining")
+	}
+
+	hasStock, err := s.warehouseRepo.WithTX(tx).HasStockOnHand(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if hasStock {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse still holds stock or reservations")
+	}
+
+	inbound, err := s.warehouseRepo.WithTX(tx).HasInboundTransfers(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if inbound {
+		return apperr.NewWithCode(apperr.CodeHTTPBadRequest, "warehouse still has stock transfers on the way in")
+	}
+
+	warehouse.Status = constant.WarehouseStatusInactive
+	if err := s.warehouseRepo.WithTX(tx).UpdateWarehouse(ctx, &warehouse); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return apperr.WrapWithCode(err, apperr.CodeHTTPInternalServerError, "failed to commit transaction")
+	}
+
+	s.logger.WithContext(ctx).Infow("Warehouse drain completed", "warehouse_id", warehouse.ID)
+
+	return nil
+}
+
+// drainPlan lists the reservations left in the warehouse and the transfers
+// that empty it. Without a target warehouse each product is sent to the active
+// warehouse already holding the most of it.
+func (s *warehouseService) drainPlan(ctx context.Context, tx *gorm.DB, warehouse model.Warehouse, stocks []model.WarehouseStock, toWarehouseID string) (payload.DrainPlan, error) {
+	plan := payload.DrainPlan{
+		WarehouseID:  warehouse.ID,
+		Status:       warehouse.Status,
+		Reservations: []payload.DrainReservation{},
+		Transfers:    []payload.DrainTransfer{},
+	}
+
+	var productIDs []string
+	for _, stock := range stocks {
+		if stock.Reserved > 0 {
+			plan.Reservations = append(plan.Reservations, payload.DrainReservation{
+				ProductID: stock.ProductID,
+				Reserved:  stock.Reserved,
+			})
+		}
+
+		if free := stock.Quantity - stock.Reserved; free > 0 {
+			plan.Transfers = append(plan.Transfers, payload.DrainTransfer{
+				ProductID: stock.ProductID,
+				Quantity:  free,
+			})
+			productIDs = append(productIDs, stock.ProductID.String())
+		}
+	}
+	plan.Ready = len(plan.Reservations) == 0 && len(plan.Transfers) == 0
+
+	if len(plan.Transfers) == 0 {
+		return plan, nil
+	}
+
+	if toWarehouseID != "" {
+		target, err := s.warehouseRepo.WithTX(tx).GetWarehouseByID(ctx, toWarehouseID)
+		if err != nil {
+			return payload.DrainPlan{}, err
+		}
+
+		if target.ID == warehouse.ID || target.Status != constant.WarehouseStatusActive {
+			return payload.DrainPlan{}, apperr.NewWithCode(apperr.CodeHTTPBadRequest, "stock can only be drained into another active warehouse")
+		}
+
+		for i := range plan.Transfers {
+			plan.Transfers[i].ToWarehouseID = &target.ID
+		}
+		return plan, nil
+	}
+
+	// only active warehouses are returned, so the draining one is never proposed
+	candidates, err := s.stockRepo.WithTX(tx).GetStocks(ctx, stockpayload.GetStocksReq{
+		ProductIDIN: productIDs,
+	})
+	if err != nil {
+		return payload.DrainPlan{}, err
+	}
+
+	best := make(map[uuid.UUID]model.WarehouseStock)
+	for _, candidate := range candidates {
+		current, ok := best[candidate.ProductID]
+		if !ok || candidate.Quantity > current.Quantity ||
+			(candidate.Quantity == current.Quantity && candidate.WarehouseID.String() < current.WarehouseID.String()) {
+			best[candidate.ProductID] = candidate
+		}
+	}
+
+	for i, transfer := range plan.Transfers {
+		if candidate, ok := best[transfer.ProductID]; ok {
+			plan.Transfers[i].ToWarehouseID = &candidate.WarehouseID
+		}
+	}
+
+	return plan, nil
+}
+
+// settleStockChanges allocates backorders and evaluates the alerts of the
+// products moved by the warehouse change inside its transaction.
+func (s *warehouseService) settleStockChanges(ctx context.Context, tx *gorm.DB, productIDs []string) ([]model.StockAlert, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+	return s.stockService.SettleStockChanges(ctx, tx, productIDs)
+}
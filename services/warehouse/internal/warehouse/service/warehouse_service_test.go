@@ -4,8 +4,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	stockRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository/mocks"
+	stockSvcMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service/mocks"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/payload"
 	warehouseRepoMock "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository/mocks"
 	"github.com/google/uuid"
@@ -27,7 +32,11 @@ func TestGetWarehouses_ShouldSuccess(t *testing.T) {
 		{
 			name: "success",
 			setup: func(m dependencyMocks) {
-				m.warehouseRepo.On("GetWarehouses", mock.Anything).
+				m.warehouseRepo.On("GetWarehouses", mock.Anything, payload.GetWarehousesReq{
+					StatusIN: []string{constant.WarehouseStatusActive, constant.WarehouseStatusDraining, constant.WarehouseStatusInactive},
+					Page:     1,
+					PageSize: payload.DefaultWarehousePageSize,
+				}).
 					Return([]model.Warehouse{
 						{
 							ID:   uuid.New(),
@@ -37,7 +46,7 @@ func TestGetWarehouses_ShouldSuccess(t *testing.T) {
 							ID:   uuid.New(),
 							Name: "Warehouse Two",
 						},
-					}, nil)
+					}, int64(2), nil)
 			},
 		},
 	}
@@ -54,11 +63,12 @@ func TestGetWarehouses_ShouldSuccess(t *testing.T) {
 			tt.setup(mocks)
 
 			// When
-			resp, err := warehouseSvc.GetWarehouses(context.Background())
+			resp, total, err := warehouseSvc.GetWarehouses(context.Background(), payload.GetWarehousesReq{})
 
 			// Then
 			assert.NoError(t, err)
 			assert.Equal(t, 2, len(resp))
+			assert.Equal(t, int64(2), total)
 			mocks.warehouseRepo.AssertExpectations(t)
 		})
 	}
@@ -78,8 +88,8 @@ func TestGetWarehouses_ShouldReturnError(t *testing.T) {
 		{
 			name: "error - failed to get warehouses",
 			setup: func(m dependencyMocks) {
-				m.warehouseRepo.On("GetWarehouses", mock.Anything).
-					Return(nil, assert.AnError)
+				m.warehouseRepo.On("GetWarehouses", mock.Anything, mock.Anything).
+					Return(nil, int64(0), assert.AnError)
 			},
 		},
 	}
@@ -96,7 +106,7 @@ func TestGetWarehouses_ShouldReturnError(t *testing.T) {
 			tt.setup(mocks)
 
 			// When
-			resp, err := warehouseSvc.GetWarehouses(context.Background())
+			resp, _, err := warehouseSvc.GetWarehouses(context.Background(), payload.GetWarehousesReq{})
 
 			// Then
 			assert.Error(t, err)
@@ -108,9 +118,15 @@ func TestGetWarehouses_ShouldReturnError(t *testing.T) {
 
 func TestUpdateWarehouse_ShouldSuccess(t *testing.T) {
 	type dependencyMocks struct {
+		db            sqlmock.Sqlmock
 		warehouseRepo *warehouseRepoMock.WarehouseRepository
 	}
 
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
 	tests := []struct {
 		name  string
 		req   payload.UpdateWarehouseReq
@@ -119,20 +135,55 @@ func TestUpdateWarehouse_ShouldSuccess(t *testing.T) {
 		)
 	}{
 		{
-			name: "success",
+			name: "success - deactivate empty warehouse",
 			req: payload.UpdateWarehouseReq{
 				ID:     uuid.New(),
 				Status: constant.WarehouseStatusInactive,
 			},
 			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
 				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, mock.AnythingOfType("string")).
 					Return(model.Warehouse{
 						ID:     uuid.New(),
 						Name:   "Warehouse One",
 						Status: constant.WarehouseStatusActive,
 					}, nil)
+				m.warehouseRepo.On("HasStockOnHand", mock.Anything, mock.AnythingOfType("string")).
+					Return(false, nil)
+				m.warehouseRepo.On("HasInboundTransfers", mock.Anything, mock.AnythingOfType("string")).
+					Return(false, nil)
 				m.warehouseRepo.On("UpdateWarehouse", mock.Anything, mock.AnythingOfType("*model.Warehouse")).
 					Return(nil)
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "success - cancel drain",
+			req: payload.UpdateWarehouseReq{
+				ID:     uuid.New(),
+				Status: constant.WarehouseStatusActive,
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, mock.AnythingOfType("string")).
+					Return(model.Warehouse{
+						ID:     uuid.New(),
+						Name:   "Warehouse One",
+						Status: constant.WarehouseStatusDraining,
+					}, nil)
+				m.warehouseRepo.On("UpdateWarehouse", mock.Anything, mock.MatchedBy(func(warehouse *model.Warehouse) bool {
+					return warehouse.Status == constant.WarehouseStatusActive
+				})).
+					Return(nil)
+				m.db.ExpectCommit()
 			},
 		},
 	}
@@ -140,9 +191,11 @@ func TestUpdateWarehouse_ShouldSuccess(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Given
 			mocks := dependencyMocks{
+				db:            mockDb.Mock,
 				warehouseRepo: warehouseRepoMock.NewWarehouseRepository(t),
 			}
 			warehouseSvc := warehouseService{
+				db:            mockDb.Db,
 				warehouseRepo: mocks.warehouseRepo,
 			}
 
@@ -152,15 +205,22 @@ func TestUpdateWarehouse_ShouldSuccess(t *testing.T) {
 
 			assert.NoError(t, err)
 			mocks.warehouseRepo.AssertExpectations(t)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
 		})
 	}
 }
 
 func TestUpdateWarehouse_ShouldReturnError(t *testing.T) {
 	type dependencyMocks struct {
+		db            sqlmock.Sqlmock
 		warehouseRepo *warehouseRepoMock.WarehouseRepository
 	}
 
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
 	tests := []struct {
 		name  string
 		req   payload.UpdateWarehouseReq
@@ -175,8 +235,83 @@ func TestUpdateWarehouse_ShouldReturnError(t *testing.T) {
 				Status: constant.WarehouseStatusInactive,
 			},
 			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
 				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, mock.AnythingOfType("string")).
 					Return(model.Warehouse{}, assert.AnError)
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - warehouse is archived",
+			req: payload.UpdateWarehouseReq{
+				ID:     uuid.New(),
+				Status: constant.WarehouseStatusActive,
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, mock.AnythingOfType("string")).
+					Return(model.Warehouse{
+						ID:     uuid.New(),
+						Name:   "Warehouse One",
+						Status: constant.WarehouseStatusArchived,
+					}, nil)
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - warehouse still holds stock",
+			req: payload.UpdateWarehouseReq{
+				ID:     uuid.New(),
+				Status: constant.WarehouseStatusInactive,
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, mock.AnythingOfType("string")).
+					Return(model.Warehouse{
+						ID:     uuid.New(),
+						Name:   "Warehouse One",
+						Status: constant.WarehouseStatusActive,
+					}, nil)
+				m.warehouseRepo.On("HasStockOnHand", mock.Anything, mock.AnythingOfType("string")).
+					Return(true, nil)
+				m.db.ExpectRollback()
+			},
+		},
+		{
+			name: "error - stock transfers still on the way in",
+			req: payload.UpdateWarehouseReq{
+				ID:     uuid.New(),
+				Status: constant.WarehouseStatusInactive,
+			},
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, mock.AnythingOfType("string")).
+					Return(model.Warehouse{
+						ID:     uuid.New(),
+						Name:   "Warehouse One",
+						Status: constant.WarehouseStatusActive,
+					}, nil)
+				m.warehouseRepo.On("HasStockOnHand", mock.Anything, mock.AnythingOfType("string")).
+					Return(false, nil)
+				m.warehouseRepo.On("HasInboundTransfers", mock.Anything, mock.AnythingOfType("string")).
+					Return(true, nil)
+				m.db.ExpectRollback()
 			},
 		},
 		{
@@ -186,14 +321,24 @@ func TestUpdateWarehouse_ShouldReturnError(t *testing.T) {
 				Status: constant.WarehouseStatusInactive,
 			},
 			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
 				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, mock.AnythingOfType("string")).
 					Return(model.Warehouse{
 						ID:     uuid.New(),
 						Name:   "Warehouse One",
 						Status: constant.WarehouseStatusActive,
 					}, nil)
+				m.warehouseRepo.On("HasStockOnHand", mock.Anything, mock.AnythingOfType("string")).
+					Return(false, nil)
+				m.warehouseRepo.On("HasInboundTransfers", mock.Anything, mock.AnythingOfType("string")).
+					Return(false, nil)
 				m.warehouseRepo.On("UpdateWarehouse", mock.Anything, mock.AnythingOfType("*model.Warehouse")).
 					Return(assert.AnError)
+				m.db.ExpectRollback()
 			},
 		},
 	}
@@ -201,9 +346,11 @@ func TestUpdateWarehouse_ShouldReturnError(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Given
 			mocks := dependencyMocks{
+				db:            mockDb.Mock,
 				warehouseRepo: warehouseRepoMock.NewWarehouseRepository(t),
 			}
 			warehouseSvc := warehouseService{
+				db:            mockDb.Db,
 				warehouseRepo: mocks.warehouseRepo,
 			}
 
@@ -213,6 +360,914 @@ func TestUpdateWarehouse_ShouldReturnError(t *testing.T) {
 
 			assert.Error(t, err)
 			mocks.warehouseRepo.AssertExpectations(t)
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreateWarehouse(t *testing.T) {
+	type dependencyMocks struct {
+		warehouseRepo *warehouseRepoMock.WarehouseRepository
+	}
+
+	tests := []struct {
+		name           string
+		req            payload.CreateWarehouseReq
+		setup          func(m dependencyMocks)
+		expectedStatus string
+		wantErr        bool
+	}{
+		{
+			name: "success - status defaults to active",
+			req: payload.CreateWarehouseReq{
+				Name:    "Warehouse One",
+				Address: "123 Warehouse St",
+			},
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("CreateWarehouse", mock.Anything, &model.Warehouse{
+					Name:    "Warehouse One",
+					Address: "123 Warehouse St",
+					Status:  constant.WarehouseStatusActive,
+				}).
+					Return(nil)
+			},
+			expectedStatus: constant.WarehouseStatusActive,
+		},
+		{
+			name: "success - created inactive",
+			req: payload.CreateWarehouseReq{
+				Name:    "Warehouse One",
+				Address: "123 Warehouse St",
+				Status:  constant.WarehouseStatusInactive,
+			},
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("CreateWarehouse", mock.Anything, mock.AnythingOfType("*model.Warehouse")).
+					Return(nil)
+			},
+			expectedStatus: constant.WarehouseStatusInactive,
+		},
+		{
+			name: "error - failed to create warehouse",
+			req: payload.CreateWarehouseReq{
+				Name:    "Warehouse One",
+				Address: "123 Warehouse St",
+			},
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("CreateWarehouse", mock.Anything, mock.AnythingOfType("*model.Warehouse")).
+					Return(assert.AnError)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				warehouseRepo: warehouseRepoMock.NewWarehouseRepository(t),
+			}
+			warehouseSvc := warehouseService{
+				warehouseRepo: mocks.warehouseRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			resp, err := warehouseSvc.CreateWarehouse(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.Status)
+		})
+	}
+}
+
+func TestArchiveWarehouse(t *testing.T) {
+	type dependencyMocks struct {
+		db            sqlmock.Sqlmock
+		warehouseRepo *warehouseRepoMock.WarehouseRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	warehouseID := uuid.New()
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - empty warehouse is archived",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusInactive}, nil)
+				m.warehouseRepo.On("HasStockOnHand", mock.Anything, warehouseID.String()).
+					Return(false, nil)
+				m.warehouseRepo.On("HasInboundTransfers", mock.Anything, warehouseID.String()).
+					Return(false, nil)
+				m.warehouseRepo.On("UpdateWarehouse", mock.Anything, mock.MatchedBy(func(warehouse *model.Warehouse) bool {
+					return warehouse.Status == constant.WarehouseStatusArchived && warehouse.ArchivedAt != nil
+				})).
+					Return(nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - warehouse still holds stock",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.warehouseRepo.On("HasStockOnHand", mock.Anything, warehouseID.String()).
+					Return(true, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - stock transfers still on the way in",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.warehouseRepo.On("HasStockOnHand", mock.Anything, warehouseID.String()).
+					Return(false, nil)
+				m.warehouseRepo.On("HasInboundTransfers", mock.Anything, warehouseID.String()).
+					Return(true, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - warehouse is already archived",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusArchived}, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - warehouse not found",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{}, assert.AnError)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:            mockDb.Mock,
+				warehouseRepo: warehouseRepoMock.NewWarehouseRepository(t),
+			}
+			warehouseSvc := warehouseService{
+				logger:        pkg.InitLogger(&config.Config{}),
+				db:            mockDb.Db,
+				warehouseRepo: mocks.warehouseRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := warehouseSvc.ArchiveWarehouse(context.Background(), warehouseID.String())
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockDb.Mock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestStartDrain(t *testing.T) {
+	type dependencyMocks struct {
+		db            sqlmock.Sqlmock
+		warehouseRepo *warehouseRepoMock.WarehouseRepository
+		stockRepo     *stockRepoMock.StockRepository
+		stockService  *stockSvcMock.StockService
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	warehouseID := uuid.New()
+	productID := uuid.New()
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - active warehouse starts draining",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+				m.warehouseRepo.On("UpdateWarehouse", mock.Anything, mock.MatchedBy(func(warehouse *model.Warehouse) bool {
+					return warehouse.Status == constant.WarehouseStatusDraining
+				})).
+					Return(nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetWarehouseStocks", mock.Anything, warehouseID.String()).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 10},
+					}, nil)
+
+				m.stockService.On("SettleStockChanges", mock.Anything, mock.Anything, []string{productID.String()}).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+
+				m.stockService.On("PublishStockChanges", mock.Anything, []string{productID.String()}, []model.StockAlert{}).
+					Return()
+			},
+		},
+		{
+			name: "error - warehouse is not active",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusInactive}, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:            mockDb.Mock,
+				warehouseRepo: warehouseRepoMock.NewWarehouseRepository(t),
+				stockRepo:     stockRepoMock.NewStockRepository(t),
+				stockService:  stockSvcMock.NewStockService(t),
+			}
+			warehouseSvc := warehouseService{
+				logger:        pkg.InitLogger(&config.Config{}),
+				db:            mockDb.Db,
+				warehouseRepo: mocks.warehouseRepo,
+				stockRepo:     mocks.stockRepo,
+				stockService:  mocks.stockService,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := warehouseSvc.StartDrain(context.Background(), warehouseID.String())
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetDrainPlan(t *testing.T) {
+	type dependencyMocks struct {
+		warehouseRepo *warehouseRepoMock.WarehouseRepository
+		stockRepo     *stockRepoMock.StockRepository
+	}
+
+	warehouseID := uuid.New()
+	targetID := uuid.New()
+	productID := uuid.New()
+	productID2 := uuid.New()
+	productID3 := uuid.New()
+	// equally stocked candidates are broken by warehouse ID
+	candidateID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	candidateID2 := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+	stocks := []model.WarehouseStock{
+		{WarehouseID: warehouseID, ProductID: productID, Quantity: 10, Reserved: 4},
+		{WarehouseID: warehouseID, ProductID: productID2, Quantity: 5, Reserved: 5},
+		{WarehouseID: warehouseID, ProductID: productID3, Quantity: 7},
+	}
+
+	tests := []struct {
+		name    string
+		req     payload.DrainWarehouseReq
+		setup   func(m dependencyMocks)
+		want    payload.DrainPlan
+		wantErr bool
+	}{
+		{
+			name: "success - proposes warehouse holding the most stock",
+			req:  payload.DrainWarehouseReq{ID: warehouseID},
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusDraining}, nil)
+				m.stockRepo.On("GetWarehouseStocks", mock.Anything, warehouseID.String()).
+					Return(stocks, nil)
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: candidateID2, ProductID: productID, Quantity: 50},
+						{WarehouseID: candidateID, ProductID: productID, Quantity: 50},
+						{WarehouseID: uuid.New(), ProductID: productID, Quantity: 20},
+					}, nil)
+			},
+			want: payload.DrainPlan{
+				WarehouseID: warehouseID,
+				Status:      constant.WarehouseStatusDraining,
+				Reservations: []payload.DrainReservation{
+					{ProductID: productID, Reserved: 4},
+					{ProductID: productID2, Reserved: 5},
+				},
+				Transfers: []payload.DrainTransfer{
+					{ProductID: productID, ToWarehouseID: &candidateID, Quantity: 6},
+					{ProductID: productID3, Quantity: 7},
+				},
+			},
+		},
+		{
+			name: "success - sends everything to the target warehouse",
+			req:  payload.DrainWarehouseReq{ID: warehouseID, ToWarehouseID: targetID.String()},
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusDraining}, nil)
+				m.stockRepo.On("GetWarehouseStocks", mock.Anything, warehouseID.String()).
+					Return(stocks, nil)
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, targetID.String()).
+					Return(model.Warehouse{ID: targetID, Status: constant.WarehouseStatusActive}, nil)
+			},
+			want: payload.DrainPlan{
+				WarehouseID: warehouseID,
+				Status:      constant.WarehouseStatusDraining,
+				Reservations: []payload.DrainReservation{
+					{ProductID: productID, Reserved: 4},
+					{ProductID: productID2, Reserved: 5},
+				},
+				Transfers: []payload.DrainTransfer{
+					{ProductID: productID, ToWarehouseID: &targetID, Quantity: 6},
+					{ProductID: productID3, ToWarehouseID: &targetID, Quantity: 7},
+				},
+			},
+		},
+		{
+			name: "success - empty warehouse is ready",
+			req:  payload.DrainWarehouseReq{ID: warehouseID},
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusDraining}, nil)
+				m.stockRepo.On("GetWarehouseStocks", mock.Anything, warehouseID.String()).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID},
+					}, nil)
+			},
+			want: payload.DrainPlan{
+				WarehouseID:  warehouseID,
+				Status:       constant.WarehouseStatusDraining,
+				Reservations: []payload.DrainReservation{},
+				Transfers:    []payload.DrainTransfer{},
+				Ready:        true,
+			},
+		},
+		{
+			name: "error - target warehouse is not active",
+			req:  payload.DrainWarehouseReq{ID: warehouseID, ToWarehouseID: targetID.String()},
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusDraining}, nil)
+				m.stockRepo.On("GetWarehouseStocks", mock.Anything, warehouseID.String()).
+					Return(stocks, nil)
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, targetID.String()).
+					Return(model.Warehouse{ID: targetID, Status: constant.WarehouseStatusInactive}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - warehouse is not draining",
+			req:  payload.DrainWarehouseReq{ID: warehouseID},
+			setup: func(m dependencyMocks) {
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				warehouseRepo: warehouseRepoMock.NewWarehouseRepository(t),
+				stockRepo:     stockRepoMock.NewStockRepository(t),
+			}
+			warehouseSvc := warehouseService{
+				logger:        pkg.InitLogger(&config.Config{}),
+				warehouseRepo: mocks.warehouseRepo,
+				stockRepo:     mocks.stockRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			plan, err := warehouseSvc.GetDrainPlan(context.Background(), tt.req)
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, plan)
+		})
+	}
+}
+
+func TestExecuteDrainTransfers(t *testing.T) {
+	type dependencyMocks struct {
+		db              sqlmock.Sqlmock
+		warehouseRepo   *warehouseRepoMock.WarehouseRepository
+		stockRepo       *stockRepoMock.StockRepository
+		stockLotRepo    *stockRepoMock.StockLotRepository
+		stockSerialRepo *stockRepoMock.StockSerialRepository
+		stockService    *stockSvcMock.StockService
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	warehouseID := uuid.New()
+	targetID := uuid.New()
+	productID := uuid.New()
+
+	expectSerialized := func(m dependencyMocks, products ...model.SerializedProduct) {
+		m.stockSerialRepo.On("WithTX", mock.Anything).
+			Return(m.stockSerialRepo)
+		m.stockSerialRepo.On("GetSerializedProducts", mock.Anything, []string{productID.String()}).
+			Return(products, nil)
+	}
+
+	expectLots := func(m dependencyMocks, quantity int) {
+		m.stockLotRepo.On("WithTX", mock.Anything).
+			Return(m.stockLotRepo)
+		m.stockLotRepo.On("MoveStockLots", mock.Anything, productID.String(), warehouseID.String(), targetID.String(), quantity).
+			Return(0, nil)
+	}
+
+	expectDestination := func(m dependencyMocks, status string) {
+		m.warehouseRepo.On("WithLockForShare").
+			Return(m.warehouseRepo)
+		m.warehouseRepo.On("GetWarehouseByID", mock.Anything, targetID.String()).
+			Return(model.Warehouse{ID: targetID, Status: status}, nil)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantLen int
+		wantErr bool
+	}{
+		{
+			name: "success - moves unreserved stock",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusDraining}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("WithLockForUpdate").
+					Return(m.stockRepo)
+				m.stockRepo.On("GetWarehouseStocks", mock.Anything, warehouseID.String()).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 10, Reserved: 3},
+					}, nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: targetID, ProductID: productID, Quantity: 1},
+					}, nil)
+
+				expectSerialized(m)
+
+				expectDestination(m, constant.WarehouseStatusActive)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), targetID.String(), 7).
+					Return(nil)
+				expectLots(m, 7)
+				m.stockRepo.On("DecreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 7).
+					Return(nil)
+				m.stockRepo.On("CreateStockTransfer", mock.Anything, mock.MatchedBy(func(transfer *model.StockTransfer) bool {
+					return transfer.FromWarehouseID == warehouseID &&
+						transfer.ToWarehouseID == targetID &&
+						transfer.Quantity == 7 &&
+						transfer.ReceivedQuantity == 7 &&
+						transfer.Status == constant.StockTransferStatusReceived
+				})).
+					Return(nil)
+
+				m.stockService.On("SettleStockChanges", mock.Anything, mock.Anything, []string{productID.String()}).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+
+				m.stockService.On("PublishStockChanges", mock.Anything, []string{productID.String()}, []model.StockAlert{}).
+					Return()
+			},
+			wantLen: 1,
+		},
+		{
+			name: "success - serials move with the stock",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusDraining}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("WithLockForUpdate").
+					Return(m.stockRepo)
+				m.stockRepo.On("GetWarehouseStocks", mock.Anything, warehouseID.String()).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 2},
+					}, nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: targetID, ProductID: productID, Quantity: 1},
+					}, nil)
+
+				expectSerialized(m, model.SerializedProduct{ProductID: productID})
+
+				expectDestination(m, constant.WarehouseStatusActive)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), targetID.String(), 2).
+					Return(nil)
+				expectLots(m, 2)
+				m.stockRepo.On("DecreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 2).
+					Return(nil)
+				m.stockSerialRepo.On("MoveStockSerials", mock.Anything, productID.String(), warehouseID.String(), targetID.String(), 2).
+					Return(nil)
+				m.stockRepo.On("CreateStockTransfer", mock.Anything, mock.Anything).
+					Return(nil)
+
+				m.stockService.On("SettleStockChanges", mock.Anything, mock.Anything, []string{productID.String()}).
+					Return([]model.StockAlert{}, nil)
+
+				m.db.ExpectCommit()
+
+				m.stockService.On("PublishStockChanges", mock.Anything, []string{productID.String()}, []model.StockAlert{}).
+					Return()
+			},
+			wantLen: 1,
+		},
+		{
+			name: "error - no destination for product",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusDraining}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("WithLockForUpdate").
+					Return(m.stockRepo)
+				m.stockRepo.On("GetWarehouseStocks", mock.Anything, warehouseID.String()).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 10},
+					}, nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{}, nil)
+
+				expectSerialized(m)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - failed to decrease stock",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusDraining}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("WithLockForUpdate").
+					Return(m.stockRepo)
+				m.stockRepo.On("GetWarehouseStocks", mock.Anything, warehouseID.String()).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 10},
+					}, nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: targetID, ProductID: productID, Quantity: 1},
+					}, nil)
+
+				expectSerialized(m)
+
+				expectDestination(m, constant.WarehouseStatusActive)
+				m.stockRepo.On("IncreaseStockQty", mock.Anything, productID.String(), targetID.String(), 10).
+					Return(nil)
+				expectLots(m, 10)
+				m.stockRepo.On("DecreaseStockQty", mock.Anything, productID.String(), warehouseID.String(), 10).
+					Return(assert.AnError)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - destination archived since the plan was read",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusDraining}, nil)
+
+				m.stockRepo.On("WithTX", mock.Anything).
+					Return(m.stockRepo)
+				m.stockRepo.On("WithLockForUpdate").
+					Return(m.stockRepo)
+				m.stockRepo.On("GetWarehouseStocks", mock.Anything, warehouseID.String()).
+					Return([]model.WarehouseStock{
+						{WarehouseID: warehouseID, ProductID: productID, Quantity: 10},
+					}, nil)
+				m.stockRepo.On("GetStocks", mock.Anything, mock.Anything).
+					Return([]model.WarehouseStock{
+						{WarehouseID: targetID, ProductID: productID, Quantity: 1},
+					}, nil)
+
+				expectSerialized(m)
+
+				expectDestination(m, constant.WarehouseStatusArchived)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - warehouse is not draining",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:              mockDb.Mock,
+				warehouseRepo:   warehouseRepoMock.NewWarehouseRepository(t),
+				stockRepo:       stockRepoMock.NewStockRepository(t),
+				stockLotRepo:    stockRepoMock.NewStockLotRepository(t),
+				stockSerialRepo: stockRepoMock.NewStockSerialRepository(t),
+				stockService:    stockSvcMock.NewStockService(t),
+			}
+			warehouseSvc := warehouseService{
+				logger:          pkg.InitLogger(&config.Config{}),
+				db:              mockDb.Db,
+				warehouseRepo:   mocks.warehouseRepo,
+				stockRepo:       mocks.stockRepo,
+				stockLotRepo:    mocks.stockLotRepo,
+				stockSerialRepo: mocks.stockSerialRepo,
+				stockService:    mocks.stockService,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			transfers, err := warehouseSvc.ExecuteDrainTransfers(context.Background(), payload.DrainWarehouseReq{ID: warehouseID})
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, transfers, tt.wantLen)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCompleteDrain(t *testing.T) {
+	type dependencyMocks struct {
+		db            sqlmock.Sqlmock
+		warehouseRepo *warehouseRepoMock.WarehouseRepository
+	}
+
+	mockDb, err := pkg.SetupMockDB()
+	if err != nil {
+		t.Errorf("Failed to open mock sql db, got error: %v", err)
+	}
+
+	warehouseID := uuid.New()
+
+	tests := []struct {
+		name    string
+		setup   func(m dependencyMocks)
+		wantErr bool
+	}{
+		{
+			name: "success - empty warehouse is deactivated",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusDraining}, nil)
+				m.warehouseRepo.On("HasStockOnHand", mock.Anything, warehouseID.String()).
+					Return(false, nil)
+				m.warehouseRepo.On("HasInboundTransfers", mock.Anything, warehouseID.String()).
+					Return(false, nil)
+				m.warehouseRepo.On("UpdateWarehouse", mock.Anything, mock.MatchedBy(func(warehouse *model.Warehouse) bool {
+					return warehouse.Status == constant.WarehouseStatusInactive
+				})).
+					Return(nil)
+
+				m.db.ExpectCommit()
+			},
+		},
+		{
+			name: "error - reservations are still outstanding",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusDraining}, nil)
+				m.warehouseRepo.On("HasStockOnHand", mock.Anything, warehouseID.String()).
+					Return(true, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - stock transfers still on the way in",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusDraining}, nil)
+				m.warehouseRepo.On("HasStockOnHand", mock.Anything, warehouseID.String()).
+					Return(false, nil)
+				m.warehouseRepo.On("HasInboundTransfers", mock.Anything, warehouseID.String()).
+					Return(true, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "error - warehouse is not draining",
+			setup: func(m dependencyMocks) {
+				m.db.ExpectBegin()
+
+				m.warehouseRepo.On("WithTX", mock.Anything).
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("WithLockForUpdate").
+					Return(m.warehouseRepo)
+				m.warehouseRepo.On("GetWarehouseByID", mock.Anything, warehouseID.String()).
+					Return(model.Warehouse{ID: warehouseID, Status: constant.WarehouseStatusActive}, nil)
+
+				m.db.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			mocks := dependencyMocks{
+				db:            mockDb.Mock,
+				warehouseRepo: warehouseRepoMock.NewWarehouseRepository(t),
+			}
+			warehouseSvc := warehouseService{
+				logger:        pkg.InitLogger(&config.Config{}),
+				db:            mockDb.Db,
+				warehouseRepo: mocks.warehouseRepo,
+			}
+
+			tt.setup(mocks)
+
+			// When
+			err := warehouseSvc.CompleteDrain(context.Background(), warehouseID.String())
+
+			// Then
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mockDb.Mock.ExpectationsWereMet())
 		})
 	}
 }
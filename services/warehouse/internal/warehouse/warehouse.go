@@ -3,6 +3,8 @@ package warehouse
 import (
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/_options"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/registry"
+	stockrepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository"
+	stockservice "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/handler"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
 	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/service"
@@ -14,13 +16,17 @@ type WarehouseModule struct {
 
 type Options struct {
 	_options.DefaultOptions
+	StockService stockservice.StockService
 }
 
 func NewWarehouseModule(opts Options) *WarehouseModule {
 
 	warehouseRepo := repository.NewWarehouseRepository(opts.Db)
+	stockRepo := stockrepository.NewStockRepository(opts.Db)
+	stockLotRepo := stockrepository.NewStockLotRepository(opts.Db)
+	stockSerialRepo := stockrepository.NewStockSerialRepository(opts.Db)
 
-	warehouseService := service.NewWarehouseService(opts.Config, warehouseRepo)
+	warehouseService := service.NewWarehouseService(opts.Config, opts.Logger, opts.Db, warehouseRepo, stockRepo, stockLotRepo, stockSerialRepo, opts.StockService)
 
 	registry.RegisterRouter(handler.NewHandler(opts.Router, opts.Config, opts.Logger, warehouseService))
 
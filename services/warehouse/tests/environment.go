@@ -23,7 +23,7 @@ type TestEnvironment struct {
 	Cleanup           func()
 }
 
-func SetupTestEnvironment(t *testing.T) *TestEnvironment {
+func SetupTestEnvironment(t testing.TB) *TestEnvironment {
 	ctx := context.Background()
 
 	// 1. Start PostgreSQL container
@@ -121,6 +121,11 @@ func SetupTestEnvironment(t *testing.T) *TestEnvironment {
 			}
 		}
 
+		// Stop the server so the next environment starts one on its own database
+		if err := cmd.StopTestServer(); err != nil {
+			t.Logf("failed to stop test server: %v", err)
+		}
+
 		// Stop containers
 		postgresContainer.Terminate(ctx)
 
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/config"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/pkg/apperr"
+	shopwarehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/shopwarehouse/repository"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/service"
+	warehouserepository "github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/warehouse/repository"
+)
+
+// openTestDB connects to the database of the environment, migrated by the test
+// server when it started.
+func openTestDB(tb testing.TB, env *TestEnvironment) *gorm.DB {
+	tb.Helper()
+
+	db, err := gorm.Open(postgres.Open(env.DatabaseURL), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 gormLogger.Discard,
+	})
+	require.NoError(tb, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(tb, err)
+	sqlDB.SetMaxOpenConns(50)
+	tb.Cleanup(func() { _ = sqlDB.Close() })
+
+	return db
+}
+
+// seedReserveStocks creates an active warehouse holding quantity of each new
+// product.
+func seedReserveStocks(tb testing.TB, db *gorm.DB, products int, quantity int) (uuid.UUID, []uuid.UUID) {
+	tb.Helper()
+
+	warehouse := model.Warehouse{Name: "reserve-test-" + uuid.NewString(), Status: constant.WarehouseStatusActive}
+	require.NoError(tb, db.Create(&warehouse).Error)
+
+	productIDs := make([]uuid.UUID, 0, products)
+	for range products {
+		stock := model.WarehouseStock{WarehouseID: warehouse.ID, ProductID: uuid.New(), Quantity: quantity}
+		require.NoError(tb, db.Create(&stock).Error)
+		productIDs = append(productIDs, stock.ProductID)
+	}
+
+	return warehouse.ID, productIDs
+}
+
+func newReserveTestService(db *gorm.DB) service.StockService {
+	return service.NewStockService(
+		&config.Config{},
+		pkg.InitLogger(&config.Config{}),
+		db,
+		repository.NewStockRepository(db),
+		repository.NewStockAlertRepository(db),
+		repository.NewStockLotRepository(db),
+		repository.NewStockSerialRepository(db),
+		repository.NewStockBinRepository(db),
+		repository.NewStockSnapshotRepository(db),
+		repository.NewBackorderRepository(db),
+		shopwarehouserepository.NewShopWarehouseRepository(db),
+		warehouserepository.NewWarehouseRepository(db),
+		nil,
+	)
+}
+
+// TestReserveStocks_ConcurrentShouldNotOversell races more demand than there is
+// stock. Requests take the two products in opposite orders, which deadlocked
+// the locking reservation.
+func TestReserveStocks_ConcurrentShouldNotOversell(t *testing.T) {
+	env := SetupTestEnvironment(t)
+	defer env.Cleanup()
+
+	db := openTestDB(t, env)
+	warehouseID, productIDs := seedReserveStocks(t, db, 2, 30)
+	stockSvc := newReserveTestService(db)
+
+	const requests = 40
+	const quantity = 2
+
+	var reserved atomic.Int64
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			first, second := productIDs[0], productIDs[1]
+			if i%2 == 1 {
+				first, second = second, first
+			}
+			_, err := stockSvc.ReserveStocks(context.Background(), payload.ReserveStocksReq{
+				Stocks: []payload.ReserveStocksData{
+					{ProductID: first.String(), Quantity: quantity},
+					{ProductID: second.String(), Quantity: quantity},
+				},
+			})
+			if err != nil {
+				assert.Equal(t, apperr.CodeHTTPBadRequest, apperr.ErrCode(err), err.Error())
+				return
+			}
+			reserved.Add(quantity)
+		}()
+	}
+	wg.Wait()
+
+	var stocks []model.WarehouseStock
+	require.NoError(t, db.Where("warehouse_id = ?", warehouseID).Find(&stocks).Error)
+	require.Len(t, stocks, 2)
+	for _, stock := range stocks {
+		assert.LessOrEqual(t, stock.Reserved, stock.Quantity)
+		assert.Equal(t, reserved.Load(), int64(stock.Reserved))
+	}
+}
+
+func BenchmarkReserveStocks(b *testing.B) {
+	env := SetupTestEnvironment(b)
+	defer env.Cleanup()
+
+	db := openTestDB(b, env)
+	_, productIDs := seedReserveStocks(b, db, 1, 1<<30)
+	stockSvc := newReserveTestService(db)
+
+	req := payload.ReserveStocksReq{
+		Stocks: []payload.ReserveStocksData{{ProductID: productIDs[0].String(), Quantity: 1}},
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := stockSvc.ReserveStocks(context.Background(), req); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
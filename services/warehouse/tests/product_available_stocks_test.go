@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/constant"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/model"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/payload"
+	"github.com/alifmufthi91/ecommerce-system/services/warehouse/internal/stock/repository"
+)
+
+// TestProductAvailableStocksView checks the product_available_stocks view the
+// product service filters listings on reports the same availability as
+// GetAvailableStocksByProduct.
+func TestProductAvailableStocksView(t *testing.T) {
+	env := SetupTestEnvironment(t)
+	defer env.Cleanup()
+
+	db := openTestDB(t, env)
+
+	newWarehouse := func(status string) uuid.UUID {
+		warehouse := model.Warehouse{Name: "availability-test-" + uuid.NewString(), Status: status}
+		require.NoError(t, db.Create(&warehouse).Error)
+		return warehouse.ID
+	}
+	active := newWarehouse(constant.WarehouseStatusActive)
+	other := newWarehouse(constant.WarehouseStatusActive)
+	inactive := newWarehouse(constant.WarehouseStatusInactive)
+
+	lotted, reserved, elsewhere := uuid.New(), uuid.New(), uuid.New()
+	expired := time.Now().AddDate(0, 0, -1)
+	fresh := time.Now().AddDate(0, 1, 0)
+
+	require.NoError(t, db.Create(&[]model.WarehouseStock{
+		{WarehouseID: active, ProductID: lotted, Quantity: 10, Reserved: 2},
+		{WarehouseID: other, ProductID: lotted, Quantity: 5},
+		{WarehouseID: inactive, ProductID: lotted, Quantity: 100},
+		{WarehouseID: active, ProductID: reserved, Quantity: 4, Reserved: 4},
+		{WarehouseID: inactive, ProductID: elsewhere, Quantity: 7},
+	}).Error)
+	require.NoError(t, db.Create(&[]model.StockLot{
+		{WarehouseID: other, ProductID: lotted, LotNumber: "EXPIRED", ExpiryDate: &expired, Quantity: 3, Reserved: 1},
+		{WarehouseID: other, ProductID: lotted, LotNumber: "FRESH", ExpiryDate: &fresh, Quantity: 2},
+	}).Error)
+
+	productIDs := []string{lotted.String(), reserved.String(), elsewhere.String()}
+
+	want, err := repository.NewStockRepository(db).GetAvailableStocksByProduct(context.Background(), payload.GetStockAvailablesByProductReq{
+		ProductIDIN: productIDs,
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []model.GetStockAvailablesByProduct{
+		{ProductID: lotted, AvailableStock: 11},
+		{ProductID: reserved, AvailableStock: 0},
+	}, want)
+
+	var got []model.GetStockAvailablesByProduct
+	require.NoError(t, db.Table("product_available_stocks").Where("product_id IN ?", productIDs).Find(&got).Error)
+	assert.ElementsMatch(t, want, got)
+}